@@ -56,6 +56,8 @@ func NewACMESolverCommand(stopCh <-chan struct{}) *cobra.Command {
 	cmd.Flags().StringVar(&s.Domain, "domain", "", "the domain name to verify")
 	cmd.Flags().StringVar(&s.Token, "token", "", "the challenge token to verify against")
 	cmd.Flags().StringVar(&s.Key, "key", "", "the challenge key to respond with")
+	cmd.Flags().StringVar(&s.TokensDir, "tokens-dir", "", "a directory containing a file for each challenge token to respond to. "+
+		"If set, --domain, --token and --key are ignored")
 
 	return cmd
 }
@@ -18,14 +18,21 @@ package app
 
 import (
 	"context"
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
 	"os"
 	"sync"
 	"time"
 
+	"google.golang.org/grpc"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -39,15 +46,31 @@ import (
 
 	"github.com/jetstack/cert-manager/cmd/controller/app/options"
 	"github.com/jetstack/cert-manager/pkg/acme/accounts"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	clientset "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
 	intscheme "github.com/jetstack/cert-manager/pkg/client/clientset/versioned/scheme"
 	informers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
 	"github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/replicas"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/servicednsnames"
+	"github.com/jetstack/cert-manager/pkg/controller/certificatesigningrequests"
 	"github.com/jetstack/cert-manager/pkg/controller/clusterissuers"
+	"github.com/jetstack/cert-manager/pkg/controller/issuancerecords"
+	"github.com/jetstack/cert-manager/pkg/controller/webhookbootstrap"
+	"github.com/jetstack/cert-manager/pkg/feature"
+	"github.com/jetstack/cert-manager/pkg/healthz"
+	"github.com/jetstack/cert-manager/pkg/issuancestatus"
 	dnsutil "github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
+	acmehttp "github.com/jetstack/cert-manager/pkg/issuer/acme/http"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	"github.com/jetstack/cert-manager/pkg/metrics"
 	"github.com/jetstack/cert-manager/pkg/util"
+	utilfeature "github.com/jetstack/cert-manager/pkg/util/feature"
+	"github.com/jetstack/cert-manager/pkg/util/issuerallowlist"
+	"github.com/jetstack/cert-manager/pkg/util/maintenance"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	servertls "github.com/jetstack/cert-manager/pkg/webhook/server/tls"
 )
 
 const controllerAgentName = "cert-manager"
@@ -77,8 +100,41 @@ func Run(opts *options.ControllerOptions, stopCh <-chan struct{}) {
 		os.Exit(1)
 	}
 
+	readiness, err := buildReadinessChecker(ctx, opts)
+	if err != nil {
+		log.Error(err, "error building readiness checker")
+		os.Exit(1)
+	}
+
+	var readinessServer *http.Server
+	if opts.ReadinessProbeListenAddress != "" {
+		readinessServer, err = readiness.Start(opts.ReadinessProbeListenAddress)
+		if err != nil {
+			log.Error(err, "failed to listen on readiness probe address", "address", opts.ReadinessProbeListenAddress)
+			os.Exit(1)
+		}
+	}
+
+	var statusServer *issuancestatus.Server
+	var grpcServer *grpc.Server
+	if opts.GRPCListenAddress != "" {
+		statusServer = issuancestatus.NewServer(log, ctx.SharedInformerFactory)
+		source := &servertls.FileCertificateSource{
+			CertPath: opts.GRPCTLSCertFile,
+			KeyPath:  opts.GRPCTLSKeyFile,
+			Log:      log,
+		}
+		grpcServer, err = statusServer.Start(opts.GRPCListenAddress, source, stopCh)
+		if err != nil {
+			log.Error(err, "failed to listen on gRPC address", "address", opts.GRPCListenAddress)
+			os.Exit(1)
+		}
+	}
+
 	var wg sync.WaitGroup
 	run := func(_ context.Context) {
+		readiness.SetLeading()
+
 		for n, fn := range controller.Known() {
 			log := log.WithValues("controller", n)
 
@@ -94,6 +150,37 @@ func Run(opts *options.ControllerOptions, stopCh <-chan struct{}) {
 				continue
 			}
 
+			// CertificateSigningRequests are cluster scoped, so can't be
+			// watched when scoped to a single namespace
+			if ctx.Namespace != "" && n == certificatesigningrequests.ControllerName {
+				log.V(logf.InfoLevel).Info("not starting controller as cert-manager has been scoped to a single namespace")
+				continue
+			}
+
+			// IssuanceRecords are only garbage collected if they are enabled
+			if !opts.EnableIssuanceRecords && n == issuancerecords.ControllerName {
+				log.V(logf.InfoLevel).Info("not starting controller as issuance records are disabled")
+				continue
+			}
+
+			// The webhook serving certificate is only issued if a Secret is configured
+			if opts.WebhookBootstrapSecretName == "" && n == webhookbootstrap.ControllerName {
+				log.V(logf.InfoLevel).Info("not starting controller as no webhook bootstrap secret is configured")
+				continue
+			}
+
+			// Certificates only select Services if the feature gate is enabled
+			if !utilfeature.DefaultFeatureGate.Enabled(feature.ServiceDNSNames) && n == servicednsnames.ControllerName {
+				log.V(logf.InfoLevel).Info("not starting controller as the ServiceDNSNames feature gate is disabled")
+				continue
+			}
+
+			// Certificates are only expanded into replicas if the feature gate is enabled
+			if !utilfeature.DefaultFeatureGate.Enabled(feature.CertificateReplicas) && n == replicas.ControllerName {
+				log.V(logf.InfoLevel).Info("not starting controller as the CertificateReplicas feature gate is disabled")
+				continue
+			}
+
 			wg.Add(1)
 			iface, err := fn(ctx)
 			if err != nil {
@@ -117,9 +204,16 @@ func Run(opts *options.ControllerOptions, stopCh <-chan struct{}) {
 		log.V(logf.DebugLevel).Info("starting shared informer factories")
 		ctx.SharedInformerFactory.Start(stopCh)
 		ctx.KubeSharedInformerFactory.Start(stopCh)
+		ctx.DynamicSharedInformerFactory.Start(stopCh)
 		wg.Wait()
 		log.V(logf.InfoLevel).Info("control loops exited")
 		ctx.Metrics.Shutdown(metricsServer)
+		if readinessServer != nil {
+			readiness.Shutdown(readinessServer)
+		}
+		if grpcServer != nil {
+			statusServer.Shutdown(grpcServer)
+		}
 		os.Exit(0)
 	}
 
@@ -164,11 +258,78 @@ func buildControllerContext(ctx context.Context, stopCh <-chan struct{}, opts *o
 		return nil, nil, fmt.Errorf("error creating kubernetes client: %s", err.Error())
 	}
 
-	nameservers := opts.DNS01RecursiveNameservers
-	if len(nameservers) == 0 {
-		nameservers = dnsutil.RecursiveNameservers
+	// Create separate clients for the shared informers, so that the list and
+	// watch requests made when populating and resyncing caches are rate
+	// limited independently of the requests made by the controllers.
+	readCfg := readRESTConfig(kubeCfg, opts)
+	readIntcl, err := clientset.NewForConfig(readCfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating internal group read client: %s", err.Error())
+	}
+	readCl, err := kubernetes.NewForConfig(readCfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating kubernetes read client: %s", err.Error())
+	}
+	readDynamicCl, err := dynamic.NewForConfig(readCfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating dynamic read client: %s", err.Error())
+	}
+
+	nameservers := &dnsutil.NameserverList{}
+	if opts.DNS01RecursiveNameserversFile != "" {
+		// the nameservers are logged by the watcher whenever they change
+		watcher := &dnsutil.NameserversFileWatcher{
+			Path:   opts.DNS01RecursiveNameserversFile,
+			Static: opts.DNS01RecursiveNameservers,
+			List:   nameservers,
+			Log:    log,
+		}
+		if err := watcher.Load(); err != nil {
+			return nil, nil, err
+		}
+		go func() {
+			if err := watcher.Run(stopCh); err != nil {
+				log.Error(err, "error watching dns01 nameservers file, nameservers will not be reloaded")
+			}
+		}()
+	} else {
+		nameservers.Set(dnsutil.MergeNameservers(nil, opts.DNS01RecursiveNameservers))
+		log.V(logf.InfoLevel).WithValues("nameservers", nameservers.Get()).Info("configured acme dns01 nameservers")
+	}
+	if opts.DNS01RecursiveNameserverFailoverThreshold > 0 {
+		dnsutil.NameserverFailover = dnsutil.NewNameserverHealth(opts.DNS01RecursiveNameserverFailoverThreshold, opts.DNS01RecursiveNameserverFailoverCooldown)
+	}
+
+	if err := pki.SetFIPSMode(opts.FIPSMode); err != nil {
+		return nil, nil, fmt.Errorf("error enabling FIPS mode: %s", err.Error())
+	}
+	if opts.FIPSMode {
+		log.V(logf.InfoLevel).Info("FIPS mode enabled, private keys will only be generated using FIPS approved algorithms")
+	}
+
+	var issuerBackendCABundle []byte
+	if opts.IssuerBackendCABundle != "" {
+		issuerBackendCABundle, err = ioutil.ReadFile(opts.IssuerBackendCABundle)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading issuer backend CA bundle: %s", err.Error())
+		}
+		if !x509.NewCertPool().AppendCertsFromPEM(issuerBackendCABundle) {
+			return nil, nil, fmt.Errorf("error parsing issuer backend CA bundle %q: no valid certificates found", opts.IssuerBackendCABundle)
+		}
+		log.V(logf.InfoLevel).WithValues("path", opts.IssuerBackendCABundle).Info("configured issuer backend CA bundle, system root CAs will not be trusted")
+	}
+
+	var additionalTrustBundle []byte
+	if opts.AdditionalTrustBundle != "" {
+		additionalTrustBundle, err = ioutil.ReadFile(opts.AdditionalTrustBundle)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading additional trust bundle: %s", err.Error())
+		}
+		if _, err := pki.DecodeX509CertificateChainBytes(additionalTrustBundle); err != nil {
+			return nil, nil, fmt.Errorf("error parsing additional trust bundle %q: %s", opts.AdditionalTrustBundle, err.Error())
+		}
+		log.V(logf.InfoLevel).WithValues("path", opts.AdditionalTrustBundle).Info("configured additional trust bundle, it will be added to the ca.crt of issued Secrets")
 	}
-	log.V(logf.InfoLevel).WithValues("nameservers", nameservers).Info("configured acme dns01 nameservers")
 
 	HTTP01SolverResourceRequestCPU, err := resource.ParseQuantity(opts.ACMEHTTP01SolverResourceRequestCPU)
 	if err != nil {
@@ -190,6 +351,26 @@ func buildControllerContext(ctx context.Context, stopCh <-chan struct{}, opts *o
 		return nil, nil, fmt.Errorf("error parsing ACMEHTTP01SolverResourceLimitsMemory: %s", err.Error())
 	}
 
+	HTTP01SolverNodeSelector, err := acmehttp.ParseSolverNodeSelector(opts.ACMEHTTP01SolverNodeSelector)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing ACMEHTTP01SolverNodeSelector: %s", err.Error())
+	}
+
+	HTTP01SolverTolerations, err := acmehttp.ParseSolverTolerations(opts.ACMEHTTP01SolverTolerations)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing ACMEHTTP01SolverTolerations: %s", err.Error())
+	}
+
+	maintenanceWindows, err := maintenance.ParseWindows(opts.MaintenanceWindows)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing maintenance windows: %s", err.Error())
+	}
+
+	allowedIssuers, err := issuerallowlist.ParseAllowlist(opts.AllowedIssuers)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing allowed issuers: %s", err.Error())
+	}
+
 	// Create event broadcaster
 	// Add cert-manager types to the default Kubernetes Scheme so Events can be
 	// logged properly
@@ -200,54 +381,141 @@ func buildControllerContext(ctx context.Context, stopCh <-chan struct{}, opts *o
 	eventBroadcaster.StartRecordingToSink(&clientv1.EventSinkImpl{Interface: cl.CoreV1().Events("")})
 	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
 
-	sharedInformerFactory := informers.NewSharedInformerFactoryWithOptions(intcl, resyncPeriod, informers.WithNamespace(opts.Namespace))
-	kubeSharedInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(cl, resyncPeriod, kubeinformers.WithNamespace(opts.Namespace))
+	sharedInformerFactory := informers.NewSharedInformerFactoryWithOptions(readIntcl, resyncPeriod, informers.WithNamespace(opts.Namespace))
+	kubeSharedInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(readCl, resyncPeriod, kubeinformers.WithNamespace(opts.Namespace))
+	dynamicSharedInformerFactory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(readDynamicCl, resyncPeriod, opts.Namespace, nil)
 
 	acmeAccountRegistry := accounts.NewDefaultRegistry()
 
 	return &controller.Context{
-		RootContext:               ctx,
-		StopCh:                    stopCh,
-		RESTConfig:                kubeCfg,
-		Client:                    cl,
-		CMClient:                  intcl,
-		Recorder:                  recorder,
-		KubeSharedInformerFactory: kubeSharedInformerFactory,
-		SharedInformerFactory:     sharedInformerFactory,
-		Namespace:                 opts.Namespace,
-		Clock:                     clock.RealClock{},
-		Metrics:                   metrics.New(log),
+		RootContext:                  ctx,
+		StopCh:                       stopCh,
+		RESTConfig:                   kubeCfg,
+		Client:                       cl,
+		CMClient:                     intcl,
+		Recorder:                     recorder,
+		KubeSharedInformerFactory:    kubeSharedInformerFactory,
+		SharedInformerFactory:        sharedInformerFactory,
+		DynamicSharedInformerFactory: dynamicSharedInformerFactory,
+		Namespace:                    opts.Namespace,
+		Clock:                        clock.RealClock{},
+		Metrics:                      metrics.New(log),
+		PermanentErrorRequeueDelay:   opts.PermanentErrorRequeueDelay,
 		ACMEOptions: controller.ACMEOptions{
 			HTTP01SolverImage:                 opts.ACMEHTTP01SolverImage,
+			HTTP01SolverImagePullSecrets:      opts.ACMEHTTP01SolverImagePullSecrets,
 			HTTP01SolverResourceRequestCPU:    HTTP01SolverResourceRequestCPU,
 			HTTP01SolverResourceRequestMemory: HTTP01SolverResourceRequestMemory,
 			HTTP01SolverResourceLimitsCPU:     HTTP01SolverResourceLimitsCPU,
 			HTTP01SolverResourceLimitsMemory:  HTTP01SolverResourceLimitsMemory,
+			HTTP01SolverNodeSelector:          HTTP01SolverNodeSelector,
+			HTTP01SolverTolerations:           HTTP01SolverTolerations,
+			HTTP01SolverSharedDeployment:      opts.ACMEHTTP01SolverSharedDeployment,
+			HTTP01SolverSharedIngress:         opts.ACMEHTTP01SolverSharedIngress,
+			DeferChallengeCleanup:             opts.ACMEDeferChallengeCleanup,
 			DNS01CheckAuthoritative:           !opts.DNS01RecursiveNameserversOnly,
 			DNS01Nameservers:                  nameservers,
 			AccountRegistry:                   acmeAccountRegistry,
 			DNS01CheckRetryPeriod:             opts.DNS01CheckRetryPeriod,
+			DNS01CheckConcurrency:             opts.DNS01CheckConcurrency,
+			DNS01ProviderAPIRetries:           opts.DNS01ProviderAPIRetries,
+			DNS01ProviderAPIRetryBackoff:      opts.DNS01ProviderAPIRetryBackoff,
+			DNS01ProviderBatchWindow:          opts.DNS01ProviderBatchWindow,
+			DNS01SelfCheckWarnDuration:        opts.DNS01SelfCheckWarnDuration,
 		},
 		IssuerOptions: controller.IssuerOptions{
-			ClusterIssuerAmbientCredentials: opts.ClusterIssuerAmbientCredentials,
-			IssuerAmbientCredentials:        opts.IssuerAmbientCredentials,
-			ClusterResourceNamespace:        opts.ClusterResourceNamespace,
+			ClusterIssuerAmbientCredentials:    opts.ClusterIssuerAmbientCredentials,
+			IssuerAmbientCredentials:           opts.IssuerAmbientCredentials,
+			AmbientCredentialProviders:         opts.AmbientCredentialProviders,
+			ClusterResourceNamespace:           opts.ClusterResourceNamespace,
+			PerClusterIssuerResourceNamespace:  opts.PerClusterIssuerResourceNamespace,
+			IssuerBackendCABundle:              issuerBackendCABundle,
+			SkipIssuedCertificateValidityCheck: opts.SkipIssuedCertificateValidityCheck,
+			EnableIssuanceRecords:              opts.EnableIssuanceRecords,
+			IssuanceRecordRetention:            opts.IssuanceRecordRetention,
+			SerialNumberBits:                   opts.SerialNumberBits,
+			MaxConcurrentSignsPerIssuer:        opts.MaxConcurrentSignsPerIssuer,
+			VenafiRequestsPerSecond:            opts.VenafiRequestsPerSecond,
+			VenafiRequestsBurst:                opts.VenafiRequestsBurst,
 		},
 		IngressShimOptions: controller.IngressShimOptions{
 			DefaultIssuerName:                 opts.DefaultIssuerName,
 			DefaultIssuerKind:                 opts.DefaultIssuerKind,
 			DefaultIssuerGroup:                opts.DefaultIssuerGroup,
 			DefaultAutoCertificateAnnotations: opts.DefaultAutoCertificateAnnotations,
+			DryRun:                            opts.IngressShimDryRun,
 		},
 		CertificateOptions: controller.CertificateOptions{
-			EnableOwnerRef: opts.EnableCertificateOwnerRef,
+			EnableOwnerRef:              opts.EnableCertificateOwnerRef,
+			SecretDeletionGracePeriod:   opts.SecretDeletionGracePeriod,
+			ClusterDomain:               opts.ClusterDomain,
+			MaintenanceWindows:          maintenanceWindows,
+			PrioritizeByExpiry:          opts.PrioritizeCertificatesByExpiry,
+			MaxIssuancesPerNamespace:    opts.MaxConcurrentIssuancesPerNamespace,
+			TriggerOnSecretAnnotation:   opts.TriggerOnSecretAnnotation,
+			MaxInFlightRequests:         opts.MaxInFlightCertificateRequests,
+			RenewalHistoryLimit:         opts.CertificateRenewalHistoryLimit,
+			WarnSecretSize:              opts.WarnSecretSize,
+			KeepReadyWithoutIssuer:      opts.KeepCertificatesReadyWithoutIssuer,
+			IssuerNotReadyThreshold:     opts.IssuerNotReadyThreshold,
+			RenewBeforeExpiryDuration:   opts.RenewBeforeExpiryDuration,
+			RenewBeforeExpiryPercentage: opts.RenewBeforeExpiryPercentage,
+			AdditionalTrustBundle:       additionalTrustBundle,
+			AllowedIssuers:              allowedIssuers,
+			SkipTerminatingNamespaces:   opts.SkipTerminatingNamespaces,
+			IssuerReadinessBackoffMax:   opts.IssuerReadinessBackoffMax,
+			DefaultIssuanceTimeout:      opts.DefaultIssuanceTimeout,
 		},
 		SchedulerOptions: controller.SchedulerOptions{
-			MaxConcurrentChallenges: opts.MaxConcurrentChallenges,
+			MaxConcurrentChallenges:       opts.MaxConcurrentChallenges,
+			MaxConcurrentHTTP01Challenges: opts.MaxConcurrentHTTP01Challenges,
+			MaxConcurrentDNS01Challenges:  opts.MaxConcurrentDNS01Challenges,
+		},
+		WebhookBootstrapOptions: controller.WebhookBootstrapOptions{
+			SecretNamespace: opts.WebhookBootstrapSecretNamespace,
+			SecretName:      opts.WebhookBootstrapSecretName,
+			DNSNames:        opts.WebhookBootstrapDNSNames,
+			Duration:        opts.WebhookBootstrapCertificateDuration,
 		},
 	}, kubeCfg, nil
 }
 
+// buildReadinessChecker returns a checker for the readiness probe endpoint,
+// gated on the issuer configured in opts. Only the informer for the
+// configured kind of issuer is registered, as ClusterIssuers cannot be
+// watched when cert-manager is scoped to a single namespace.
+func buildReadinessChecker(ctx *controller.Context, opts *options.ControllerOptions) (*healthz.ReadinessChecker, error) {
+	issuerRef, err := opts.ReadinessIssuerRef()
+	if err != nil {
+		return nil, err
+	}
+
+	var issuerLister cmlisters.IssuerLister
+	var clusterIssuerLister cmlisters.ClusterIssuerLister
+	if issuerRef != nil {
+		switch issuerRef.Kind {
+		case cmapi.ClusterIssuerKind:
+			clusterIssuerLister = ctx.SharedInformerFactory.Certmanager().V1().ClusterIssuers().Lister()
+		default:
+			issuerLister = ctx.SharedInformerFactory.Certmanager().V1().Issuers().Lister()
+		}
+	}
+
+	return healthz.NewReadinessChecker(logf.FromContext(ctx.RootContext), issuerRef, issuerLister, clusterIssuerLister), nil
+}
+
+// readRESTConfig returns a copy of the given REST config that is rate limited
+// using the configured API read QPS and burst, for use by the shared informers.
+func readRESTConfig(kubeCfg *rest.Config, opts *options.ControllerOptions) *rest.Config {
+	readCfg := rest.CopyConfig(kubeCfg)
+	readCfg.QPS = opts.KubernetesAPIReadQPS
+	readCfg.Burst = opts.KubernetesAPIReadBurst
+	// Ensure a new rate limiter is built from the QPS and burst above, rather
+	// than sharing one with the original config.
+	readCfg.RateLimiter = nil
+	return readCfg
+}
+
 func startLeaderElection(ctx context.Context, opts *options.ControllerOptions, leaderElectionClient kubernetes.Interface, recorder record.EventRecorder, run func(context.Context)) {
 	log := logf.FromContext(ctx, "leader-election")
 
@@ -271,12 +539,16 @@ func startLeaderElection(ctx context.Context, opts *options.ControllerOptions, l
 		},
 	}
 
+	leaseDuration, renewDeadline, retryPeriod := leaderElectionTimings(opts, rand.Float64)
+	log.V(logf.InfoLevel).Info("using leader election timings", "lease_duration", leaseDuration,
+		"renew_deadline", renewDeadline, "retry_period", retryPeriod)
+
 	// Try and become the leader and start controller manager loops
 	leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
 		Lock:          &rl,
-		LeaseDuration: opts.LeaderElectionLeaseDuration,
-		RenewDeadline: opts.LeaderElectionRenewDeadline,
-		RetryPeriod:   opts.LeaderElectionRetryPeriod,
+		LeaseDuration: leaseDuration,
+		RenewDeadline: renewDeadline,
+		RetryPeriod:   retryPeriod,
 		Callbacks: leaderelection.LeaderCallbacks{
 			OnStartedLeading: run,
 			OnStoppedLeading: func() {
@@ -286,3 +558,21 @@ func startLeaderElection(ctx context.Context, opts *options.ControllerOptions, l
 		},
 	})
 }
+
+// leaderElectionTimings returns the durations used for leader election. The
+// lease duration and renew deadline are scaled by the configured grace
+// multiplier, and the lease duration and retry period are then each extended
+// by a random fraction of up to the configured jitter. randFloat must return
+// a value in [0, 1).
+func leaderElectionTimings(opts *options.ControllerOptions, randFloat func() float64) (leaseDuration, renewDeadline, retryPeriod time.Duration) {
+	leaseDuration = time.Duration(float64(opts.LeaderElectionLeaseDuration) * opts.LeaderElectionGraceMultiplier)
+	renewDeadline = time.Duration(float64(opts.LeaderElectionRenewDeadline) * opts.LeaderElectionGraceMultiplier)
+	retryPeriod = opts.LeaderElectionRetryPeriod
+
+	if opts.LeaderElectionJitter > 0 {
+		leaseDuration += time.Duration(float64(leaseDuration) * opts.LeaderElectionJitter * randFloat())
+		retryPeriod += time.Duration(float64(retryPeriod) * opts.LeaderElectionJitter * randFloat())
+	}
+
+	return leaseDuration, renewDeadline, retryPeriod
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/jetstack/cert-manager/cmd/controller/app/options"
+)
+
+func TestReadRESTConfig(t *testing.T) {
+	kubeCfg := &rest.Config{
+		Host:  "https://kubernetes.example.com",
+		QPS:   20,
+		Burst: 50,
+	}
+	opts := options.NewControllerOptions()
+	opts.KubernetesAPIReadQPS = 5
+	opts.KubernetesAPIReadBurst = 10
+
+	readCfg := readRESTConfig(kubeCfg, opts)
+	if readCfg.QPS != 5 || readCfg.Burst != 10 {
+		t.Errorf("expected read config to have QPS 5 and burst 10, got QPS %v and burst %v", readCfg.QPS, readCfg.Burst)
+	}
+	if kubeCfg.QPS != 20 || kubeCfg.Burst != 50 {
+		t.Errorf("expected original config to be unchanged, got QPS %v and burst %v", kubeCfg.QPS, kubeCfg.Burst)
+	}
+
+	readCl, err := kubernetes.NewForConfig(readCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if qps := readCl.CoreV1().RESTClient().GetRateLimiter().QPS(); qps != 5 {
+		t.Errorf("expected read client rate limiter to have QPS 5, got %v", qps)
+	}
+
+	cl, err := kubernetes.NewForConfig(kubeCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if qps := cl.CoreV1().RESTClient().GetRateLimiter().QPS(); qps != 20 {
+		t.Errorf("expected client rate limiter to have QPS 20, got %v", qps)
+	}
+}
+
+func TestLeaderElectionTimings(t *testing.T) {
+	tests := map[string]struct {
+		jitter          float64
+		graceMultiplier float64
+		rand            float64
+		expLease        time.Duration
+		expRenew        time.Duration
+		expRetry        time.Duration
+	}{
+		"defaults should leave the timings unchanged": {
+			jitter:          0,
+			graceMultiplier: 1,
+			rand:            0.5,
+			expLease:        60 * time.Second,
+			expRenew:        40 * time.Second,
+			expRetry:        15 * time.Second,
+		},
+		"jitter should extend the lease duration and retry period": {
+			jitter:          0.2,
+			graceMultiplier: 1,
+			rand:            0.5,
+			expLease:        66 * time.Second,
+			expRenew:        40 * time.Second,
+			expRetry:        16500 * time.Millisecond,
+		},
+		"jitter should not be applied if the random value is 0": {
+			jitter:          0.2,
+			graceMultiplier: 1,
+			rand:            0,
+			expLease:        60 * time.Second,
+			expRenew:        40 * time.Second,
+			expRetry:        15 * time.Second,
+		},
+		"grace multiplier should scale the lease duration and renew deadline": {
+			jitter:          0,
+			graceMultiplier: 2,
+			rand:            0.5,
+			expLease:        120 * time.Second,
+			expRenew:        80 * time.Second,
+			expRetry:        15 * time.Second,
+		},
+		"jitter should be applied to the scaled lease duration": {
+			jitter:          0.1,
+			graceMultiplier: 2,
+			rand:            0.5,
+			expLease:        126 * time.Second,
+			expRenew:        80 * time.Second,
+			expRetry:        15750 * time.Millisecond,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := options.NewControllerOptions()
+			opts.LeaderElectionLeaseDuration = 60 * time.Second
+			opts.LeaderElectionRenewDeadline = 40 * time.Second
+			opts.LeaderElectionRetryPeriod = 15 * time.Second
+			opts.LeaderElectionJitter = test.jitter
+			opts.LeaderElectionGraceMultiplier = test.graceMultiplier
+
+			lease, renew, retry := leaderElectionTimings(opts, func() float64 { return test.rand })
+			if lease != test.expLease {
+				t.Errorf("unexpected lease duration, exp=%v got=%v", test.expLease, lease)
+			}
+			if renew != test.expRenew {
+				t.Errorf("unexpected renew deadline, exp=%v got=%v", test.expRenew, renew)
+			}
+			if retry != test.expRetry {
+				t.Errorf("unexpected retry period, exp=%v got=%v", test.expRetry, retry)
+			}
+		})
+	}
+}
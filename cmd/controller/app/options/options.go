@@ -18,33 +18,51 @@ package options
 
 import (
 	"fmt"
-	"net"
 	"strings"
 	"time"
 
 	"github.com/spf13/pflag"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/leaderelection"
 
 	cm "github.com/jetstack/cert-manager/pkg/apis/certmanager"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	challengescontroller "github.com/jetstack/cert-manager/pkg/controller/acmechallenges"
 	orderscontroller "github.com/jetstack/cert-manager/pkg/controller/acmeorders"
+	cacrlcontroller "github.com/jetstack/cert-manager/pkg/controller/cacrl"
 	cracmecontroller "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/acme"
+	cradcscontroller "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/adcs"
 	crapprovercontroller "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/approver"
 	crcacontroller "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/ca"
 	crselfsignedcontroller "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/selfsigned"
 	crvaultcontroller "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/vault"
 	crvenaficontroller "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/venafi"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/canary"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/issuing"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/keymanager"
 	certificatesmetricscontroller "github.com/jetstack/cert-manager/pkg/controller/certificates/metrics"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/ocspstaple"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/readiness"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/replicas"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/requestmanager"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/revisionmanager"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/secretcleanup"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/servicednsnames"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/trigger"
+	csrcontroller "github.com/jetstack/cert-manager/pkg/controller/certificatesigningrequests"
 	clusterissuerscontroller "github.com/jetstack/cert-manager/pkg/controller/clusterissuers"
 	ingressshimcontroller "github.com/jetstack/cert-manager/pkg/controller/ingress-shim"
+	issuancerecordscontroller "github.com/jetstack/cert-manager/pkg/controller/issuancerecords"
 	issuerscontroller "github.com/jetstack/cert-manager/pkg/controller/issuers"
+	webhookbootstrapcontroller "github.com/jetstack/cert-manager/pkg/controller/webhookbootstrap"
+	"github.com/jetstack/cert-manager/pkg/healthz"
+	dnsutil "github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
+	acmehttp "github.com/jetstack/cert-manager/pkg/issuer/acme/http"
 	"github.com/jetstack/cert-manager/pkg/util"
+	"github.com/jetstack/cert-manager/pkg/util/issuerallowlist"
+	"github.com/jetstack/cert-manager/pkg/util/maintenance"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
 )
 
 type ControllerOptions struct {
@@ -53,41 +71,235 @@ type ControllerOptions struct {
 	KubernetesAPIQPS   float32
 	KubernetesAPIBurst int
 
+	// KubernetesAPIReadQPS and KubernetesAPIReadBurst rate limit the list and
+	// watch requests made by the shared informers, separately from the
+	// requests made by the controllers themselves.
+	KubernetesAPIReadQPS   float32
+	KubernetesAPIReadBurst int
+
 	ClusterResourceNamespace string
 	Namespace                string
 
+	// PerClusterIssuerResourceNamespace allows ClusterIssuers to override
+	// ClusterResourceNamespace using the
+	// cert-manager.io/cluster-resource-namespace annotation.
+	PerClusterIssuerResourceNamespace bool
+
 	LeaderElect                 bool
 	LeaderElectionNamespace     string
 	LeaderElectionLeaseDuration time.Duration
 	LeaderElectionRenewDeadline time.Duration
 	LeaderElectionRetryPeriod   time.Duration
+	// LeaderElectionJitter is the maximum fraction by which the lease
+	// duration and retry period are randomly extended by each instance, so
+	// that candidates do not retry in lockstep.
+	LeaderElectionJitter float64
+	// LeaderElectionGraceMultiplier scales the lease duration and renew
+	// deadline, so that transient failures to renew the lease do not cause
+	// leadership to change.
+	LeaderElectionGraceMultiplier float64
 
 	controllers []string
 
 	ACMEHTTP01SolverImage                 string
+	ACMEHTTP01SolverImagePullSecrets      []string
 	ACMEHTTP01SolverResourceRequestCPU    string
 	ACMEHTTP01SolverResourceRequestMemory string
 	ACMEHTTP01SolverResourceLimitsCPU     string
 	ACMEHTTP01SolverResourceLimitsMemory  string
+	ACMEHTTP01SolverSharedDeployment      bool
+	ACMEHTTP01SolverSharedIngress         bool
+	// ACMEHTTP01SolverNodeSelector is a list of key=value pairs that are
+	// set as the node selector of HTTP01 solver pods.
+	ACMEHTTP01SolverNodeSelector []string
+	// ACMEHTTP01SolverTolerations is a JSON encoded list of tolerations
+	// that are set on HTTP01 solver pods.
+	ACMEHTTP01SolverTolerations string
+
+	// ACMEDeferChallengeCleanup delays the clean up of ACME challenge
+	// records until the Order that owns the challenge has reached a final
+	// state.
+	ACMEDeferChallengeCleanup bool
 
 	ClusterIssuerAmbientCredentials bool
 	IssuerAmbientCredentials        bool
+	// AmbientCredentialProviders is the list of providers that may use
+	// ambient credentials. If empty, all providers may use them.
+	AmbientCredentialProviders []string
+
+	// IssuerBackendCABundle is the path to a PEM encoded CA bundle that is
+	// trusted exclusively, instead of the system root CAs, when connecting to
+	// ACME, ADCS, Vault and Venafi servers.
+	IssuerBackendCABundle string
+
+	// SkipIssuedCertificateValidityCheck disables failing CertificateRequests
+	// whose issuer returned a certificate that is not currently valid.
+	SkipIssuedCertificateValidityCheck bool
+
+	// EnableIssuanceRecords enables creating an IssuanceRecord for every
+	// certificate issued by a CertificateRequest controller.
+	EnableIssuanceRecords bool
+	// IssuanceRecordRetention is how long IssuanceRecords are kept before
+	// being garbage collected. Zero means records are kept forever.
+	IssuanceRecordRetention time.Duration
+
+	// WebhookBootstrapSecretNamespace and WebhookBootstrapSecretName identify
+	// the Secret that a self signed serving certificate for the webhook is
+	// stored in. If the name is empty, the certificate is not issued.
+	WebhookBootstrapSecretNamespace string
+	WebhookBootstrapSecretName      string
+	// WebhookBootstrapDNSNames are the DNS names of the webhook serving
+	// certificate.
+	WebhookBootstrapDNSNames []string
+	// WebhookBootstrapCertificateDuration is the duration of the webhook
+	// serving certificate.
+	WebhookBootstrapCertificateDuration time.Duration
+
+	// PermanentErrorRequeueDelay is how long controllers wait before
+	// retrying a resource that failed with a permanent error. Zero means
+	// such resources are not retried until they are next changed.
+	PermanentErrorRequeueDelay time.Duration
+
+	// SerialNumberBits is the number of random bits in the serial numbers of
+	// certificates signed by the CA and SelfSigned issuers.
+	SerialNumberBits int
+
+	// MaxConcurrentSignsPerIssuer is the maximum number of
+	// CertificateRequests that each CertificateRequest controller may be
+	// signing with a single issuer at once. If zero, there is no limit.
+	MaxConcurrentSignsPerIssuer int
+
+	// VenafiRequestsPerSecond is the maximum rate of requests made to Venafi
+	// by the Venafi CertificateRequest controller. If zero, there is no limit.
+	VenafiRequestsPerSecond float64
+	// VenafiRequestsBurst is the maximum burst of requests made to Venafi.
+	VenafiRequestsBurst int
 
 	// Default issuer/certificates details consumed by ingress-shim
 	DefaultIssuerName                 string
 	DefaultIssuerKind                 string
 	DefaultIssuerGroup                string
 	DefaultAutoCertificateAnnotations []string
+	// AdditionalIssuerGroups is a list of external issuer API groups that
+	// may be used as the DefaultIssuerGroup, in addition to cert-manager.io.
+	AdditionalIssuerGroups []string
+	// IngressShimDryRun stops ingress-shim from creating, updating or
+	// deleting Certificates, logging the changes it would make instead.
+	IngressShimDryRun bool
 
 	// Allows specifying a list of custom nameservers to perform DNS checks on.
 	DNS01RecursiveNameservers []string
+	// DNS01RecursiveNameserversFile is the path to a file containing one
+	// DNS01 nameserver per line. It is watched for changes and reloaded
+	// without restarting the controller.
+	DNS01RecursiveNameserversFile string
 	// Allows controlling if recursive nameservers are only used for all checks.
 	// Normally authoritative nameservers are used for checking propagation.
 	DNS01RecursiveNameserversOnly bool
+	// DNS01RecursiveNameserverFailoverThreshold is the number of consecutive
+	// failed queries after which a DNS01 nameserver is skipped in favour of
+	// the others. If zero, nameservers are never skipped.
+	DNS01RecursiveNameserverFailoverThreshold int
+	// DNS01RecursiveNameserverFailoverCooldown is how long a nameserver is
+	// skipped for once it has reached the failover threshold.
+	DNS01RecursiveNameserverFailoverCooldown time.Duration
 
 	EnableCertificateOwnerRef bool
+	// SecretDeletionGracePeriod is the duration cert-manager waits after a
+	// Certificate is deleted before deleting its Secret. Only used if
+	// EnableCertificateOwnerRef is true.
+	SecretDeletionGracePeriod time.Duration
+
+	// ClusterDomain is the DNS domain of the cluster, used when computing the
+	// cluster DNS names of Services selected by a Certificate.
+	ClusterDomain string
+
+	// MaintenanceWindows are the periods of time, in the form
+	// `<start>/<end>` with RFC 3339 timestamps, during which the issuance of
+	// Certificates is deferred.
+	MaintenanceWindows []string
+
+	// PrioritizeCertificatesByExpiry configures the certificates-trigger
+	// controller to process the Certificates that expire soonest first.
+	PrioritizeCertificatesByExpiry bool
+
+	// MaxConcurrentIssuancesPerNamespace is the maximum number of
+	// Certificates in a single namespace that may be issued at once. If
+	// zero, there is no limit.
+	MaxConcurrentIssuancesPerNamespace int
+
+	// TriggerOnSecretAnnotation is the name of an annotation on Secrets
+	// which, when changed, causes the owning Certificate to be reconciled.
+	TriggerOnSecretAnnotation string
+
+	// MaxInFlightCertificateRequests is the maximum number of pending
+	// CertificateRequests per Certificate. If zero, there is no limit.
+	MaxInFlightCertificateRequests int
+
+	// CertificateRenewalHistoryLimit is the maximum number of records kept
+	// in the status.renewalHistory of each Certificate. If zero, no history
+	// is kept.
+	CertificateRenewalHistoryLimit int
+
+	// WarnSecretSize is the total size in bytes of the certificate, CA and
+	// private key data stored in an issued Secret above which a Warning event
+	// is recorded on the Certificate. If zero, no warning is recorded.
+	WarnSecretSize int
+
+	// KeepCertificatesReadyWithoutIssuer keeps Certificates whose issuer has
+	// been deleted Ready while their existing certificate remains valid.
+	KeepCertificatesReadyWithoutIssuer bool
+
+	// IssuerNotReadyThreshold is how long the issuer referenced by a
+	// Certificate must have not been Ready before the IssuerNotReady
+	// condition is set on the Certificate. If zero, the condition is never
+	// set.
+	IssuerNotReadyThreshold time.Duration
+
+	// RenewBeforeExpiryDuration is how long before they expire certificates
+	// are renewed if their Certificate does not set spec.renewBefore.
+	RenewBeforeExpiryDuration time.Duration
+	// RenewBeforeExpiryPercentage, if non-zero, is the percentage of their
+	// lifetime that must remain before certificates are renewed if their
+	// Certificate does not set spec.renewBefore, and takes precedence over
+	// RenewBeforeExpiryDuration.
+	RenewBeforeExpiryPercentage int
+
+	// AdditionalTrustBundle is the path to a PEM encoded bundle of CA
+	// certificates that is appended to the `ca.crt` of every issued Secret.
+	AdditionalTrustBundle string
+
+	// AllowedIssuers are the issuers, as patterns in the form
+	// `<kind>[.<group>]/<name>`, that Certificates may reference. If empty,
+	// every issuer is allowed.
+	AllowedIssuers []string
+
+	// IssuerReadinessBackoffMax is the maximum delay between checks of
+	// whether the issuer of a Certificate is ready before a
+	// CertificateRequest is created for it. If zero, CertificateRequests are
+	// created regardless of whether the issuer is ready.
+	IssuerReadinessBackoffMax time.Duration
+
+	// DefaultIssuanceTimeout is how long an issuance may remain in progress
+	// before it is marked as failed, for Certificates that do not set
+	// spec.issuanceTimeout. If zero, such issuances never time out.
+	DefaultIssuanceTimeout time.Duration
+
+	// SkipTerminatingNamespaces stops the issuance of Certificates in
+	// namespaces that are being deleted.
+	SkipTerminatingNamespaces bool
+
+	// FIPSMode restricts private key generation to FIPS approved algorithms
+	// and key sizes. It requires cert-manager to be built with a FIPS
+	// validated cryptographic module.
+	FIPSMode bool
 
 	MaxConcurrentChallenges int
+	// MaxConcurrentHTTP01Challenges and MaxConcurrentDNS01Challenges further
+	// limit the number of challenges of each type that can be processing at
+	// once. If zero, only MaxConcurrentChallenges applies.
+	MaxConcurrentHTTP01Challenges int
+	MaxConcurrentDNS01Challenges  int
 
 	// The host and port address, separated by a ':', that the Prometheus server
 	// should expose metrics on.
@@ -96,7 +308,48 @@ type ControllerOptions struct {
 	// the HTTP listener.
 	EnablePprof bool
 
+	// The host and port address, separated by a ':', that the readiness probe
+	// endpoint is served on. If empty, the endpoint is not served.
+	ReadinessProbeListenAddress string
+	// ReadinessIssuerName and ReadinessIssuerKind identify an issuer that
+	// must be Ready for the readiness probe to succeed. Issuer names are given
+	// as <namespace>/<name>, unless cert-manager is scoped to a single
+	// namespace.
+	ReadinessIssuerName string
+	ReadinessIssuerKind string
+
+	// The host and port address, separated by a ':', that the gRPC issuance
+	// status and health services are served on. If empty, they are not
+	// served. If the host is empty, only the loopback interface is listened
+	// on.
+	GRPCListenAddress string
+
+	// Paths to the TLS certificate and private key that the gRPC services
+	// are served with. Both must be set if GRPCListenAddress is set.
+	GRPCTLSCertFile string
+	GRPCTLSKeyFile  string
+
 	DNS01CheckRetryPeriod time.Duration
+
+	// DNS01CheckConcurrency is the maximum number of DNS01 self checks that
+	// may be run in parallel.
+	DNS01CheckConcurrency int
+
+	// DNS01ProviderAPIRetries is the number of times a failed DNS01 provider
+	// API call is retried before the error is surfaced on the Challenge.
+	DNS01ProviderAPIRetries int
+	// DNS01ProviderAPIRetryBackoff is the initial time to wait between
+	// retries of a failed DNS01 provider API call.
+	DNS01ProviderAPIRetryBackoff time.Duration
+	// DNS01ProviderBatchWindow is the time to wait for other DNS01 records
+	// in the same zone, so that they are changed by a single API call of
+	// providers that support it. Batching is disabled if it is zero.
+	DNS01ProviderBatchWindow time.Duration
+
+	// DNS01SelfCheckWarnDuration is the duration after which a DNS01 self
+	// check is reported as slow by an Event on the Challenge. Slow self
+	// checks are not reported if it is zero.
+	DNS01SelfCheckWarnDuration time.Duration
 }
 
 const (
@@ -105,14 +358,21 @@ const (
 	defaultKubernetesAPIQPS   float32 = 20
 	defaultKubernetesAPIBurst         = 50
 
+	defaultKubernetesAPIReadQPS   float32 = 20
+	defaultKubernetesAPIReadBurst         = 50
+
 	defaultClusterResourceNamespace = "kube-system"
 	defaultNamespace                = ""
 
-	defaultLeaderElect                 = true
-	defaultLeaderElectionNamespace     = "kube-system"
-	defaultLeaderElectionLeaseDuration = 60 * time.Second
-	defaultLeaderElectionRenewDeadline = 40 * time.Second
-	defaultLeaderElectionRetryPeriod   = 15 * time.Second
+	defaultPerClusterIssuerResourceNamespace = false
+
+	defaultLeaderElect                   = true
+	defaultLeaderElectionNamespace       = "kube-system"
+	defaultLeaderElectionLeaseDuration   = 60 * time.Second
+	defaultLeaderElectionRenewDeadline   = 40 * time.Second
+	defaultLeaderElectionRetryPeriod     = 15 * time.Second
+	defaultLeaderElectionJitter          = 0.0
+	defaultLeaderElectionGraceMultiplier = 1.0
 
 	defaultClusterIssuerAmbientCredentials = true
 	defaultIssuerAmbientCredentials        = false
@@ -120,7 +380,52 @@ const (
 	defaultTLSACMEIssuerName         = ""
 	defaultTLSACMEIssuerKind         = "Issuer"
 	defaultTLSACMEIssuerGroup        = cm.GroupName
+	defaultIngressShimDryRun         = false
 	defaultEnableCertificateOwnerRef = false
+	defaultSecretDeletionGracePeriod = time.Duration(0)
+	defaultClusterDomain             = "cluster.local"
+	defaultFIPSMode                  = false
+
+	defaultPrioritizeCertificatesByExpiry = false
+
+	defaultMaxConcurrentIssuancesPerNamespace = 0
+
+	defaultTriggerOnSecretAnnotation = ""
+
+	defaultMaxInFlightCertificateRequests = 1
+
+	defaultCertificateRenewalHistoryLimit = 0
+
+	defaultWarnSecretSize = 0
+
+	defaultKeepCertificatesReadyWithoutIssuer = false
+
+	defaultIssuerNotReadyThreshold = 10 * time.Minute
+
+	defaultRenewBeforeExpiryDuration   = cmapi.DefaultRenewBefore
+	defaultRenewBeforeExpiryPercentage = 0
+
+	defaultSkipTerminatingNamespaces = false
+
+	defaultIssuerReadinessBackoffMax = 5 * time.Minute
+
+	defaultIssuanceTimeout = time.Duration(0)
+
+	defaultSkipIssuedCertificateValidityCheck = false
+
+	defaultEnableIssuanceRecords   = false
+	defaultIssuanceRecordRetention = 90 * 24 * time.Hour
+
+	defaultWebhookBootstrapCertificateDuration = 365 * 24 * time.Hour
+
+	defaultPermanentErrorRequeueDelay = 10 * time.Minute
+
+	defaultSerialNumberBits = pki.DefaultSerialNumberBits
+
+	defaultMaxConcurrentSignsPerIssuer = 0
+
+	defaultVenafiRequestsPerSecond = 20
+	defaultVenafiRequestsBurst     = 100
 
 	defaultDNS01RecursiveNameserversOnly = false
 
@@ -128,7 +433,20 @@ const (
 
 	defaultPrometheusMetricsServerAddress = "0.0.0.0:9402"
 
+	defaultReadinessProbeListenAddress = "0.0.0.0:9403"
+	defaultReadinessIssuerKind         = "ClusterIssuer"
+
 	defaultDNS01CheckRetryPeriod = 10 * time.Second
+	defaultDNS01CheckConcurrency = 5
+
+	defaultDNS01ProviderAPIRetries      = 0
+	defaultDNS01ProviderAPIRetryBackoff = time.Second
+	defaultDNS01ProviderBatchWindow     = 0
+
+	defaultDNS01SelfCheckWarnDuration = 0
+
+	defaultDNS01RecursiveNameserverFailoverThreshold = 0
+	defaultDNS01RecursiveNameserverFailoverCooldown  = time.Minute
 )
 
 var (
@@ -137,6 +455,9 @@ var (
 	defaultACMEHTTP01SolverResourceRequestMemory = "64Mi"
 	defaultACMEHTTP01SolverResourceLimitsCPU     = "100m"
 	defaultACMEHTTP01SolverResourceLimitsMemory  = "64Mi"
+	defaultACMEHTTP01SolverSharedDeployment      = false
+	defaultACMEHTTP01SolverSharedIngress         = false
+	defaultACMEDeferChallengeCleanup             = false
 
 	defaultAutoCertificateAnnotations = []string{"kubernetes.io/tls-acme"}
 
@@ -145,14 +466,19 @@ var (
 		clusterissuerscontroller.ControllerName,
 		certificatesmetricscontroller.ControllerName,
 		ingressshimcontroller.ControllerName,
+		ingressshimcontroller.GatewayControllerName,
 		orderscontroller.ControllerName,
 		challengescontroller.ControllerName,
 		cracmecontroller.CRControllerName,
+		cradcscontroller.CRControllerName,
 		crapprovercontroller.ControllerName,
 		crcacontroller.CRControllerName,
 		crselfsignedcontroller.CRControllerName,
 		crvaultcontroller.CRControllerName,
 		crvenaficontroller.CRControllerName,
+		issuancerecordscontroller.ControllerName,
+		cacrlcontroller.ControllerName,
+		webhookbootstrapcontroller.ControllerName,
 		// certificate controllers
 		trigger.ControllerName,
 		issuing.ControllerName,
@@ -160,6 +486,20 @@ var (
 		requestmanager.ControllerName,
 		readiness.ControllerName,
 		revisionmanager.ControllerName,
+		secretcleanup.ControllerName,
+		ocspstaple.ControllerName,
+		servicednsnames.ControllerName,
+		replicas.ControllerName,
+		canary.ControllerName,
+		csrcontroller.ControllerName,
+	}
+
+	// defaultOffControllers are not enabled by '*' and must be named
+	// explicitly in order to run.
+	defaultOffControllers = []string{
+		csrcontroller.ControllerName,
+		// the gateway-shim requires the Gateway API CRDs to be installed
+		ingressshimcontroller.GatewayControllerName,
 	}
 
 	defaultEnabledControllers = []string{"*"}
@@ -169,27 +509,49 @@ func NewControllerOptions() *ControllerOptions {
 	return &ControllerOptions{
 		APIServerHost:                     defaultAPIServerHost,
 		ClusterResourceNamespace:          defaultClusterResourceNamespace,
+		PerClusterIssuerResourceNamespace: defaultPerClusterIssuerResourceNamespace,
 		KubernetesAPIQPS:                  defaultKubernetesAPIQPS,
 		KubernetesAPIBurst:                defaultKubernetesAPIBurst,
+		KubernetesAPIReadQPS:              defaultKubernetesAPIReadQPS,
+		KubernetesAPIReadBurst:            defaultKubernetesAPIReadBurst,
 		Namespace:                         defaultNamespace,
 		LeaderElect:                       defaultLeaderElect,
 		LeaderElectionNamespace:           defaultLeaderElectionNamespace,
 		LeaderElectionLeaseDuration:       defaultLeaderElectionLeaseDuration,
 		LeaderElectionRenewDeadline:       defaultLeaderElectionRenewDeadline,
 		LeaderElectionRetryPeriod:         defaultLeaderElectionRetryPeriod,
+		LeaderElectionJitter:              defaultLeaderElectionJitter,
+		LeaderElectionGraceMultiplier:     defaultLeaderElectionGraceMultiplier,
 		controllers:                       defaultEnabledControllers,
 		ClusterIssuerAmbientCredentials:   defaultClusterIssuerAmbientCredentials,
 		IssuerAmbientCredentials:          defaultIssuerAmbientCredentials,
+		AmbientCredentialProviders:        []string{},
 		DefaultIssuerName:                 defaultTLSACMEIssuerName,
 		DefaultIssuerKind:                 defaultTLSACMEIssuerKind,
 		DefaultIssuerGroup:                defaultTLSACMEIssuerGroup,
 		DefaultAutoCertificateAnnotations: defaultAutoCertificateAnnotations,
+		AdditionalIssuerGroups:            []string{},
 		DNS01RecursiveNameservers:         []string{},
 		DNS01RecursiveNameserversOnly:     defaultDNS01RecursiveNameserversOnly,
 		EnableCertificateOwnerRef:         defaultEnableCertificateOwnerRef,
+		SecretDeletionGracePeriod:         defaultSecretDeletionGracePeriod,
+		ClusterDomain:                     defaultClusterDomain,
+		PrioritizeCertificatesByExpiry:    defaultPrioritizeCertificatesByExpiry,
+		EnableIssuanceRecords:             defaultEnableIssuanceRecords,
+		IssuanceRecordRetention:           defaultIssuanceRecordRetention,
+		PermanentErrorRequeueDelay:        defaultPermanentErrorRequeueDelay,
+		SerialNumberBits:                  defaultSerialNumberBits,
+		FIPSMode:                          defaultFIPSMode,
 		MetricsListenAddress:              defaultPrometheusMetricsServerAddress,
 		DNS01CheckRetryPeriod:             defaultDNS01CheckRetryPeriod,
+		DNS01CheckConcurrency:             defaultDNS01CheckConcurrency,
+		DNS01ProviderAPIRetries:           defaultDNS01ProviderAPIRetries,
+		DNS01ProviderAPIRetryBackoff:      defaultDNS01ProviderAPIRetryBackoff,
+		DNS01ProviderBatchWindow:          defaultDNS01ProviderBatchWindow,
+		DNS01SelfCheckWarnDuration:        defaultDNS01SelfCheckWarnDuration,
 		EnablePprof:                       false,
+		ReadinessProbeListenAddress:       defaultReadinessProbeListenAddress,
+		ReadinessIssuerKind:               defaultReadinessIssuerKind,
 	}
 }
 
@@ -201,9 +563,19 @@ func (s *ControllerOptions) AddFlags(fs *pflag.FlagSet) {
 		"Paths to a kubeconfig. Only required if out-of-cluster.")
 	fs.Float32Var(&s.KubernetesAPIQPS, "kube-api-qps", defaultKubernetesAPIQPS, "indicates the maximum queries-per-second requests to the Kubernetes apiserver")
 	fs.IntVar(&s.KubernetesAPIBurst, "kube-api-burst", defaultKubernetesAPIBurst, "the maximum burst queries-per-second of requests sent to the Kubernetes apiserver")
+	fs.Float32Var(&s.KubernetesAPIReadQPS, "api-read-qps", defaultKubernetesAPIReadQPS, ""+
+		"indicates the maximum queries-per-second of list and watch requests made to the Kubernetes apiserver "+
+		"when populating and resyncing the controllers' caches. This is applied separately from --kube-api-qps.")
+	fs.IntVar(&s.KubernetesAPIReadBurst, "api-read-burst", defaultKubernetesAPIReadBurst, ""+
+		"the maximum burst queries-per-second of list and watch requests made to the Kubernetes apiserver "+
+		"when populating and resyncing the controllers' caches. This is applied separately from --kube-api-burst.")
 	fs.StringVar(&s.ClusterResourceNamespace, "cluster-resource-namespace", defaultClusterResourceNamespace, ""+
 		"Namespace to store resources owned by cluster scoped resources such as ClusterIssuer in. "+
 		"This must be specified if ClusterIssuers are enabled.")
+	fs.BoolVar(&s.PerClusterIssuerResourceNamespace, "per-cluster-issuer-resource-namespace", defaultPerClusterIssuerResourceNamespace, ""+
+		"If true, a ClusterIssuer may set the cert-manager.io/cluster-resource-namespace annotation to store the "+
+		"resources it references, such as Secrets, in that namespace instead of --cluster-resource-namespace. "+
+		"This allows different ClusterIssuers to isolate their Secrets in namespaces with separate RBAC.")
 	fs.StringVar(&s.Namespace, "namespace", defaultNamespace, ""+
 		"If set, this limits the scope of cert-manager to a single namespace and ClusterIssuers are disabled. "+
 		"If not specified, all namespaces will be watched")
@@ -220,11 +592,19 @@ func (s *ControllerOptions) AddFlags(fs *pflag.FlagSet) {
 		"election is enabled.")
 	fs.DurationVar(&s.LeaderElectionRenewDeadline, "leader-election-renew-deadline", defaultLeaderElectionRenewDeadline, ""+
 		"The interval between attempts by the acting master to renew a leadership slot "+
-		"before it stops leading. This must be less than or equal to the lease duration. "+
+		"before it stops leading. This must be less than the lease duration. "+
 		"This is only applicable if leader election is enabled.")
 	fs.DurationVar(&s.LeaderElectionRetryPeriod, "leader-election-retry-period", defaultLeaderElectionRetryPeriod, ""+
 		"The duration the clients should wait between attempting acquisition and renewal "+
 		"of a leadership. This is only applicable if leader election is enabled.")
+	fs.Float64Var(&s.LeaderElectionJitter, "leader-election-jitter", defaultLeaderElectionJitter, ""+
+		"The maximum fraction, between 0 and 1, by which each instance randomly extends the lease duration and "+
+		"retry period when it starts, so that candidates do not attempt to acquire leadership in lockstep. "+
+		"This is only applicable if leader election is enabled.")
+	fs.Float64Var(&s.LeaderElectionGraceMultiplier, "leader-election-grace-multiplier", defaultLeaderElectionGraceMultiplier, ""+
+		"A multiplier, of at least 1, applied to the lease duration and renew deadline so that transient "+
+		"failures to renew the lease, such as on networks with intermittent latency, do not cause leadership "+
+		"to change. This is only applicable if leader election is enabled.")
 
 	fs.StringSliceVar(&s.controllers, "controllers", defaultEnabledControllers, fmt.Sprintf(""+
 		"A list of controllers to enable. '--controllers=*' enables all "+
@@ -237,6 +617,10 @@ func (s *ControllerOptions) AddFlags(fs *pflag.FlagSet) {
 		"The docker image to use to solve ACME HTTP01 challenges. You most likely will not "+
 		"need to change this parameter unless you are testing a new feature or developing cert-manager.")
 
+	fs.StringSliceVar(&s.ACMEHTTP01SolverImagePullSecrets, "acme-http01-solver-image-pull-secrets", []string{}, ""+
+		"A list of names of Secrets used to pull the ACME HTTP01 solver image, e.g. from a private registry. "+
+		"The Secrets must exist in the namespace of each challenge that is solved.")
+
 	fs.StringVar(&s.ACMEHTTP01SolverResourceRequestCPU, "acme-http01-solver-resource-request-cpu", defaultACMEHTTP01SolverResourceRequestCPU, ""+
 		"Defines the resource request CPU size when spawning new ACME HTTP01 challenge solver pods.")
 
@@ -249,27 +633,125 @@ func (s *ControllerOptions) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&s.ACMEHTTP01SolverResourceLimitsMemory, "acme-http01-solver-resource-limits-memory", defaultACMEHTTP01SolverResourceLimitsMemory, ""+
 		"Defines the resource limits Memory size when spawning new ACME HTTP01 challenge solver pods.")
 
+	fs.StringSliceVar(&s.ACMEHTTP01SolverNodeSelector, "acme-http01-solver-node-selector", []string{}, ""+
+		"A key=value pair to add to the node selector of ACME HTTP01 challenge solver pods. May be given multiple "+
+		"times. Node selectors configured in the pod template of a solver are added to these.")
+
+	fs.StringVar(&s.ACMEHTTP01SolverTolerations, "acme-http01-solver-tolerations", "", ""+
+		"A JSON encoded list of tolerations to set on ACME HTTP01 challenge solver pods, e.g. "+
+		`'[{"key":"dedicated","operator":"Equal","value":"acme","effect":"NoSchedule"}]'. `+
+		"Tolerations configured in the pod template of a solver are added to these.")
+
+	fs.BoolVar(&s.ACMEHTTP01SolverSharedDeployment, "acme-http01-solver-shared-deployment", defaultACMEHTTP01SolverSharedDeployment, ""+
+		"If true, ACME HTTP01 challenges are solved by a single long-lived solver Deployment in each "+
+		"namespace instead of a solver pod per challenge. Challenge tokens are distributed to the solver "+
+		"using a ConfigMap, so newly presented challenges may take up to a minute to be served. "+
+		"Pod templates configured on HTTP01 solvers are not applied to the shared Deployment.")
+	fs.BoolVar(&s.ACMEHTTP01SolverSharedIngress, "acme-http01-solver-shared-ingress", defaultACMEHTTP01SolverSharedIngress, ""+
+		"If true, ACME HTTP01 challenges that do not name an existing Ingress are routed through a single "+
+		"shared Ingress in each namespace (one per ingress class), with a path for each in-flight challenge, "+
+		"instead of an Ingress per challenge. The shared Ingress is deleted once it has no challenge paths left. "+
+		"Ingress templates configured on HTTP01 solvers are only applied when the shared Ingress is created.")
+	fs.BoolVar(&s.ACMEDeferChallengeCleanup, "acme-defer-challenge-cleanup", defaultACMEDeferChallengeCleanup, ""+
+		"If true, ACME challenge records are left in place until the Order that owns the challenge "+
+		"has reached a final state (valid, invalid or errored), rather than being cleaned up as soon "+
+		"as each challenge has been validated. This allows the records to be reused if finalizing the "+
+		"Order fails and is retried.")
+
 	fs.BoolVar(&s.ClusterIssuerAmbientCredentials, "cluster-issuer-ambient-credentials", defaultClusterIssuerAmbientCredentials, ""+
 		"Whether a cluster-issuer may make use of ambient credentials for issuers. 'Ambient Credentials' are credentials drawn from the environment, metadata services, or local files which are not explicitly configured in the ClusterIssuer API object. "+
 		"When this flag is enabled, the following sources for credentials are also used: "+
-		"AWS - All sources the Go SDK defaults to, notably including any EC2 IAM roles available via instance metadata.")
+		"AWS - All sources the Go SDK defaults to, notably including any EC2 IAM roles available via instance metadata. "+
+		"Vault - The ServiceAccount token file configured by tokenPath for Kubernetes auth.")
 	fs.BoolVar(&s.IssuerAmbientCredentials, "issuer-ambient-credentials", defaultIssuerAmbientCredentials, ""+
 		"Whether an issuer may make use of ambient credentials. 'Ambient Credentials' are credentials drawn from the environment, metadata services, or local files which are not explicitly configured in the Issuer API object. "+
 		"When this flag is enabled, the following sources for credentials are also used: "+
-		"AWS - All sources the Go SDK defaults to, notably including any EC2 IAM roles available via instance metadata.")
+		"AWS - All sources the Go SDK defaults to, notably including any EC2 IAM roles available via instance metadata. "+
+		"Vault - The ServiceAccount token file configured by tokenPath for Kubernetes auth.")
+	fs.StringSliceVar(&s.AmbientCredentialProviders, "ambient-credential-providers", []string{}, fmt.Sprintf(""+
+		"A list of providers that may use ambient credentials when they are enabled by "+
+		"--cluster-issuer-ambient-credentials or --issuer-ambient-credentials. If empty, all providers may "+
+		"use ambient credentials.\nAll providers: %s",
+		strings.Join(controllerpkg.KnownAmbientCredentialProviders, ", ")))
+	fs.StringVar(&s.IssuerBackendCABundle, "issuer-backend-ca-bundle", "", ""+
+		"Path to a PEM encoded CA bundle that will be trusted when connecting to ACME, ADCS, Vault and Venafi servers. "+
+		"If set, the system root CAs are ignored and only the certificates in this bundle are trusted. "+
+		"CA bundles configured on individual Issuers and ClusterIssuers take precedence for ADCS, Vault and Venafi.")
+	fs.BoolVar(&s.SkipIssuedCertificateValidityCheck, "skip-issued-certificate-validity-check", defaultSkipIssuedCertificateValidityCheck, ""+
+		"If true, certificates returned by issuers will be accepted even if they have already expired or "+
+		"are not valid until more than an hour in the future. By default, CertificateRequests are failed "+
+		"when this happens, so that the owning Certificate backs off before retrying issuance.")
+	fs.BoolVar(&s.EnableIssuanceRecords, "enable-issuance-records", defaultEnableIssuanceRecords, ""+
+		"If true, an IssuanceRecord resource is created in the CertificateRequest's namespace for every "+
+		"certificate that is issued, recording its issuer, subject alternative names, serial number and validity. "+
+		"IssuanceRecords are retained after the CertificateRequest has been deleted. "+
+		"The IssuanceRecord CustomResourceDefinition must be installed for this flag to be used.")
+	fs.DurationVar(&s.IssuanceRecordRetention, "issuance-record-retention", defaultIssuanceRecordRetention, ""+
+		"The duration IssuanceRecords are kept for before being deleted. A value of 0 keeps records forever. "+
+		"Only used if --enable-issuance-records is set.")
+	fs.StringVar(&s.WebhookBootstrapSecretNamespace, "webhook-bootstrap-secret-namespace", "", ""+
+		"The namespace of the Secret named by --webhook-bootstrap-secret-name.")
+	fs.StringVar(&s.WebhookBootstrapSecretName, "webhook-bootstrap-secret-name", "", ""+
+		"If set, a self signed serving certificate for the webhook is issued and stored in the named Secret, "+
+		"with the certificate also stored as 'ca.crt' so that it can be used as the caBundle of the webhook configurations. "+
+		"The certificate is signed by the controller itself and written directly to the Secret, without creating any "+
+		"Issuer, Certificate or CertificateRequest, so that it can be issued during installation before the webhook "+
+		"is able to admit cert-manager resources. It is reissued once two thirds of its duration has passed.")
+	fs.StringSliceVar(&s.WebhookBootstrapDNSNames, "webhook-bootstrap-dns-names", []string{}, ""+
+		"The DNS names of the webhook serving certificate stored in the Secret named by --webhook-bootstrap-secret-name.")
+	fs.DurationVar(&s.WebhookBootstrapCertificateDuration, "webhook-bootstrap-certificate-duration", defaultWebhookBootstrapCertificateDuration, ""+
+		"The duration of the webhook serving certificate stored in the Secret named by --webhook-bootstrap-secret-name.")
+	fs.DurationVar(&s.PermanentErrorRequeueDelay, "permanent-error-requeue-delay", defaultPermanentErrorRequeueDelay, ""+
+		"The duration controllers wait before retrying a resource that failed to be processed with a permanent error, "+
+		"such as a 4xx response from an issuer or invalid configuration. Transient errors, such as network errors and "+
+		"5xx responses, are retried with a short exponential backoff. A value of 0 disables retrying permanent errors "+
+		"until the resource is next changed.")
+	fs.IntVar(&s.SerialNumberBits, "serial-number-bits", defaultSerialNumberBits, fmt.Sprintf(""+
+		"The number of random bits in the serial numbers of certificates signed by CA and SelfSigned issuers. "+
+		"Must be between %d, the minimum required by the CA/Browser Forum Baseline Requirements, and %d, "+
+		"the most that fits in the 20 octets allowed by RFC 5280.", pki.MinSerialNumberBits, pki.MaxSerialNumberBits))
+	fs.IntVar(&s.MaxConcurrentSignsPerIssuer, "max-concurrent-signs-per-issuer", defaultMaxConcurrentSignsPerIssuer, ""+
+		"The maximum number of CertificateRequests that may be signed by a single Issuer or ClusterIssuer at once, "+
+		"so that an issuer that is slow or not responding cannot occupy all of the workers of its CertificateRequest "+
+		"controller and delay requests to other issuers of the same type. Requests over the limit are retried once "+
+		"others complete. If 0, the number of concurrent requests per issuer is not limited.")
+	fs.Float64Var(&s.VenafiRequestsPerSecond, "venafi-requests-per-second", defaultVenafiRequestsPerSecond, ""+
+		"The maximum number of requests per second made to Venafi TPP and Venafi Cloud by the Venafi CertificateRequest "+
+		"controller, shared across all Venafi issuers. CertificateRequests delayed by the limit are marked as pending "+
+		"with the reason RateLimited and retried once the limit allows. If 0, the rate of requests is not limited.")
+	fs.IntVar(&s.VenafiRequestsBurst, "venafi-requests-burst", defaultVenafiRequestsBurst, ""+
+		"The maximum number of requests made to Venafi TPP and Venafi Cloud at once before being limited by "+
+		"--venafi-requests-per-second.")
 	fs.StringSliceVar(&s.DefaultAutoCertificateAnnotations, "auto-certificate-annotations", defaultAutoCertificateAnnotations, ""+
-		"The annotation consumed by the ingress-shim controller to indicate a ingress is requesting a certificate")
+		"The annotation consumed by the ingress-shim and gateway-shim controllers to indicate a ingress or gateway is requesting a certificate")
 
 	fs.StringVar(&s.DefaultIssuerName, "default-issuer-name", defaultTLSACMEIssuerName, ""+
 		"Name of the Issuer to use when the tls is requested but issuer name is not specified on the ingress resource.")
 	fs.StringVar(&s.DefaultIssuerKind, "default-issuer-kind", defaultTLSACMEIssuerKind, ""+
 		"Kind of the Issuer to use when the tls is requested but issuer kind is not specified on the ingress resource.")
 	fs.StringVar(&s.DefaultIssuerGroup, "default-issuer-group", defaultTLSACMEIssuerGroup, ""+
-		"Group of the Issuer to use when the tls is requested but issuer group is not specified on the ingress resource.")
+		"Group of the Issuer to use when the tls is requested but issuer group is not specified on the ingress resource. "+
+		"Must be either "+cm.GroupName+" or one of the groups given in --additional-issuer-groups.")
+	fs.StringSliceVar(&s.AdditionalIssuerGroups, "additional-issuer-groups", []string{}, ""+
+		"A list of comma separated API groups of external issuers, for example awspca.cert-manager.io, "+
+		"that may be used as the --default-issuer-group.")
+	fs.BoolVar(&s.IngressShimDryRun, "ingress-shim-dry-run", defaultIngressShimDryRun, ""+
+		"If true, the ingress-shim controller does not create, update or delete any Certificates. Instead it logs "+
+		"the Certificates it would create, update or delete, including the resolved issuer, and records them as "+
+		"events on the Ingress. Useful to check ingress annotations and the --default-issuer-* flags before "+
+		"enabling ingress-shim on an existing cluster.")
 	fs.StringSliceVar(&s.DNS01RecursiveNameservers, "dns01-recursive-nameservers",
 		[]string{}, "A list of comma separated dns server endpoints used for "+
 			"DNS01 check requests. This should be a list containing host and "+
-			"port, for example 8.8.8.8:53,8.8.4.4:53")
+			"port, for example 8.8.8.8:53,8.8.4.4:53. DNS-over-HTTPS endpoints "+
+			"may be given as a URL, for example https://1.1.1.1/dns-query, and "+
+			"DNS-over-TLS servers using the tls:// prefix, for example tls://1.1.1.1:853")
+	fs.StringVar(&s.DNS01RecursiveNameserversFile, "dns01-recursive-nameservers-file", "", ""+
+		"Path to a file containing DNS server endpoints used for DNS01 check requests, one per line, in any of the "+
+		"forms accepted by --dns01-recursive-nameservers. Blank lines and lines starting with '#' are ignored, and "+
+		"invalid lines are logged and skipped. The file is watched and the nameservers are updated when it changes, "+
+		"without restarting the controller. If --dns01-recursive-nameservers is also set, both lists are used, "+
+		"with the nameservers in the file first.")
 	fs.BoolVar(&s.DNS01RecursiveNameserversOnly, "dns01-recursive-nameservers-only",
 		defaultDNS01RecursiveNameserversOnly,
 		"When true, cert-manager will only ever query the configured DNS resolvers "+
@@ -282,27 +764,180 @@ func (s *ControllerOptions) AddFlags(fs *pflag.FlagSet) {
 			"DNS01 check requests. This should be a list containing host and port, "+
 			"for example 8.8.8.8:53,8.8.4.4:53")
 	fs.MarkDeprecated("dns01-self-check-nameservers", "Deprecated in favour of dns01-recursive-nameservers")
+	fs.IntVar(&s.DNS01RecursiveNameserverFailoverThreshold, "dns01-recursive-nameserver-failover-threshold",
+		defaultDNS01RecursiveNameserverFailoverThreshold, ""+
+			"The number of consecutive failed queries after which a DNS01 recursive nameserver is considered "+
+			"unhealthy. Unhealthy nameservers are only queried once the others have failed, until the cooldown "+
+			"set by --dns01-recursive-nameserver-failover-cooldown has passed, and with "+
+			"--dns01-recursive-nameservers-only the self check only fails if every nameserver fails. "+
+			"If 0, nameservers are always queried in turn.")
+	fs.DurationVar(&s.DNS01RecursiveNameserverFailoverCooldown, "dns01-recursive-nameserver-failover-cooldown",
+		defaultDNS01RecursiveNameserverFailoverCooldown, ""+
+			"How long a DNS01 recursive nameserver that has reached the failover threshold is considered "+
+			"unhealthy before it is queried again. Only used if --dns01-recursive-nameserver-failover-threshold is set.")
 	fs.BoolVar(&s.EnableCertificateOwnerRef, "enable-certificate-owner-ref", defaultEnableCertificateOwnerRef, ""+
 		"Whether to set the certificate resource as an owner of secret where the tls certificate is stored. "+
 		"When this flag is enabled, the secret will be automatically removed when the certificate resource is deleted.")
+	fs.DurationVar(&s.SecretDeletionGracePeriod, "secret-deletion-grace-period", defaultSecretDeletionGracePeriod, ""+
+		"If set along with --enable-certificate-owner-ref, the secret of a deleted certificate is kept for this "+
+		"duration before being deleted by cert-manager, giving workloads using it time to drain. A finalizer is added "+
+		"to certificates to delay their deletion until then, instead of setting an owner reference on the secret.")
+	fs.StringVar(&s.ClusterDomain, "cluster-domain", defaultClusterDomain, ""+
+		"The DNS domain of the cluster. Used to compute the dnsNames of certificates that select services "+
+		"using spec.serviceSelector, which requires the ServiceDNSNames feature gate.")
+	fs.StringSliceVar(&s.MaintenanceWindows, "maintenance-windows", []string{}, ""+
+		"A list of maintenance windows during which certificates will not be issued or renewed, each in the form "+
+		"<start>/<end> where start and end are RFC 3339 timestamps, e.g. 2021-12-20T00:00:00Z/2022-01-03T09:00:00Z. "+
+		"Certificates that become due for issuance during a maintenance window are given an IssuanceDeferred "+
+		"condition and are issued once it ends.")
+	fs.BoolVar(&s.PrioritizeCertificatesByExpiry, "prioritize-certificates-by-expiry", defaultPrioritizeCertificatesByExpiry, ""+
+		"If true, the certificates-trigger controller processes queued certificates in order of their expiry rather "+
+		"than the order in which they were queued, so that certificates that are not yet issued, have expired or "+
+		"expire soonest are renewed first when a backlog has built up, e.g. after an outage.")
+	fs.IntVar(&s.MaxConcurrentIssuancesPerNamespace, "max-concurrent-issuances-per-namespace", defaultMaxConcurrentIssuancesPerNamespace, ""+
+		"The maximum number of Certificates in a single namespace that can be issued at once, so that a namespace "+
+		"with many Certificates due for issuance does not starve issuance in other namespaces. Certificates over "+
+		"the limit are given an IssuanceDeferred condition and are issued once other issuances in their namespace "+
+		"complete. If 0, the number of concurrent issuances is not limited.")
+	fs.StringVar(&s.TriggerOnSecretAnnotation, "trigger-on-secret-annotation", defaultTriggerOnSecretAnnotation, ""+
+		"The name of an annotation on Secrets which, when added, changed or removed, causes the Certificate that "+
+		"owns the Secret to be reconciled. This allows tooling that manages Secrets to signal cert-manager to "+
+		"re-examine a Certificate. If empty, Secret annotations do not trigger reconciles.")
+	fs.IntVar(&s.MaxInFlightCertificateRequests, "max-in-flight-certificate-requests", defaultMaxInFlightCertificateRequests, ""+
+		"The maximum number of CertificateRequests owned by a single Certificate that may be pending at once. "+
+		"A new CertificateRequest is not created for a Certificate until enough of its earlier requests have "+
+		"been issued, failed or been denied. If 0, the number of pending CertificateRequests is not limited.")
+	fs.IntVar(&s.CertificateRenewalHistoryLimit, "certificate-renewal-history-limit", defaultCertificateRenewalHistoryLimit, ""+
+		"The maximum number of records of issuance attempts, with their time, result and issuer, kept in the "+
+		"status.renewalHistory field of each Certificate. The oldest records are dropped once the limit is reached. "+
+		"If 0, no renewal history is recorded.")
+	fs.IntVar(&s.WarnSecretSize, "warn-secret-size", defaultWarnSecretSize, ""+
+		"The total size in bytes of the PEM encoded certificate chain, CA and private key stored in an issued Secret "+
+		"above which a Warning event is recorded on the Certificate, as some ingress controllers fail to load large "+
+		"certificates. Issuance is not blocked. If 0, no warning is recorded.")
+	fs.BoolVar(&s.KeepCertificatesReadyWithoutIssuer, "keep-certificates-ready-without-issuer", defaultKeepCertificatesReadyWithoutIssuer, ""+
+		"If true, a Certificate whose Issuer or ClusterIssuer does not exist remains Ready for as long as its "+
+		"existing certificate is valid, so that it keeps being served until the issuer is recreated. If false, "+
+		"the Certificate is marked as not Ready. The IssuerNotFound condition is set on the Certificate either way.")
+	fs.DurationVar(&s.IssuerNotReadyThreshold, "issuer-not-ready-threshold", defaultIssuerNotReadyThreshold, ""+
+		"The duration for which the Issuer or ClusterIssuer referenced by a Certificate must have not been Ready "+
+		"before an IssuerNotReady condition is set on the Certificate. The Ready condition of the Certificate is "+
+		"not changed, so an existing valid certificate continues to be served. If 0, the condition is never set.")
+	fs.DurationVar(&s.RenewBeforeExpiryDuration, "renew-before-expiry-duration", defaultRenewBeforeExpiryDuration, ""+
+		"How long before they expire certificates are renewed if their Certificate does not set spec.renewBefore. "+
+		"Certificates that are not valid for longer than this are renewed when a third of their lifetime remains.")
+	fs.IntVar(&s.RenewBeforeExpiryPercentage, "renew-before-expiry-percentage", defaultRenewBeforeExpiryPercentage, ""+
+		"The percentage of their lifetime, computed from their notBefore and notAfter times, that must remain before "+
+		"certificates are renewed if their Certificate does not set spec.renewBefore, e.g. 33 to renew certificates "+
+		"when a third of their lifetime remains. Takes precedence over --renew-before-expiry-duration if non-zero. "+
+		"Must be between 0 and 99.")
+	fs.StringVar(&s.AdditionalTrustBundle, "additional-trust-bundle", "", ""+
+		"Path to a PEM encoded bundle of CA certificates that will be appended to the ca.crt of every Secret "+
+		"issued for a Certificate, in addition to the CA returned by the issuer. Certificates already present "+
+		"in the issuer's CA are not duplicated. Existing Secrets are updated when they are next renewed.")
+	fs.StringSliceVar(&s.AllowedIssuers, "allowed-issuers", []string{}, ""+
+		"A list of the issuers that Certificates are allowed to reference, each as a pattern in the form "+
+		"<kind>[.<group>]/<name> where the group defaults to cert-manager.io and each part may contain * wildcards, "+
+		"e.g. ClusterIssuer/letsencrypt-* or Issuer/*. Certificates referencing any other issuer are given an "+
+		"IssuerNotAllowed condition and are not issued. If empty, every issuer is allowed.")
+	fs.DurationVar(&s.IssuerReadinessBackoffMax, "issuer-readiness-backoff-max", defaultIssuerReadinessBackoffMax, ""+
+		"The maximum delay between checks of whether the Issuer or ClusterIssuer referenced by a Certificate is ready. "+
+		"No CertificateRequest is created for a Certificate whose issuer is not ready; instead the IssuanceDeferred "+
+		"condition is set with the reason IssuerNotReady and the issuer is checked again after a delay that doubles "+
+		"each time, up to this maximum. If 0, CertificateRequests are created regardless of whether the issuer is ready.")
+	fs.DurationVar(&s.DefaultIssuanceTimeout, "default-issuance-timeout", defaultIssuanceTimeout, ""+
+		"The maximum amount of time an issuance may remain in progress before the Certificate's Issuing condition "+
+		"is set to False with the reason IssuanceTimeout and the issuance is retried later with backoff. Certificates "+
+		"may override this with spec.issuanceTimeout. If 0, issuances of Certificates that do not set "+
+		"spec.issuanceTimeout never time out.")
+	fs.BoolVar(&s.SkipTerminatingNamespaces, "skip-terminating-namespaces", defaultSkipTerminatingNamespaces, ""+
+		"If true, Certificates in namespaces that are being deleted are not issued, and any issuance in progress "+
+		"is abandoned, so that cert-manager does not attempt to create resources in the namespace while it is "+
+		"terminating. The IssuanceDeferred condition is set on the Certificates instead.")
+	fs.BoolVar(&s.FIPSMode, "fips-mode", defaultFIPSMode, ""+
+		"If true, private keys will only be generated using FIPS approved algorithms and key sizes "+
+		"(RSA 2048 or 3072, ECDSA P-256 or P-384), and Certificates requesting any other key type will fail. "+
+		"cert-manager must be built with a boringcrypto enabled Go toolchain for this flag to be used.")
 	fs.IntVar(&s.MaxConcurrentChallenges, "max-concurrent-challenges", defaultMaxConcurrentChallenges, ""+
 		"The maximum number of challenges that can be scheduled as 'processing' at once.")
+	fs.IntVar(&s.MaxConcurrentHTTP01Challenges, "max-concurrent-http01-challenges", 0, ""+
+		"The maximum number of HTTP01 challenges that can be scheduled as 'processing' at once. "+
+		"If zero, HTTP01 challenges are only limited by --max-concurrent-challenges.")
+	fs.IntVar(&s.MaxConcurrentDNS01Challenges, "max-concurrent-dns01-challenges", 0, ""+
+		"The maximum number of DNS01 challenges that can be scheduled as 'processing' at once. "+
+		"If zero, DNS01 challenges are only limited by --max-concurrent-challenges.")
 	fs.DurationVar(&s.DNS01CheckRetryPeriod, "dns01-check-retry-period", defaultDNS01CheckRetryPeriod, ""+
 		"The duration the controller should wait between checking if a ACME dns entry exists."+
 		"This should be a valid duration string, for example 180s or 1h")
+	fs.IntVar(&s.DNS01CheckConcurrency, "dns01-check-concurrency", defaultDNS01CheckConcurrency, ""+
+		"The maximum number of ACME DNS01 propagation self checks that can be run in parallel. "+
+		"Self checks are run in the background, so a Certificate with many dnsNames does not block "+
+		"the processing of other challenges whilst its records propagate.")
+	fs.IntVar(&s.DNS01ProviderAPIRetries, "dns01-provider-api-retries", defaultDNS01ProviderAPIRetries, ""+
+		"The number of times a failed DNS01 provider API call to present or clean up a challenge "+
+		"record is retried before the error is reported on the Challenge.")
+	fs.DurationVar(&s.DNS01ProviderAPIRetryBackoff, "dns01-provider-api-retry-backoff", defaultDNS01ProviderAPIRetryBackoff, ""+
+		"The initial time to wait before retrying a failed DNS01 provider API call. "+
+		"It is doubled after each failed attempt, up to a maximum of 1m.")
+	fs.DurationVar(&s.DNS01ProviderBatchWindow, "dns01-provider-batch-window", defaultDNS01ProviderBatchWindow, ""+
+		"The time to wait for other DNS01 challenge records in the same zone to be presented or cleaned up, "+
+		"so that they can be changed by a single API call. Only the Route53 and Cloudflare providers support "+
+		"batching. If zero, each record is changed by its own API call.")
+	fs.DurationVar(&s.DNS01SelfCheckWarnDuration, "dns01-self-check-warn-duration", defaultDNS01SelfCheckWarnDuration, ""+
+		"The duration after which an ACME DNS01 self check is considered slow, and a Warning event is recorded "+
+		"on the Challenge. The duration of every self check is also exposed by the "+
+		"certmanager_dns01_self_check_duration_seconds metric. If zero, no events are recorded.")
 
 	fs.StringVar(&s.MetricsListenAddress, "metrics-listen-address", defaultPrometheusMetricsServerAddress, ""+
 		"The host and port that the metrics endpoint should listen on.")
 	fs.BoolVar(&s.EnablePprof, "enable-profiling", false, ""+
 		"Enable profiling for controller.")
+
+	fs.StringVar(&s.ReadinessProbeListenAddress, "readiness-probe-listen-address", defaultReadinessProbeListenAddress, ""+
+		"The host and port that the /readyz readiness probe endpoint should listen on. The endpoint reports "+
+		"ready once this instance has acquired leader election and, if --readiness-issuer-name is set, the "+
+		"named issuer is Ready. If empty, the endpoint is not served.")
+	fs.StringVar(&s.ReadinessIssuerName, "readiness-issuer-name", "", ""+
+		"Name of an issuer that must be Ready for the /readyz endpoint to report ready. Issuers must be given as "+
+		"<namespace>/<name>, unless cert-manager is scoped to a single namespace with --namespace.")
+	fs.StringVar(&s.ReadinessIssuerKind, "readiness-issuer-kind", defaultReadinessIssuerKind, ""+
+		"Kind of the issuer named by --readiness-issuer-name, either Issuer or ClusterIssuer.")
+
+	fs.StringVar(&s.GRPCListenAddress, "grpc-address", "", ""+
+		"The host and port that the gRPC issuance status service and standard gRPC health service should "+
+		"listen on. The issuance status service answers queries for the Ready status, notAfter time and current "+
+		"CertificateRequest of Certificates from the controller's caches, and so is only available on the elected "+
+		"leader. If empty, the services are not served. If no host is given, for example ':9404', only the "+
+		"loopback interface is listened on.")
+	fs.StringVar(&s.GRPCTLSCertFile, "grpc-tls-cert-file", "", ""+
+		"Path to the file containing the TLS certificate that the gRPC services are served with. "+
+		"Required if --grpc-address is set.")
+	fs.StringVar(&s.GRPCTLSKeyFile, "grpc-tls-private-key-file", "", ""+
+		"Path to the file containing the TLS private key that the gRPC services are served with. "+
+		"Required if --grpc-address is set.")
 }
 
 func (o *ControllerOptions) Validate() error {
-	switch o.DefaultIssuerKind {
-	case "Issuer":
-	case "ClusterIssuer":
+	switch o.DefaultIssuerGroup {
+	case "", cm.GroupName:
+		switch o.DefaultIssuerKind {
+		case "Issuer":
+		case "ClusterIssuer":
+		default:
+			return fmt.Errorf("invalid default issuer kind: %v", o.DefaultIssuerKind)
+		}
 	default:
-		return fmt.Errorf("invalid default issuer kind: %v", o.DefaultIssuerKind)
+		// the kinds of external issuers are defined by their own API groups,
+		// so only the group itself can be checked here
+		if !sets.NewString(o.AdditionalIssuerGroups...).Has(o.DefaultIssuerGroup) {
+			return fmt.Errorf("invalid default issuer group: %q is not %s or one of the groups given in --additional-issuer-groups", o.DefaultIssuerGroup, cm.GroupName)
+		}
+	}
+
+	if o.LeaderElect {
+		if err := o.validateLeaderElection(); err != nil {
+			return err
+		}
 	}
 
 	if o.KubernetesAPIBurst <= 0 {
@@ -313,18 +948,171 @@ func (o *ControllerOptions) Validate() error {
 		return fmt.Errorf("invalid value for kube-api-qps: %v must be higher than 0", o.KubernetesAPIQPS)
 	}
 
+	if o.KubernetesAPIReadBurst <= 0 {
+		return fmt.Errorf("invalid value for api-read-burst: %v must be higher than 0", o.KubernetesAPIReadBurst)
+	}
+
+	if o.KubernetesAPIReadQPS <= 0 {
+		return fmt.Errorf("invalid value for api-read-qps: %v must be higher than 0", o.KubernetesAPIReadQPS)
+	}
+
+	if float32(o.KubernetesAPIReadBurst) < o.KubernetesAPIReadQPS {
+		return fmt.Errorf("invalid value for api-read-burst: %v must be higher or equal to api-read-qps: %v", o.KubernetesAPIReadBurst, o.KubernetesAPIReadQPS)
+	}
+
+	if o.DNS01CheckConcurrency <= 0 {
+		return fmt.Errorf("invalid value for dns01-check-concurrency: %v must be higher than 0", o.DNS01CheckConcurrency)
+	}
+
+	if o.DNS01ProviderAPIRetries < 0 {
+		return fmt.Errorf("invalid value for dns01-provider-api-retries: %v must not be negative", o.DNS01ProviderAPIRetries)
+	}
+
+	if o.DNS01ProviderAPIRetryBackoff < 0 {
+		return fmt.Errorf("invalid value for dns01-provider-api-retry-backoff: %v must not be negative", o.DNS01ProviderAPIRetryBackoff)
+	}
+
+	if o.DNS01ProviderBatchWindow < 0 {
+		return fmt.Errorf("invalid value for dns01-provider-batch-window: %v must not be negative", o.DNS01ProviderBatchWindow)
+	}
+
+	if o.DNS01SelfCheckWarnDuration < 0 {
+		return fmt.Errorf("invalid value for dns01-self-check-warn-duration: %v must not be negative", o.DNS01SelfCheckWarnDuration)
+	}
+
+	if o.SecretDeletionGracePeriod < 0 {
+		return fmt.Errorf("invalid value for secret-deletion-grace-period: %v must not be negative", o.SecretDeletionGracePeriod)
+	}
+
+	if _, err := acmehttp.ParseSolverNodeSelector(o.ACMEHTTP01SolverNodeSelector); err != nil {
+		return fmt.Errorf("invalid value for acme-http01-solver-node-selector: %v", err)
+	}
+
+	if _, err := acmehttp.ParseSolverTolerations(o.ACMEHTTP01SolverTolerations); err != nil {
+		return fmt.Errorf("invalid value for acme-http01-solver-tolerations: %v", err)
+	}
+
+	if _, err := maintenance.ParseWindows(o.MaintenanceWindows); err != nil {
+		return fmt.Errorf("invalid value for maintenance-windows: %v", err)
+	}
+
+	if _, err := issuerallowlist.ParseAllowlist(o.AllowedIssuers); err != nil {
+		return fmt.Errorf("invalid value for allowed-issuers: %v", err)
+	}
+
+	if o.GRPCListenAddress != "" && (o.GRPCTLSCertFile == "" || o.GRPCTLSKeyFile == "") {
+		return fmt.Errorf("invalid value for grpc-address: grpc-tls-cert-file and grpc-tls-private-key-file must be set to serve the gRPC services")
+	}
+
+	if o.MaxInFlightCertificateRequests < 0 {
+		return fmt.Errorf("invalid value for max-in-flight-certificate-requests: %v must not be negative", o.MaxInFlightCertificateRequests)
+	}
+
+	if o.CertificateRenewalHistoryLimit < 0 {
+		return fmt.Errorf("invalid value for certificate-renewal-history-limit: %v must not be negative", o.CertificateRenewalHistoryLimit)
+	}
+
+	if o.WarnSecretSize < 0 {
+		return fmt.Errorf("invalid value for warn-secret-size: %v must not be negative", o.WarnSecretSize)
+	}
+
+	if o.IssuerNotReadyThreshold < 0 {
+		return fmt.Errorf("invalid value for issuer-not-ready-threshold: %v must not be negative", o.IssuerNotReadyThreshold)
+	}
+
+	if o.IssuerReadinessBackoffMax < 0 {
+		return fmt.Errorf("invalid value for issuer-readiness-backoff-max: %v must not be negative", o.IssuerReadinessBackoffMax)
+	}
+
+	if o.DefaultIssuanceTimeout < 0 {
+		return fmt.Errorf("invalid value for default-issuance-timeout: %v must not be negative", o.DefaultIssuanceTimeout)
+	}
+
+	if o.RenewBeforeExpiryDuration < 0 {
+		return fmt.Errorf("invalid value for renew-before-expiry-duration: %v must not be negative", o.RenewBeforeExpiryDuration)
+	}
+
+	if o.RenewBeforeExpiryPercentage < 0 || o.RenewBeforeExpiryPercentage > 99 {
+		return fmt.Errorf("invalid value for renew-before-expiry-percentage: %v must be between 0 and 99", o.RenewBeforeExpiryPercentage)
+	}
+
+	if o.MaxConcurrentIssuancesPerNamespace < 0 {
+		return fmt.Errorf("invalid value for max-concurrent-issuances-per-namespace: %v must not be negative", o.MaxConcurrentIssuancesPerNamespace)
+	}
+
+	if o.IssuanceRecordRetention < 0 {
+		return fmt.Errorf("invalid value for issuance-record-retention: %v must not be negative", o.IssuanceRecordRetention)
+	}
+
+	if o.WebhookBootstrapSecretName != "" {
+		if o.WebhookBootstrapSecretNamespace == "" {
+			return fmt.Errorf("invalid value for webhook-bootstrap-secret-namespace: must be set if webhook-bootstrap-secret-name is set")
+		}
+		if len(o.WebhookBootstrapDNSNames) == 0 {
+			return fmt.Errorf("invalid value for webhook-bootstrap-dns-names: must be set if webhook-bootstrap-secret-name is set")
+		}
+		if o.WebhookBootstrapCertificateDuration < time.Hour {
+			return fmt.Errorf("invalid value for webhook-bootstrap-certificate-duration: %v must be at least 1h", o.WebhookBootstrapCertificateDuration)
+		}
+	}
+
+	if o.PermanentErrorRequeueDelay < 0 {
+		return fmt.Errorf("invalid value for permanent-error-requeue-delay: %v must not be negative", o.PermanentErrorRequeueDelay)
+	}
+
+	if o.SerialNumberBits < pki.MinSerialNumberBits || o.SerialNumberBits > pki.MaxSerialNumberBits {
+		return fmt.Errorf("invalid value for serial-number-bits: %v must be between %d and %d", o.SerialNumberBits, pki.MinSerialNumberBits, pki.MaxSerialNumberBits)
+	}
+
+	if o.MaxConcurrentSignsPerIssuer < 0 {
+		return fmt.Errorf("invalid value for max-concurrent-signs-per-issuer: %v must not be negative", o.MaxConcurrentSignsPerIssuer)
+	}
+
+	if o.VenafiRequestsPerSecond < 0 {
+		return fmt.Errorf("invalid value for venafi-requests-per-second: %v must not be negative", o.VenafiRequestsPerSecond)
+	}
+
+	if o.VenafiRequestsPerSecond > 0 && o.VenafiRequestsBurst < 1 {
+		return fmt.Errorf("invalid value for venafi-requests-burst: %v must be at least 1", o.VenafiRequestsBurst)
+	}
+
+	if o.MaxConcurrentHTTP01Challenges < 0 {
+		return fmt.Errorf("invalid value for max-concurrent-http01-challenges: %v must not be negative", o.MaxConcurrentHTTP01Challenges)
+	}
+
+	if o.MaxConcurrentDNS01Challenges < 0 {
+		return fmt.Errorf("invalid value for max-concurrent-dns01-challenges: %v must not be negative", o.MaxConcurrentDNS01Challenges)
+	}
+
+	if _, err := o.ReadinessIssuerRef(); err != nil {
+		return err
+	}
+
 	if float32(o.KubernetesAPIBurst) < o.KubernetesAPIQPS {
 		return fmt.Errorf("invalid value for kube-api-burst: %v must be higher or equal to kube-api-qps: %v", o.KubernetesAPIQPS, o.KubernetesAPIQPS)
 	}
 
 	for _, server := range o.DNS01RecursiveNameservers {
-		// ensure all servers have a port number
-		_, _, err := net.SplitHostPort(server)
-		if err != nil {
+		if err := dnsutil.ValidateNameserver(server); err != nil {
 			return fmt.Errorf("invalid DNS server (%v): %v", err, server)
 		}
 	}
 
+	if o.DNS01RecursiveNameserverFailoverThreshold < 0 {
+		return fmt.Errorf("invalid value for dns01-recursive-nameserver-failover-threshold: %v must not be negative", o.DNS01RecursiveNameserverFailoverThreshold)
+	}
+
+	if o.DNS01RecursiveNameserverFailoverCooldown < 0 {
+		return fmt.Errorf("invalid value for dns01-recursive-nameserver-failover-cooldown: %v must not be negative", o.DNS01RecursiveNameserverFailoverCooldown)
+	}
+
+	knownProviders := sets.NewString(controllerpkg.KnownAmbientCredentialProviders...)
+	for _, provider := range o.AmbientCredentialProviders {
+		if !knownProviders.Has(provider) {
+			return fmt.Errorf("invalid value for ambient-credential-providers: %q is not a known provider", provider)
+		}
+	}
+
 	errs := []error{}
 	allControllersSet := sets.NewString(allControllers...)
 	for _, controller := range o.controllers {
@@ -345,6 +1133,36 @@ func (o *ControllerOptions) Validate() error {
 	return nil
 }
 
+// validateLeaderElection checks the relationships between the leader election
+// durations that are required by client-go, taking into account the largest
+// retry period that jitter may produce.
+func (o *ControllerOptions) validateLeaderElection() error {
+	if o.LeaderElectionJitter < 0 || o.LeaderElectionJitter > 1 {
+		return fmt.Errorf("invalid value for leader-election-jitter: %v must be between 0 and 1", o.LeaderElectionJitter)
+	}
+
+	if o.LeaderElectionGraceMultiplier < 1 {
+		return fmt.Errorf("invalid value for leader-election-grace-multiplier: %v must be at least 1", o.LeaderElectionGraceMultiplier)
+	}
+
+	if o.LeaderElectionRetryPeriod <= 0 {
+		return fmt.Errorf("invalid value for leader-election-retry-period: %v must be higher than 0", o.LeaderElectionRetryPeriod)
+	}
+
+	if o.LeaderElectionRenewDeadline >= o.LeaderElectionLeaseDuration {
+		return fmt.Errorf("invalid value for leader-election-renew-deadline: %v must be less than the leader-election-lease-duration of %v",
+			o.LeaderElectionRenewDeadline, o.LeaderElectionLeaseDuration)
+	}
+
+	maxRetryPeriod := time.Duration(float64(o.LeaderElectionRetryPeriod) * (1 + o.LeaderElectionJitter) * leaderelection.JitterFactor)
+	if o.LeaderElectionRenewDeadline <= maxRetryPeriod {
+		return fmt.Errorf("invalid value for leader-election-renew-deadline: %v must be greater than %v, the leader-election-retry-period "+
+			"including jitter", o.LeaderElectionRenewDeadline, maxRetryPeriod)
+	}
+
+	return nil
+}
+
 func (o *ControllerOptions) EnabledControllers() sets.String {
 	var disabled []string
 	enabled := sets.NewString()
@@ -352,7 +1170,7 @@ func (o *ControllerOptions) EnabledControllers() sets.String {
 	for _, controller := range o.controllers {
 		switch {
 		case controller == "*":
-			enabled = enabled.Insert(allControllers...)
+			enabled = enabled.Insert(sets.NewString(allControllers...).Delete(defaultOffControllers...).List()...)
 		case strings.HasPrefix(controller, "-"):
 			disabled = append(disabled, strings.TrimPrefix(controller, "-"))
 		default:
@@ -364,3 +1182,33 @@ func (o *ControllerOptions) EnabledControllers() sets.String {
 
 	return enabled
 }
+
+// ReadinessIssuerRef returns a reference to the issuer that readiness is gated
+// on, or nil if no issuer has been configured.
+func (o *ControllerOptions) ReadinessIssuerRef() (*healthz.IssuerRef, error) {
+	if o.ReadinessIssuerName == "" {
+		return nil, nil
+	}
+
+	switch o.ReadinessIssuerKind {
+	case "ClusterIssuer":
+		if o.Namespace != "" {
+			return nil, fmt.Errorf("invalid value for readiness-issuer-kind: ClusterIssuers cannot be used when cert-manager is scoped to a single namespace")
+		}
+		return &healthz.IssuerRef{Kind: o.ReadinessIssuerKind, Name: o.ReadinessIssuerName}, nil
+	case "Issuer":
+		namespace, name := o.Namespace, o.ReadinessIssuerName
+		if parts := strings.SplitN(o.ReadinessIssuerName, "/", 2); len(parts) == 2 {
+			namespace, name = parts[0], parts[1]
+		}
+		if namespace == "" || name == "" {
+			return nil, fmt.Errorf("invalid value for readiness-issuer-name: %q must be of the form <namespace>/<name>", o.ReadinessIssuerName)
+		}
+		if o.Namespace != "" && namespace != o.Namespace {
+			return nil, fmt.Errorf("invalid value for readiness-issuer-name: %q is not in the namespace cert-manager is scoped to", o.ReadinessIssuerName)
+		}
+		return &healthz.IssuerRef{Kind: o.ReadinessIssuerKind, Namespace: namespace, Name: name}, nil
+	default:
+		return nil, fmt.Errorf("invalid readiness issuer kind: %v", o.ReadinessIssuerKind)
+	}
+}
@@ -17,9 +17,15 @@ limitations under the License.
 package options
 
 import (
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"k8s.io/apimachinery/pkg/util/sets"
+
+	ingressshimcontroller "github.com/jetstack/cert-manager/pkg/controller/ingress-shim"
+	"github.com/jetstack/cert-manager/pkg/healthz"
 )
 
 func TestEnabledControllers(t *testing.T) {
@@ -39,13 +45,21 @@ func TestEnabledControllers(t *testing.T) {
 			controllers: []string{"foo", "bar", "-foo"},
 			expEnabled:  sets.NewString("bar"),
 		},
-		"if all controllers enabled, return all controllers": {
+		"if all controllers enabled, return all on-by-default controllers": {
 			controllers: []string{"*"},
-			expEnabled:  sets.NewString(allControllers...),
+			expEnabled:  sets.NewString(allControllers...).Delete(defaultOffControllers...),
 		},
 		"if all controllers enabled, some diabled, return all controllers with disabled": {
 			controllers: []string{"*", "-clusrerissuers", "-issuer"},
-			expEnabled:  sets.NewString(allControllers...).Delete("-clusterissuers", "-issuers"),
+			expEnabled:  sets.NewString(allControllers...).Delete(defaultOffControllers...).Delete("-clusterissuers", "-issuers"),
+		},
+		"if all controllers enabled along with an off-by-default controller, return all on-by-default controllers and that controller": {
+			controllers: []string{"*", "certificatesigningrequests"},
+			expEnabled:  sets.NewString(allControllers...).Delete(ingressshimcontroller.GatewayControllerName),
+		},
+		"if all controllers enabled along with the gateway-shim controller, return all on-by-default controllers and the gateway-shim controller": {
+			controllers: []string{"*", ingressshimcontroller.GatewayControllerName},
+			expEnabled:  sets.NewString(allControllers...).Delete("certificatesigningrequests"),
 		},
 	}
 
@@ -63,3 +77,596 @@ func TestEnabledControllers(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateDNS01CheckConcurrency(t *testing.T) {
+	tests := map[string]struct {
+		concurrency int
+		expErr      bool
+	}{
+		"if concurrency is positive, no error": {
+			concurrency: 5,
+			expErr:      false,
+		},
+		"if concurrency is zero, error": {
+			concurrency: 0,
+			expErr:      true,
+		},
+		"if concurrency is negative, error": {
+			concurrency: -1,
+			expErr:      true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := NewControllerOptions()
+			o.DNS01CheckConcurrency = test.concurrency
+
+			err := o.Validate()
+			if test.expErr != (err != nil) {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateDNS01RecursiveNameserverFailover(t *testing.T) {
+	tests := map[string]struct {
+		threshold int
+		cooldown  time.Duration
+		expErr    bool
+	}{
+		"if failover is disabled, no error": {
+			threshold: 0,
+			cooldown:  time.Minute,
+			expErr:    false,
+		},
+		"if a threshold and cooldown are given, no error": {
+			threshold: 3,
+			cooldown:  5 * time.Minute,
+			expErr:    false,
+		},
+		"if the threshold is negative, error": {
+			threshold: -1,
+			cooldown:  time.Minute,
+			expErr:    true,
+		},
+		"if the cooldown is negative, error": {
+			threshold: 3,
+			cooldown:  -time.Minute,
+			expErr:    true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := NewControllerOptions()
+			o.DNS01RecursiveNameserverFailoverThreshold = test.threshold
+			o.DNS01RecursiveNameserverFailoverCooldown = test.cooldown
+
+			err := o.Validate()
+			if test.expErr != (err != nil) {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateAPIReadRateLimit(t *testing.T) {
+	tests := map[string]struct {
+		qps    float32
+		burst  int
+		expErr bool
+	}{
+		"if qps and burst are positive, no error": {
+			qps:   10,
+			burst: 20,
+		},
+		"if qps is zero, error": {
+			qps:    0,
+			burst:  20,
+			expErr: true,
+		},
+		"if burst is negative, error": {
+			qps:    10,
+			burst:  -1,
+			expErr: true,
+		},
+		"if burst is lower than qps, error": {
+			qps:    10,
+			burst:  5,
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := NewControllerOptions()
+			o.KubernetesAPIReadQPS = test.qps
+			o.KubernetesAPIReadBurst = test.burst
+
+			err := o.Validate()
+			if test.expErr != (err != nil) {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateDNS01RecursiveNameservers(t *testing.T) {
+	tests := map[string]struct {
+		nameservers []string
+		expErr      bool
+	}{
+		"if a host and port are given, no error": {
+			nameservers: []string{"8.8.8.8:53"},
+		},
+		"if no port is given, error": {
+			nameservers: []string{"8.8.8.8"},
+			expErr:      true,
+		},
+		"if a DNS-over-HTTPS endpoint is given, no error": {
+			nameservers: []string{"https://1.1.1.1/dns-query"},
+		},
+		"if a DNS-over-HTTPS endpoint has no host, error": {
+			nameservers: []string{"https:///dns-query"},
+			expErr:      true,
+		},
+		"if a DNS-over-TLS server is given, no error": {
+			nameservers: []string{"tls://1.1.1.1:853", "tls://1.1.1.1"},
+		},
+		"if a DNS-over-TLS server has no host, error": {
+			nameservers: []string{"tls://"},
+			expErr:      true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := NewControllerOptions()
+			o.DNS01RecursiveNameservers = test.nameservers
+
+			err := o.Validate()
+			if test.expErr != (err != nil) {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateDefaultIssuerGroup(t *testing.T) {
+	tests := map[string]struct {
+		group            string
+		kind             string
+		additionalGroups []string
+		expErr           bool
+	}{
+		"if the built-in group is given, no error": {
+			group: "cert-manager.io",
+			kind:  "ClusterIssuer",
+		},
+		"if the built-in group is given with an unknown kind, error": {
+			group:  "cert-manager.io",
+			kind:   "AWSPCAClusterIssuer",
+			expErr: true,
+		},
+		"if an external group is given that is not in the allow-list, error": {
+			group:  "awspca.cert-manager.io",
+			kind:   "AWSPCAClusterIssuer",
+			expErr: true,
+		},
+		"if a misspelled group is given, error": {
+			group:            "cert-manger.io",
+			kind:             "ClusterIssuer",
+			additionalGroups: []string{"awspca.cert-manager.io"},
+			expErr:           true,
+		},
+		"if an external group in the allow-list is given, no error": {
+			group:            "awspca.cert-manager.io",
+			kind:             "AWSPCAClusterIssuer",
+			additionalGroups: []string{"example.com", "awspca.cert-manager.io"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := NewControllerOptions()
+			o.DefaultIssuerGroup = test.group
+			o.DefaultIssuerKind = test.kind
+			o.AdditionalIssuerGroups = test.additionalGroups
+
+			err := o.Validate()
+			if test.expErr != (err != nil) {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+			if err != nil && !strings.Contains(err.Error(), test.group) && test.group != "cert-manager.io" {
+				t.Errorf("expected error to name the group %q, got=%v", test.group, err)
+			}
+		})
+	}
+}
+
+func TestValidateSerialNumberBits(t *testing.T) {
+	tests := map[string]struct {
+		bits   int
+		expErr bool
+	}{
+		"if bits is the minimum, no error": {
+			bits:   64,
+			expErr: false,
+		},
+		"if bits is the maximum, no error": {
+			bits:   158,
+			expErr: false,
+		},
+		"if bits is below the minimum, error": {
+			bits:   63,
+			expErr: true,
+		},
+		"if bits is above the maximum, error": {
+			bits:   159,
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := NewControllerOptions()
+			o.SerialNumberBits = test.bits
+
+			err := o.Validate()
+			if test.expErr != (err != nil) {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateRenewBeforeExpiryPercentage(t *testing.T) {
+	tests := map[string]struct {
+		percentage int
+		expErr     bool
+	}{
+		"if the percentage is zero, no error": {
+			percentage: 0,
+			expErr:     false,
+		},
+		"if the percentage is the maximum, no error": {
+			percentage: 99,
+			expErr:     false,
+		},
+		"if the percentage is negative, error": {
+			percentage: -1,
+			expErr:     true,
+		},
+		"if the percentage is 100, error": {
+			percentage: 100,
+			expErr:     true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := NewControllerOptions()
+			o.RenewBeforeExpiryPercentage = test.percentage
+
+			err := o.Validate()
+			if test.expErr != (err != nil) {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateLeaderElection(t *testing.T) {
+	tests := map[string]struct {
+		leaderElect     bool
+		lease           time.Duration
+		renew           time.Duration
+		retry           time.Duration
+		jitter          float64
+		graceMultiplier float64
+		expErr          bool
+	}{
+		"if the defaults are used, no error": {
+			leaderElect:     true,
+			lease:           60 * time.Second,
+			renew:           40 * time.Second,
+			retry:           15 * time.Second,
+			jitter:          0,
+			graceMultiplier: 1,
+			expErr:          false,
+		},
+		"if jitter and a grace multiplier are given, no error": {
+			leaderElect:     true,
+			lease:           60 * time.Second,
+			renew:           40 * time.Second,
+			retry:           15 * time.Second,
+			jitter:          0.5,
+			graceMultiplier: 2,
+			expErr:          false,
+		},
+		"if the renew deadline is equal to the lease duration, error": {
+			leaderElect:     true,
+			lease:           40 * time.Second,
+			renew:           40 * time.Second,
+			retry:           15 * time.Second,
+			jitter:          0,
+			graceMultiplier: 1,
+			expErr:          true,
+		},
+		"if the renew deadline is not greater than the jittered retry period, error": {
+			leaderElect:     true,
+			lease:           60 * time.Second,
+			renew:           40 * time.Second,
+			retry:           18 * time.Second,
+			jitter:          1,
+			graceMultiplier: 1,
+			expErr:          true,
+		},
+		"if the retry period is zero, error": {
+			leaderElect:     true,
+			lease:           60 * time.Second,
+			renew:           40 * time.Second,
+			retry:           0,
+			jitter:          0,
+			graceMultiplier: 1,
+			expErr:          true,
+		},
+		"if the jitter is negative, error": {
+			leaderElect:     true,
+			lease:           60 * time.Second,
+			renew:           40 * time.Second,
+			retry:           15 * time.Second,
+			jitter:          -0.1,
+			graceMultiplier: 1,
+			expErr:          true,
+		},
+		"if the jitter is greater than 1, error": {
+			leaderElect:     true,
+			lease:           60 * time.Second,
+			renew:           40 * time.Second,
+			retry:           15 * time.Second,
+			jitter:          1.5,
+			graceMultiplier: 1,
+			expErr:          true,
+		},
+		"if the grace multiplier is less than 1, error": {
+			leaderElect:     true,
+			lease:           60 * time.Second,
+			renew:           40 * time.Second,
+			retry:           15 * time.Second,
+			jitter:          0,
+			graceMultiplier: 0.5,
+			expErr:          true,
+		},
+		"if leader election is disabled, the timings are not validated": {
+			leaderElect:     false,
+			lease:           40 * time.Second,
+			renew:           40 * time.Second,
+			retry:           0,
+			jitter:          0,
+			graceMultiplier: 1,
+			expErr:          false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := NewControllerOptions()
+			o.LeaderElect = test.leaderElect
+			o.LeaderElectionLeaseDuration = test.lease
+			o.LeaderElectionRenewDeadline = test.renew
+			o.LeaderElectionRetryPeriod = test.retry
+			o.LeaderElectionJitter = test.jitter
+			o.LeaderElectionGraceMultiplier = test.graceMultiplier
+
+			err := o.Validate()
+			if test.expErr != (err != nil) {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateACMEHTTP01SolverScheduling(t *testing.T) {
+	tests := map[string]struct {
+		nodeSelector []string
+		tolerations  string
+		expErr       bool
+	}{
+		"if neither is set, no error": {
+			expErr: false,
+		},
+		"if the node selector and tolerations are valid, no error": {
+			nodeSelector: []string{"node-pool=acme", "kubernetes.io/os=linux"},
+			tolerations:  `[{"key":"dedicated","operator":"Equal","value":"acme","effect":"NoSchedule"},{"operator":"Exists"}]`,
+			expErr:       false,
+		},
+		"if a node selector entry is not of the form key=value, error": {
+			nodeSelector: []string{"node-pool"},
+			expErr:       true,
+		},
+		"if a node selector key is invalid, error": {
+			nodeSelector: []string{"node pool=acme"},
+			expErr:       true,
+		},
+		"if a node selector value is invalid, error": {
+			nodeSelector: []string{"node-pool=acme pool"},
+			expErr:       true,
+		},
+		"if the tolerations are not a JSON list, error": {
+			tolerations: `dedicated=acme:NoSchedule`,
+			expErr:      true,
+		},
+		"if a toleration has an unknown field, error": {
+			tolerations: `[{"key":"dedicated","efect":"NoSchedule"}]`,
+			expErr:      true,
+		},
+		"if a toleration has an unsupported effect, error": {
+			tolerations: `[{"key":"dedicated","effect":"NoRun"}]`,
+			expErr:      true,
+		},
+		"if a toleration with an empty key does not use Exists, error": {
+			tolerations: `[{"operator":"Equal","value":"acme"}]`,
+			expErr:      true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := NewControllerOptions()
+			o.ACMEHTTP01SolverNodeSelector = test.nodeSelector
+			o.ACMEHTTP01SolverTolerations = test.tolerations
+
+			err := o.Validate()
+			if test.expErr != (err != nil) {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateAllowedIssuers(t *testing.T) {
+	tests := map[string]struct {
+		allowedIssuers []string
+		expErr         bool
+	}{
+		"if not set, no error": {
+			expErr: false,
+		},
+		"if the patterns are valid, no error": {
+			allowedIssuers: []string{"ClusterIssuer/letsencrypt-*", "Issuer/*", "AWSPCAClusterIssuer.awspca.cert-manager.io/pca"},
+			expErr:         false,
+		},
+		"if a pattern has no name, error": {
+			allowedIssuers: []string{"ClusterIssuer/letsencrypt-*", "ClusterIssuer"},
+			expErr:         true,
+		},
+		"if a pattern has an invalid wildcard, error": {
+			allowedIssuers: []string{"Issuer/[a-"},
+			expErr:         true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := NewControllerOptions()
+			o.AllowedIssuers = test.allowedIssuers
+
+			err := o.Validate()
+			if test.expErr != (err != nil) {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestReadinessIssuerRef(t *testing.T) {
+	tests := map[string]struct {
+		namespace  string
+		issuerName string
+		issuerKind string
+		expRef     *healthz.IssuerRef
+		expErr     bool
+	}{
+		"if no issuer name is set, return nil": {
+			issuerKind: "ClusterIssuer",
+		},
+		"if a cluster issuer is named, return a reference to it": {
+			issuerName: "letsencrypt",
+			issuerKind: "ClusterIssuer",
+			expRef:     &healthz.IssuerRef{Kind: "ClusterIssuer", Name: "letsencrypt"},
+		},
+		"if a cluster issuer is named when scoped to a namespace, error": {
+			namespace:  "cert-manager",
+			issuerName: "letsencrypt",
+			issuerKind: "ClusterIssuer",
+			expErr:     true,
+		},
+		"if an issuer is named with its namespace, return a reference to it": {
+			issuerName: "team-a/letsencrypt",
+			issuerKind: "Issuer",
+			expRef:     &healthz.IssuerRef{Kind: "Issuer", Namespace: "team-a", Name: "letsencrypt"},
+		},
+		"if an issuer is named without its namespace, error": {
+			issuerName: "letsencrypt",
+			issuerKind: "Issuer",
+			expErr:     true,
+		},
+		"if an issuer is named without its namespace when scoped to a namespace, use that namespace": {
+			namespace:  "team-a",
+			issuerName: "letsencrypt",
+			issuerKind: "Issuer",
+			expRef:     &healthz.IssuerRef{Kind: "Issuer", Namespace: "team-a", Name: "letsencrypt"},
+		},
+		"if an issuer outside of the scoped namespace is named, error": {
+			namespace:  "team-a",
+			issuerName: "team-b/letsencrypt",
+			issuerKind: "Issuer",
+			expErr:     true,
+		},
+		"if the issuer kind is unknown, error": {
+			issuerName: "letsencrypt",
+			issuerKind: "Foo",
+			expErr:     true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := NewControllerOptions()
+			o.Namespace = test.namespace
+			o.ReadinessIssuerName = test.issuerName
+			o.ReadinessIssuerKind = test.issuerKind
+
+			ref, err := o.ReadinessIssuerRef()
+			if test.expErr != (err != nil) {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+			if !reflect.DeepEqual(ref, test.expRef) {
+				t.Errorf("got unexpected issuer ref, exp=%+v got=%+v", test.expRef, ref)
+			}
+		})
+	}
+}
+
+func TestValidateGRPCTLS(t *testing.T) {
+	tests := map[string]struct {
+		address  string
+		certFile string
+		keyFile  string
+		expErr   bool
+	}{
+		"if the gRPC services are not served, no error": {},
+		"if the gRPC services are served with a certificate and key, no error": {
+			address:  ":9404",
+			certFile: "/tls/tls.crt",
+			keyFile:  "/tls/tls.key",
+		},
+		"if the gRPC services are served without a certificate, error": {
+			address: ":9404",
+			keyFile: "/tls/tls.key",
+			expErr:  true,
+		},
+		"if the gRPC services are served without a key, error": {
+			address:  ":9404",
+			certFile: "/tls/tls.crt",
+			expErr:   true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := NewControllerOptions()
+			o.GRPCListenAddress = test.address
+			o.GRPCTLSCertFile = test.certFile
+			o.GRPCTLSKeyFile = test.keyFile
+
+			err := o.Validate()
+			if test.expErr != (err != nil) {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
@@ -17,8 +17,9 @@ limitations under the License.
 package accounts
 
 import (
-	"crypto/rsa"
+	"crypto"
 	"crypto/tls"
+	"crypto/x509"
 	"net"
 	"net/http"
 	"time"
@@ -33,12 +34,15 @@ import (
 )
 
 // NewClientFunc is a function type for building a new ACME client.
-type NewClientFunc func(*http.Client, cmacme.ACMEIssuer, *rsa.PrivateKey) acmecl.Interface
+type NewClientFunc func(*http.Client, cmacme.ACMEIssuer, crypto.Signer) acmecl.Interface
 
 var _ NewClientFunc = NewClient
 
 // NewClient is an implementation of NewClientFunc that returns a real ACME client.
-func NewClient(client *http.Client, config cmacme.ACMEIssuer, privateKey *rsa.PrivateKey) acmecl.Interface {
+func NewClient(client *http.Client, config cmacme.ACMEIssuer, privateKey crypto.Signer) acmecl.Interface {
+	if config.NoncePoolSize > 0 {
+		client = acmecl.NewNoncePrefetchingClient(client, config.NoncePoolSize)
+	}
 	return &acmeapi.Client{
 		Key:          privateKey,
 		HTTPClient:   client,
@@ -55,7 +59,9 @@ func NewClient(client *http.Client, config cmacme.ACMEIssuer, privateKey *rsa.Pr
 // itself.
 // In future, we may change to having two global HTTP clients - one that ignores
 // TLS connection errors, and the other that does not.
-func BuildHTTPClient(metrics *metrics.Metrics, skipTLSVerify bool) *http.Client {
+// If rootCAs is non-nil, it is used exclusively to verify the ACME server's
+// certificate instead of the system root CAs.
+func BuildHTTPClient(metrics *metrics.Metrics, skipTLSVerify bool, rootCAs *x509.CertPool) *http.Client {
 	return acmecl.NewInstrumentedClient(metrics,
 		&http.Client{
 			Transport: &http.Transport{
@@ -64,7 +70,10 @@ func BuildHTTPClient(metrics *metrics.Metrics, skipTLSVerify bool) *http.Client
 					Timeout:   30 * time.Second,
 					KeepAlive: 30 * time.Second,
 				}).DialContext,
-				TLSClientConfig:       &tls.Config{InsecureSkipVerify: skipTLSVerify},
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: skipTLSVerify,
+					RootCAs:            rootCAs,
+				},
 				MaxIdleConns:          100,
 				IdleConnTimeout:       90 * time.Second,
 				TLSHandshakeTimeout:   10 * time.Second,
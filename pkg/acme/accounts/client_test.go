@@ -0,0 +1,114 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accounts
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/metrics"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+func TestBuildHTTPClient_RootCAs(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	trustedPool := x509.NewCertPool()
+	trustedPool.AddCert(srv.Certificate())
+
+	untrustedPool := x509.NewCertPool()
+	untrustedPool.AddCert(generateTestCA(t))
+
+	tests := map[string]struct {
+		rootCAs       *x509.CertPool
+		skipTLSVerify bool
+		expectErr     bool
+	}{
+		"should trust a server whose certificate is in the bundle": {
+			rootCAs:   trustedPool,
+			expectErr: false,
+		},
+		"should reject a server whose certificate is not in the bundle": {
+			rootCAs:   untrustedPool,
+			expectErr: true,
+		},
+		"should not consult the system roots when a bundle is given": {
+			rootCAs:   x509.NewCertPool(),
+			expectErr: true,
+		},
+		"should not verify the server if skipTLSVerify is set": {
+			rootCAs:       untrustedPool,
+			skipTLSVerify: true,
+			expectErr:     false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cl := BuildHTTPClient(metrics.New(logf.Log), test.skipTLSVerify, test.rootCAs)
+			resp, err := cl.Get(srv.URL)
+			if resp != nil {
+				resp.Body.Close()
+			}
+			if err != nil && !test.expectErr {
+				t.Errorf("expected no error but got: %v", err)
+			}
+			if err == nil && test.expectErr {
+				t.Errorf("expected an error but got none")
+			}
+		})
+	}
+}
+
+func generateTestCA(t *testing.T) *x509.Certificate {
+	pk, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "untrusted-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, pk.Public(), pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accounts
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"fmt"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// JWSAlgorithm returns the algorithm used to sign requests to an ACME server
+// with the given account private key. The ACME client chooses the algorithm
+// based on the type of the key, so this mirrors the choice it makes.
+// An error is returned if the key cannot be used as an ACME account key.
+func JWSAlgorithm(key crypto.Signer) (cmacme.JWSAlgorithm, error) {
+	switch pub := key.Public().(type) {
+	case *rsa.PublicKey:
+		return cmacme.RS256, nil
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			return cmacme.ES256, nil
+		case elliptic.P384():
+			return cmacme.ES384, nil
+		case elliptic.P521():
+			return cmacme.ES512, nil
+		}
+		return "", fmt.Errorf("unsupported ECDSA curve %s", pub.Curve.Params().Name)
+	default:
+		return "", fmt.Errorf("unsupported key type %T", pub)
+	}
+}
+
+// GeneratePrivateKey generates a new ACME account private key of the type
+// required to sign requests using the given algorithm. An RSA key is
+// generated if no algorithm is given.
+func GeneratePrivateKey(alg cmacme.JWSAlgorithm) (crypto.Signer, error) {
+	switch alg {
+	case "", cmacme.RS256:
+		return pki.GenerateRSAPrivateKey(pki.MinRSAKeySize)
+	case cmacme.ES256:
+		return pki.GenerateECPrivateKey(pki.ECCurve256)
+	case cmacme.ES384:
+		return pki.GenerateECPrivateKey(pki.ECCurve384)
+	case cmacme.ES512:
+		return pki.GenerateECPrivateKey(pki.ECCurve521)
+	default:
+		return nil, fmt.Errorf("unsupported JWS algorithm %q", alg)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accounts
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http"
+	"testing"
+
+	acmeapi "golang.org/x/crypto/acme"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	"github.com/jetstack/cert-manager/test/unit/acmeserver"
+)
+
+func TestJWSAlgorithm(t *testing.T) {
+	algs := []cmacme.JWSAlgorithm{cmacme.RS256, cmacme.ES256, cmacme.ES384, cmacme.ES512}
+	for _, alg := range algs {
+		t.Run(string(alg), func(t *testing.T) {
+			pk, err := GeneratePrivateKey(alg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gotAlg, err := JWSAlgorithm(pk)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gotAlg != alg {
+				t.Errorf("expected generated key to use %s but got %s", alg, gotAlg)
+			}
+
+			// the ACME client must sign requests using the same algorithm
+			srv := acmeserver.NewServer(t)
+			defer srv.Close()
+
+			cl := NewClient(http.DefaultClient, cmacme.ACMEIssuer{Server: srv.URL + "/directory"}, pk)
+			if _, err := cl.Register(context.TODO(), &acmeapi.Account{}, acmeapi.AcceptTOS); err != nil {
+				t.Fatalf("unexpected error registering account: %v", err)
+			}
+			reqs := srv.Requests("/account")
+			if len(reqs) != 1 || reqs[0].Alg != string(alg) {
+				t.Errorf("expected request to be signed using %s but got %v", alg, reqs)
+			}
+		})
+	}
+}
+
+func TestJWSAlgorithmUnsupportedKey(t *testing.T) {
+	pk, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := JWSAlgorithm(pk); err == nil {
+		t.Errorf("expected an error for a key on the P-224 curve")
+	}
+}
+
+func TestGeneratePrivateKey(t *testing.T) {
+	pk, err := GeneratePrivateKey("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alg, _ := JWSAlgorithm(pk); alg != cmacme.RS256 {
+		t.Errorf("expected an RSA key to be generated by default, got a key for %s", alg)
+	}
+
+	if _, err := GeneratePrivateKey("PS256"); err == nil {
+		t.Errorf("expected an error for an unsupported algorithm")
+	}
+}
@@ -17,7 +17,8 @@ limitations under the License.
 package accounts
 
 import (
-	"crypto/rsa"
+	"crypto"
+	"crypto/x509"
 	"errors"
 	"net/http"
 	"sync"
@@ -35,7 +36,7 @@ var ErrNotFound = errors.New("ACME client for issuer not initialised/available")
 type Registry interface {
 	// AddClient will ensure the registry has a stored ACME client for the Issuer
 	// object with the given UID, configuration and private key.
-	AddClient(client *http.Client, uid string, config cmacme.ACMEIssuer, privateKey *rsa.PrivateKey)
+	AddClient(client *http.Client, uid string, config cmacme.ACMEIssuer, privateKey crypto.Signer)
 
 	// RemoveClient will remove a registered client using the UID of the Issuer
 	// resource that constructed it.
@@ -81,22 +82,22 @@ type stableOptions struct {
 	skipVerifyTLS bool
 	issuerUID     string
 	publicKey     string
-	exponent      int
+	noncePoolSize int
 }
 
 func (c stableOptions) equalTo(c2 stableOptions) bool {
 	return c == c2
 }
 
-func newStableOptions(uid string, config cmacme.ACMEIssuer, privateKey *rsa.PrivateKey) stableOptions {
-	// Encoding a big.Int cannot fail
-	publicNBytes, _ := privateKey.PublicKey.N.GobEncode()
+func newStableOptions(uid string, config cmacme.ACMEIssuer, privateKey crypto.Signer) stableOptions {
+	// Marshalling cannot fail for the RSA and ECDSA keys used as account keys
+	publicKeyBytes, _ := x509.MarshalPKIXPublicKey(privateKey.Public())
 	return stableOptions{
 		serverURL:     config.Server,
 		skipVerifyTLS: config.SkipTLSVerify,
 		issuerUID:     uid,
-		publicKey:     string(publicNBytes),
-		exponent:      privateKey.PublicKey.E,
+		publicKey:     string(publicKeyBytes),
+		noncePoolSize: config.NoncePoolSize,
 	}
 }
 
@@ -110,7 +111,7 @@ type clientWithMeta struct {
 
 // AddClient will ensure the registry has a stored ACME client for the Issuer
 // object with the given UID, configuration and private key.
-func (r *registry) AddClient(client *http.Client, uid string, config cmacme.ACMEIssuer, privateKey *rsa.PrivateKey) {
+func (r *registry) AddClient(client *http.Client, uid string, config cmacme.ACMEIssuer, privateKey crypto.Signer) {
 	// ensure the client is up to date for the current configuration
 	r.ensureClient(client, uid, config, privateKey)
 }
@@ -120,7 +121,7 @@ func (r *registry) AddClient(client *http.Client, uid string, config cmacme.ACME
 // the client will NOT be mutated or replaced, allowing this method to be called
 // even if the client does not need replacing/updating without causing issues for
 // consumers of the registry.
-func (r *registry) ensureClient(client *http.Client, uid string, config cmacme.ACMEIssuer, privateKey *rsa.PrivateKey) {
+func (r *registry) ensureClient(client *http.Client, uid string, config cmacme.ACMEIssuer, privateKey crypto.Signer) {
 	// acquire a read-write lock even if we hit the fast-path where the client
 	// is already present to avoid having to RLock, RUnlock and Lock again,
 	// which could itself cause a race
@@ -17,7 +17,7 @@ limitations under the License.
 package test
 
 import (
-	"crypto/rsa"
+	"crypto"
 	"net/http"
 
 	acmecl "github.com/jetstack/cert-manager/pkg/acme/client"
@@ -30,13 +30,13 @@ var _ accounts.Registry = &FakeRegistry{}
 
 // FakeRegistry implements the accounts.Registry interface using stub functions
 type FakeRegistry struct {
-	AddClientFunc    func(uid string, config cmacme.ACMEIssuer, privateKey *rsa.PrivateKey)
+	AddClientFunc    func(uid string, config cmacme.ACMEIssuer, privateKey crypto.Signer)
 	RemoveClientFunc func(uid string)
 	GetClientFunc    func(uid string) (acmecl.Interface, error)
 	ListClientsFunc  func() map[string]acmecl.Interface
 }
 
-func (f *FakeRegistry) AddClient(client *http.Client, uid string, config cmacme.ACMEIssuer, privateKey *rsa.PrivateKey) {
+func (f *FakeRegistry) AddClient(client *http.Client, uid string, config cmacme.ACMEIssuer, privateKey crypto.Signer) {
 	f.AddClientFunc(uid, config, privateKey)
 }
 
@@ -0,0 +1,242 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// This file implements a http.RoundTripper that keeps a pool of replay nonces
+// fetched ahead of time from the ACME server.
+//
+// The ACME library fetches a new nonce with a HEAD request whenever it has
+// none stored, holding a lock whilst it does so. Under a burst of requests
+// each request has to wait for a nonce to be fetched in turn, so serving
+// these HEAD requests from a pool of prefetched nonces reduces the latency
+// of issuing many certificates at once.
+
+const (
+	// badNonceProblemType is the ACME problem type returned by the server
+	// when the nonce of a request has expired or was not issued by it.
+	badNonceProblemType = "urn:ietf:params:acme:error:badNonce"
+
+	// nonceMaxAge is the maximum age of a prefetched nonce. ACME servers
+	// expire nonces that are not used within some period, so older nonces
+	// are discarded rather than used.
+	nonceMaxAge = time.Minute
+
+	// nonceFetchTimeout is the timeout for prefetching a single nonce.
+	nonceFetchTimeout = 30 * time.Second
+)
+
+// NoncePrefetcher is a http.RoundTripper that answers the HEAD requests made
+// by the ACME library to fetch a new nonce from a pool of prefetched nonces.
+// The pool is replenished asynchronously whenever a nonce is taken from it,
+// and is discarded if the ACME server rejects a request with a badNonce
+// error.
+type NoncePrefetcher struct {
+	size      int
+	wrappedRT http.RoundTripper
+
+	lock sync.Mutex
+	// nonceURL is the URL of the last nonce requested by the ACME library,
+	// which is used to fetch nonces for the pool.
+	nonceURL string
+	nonces   []prefetchedNonce
+	// generation is incremented whenever the pool is discarded, so that
+	// nonces fetched before the pool was discarded are not added to it.
+	generation int
+	refilling  bool
+}
+
+type prefetchedNonce struct {
+	value   string
+	fetched time.Time
+}
+
+// NewNoncePrefetchingClient returns a copy of client whose RoundTripper keeps
+// a pool of up to size prefetched nonces.
+func NewNoncePrefetchingClient(client *http.Client, size int) *http.Client {
+	// If next client is not defined we'll use http.DefaultClient.
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	rt := client.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	c := *client
+	c.Transport = &NoncePrefetcher{
+		size:      size,
+		wrappedRT: rt,
+	}
+
+	return &c
+}
+
+// RoundTrip implements http.RoundTripper. HEAD requests are answered with a
+// prefetched nonce if one is available, and all other requests are forwarded
+// to the wrapped RoundTripper.
+func (p *NoncePrefetcher) RoundTrip(req *http.Request) (*http.Response, error) {
+	// the ACME library only makes HEAD requests to fetch a new nonce
+	if req.Method != http.MethodHead {
+		resp, err := p.wrappedRT.RoundTrip(req)
+		if err == nil && isBadNonceResponse(resp) {
+			p.discard()
+		}
+		return resp, err
+	}
+
+	nonce, ok := p.pop(req.URL.String())
+	p.refill()
+	if !ok {
+		return p.wrappedRT.RoundTrip(req)
+	}
+
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header: http.Header{
+			"Replay-Nonce":  []string{nonce},
+			"Cache-Control": []string{"no-store"},
+		},
+		Body:    http.NoBody,
+		Request: req,
+	}, nil
+}
+
+// pop returns a prefetched nonce, if one is available that has not expired.
+// It also records url as the URL to prefetch nonces from.
+func (p *NoncePrefetcher) pop(url string) (string, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.nonceURL = url
+	for len(p.nonces) > 0 {
+		nonce := p.nonces[0]
+		p.nonces = p.nonces[1:]
+		if time.Since(nonce.fetched) < nonceMaxAge {
+			return nonce.value, true
+		}
+	}
+	return "", false
+}
+
+// discard removes all prefetched nonces from the pool.
+func (p *NoncePrefetcher) discard() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.nonces = nil
+	p.generation++
+}
+
+// refill starts replenishing the pool in the background, unless it is
+// already full or being replenished.
+func (p *NoncePrefetcher) refill() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.refilling || p.nonceURL == "" || len(p.nonces) >= p.size {
+		return
+	}
+	p.refilling = true
+	go p.fill(p.nonceURL)
+}
+
+// fill fetches nonces from url until the pool is full. If a nonce cannot be
+// fetched, filling stops until the next nonce is taken from the pool.
+func (p *NoncePrefetcher) fill(url string) {
+	for {
+		p.lock.Lock()
+		if len(p.nonces) >= p.size {
+			p.refilling = false
+			p.lock.Unlock()
+			return
+		}
+		generation := p.generation
+		p.lock.Unlock()
+
+		nonce, err := p.fetch(url)
+
+		p.lock.Lock()
+		if err != nil || generation != p.generation {
+			p.refilling = false
+			p.lock.Unlock()
+			return
+		}
+		p.nonces = append(p.nonces, prefetchedNonce{value: nonce, fetched: time.Now()})
+		p.lock.Unlock()
+	}
+}
+
+func (p *NoncePrefetcher) fetch(url string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), nonceFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := p.wrappedRT.RoundTrip(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	nonce := resp.Header.Get("Replay-Nonce")
+	if nonce == "" {
+		return "", fmt.Errorf("no nonce returned by ACME server, status code %d", resp.StatusCode)
+	}
+	return nonce, nil
+}
+
+// isBadNonceResponse returns true if resp is an ACME badNonce error. The
+// response body is restored so that it can be read by the ACME library.
+func isBadNonceResponse(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusBadRequest || !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/problem+json") {
+		return false
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+
+	var problem struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(body, &problem); err != nil {
+		return false
+	}
+	return problem.Type == badNonceProblemType
+}
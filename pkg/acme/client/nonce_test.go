@@ -0,0 +1,220 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// nonceServer is a fake ACME server that counts the nonces it has issued.
+// HEAD requests are blocked whilst block is set, until release is closed.
+type nonceServer struct {
+	*httptest.Server
+
+	heads   int32
+	block   int32
+	release chan struct{}
+}
+
+func newNonceServer(t *testing.T) *nonceServer {
+	s := &nonceServer{release: make(chan struct{})}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodHead:
+			if atomic.LoadInt32(&s.block) == 1 {
+				<-s.release
+			}
+			n := atomic.AddInt32(&s.heads, 1)
+			w.Header().Set("Replay-Nonce", fmt.Sprintf("nonce-%d", n))
+		case r.URL.Path == "/bad-nonce":
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"type":%q,"detail":"JWS has an invalid anti-replay nonce"}`, badNonceProblemType)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	t.Cleanup(func() {
+		close(s.release)
+		s.Close()
+	})
+	return s
+}
+
+func (s *nonceServer) fetchNonce(t *testing.T, cl *http.Client) string {
+	resp, err := cl.Head(s.URL + "/new-nonce")
+	if err != nil {
+		t.Fatalf("unexpected error fetching nonce: %v", err)
+	}
+	defer resp.Body.Close()
+	nonce := resp.Header.Get("Replay-Nonce")
+	if nonce == "" {
+		t.Fatalf("expected a nonce to be returned")
+	}
+	return nonce
+}
+
+func poolSize(p *NoncePrefetcher) int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return len(p.nonces)
+}
+
+func waitForPoolSize(t *testing.T, p *NoncePrefetcher, size int) {
+	for i := 0; i < 100; i++ {
+		if poolSize(p) == size {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for nonce pool to contain %d nonces, it contains %d", size, poolSize(p))
+}
+
+func TestNoncePrefetcher_BurstIsServedFromPool(t *testing.T) {
+	const size = 5
+	srv := newNonceServer(t)
+	cl := NewNoncePrefetchingClient(srv.Client(), size)
+	p := cl.Transport.(*NoncePrefetcher)
+
+	// the first nonce has to be fetched from the server, which also starts
+	// filling the pool
+	first := srv.fetchNonce(t, cl)
+	waitForPoolSize(t, p, size)
+	if heads := atomic.LoadInt32(&srv.heads); heads != size+1 {
+		t.Errorf("expected %d nonces to have been fetched, got %d", size+1, heads)
+	}
+
+	// block the server so that only nonces in the pool can be returned
+	atomic.StoreInt32(&srv.block, 1)
+
+	var wg sync.WaitGroup
+	nonces := make(chan string, size)
+	for i := 0; i < size; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := cl.Head(srv.URL + "/new-nonce")
+			if err != nil {
+				t.Errorf("unexpected error fetching nonce: %v", err)
+				return
+			}
+			resp.Body.Close()
+			nonces <- resp.Header.Get("Replay-Nonce")
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected a burst of %d nonce requests to be served from the pool without waiting for the server", size)
+	}
+	close(nonces)
+
+	seen := make(map[string]bool)
+	for nonce := range nonces {
+		if seen[nonce] {
+			t.Errorf("nonce %q was returned more than once", nonce)
+		}
+		if nonce == "" || nonce == first {
+			t.Errorf("expected nonce to be served from the pool, got %q", nonce)
+		}
+		seen[nonce] = true
+	}
+	if heads := atomic.LoadInt32(&srv.heads); heads != size+1 {
+		t.Errorf("expected no nonce round-trips to the server to complete during the burst, got %d", heads-(size+1))
+	}
+}
+
+func TestNoncePrefetcher_DiscardsPoolOnBadNonce(t *testing.T) {
+	const size = 3
+	srv := newNonceServer(t)
+	cl := NewNoncePrefetchingClient(srv.Client(), size)
+	p := cl.Transport.(*NoncePrefetcher)
+
+	srv.fetchNonce(t, cl)
+	waitForPoolSize(t, p, size)
+
+	resp, err := cl.Post(srv.URL+"/bad-nonce", "application/jose+json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unexpected error reading response body: %v", err)
+	}
+	if !strings.Contains(string(body), badNonceProblemType) {
+		t.Errorf("expected the response body to still be readable, got %q", string(body))
+	}
+	if n := poolSize(p); n != 0 {
+		t.Errorf("expected the nonce pool to be discarded, it contains %d nonces", n)
+	}
+
+	// the next nonce is fetched from the server rather than the discarded pool
+	nonce := srv.fetchNonce(t, cl)
+	for i := 1; i <= size+1; i++ {
+		if nonce == fmt.Sprintf("nonce-%d", i) {
+			t.Errorf("expected a fresh nonce to be fetched from the server, got %q", nonce)
+		}
+	}
+}
+
+func TestNoncePrefetcher_OtherRequestsDoNotDiscardPool(t *testing.T) {
+	const size = 2
+	srv := newNonceServer(t)
+	cl := NewNoncePrefetchingClient(srv.Client(), size)
+	p := cl.Transport.(*NoncePrefetcher)
+
+	srv.fetchNonce(t, cl)
+	waitForPoolSize(t, p, size)
+
+	resp, err := cl.Post(srv.URL+"/new-order", "application/jose+json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if n := poolSize(p); n != size {
+		t.Errorf("expected the nonce pool to contain %d nonces, it contains %d", size, n)
+	}
+}
+
+func TestNoncePrefetcher_ExpiredNoncesAreNotUsed(t *testing.T) {
+	p := &NoncePrefetcher{
+		size: 1,
+		nonces: []prefetchedNonce{
+			{value: "expired", fetched: time.Now().Add(-2 * nonceMaxAge)},
+		},
+	}
+	if nonce, ok := p.pop("https://acme.example.com/new-nonce"); ok {
+		t.Errorf("expected no nonce to be returned, got %q", nonce)
+	}
+	if n := len(p.nonces); n != 0 {
+		t.Errorf("expected expired nonce to be removed from the pool, it contains %d nonces", n)
+	}
+}
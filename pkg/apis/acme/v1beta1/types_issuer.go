@@ -19,6 +19,7 @@ package v1beta1
 import (
 	corev1 "k8s.io/api/core/v1"
 	apiext "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 )
@@ -94,13 +95,42 @@ type ACMEIssuer struct {
 	// +optional
 	DisableAccountKeyGeneration bool `json:"disableAccountKeyGeneration,omitempty"`
 
-	// Enables requesting a Not After date on certificates that matches the
-	// duration of the certificate. This is not supported by all ACME servers
+	// Enables requesting Not Before and Not After dates on certificates that
+	// match the duration of the certificate, by sending them as hints in the
+	// newOrder request. This is not supported by all ACME servers
 	// like Let's Encrypt. If set to true when the ACME server does not support
 	// it it will create an error on the Order.
 	// Defaults to false.
 	// +optional
 	EnableDurationFeature bool `json:"enableDurationFeature,omitempty"`
+
+	// JWSAlgorithm is the algorithm used to sign requests to the ACME server.
+	// The ACME account private key must be of a type that can be used with the
+	// algorithm: an RSA key for RS256, or an ECDSA key on the P-256, P-384 or
+	// P-521 curve for ES256, ES384 or ES512 respectively. If the account key is
+	// generated by cert-manager, a key of the matching type is generated.
+	// If not set, the algorithm is chosen based on the type of the account key.
+	// +optional
+	JWSAlgorithm JWSAlgorithm `json:"jwsAlgorithm,omitempty"`
+
+	// CAContent controls which certificates from the chain returned by the
+	// ACME server are stored in the `ca.crt` key of issued certificate Secrets.
+	// `Chain` stores all of the issuing certificates in the chain, `Issuer`
+	// stores only the certificate that directly issued the leaf certificate,
+	// and `Root` stores only the self-signed root certificate, leaving `ca.crt`
+	// empty if the ACME server does not include the root in the chain.
+	// If not set, the highest certificate in the chain is stored.
+	// +optional
+	CAContent ACMECAContent `json:"caContent,omitempty"`
+
+	// NoncePoolSize is the number of replay nonces that are fetched from the
+	// ACME server ahead of time and kept ready for use by requests made for this
+	// issuer. Prefetching nonces reduces the latency of issuing many certificates
+	// at once, as requests do not have to wait for a nonce to be fetched first.
+	// If not set or zero, nonces are only fetched when they are needed. The
+	// pool size must not be greater than 100.
+	// +optional
+	NoncePoolSize int `json:"noncePoolSize,omitempty"`
 }
 
 // ACMEExternalAccountBinding is a reference to a CA external account of the ACME
@@ -135,6 +165,36 @@ const (
 	HS512 HMACKeyAlgorithm = "HS512"
 )
 
+// JWSAlgorithm is the name of an algorithm used to sign JSON Web Signatures
+// sent to an ACME server.
+// +kubebuilder:validation:Enum=RS256;ES256;ES384;ES512
+type JWSAlgorithm string
+
+const (
+	RS256 JWSAlgorithm = "RS256"
+	ES256 JWSAlgorithm = "ES256"
+	ES384 JWSAlgorithm = "ES384"
+	ES512 JWSAlgorithm = "ES512"
+)
+
+// ACMECAContent controls which certificates from the chain returned by an
+// ACME server are stored in the `ca.crt` key of issued certificate Secrets.
+// +kubebuilder:validation:Enum=Chain;Issuer;Root
+type ACMECAContent string
+
+const (
+	// ACMECAContentChain stores all of the issuing certificates in the chain.
+	ACMECAContentChain ACMECAContent = "Chain"
+
+	// ACMECAContentIssuer stores only the certificate that directly issued
+	// the leaf certificate.
+	ACMECAContentIssuer ACMECAContent = "Issuer"
+
+	// ACMECAContentRoot stores only the self-signed root certificate of the
+	// chain.
+	ACMECAContentRoot ACMECAContent = "Root"
+)
+
 // Configures an issuer to solve challenges using the specified options.
 // Only one of HTTP01 or DNS01 may be provided.
 type ACMEChallengeSolver struct {
@@ -234,6 +294,12 @@ type ACMEChallengeSolverHTTP01Ingress struct {
 	// ingress used for HTTP01 challenges
 	// +optional
 	IngressTemplate *ACMEChallengeSolverHTTP01IngressTemplate `json:"ingressTemplate,omitempty"`
+
+	// If set to true, the solver Service is created with the IPv6 IP family
+	// and the HTTP01 self check only connects to the IPv6 (AAAA) addresses of
+	// the domain being validated. This should be set in IPv6-only clusters.
+	// +optional
+	ForceIPv6 bool `json:"forceIPv6,omitempty"`
 }
 
 type ACMEChallengeSolverHTTP01IngressPodTemplate struct {
@@ -352,6 +418,61 @@ type ACMEChallengeSolverDNS01 struct {
 	// DNS01 challenge records.
 	// +optional
 	Webhook *ACMEIssuerDNS01ProviderWebhook `json:"webhook,omitempty"`
+
+	// CleanupDelay is the amount of time the challenge controller will wait,
+	// after a DNS01 challenge has reached a final state, before removing the
+	// challenge TXT record from the DNS provider.
+	// Defaults to 0, meaning records are cleaned up immediately.
+	// +optional
+	CleanupDelay *metav1.Duration `json:"cleanupDelay,omitempty"`
+
+	// ValidateDNSSEC requires the DNS01 self check to only succeed if the
+	// challenge record is returned with a valid DNSSEC signature. When set,
+	// the self check queries the configured recursive nameservers, which must
+	// perform DNSSEC validation, instead of the zone's authoritative
+	// nameservers. The zone containing the challenge record must be signed.
+	// +optional
+	ValidateDNSSEC bool `json:"validateDNSSEC,omitempty"`
+
+	// ZoneCredentials configures the provider to use different credentials for
+	// challenges in particular DNS zones. Challenges that do not match any of
+	// the listed zones use the Secrets referenced by the provider configuration.
+	// Webhook based solvers are not supported.
+	// +optional
+	ZoneCredentials []ACMEChallengeSolverDNS01ZoneCredentials `json:"zoneCredentials,omitempty"`
+
+	// PropagationThresholdPercent is the percentage of the nameservers queried
+	// by the DNS01 self check that must return the challenge record for the
+	// self check to succeed, between 1 and 100. This allows the self check to
+	// pass before the record has propagated to every nameserver, such as the
+	// many nodes of an anycast DNS service. Defaults to 100, meaning every
+	// nameserver must return the record.
+	// +optional
+	PropagationThresholdPercent *int `json:"propagationThresholdPercent,omitempty"`
+
+	// ZoneName is the DNS zone that challenge records are created in, e.g.
+	// 'example.com'. If set, the zone is not detected automatically by querying
+	// nameservers for SOA records, so this can be used when detection fails. The
+	// DNS names of all challenges solved by this solver must be within the zone.
+	// +optional
+	ZoneName string `json:"zoneName,omitempty"`
+}
+
+// ACMEChallengeSolverDNS01ZoneCredentials selects the Secret containing the
+// DNS provider credentials to use for challenges in the given DNS zones.
+type ACMEChallengeSolverDNS01ZoneCredentials struct {
+	// List of DNSZones that these credentials should be used for.
+	// A challenge matches a zone if its DNS name is equal to the zone, or is a
+	// subdomain of it. If a challenge matches zones in more than one entry, the
+	// entry with the longest (most specific) matching zone is used.
+	DNSZones []string `json:"dnsZones"`
+
+	// SecretName is the name of the Secret containing the credentials for
+	// these zones. It replaces the name of every Secret referenced by the DNS
+	// provider configuration of this solver, while the keys within the Secret
+	// are left unchanged, so the Secret must contain the same keys as the
+	// default credentials Secret.
+	SecretName string `json:"secretName"`
 }
 
 // CNAMEStrategy configures how the DNS01 provider should handle CNAME records
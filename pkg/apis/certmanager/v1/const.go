@@ -41,3 +41,10 @@ const (
 	// left as the default, `/v1/auth/kubernetes/login` will be called.
 	DefaultVaultKubernetesAuthMountPath = "/v1/auth/kubernetes"
 )
+
+const (
+	// CertificateReplicaOrdinalPlaceholder is replaced with the ordinal of
+	// each replica Certificate created from a Certificate that has
+	// `spec.replicas` set.
+	CertificateReplicaOrdinalPlaceholder = "$(ORDINAL)"
+)
@@ -56,6 +56,13 @@ const (
 	IsNextPrivateKeySecretLabelKey = "cert-manager.io/next-private-key"
 )
 
+const (
+	// CertificateSecretCleanupFinalizer is added to Certificates when
+	// cert-manager is configured to delete their Secret a grace period after
+	// the Certificate is deleted, rather than relying on owner references.
+	CertificateSecretCleanupFinalizer = "cert-manager.io/secret-cleanup"
+)
+
 const (
 	// issuerNameAnnotation can be used to override the issuer specified on the
 	// created Certificate resource.
@@ -85,6 +92,22 @@ const (
 
 	// Annotation to declare the CertificateRequest "revision", belonging to a Certificate Resource
 	CertificateRequestRevisionAnnotationKey = "cert-manager.io/certificate-revision"
+
+	// Annotation added to CertificateRequest resources created to issue a
+	// Certificate from its canary issuer. Canary CertificateRequests are not
+	// controlled by the Certificate, so are not treated as a revision of it.
+	CertificateRequestCanaryAnnotationKey = "cert-manager.io/canary"
+
+	// Annotation added to CertificateRequest resources created to sign a
+	// Kubernetes CertificateSigningRequest, to denote the name of the
+	// CertificateSigningRequest.
+	CertificateRequestCSRNameAnnotationKey = "cert-manager.io/certificate-signing-request-name"
+
+	// Annotation added to CertificateRequest resources created for a
+	// Certificate, to record why the Certificate was being issued. The value
+	// is the reason of the Certificate's Issuing condition, e.g. 'Renewing'
+	// or 'DoesNotExist'.
+	CertificateRequestIssuingReasonAnnotationKey = "cert-manager.io/issuing-reason"
 )
 
 const (
@@ -94,6 +117,38 @@ const (
 	// stored in the target Secret resource whilst the real Issuer is processing
 	// the certificate request.
 	IssueTemporaryCertificateAnnotation = "cert-manager.io/issue-temporary-certificate"
+
+	// ImmutableSecretAnnotationKey is an annotation that can be added to
+	// Certificate resources.
+	// If set to "true", the Secret resource for the Certificate will be
+	// created as immutable. As the data of an immutable Secret cannot be
+	// changed, it will be deleted and recreated whenever it is renewed.
+	ImmutableSecretAnnotationKey = "cert-manager.io/immutable-secret"
+
+	// CanaryIssuerNameAnnotationKey is an annotation that can be added to
+	// Certificate resources to name a canary issuer, such as a staging
+	// issuer. A CertificateRequest for the Certificate is issued by the canary
+	// issuer in parallel to the Certificate's own issuer, and the result is
+	// recorded in the CanaryIssued condition. The canary certificate is not
+	// stored in the Certificate's Secret.
+	CanaryIssuerNameAnnotationKey = "cert-manager.io/canary-issuer-name"
+
+	// CanaryIssuerKindAnnotationKey is the 'kind' of the canary issuer named
+	// by CanaryIssuerNameAnnotationKey. Defaults to Issuer.
+	CanaryIssuerKindAnnotationKey = "cert-manager.io/canary-issuer-kind"
+
+	// CanaryIssuerGroupAnnotationKey is the 'group' of the canary issuer
+	// named by CanaryIssuerNameAnnotationKey. Defaults to cert-manager.io.
+	CanaryIssuerGroupAnnotationKey = "cert-manager.io/canary-issuer-group"
+)
+
+const (
+	// ClusterIssuerResourceNamespaceAnnotationKey is an annotation that can be
+	// added to ClusterIssuer resources to name the namespace in which the
+	// resources referenced by the ClusterIssuer, such as Secrets, are stored,
+	// instead of the cluster resource namespace. It is only honoured if
+	// cert-manager is run with --per-cluster-issuer-resource-namespace.
+	ClusterIssuerResourceNamespaceAnnotationKey = "cert-manager.io/cluster-resource-namespace"
 )
 
 // Common/known resource kinds.
@@ -102,6 +157,7 @@ const (
 	IssuerKind             = "Issuer"
 	CertificateKind        = "Certificate"
 	CertificateRequestKind = "CertificateRequest"
+	IssuanceRecordKind     = "IssuanceRecord"
 )
 
 const (
@@ -141,6 +197,11 @@ const (
 	// Venafi Pickup ID of a certificate signing request that has been submitted
 	// to the Venafi API for collection later.
 	VenafiPickupIDAnnotationKey = "venafi.cert-manager.io/pickup-id"
+
+	// ADCSRequestIDAnnotationKey is the annotation key used to record the
+	// ADCS request ID of a certificate signing request that is pending
+	// approval, so that the certificate can be collected once it is issued.
+	ADCSRequestIDAnnotationKey = "adcs.cert-manager.io/request-id"
 )
 
 // KeyUsage specifies valid usage contexts for keys.
@@ -0,0 +1,106 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:storageversion
+
+// An IssuanceRecord is an audit record of a certificate that has been issued
+// for a CertificateRequest.
+//
+// IssuanceRecords are only created if cert-manager is run with the
+// `--enable-issuance-records` flag. Unlike Events, they are retained after the
+// CertificateRequest has been deleted, until they are older than the
+// configured retention period.
+// +k8s:openapi-gen=true
+type IssuanceRecord struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Details of the issued certificate.
+	Spec IssuanceRecordSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// IssuanceRecordList is a list of IssuanceRecords
+type IssuanceRecordList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []IssuanceRecord `json:"items"`
+}
+
+// IssuanceRecordSpec describes an issued certificate.
+type IssuanceRecordSpec struct {
+	// CertificateRequestName is the name of the CertificateRequest that the
+	// certificate was issued for.
+	CertificateRequestName string `json:"certificateRequestName"`
+
+	// CertificateName is the name of the Certificate that created the
+	// CertificateRequest, if any.
+	// +optional
+	CertificateName string `json:"certificateName,omitempty"`
+
+	// IssuerRef is a reference to the issuer that issued the certificate.
+	IssuerRef cmmeta.ObjectReference `json:"issuerRef"`
+
+	// SerialNumber is the serial number of the certificate, encoded as a
+	// hexadecimal string.
+	SerialNumber string `json:"serialNumber"`
+
+	// CommonName is the common name of the certificate's subject.
+	// +optional
+	CommonName string `json:"commonName,omitempty"`
+
+	// DNSNames is the list of DNS subjectAltNames of the certificate.
+	// +optional
+	DNSNames []string `json:"dnsNames,omitempty"`
+
+	// IPAddresses is the list of IP address subjectAltNames of the certificate.
+	// +optional
+	IPAddresses []string `json:"ipAddresses,omitempty"`
+
+	// URIs is the list of URI subjectAltNames of the certificate.
+	// +optional
+	URIs []string `json:"uris,omitempty"`
+
+	// EmailAddresses is the list of email subjectAltNames of the certificate.
+	// +optional
+	EmailAddresses []string `json:"emailAddresses,omitempty"`
+
+	// NotBefore is the time at which the certificate becomes valid.
+	// +optional
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+
+	// NotAfter is the time at which the certificate expires.
+	// +optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+
+	// IssuedAt is the time at which cert-manager observed that the certificate
+	// had been issued.
+	// +optional
+	IssuedAt *metav1.Time `json:"issuedAt,omitempty"`
+}
@@ -139,6 +139,12 @@ type CertificateSpec struct {
 	// +optional
 	Keystores *CertificateKeystores `json:"keystores,omitempty"`
 
+	// SecretTemplate configures how the `secretName` Secret resource is
+	// populated, in addition to the standard `tls.crt`, `tls.key` and `ca.crt`
+	// keys.
+	// +optional
+	SecretTemplate *CertificateSecretTemplate `json:"secretTemplate,omitempty"`
+
 	// IssuerRef is a reference to the issuer for this certificate.
 	// If the `kind` field is not set, or set to `Issuer`, an Issuer resource
 	// with the given name in the same namespace as the Certificate will be used.
@@ -200,6 +206,115 @@ type CertificateSpec struct {
 	// +kubebuilder:validation:ExclusiveMaximum=false
 	// +optional
 	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"` // Validated by the validating webhook.
+
+	// ServiceSelector selects Services in the namespace of the Certificate whose
+	// cluster DNS names, `<name>.<namespace>.svc` and
+	// `<name>.<namespace>.svc.<cluster domain>`, are requested as `dnsNames`.
+	// When set, `dnsNames` is managed by cert-manager and the Certificate is
+	// re-issued as matching Services are added or removed.
+	// This is an experimental field that requires the `ServiceDNSNames` feature
+	// gate to be enabled on the controller.
+	// +optional
+	ServiceSelector *metav1.LabelSelector `json:"serviceSelector,omitempty"`
+
+	// RenewalSchedule restricts when the certificate is renewed, for example to
+	// business hours. A renewal that becomes due outside of the schedule is
+	// deferred until the next time the schedule allows, unless the certificate
+	// would expire before then.
+	// +optional
+	RenewalSchedule *CertificateRenewalSchedule `json:"renewalSchedule,omitempty"`
+
+	// OCSPStapling configures cert-manager to fetch an OCSP response for the
+	// issued certificate from the OCSP server named in the certificate, and to
+	// store it DER encoded in the `tls.ocsp` key of the `secretName` Secret so
+	// that it can be stapled by servers that do not fetch OCSP responses
+	// themselves. The response is refreshed halfway through its validity period.
+	// +optional
+	OCSPStapling *CertificateOCSPStapling `json:"ocspStapling,omitempty"`
+
+	// AddCommonNameToDNSNames controls whether the `commonName` is added to
+	// the DNS names requested in the CertificateRequest if it is not already
+	// present in `dnsNames`. Some CAs require the common name to also be present
+	// as a subject alternative name, whilst others reject the duplication.
+	// Defaults to `false`.
+	// +optional
+	AddCommonNameToDNSNames *bool `json:"addCommonNameToDNSNames,omitempty"`
+
+	// Replicas turns this Certificate into a template for the given number of
+	// Certificates, one for each ordinal from 0 to replicas-1, for workloads
+	// such as StatefulSets that need a distinct certificate for each replica.
+	// Each replica Certificate is named `<name>-<ordinal>`, stores its
+	// certificate in the Secret `<secretName>-<ordinal>` and has every
+	// occurrence of `$(ORDINAL)` in its `commonName`, `dnsNames`, `uris` and
+	// `emailAddresses` replaced with its ordinal. The template Certificate itself
+	// is not issued.
+	// This is an experimental field that requires the `CertificateReplicas`
+	// feature gate to be enabled on the controller.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// IssuanceTimeout is the maximum amount of time an issuance of this
+	// certificate may remain in progress before it is marked as failed and
+	// retried later with backoff. If unset, the controller's
+	// `--default-issuance-timeout` is used. A value of `0s` means the issuance
+	// never times out.
+	// +optional
+	IssuanceTimeout *metav1.Duration `json:"issuanceTimeout,omitempty"`
+
+	// CertificatePolicies is a list of certificate policies requested to be
+	// included in the certificatePolicies extension of the issued certificate.
+	// Requested policies are only issued by CA and SelfSigned issuers that
+	// list their policy ID in allowedCertificatePolicies, with the CPS URI
+	// configured on the issuer.
+	// +optional
+	CertificatePolicies []CertificatePolicy `json:"certificatePolicies,omitempty"`
+}
+
+// CertificateRenewalSchedule restricts the times at which a Certificate is
+// renewed to a recurring weekly schedule.
+type CertificateRenewalSchedule struct {
+	// Days is the list of days of the week on which the certificate may be
+	// renewed, e.g. `Monday`. If empty, the certificate may be renewed on any
+	// day.
+	// +optional
+	Days []string `json:"days,omitempty"`
+
+	// StartHour is the hour of the day, from 0 to 23, from which the
+	// certificate may be renewed on each of the allowed days.
+	StartHour int `json:"startHour"`
+
+	// EndHour is the hour of the day, from 1 to 24, until which the
+	// certificate may be renewed on each of the allowed days. It must be after
+	// `startHour`.
+	EndHour int `json:"endHour"`
+
+	// TimeZone is the IANA time zone, e.g. `Europe/London`, in which the
+	// days and hours are evaluated. Defaults to `UTC`.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// CertificateOCSPStapling configures the OCSP staple stored in the Secret of
+// a Certificate.
+type CertificateOCSPStapling struct {
+	// Enabled configures cert-manager to fetch an OCSP response for the issued
+	// certificate and store it in the `tls.ocsp` key of the Secret.
+	Enabled bool `json:"enabled"`
+}
+
+// CertificatePolicy is a certificate policy included in the
+// certificatePolicies extension of issued certificates, as described in
+// RFC 5280 section 4.2.1.4, such as the policies required for qualified
+// certificates under eIDAS.
+type CertificatePolicy struct {
+	// PolicyID is the object identifier of the policy in dotted decimal
+	// notation, e.g. `0.4.0.194112.1.0`.
+	PolicyID string `json:"policyID"`
+
+	// CPSURI is the URI of the certification practice statement for the
+	// policy, included as a CPS pointer qualifier of the policy.
+	// +optional
+	CPSURI string `json:"cpsURI,omitempty"`
 }
 
 // CertificatePrivateKey contains configuration options for private keys
@@ -260,6 +375,50 @@ type X509Subject struct {
 	SerialNumber string `json:"serialNumber,omitempty"`
 }
 
+// CertificateSecretTemplate configures how the Secret resource named by
+// `secretName` is populated.
+type CertificateSecretTemplate struct {
+	// CertificateKeys is a list of additional keys in the Secret under which
+	// the PEM encoded certificate is stored, in addition to `tls.crt`.
+	// This is useful for consumers that expect a non-standard key such as
+	// `server.crt`. The additional keys are updated whenever the certificate is
+	// renewed. Keys removed from this list are not deleted from the Secret.
+	// +optional
+	CertificateKeys []string `json:"certificateKeys,omitempty"`
+
+	// PrivateKeyKeys is a list of additional keys in the Secret under which
+	// the PEM encoded private key is stored, in addition to `tls.key`.
+	// This is useful for consumers that expect a non-standard key such as
+	// `server.key`. The additional keys are updated whenever the certificate is
+	// renewed. Keys removed from this list are not deleted from the Secret.
+	// +optional
+	PrivateKeyKeys []string `json:"privateKeyKeys,omitempty"`
+
+	// Annotations is a key value map to be copied to the Secret's annotations.
+	// Annotations are synced to an existing Secret without re-issuing the
+	// certificate. Annotations removed from the template are not removed from
+	// the Secret.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Labels is a key value map to be copied to the Secret's labels.
+	// Labels are synced to an existing Secret without re-issuing the
+	// certificate. Labels removed from the template are not removed from
+	// the Secret.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// OwnerReferences is a list of additional owner references to be added to
+	// the Secret, for example so that the Secret is garbage collected when a
+	// higher-level resource that manages the Certificate is deleted. Each owner
+	// must be in the same namespace as the Certificate, or be cluster scoped,
+	// and must not be set as the controller of the Secret. Owner references are
+	// synced to an existing Secret without re-issuing the certificate. Owner
+	// references removed from the template are not removed from the Secret.
+	// +optional
+	OwnerReferences []metav1.OwnerReference `json:"ownerReferences,omitempty"`
+}
+
 // CertificateKeystores configures additional keystore output formats to be
 // created in the Certificate's output Secret.
 type CertificateKeystores struct {
@@ -285,6 +444,22 @@ type JKSKeystore struct {
 	// PasswordSecretRef is a reference to a key in a Secret resource
 	// containing the password used to encrypt the JKS keystore.
 	PasswordSecretRef cmmeta.SecretKeySelector `json:"passwordSecretRef"`
+
+	// Alias is the alias of the entry holding the private key and certificate
+	// in the JKS keystore. Java keystore loaders that look up the entry by a
+	// specific name, such as the certificate's common name, can be configured
+	// by setting this. It must not be `ca` or `ca-<number>`, which are the
+	// aliases of the CA certificate entries.
+	// Defaults to `certificate` if not specified.
+	// +optional
+	Alias string `json:"alias,omitempty"`
+
+	// IncludeChain adds every certificate in the `ca.crt` entry of the target
+	// Secret resource to the JKS keystore and truststore as a separate trusted
+	// certificate entry, aliased `ca`, `ca-1`, `ca-2` and so on. If false, only
+	// the first certificate in `ca.crt` is added, aliased `ca`.
+	// +optional
+	IncludeChain bool `json:"includeChain,omitempty"`
 }
 
 // PKCS12 configures options for storing a PKCS12 keystore in the
@@ -294,14 +469,53 @@ type PKCS12Keystore struct {
 	// If true, a file named `keystore.p12` will be created in the target
 	// Secret resource, encrypted using the password stored in
 	// `passwordSecretRef`.
-	// The keystore file will only be updated upon re-issuance.
+	// The keystore file will only be updated upon re-issuance, or when
+	// the password stored in `passwordSecretRef` changes.
 	Create bool `json:"create"`
 
 	// PasswordSecretRef is a reference to a key in a Secret resource
 	// containing the password used to encrypt the PKCS12 keystore.
 	PasswordSecretRef cmmeta.SecretKeySelector `json:"passwordSecretRef"`
+
+	// Profile specifies the encryption algorithms used to create the PKCS12
+	// keystore. Allowed values are `LegacyRC2`, `LegacyDES`, `Modern3DES` and
+	// `AES256`. `LegacyRC2` can be read by the widest range of software,
+	// including Java 8, but uses weak encryption. `AES256` is recommended
+	// where all consumers support it.
+	// Defaults to `LegacyRC2` if not specified.
+	// +optional
+	Profile PKCS12Profile `json:"profile,omitempty"`
 }
 
+// +kubebuilder:validation:Enum=LegacyRC2;LegacyDES;Modern3DES;AES256
+type PKCS12Profile string
+
+const (
+	// LegacyRC2PKCS12Profile encrypts certificates using RC2 and private keys
+	// using 3DES, with a SHA-1 MAC. This is the most widely compatible
+	// profile, and can be read by Java 8 and OpenSSL before 3.0.0, but uses
+	// weak algorithms.
+	LegacyRC2PKCS12Profile PKCS12Profile = "LegacyRC2"
+
+	// LegacyDESPKCS12Profile encrypts both certificates and private keys
+	// using 3DES, with a SHA-1 MAC. Keystores using this profile can be read
+	// by OpenSSL 3 without enabling the legacy provider, as well as by most
+	// older software.
+	LegacyDESPKCS12Profile PKCS12Profile = "LegacyDES"
+
+	// Modern3DESPKCS12Profile encrypts both certificates and private keys
+	// using 3DES with a key derived using PBKDF2-HMAC-SHA-256, with a SHA-256
+	// MAC. Keystores using this profile can be read by OpenSSL 1.1.1 and
+	// higher and by Java 12 and higher.
+	Modern3DESPKCS12Profile PKCS12Profile = "Modern3DES"
+
+	// AES256PKCS12Profile encrypts both certificates and private keys using
+	// AES-256-CBC with a key derived using PBKDF2-HMAC-SHA-256, with a SHA-256
+	// MAC. Keystores using this profile can be read by OpenSSL 1.1.1 and
+	// higher and by Java 12 and higher, but not by Java 8.
+	AES256PKCS12Profile PKCS12Profile = "AES256"
+)
+
 // CertificateStatus defines the observed state of Certificate
 type CertificateStatus struct {
 	// List of status conditions to indicate the status of certificates.
@@ -358,8 +572,47 @@ type CertificateStatus struct {
 	// not set or False.
 	// +optional
 	NextPrivateKeySecretName *string `json:"nextPrivateKeySecretName,omitempty"`
+
+	// RenewalHistory records the outcome of the most recent attempts to issue
+	// this Certificate, oldest first. It is only maintained if the controller
+	// is configured with a renewal history limit, which caps the number of
+	// records kept.
+	// +optional
+	RenewalHistory []CertificateRenewalRecord `json:"renewalHistory,omitempty"`
+}
+
+// CertificateRenewalRecord records the outcome of an attempt to issue a
+// Certificate.
+type CertificateRenewalRecord struct {
+	// Time is the time at which the attempt completed.
+	Time metav1.Time `json:"time"`
+
+	// Result is the outcome of the attempt, either `Issued` or `Failed`.
+	Result CertificateRenewalResult `json:"result"`
+
+	// IssuerRef is a reference to the issuer that the CertificateRequest of the
+	// attempt was sent to.
+	IssuerRef cmmeta.ObjectReference `json:"issuerRef"`
+
+	// Revision is the revision of the CertificateRequest of the attempt.
+	// +optional
+	Revision *int `json:"revision,omitempty"`
 }
 
+// CertificateRenewalResult is the outcome of an attempt to issue a
+// Certificate.
+type CertificateRenewalResult string
+
+const (
+	// CertificateRenewalResultIssued indicates that the certificate was
+	// issued and stored in the Certificate's Secret.
+	CertificateRenewalResultIssued CertificateRenewalResult = "Issued"
+
+	// CertificateRenewalResultFailed indicates that the CertificateRequest
+	// for the attempt failed or was denied.
+	CertificateRenewalResultFailed CertificateRenewalResult = "Failed"
+)
+
 // CertificateCondition contains condition information for an Certificate.
 type CertificateCondition struct {
 	// Type of the condition, known values are (`Ready`, `Issuing`).
@@ -421,4 +674,66 @@ const (
 	//
 	// It will be removed by the 'issuing' controller upon completing issuance.
 	CertificateConditionIssuing CertificateConditionType = "Issuing"
+
+	// A condition added to Certificate resources when the Secret named in
+	// their `spec.secretName` is owned by another Certificate in the same
+	// namespace. When several Certificates use the same `spec.secretName`,
+	// the Secret is owned by the Certificate named in its
+	// `cert-manager.io/certificate-name` annotation, or otherwise by the
+	// Certificate with the oldest creationTimestamp. A Certificate with this
+	// condition set to true will not be issued, and any issuance in progress is
+	// abandoned, in order to prevent the Certificates from repeatedly
+	// overwriting each other's Secret.
+	//
+	// It will be removed once the Certificate owns its `spec.secretName`.
+	CertificateConditionSecretOwnedByAnother CertificateConditionType = "SecretOwnedByAnother"
+
+	// A condition added to Certificate resources when an issuance is required
+	// but has been deferred because the controller is in a maintenance window,
+	// or because a renewal is not allowed by the `renewalSchedule` of the
+	// Certificate at this time. The Certificate will be issued once the
+	// maintenance window ends or the renewal schedule allows.
+	//
+	// It will be removed once the issuance is triggered, or if the issuance
+	// is no longer required.
+	CertificateConditionIssuanceDeferred CertificateConditionType = "IssuanceDeferred"
+
+	// A CanaryIssued condition is added to Certificates that reference a
+	// canary issuer using the `cert-manager.io/canary-issuer-name` annotation.
+	// It is set to true once a CertificateRequest for the Certificate has been
+	// successfully issued by the canary issuer, and false whilst the canary
+	// request is pending or if it failed.
+	// The certificate issued by the canary issuer is never stored in the
+	// Certificate's Secret.
+	CertificateConditionCanaryIssued CertificateConditionType = "CanaryIssued"
+
+	// A RenewBeforeAdjusted condition is added to Certificates whose issued
+	// certificate is valid for no longer than the `renewBefore` requested in
+	// the Certificate spec, or the default `renewBefore` of 30 days if it is
+	// not set, for example because the issuer enforces a shorter duration
+	// than requested. The renewal time is adjusted to a third of
+	// the certificate's lifetime to avoid immediately renewing it again.
+	// It will be removed once a certificate that is valid for longer than
+	// `renewBefore` is issued, or if `renewBefore` is changed.
+	CertificateConditionRenewBeforeAdjusted CertificateConditionType = "RenewBeforeAdjusted"
+
+	// An IssuerNotFound condition is added to Certificates whose referenced
+	// Issuer or ClusterIssuer does not exist, in which case the Certificate
+	// cannot be renewed. It will be removed once the referenced issuer exists.
+	CertificateConditionIssuerNotFound CertificateConditionType = "IssuerNotFound"
+
+	// An IssuerNotAllowed condition is added to Certificates whose referenced
+	// Issuer or ClusterIssuer is not in the list of issuers allowed by the
+	// `--allowed-issuers` flag of the controller. A Certificate with this
+	// condition set to true will not be issued. It will be removed once the
+	// Certificate references an allowed issuer.
+	CertificateConditionIssuerNotAllowed CertificateConditionType = "IssuerNotAllowed"
+
+	// An IssuerNotReady condition is added to Certificates whose referenced
+	// Issuer or ClusterIssuer has not been Ready for longer than the
+	// `--issuer-not-ready-threshold` of the controller, in which case the
+	// Certificate cannot be renewed. The Ready condition of the Certificate
+	// is not changed, so an existing valid certificate continues to be
+	// served. It will be removed once the referenced issuer is Ready.
+	CertificateConditionIssuerNotReady CertificateConditionType = "IssuerNotReady"
 )
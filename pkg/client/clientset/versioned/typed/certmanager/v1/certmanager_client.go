@@ -29,6 +29,7 @@ type CertmanagerV1Interface interface {
 	CertificatesGetter
 	CertificateRequestsGetter
 	ClusterIssuersGetter
+	IssuanceRecordsGetter
 	IssuersGetter
 }
 
@@ -49,6 +50,10 @@ func (c *CertmanagerV1Client) ClusterIssuers() ClusterIssuerInterface {
 	return newClusterIssuers(c)
 }
 
+func (c *CertmanagerV1Client) IssuanceRecords(namespace string) IssuanceRecordInterface {
+	return newIssuanceRecords(c, namespace)
+}
+
 func (c *CertmanagerV1Client) Issuers(namespace string) IssuerInterface {
 	return newIssuers(c, namespace)
 }
@@ -40,6 +40,10 @@ func (c *FakeCertmanagerV1) ClusterIssuers() v1.ClusterIssuerInterface {
 	return &FakeClusterIssuers{c}
 }
 
+func (c *FakeCertmanagerV1) IssuanceRecords(namespace string) v1.IssuanceRecordInterface {
+	return &FakeIssuanceRecords{c, namespace}
+}
+
 func (c *FakeCertmanagerV1) Issuers(namespace string) v1.IssuerInterface {
 	return &FakeIssuers{c, namespace}
 }
@@ -24,4 +24,6 @@ type CertificateRequestExpansion interface{}
 
 type ClusterIssuerExpansion interface{}
 
+type IssuanceRecordExpansion interface{}
+
 type IssuerExpansion interface{}
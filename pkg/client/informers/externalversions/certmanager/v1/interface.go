@@ -30,6 +30,8 @@ type Interface interface {
 	CertificateRequests() CertificateRequestInformer
 	// ClusterIssuers returns a ClusterIssuerInformer.
 	ClusterIssuers() ClusterIssuerInformer
+	// IssuanceRecords returns a IssuanceRecordInformer.
+	IssuanceRecords() IssuanceRecordInformer
 	// Issuers returns a IssuerInformer.
 	Issuers() IssuerInformer
 }
@@ -60,6 +62,11 @@ func (v *version) ClusterIssuers() ClusterIssuerInformer {
 	return &clusterIssuerInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// IssuanceRecords returns a IssuanceRecordInformer.
+func (v *version) IssuanceRecords() IssuanceRecordInformer {
+	return &issuanceRecordInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // Issuers returns a IssuerInformer.
 func (v *version) Issuers() IssuerInformer {
 	return &issuerInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
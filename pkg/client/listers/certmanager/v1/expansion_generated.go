@@ -38,6 +38,14 @@ type CertificateRequestNamespaceListerExpansion interface{}
 // ClusterIssuerLister.
 type ClusterIssuerListerExpansion interface{}
 
+// IssuanceRecordListerExpansion allows custom methods to be added to
+// IssuanceRecordLister.
+type IssuanceRecordListerExpansion interface{}
+
+// IssuanceRecordNamespaceListerExpansion allows custom methods to be added to
+// IssuanceRecordNamespaceLister.
+type IssuanceRecordNamespaceListerExpansion interface{}
+
 // IssuerListerExpansion allows custom methods to be added to
 // IssuerLister.
 type IssuerListerExpansion interface{}
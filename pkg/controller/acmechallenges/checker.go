@@ -0,0 +1,114 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acmechallenges
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+)
+
+// errCheckInProgress is returned by the propagationChecker whilst a
+// challenge's self check is waiting to run or running.
+var errCheckInProgress = errors.New("self check in progress")
+
+// propagationChecker runs challenge self checks in the background, bounding
+// the number that run concurrently.
+// DNS01 self checks include waiting for the record's TTL to expire, so
+// running them in the background prevents a Certificate with many dnsNames
+// from occupying every controller worker whilst its records propagate.
+type propagationChecker struct {
+	// sem bounds the number of checks that may run concurrently
+	sem chan struct{}
+
+	// onComplete is called with a challenge once its check has completed,
+	// so that it can be re-synced to observe the result.
+	onComplete func(ch *cmacme.Challenge)
+
+	lock   sync.Mutex
+	checks map[types.UID]*propagationCheck
+}
+
+type propagationCheck struct {
+	done bool
+	err  error
+}
+
+func newPropagationChecker(concurrency int, onComplete func(ch *cmacme.Challenge)) *propagationChecker {
+	return &propagationChecker{
+		sem:        make(chan struct{}, concurrency),
+		onComplete: onComplete,
+		checks:     make(map[types.UID]*propagationCheck),
+	}
+}
+
+// Check returns the result of the completed self check for the given
+// challenge, starting the check in the background if it has not yet been
+// started. errCheckInProgress is returned until the check has completed.
+// The result of a check is only returned once, so a failed check will be
+// retried on the following call.
+// The check is passed a copy of the challenge, as the caller may go on to
+// modify it whilst the check runs.
+func (p *propagationChecker) Check(ctx context.Context, ch *cmacme.Challenge, check func(*cmacme.Challenge) error) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if c, ok := p.checks[ch.UID]; ok {
+		if !c.done {
+			return errCheckInProgress
+		}
+		delete(p.checks, ch.UID)
+		return c.err
+	}
+
+	c := &propagationCheck{}
+	p.checks[ch.UID] = c
+	go p.run(ctx, ch.DeepCopy(), c, check)
+
+	return errCheckInProgress
+}
+
+// Forget discards the state of any self check for the given challenge.
+// A check that is already running will complete, but its result is
+// discarded.
+func (p *propagationChecker) Forget(ch *cmacme.Challenge) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	delete(p.checks, ch.UID)
+}
+
+func (p *propagationChecker) run(ctx context.Context, ch *cmacme.Challenge, c *propagationCheck, check func(*cmacme.Challenge) error) {
+	var err error
+	select {
+	case p.sem <- struct{}{}:
+		err = check(ch)
+		<-p.sem
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	p.lock.Lock()
+	c.done = true
+	c.err = err
+	p.lock.Unlock()
+
+	p.onComplete(ch)
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acmechallenges
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestPropagationCheckerConcurrency(t *testing.T) {
+	const (
+		numChallenges = 6
+		concurrency   = 2
+		checkDuration = time.Millisecond * 100
+	)
+
+	var wg sync.WaitGroup
+	wg.Add(numChallenges)
+	checker := newPropagationChecker(concurrency, func(*cmacme.Challenge) {
+		wg.Done()
+	})
+
+	var lock sync.Mutex
+	var running, maxRunning int
+	check := func(ch *cmacme.Challenge) error {
+		lock.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		lock.Unlock()
+
+		time.Sleep(checkDuration)
+
+		lock.Lock()
+		running--
+		lock.Unlock()
+
+		if ch.Spec.DNSName == "0.example.com" {
+			return fmt.Errorf("record not yet propagated")
+		}
+		return nil
+	}
+
+	var challenges []*cmacme.Challenge
+	for i := 0; i < numChallenges; i++ {
+		challenges = append(challenges, gen.Challenge(fmt.Sprintf("test-%d", i),
+			gen.SetChallengeDNSName(fmt.Sprintf("%d.example.com", i)),
+			gen.SetChallengeType(cmacme.ACMEChallengeTypeDNS01),
+		))
+		challenges[i].UID = types.UID(fmt.Sprintf("uid-%d", i))
+	}
+
+	start := time.Now()
+	for _, ch := range challenges {
+		if err := checker.Check(context.TODO(), ch, check); err != errCheckInProgress {
+			t.Fatalf("expected check of %q to be in progress but got: %v", ch.Name, err)
+		}
+	}
+	// checking again whilst the checks are running does not start another
+	if err := checker.Check(context.TODO(), challenges[0], check); err != errCheckInProgress {
+		t.Fatalf("expected check to be in progress but got: %v", err)
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	if maxRunning > concurrency {
+		t.Errorf("expected at most %d checks to run concurrently but got %d", concurrency, maxRunning)
+	}
+	if maxRunning < 2 {
+		t.Errorf("expected checks to run concurrently but at most %d ran at once", maxRunning)
+	}
+	if serial := checkDuration * numChallenges; elapsed >= serial {
+		t.Errorf("expected checks to complete in less than %s but took %s", serial, elapsed)
+	}
+
+	// the result of each check is returned once it has completed
+	for i, ch := range challenges {
+		err := checker.Check(context.TODO(), ch, check)
+		if i == 0 && (err == nil || err == errCheckInProgress) {
+			t.Errorf("expected check of %q to have failed but got: %v", ch.Name, err)
+		}
+		if i != 0 && err != nil {
+			t.Errorf("expected check of %q to have succeeded but got: %v", ch.Name, err)
+		}
+	}
+
+	// the failed check is retried the next time it is checked
+	wg.Add(1)
+	if err := checker.Check(context.TODO(), challenges[0], check); err != errCheckInProgress {
+		t.Errorf("expected failed check to be retried but got: %v", err)
+	}
+	wg.Wait()
+	checker.Forget(challenges[0])
+	if len(checker.checks) != 0 {
+		t.Errorf("expected all checks to be forgotten but got: %v", checker.checks)
+	}
+}
@@ -18,18 +18,23 @@ package acmechallenges
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
 
 	"github.com/jetstack/cert-manager/pkg/acme/accounts"
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
 	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
 	cmacmelisters "github.com/jetstack/cert-manager/pkg/client/listers/acme/v1"
 	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
@@ -37,8 +42,17 @@ import (
 	"github.com/jetstack/cert-manager/pkg/controller/acmechallenges/scheduler"
 	"github.com/jetstack/cert-manager/pkg/issuer"
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns"
+	dnsutil "github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/http"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/metrics"
+)
+
+const (
+	// nameserverModeAuthoritative and nameserverModeRecursive label the
+	// duration of DNS01 self checks with the kind of nameservers queried.
+	nameserverModeAuthoritative = "authoritative"
+	nameserverModeRecursive     = "recursive"
 )
 
 type controller struct {
@@ -50,6 +64,7 @@ type controller struct {
 
 	// all the listers used by this controller
 	challengeLister     cmacmelisters.ChallengeLister
+	orderLister         cmacmelisters.OrderLister
 	issuerLister        cmlisters.IssuerLister
 	clusterIssuerLister cmlisters.ClusterIssuerLister
 	secretLister        corelisters.SecretLister
@@ -76,9 +91,39 @@ type controller struct {
 	// logger to be used by this controller
 	log logr.Logger
 
-	dns01Nameservers []string
+	dns01Nameservers *dnsutil.NameserverList
 
 	DNS01CheckRetryPeriod time.Duration
+
+	// dns01Checker runs DNS01 self checks in the background so that they do
+	// not block a worker whilst waiting for records to propagate.
+	// If nil, self checks are run synchronously.
+	dns01Checker *propagationChecker
+
+	// metrics is used to record the duration of DNS01 self checks, labelled
+	// with dns01NameserverMode, the kind of nameservers they query.
+	metrics             *metrics.Metrics
+	dns01NameserverMode string
+
+	// dns01SelfCheckWarnDuration is the duration after which a DNS01 self
+	// check is reported as slow by an Event on the Challenge. If zero, slow
+	// self checks are not reported.
+	dns01SelfCheckWarnDuration time.Duration
+
+	// clock is used to determine when a challenge's DNS01 cleanup delay has
+	// elapsed
+	clock clock.Clock
+
+	// cleanupDue records the time after which each challenge with a DNS01
+	// cleanupDelay may be cleaned up, keyed by challenge UID.
+	// It is only held in memory, so a restart of the controller restarts
+	// any in-progress delays.
+	cleanupDue     map[types.UID]time.Time
+	cleanupDueLock sync.Mutex
+
+	// deferCleanup leaves presented challenge records in place until the
+	// Order that owns the challenge has reached a final state.
+	deferCleanup bool
 }
 
 func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
@@ -121,13 +166,29 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 		c.clusterIssuerLister = clusterIssuerInformer.Lister()
 	}
 
+	// if challenge clean up is deferred until the owning Order has reached a
+	// final state, we also watch Orders in order to re-sync their Challenges
+	// when they change state.
+	c.deferCleanup = ctx.ACMEOptions.DeferChallengeCleanup
+	if c.deferCleanup {
+		orderInformer := ctx.SharedInformerFactory.Acme().V1().Orders()
+		mustSync = append(mustSync, orderInformer.Informer().HasSynced)
+		c.orderLister = orderInformer.Lister()
+		orderInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{WorkFunc: c.handleOrder})
+	}
+
 	// register handler functions
 	challengeInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: c.queue})
 
 	c.helper = issuer.NewHelper(c.issuerLister, c.clusterIssuerLister)
-	c.scheduler = scheduler.New(logf.NewContext(ctx.RootContext, c.log), c.challengeLister, ctx.SchedulerOptions.MaxConcurrentChallenges)
+	c.scheduler = scheduler.New(logf.NewContext(ctx.RootContext, c.log), c.challengeLister, ctx.SchedulerOptions.MaxConcurrentChallenges, map[cmacme.ACMEChallengeType]int{
+		cmacme.ACMEChallengeTypeHTTP01: ctx.SchedulerOptions.MaxConcurrentHTTP01Challenges,
+		cmacme.ACMEChallengeTypeDNS01:  ctx.SchedulerOptions.MaxConcurrentDNS01Challenges,
+	})
 	c.recorder = ctx.Recorder
 	c.cmClient = ctx.CMClient
+	c.clock = ctx.Clock
+	c.cleanupDue = make(map[types.UID]time.Time)
 	c.httpSolver = http.NewSolver(ctx)
 	c.accountRegistry = ctx.ACMEOptions.AccountRegistry
 
@@ -140,10 +201,51 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 	// read options from context
 	c.dns01Nameservers = ctx.ACMEOptions.DNS01Nameservers
 	c.DNS01CheckRetryPeriod = ctx.ACMEOptions.DNS01CheckRetryPeriod
+	if ctx.ACMEOptions.DNS01CheckConcurrency > 0 {
+		c.dns01Checker = newPropagationChecker(ctx.ACMEOptions.DNS01CheckConcurrency, c.requeue)
+	}
+	c.metrics = ctx.Metrics
+	c.dns01NameserverMode = nameserverModeRecursive
+	if ctx.ACMEOptions.DNS01CheckAuthoritative {
+		c.dns01NameserverMode = nameserverModeAuthoritative
+	}
+	c.dns01SelfCheckWarnDuration = ctx.ACMEOptions.DNS01SelfCheckWarnDuration
 
 	return c.queue, mustSync, nil
 }
 
+// requeue adds the given challenge to the workqueue so that it is re-synced.
+func (c *controller) requeue(ch *cmacme.Challenge) {
+	key, err := controllerpkg.KeyFunc(ch)
+	if err != nil {
+		c.log.Error(err, "error computing key for challenge")
+		return
+	}
+	c.queue.Add(key)
+}
+
+// handleOrder enqueues all Challenges owned by the given Order so that the
+// clean up of their records is re-evaluated when the Order changes state.
+func (c *controller) handleOrder(obj interface{}) {
+	order, ok := obj.(*cmacme.Order)
+	if !ok {
+		c.log.Error(nil, "Non-Order object passed to handleOrder")
+		return
+	}
+
+	challenges, err := c.challengeLister.Challenges(order.Namespace).List(labels.Everything())
+	if err != nil {
+		c.log.Error(err, "error listing challenges for order")
+		return
+	}
+
+	for _, ch := range challenges {
+		if metav1.IsControlledBy(ch, order) {
+			c.requeue(ch)
+		}
+	}
+}
+
 // MaxChallengesPerSchedule is the maximum number of challenges that can be
 // scheduled with a single call to the scheduler.
 // This provides a very crude rate limit on how many challenges we will schedule
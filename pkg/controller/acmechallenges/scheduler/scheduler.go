@@ -36,12 +36,23 @@ type Scheduler struct {
 	log                     logr.Logger
 	challengeLister         cmacmelisters.ChallengeLister
 	maxConcurrentChallenges int
+	// maxConcurrentChallengesByType limits the number of challenges of each
+	// type that can be processing at once. Challenges of a type that has no
+	// positive limit set are only limited by maxConcurrentChallenges.
+	maxConcurrentChallengesByType map[cmacme.ACMEChallengeType]int
 }
 
-// New will construct a new instance of a scheduler
-func New(ctx context.Context, l cmacmelisters.ChallengeLister, maxConcurrentChallenges int) *Scheduler {
+// New will construct a new instance of a scheduler.
+// maxConcurrentChallengesByType may be used to further limit the number of
+// challenges of a particular type that can be processing at once.
+func New(ctx context.Context, l cmacmelisters.ChallengeLister, maxConcurrentChallenges int, maxConcurrentChallengesByType map[cmacme.ACMEChallengeType]int) *Scheduler {
 	log := logs.FromContext(ctx, "challenge-scheduler")
-	return &Scheduler{log: log, challengeLister: l, maxConcurrentChallenges: maxConcurrentChallenges}
+	return &Scheduler{
+		log:                           log,
+		challengeLister:               l,
+		maxConcurrentChallenges:       maxConcurrentChallenges,
+		maxConcurrentChallengesByType: maxConcurrentChallengesByType,
+	}
 }
 
 // ScheduleN will return a maximum of N challenge resources that should be
@@ -62,13 +73,13 @@ func (s *Scheduler) scheduleN(n int, allChallenges []*cmacme.Challenge) ([]*cmac
 	// Determine the list of challenges that could feasibly be scheduled on
 	// this pass of the scheduler.
 	// This function returns a list of candidates sorted by creation timestamp.
-	candidates, inProgressChallengeCount, err := s.determineChallengeCandidates(allChallenges)
+	candidates, inProgress, err := s.determineChallengeCandidates(allChallenges)
 	if err != nil {
 		return nil, err
 	}
 
 	numberToSelect := n
-	remainingNumberAllowedChallenges := s.maxConcurrentChallenges - inProgressChallengeCount
+	remainingNumberAllowedChallenges := s.maxConcurrentChallenges - len(inProgress)
 	if remainingNumberAllowedChallenges < 0 {
 		remainingNumberAllowedChallenges = 0
 	}
@@ -76,7 +87,7 @@ func (s *Scheduler) scheduleN(n int, allChallenges []*cmacme.Challenge) ([]*cmac
 		numberToSelect = remainingNumberAllowedChallenges
 	}
 
-	candidates, err = s.selectChallengesToSchedule(candidates, numberToSelect)
+	candidates, err = s.selectChallengesToSchedule(candidates, inProgress, numberToSelect)
 	if err != nil {
 		return nil, err
 	}
@@ -87,20 +98,44 @@ func (s *Scheduler) scheduleN(n int, allChallenges []*cmacme.Challenge) ([]*cmac
 // selectChallengesToSchedule will apply some sorting heuristic to the allowed
 // challenge candidates and return a maximum of N challenges that should be
 // scheduled for processing.
-func (s *Scheduler) selectChallengesToSchedule(candidates []*cmacme.Challenge, n int) ([]*cmacme.Challenge, error) {
-	// Trim the candidates returned to 'n'
-	if len(candidates) > n {
-		candidates = candidates[:n]
+// Candidates are skipped if scheduling them would exceed the limit for their
+// challenge type, taking into account the challenges already in progress.
+func (s *Scheduler) selectChallengesToSchedule(candidates, inProgress []*cmacme.Challenge, n int) ([]*cmacme.Challenge, error) {
+	if len(s.maxConcurrentChallengesByType) == 0 {
+		// Trim the candidates returned to 'n'
+		if len(candidates) > n {
+			candidates = candidates[:n]
+		}
+		return candidates, nil
 	}
-	return candidates, nil
+
+	countByType := make(map[cmacme.ACMEChallengeType]int)
+	for _, ch := range inProgress {
+		countByType[ch.Spec.Type]++
+	}
+
+	selected := []*cmacme.Challenge{}
+	for _, ch := range candidates {
+		if len(selected) >= n {
+			break
+		}
+		if limit := s.maxConcurrentChallengesByType[ch.Spec.Type]; limit > 0 && countByType[ch.Spec.Type] >= limit {
+			s.log.V(logs.DebugLevel).Info("hit maximum concurrent challenge limit for challenge type. not scheduling challenge.", "type", ch.Spec.Type, "max_concurrent", limit)
+			continue
+		}
+		countByType[ch.Spec.Type]++
+		selected = append(selected, ch)
+	}
+	return selected, nil
 }
 
 // determineChallengeCandidates will determine which, if any, challenges can
 // be scheduled given the current state of items to be scheduled and currently
 // processing.
 // The returned challenges will be sorted in ascending order based on timestamp
-// (i.e. the oldest challenge will be element zero).
-func (s *Scheduler) determineChallengeCandidates(allChallenges []*cmacme.Challenge) ([]*cmacme.Challenge, int, error) {
+// (i.e. the oldest challenge will be element zero). The challenges that are
+// currently processing are also returned.
+func (s *Scheduler) determineChallengeCandidates(allChallenges []*cmacme.Challenge) ([]*cmacme.Challenge, []*cmacme.Challenge, error) {
 	// consider the entire set of challenges for 'in progress', in case a challenge
 	// has processing=true whilst still being in a 'final' state
 	inProgress := processingChallenges(allChallenges)
@@ -111,7 +146,7 @@ func (s *Scheduler) determineChallengeCandidates(allChallenges []*cmacme.Challen
 	// hit the maximum number of challenges.
 	if inProgressChallengeCount >= s.maxConcurrentChallenges {
 		s.log.V(logs.DebugLevel).Info("hit maximum concurrent challenge limit. refusing to schedule more challenges.", "in_progress", len(inProgress), "max_concurrent", s.maxConcurrentChallenges)
-		return []*cmacme.Challenge{}, inProgressChallengeCount, nil
+		return []*cmacme.Challenge{}, inProgress, nil
 	}
 
 	// Calculate incomplete challenges
@@ -139,7 +174,7 @@ func (s *Scheduler) determineChallengeCandidates(allChallenges []*cmacme.Challen
 	// Finally, sorted the challenges by timestamp to ensure a stable output
 	sortChallengesByTimestamp(candidates)
 
-	return candidates, inProgressChallengeCount, nil
+	return candidates, inProgress, nil
 }
 
 func sortChallengesByTimestamp(chs []*cmacme.Challenge) {
@@ -124,6 +124,7 @@ func TestScheduleN(t *testing.T) {
 		name       string
 		n          int
 		challenges []*cmacme.Challenge
+		maxByType  map[cmacme.ACMEChallengeType]int
 		expected   []*cmacme.Challenge
 		err        bool
 	}{
@@ -298,6 +299,79 @@ func TestScheduleN(t *testing.T) {
 					gen.SetChallengeProcessing(true)),
 			},
 		},
+		{
+			name:       "schedule a maximum of the per-type limit for a challenge type",
+			n:          10,
+			challenges: ascendingChallengeN(10),
+			maxByType:  map[cmacme.ACMEChallengeType]int{cmacme.ACMEChallengeTypeHTTP01: 3},
+			expected:   ascendingChallengeN(3),
+		},
+		{
+			name: "per-type limits take into account challenges already processing",
+			n:    10,
+			challenges: append(
+				ascendingChallengeN(2, gen.SetChallengeProcessing(true)),
+				gen.Challenge("dns",
+					gen.SetChallengeDNSName("dns.example.com"),
+					gen.SetChallengeType(cmacme.ACMEChallengeTypeDNS01)),
+				gen.Challenge("http",
+					gen.SetChallengeDNSName("http.example.com"),
+					gen.SetChallengeType(cmacme.ACMEChallengeTypeHTTP01)),
+			),
+			maxByType: map[cmacme.ACMEChallengeType]int{cmacme.ACMEChallengeTypeHTTP01: 2},
+			expected: []*cmacme.Challenge{
+				gen.Challenge("dns",
+					gen.SetChallengeDNSName("dns.example.com"),
+					gen.SetChallengeType(cmacme.ACMEChallengeTypeDNS01)),
+			},
+		},
+		{
+			name: "challenges of a type without a limit are scheduled when another type has reached its limit",
+			n:    3,
+			challenges: []*cmacme.Challenge{
+				gen.Challenge("http-1",
+					gen.SetChallengeDNSName("http-1.example.com"),
+					gen.SetChallengeType(cmacme.ACMEChallengeTypeHTTP01),
+					withCreationTimestamp(1)),
+				gen.Challenge("http-2",
+					gen.SetChallengeDNSName("http-2.example.com"),
+					gen.SetChallengeType(cmacme.ACMEChallengeTypeHTTP01),
+					withCreationTimestamp(2)),
+				gen.Challenge("dns-1",
+					gen.SetChallengeDNSName("dns-1.example.com"),
+					gen.SetChallengeType(cmacme.ACMEChallengeTypeDNS01),
+					withCreationTimestamp(3)),
+				gen.Challenge("dns-2",
+					gen.SetChallengeDNSName("dns-2.example.com"),
+					gen.SetChallengeType(cmacme.ACMEChallengeTypeDNS01),
+					withCreationTimestamp(4)),
+			},
+			maxByType: map[cmacme.ACMEChallengeType]int{
+				cmacme.ACMEChallengeTypeHTTP01: 1,
+				cmacme.ACMEChallengeTypeDNS01:  0,
+			},
+			expected: []*cmacme.Challenge{
+				gen.Challenge("http-1",
+					gen.SetChallengeDNSName("http-1.example.com"),
+					gen.SetChallengeType(cmacme.ACMEChallengeTypeHTTP01),
+					withCreationTimestamp(1)),
+				gen.Challenge("dns-1",
+					gen.SetChallengeDNSName("dns-1.example.com"),
+					gen.SetChallengeType(cmacme.ACMEChallengeTypeDNS01),
+					withCreationTimestamp(3)),
+				gen.Challenge("dns-2",
+					gen.SetChallengeDNSName("dns-2.example.com"),
+					gen.SetChallengeType(cmacme.ACMEChallengeTypeDNS01),
+					withCreationTimestamp(4)),
+			},
+		},
+		{
+			name:       "the global limit applies even if the per-type limit is higher",
+			n:          maxConcurrentChallenges * 2,
+			challenges: ascendingChallengeN(maxConcurrentChallenges * 2),
+			maxByType:  map[cmacme.ACMEChallengeType]int{cmacme.ACMEChallengeTypeHTTP01: maxConcurrentChallenges * 2},
+			expected:   ascendingChallengeN(maxConcurrentChallenges),
+		},
 		{
 			name: "don't schedule anything if all challenges are in a final state",
 			n:    5,
@@ -319,7 +393,7 @@ func TestScheduleN(t *testing.T) {
 				require.NoError(t, err)
 			}
 
-			s := New(context.Background(), challengesInformer.Lister(), maxConcurrentChallenges)
+			s := New(context.Background(), challengesInformer.Lister(), maxConcurrentChallenges, test.maxByType)
 
 			if test.expected == nil {
 				test.expected = []*cmacme.Challenge{}
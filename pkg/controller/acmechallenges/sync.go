@@ -19,10 +19,12 @@ package acmechallenges
 import (
 	"context"
 	"fmt"
+	"time"
 
 	acmeapi "golang.org/x/crypto/acme"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 
@@ -43,6 +45,7 @@ const (
 	reasonPresentError   = "PresentError"
 	reasonPresented      = "Presented"
 	reasonFailed         = "Failed"
+	reasonSelfCheckSlow  = "SelfCheckSlow"
 )
 
 // solver solves ACME challenges by presenting the given token and key in an
@@ -102,6 +105,36 @@ func (c *controller) Sync(ctx context.Context, ch *cmacme.Challenge) (err error)
 				return err
 			}
 
+			// if clean up is deferred, leave the challenge record in place
+			// until the owning Order has reached a final state so that it
+			// can be reused if finalizing the Order fails and is retried.
+			// The Order informer will re-sync the challenge once it has.
+			if c.deferCleanup {
+				finished, err := c.orderFinished(ch)
+				if err != nil {
+					return err
+				}
+				if !finished {
+					log.V(logf.DebugLevel).Info("deferring clean up of challenge until its order has reached a final state")
+					return nil
+				}
+			}
+
+			// if the DNS01 solver is configured with a cleanup delay, leave
+			// the challenge record in place until the delay has elapsed.
+			if remaining := c.cleanupDelayRemaining(ch); remaining > 0 {
+				key, err := controllerpkg.KeyFunc(ch)
+				// This is an unexpected edge case and should never occur
+				if err != nil {
+					return err
+				}
+
+				log.V(logf.DebugLevel).Info("delaying clean up of challenge", "remaining", remaining)
+				c.queue.AddAfter(key, remaining)
+
+				return nil
+			}
+
 			err = solver.CleanUp(ctx, genericIssuer, ch)
 			if err != nil {
 				c.recorder.Eventf(ch, corev1.EventTypeWarning, reasonCleanUpError, "Error cleaning up challenge: %v", err)
@@ -110,6 +143,8 @@ func (c *controller) Sync(ctx context.Context, ch *cmacme.Challenge) (err error)
 				return err
 			}
 
+			c.forgetCleanupDelay(ch)
+			c.forgetPropagationCheck(ch)
 			ch.Status.Presented = false
 		}
 
@@ -155,7 +190,7 @@ func (c *controller) Sync(ctx context.Context, ch *cmacme.Challenge) (err error)
 		// means no CAA check is performed by ACME server or if any valid
 		// CAA would stop issuance (strongly suspect the former)
 		if len(dir.CAA) != 0 {
-			err := dnsutil.ValidateCAA(ch.Spec.DNSName, dir.CAA, ch.Spec.Wildcard, c.dns01Nameservers)
+			err := dnsutil.ValidateCAA(ch.Spec.DNSName, dir.CAA, ch.Spec.Wildcard, c.dns01Nameservers.Get())
 			if err != nil {
 				ch.Status.Reason = fmt.Sprintf("CAA self-check failed: %s", err)
 				return err
@@ -180,7 +215,13 @@ func (c *controller) Sync(ctx context.Context, ch *cmacme.Challenge) (err error)
 		c.recorder.Eventf(ch, corev1.EventTypeNormal, reasonPresented, "Presented challenge using %s challenge mechanism", ch.Spec.Type)
 	}
 
-	err = solver.Check(ctx, genericIssuer, ch)
+	err = c.checkPropagation(ctx, solver, genericIssuer, ch)
+	if err == errCheckInProgress {
+		log.V(logf.DebugLevel).Info("waiting for propagation check to complete")
+		ch.Status.Reason = fmt.Sprintf("Waiting for %s challenge propagation: %s", ch.Spec.Type, err)
+		// the challenge will be re-queued once the check has completed
+		return nil
+	}
 	if err != nil {
 		log.Error(err, "propagation check failed")
 		ch.Status.Reason = fmt.Sprintf("Waiting for %s challenge propagation: %s", ch.Spec.Type, err)
@@ -245,6 +286,9 @@ func (c *controller) handleFinalizer(ctx context.Context, ch *cmacme.Challenge)
 	if len(ch.Finalizers) == 0 {
 		return nil
 	}
+	// the challenge is being deleted, so any cleanup delay no longer applies
+	c.forgetCleanupDelay(ch)
+	c.forgetPropagationCheck(ch)
 	if ch.Finalizers[0] != cmacme.ACMEFinalizer {
 		log.V(logf.DebugLevel).Info("waiting to run challenge finalization...")
 		return nil
@@ -395,3 +439,102 @@ func (c *controller) solverFor(challengeType cmacme.ACMEChallengeType) (solver,
 	}
 	return nil, fmt.Errorf("no solver for %q implemented", challengeType)
 }
+
+// cleanupDelayRemaining returns how much longer the clean up of the given
+// challenge should be delayed for, as configured by the cleanupDelay field of
+// its DNS01 solver. The delay is measured from the first time the challenge
+// is observed in a final state. A value <= 0 means that the challenge may be
+// cleaned up immediately.
+func (c *controller) cleanupDelayRemaining(ch *cmacme.Challenge) time.Duration {
+	if ch.Spec.Type != cmacme.ACMEChallengeTypeDNS01 || ch.Spec.Solver.DNS01 == nil {
+		return 0
+	}
+	delay := ch.Spec.Solver.DNS01.CleanupDelay
+	if delay == nil || delay.Duration <= 0 {
+		return 0
+	}
+
+	c.cleanupDueLock.Lock()
+	defer c.cleanupDueLock.Unlock()
+
+	now := c.clock.Now()
+	due, ok := c.cleanupDue[ch.UID]
+	if !ok {
+		due = now.Add(delay.Duration)
+		c.cleanupDue[ch.UID] = due
+	}
+
+	return due.Sub(now)
+}
+
+// orderFinished returns true if the Order that owns the given challenge has
+// reached a final state, or if the challenge is not owned by an Order that
+// still exists.
+func (c *controller) orderFinished(ch *cmacme.Challenge) (bool, error) {
+	ref := metav1.GetControllerOf(ch)
+	if ref == nil || ref.Kind != cmacme.OrderKind {
+		return true, nil
+	}
+
+	order, err := c.orderLister.Orders(ch.Namespace).Get(ref.Name)
+	if k8sErrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if order.UID != ref.UID {
+		return true, nil
+	}
+
+	return acme.IsFinalState(order.Status.State), nil
+}
+
+// forgetCleanupDelay stops tracking the cleanup delay of the given challenge.
+func (c *controller) forgetCleanupDelay(ch *cmacme.Challenge) {
+	c.cleanupDueLock.Lock()
+	defer c.cleanupDueLock.Unlock()
+	delete(c.cleanupDue, ch.UID)
+}
+
+// checkPropagation runs the solver's self check for the given challenge.
+// DNS01 self checks are run in the background when a dns01Checker is
+// configured, in which case errCheckInProgress is returned until the check
+// has completed.
+func (c *controller) checkPropagation(ctx context.Context, solver solver, issuer cmapi.GenericIssuer, ch *cmacme.Challenge) error {
+	if ch.Spec.Type != cmacme.ACMEChallengeTypeDNS01 {
+		return solver.Check(ctx, issuer, ch)
+	}
+
+	check := func(ch *cmacme.Challenge) error {
+		start := c.clock.Now()
+		err := solver.Check(ctx, issuer, ch)
+		c.observeDNS01SelfCheck(ch, c.clock.Since(start))
+		return err
+	}
+	if c.dns01Checker == nil {
+		return check(ch)
+	}
+	return c.dns01Checker.Check(ctx, ch, check)
+}
+
+// observeDNS01SelfCheck records the duration of a DNS01 self check of the
+// given challenge, and records an Event on the challenge if the self check
+// took longer than the configured warning duration.
+func (c *controller) observeDNS01SelfCheck(ch *cmacme.Challenge, duration time.Duration) {
+	c.metrics.ObserveDNS01SelfCheckDuration(duration, ch.Spec.DNSName, c.dns01NameserverMode)
+
+	if c.dns01SelfCheckWarnDuration > 0 && duration > c.dns01SelfCheckWarnDuration {
+		c.recorder.Eventf(ch, corev1.EventTypeWarning, reasonSelfCheckSlow,
+			"DNS01 self check of %s using %s nameservers took %s, longer than the warning threshold of %s",
+			ch.Spec.DNSName, c.dns01NameserverMode, duration.Round(time.Millisecond), c.dns01SelfCheckWarnDuration)
+	}
+}
+
+// forgetPropagationCheck discards any background self check result for the
+// given challenge.
+func (c *controller) forgetPropagationCheck(ch *cmacme.Challenge) {
+	if c.dns01Checker != nil {
+		c.dns01Checker.Forget(ch)
+	}
+}
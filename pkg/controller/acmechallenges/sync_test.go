@@ -20,10 +20,13 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	acmeapi "golang.org/x/crypto/acme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	coretesting "k8s.io/client-go/testing"
+	fakeclock "k8s.io/utils/clock/testing"
 
 	accountstest "github.com/jetstack/cert-manager/pkg/acme/accounts/test"
 	acmecl "github.com/jetstack/cert-manager/pkg/acme/client"
@@ -411,6 +414,291 @@ func TestSyncHappyPath(t *testing.T) {
 	}
 }
 
+func TestSyncDNS01CleanupDelay(t *testing.T) {
+	testIssuer := gen.Issuer("testissuer", gen.SetIssuerACME(cmacme.ACMEIssuer{
+		Solvers: []cmacme.ACMEChallengeSolver{
+			{
+				DNS01: &cmacme.ACMEChallengeSolverDNS01{},
+			},
+		},
+	}))
+	baseChallenge := gen.Challenge("testchal",
+		gen.SetChallengeIssuer(cmmeta.ObjectReference{
+			Name: "testissuer",
+		}),
+		gen.SetChallengeURL("testurl"),
+		gen.SetChallengeState(cmacme.Valid),
+		gen.SetChallengeType(cmacme.ACMEChallengeTypeDNS01),
+	)
+	baseChallenge.Spec.Solver = cmacme.ACMEChallengeSolver{
+		DNS01: &cmacme.ACMEChallengeSolverDNS01{
+			CleanupDelay: &metav1.Duration{Duration: time.Minute},
+		},
+	}
+	challenge := gen.ChallengeFrom(baseChallenge,
+		gen.SetChallengeProcessing(true),
+		gen.SetChallengePresented(true),
+	)
+
+	fakeClock := fakeclock.NewFakeClock(time.Now())
+	builder := &testpkg.Builder{
+		T:                  t,
+		Clock:              fakeClock,
+		CertManagerObjects: []runtime.Object{challenge, testIssuer},
+		ExpectedActions: []testpkg.Action{
+			testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmacme.SchemeGroupVersion.WithResource("challenges"),
+				"status",
+				gen.DefaultTestNamespace,
+				gen.ChallengeFrom(baseChallenge,
+					gen.SetChallengeProcessing(false),
+					gen.SetChallengePresented(false),
+				))),
+		},
+	}
+	builder.Init()
+	defer builder.Stop()
+
+	cleanUpCalls := 0
+	c := &controller{}
+	c.Register(builder.Context)
+	c.helper = issuer.NewHelper(
+		builder.SharedInformerFactory.Certmanager().V1().Issuers().Lister(),
+		builder.SharedInformerFactory.Certmanager().V1().ClusterIssuers().Lister(),
+	)
+	c.dnsSolver = &fakeSolver{
+		fakeCleanUp: func(context.Context, v1.GenericIssuer, *cmacme.Challenge) error {
+			cleanUpCalls++
+			return nil
+		},
+	}
+	builder.Start()
+
+	// the challenge should not be cleaned up until the delay has elapsed
+	for _, step := range []time.Duration{0, 30 * time.Second, 29 * time.Second} {
+		fakeClock.Step(step)
+		if err := c.Sync(context.Background(), challenge); err != nil {
+			t.Fatalf("Expected function to not error, but got: %v", err)
+		}
+	}
+	if cleanUpCalls != 0 {
+		t.Fatalf("Expected CleanUp to not be called before the cleanup delay elapsed, but it was called %d times", cleanUpCalls)
+	}
+
+	fakeClock.Step(time.Second)
+	if err := c.Sync(context.Background(), challenge); err != nil {
+		t.Fatalf("Expected function to not error, but got: %v", err)
+	}
+	if cleanUpCalls != 1 {
+		t.Fatalf("Expected CleanUp to be called once after the cleanup delay elapsed, but it was called %d times", cleanUpCalls)
+	}
+
+	builder.CheckAndFinish(nil)
+}
+
+func TestSyncDeferChallengeCleanup(t *testing.T) {
+	testIssuer := gen.Issuer("testissuer", gen.SetIssuerACME(cmacme.ACMEIssuer{
+		Solvers: []cmacme.ACMEChallengeSolver{
+			{
+				DNS01: &cmacme.ACMEChallengeSolverDNS01{},
+			},
+		},
+	}))
+	baseOrder := gen.Order("testorder")
+	baseOrder.UID = "order-uid"
+	baseChallenge := gen.Challenge("testchal",
+		gen.SetChallengeIssuer(cmmeta.ObjectReference{
+			Name: "testissuer",
+		}),
+		gen.SetChallengeURL("testurl"),
+		gen.SetChallengeState(cmacme.Valid),
+		gen.SetChallengeType(cmacme.ACMEChallengeTypeDNS01),
+	)
+	baseChallenge.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(baseOrder, cmacme.SchemeGroupVersion.WithKind(cmacme.OrderKind))}
+	challenge := gen.ChallengeFrom(baseChallenge,
+		gen.SetChallengeProcessing(true),
+		gen.SetChallengePresented(true),
+	)
+	cleanedUpChallenge := gen.ChallengeFrom(baseChallenge,
+		gen.SetChallengeProcessing(false),
+		gen.SetChallengePresented(false),
+	)
+
+	tests := map[string]struct {
+		deferCleanup bool
+		// order is the Order that owns the challenge. If nil, the Order does
+		// not exist.
+		order *cmacme.Order
+
+		expectCleanUp bool
+	}{
+		"if clean up is not deferred, the challenge should be cleaned up whilst the order is being finalized": {
+			order:         gen.OrderFrom(baseOrder, gen.SetOrderState(cmacme.Ready)),
+			expectCleanUp: true,
+		},
+		"if finalizing the order failed and will be retried, the challenge should not be cleaned up": {
+			deferCleanup:  true,
+			order:         gen.OrderFrom(baseOrder, gen.SetOrderState(cmacme.Ready), gen.SetOrderReason("Failed to finalize Order: 500 internal error")),
+			expectCleanUp: false,
+		},
+		"if the order is being processed by the ACME server, the challenge should not be cleaned up": {
+			deferCleanup:  true,
+			order:         gen.OrderFrom(baseOrder, gen.SetOrderState(cmacme.Processing)),
+			expectCleanUp: false,
+		},
+		"if the order is valid, the challenge should be cleaned up": {
+			deferCleanup:  true,
+			order:         gen.OrderFrom(baseOrder, gen.SetOrderState(cmacme.Valid)),
+			expectCleanUp: true,
+		},
+		"if the order is invalid, the challenge should be cleaned up": {
+			deferCleanup:  true,
+			order:         gen.OrderFrom(baseOrder, gen.SetOrderState(cmacme.Invalid)),
+			expectCleanUp: true,
+		},
+		"if the order no longer exists, the challenge should be cleaned up": {
+			deferCleanup:  true,
+			expectCleanUp: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := &testpkg.Builder{
+				T:                  t,
+				CertManagerObjects: []runtime.Object{challenge, testIssuer},
+			}
+			if test.order != nil {
+				builder.CertManagerObjects = append(builder.CertManagerObjects, test.order)
+			}
+			if test.expectCleanUp {
+				builder.ExpectedActions = []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmacme.SchemeGroupVersion.WithResource("challenges"),
+						"status",
+						gen.DefaultTestNamespace,
+						cleanedUpChallenge,
+					)),
+				}
+			}
+			builder.Init()
+			builder.Context.ACMEOptions.DeferChallengeCleanup = test.deferCleanup
+			defer builder.Stop()
+
+			cleanUpCalls := 0
+			c := &controller{}
+			c.Register(builder.Context)
+			c.helper = issuer.NewHelper(
+				builder.SharedInformerFactory.Certmanager().V1().Issuers().Lister(),
+				builder.SharedInformerFactory.Certmanager().V1().ClusterIssuers().Lister(),
+			)
+			c.dnsSolver = &fakeSolver{
+				fakeCleanUp: func(context.Context, v1.GenericIssuer, *cmacme.Challenge) error {
+					cleanUpCalls++
+					return nil
+				},
+			}
+			builder.Start()
+
+			if err := c.Sync(context.Background(), challenge); err != nil {
+				t.Fatalf("Expected function to not error, but got: %v", err)
+			}
+
+			expectedCalls := 0
+			if test.expectCleanUp {
+				expectedCalls = 1
+			}
+			if cleanUpCalls != expectedCalls {
+				t.Errorf("Expected CleanUp to be called %d times, but it was called %d times", expectedCalls, cleanUpCalls)
+			}
+
+			builder.CheckAndFinish(nil)
+		})
+	}
+}
+
+func TestSyncDNS01SelfCheckWarnDuration(t *testing.T) {
+	testIssuer := gen.Issuer("testissuer", gen.SetIssuerACME(cmacme.ACMEIssuer{
+		Solvers: []cmacme.ACMEChallengeSolver{
+			{
+				DNS01: &cmacme.ACMEChallengeSolverDNS01{},
+			},
+		},
+	}))
+	challenge := gen.Challenge("testchal",
+		gen.SetChallengeIssuer(cmmeta.ObjectReference{
+			Name: "testissuer",
+		}),
+		gen.SetChallengeProcessing(true),
+		gen.SetChallengeURL("testurl"),
+		gen.SetChallengeDNSName("test.com"),
+		gen.SetChallengeState(cmacme.Pending),
+		gen.SetChallengeType(cmacme.ACMEChallengeTypeDNS01),
+		gen.SetChallengePresented(true),
+	)
+
+	tests := map[string]struct {
+		checkDuration  time.Duration
+		expectedEvents []string
+	}{
+		"a self check shorter than the warning duration should not record an event": {
+			checkDuration: 30 * time.Second,
+		},
+		"a self check longer than the warning duration should record an event": {
+			checkDuration: 2 * time.Minute,
+			expectedEvents: []string{
+				"Warning SelfCheckSlow DNS01 self check of test.com using recursive nameservers took 2m0s, longer than the warning threshold of 1m0s",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeClock := fakeclock.NewFakeClock(time.Now())
+			builder := &testpkg.Builder{
+				T:                  t,
+				Clock:              fakeClock,
+				CertManagerObjects: []runtime.Object{challenge, testIssuer},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmacme.SchemeGroupVersion.WithResource("challenges"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.ChallengeFrom(challenge,
+							gen.SetChallengeReason("Waiting for DNS-01 challenge propagation: record not yet propagated"),
+						))),
+				},
+				ExpectedEvents: test.expectedEvents,
+			}
+			builder.Init()
+			defer builder.Stop()
+
+			c := &controller{}
+			c.Register(builder.Context)
+			c.helper = issuer.NewHelper(
+				builder.SharedInformerFactory.Certmanager().V1().Issuers().Lister(),
+				builder.SharedInformerFactory.Certmanager().V1().ClusterIssuers().Lister(),
+			)
+			c.accountRegistry = &accountstest.FakeRegistry{
+				GetClientFunc: func(_ string) (acmecl.Interface, error) {
+					return &acmecl.FakeACME{}, nil
+				},
+			}
+			c.dnsSolver = &fakeSolver{
+				fakeCheck: func(context.Context, v1.GenericIssuer, *cmacme.Challenge) error {
+					fakeClock.Step(test.checkDuration)
+					return fmt.Errorf("record not yet propagated")
+				},
+			}
+			c.dns01SelfCheckWarnDuration = time.Minute
+			builder.Start()
+
+			if err := c.Sync(context.Background(), challenge); err != nil {
+				t.Fatalf("Expected function to not error, but got: %v", err)
+			}
+
+			builder.CheckAndFinish(nil)
+		})
+	}
+}
+
 func runTest(t *testing.T, test testT) {
 	test.builder.T = t
 	test.builder.Init()
@@ -21,6 +21,7 @@ import (
 	"context"
 	"crypto/x509"
 	"encoding/pem"
+	"errors"
 	"fmt"
 
 	acmeapi "golang.org/x/crypto/acme"
@@ -37,11 +38,13 @@ import (
 	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	cmerrors "github.com/jetstack/cert-manager/pkg/util/errors"
 )
 
 const (
-	reasonSolver  = "Solver"
-	reasonCreated = "Created"
+	reasonSolver                = "Solver"
+	reasonCreated               = "Created"
+	reasonWildcardRequiresDNS01 = "WildcardRequiresDNS01"
 )
 
 func (c *controller) Sync(ctx context.Context, o *cmacme.Order) (err error) {
@@ -67,6 +70,10 @@ func (c *controller) Sync(ctx context.Context, o *cmacme.Order) (err error) {
 	}()
 
 	genericIssuer, err := c.helper.GetGenericIssuer(o.Spec.IssuerRef, o.Namespace)
+	if apierrors.IsNotFound(err) {
+		// the Order will be resynced when the referenced issuer is created
+		return cmerrors.NewPermanent(fmt.Errorf("referenced (cluster)issuer %q not found: %v", o.Spec.IssuerRef.Name, err))
+	}
 	if err != nil {
 		return fmt.Errorf("error reading (cluster)issuer %q: %v", o.Spec.IssuerRef.Name, err)
 	}
@@ -110,6 +117,16 @@ func (c *controller) Sync(ctx context.Context, o *cmacme.Order) (err error) {
 
 	dbg.Info("Computing list of Challenge resources that need to exist to complete this Order")
 	requiredChallenges, err := buildRequiredChallenges(ctx, cl, genericIssuer, o)
+	var wcErr *wildcardRequiresDNS01Error
+	if errors.As(err, &wcErr) {
+		// the Order can never be completed with the issuer's current
+		// solvers, so fail it rather than retrying forever
+		log.Error(err, "Failed to determine the list of Challenge resources needed for the Order, marking Order as failed")
+		c.recorder.Event(o, corev1.EventTypeWarning, reasonWildcardRequiresDNS01, err.Error())
+		c.setOrderState(&o.Status, string(cmacme.Errored))
+		o.Status.Reason = fmt.Sprintf("%s: %v", reasonWildcardRequiresDNS01, err)
+		return nil
+	}
 	if err != nil {
 		log.Error(err, "Failed to determine the list of Challenge resources needed for the Order")
 		c.recorder.Eventf(o, corev1.EventTypeWarning, reasonSolver, "Failed to determine a valid solver configuration for the set of domains on the Order: %v", err)
@@ -188,7 +205,7 @@ func (c *controller) createOrder(ctx context.Context, cl acmecl.Interface, o *cm
 	log := logf.FromContext(ctx)
 
 	if o.Status.URL != "" {
-		return fmt.Errorf("refusing to recreate a new order for Order %q. Please create a new Order resource to initiate a new order", o.Name)
+		return cmerrors.NewPermanent(fmt.Errorf("refusing to recreate a new order for Order %q. Please create a new Order resource to initiate a new order", o.Name))
 	}
 	log.V(logf.DebugLevel).Info("order URL not set, submitting Order to ACME server")
 
@@ -205,9 +222,16 @@ func (c *controller) createOrder(ctx context.Context, cl acmecl.Interface, o *cm
 	authzIDs = append(authzIDs, acmeapi.IPIDs(ipIdentifierSet.List()...)...)
 	// create a new order with the acme server
 
+	// The Duration is only set on the Order if the Issuer has opted in to
+	// requesting validity dates, in which case send both the not before and
+	// not after hints so that the requested validity window is unambiguous.
 	var options []acmeapi.OrderOption
 	if o.Spec.Duration != nil {
-		options = append(options, acmeapi.WithOrderNotAfter(c.clock.Now().Add(o.Spec.Duration.Duration)))
+		now := c.clock.Now()
+		options = append(options,
+			acmeapi.WithOrderNotBefore(now),
+			acmeapi.WithOrderNotAfter(now.Add(o.Spec.Duration.Duration)),
+		)
 	}
 	acmeOrder, err := cl.AuthorizeOrder(ctx, authzIDs, options...)
 	if acmeErr, ok := err.(*acmeapi.Error); ok {
@@ -18,6 +18,10 @@ package acmeorders
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
 	"encoding/pem"
 	"errors"
 	"fmt"
@@ -29,12 +33,14 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	coretesting "k8s.io/client-go/testing"
 	fakeclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/pointer"
 
 	accountstest "github.com/jetstack/cert-manager/pkg/acme/accounts/test"
 	acmecl "github.com/jetstack/cert-manager/pkg/acme/client"
 	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/test/unit/acmeserver"
 	"github.com/jetstack/cert-manager/test/unit/gen"
 )
 
@@ -122,6 +128,37 @@ dGVzdA==
 	testOrderReady := testOrderPending.DeepCopy()
 	testOrderReady.Status.State = cmacme.Ready
 
+	testOrderWildcard := gen.Order("testorder",
+		gen.SetOrderDNSNames("*.test.com"),
+		gen.SetOrderIssuer(cmmeta.ObjectReference{
+			Name: testIssuerHTTP01.Name,
+		}),
+	)
+	wildcardPendingStatus := cmacme.OrderStatus{
+		State:       cmacme.Pending,
+		URL:         "http://testurl.com/abcde",
+		FinalizeURL: "http://testurl.com/abcde/finalize",
+		Authorizations: []cmacme.ACMEAuthorization{
+			{
+				URL:        "http://authzurl",
+				Identifier: "test.com",
+				Wildcard:   pointer.BoolPtr(true),
+				Challenges: []cmacme.ACMEChallenge{
+					{
+						URL:   "http://chalurl",
+						Token: "token",
+						Type:  "dns-01",
+					},
+				},
+			},
+		},
+	}
+	testOrderWildcardPending := gen.OrderFrom(testOrderWildcard, gen.SetOrderStatus(wildcardPendingStatus))
+	testOrderWildcardErrored := testOrderWildcardPending.DeepCopy()
+	testOrderWildcardErrored.Status.State = cmacme.Errored
+	testOrderWildcardErrored.Status.Reason = `WildcardRequiresDNS01: wildcard identifier "*.test.com" can only be validated using DNS01, but no configured DNS01 solver matches it`
+	testOrderWildcardErrored.Status.FailureTime = &nowMetaTime
+
 	testCert := []byte(`-----BEGIN CERTIFICATE-----
 MIIFjTCCA3WgAwIBAgIRANOxciY0IzLc9AUoUSrsnGowDQYJKoZIhvcNAQELBQAw
 TzELMAkGA1UEBhMCVVMxKTAnBgNVBAoTIEludGVybmV0IFNlY3VyaXR5IFJlc2Vh
@@ -340,6 +377,30 @@ rUCGwbCUDI0mxadJ3Bz4WxR6fyNpBK2yAinWEsikxqEt
 				},
 			},
 		},
+		"should fail the order if a wildcard identifier can only be validated by DNS01 and only an HTTP01 solver is configured": {
+			order: testOrderWildcardPending,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{testIssuerHTTP01, testOrderWildcardPending},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmacme.SchemeGroupVersion.WithResource("orders"),
+						"status",
+						testOrderWildcardErrored.Namespace, testOrderWildcardErrored)),
+				},
+				ExpectedEvents: []string{
+					`Warning WildcardRequiresDNS01 wildcard identifier "*.test.com" can only be validated using DNS01, but no configured DNS01 solver matches it`,
+				},
+			},
+			acmeClient: &acmecl.FakeACME{},
+		},
+		"do nothing if a wildcard order has already failed because it requires a DNS01 solver": {
+			order: testOrderWildcardErrored,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{testIssuerHTTP01, testOrderWildcardErrored},
+				ExpectedActions:    []testpkg.Action{},
+				ExpectedEvents:     []string{},
+			},
+			acmeClient: &acmecl.FakeACME{},
+		},
 		// TODO: we should improve this behaviour as this is the 'stuck order' problem described in:
 		//  https://github.com/jetstack/cert-manager/issues/2868
 		"skip creating a Challenge for an already valid authorization, and do nothing if the order is pending": {
@@ -636,3 +697,73 @@ func runTest(t *testing.T, test testT) {
 
 	test.builder.CheckAndFinish(err)
 }
+
+func TestCreateOrderValidityHints(t *testing.T) {
+	nowTime := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	fixedClock := fakeclock.NewFakeClock(nowTime)
+
+	tests := map[string]struct {
+		order         *cmacme.Order
+		wantNotBefore string
+		wantNotAfter  string
+	}{
+		"should not send validity hints if the order has no duration": {
+			order: gen.Order("test", gen.SetOrderDNSNames("example.com")),
+		},
+		"should send not before and not after hints derived from the order's duration": {
+			order: gen.Order("test",
+				gen.SetOrderDNSNames("example.com"),
+				gen.SetOrderDuration(time.Hour*24*7),
+			),
+			wantNotBefore: "2021-06-01T12:00:00Z",
+			wantNotAfter:  "2021-06-08T12:00:00Z",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := acmeserver.NewServer(t)
+			defer srv.Close()
+
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cl := &acmeapi.Client{Key: key, DirectoryURL: srv.URL + "/directory"}
+
+			c := &controller{clock: fixedClock}
+			o := test.order.DeepCopy()
+			if err := c.createOrder(context.Background(), cl, o); err != nil {
+				t.Fatalf("unexpected error creating order: %v", err)
+			}
+
+			reqs := srv.Requests("/order")
+			if len(reqs) != 1 {
+				t.Fatalf("expected a single newOrder request but got %d", len(reqs))
+			}
+			var got newOrderRequest
+			if err := json.Unmarshal(reqs[0].Payload, &got); err != nil {
+				t.Fatalf("failed to decode newOrder request: %v", err)
+			}
+			if got.NotBefore != test.wantNotBefore {
+				t.Errorf("unexpected notBefore hint, exp=%q got=%q", test.wantNotBefore, got.NotBefore)
+			}
+			if got.NotAfter != test.wantNotAfter {
+				t.Errorf("unexpected notAfter hint, exp=%q got=%q", test.wantNotAfter, got.NotAfter)
+			}
+			if o.Status.URL != srv.URL+"/order/1" {
+				t.Errorf("unexpected order URL, exp=%q got=%q", srv.URL+"/order/1", o.Status.URL)
+			}
+			if o.Status.State != cmacme.Pending {
+				t.Errorf("unexpected order state, exp=%q got=%q", cmacme.Pending, o.Status.State)
+			}
+		})
+	}
+}
+
+// newOrderRequest is the subset of an ACME newOrder request payload inspected
+// by TestCreateOrderValidityHints.
+type newOrderRequest struct {
+	NotBefore string `json:"notBefore,omitempty"`
+	NotAfter  string `json:"notAfter,omitempty"`
+}
@@ -37,6 +37,17 @@ var (
 	orderGvk = cmacme.SchemeGroupVersion.WithKind("Order")
 )
 
+// wildcardRequiresDNS01Error is returned when no solver can be selected for a
+// wildcard identifier. Wildcard identifiers can only be validated using DNS01,
+// so retrying will not succeed until the issuer's solvers are changed.
+type wildcardRequiresDNS01Error struct {
+	identifier string
+}
+
+func (e *wildcardRequiresDNS01Error) Error() string {
+	return fmt.Sprintf("wildcard identifier %q can only be validated using DNS01, but no configured DNS01 solver matches it", e.identifier)
+}
+
 func buildRequiredChallenges(ctx context.Context, cl acmecl.Interface, issuer cmapi.GenericIssuer, o *cmacme.Order) ([]cmacme.Challenge, error) {
 	chs := make([]cmacme.Challenge, 0)
 	for _, a := range o.Status.Authorizations {
@@ -121,7 +132,9 @@ func challengeSpecForAuthorization(ctx context.Context, cl acmecl.Interface, iss
 	challengeForSolver := func(solver *cmacme.ACMEChallengeSolver) *cmacme.ACMEChallenge {
 		for _, ch := range authz.Challenges {
 			switch {
-			case ch.Type == "http-01" && solver.HTTP01 != nil:
+			// ACME does not allow wildcard identifiers to be validated
+			// using HTTP01, even if the server offers the challenge
+			case ch.Type == "http-01" && solver.HTTP01 != nil && !wc:
 				return &ch
 			case ch.Type == "dns-01" && solver.DNS01 != nil:
 				return &ch
@@ -265,6 +278,9 @@ func challengeSpecForAuthorization(ctx context.Context, cl acmecl.Interface, iss
 	}
 
 	if selectedSolver == nil || selectedChallenge == nil {
+		if wc {
+			return nil, &wildcardRequiresDNS01Error{identifier: domainToFind}
+		}
 		return nil, fmt.Errorf("no configured challenge solvers can be used for this challenge")
 	}
 
@@ -0,0 +1,323 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cacrl implements a controller that maintains a certificate
+// revocation list (CRL) for CA issuers that have `spec.ca.crl` set, and
+// publishes it to a Secret.
+package cacrl
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cminformers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/errors"
+	"github.com/jetstack/cert-manager/pkg/util/kube"
+)
+
+const (
+	ControllerName = "ca-crl"
+
+	reasonErrGetKeyPair = "ErrGetKeyPair"
+	reasonCRLFailed     = "CRLFailed"
+	reasonCRLPublished  = "CRLPublished"
+)
+
+// controller generates a CRL for each CA Issuer and ClusterIssuer with a
+// `spec.ca.crl`, listing the configured revoked certificates, and stores it
+// in the configured Secret. The CRL is regenerated whenever the revoked
+// certificates or signing CA change, and once half of its validity has passed.
+type controller struct {
+	issuerLister cmlisters.IssuerLister
+	// clusterIssuerLister is nil if the controller is scoped to a single
+	// namespace.
+	clusterIssuerLister cmlisters.ClusterIssuerLister
+	secretLister        corelisters.SecretLister
+	client              kubernetes.Interface
+	recorder            record.EventRecorder
+	clock               clock.Clock
+	queue               workqueue.RateLimitingInterface
+	log                 logr.Logger
+
+	issuerOptions controllerpkg.IssuerOptions
+}
+
+func NewController(
+	log logr.Logger,
+	client kubernetes.Interface,
+	factory informers.SharedInformerFactory,
+	cmFactory cminformers.SharedInformerFactory,
+	recorder record.EventRecorder,
+	clock clock.Clock,
+	namespace string,
+	issuerOptions controllerpkg.IssuerOptions,
+) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
+	// create a queue used to queue up items to be processed
+	queue := workqueue.NewNamedRateLimitingQueue(controllerpkg.DefaultItemBasedRateLimiter(), ControllerName)
+
+	// obtain references to all the informers used by this controller
+	issuerInformer := cmFactory.Certmanager().V1().Issuers()
+	secretInformer := factory.Core().V1().Secrets()
+
+	c := &controller{
+		issuerLister:  issuerInformer.Lister(),
+		secretLister:  secretInformer.Lister(),
+		client:        client,
+		recorder:      recorder,
+		clock:         clock,
+		queue:         queue,
+		log:           log,
+		issuerOptions: issuerOptions,
+	}
+
+	issuerInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: queue})
+	// When a Secret changes, enqueue any issuers that use it as either their
+	// signing CA or CRL Secret.
+	secretInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{WorkFunc: c.secretChanged})
+
+	// build a list of InformerSynced functions that will be returned by the Register method.
+	// the controller will only begin processing items once all of these informers have synced.
+	mustSync := []cache.InformerSynced{
+		issuerInformer.Informer().HasSynced,
+		secretInformer.Informer().HasSynced,
+	}
+
+	// ClusterIssuers are not watched when scoped to a single namespace.
+	if namespace == "" {
+		clusterIssuerInformer := cmFactory.Certmanager().V1().ClusterIssuers()
+		clusterIssuerInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: queue})
+		mustSync = append(mustSync, clusterIssuerInformer.Informer().HasSynced)
+		c.clusterIssuerLister = clusterIssuerInformer.Lister()
+	}
+
+	return c, queue, mustSync
+}
+
+// secretChanged enqueues the issuers that reference the given Secret.
+func (c *controller) secretChanged(obj interface{}) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		c.log.Error(nil, "object was not a secret object")
+		return
+	}
+
+	issuers, err := c.issuerLister.Issuers(secret.Namespace).List(labels.Everything())
+	if err != nil {
+		c.log.Error(err, "error listing issuers")
+		return
+	}
+	for _, iss := range issuers {
+		if referencesSecret(iss, secret.Name) {
+			c.enqueue(iss)
+		}
+	}
+
+	if c.clusterIssuerLister == nil {
+		return
+	}
+
+	clusterIssuers, err := c.clusterIssuerLister.List(labels.Everything())
+	if err != nil {
+		c.log.Error(err, "error listing clusterissuers")
+		return
+	}
+	for _, iss := range clusterIssuers {
+		if secret.Namespace == c.issuerOptions.ResourceNamespace(iss) && referencesSecret(iss, secret.Name) {
+			c.enqueue(iss)
+		}
+	}
+}
+
+func (c *controller) enqueue(iss cmapi.GenericIssuer) {
+	key, err := controllerpkg.KeyFunc(iss)
+	if err != nil {
+		c.log.Error(err, "error computing key for resource")
+		return
+	}
+	c.queue.Add(key)
+}
+
+// referencesSecret returns true if the given issuer maintains a CRL and uses
+// the named Secret as either its signing CA or CRL Secret.
+func referencesSecret(iss cmapi.GenericIssuer, name string) bool {
+	ca := iss.GetSpec().CA
+	if ca == nil || ca.CRL == nil {
+		return false
+	}
+	return ca.SecretName == name || ca.CRL.SecretName == name
+}
+
+// ProcessItem regenerates and publishes the CRL of the Issuer or
+// ClusterIssuer with the given key if it is missing or out of date. Keys
+// without a namespace refer to ClusterIssuers.
+func (c *controller) ProcessItem(ctx context.Context, key string) error {
+	log := logf.FromContext(ctx).WithValues("key", key)
+
+	ctx = logf.NewContext(ctx, log)
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		log.Error(err, "invalid resource key passed to ProcessItem")
+		return nil
+	}
+
+	var iss cmapi.GenericIssuer
+	if namespace == "" {
+		if c.clusterIssuerLister == nil {
+			return nil
+		}
+		iss, err = c.clusterIssuerLister.Get(name)
+	} else {
+		iss, err = c.issuerLister.Issuers(namespace).Get(name)
+	}
+	if apierrors.IsNotFound(err) {
+		log.V(logf.DebugLevel).Info("issuer not found for key")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	ca := iss.GetSpec().CA
+	if ca == nil || ca.CRL == nil {
+		return nil
+	}
+
+	log = logf.WithResource(log, iss)
+	resourceNamespace := c.issuerOptions.ResourceNamespace(iss)
+
+	caCerts, caKey, err := kube.SecretTLSKeyPair(ctx, c.secretLister, resourceNamespace, ca.SecretName)
+	if apierrors.IsNotFound(err) || errors.IsInvalidData(err) {
+		// the Secret is watched, so the issuer will be processed again once it
+		// has been fixed
+		log.Error(err, "error getting signing CA key pair")
+		c.recorder.Eventf(iss, corev1.EventTypeWarning, reasonErrGetKeyPair, "Error getting key pair to sign CRL: %v", err)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	revoked, err := revokedCertificates(ca.CRL.RevokedCertificates)
+	if err != nil {
+		// the webhook validates the serial numbers, so this is not retried
+		log.Error(err, "invalid revoked certificate on issuer")
+		return nil
+	}
+
+	validity := DefaultCRLValidity
+	if ca.CRL.Validity != nil {
+		validity = ca.CRL.Validity.Duration
+	}
+
+	secret, err := c.secretLister.Secrets(resourceNamespace).Get(ca.CRL.SecretName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	now := c.clock.Now()
+	if secret != nil {
+		renewalTime := crlRenewalTime(secret.Data[CRLSecretKey], caCerts[0], revoked, validity)
+		if renewalTime.After(now) {
+			log.V(logf.DebugLevel).Info("CRL is up to date, scheduling regeneration", "after", renewalTime.Sub(now))
+			c.queue.AddAfter(key, renewalTime.Sub(now))
+			return nil
+		}
+	}
+
+	crlPEM, err := generateCRL(caCerts[0], caKey, revoked, now, validity)
+	if err != nil {
+		// the CA may be missing the 'crl sign' key usage, which will not be
+		// fixed by retrying
+		log.Error(err, "error generating CRL")
+		c.recorder.Eventf(iss, corev1.EventTypeWarning, reasonCRLFailed, "Failed to generate CRL: %v", err)
+		return nil
+	}
+
+	if secret == nil {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ca.CRL.SecretName,
+				Namespace: resourceNamespace,
+			},
+			Data: map[string][]byte{CRLSecretKey: crlPEM},
+		}
+		_, err = c.client.CoreV1().Secrets(resourceNamespace).Create(ctx, secret, metav1.CreateOptions{})
+	} else {
+		secret = secret.DeepCopy()
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
+		}
+		secret.Data[CRLSecretKey] = crlPEM
+		_, err = c.client.CoreV1().Secrets(resourceNamespace).Update(ctx, secret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+
+	log.V(logf.InfoLevel).Info("published CRL", "revoked", len(revoked), "secret", ca.CRL.SecretName)
+	c.recorder.Eventf(iss, corev1.EventTypeNormal, reasonCRLPublished, "Published CRL listing %d revoked certificates to Secret %q", len(revoked), ca.CRL.SecretName)
+	c.queue.AddAfter(key, validity/2)
+
+	return nil
+}
+
+// controllerWrapper wraps the `controller` structure to make it implement
+// the controllerpkg.queueingController interface
+type controllerWrapper struct {
+	*controller
+}
+
+func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
+	// construct a new named logger to be reused throughout the controller
+	log := logf.FromContext(ctx.RootContext, ControllerName)
+
+	ctrl, queue, mustSync := NewController(log,
+		ctx.Client,
+		ctx.KubeSharedInformerFactory,
+		ctx.SharedInformerFactory,
+		ctx.Recorder,
+		ctx.Clock,
+		ctx.Namespace,
+		ctx.IssuerOptions,
+	)
+	c.controller = ctrl
+
+	return queue, mustSync, nil
+}
+
+func init() {
+	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		return controllerpkg.NewBuilder(ctx, ControllerName).
+			For(&controllerWrapper{}).
+			Complete()
+	})
+}
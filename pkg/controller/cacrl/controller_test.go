@@ -0,0 +1,217 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cacrl
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coretesting "k8s.io/client-go/testing"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestProcessItem(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	ca := mustCreateCA(t, x509.KeyUsageCertSign|x509.KeyUsageCRLSign)
+	revoked := cmapi.CARevokedCertificate{SerialNumber: "1f:2a:03", RevocationTime: metav1.NewTime(now.Add(-time.Hour))}
+
+	caSecret := func(namespace string) *corev1.Secret {
+		return gen.Secret("ca-key-pair",
+			gen.SetSecretNamespace(namespace),
+			gen.SetSecretData(map[string][]byte{
+				corev1.TLSCertKey:       ca.certPEM,
+				corev1.TLSPrivateKeyKey: ca.keyPEM,
+			}),
+		)
+	}
+	crlSecret := func(crlPEM []byte) *corev1.Secret {
+		return gen.Secret("ca-crl",
+			gen.SetSecretNamespace("testns"),
+			gen.SetSecretData(map[string][]byte{CRLSecretKey: crlPEM}),
+		)
+	}
+	caIssuer := cmapi.CAIssuer{
+		SecretName: "ca-key-pair",
+		CRL: &cmapi.CACRL{
+			SecretName:          "ca-crl",
+			RevokedCertificates: []cmapi.CARevokedCertificate{revoked},
+		},
+	}
+	issuer := gen.Issuer("ca", gen.SetIssuerNamespace("testns"), gen.SetIssuerCA(caIssuer))
+
+	// expectCRL matches an action that creates or updates the CRL Secret
+	// with a CRL signed by the CA that lists exactly the given serial numbers.
+	expectCRL := func(action coretesting.Action, serials ...string) testpkg.Action {
+		return testpkg.NewCustomMatch(action, func(exp, actual coretesting.Action) error {
+			if exp.GetVerb() != actual.GetVerb() || exp.GetNamespace() != actual.GetNamespace() {
+				return fmt.Errorf("unexpected %s action in namespace %q", actual.GetVerb(), actual.GetNamespace())
+			}
+			secret := actual.(interface{ GetObject() runtime.Object }).GetObject().(*corev1.Secret)
+			if secret.Name != "ca-crl" {
+				return fmt.Errorf("unexpected secret name %q", secret.Name)
+			}
+
+			crl, err := x509.ParseCRL(secret.Data[CRLSecretKey])
+			if err != nil {
+				return fmt.Errorf("failed to parse CRL: %v", err)
+			}
+			if err := ca.cert.CheckCRLSignature(crl); err != nil {
+				return err
+			}
+			if !crl.TBSCertList.ThisUpdate.Equal(now) {
+				return fmt.Errorf("unexpected thisUpdate %v", crl.TBSCertList.ThisUpdate)
+			}
+
+			var got []string
+			for _, r := range crl.TBSCertList.RevokedCertificates {
+				got = append(got, fmt.Sprintf("%x", r.SerialNumber))
+			}
+			if fmt.Sprint(got) != fmt.Sprint(serials) {
+				return fmt.Errorf("expected revoked serial numbers %v but got %v", serials, got)
+			}
+			return nil
+		})
+	}
+	secretsResource := corev1.SchemeGroupVersion.WithResource("secrets")
+
+	tests := map[string]struct {
+		key       string
+		issuers   []runtime.Object
+		secrets   []runtime.Object
+		namespace string
+
+		expectedActions []testpkg.Action
+		expectedEvents  []string
+	}{
+		"do nothing if the issuer does not exist": {
+			key: "testns/ca",
+		},
+		"do nothing if the issuer does not maintain a CRL": {
+			key:     "testns/ca",
+			issuers: []runtime.Object{gen.Issuer("ca", gen.SetIssuerNamespace("testns"), gen.SetIssuerCASecretName("ca-key-pair"))},
+			secrets: []runtime.Object{caSecret("testns")},
+		},
+		"fire an event if the signing CA Secret does not exist": {
+			key:            "testns/ca",
+			issuers:        []runtime.Object{issuer},
+			expectedEvents: []string{`Warning ErrGetKeyPair Error getting key pair to sign CRL: secret "ca-key-pair" not found`},
+		},
+		"publish a CRL listing the revoked certificates if the CRL Secret does not exist": {
+			key:     "testns/ca",
+			issuers: []runtime.Object{issuer},
+			secrets: []runtime.Object{caSecret("testns")},
+			expectedActions: []testpkg.Action{
+				expectCRL(coretesting.NewCreateAction(secretsResource, "testns", nil), "1f2a03"),
+			},
+			expectedEvents: []string{`Normal CRLPublished Published CRL listing 1 revoked certificates to Secret "ca-crl"`},
+		},
+		"do nothing if the published CRL is up to date": {
+			key:     "testns/ca",
+			issuers: []runtime.Object{issuer},
+			secrets: []runtime.Object{
+				caSecret("testns"),
+				crlSecret(mustGenerateCRL(t, ca, now.Add(-time.Hour), DefaultCRLValidity, revoked)),
+			},
+		},
+		"update the CRL if a certificate has been revoked since it was published": {
+			key:     "testns/ca",
+			issuers: []runtime.Object{issuer},
+			secrets: []runtime.Object{
+				caSecret("testns"),
+				crlSecret(mustGenerateCRL(t, ca, now.Add(-time.Hour), DefaultCRLValidity)),
+			},
+			expectedActions: []testpkg.Action{
+				expectCRL(coretesting.NewUpdateAction(secretsResource, "testns", nil), "1f2a03"),
+			},
+			expectedEvents: []string{`Normal CRLPublished Published CRL listing 1 revoked certificates to Secret "ca-crl"`},
+		},
+		"update the CRL once half of its validity has passed": {
+			key:     "testns/ca",
+			issuers: []runtime.Object{issuer},
+			secrets: []runtime.Object{
+				caSecret("testns"),
+				crlSecret(mustGenerateCRL(t, ca, now.Add(-DefaultCRLValidity/2), DefaultCRLValidity, revoked)),
+			},
+			expectedActions: []testpkg.Action{
+				expectCRL(coretesting.NewUpdateAction(secretsResource, "testns", nil), "1f2a03"),
+			},
+			expectedEvents: []string{`Normal CRLPublished Published CRL listing 1 revoked certificates to Secret "ca-crl"`},
+		},
+		"publish the CRL of a ClusterIssuer to the cluster resource namespace": {
+			key:     "ca",
+			issuers: []runtime.Object{gen.ClusterIssuer("ca", gen.SetIssuerCA(caIssuer))},
+			secrets: []runtime.Object{caSecret("cert-manager")},
+			expectedActions: []testpkg.Action{
+				expectCRL(coretesting.NewCreateAction(secretsResource, "cert-manager", nil), "1f2a03"),
+			},
+			expectedEvents: []string{`Normal CRLPublished Published CRL listing 1 revoked certificates to Secret "ca-crl"`},
+		},
+		"do nothing for ClusterIssuers if scoped to a single namespace": {
+			key:       "ca",
+			issuers:   []runtime.Object{gen.ClusterIssuer("ca", gen.SetIssuerCA(caIssuer))},
+			secrets:   []runtime.Object{caSecret("cert-manager")},
+			namespace: "testns",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Create and initialise a new unit test builder
+			builder := &testpkg.Builder{
+				T:                  t,
+				Clock:              fakeclock.NewFakeClock(now),
+				CertManagerObjects: test.issuers,
+				KubeObjects:        test.secrets,
+				ExpectedActions:    test.expectedActions,
+				ExpectedEvents:     test.expectedEvents,
+			}
+			builder.Init()
+			builder.Context.Namespace = test.namespace
+			builder.Context.ClusterResourceNamespace = "cert-manager"
+
+			// Register informers used by the controller using the registration wrapper
+			w := &controllerWrapper{}
+			_, _, err := w.Register(builder.Context)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Start the informers and begin processing updates
+			builder.Start()
+			defer builder.Stop()
+
+			if err := w.controller.ProcessItem(context.Background(), test.key); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if err := builder.AllEventsCalled(); err != nil {
+				builder.T.Error(err)
+			}
+			if err := builder.AllActionsExecuted(); err != nil {
+				builder.T.Error(err)
+			}
+		})
+	}
+}
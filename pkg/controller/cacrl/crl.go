@@ -0,0 +1,134 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cacrl
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+const (
+	// CRLSecretKey is the key in a CA issuer's CRL Secret that the PEM
+	// encoded CRL is stored under.
+	CRLSecretKey = "ca.crl"
+
+	// DefaultCRLValidity is the validity of generated CRLs if the issuer does
+	// not specify one.
+	DefaultCRLValidity = time.Hour * 24
+)
+
+// generateCRL returns a PEM encoded CRL listing the given revoked
+// certificates, signed by the given CA certificate and private key.
+func generateCRL(caCert *x509.Certificate, caKey crypto.Signer, revoked []pkix.RevokedCertificate, now time.Time, validity time.Duration) ([]byte, error) {
+	template := &x509.RevocationList{
+		// The CRL number must increase with each CRL issued by the CA, so the
+		// time the CRL was generated at is used.
+		Number:              big.NewInt(now.Unix()),
+		ThisUpdate:          now,
+		NextUpdate:          now.Add(validity),
+		RevokedCertificates: revoked,
+	}
+
+	der, err := x509.CreateRevocationList(rand.Reader, template, caCert, caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}), nil
+}
+
+// revokedCertificates converts the revoked certificates listed on a CA
+// issuer into CRL entries.
+func revokedCertificates(revoked []cmapi.CARevokedCertificate) ([]pkix.RevokedCertificate, error) {
+	var entries []pkix.RevokedCertificate
+	for _, r := range revoked {
+		serial, err := pki.ParseSerialNumber(r.SerialNumber)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, pkix.RevokedCertificate{
+			SerialNumber:   serial,
+			RevocationTime: r.RevocationTime.UTC().Truncate(time.Second),
+		})
+	}
+
+	return entries, nil
+}
+
+// crlRenewalTime returns the time at which the given PEM encoded CRL should
+// be regenerated, which is once half of its validity has passed. The zero
+// time is returned if the CRL must be regenerated straight away because it
+// cannot be parsed, was not signed by the given CA, has a different validity
+// or does not list exactly the given revoked certificates.
+func crlRenewalTime(crlPEM []byte, caCert *x509.Certificate, revoked []pkix.RevokedCertificate, validity time.Duration) time.Time {
+	crl, err := x509.ParseCRL(crlPEM)
+	if err != nil {
+		return time.Time{}
+	}
+
+	if err := caCert.CheckCRLSignature(crl); err != nil {
+		return time.Time{}
+	}
+
+	tbs := crl.TBSCertList
+	if tbs.NextUpdate.Sub(tbs.ThisUpdate) != validity {
+		return time.Time{}
+	}
+
+	if !revokedCertificatesEqual(tbs.RevokedCertificates, revoked) {
+		return time.Time{}
+	}
+
+	return tbs.ThisUpdate.Add(validity / 2)
+}
+
+// revokedCertificatesEqual returns true if a and b contain the same serial
+// numbers and revocation times, regardless of order.
+func revokedCertificatesEqual(a, b []pkix.RevokedCertificate) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	entries := func(revoked []pkix.RevokedCertificate) []string {
+		var s []string
+		for _, r := range revoked {
+			s = append(s, fmt.Sprintf("%s/%d", r.SerialNumber, r.RevocationTime.Unix()))
+		}
+		sort.Strings(s)
+		return s
+	}
+
+	ea, eb := entries(a), entries(b)
+	for i := range ea {
+		if ea[i] != eb[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cacrl
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+type testCA struct {
+	cert    *x509.Certificate
+	key     crypto.Signer
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func mustCreateCA(t *testing.T, keyUsage x509.KeyUsage) *testCA {
+	key, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour * 24 * 365),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              keyUsage,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM, err := pki.EncodePKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCA{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  keyPEM,
+	}
+}
+
+func mustRevokedCertificates(t *testing.T, revoked ...cmapi.CARevokedCertificate) []pkix.RevokedCertificate {
+	entries, err := revokedCertificates(revoked)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entries
+}
+
+func mustGenerateCRL(t *testing.T, ca *testCA, now time.Time, validity time.Duration, revoked ...cmapi.CARevokedCertificate) []byte {
+	crlPEM, err := generateCRL(ca.cert, ca.key, mustRevokedCertificates(t, revoked...), now, validity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return crlPEM
+}
+
+func TestGenerateCRL(t *testing.T) {
+	ca := mustCreateCA(t, x509.KeyUsageCertSign|x509.KeyUsageCRLSign)
+	now := time.Now()
+	revokedAt := metav1.NewTime(now.Add(-time.Hour))
+
+	crlPEM := mustGenerateCRL(t, ca, now, time.Hour,
+		cmapi.CARevokedCertificate{SerialNumber: "1f:2a:03", RevocationTime: revokedAt},
+		cmapi.CARevokedCertificate{SerialNumber: "abcdef", RevocationTime: revokedAt},
+	)
+
+	crl, err := x509.ParseCRL(crlPEM)
+	if err != nil {
+		t.Fatalf("failed to parse generated CRL: %v", err)
+	}
+	if err := ca.cert.CheckCRLSignature(crl); err != nil {
+		t.Errorf("CRL not signed by CA: %v", err)
+	}
+
+	tbs := crl.TBSCertList
+	if !tbs.NextUpdate.Equal(now.Add(time.Hour).Truncate(time.Second)) {
+		t.Errorf("unexpected nextUpdate %v", tbs.NextUpdate)
+	}
+
+	expected := map[string]bool{"2042371": true, "11259375": true}
+	if len(tbs.RevokedCertificates) != len(expected) {
+		t.Fatalf("expected %d revoked certificates but got %d", len(expected), len(tbs.RevokedCertificates))
+	}
+	for _, r := range tbs.RevokedCertificates {
+		if !expected[r.SerialNumber.String()] {
+			t.Errorf("unexpected revoked serial number %s", r.SerialNumber)
+		}
+		if !r.RevocationTime.Equal(revokedAt.Truncate(time.Second)) {
+			t.Errorf("unexpected revocation time %v", r.RevocationTime)
+		}
+	}
+
+	// CAs must be permitted to sign CRLs
+	noCRLSign := mustCreateCA(t, x509.KeyUsageCertSign)
+	if _, err := generateCRL(noCRLSign.cert, noCRLSign.key, nil, now, time.Hour); err == nil {
+		t.Errorf("expected an error generating a CRL with a CA without the crl sign key usage")
+	}
+}
+
+func TestCRLRenewalTime(t *testing.T) {
+	ca := mustCreateCA(t, x509.KeyUsageCertSign|x509.KeyUsageCRLSign)
+	otherCA := mustCreateCA(t, x509.KeyUsageCertSign|x509.KeyUsageCRLSign)
+	now := time.Now().Truncate(time.Second)
+	revoked := cmapi.CARevokedCertificate{SerialNumber: "1f:2a:03", RevocationTime: metav1.NewTime(now.Add(-time.Hour))}
+
+	tests := map[string]struct {
+		crlPEM   []byte
+		revoked  []cmapi.CARevokedCertificate
+		validity time.Duration
+		expected time.Time
+	}{
+		"an up to date CRL is renewed after half of its validity": {
+			crlPEM:   mustGenerateCRL(t, ca, now, time.Hour, revoked),
+			revoked:  []cmapi.CARevokedCertificate{revoked},
+			validity: time.Hour,
+			expected: now.Add(time.Minute * 30),
+		},
+		"a missing CRL is renewed immediately": {
+			revoked:  []cmapi.CARevokedCertificate{revoked},
+			validity: time.Hour,
+		},
+		"a CRL signed by another CA is renewed immediately": {
+			crlPEM:   mustGenerateCRL(t, otherCA, now, time.Hour, revoked),
+			revoked:  []cmapi.CARevokedCertificate{revoked},
+			validity: time.Hour,
+		},
+		"a CRL missing a revoked certificate is renewed immediately": {
+			crlPEM:   mustGenerateCRL(t, ca, now, time.Hour),
+			revoked:  []cmapi.CARevokedCertificate{revoked},
+			validity: time.Hour,
+		},
+		"a CRL with a different validity is renewed immediately": {
+			crlPEM:   mustGenerateCRL(t, ca, now, time.Hour, revoked),
+			revoked:  []cmapi.CARevokedCertificate{revoked},
+			validity: time.Hour * 2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			renewalTime := crlRenewalTime(test.crlPEM, ca.cert, mustRevokedCertificates(t, test.revoked...), test.validity)
+			if !renewalTime.Equal(test.expected) {
+				t.Errorf("expected renewal time %v but got %v", test.expected, renewalTime)
+			}
+		})
+	}
+}
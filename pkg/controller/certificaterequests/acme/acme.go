@@ -17,6 +17,7 @@ limitations under the License.
 package acme
 
 import (
+	"bytes"
 	"context"
 	"crypto/x509"
 	"fmt"
@@ -212,16 +213,71 @@ func (a *ACME) Sign(ctx context.Context, cr *v1.CertificateRequest, issuer v1.Ge
 		return nil, a.acmeClientV.Orders(order.Namespace).Delete(ctx, order.Name, metav1.DeleteOptions{})
 	}
 
+	caPEM, err := caForContent(bundle, issuer.GetSpec().ACME.CAContent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select CA certificates from chain: %w", err)
+	}
+
 	log.V(logf.InfoLevel).Info("certificate issued")
 
 	// Order valid, return cert. The calling controller will update with ready if its happy with the cert.
 	return &issuerpkg.IssueResponse{
 		Certificate: bundle.ChainPEM,
-		CA:          bundle.CAPEM,
+		CA:          caPEM,
 	}, nil
 
 }
 
+// caForContent returns the PEM encoded certificates from the given bundle that
+// should be stored as the CA of the issued certificate, according to the CA
+// content configured on the ACME issuer. If no CA content is configured, the
+// highest certificate in the chain is returned.
+func caForContent(bundle pki.PEMBundle, content cmacme.ACMECAContent) ([]byte, error) {
+	if len(content) == 0 || len(bundle.CAPEM) == 0 {
+		return bundle.CAPEM, nil
+	}
+
+	// The chain is ordered leaf first and does not include the highest
+	// certificate, which is stored separately as the CA.
+	chain, err := pki.DecodeX509CertificateChainBytes(bundle.ChainPEM)
+	if err != nil {
+		return nil, err
+	}
+	ca, err := pki.DecodeX509CertificateBytes(bundle.CAPEM)
+	if err != nil {
+		return nil, err
+	}
+	issuers := append(chain[1:], ca)
+
+	switch content {
+	case cmacme.ACMECAContentChain:
+		var caPEM []byte
+		for _, cert := range issuers {
+			certPEM, err := pki.EncodeX509(cert)
+			if err != nil {
+				return nil, err
+			}
+			caPEM = append(caPEM, certPEM...)
+		}
+		return caPEM, nil
+
+	case cmacme.ACMECAContentIssuer:
+		return pki.EncodeX509(issuers[0])
+
+	case cmacme.ACMECAContentRoot:
+		// The root can only be the highest certificate in the chain. ACME
+		// servers commonly omit the root, in which case there is nothing to
+		// store.
+		if !bytes.Equal(ca.RawIssuer, ca.RawSubject) || ca.CheckSignatureFrom(ca) != nil {
+			return nil, nil
+		}
+		return bundle.CAPEM, nil
+
+	default:
+		return bundle.CAPEM, nil
+	}
+}
+
 // Build order. If we error here it is a terminating failure.
 func buildOrder(cr *v1.CertificateRequest, csr *x509.CertificateRequest, enableDurationFeature bool) (*cmacme.Order, error) {
 	var ipAddresses []string
@@ -745,3 +745,119 @@ func Test_buildOrder(t *testing.T) {
 		})
 	}
 }
+
+func Test_caForContent(t *testing.T) {
+	root := mustCreateCACertificate(t, nil, "root")
+	int1 := mustCreateCACertificate(t, root, "intermediate-1")
+	int2 := mustCreateCACertificate(t, int1, "intermediate-2")
+	leaf := mustCreateCACertificate(t, int2, "leaf")
+
+	joinPEM := func(pems ...[]byte) []byte {
+		var out []byte
+		for _, p := range pems {
+			out = append(out, p...)
+		}
+		return out
+	}
+
+	tests := map[string]struct {
+		chain   []byte
+		content cmacme.ACMECAContent
+		want    []byte
+	}{
+		"if no content is set, the highest certificate in the chain should be returned": {
+			chain: joinPEM(leaf.pem, int2.pem, int1.pem, root.pem),
+			want:  root.pem,
+		},
+		"if content is Chain, all of the issuing certificates should be returned": {
+			chain:   joinPEM(leaf.pem, int2.pem, int1.pem, root.pem),
+			content: cmacme.ACMECAContentChain,
+			want:    joinPEM(int2.pem, int1.pem, root.pem),
+		},
+		"if content is Chain and the chain has no root, all of the intermediates should be returned": {
+			chain:   joinPEM(leaf.pem, int2.pem, int1.pem),
+			content: cmacme.ACMECAContentChain,
+			want:    joinPEM(int2.pem, int1.pem),
+		},
+		"if content is Issuer, only the issuer of the leaf should be returned": {
+			chain:   joinPEM(leaf.pem, int2.pem, int1.pem, root.pem),
+			content: cmacme.ACMECAContentIssuer,
+			want:    int2.pem,
+		},
+		"if content is Root, only the self-signed root should be returned": {
+			chain:   joinPEM(leaf.pem, int2.pem, int1.pem, root.pem),
+			content: cmacme.ACMECAContentRoot,
+			want:    root.pem,
+		},
+		"if content is Root and the chain has no root, nothing should be returned": {
+			chain:   joinPEM(leaf.pem, int2.pem, int1.pem),
+			content: cmacme.ACMECAContentRoot,
+			want:    nil,
+		},
+		"if only a single certificate is returned, nothing should be returned": {
+			chain:   leaf.pem,
+			content: cmacme.ACMECAContentChain,
+			want:    nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			bundle, err := pki.ParseSingleCertificateChainPEM(test.chain)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := caForContent(bundle, test.content)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != string(test.want) {
+				t.Errorf("unexpected CA, exp=%s got=%s", test.want, got)
+			}
+		})
+	}
+}
+
+type testCertificate struct {
+	pem  []byte
+	cert *x509.Certificate
+	pk   crypto.Signer
+}
+
+func mustCreateCACertificate(t *testing.T, issuer *testCertificate, name string) *testCertificate {
+	pk, err := pki.GenerateECPrivateKey(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		Version:               3,
+		BasicConstraintsValid: true,
+		SerialNumber:          big.NewInt(1),
+		PublicKeyAlgorithm:    x509.ECDSA,
+		PublicKey:             pk.Public(),
+		IsCA:                  true,
+		Subject: pkix.Name{
+			CommonName: name,
+		},
+		NotBefore: time.Now(),
+		NotAfter:  time.Now().Add(time.Minute),
+		KeyUsage:  x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+
+	var (
+		issuerCert               = template
+		issuerKey  crypto.Signer = pk
+	)
+	if issuer != nil {
+		issuerCert, issuerKey = issuer.cert, issuer.pk
+	}
+
+	certPEM, cert, err := pki.SignCertificate(template, issuerCert, pk.Public(), issuerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCertificate{pem: certPEM, cert: cert, pk: pk}
+}
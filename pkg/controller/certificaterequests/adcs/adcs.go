@@ -0,0 +1,165 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adcs
+
+import (
+	"context"
+
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/controller/certificaterequests"
+	crutil "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/util"
+	adcsinternal "github.com/jetstack/cert-manager/pkg/internal/adcs"
+	issuerpkg "github.com/jetstack/cert-manager/pkg/issuer"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	cmerrors "github.com/jetstack/cert-manager/pkg/util/errors"
+	utilpki "github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+const (
+	CRControllerName = "certificaterequests-issuer-adcswebenrollment"
+)
+
+type ADCS struct {
+	issuerOptions controllerpkg.IssuerOptions
+	secretsLister corelisters.SecretLister
+	reporter      *crutil.Reporter
+
+	clientBuilder adcsinternal.ClientBuilder
+}
+
+func init() {
+	// create certificate request controller for adcs issuer
+	controllerpkg.Register(CRControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		return controllerpkg.NewBuilder(ctx, CRControllerName).
+			For(certificaterequests.New(apiutil.IssuerADCSWebEnrollment, NewADCS(ctx))).
+			Complete()
+	})
+}
+
+func NewADCS(ctx *controllerpkg.Context) *ADCS {
+	return &ADCS{
+		issuerOptions: ctx.IssuerOptions,
+		secretsLister: ctx.KubeSharedInformerFactory.Core().V1().Secrets().Lister(),
+		reporter:      crutil.NewReporter(ctx.Clock, ctx.Recorder),
+		clientBuilder: adcsinternal.NewBuilder(ctx.IssuerOptions.IssuerBackendCABundle),
+	}
+}
+
+func (a *ADCS) Sign(ctx context.Context, cr *cmapi.CertificateRequest, issuerObj cmapi.GenericIssuer) (*issuerpkg.IssueResponse, error) {
+	log := logf.FromContext(ctx, "sign")
+	log = logf.WithRelatedResource(log, issuerObj)
+
+	client, err := a.clientBuilder(a.issuerOptions.ResourceNamespace(issuerObj), a.secretsLister, issuerObj)
+	if k8sErrors.IsNotFound(err) {
+		message := "Required secret resource not found"
+
+		a.reporter.Pending(cr, err, "SecretMissing", message)
+		log.Error(err, message)
+
+		return nil, nil
+	}
+
+	if err != nil {
+		message := "Failed to initialise ADCS client for signing"
+
+		a.reporter.Pending(cr, err, "ADCSInitError", message)
+		log.Error(err, message)
+
+		return nil, err
+	}
+
+	var certPEM []byte
+	requestID := cr.ObjectMeta.Annotations[cmapi.ADCSRequestIDAnnotationKey]
+
+	// check if the request ID annotation is there, if not submit the request.
+	if requestID == "" {
+		requestID, certPEM, err = client.RequestCertificate(ctx, cr.Spec.Request)
+		if err != nil && requestID != "" {
+			// The request has been accepted by ADCS but the certificate could
+			// not be collected. Record the request ID so that the same request
+			// is retrieved later rather than submitting a new one.
+			metav1.SetMetaDataAnnotation(&cr.ObjectMeta, cmapi.ADCSRequestIDAnnotationKey, requestID)
+
+			if err == adcsinternal.ErrRequestPending {
+				a.reporter.Pending(cr, nil, "IssuancePending", "ADCS certificate request is pending approval")
+				log.V(logf.DebugLevel).Info("certificate request is pending approval", "request_id", requestID)
+
+				return nil, nil
+			}
+		}
+	} else {
+		certPEM, err = client.RetrieveCertificate(ctx, requestID)
+	}
+
+	if err != nil {
+		switch err.(type) {
+		case adcsinternal.ErrRequestDenied:
+			message := "ADCS denied the certificate request"
+
+			a.reporter.Failed(cr, err, "RequestDenied", message)
+			log.Error(err, message)
+
+			return nil, nil
+
+		default:
+			message := "Failed to obtain ADCS certificate"
+			reason := "RetrieveError"
+			if err == adcsinternal.ErrRequestPending {
+				message = "ADCS certificate request still pending approval, the request will be retried"
+				reason = "IssuancePending"
+			}
+
+			a.reporter.Pending(cr, err, reason, message)
+			log.Error(err, message)
+
+			return nil, err
+		}
+	}
+
+	log.V(logf.DebugLevel).Info("certificate issued", "request_id", requestID)
+
+	caPEM, err := client.CACertificate(ctx)
+	if err != nil {
+		message := "Failed to retrieve ADCS CA certificate"
+
+		a.reporter.Pending(cr, err, "RetrieveError", message)
+		log.Error(err, message)
+
+		return nil, err
+	}
+
+	bundle, err := utilpki.ParseSingleCertificateChainPEM(append(certPEM, caPEM...))
+	if err != nil {
+		message := "Failed to parse returned certificate bundle"
+
+		a.reporter.Failed(cr, err, "ParseError", message)
+		log.Error(err, message)
+
+		return nil, cmerrors.NewPermanent(err)
+	}
+
+	return &issuerpkg.IssueResponse{
+		Certificate: bundle.ChainPEM,
+		CA:          bundle.CAPEM,
+	}, nil
+}
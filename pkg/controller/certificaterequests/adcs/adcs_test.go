@@ -0,0 +1,484 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adcs
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	coretesting "k8s.io/client-go/testing"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/controller/certificaterequests"
+	controllertest "github.com/jetstack/cert-manager/pkg/controller/test"
+	adcsinternal "github.com/jetstack/cert-manager/pkg/internal/adcs"
+	adcsfake "github.com/jetstack/cert-manager/pkg/internal/adcs/fake"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+var (
+	fixedClockStart = time.Now()
+	fixedClock      = fakeclock.NewFakeClock(fixedClockStart)
+)
+
+func generateCSR(t *testing.T, secretKey crypto.Signer) []byte {
+	template := x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName: "test-common-name",
+		},
+		DNSNames: []string{
+			"foo.example.com", "bar.example.com",
+		},
+		SignatureAlgorithm: x509.ECDSAWithSHA256,
+		PublicKey:          secretKey.Public(),
+	}
+
+	csrBytes, err := x509.CreateCertificateRequest(rand.Reader, &template, secretKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrBytes})
+}
+
+func TestSign(t *testing.T) {
+	metaFixedClockStart := metav1.NewTime(fixedClockStart)
+	rootPK, err := pki.GenerateECPrivateKey(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootTmpl := &x509.Certificate{
+		Version:               3,
+		BasicConstraintsValid: true,
+		SerialNumber:          serialNumber,
+		PublicKeyAlgorithm:    x509.ECDSA,
+		PublicKey:             rootPK.Public(),
+		IsCA:                  true,
+		Subject: pkix.Name{
+			CommonName: "root-ca",
+		},
+		NotBefore: time.Now(),
+		NotAfter:  time.Now().Add(time.Minute),
+		KeyUsage:  x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	rootPEM, rootCert, err := pki.SignCertificate(rootTmpl, rootTmpl, rootPK.Public(), rootPK)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testPK, err := pki.GenerateECPrivateKey(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	csrPEM := generateCSR(t, testPK)
+
+	adcsSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-adcs-secret",
+			Namespace: gen.DefaultTestNamespace,
+		},
+		Data: map[string][]byte{
+			"username": []byte("test-username"),
+			"password": []byte("test-password"),
+		},
+	}
+
+	adcsIssuer := gen.Issuer("test-issuer",
+		gen.SetIssuerADCSWebEnrollment(cmapi.ADCSWebEnrollmentIssuer{
+			URL:      "https://adcs.example.com/certsrv",
+			Template: "WebServer",
+			CredentialsRef: cmmeta.LocalObjectReference{
+				Name: adcsSecret.Name,
+			},
+		}),
+		gen.AddIssuerCondition(cmapi.IssuerCondition{
+			Type:   cmapi.IssuerConditionReady,
+			Status: cmmeta.ConditionTrue,
+		}),
+	)
+
+	baseCR := gen.CertificateRequest("test-cr",
+		gen.SetCertificateRequestCSR(csrPEM),
+		gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+			Group: certmanager.GroupName,
+			Name:  adcsIssuer.Name,
+			Kind:  adcsIssuer.Kind,
+		}),
+		gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+			Type:               cmapi.CertificateRequestConditionApproved,
+			Status:             cmmeta.ConditionTrue,
+			Reason:             "cert-manager.io",
+			Message:            "Certificate request has been approved by cert-manager.io",
+			LastTransitionTime: &metaFixedClockStart,
+		}),
+	)
+
+	pendingCR := gen.CertificateRequestFrom(baseCR,
+		gen.AddCertificateRequestAnnotations(map[string]string{cmapi.ADCSRequestIDAnnotationKey: "5"}),
+	)
+
+	template, err := pki.GenerateTemplateFromCertificateRequest(baseCR)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPEM, _, err := pki.SignCertificate(template, rootCert, testPK.Public(), rootPK)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	returnsCA := func(context.Context) ([]byte, error) {
+		return rootPEM, nil
+	}
+
+	clientReturnsPending := &adcsfake.ADCS{
+		RequestCertificateFn: func(context.Context, []byte) (string, []byte, error) {
+			return "5", nil, adcsinternal.ErrRequestPending
+		},
+		RetrieveCertificateFn: func(_ context.Context, requestID string) ([]byte, error) {
+			if requestID != "5" {
+				t.Errorf("expected request ID %q to be retrieved but got %q", "5", requestID)
+			}
+			return nil, adcsinternal.ErrRequestPending
+		},
+		CACertificateFn: returnsCA,
+	}
+	clientReturnsApproved := &adcsfake.ADCS{
+		RequestCertificateFn: func(context.Context, []byte) (string, []byte, error) {
+			return "5", nil, adcsinternal.ErrRequestPending
+		},
+		RetrieveCertificateFn: func(_ context.Context, requestID string) ([]byte, error) {
+			if requestID != "5" {
+				t.Errorf("expected request ID %q to be retrieved but got %q", "5", requestID)
+			}
+			return certPEM, nil
+		},
+		CACertificateFn: returnsCA,
+	}
+	clientReturnsCert := &adcsfake.ADCS{
+		RequestCertificateFn: func(context.Context, []byte) (string, []byte, error) {
+			return "5", certPEM, nil
+		},
+		CACertificateFn: returnsCA,
+	}
+	clientReturnsDenied := &adcsfake.ADCS{
+		RequestCertificateFn: func(context.Context, []byte) (string, []byte, error) {
+			return "", nil, adcsinternal.ErrRequestDenied{Message: "Denied by Policy Module"}
+		},
+	}
+	clientReturnsGenericError := &adcsfake.ADCS{
+		RequestCertificateFn: func(context.Context, []byte) (string, []byte, error) {
+			return "", nil, errors.New("this is an error")
+		},
+	}
+
+	tests := map[string]testT{
+		"if the credentials secret is missing then set pending and return nil": {
+			certificateRequest: baseCR.DeepCopy(),
+			builder: &controllertest.Builder{
+				CertManagerObjects: []runtime.Object{baseCR.DeepCopy(), adcsIssuer.DeepCopy()},
+				ExpectedEvents: []string{
+					`Normal SecretMissing Required secret resource not found: secret "test-adcs-secret" not found`,
+				},
+				ExpectedActions: []controllertest.Action{
+					controllertest.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCR,
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             cmapi.CertificateRequestReasonPending,
+								Message:            `Required secret resource not found: secret "test-adcs-secret" not found`,
+								LastTransitionTime: &metaFixedClockStart,
+							}),
+						),
+					)),
+				},
+			},
+		},
+		"if the request is pending approval then record the request ID and set pending": {
+			certificateRequest: baseCR.DeepCopy(),
+			builder: &controllertest.Builder{
+				KubeObjects:        []runtime.Object{adcsSecret},
+				CertManagerObjects: []runtime.Object{baseCR.DeepCopy(), adcsIssuer.DeepCopy()},
+				ExpectedEvents: []string{
+					"Normal IssuancePending ADCS certificate request is pending approval",
+				},
+				ExpectedActions: []controllertest.Action{
+					controllertest.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(pendingCR,
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             cmapi.CertificateRequestReasonPending,
+								Message:            "ADCS certificate request is pending approval",
+								LastTransitionTime: &metaFixedClockStart,
+							}),
+						),
+					)),
+				},
+			},
+			fakeClient: clientReturnsPending,
+		},
+		"if the recorded request is still pending approval then set pending and return error": {
+			certificateRequest: pendingCR.DeepCopy(),
+			builder: &controllertest.Builder{
+				KubeObjects:        []runtime.Object{adcsSecret},
+				CertManagerObjects: []runtime.Object{pendingCR.DeepCopy(), adcsIssuer.DeepCopy()},
+				ExpectedEvents: []string{
+					"Normal IssuancePending ADCS certificate request still pending approval, the request will be retried: certificate request is pending approval by a CA administrator",
+				},
+				ExpectedActions: []controllertest.Action{
+					controllertest.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(pendingCR,
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             cmapi.CertificateRequestReasonPending,
+								Message:            "ADCS certificate request still pending approval, the request will be retried: certificate request is pending approval by a CA administrator",
+								LastTransitionTime: &metaFixedClockStart,
+							}),
+						),
+					)),
+				},
+			},
+			fakeClient:  clientReturnsPending,
+			expectedErr: true,
+		},
+		"if the request is approved after pending then the certificate is retrieved on the next sync": {
+			certificateRequest: baseCR.DeepCopy(),
+			builder: &controllertest.Builder{
+				KubeObjects:        []runtime.Object{adcsSecret},
+				CertManagerObjects: []runtime.Object{baseCR.DeepCopy(), adcsIssuer.DeepCopy()},
+				ExpectedEvents: []string{
+					"Normal IssuancePending ADCS certificate request is pending approval",
+					"Normal CertificateIssued Certificate fetched from issuer successfully",
+				},
+				ExpectedActions: []controllertest.Action{
+					controllertest.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(pendingCR,
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             cmapi.CertificateRequestReasonPending,
+								Message:            "ADCS certificate request is pending approval",
+								LastTransitionTime: &metaFixedClockStart,
+							}),
+						),
+					)),
+					controllertest.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(pendingCR,
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionTrue,
+								Reason:             cmapi.CertificateRequestReasonIssued,
+								Message:            "Certificate fetched from issuer successfully",
+								LastTransitionTime: &metaFixedClockStart,
+							}),
+							gen.SetCertificateRequestCertificate(certPEM),
+							gen.SetCertificateRequestCA(rootPEM),
+						),
+					)),
+				},
+			},
+			fakeClient: clientReturnsApproved,
+			resync:     true,
+		},
+		"if the certificate is issued immediately then return it without recording the request ID": {
+			certificateRequest: baseCR.DeepCopy(),
+			builder: &controllertest.Builder{
+				KubeObjects:        []runtime.Object{adcsSecret},
+				CertManagerObjects: []runtime.Object{baseCR.DeepCopy(), adcsIssuer.DeepCopy()},
+				ExpectedEvents: []string{
+					"Normal CertificateIssued Certificate fetched from issuer successfully",
+				},
+				ExpectedActions: []controllertest.Action{
+					controllertest.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCR,
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionTrue,
+								Reason:             cmapi.CertificateRequestReasonIssued,
+								Message:            "Certificate fetched from issuer successfully",
+								LastTransitionTime: &metaFixedClockStart,
+							}),
+							gen.SetCertificateRequestCertificate(certPEM),
+							gen.SetCertificateRequestCA(rootPEM),
+						),
+					)),
+				},
+			},
+			fakeClient: clientReturnsCert,
+		},
+		"if the request is denied then fail the request": {
+			certificateRequest: baseCR.DeepCopy(),
+			builder: &controllertest.Builder{
+				KubeObjects:        []runtime.Object{adcsSecret},
+				CertManagerObjects: []runtime.Object{baseCR.DeepCopy(), adcsIssuer.DeepCopy()},
+				ExpectedEvents: []string{
+					"Warning RequestDenied ADCS denied the certificate request: certificate request was denied: Denied by Policy Module",
+				},
+				ExpectedActions: []controllertest.Action{
+					controllertest.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCR,
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             cmapi.CertificateRequestReasonFailed,
+								Message:            "ADCS denied the certificate request: certificate request was denied: Denied by Policy Module",
+								LastTransitionTime: &metaFixedClockStart,
+							}),
+							gen.SetCertificateRequestFailureTime(metaFixedClockStart),
+						),
+					)),
+				},
+			},
+			fakeClient: clientReturnsDenied,
+		},
+		"if the request fails then set pending and return error": {
+			certificateRequest: baseCR.DeepCopy(),
+			builder: &controllertest.Builder{
+				KubeObjects:        []runtime.Object{adcsSecret},
+				CertManagerObjects: []runtime.Object{baseCR.DeepCopy(), adcsIssuer.DeepCopy()},
+				ExpectedEvents: []string{
+					"Normal RetrieveError Failed to obtain ADCS certificate: this is an error",
+				},
+				ExpectedActions: []controllertest.Action{
+					controllertest.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCR,
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             cmapi.CertificateRequestReasonPending,
+								Message:            "Failed to obtain ADCS certificate: this is an error",
+								LastTransitionTime: &metaFixedClockStart,
+							}),
+						),
+					)),
+				},
+			},
+			fakeClient:  clientReturnsGenericError,
+			expectedErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fixedClock.SetTime(fixedClockStart)
+			test.builder.Clock = fixedClock
+			runTest(t, test)
+		})
+	}
+}
+
+type testT struct {
+	builder            *controllertest.Builder
+	certificateRequest *cmapi.CertificateRequest
+
+	fakeClient *adcsfake.ADCS
+
+	expectedErr bool
+
+	// resync simulates a second sync of the CertificateRequest after the
+	// request ID annotation has been persisted.
+	resync bool
+}
+
+func runTest(t *testing.T, test testT) {
+	test.builder.T = t
+	test.builder.Init()
+	defer test.builder.Stop()
+
+	a := NewADCS(test.builder.Context)
+
+	if test.fakeClient != nil {
+		a.clientBuilder = func(namespace string, secretsLister corelisters.SecretLister,
+			issuer cmapi.GenericIssuer) (adcsinternal.Interface, error) {
+			if _, err := secretsLister.Secrets(namespace).Get(issuer.GetSpec().ADCSWebEnrollment.CredentialsRef.Name); err != nil {
+				return nil, err
+			}
+			return test.fakeClient, nil
+		}
+	}
+
+	controller := certificaterequests.New(apiutil.IssuerADCSWebEnrollment, a)
+	controller.Register(test.builder.Context)
+	test.builder.Start()
+
+	err := controller.Sync(context.Background(), test.certificateRequest)
+
+	if err == nil && test.resync {
+		metav1.SetMetaDataAnnotation(&test.certificateRequest.ObjectMeta, cmapi.ADCSRequestIDAnnotationKey, "5")
+		err = controller.Sync(context.Background(), test.certificateRequest)
+	}
+
+	if err != nil && !test.expectedErr {
+		t.Errorf("expected to not get an error, but got: %v", err)
+	}
+	if err == nil && test.expectedErr {
+		t.Errorf("expected to get an error but did not get one")
+	}
+
+	test.builder.CheckAndFinish(err)
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificaterequests
+
+import (
+	"sync"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+// issuerBulkhead limits the number of CertificateRequests that may be signed
+// by each issuer at once, so that requests to an issuer that is slow or not
+// responding cannot occupy all of the workers of a controller.
+type issuerBulkhead struct {
+	// limit is the maximum number of in-flight requests per issuer. If zero
+	// or negative, there is no limit.
+	limit int
+
+	lock     sync.Mutex
+	inFlight map[string]int
+}
+
+func newIssuerBulkhead(limit int) *issuerBulkhead {
+	return &issuerBulkhead{
+		limit:    limit,
+		inFlight: make(map[string]int),
+	}
+}
+
+// tryAcquire reserves a slot for a request to the issuer of the given
+// CertificateRequest, returning false without blocking if the issuer already
+// has the maximum number of requests in flight. If true is returned, release
+// must be called once the request completes.
+func (b *issuerBulkhead) tryAcquire(cr *cmapi.CertificateRequest) bool {
+	if b == nil || b.limit <= 0 {
+		return true
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	key := issuerKey(cr)
+	if b.inFlight[key] >= b.limit {
+		return false
+	}
+	b.inFlight[key]++
+	return true
+}
+
+// release frees the slot reserved by a previous call to tryAcquire.
+func (b *issuerBulkhead) release(cr *cmapi.CertificateRequest) {
+	if b == nil || b.limit <= 0 {
+		return
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	key := issuerKey(cr)
+	if b.inFlight[key] <= 1 {
+		delete(b.inFlight, key)
+		return
+	}
+	b.inFlight[key]--
+}
+
+// issuerKey returns a key identifying the issuer referenced by the given
+// CertificateRequest. Issuers are namespaced, so the namespace of the request
+// is included for them.
+func issuerKey(cr *cmapi.CertificateRequest) string {
+	if cr.Spec.IssuerRef.Kind == cmapi.ClusterIssuerKind {
+		return cmapi.ClusterIssuerKind + "/" + cr.Spec.IssuerRef.Name
+	}
+	return cmapi.IssuerKind + "/" + cr.Namespace + "/" + cr.Spec.IssuerRef.Name
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificaterequests
+
+import (
+	"testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestIssuerBulkhead(t *testing.T) {
+	slowCR := gen.CertificateRequest("slow-1",
+		gen.SetCertificateRequestNamespace("default"),
+		gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{Name: "slow", Kind: cmapi.IssuerKind}),
+	)
+	otherSlowCR := gen.CertificateRequestFrom(slowCR, gen.SetCertificateRequestName("slow-2"))
+	otherNamespaceCR := gen.CertificateRequestFrom(slowCR, gen.SetCertificateRequestNamespace("other"))
+	fastCR := gen.CertificateRequestFrom(slowCR,
+		gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{Name: "fast", Kind: cmapi.IssuerKind}),
+	)
+
+	b := newIssuerBulkhead(1)
+	if !b.tryAcquire(slowCR) {
+		t.Fatal("expected first request to the slow issuer to acquire a slot")
+	}
+	if b.tryAcquire(otherSlowCR) {
+		t.Error("expected second request to the slow issuer to be rejected while the first is in flight")
+	}
+	if !b.tryAcquire(otherNamespaceCR) {
+		t.Error("expected request to an issuer with the same name in another namespace to acquire a slot")
+	}
+	if !b.tryAcquire(fastCR) {
+		t.Error("expected request to another issuer to acquire a slot")
+	}
+
+	b.release(slowCR)
+	if !b.tryAcquire(otherSlowCR) {
+		t.Error("expected request to the slow issuer to acquire a slot once released")
+	}
+}
+
+func TestIssuerBulkheadUnlimited(t *testing.T) {
+	cr := gen.CertificateRequest("test",
+		gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{Name: "issuer", Kind: cmapi.ClusterIssuerKind}),
+	)
+
+	for _, b := range []*issuerBulkhead{nil, newIssuerBulkhead(0)} {
+		for i := 0; i < 10; i++ {
+			if !b.tryAcquire(cr) {
+				t.Fatalf("expected unlimited bulkhead to always acquire a slot, failed on attempt %d", i)
+			}
+		}
+	}
+}
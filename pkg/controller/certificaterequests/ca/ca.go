@@ -20,10 +20,13 @@ import (
 	"context"
 	"crypto"
 	"crypto/x509"
+	"errors"
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
 
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
@@ -41,6 +44,11 @@ const (
 	CRControllerName = "certificaterequests-issuer-ca"
 )
 
+var (
+	errCAIssuanceNotAllowed    = errors.New("request for a CA certificate denied by issuer")
+	errMaxLeafDurationExceeded = errors.New("requested duration exceeds the maximum allowed by issuer")
+)
+
 type templateGenerator func(*cmapi.CertificateRequest) (*x509.Certificate, error)
 type signingFn func([]*x509.Certificate, crypto.Signer, *x509.Certificate) (pki.PEMBundle, error)
 
@@ -49,6 +57,7 @@ type CA struct {
 	secretsLister corelisters.SecretLister
 
 	reporter *crutil.Reporter
+	recorder record.EventRecorder
 
 	// Used for testing to get reproducible resulting certificates
 	templateGenerator templateGenerator
@@ -69,6 +78,7 @@ func NewCA(ctx *controllerpkg.Context) *CA {
 		issuerOptions:     ctx.IssuerOptions,
 		secretsLister:     ctx.KubeSharedInformerFactory.Core().V1().Secrets().Lister(),
 		reporter:          crutil.NewReporter(ctx.Clock, ctx.Recorder),
+		recorder:          ctx.Recorder,
 		templateGenerator: pki.GenerateTemplateFromCertificateRequest,
 		signingFn:         pki.SignCSRTemplate,
 	}
@@ -118,9 +128,51 @@ func (c *CA) Sign(ctx context.Context, cr *cmapi.CertificateRequest, issuerObj c
 		return nil, nil
 	}
 
+	template.SerialNumber, err = pki.GenerateSerialNumber(c.issuerOptions.SerialNumberBits)
+	if err != nil {
+		message := "Error generating certificate serial number"
+		c.reporter.Failed(cr, err, "SigningError", message)
+		log.Error(err, message)
+		return nil, nil
+	}
+
+	if template.IsCA && !issuerObj.GetSpec().CA.AllowCAIssuance {
+		message := "Issuer does not permit signing CA certificates, set spec.ca.allowCAIssuance to allow this"
+		c.reporter.Failed(cr, errCAIssuanceNotAllowed, "CAIssuanceNotAllowed", message)
+		log.Error(errCAIssuanceNotAllowed, message)
+		return nil, nil
+	}
+
+	if !c.enforceMaxLeafDuration(cr, issuerObj.GetSpec().CA, template) {
+		log.Error(errMaxLeafDurationExceeded, "Requested duration exceeds the maximum leaf duration of the issuer")
+		return nil, nil
+	}
+
 	template.CRLDistributionPoints = issuerObj.GetSpec().CA.CRLDistributionPoints
 	template.OCSPServer = issuerObj.GetSpec().CA.OCSPServers
 
+	policies, err := pki.CertificatePoliciesForRequest(cr.Spec.Request, issuerObj.GetSpec().CA.CertificatePolicies, issuerObj.GetSpec().CA.AllowedCertificatePolicies)
+	if err != nil {
+		message := "Error decoding requested certificate policies"
+		c.reporter.Failed(cr, err, "SigningError", message)
+		log.Error(err, message)
+		return nil, nil
+	}
+
+	if err := pki.AddCertificatePolicies(template, policies); err != nil {
+		message := "Error adding certificate policies"
+		c.reporter.Failed(cr, err, "SigningError", message)
+		log.Error(err, message)
+		return nil, nil
+	}
+
+	if err := pki.SetKeyIdentifiers(template, caCerts[0], issuerObj.GetSpec().CA.SubjectKeyIdentifierMethod); err != nil {
+		message := "Error computing certificate key identifiers"
+		c.reporter.Failed(cr, err, "SigningError", message)
+		log.Error(err, message)
+		return nil, nil
+	}
+
 	bundle, err := c.signingFn(caCerts, caKey, template)
 	if err != nil {
 		message := "Error signing certificate"
@@ -129,6 +181,14 @@ func (c *CA) Sign(ctx context.Context, cr *cmapi.CertificateRequest, issuerObj c
 		return nil, err
 	}
 
+	bundle.ChainPEM, err = pki.ApplyCertificateProfile(bundle.ChainPEM, issuerObj.GetSpec().CA.CertificateProfile, caCerts[0], caKey)
+	if err != nil {
+		message := "Error applying certificate profile"
+		c.reporter.Failed(cr, err, "SigningError", message)
+		log.Error(err, message)
+		return nil, nil
+	}
+
 	log.V(logf.DebugLevel).Info("certificate issued")
 
 	return &issuerpkg.IssueResponse{
@@ -136,3 +196,34 @@ func (c *CA) Sign(ctx context.Context, cr *cmapi.CertificateRequest, issuerObj c
 		CA:          bundle.CAPEM,
 	}, nil
 }
+
+// enforceMaxLeafDuration ensures that the given template of a non-CA
+// certificate is not valid for longer than the maximum leaf duration of the
+// issuer. Depending on the policy of the issuer, a template that is too long
+// is either clamped to the maximum duration, in which case an event is
+// recorded, or the request is failed and false is returned.
+func (c *CA) enforceMaxLeafDuration(cr *cmapi.CertificateRequest, iss *cmapi.CAIssuer, template *x509.Certificate) bool {
+	if iss.MaxLeafDuration == nil || template.IsCA {
+		return true
+	}
+
+	maxDuration := iss.MaxLeafDuration.Duration
+	// the template's validity is derived from the requested duration, but
+	// may differ from it by the time between the two calls to time.Now()
+	requested := apiutil.DefaultCertDuration(cr.Spec.Duration)
+	if requested <= maxDuration {
+		return true
+	}
+
+	if iss.MaxLeafDurationPolicy == cmapi.MaxLeafDurationPolicyReject {
+		message := fmt.Sprintf("Requested duration of %s exceeds the maximum duration of %s allowed by the issuer", requested, maxDuration)
+		c.reporter.Failed(cr, errMaxLeafDurationExceeded, "MaxLeafDurationExceeded", message)
+		return false
+	}
+
+	template.NotAfter = template.NotBefore.Add(maxDuration)
+	c.recorder.Eventf(cr, corev1.EventTypeWarning, "MaxLeafDurationEnforced",
+		"Requested duration of %s exceeds the maximum duration of %s allowed by the issuer, so the certificate is signed with the maximum duration",
+		requested, maxDuration)
+	return true
+}
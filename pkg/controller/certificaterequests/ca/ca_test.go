@@ -20,12 +20,14 @@ import (
 	"context"
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/asn1"
 	"encoding/pem"
 	"errors"
+	"fmt"
 	"math"
 	"math/big"
 	"testing"
@@ -104,7 +106,7 @@ func TestSign(t *testing.T) {
 	metaFixedClockStart := metav1.NewTime(fixedClockStart)
 
 	baseIssuer := gen.Issuer("test-issuer",
-		gen.SetIssuerCA(cmapi.CAIssuer{SecretName: "root-ca-secret"}),
+		gen.SetIssuerCA(cmapi.CAIssuer{SecretName: "root-ca-secret", AllowCAIssuance: true}),
 		gen.AddIssuerCondition(cmapi.IssuerCondition{
 			Type:   cmapi.IssuerConditionReady,
 			Status: cmmeta.ConditionTrue,
@@ -472,12 +474,36 @@ func TestCA_Sign(t *testing.T) {
 	}
 	testCSR := generateCSR(t, testpk, x509.ECDSAWithSHA256)
 
+	// Build test CSR requesting certificate policies
+	policiesCSRTemplate, err := pki.GenerateCSR(&cmapi.Certificate{Spec: cmapi.CertificateSpec{
+		CommonName: "test",
+		PrivateKey: &cmapi.CertificatePrivateKey{Algorithm: cmapi.ECDSAKeyAlgorithm},
+		CertificatePolicies: []cmapi.CertificatePolicy{
+			{PolicyID: "0.4.0.194112.1.2", CPSURI: "https://requester.example.com/cps"},
+			{PolicyID: "2.23.140.1.2.1"},
+		},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	policiesCSRDER, err := pki.EncodeCSR(policiesCSRTemplate, testpk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	policiesCSR := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: policiesCSRDER})
+
 	tests := map[string]struct {
 		givenCASecret    *corev1.Secret
 		givenCAIssuer    cmapi.GenericIssuer
 		givenCR          *cmapi.CertificateRequest
+		serialNumberBits int
 		assertSignedCert func(t *testing.T, got *x509.Certificate)
 		wantErr          string
+		// wantFailedReason, if set, is the reason of the event expected
+		// when the CertificateRequest is failed without a retryable error.
+		wantFailedReason string
+		// wantEvents are the events expected when the certificate is signed.
+		wantEvents []string
 	}{
 		"when the CertificateRequest has the duration field set, it should appear as notAfter on the signed ca": {
 			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rootPK, rootCert))),
@@ -520,10 +546,11 @@ func TestCA_Sign(t *testing.T) {
 				assert.LessOrEqualf(t, deltaSec, 1., "expected a time delta lower than 1 second. Time expected='%s', got='%s'", expectNotAfter.String(), got.NotAfter.String())
 			},
 		},
-		"when the CertificateRequest has the isCA field set, it should appear on the signed ca": {
+		"when the CertificateRequest has the isCA field set and the Issuer allows CA issuance, it should appear on the signed ca": {
 			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rootPK, rootCert))),
 			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
-				SecretName: "secret-1",
+				SecretName:      "secret-1",
+				AllowCAIssuance: true,
 			})),
 			givenCR: gen.CertificateRequest("cr-1",
 				gen.SetCertificateRequestCSR(testCSR),
@@ -538,6 +565,22 @@ func TestCA_Sign(t *testing.T) {
 				assert.Equal(t, true, got.IsCA)
 			},
 		},
+		"when the CertificateRequest has the isCA field set and the Issuer does not allow CA issuance, it should be failed": {
+			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rootPK, rootCert))),
+			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+				SecretName: "secret-1",
+			})),
+			givenCR: gen.CertificateRequest("cr-1",
+				gen.SetCertificateRequestCSR(testCSR),
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Group: certmanager.GroupName,
+					Kind:  "Issuer",
+				}),
+				gen.SetCertificateRequestIsCA(true),
+			),
+			wantFailedReason: "CAIssuanceNotAllowed",
+		},
 		"when the Issuer has ocspServers set, it should appear on the signed ca": {
 			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rootPK, rootCert))),
 			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
@@ -561,6 +604,7 @@ func TestCA_Sign(t *testing.T) {
 			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
 				SecretName:            "secret-1",
 				CRLDistributionPoints: []string{"http://www.example.com/crl/test.crl"},
+				AllowCAIssuance:       true,
 			})),
 			givenCR: gen.CertificateRequest("cr-1",
 				gen.SetCertificateRequestIsCA(true),
@@ -575,6 +619,226 @@ func TestCA_Sign(t *testing.T) {
 				assert.Equal(t, []string{"http://www.example.com/crl/test.crl"}, gotCA.CRLDistributionPoints)
 			},
 		},
+		"when the CertificateRequest asks for certificate policies the Issuer does not allow, only the Issuer's policies should appear on the signed certificate": {
+			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rootPK, rootCert))),
+			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+				SecretName: "secret-1",
+				CertificatePolicies: []cmapi.CertificatePolicy{
+					{PolicyID: "1.3.6.1.4.1.99999.1", CPSURI: "https://pki.example.com/cps"},
+				},
+			})),
+			givenCR: gen.CertificateRequest("cr-1",
+				gen.SetCertificateRequestCSR(policiesCSR),
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Group: certmanager.GroupName,
+					Kind:  "Issuer",
+				}),
+			),
+			assertSignedCert: func(t *testing.T, got *x509.Certificate) {
+				assert.Equal(t, []asn1.ObjectIdentifier{{1, 3, 6, 1, 4, 1, 99999, 1}}, got.PolicyIdentifiers)
+
+				policies, err := pki.CertificatePoliciesFromExtensions(got.Extensions)
+				require.NoError(t, err)
+				assert.Equal(t, []cmapi.CertificatePolicy{
+					{PolicyID: "1.3.6.1.4.1.99999.1", CPSURI: "https://pki.example.com/cps"},
+				}, policies)
+			},
+		},
+		"when the CertificateRequest asks for certificate policies the Issuer allows, they should appear on the signed certificate as configured on the Issuer": {
+			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rootPK, rootCert))),
+			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+				SecretName: "secret-1",
+				CertificatePolicies: []cmapi.CertificatePolicy{
+					{PolicyID: "1.3.6.1.4.1.99999.1"},
+				},
+				AllowedCertificatePolicies: []cmapi.CertificatePolicy{
+					{PolicyID: "0.4.0.194112.1.2", CPSURI: "https://pki.example.com/cps"},
+				},
+			})),
+			givenCR: gen.CertificateRequest("cr-1",
+				gen.SetCertificateRequestCSR(policiesCSR),
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Group: certmanager.GroupName,
+					Kind:  "Issuer",
+				}),
+			),
+			assertSignedCert: func(t *testing.T, got *x509.Certificate) {
+				// 2.23.140.1.2.1 is requested but not allowed by the Issuer
+				assert.Equal(t, []asn1.ObjectIdentifier{
+					{1, 3, 6, 1, 4, 1, 99999, 1},
+					{0, 4, 0, 194112, 1, 2},
+				}, got.PolicyIdentifiers)
+
+				policies, err := pki.CertificatePoliciesFromExtensions(got.Extensions)
+				require.NoError(t, err)
+				assert.Equal(t, []cmapi.CertificatePolicy{
+					{PolicyID: "1.3.6.1.4.1.99999.1"},
+					{PolicyID: "0.4.0.194112.1.2", CPSURI: "https://pki.example.com/cps"},
+				}, policies)
+			},
+		},
+		"when the Issuer has an invalid certificate policy, it should be failed": {
+			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rootPK, rootCert))),
+			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+				SecretName:          "secret-1",
+				CertificatePolicies: []cmapi.CertificatePolicy{{PolicyID: "not-an-oid"}},
+			})),
+			givenCR: gen.CertificateRequest("cr-1",
+				gen.SetCertificateRequestCSR(testCSR),
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Group: certmanager.GroupName,
+					Kind:  "Issuer",
+				}),
+			),
+			wantFailedReason: "SigningError",
+		},
+		"when the requested duration exceeds the maxLeafDuration of the Issuer, it should be clamped": {
+			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rootPK, rootCert))),
+			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+				SecretName:      "secret-1",
+				MaxLeafDuration: &metav1.Duration{Duration: time.Hour},
+			})),
+			givenCR: gen.CertificateRequest("cr-1",
+				gen.SetCertificateRequestCSR(testCSR),
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Group: certmanager.GroupName,
+					Kind:  "Issuer",
+				}),
+				gen.SetCertificateRequestDuration(&metav1.Duration{Duration: 24 * time.Hour}),
+			),
+			assertSignedCert: func(t *testing.T, got *x509.Certificate) {
+				assert.Equal(t, time.Hour, got.NotAfter.Sub(got.NotBefore))
+			},
+			wantEvents: []string{
+				"Warning MaxLeafDurationEnforced Requested duration of 24h0m0s exceeds the maximum duration of 1h0m0s allowed by the issuer, so the certificate is signed with the maximum duration",
+			},
+		},
+		"when the requested duration exceeds the maxLeafDuration of the Issuer and the policy is Reject, it should be failed": {
+			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rootPK, rootCert))),
+			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+				SecretName:            "secret-1",
+				MaxLeafDuration:       &metav1.Duration{Duration: time.Hour},
+				MaxLeafDurationPolicy: cmapi.MaxLeafDurationPolicyReject,
+			})),
+			givenCR: gen.CertificateRequest("cr-1",
+				gen.SetCertificateRequestCSR(testCSR),
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Group: certmanager.GroupName,
+					Kind:  "Issuer",
+				}),
+				gen.SetCertificateRequestDuration(&metav1.Duration{Duration: 24 * time.Hour}),
+			),
+			wantFailedReason: "MaxLeafDurationExceeded",
+		},
+		"when the CertificateRequest is for a CA certificate, the maxLeafDuration of the Issuer should not apply": {
+			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rootPK, rootCert))),
+			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+				SecretName:            "secret-1",
+				AllowCAIssuance:       true,
+				MaxLeafDuration:       &metav1.Duration{Duration: time.Hour},
+				MaxLeafDurationPolicy: cmapi.MaxLeafDurationPolicyReject,
+			})),
+			givenCR: gen.CertificateRequest("cr-1",
+				gen.SetCertificateRequestCSR(testCSR),
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Group: certmanager.GroupName,
+					Kind:  "Issuer",
+				}),
+				gen.SetCertificateRequestIsCA(true),
+				gen.SetCertificateRequestDuration(&metav1.Duration{Duration: 24 * time.Hour}),
+			),
+			assertSignedCert: func(t *testing.T, got *x509.Certificate) {
+				assert.Equal(t, 24*time.Hour, got.NotAfter.Sub(got.NotBefore))
+			},
+		},
+		"when the Issuer has the Minimal certificate profile, only the minimal extensions should appear on the signed certificate": {
+			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rootPK, rootCert))),
+			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+				SecretName:                 "secret-1",
+				OCSPServers:                []string{"http://ocsp-v3.example.org"},
+				SubjectKeyIdentifierMethod: cmapi.SubjectKeyIdentifierMethod1,
+				CertificateProfile:         cmapi.CertificateProfileMinimal,
+			})),
+			givenCR: gen.CertificateRequest("cr-1",
+				gen.SetCertificateRequestCSR(testCSR),
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Group: certmanager.GroupName,
+					Kind:  "Issuer",
+				}),
+			),
+			assertSignedCert: func(t *testing.T, got *x509.Certificate) {
+				require.NoError(t, got.CheckSignatureFrom(rootCert))
+				assert.Equal(t, 3, got.Version)
+				assert.Empty(t, got.SubjectKeyId)
+				assert.Empty(t, got.AuthorityKeyId)
+				assert.Empty(t, got.OCSPServer)
+				assert.NotZero(t, got.KeyUsage)
+				assert.True(t, got.BasicConstraintsValid)
+			},
+		},
+		"when the Issuer has the V1 certificate profile, the signed certificate should be a v1 certificate without extensions": {
+			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rootPK, rootCert))),
+			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+				SecretName:         "secret-1",
+				CertificateProfile: cmapi.CertificateProfileV1,
+			})),
+			givenCR: gen.CertificateRequest("cr-1",
+				gen.SetCertificateRequestCSR(testCSR),
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Group: certmanager.GroupName,
+					Kind:  "Issuer",
+				}),
+			),
+			assertSignedCert: func(t *testing.T, got *x509.Certificate) {
+				require.NoError(t, got.CheckSignatureFrom(rootCert))
+				assert.Equal(t, 1, got.Version)
+				assert.Empty(t, got.Extensions)
+			},
+		},
+		"when serialNumberBits is set, the serial number of the signed certificate should have that many random bits": {
+			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rootPK, rootCert))),
+			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+				SecretName: "secret-1",
+			})),
+			givenCR: gen.CertificateRequest("cr-1",
+				gen.SetCertificateRequestCSR(testCSR),
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Group: certmanager.GroupName,
+					Kind:  "Issuer",
+				}),
+			),
+			serialNumberBits: 64,
+			assertSignedCert: func(t *testing.T, got *x509.Certificate) {
+				// The bit above the random bits is always set, so the
+				// length of the serial number does not vary.
+				assert.Equal(t, 65, got.SerialNumber.BitLen())
+			},
+		},
+		"when serialNumberBits is out of range, it should be failed": {
+			givenCASecret: gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(secretDataFor(t, rootPK, rootCert))),
+			givenCAIssuer: gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{
+				SecretName: "secret-1",
+			})),
+			givenCR: gen.CertificateRequest("cr-1",
+				gen.SetCertificateRequestCSR(testCSR),
+				gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+					Name:  "issuer-1",
+					Group: certmanager.GroupName,
+					Kind:  "Issuer",
+				}),
+			),
+			serialNumberBits: 32,
+			wantFailedReason: "SigningError",
+		},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -585,8 +849,10 @@ func TestCA_Sign(t *testing.T) {
 					ClusterResourceNamespace:        "",
 					ClusterIssuerAmbientCredentials: false,
 					IssuerAmbientCredentials:        false,
+					SerialNumberBits:                test.serialNumberBits,
 				},
 				reporter: util.NewReporter(fixedClock, rec),
+				recorder: rec,
 				secretsLister: testlisters.FakeSecretListerFrom(testlisters.NewFakeSecretLister(),
 					testlisters.SetFakeSecretNamespaceListerGet(test.givenCASecret, nil),
 				),
@@ -597,6 +863,15 @@ func TestCA_Sign(t *testing.T) {
 			gotIssueResp, gotErr := c.Sign(context.Background(), test.givenCR, test.givenCAIssuer)
 			if test.wantErr != "" {
 				require.EqualError(t, gotErr, test.wantErr)
+			} else if test.wantFailedReason != "" {
+				require.NoError(t, gotErr)
+				require.Nil(t, gotIssueResp)
+
+				cond := apiutil.GetCertificateRequestCondition(test.givenCR, cmapi.CertificateRequestConditionReady)
+				require.NotNil(t, cond)
+				assert.Equal(t, cmapi.CertificateRequestReasonFailed, cond.Reason)
+				require.Len(t, rec.Events, 1)
+				assert.Contains(t, rec.Events[0], test.wantFailedReason)
 			} else {
 				require.NoError(t, gotErr)
 
@@ -605,11 +880,96 @@ func TestCA_Sign(t *testing.T) {
 				require.NoError(t, err)
 
 				test.assertSignedCert(t, gotCert)
+				assert.Equal(t, test.wantEvents, rec.Events)
 			}
 		})
 	}
 }
 
+// TestCA_SignKeyAlgorithms tests that requests for each type of key are signed
+// by CA issuers with each type of key. The issued certificate must contain the
+// requested public key, while its signature algorithm is that of the CA key.
+func TestCA_SignKeyAlgorithms(t *testing.T) {
+	mustGenerateRSA := func(keySize int) crypto.Signer {
+		pk, err := pki.GenerateRSAPrivateKey(keySize)
+		require.NoError(t, err)
+		return pk
+	}
+	mustGenerateECDSA := func(keySize int) crypto.Signer {
+		pk, err := pki.GenerateECPrivateKey(keySize)
+		require.NoError(t, err)
+		return pk
+	}
+	mustGenerateEd25519 := func() crypto.Signer {
+		_, pk, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		return pk
+	}
+
+	type key struct {
+		signer       crypto.Signer
+		pubKeyAlgo   x509.PublicKeyAlgorithm
+		signatureAlg x509.SignatureAlgorithm
+	}
+	keys := map[string]key{
+		"RSA 2048":   {mustGenerateRSA(2048), x509.RSA, x509.SHA256WithRSA},
+		"RSA 4096":   {mustGenerateRSA(4096), x509.RSA, x509.SHA512WithRSA},
+		"ECDSA P256": {mustGenerateECDSA(pki.ECCurve256), x509.ECDSA, x509.ECDSAWithSHA256},
+		"ECDSA P384": {mustGenerateECDSA(pki.ECCurve384), x509.ECDSA, x509.ECDSAWithSHA384},
+		"Ed25519":    {mustGenerateEd25519(), x509.Ed25519, x509.PureEd25519},
+	}
+
+	for caName, caKey := range keys {
+		caCert, caCertPEM := generateSelfSignedCACert(t, caKey.signer, "root")
+		require.Equal(t, caKey.signatureAlg, caCert.SignatureAlgorithm)
+		caKeyPEM, err := pki.EncodePKCS8PrivateKey(caKey.signer)
+		require.NoError(t, err)
+		caSecret := gen.SecretFrom(gen.Secret("secret-1"), gen.SetSecretNamespace("default"), gen.SetSecretData(map[string][]byte{
+			corev1.TLSPrivateKeyKey: caKeyPEM,
+			corev1.TLSCertKey:       caCertPEM,
+		}))
+
+		for requestName, requestKey := range keys {
+			t.Run(fmt.Sprintf("%s CA signing a %s request", caName, requestName), func(t *testing.T) {
+				rec := &testpkg.FakeRecorder{}
+				c := &CA{
+					issuerOptions: controller.IssuerOptions{},
+					reporter:      util.NewReporter(fixedClock, rec),
+					recorder:      rec,
+					secretsLister: testlisters.FakeSecretListerFrom(testlisters.NewFakeSecretLister(),
+						testlisters.SetFakeSecretNamespaceListerGet(caSecret, nil),
+					),
+					templateGenerator: pki.GenerateTemplateFromCertificateRequest,
+					signingFn:         pki.SignCSRTemplate,
+				}
+
+				cr := gen.CertificateRequest("cr-1",
+					gen.SetCertificateRequestCSR(generateCSR(t, requestKey.signer, x509.UnknownSignatureAlgorithm)),
+					gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{
+						Name:  "issuer-1",
+						Group: certmanager.GroupName,
+						Kind:  "Issuer",
+					}),
+				)
+				issuer := gen.Issuer("issuer-1", gen.SetIssuerCA(cmapi.CAIssuer{SecretName: "secret-1"}))
+
+				resp, err := c.Sign(context.Background(), cr, issuer)
+				require.NoError(t, err)
+				require.NotNil(t, resp, "expected the request to be signed, got events: %v", rec.Events)
+
+				got, err := pki.DecodeX509CertificateBytes(resp.Certificate)
+				require.NoError(t, err)
+				assert.Equal(t, requestKey.pubKeyAlgo, got.PublicKeyAlgorithm)
+				equal, err := pki.PublicKeysEqual(requestKey.signer.Public(), got.PublicKey)
+				require.NoError(t, err)
+				assert.True(t, equal, "expected the certificate to contain the requested public key")
+				assert.Equal(t, caKey.signatureAlg, got.SignatureAlgorithm)
+				require.NoError(t, got.CheckSignatureFrom(caCert))
+			})
+		}
+	}
+}
+
 // Returns a map that is meant to be used for creating a certificate Secret
 // that contains the fields "tls.crt" and "tls.key".
 func secretDataFor(t *testing.T, caKey *ecdsa.PrivateKey, caCrt *x509.Certificate) (secretData map[string][]byte) {
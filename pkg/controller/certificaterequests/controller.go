@@ -34,6 +34,7 @@ import (
 	"github.com/jetstack/cert-manager/pkg/controller/certificaterequests/util"
 	"github.com/jetstack/cert-manager/pkg/issuer"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/metrics"
 )
 
 const (
@@ -80,6 +81,22 @@ type Controller struct {
 	clock clock.Clock
 
 	reporter *util.Reporter
+
+	// skipIssuedCertificateValidityCheck disables failing requests whose
+	// issuer returned a certificate that is not currently valid
+	skipIssuedCertificateValidityCheck bool
+
+	// enableIssuanceRecords enables creating an IssuanceRecord for every
+	// certificate that is issued
+	enableIssuanceRecords bool
+
+	// issuerBulkhead limits the number of requests being signed by each
+	// issuer at once
+	issuerBulkhead *issuerBulkhead
+
+	// metrics is used to record the time taken for requests to be issued or
+	// to fail, labelled with the issuer type of this controller
+	metrics *metrics.Metrics
 }
 
 // New will construct a new certificaterequest controller using the given
@@ -164,6 +181,10 @@ func (c *Controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 	c.recorder = ctx.Recorder
 	c.reporter = util.NewReporter(c.clock, c.recorder)
 	c.cmClient = ctx.CMClient
+	c.skipIssuedCertificateValidityCheck = ctx.IssuerOptions.SkipIssuedCertificateValidityCheck
+	c.enableIssuanceRecords = ctx.IssuerOptions.EnableIssuanceRecords
+	c.issuerBulkhead = newIssuerBulkhead(ctx.IssuerOptions.MaxConcurrentSignsPerIssuer)
+	c.metrics = ctx.Metrics
 
 	c.log.V(logf.DebugLevel).Info("new certificate request controller registered",
 		"type", c.issuerType)
@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificaterequests
+
+import (
+	"context"
+	"crypto/x509"
+
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// createIssuanceRecord creates an IssuanceRecord describing the certificate
+// that has been issued for the given CertificateRequest. The record has the
+// same name as the request, and is not owned by it so that it outlives the
+// request.
+// Failing to create a record does not prevent the CertificateRequest from
+// becoming Ready, since the certificate has already been issued. Instead, a
+// warning Event is recorded on the CertificateRequest.
+func (c *Controller) createIssuanceRecord(ctx context.Context, cr *cmapi.CertificateRequest, cert *x509.Certificate) {
+	log := logf.FromContext(ctx)
+
+	now := metav1.NewTime(c.clock.Now())
+	record := &cmapi.IssuanceRecord{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name,
+			Namespace: cr.Namespace,
+		},
+		Spec: cmapi.IssuanceRecordSpec{
+			CertificateRequestName: cr.Name,
+			CertificateName:        cr.Annotations[cmapi.CertificateNameKey],
+			IssuerRef:              cr.Spec.IssuerRef,
+			SerialNumber:           cert.SerialNumber.Text(16),
+			CommonName:             cert.Subject.CommonName,
+			DNSNames:               cert.DNSNames,
+			IPAddresses:            pki.IPAddressesToString(cert.IPAddresses),
+			URIs:                   pki.URLsToString(cert.URIs),
+			EmailAddresses:         cert.EmailAddresses,
+			NotBefore:              &metav1.Time{Time: cert.NotBefore},
+			NotAfter:               &metav1.Time{Time: cert.NotAfter},
+			IssuedAt:               &now,
+		},
+	}
+
+	_, err := c.cmClient.CertmanagerV1().IssuanceRecords(record.Namespace).Create(ctx, record, metav1.CreateOptions{})
+	if k8sErrors.IsAlreadyExists(err) {
+		log.V(logf.DebugLevel).Info("issuance record already exists for certificate request")
+		return
+	}
+	if err != nil {
+		log.Error(err, "failed to create issuance record")
+		c.recorder.Eventf(cr, corev1.EventTypeWarning, "IssuanceRecordError", "Failed to create IssuanceRecord: %v", err)
+		return
+	}
+
+	log.V(logf.DebugLevel).Info("created issuance record for issued certificate")
+}
@@ -130,8 +130,56 @@ func (s *SelfSigned) Sign(ctx context.Context, cr *cmapi.CertificateRequest, iss
 		return nil, nil
 	}
 
+	// if the issuer derives keys from a seed, also derive the serial number
+	// and sign without randomness so that the same request for the same key
+	// always results in the same certificate.
+	var seed []byte
+	sign := s.signingFn
+	applyProfile := pki.ApplyCertificateProfile
+	if ref := issuerObj.GetSpec().SelfSigned.KeySeedSecretRef; ref != nil {
+		seed, err = kube.SecretKeySeed(ctx, s.secretsLister, resourceNamespace, *ref)
+		if err != nil {
+			message := fmt.Sprintf("Failed to read key seed from secret %s/%s", resourceNamespace, ref.Name)
+			s.reporter.Pending(cr, err, "ErrorGettingKeySeed", message)
+			log.Error(err, message)
+			if k8sErrors.IsNotFound(err) || cmerrors.IsInvalidData(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		sign = pki.SignCertificateDeterministically
+		applyProfile = pki.ApplyCertificateProfileDeterministically
+	}
+
+	if seed != nil {
+		template.SerialNumber, err = pki.GenerateSerialNumberFromSeed(s.issuerOptions.SerialNumberBits, seed, cr.Spec.Request)
+	} else {
+		template.SerialNumber, err = pki.GenerateSerialNumber(s.issuerOptions.SerialNumberBits)
+	}
+	if err != nil {
+		message := "Error generating certificate serial number"
+		s.reporter.Failed(cr, err, "ErrorGenerating", message)
+		log.Error(err, message)
+		return nil, nil
+	}
+
 	template.CRLDistributionPoints = issuerObj.GetSpec().SelfSigned.CRLDistributionPoints
 
+	policies, err := pki.CertificatePoliciesForRequest(cr.Spec.Request, issuerObj.GetSpec().SelfSigned.CertificatePolicies, issuerObj.GetSpec().SelfSigned.AllowedCertificatePolicies)
+	if err != nil {
+		message := "Error decoding requested certificate policies"
+		s.reporter.Failed(cr, err, "ErrorGenerating", message)
+		log.Error(err, message)
+		return nil, nil
+	}
+
+	if err := pki.AddCertificatePolicies(template, policies); err != nil {
+		message := "Error adding certificate policies"
+		s.reporter.Failed(cr, err, "ErrorGenerating", message)
+		log.Error(err, message)
+		return nil, nil
+	}
+
 	if template.Subject.String() == "" {
 		// RFC 5280 (https://tools.ietf.org/html/rfc5280#section-4.1.2.4) says that:
 		// "The issuer field MUST contain a non-empty distinguished name (DN)."
@@ -164,8 +212,15 @@ func (s *SelfSigned) Sign(ctx context.Context, cr *cmapi.CertificateRequest, iss
 		return nil, nil
 	}
 
+	if err := pki.SetKeyIdentifiers(template, nil, issuerObj.GetSpec().SelfSigned.SubjectKeyIdentifierMethod); err != nil {
+		message := "Error computing certificate key identifiers"
+		s.reporter.Failed(cr, err, "ErrorSigning", message)
+		log.Error(err, message)
+		return nil, nil
+	}
+
 	// sign and encode the certificate
-	certPem, _, err := s.signingFn(template, template, publickey, privatekey)
+	certPem, _, err := sign(template, template, publickey, privatekey)
 	if err != nil {
 		message := "Error signing certificate"
 		s.reporter.Failed(cr, err, "ErrorSigning", message)
@@ -173,6 +228,14 @@ func (s *SelfSigned) Sign(ctx context.Context, cr *cmapi.CertificateRequest, iss
 		return nil, nil
 	}
 
+	certPem, err = applyProfile(certPem, issuerObj.GetSpec().SelfSigned.CertificateProfile, nil, privatekey)
+	if err != nil {
+		message := "Error applying certificate profile"
+		s.reporter.Failed(cr, err, "ErrorSigning", message)
+		log.Error(err, message)
+		return nil, nil
+	}
+
 	log.V(logf.DebugLevel).Info("self signed certificate issued")
 
 	// We set the CA to the returned certificate here since this is self signed.
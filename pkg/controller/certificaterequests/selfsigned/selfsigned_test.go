@@ -19,6 +19,7 @@ package selfsigned
 import (
 	"context"
 	"crypto"
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/x509"
 	"crypto/x509/pkix"
@@ -26,6 +27,7 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
@@ -40,7 +42,9 @@ import (
 	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/certificaterequests"
+	crutil "github.com/jetstack/cert-manager/pkg/controller/certificaterequests/util"
 	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
 	"github.com/jetstack/cert-manager/test/unit/gen"
@@ -83,6 +87,12 @@ func TestSign(t *testing.T) {
 		}),
 	)
 
+	v1ProfileIssuer := gen.IssuerFrom(baseIssuer,
+		gen.SetIssuerSelfSigned(cmapi.SelfSignedIssuer{
+			CertificateProfile: cmapi.CertificateProfileV1,
+		}),
+	)
+
 	skRSA, err := pki.GenerateRSAPrivateKey(2048)
 	if err != nil {
 		t.Errorf("failed to generate RSA private key: %s", err)
@@ -599,6 +609,34 @@ func TestSign(t *testing.T) {
 				},
 			},
 		},
+		"should sign a v1 certificate without extensions if the issuer has the V1 certificate profile": {
+			certificateRequest: ecCR.DeepCopy(),
+			builder: &testpkg.Builder{
+				KubeObjects:        []runtime.Object{ecKeySecret},
+				CertManagerObjects: []runtime.Object{ecCR.DeepCopy(), v1ProfileIssuer},
+				ExpectedEvents: []string{
+					"Normal CertificateIssued Certificate fetched from issuer successfully",
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewCustomMatch(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						nil,
+					), func(exp, act coretesting.Action) error {
+						cr := act.(coretesting.UpdateAction).GetObject().(*cmapi.CertificateRequest)
+						cert, err := pki.DecodeX509CertificateBytes(cr.Status.Certificate)
+						if err != nil {
+							return err
+						}
+						if cert.Version != 1 || len(cert.Extensions) > 0 {
+							return fmt.Errorf("expected a v1 certificate without extensions, got version %d with %d extensions", cert.Version, len(cert.Extensions))
+						}
+						return cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature)
+					}),
+				},
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -649,3 +687,215 @@ func runTest(t *testing.T, test testT) {
 
 	test.builder.CheckAndFinish(err)
 }
+
+// TestSign_KeyAlgorithms tests that requests for each type of key are self
+// signed with that key, using the signature algorithm appropriate for it.
+func TestSign_KeyAlgorithms(t *testing.T) {
+	rsa2048, err := pki.GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsa4096, err := pki.GenerateRSAPrivateKey(4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaP256, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaP384, err := pki.GenerateECPrivateKey(pki.ECCurve384)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		key                   crypto.Signer
+		expPublicKeyAlgorithm x509.PublicKeyAlgorithm
+		expSignatureAlgorithm x509.SignatureAlgorithm
+	}{
+		"RSA 2048":   {rsa2048, x509.RSA, x509.SHA256WithRSA},
+		"RSA 4096":   {rsa4096, x509.RSA, x509.SHA512WithRSA},
+		"ECDSA P256": {ecdsaP256, x509.ECDSA, x509.ECDSAWithSHA256},
+		"ECDSA P384": {ecdsaP384, x509.ECDSA, x509.ECDSAWithSHA384},
+		"Ed25519":    {ed25519Key, x509.Ed25519, x509.PureEd25519},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			keyPEM, err := pki.EncodePKCS8PrivateKey(test.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			keySecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-key",
+					Namespace: gen.DefaultTestNamespace,
+				},
+				Data: map[string][]byte{
+					corev1.TLSPrivateKeyKey: keyPEM,
+				},
+			}
+
+			rec := &testpkg.FakeRecorder{}
+			s := &SelfSigned{
+				issuerOptions: controllerpkg.IssuerOptions{},
+				secretsLister: listersfake.FakeSecretListerFrom(listersfake.NewFakeSecretLister(),
+					listersfake.SetFakeSecretNamespaceListerGet(keySecret, nil),
+				),
+				reporter:  crutil.NewReporter(fixedClock, rec),
+				recorder:  rec,
+				signingFn: pki.SignCertificate,
+			}
+
+			cr := gen.CertificateRequest("test-cr",
+				gen.SetCertificateRequestAnnotations(map[string]string{
+					cmapi.CertificateRequestPrivateKeyAnnotationKey: keySecret.Name,
+				}),
+				gen.SetCertificateRequestCSR(generateCSR(t, test.key, x509.UnknownSignatureAlgorithm, "test")),
+			)
+			issuer := gen.Issuer("test-issuer", gen.SetIssuerSelfSigned(cmapi.SelfSignedIssuer{}))
+
+			resp, err := s.Sign(context.Background(), cr, issuer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp == nil {
+				t.Fatalf("expected the request to be signed, got events: %v", rec.Events)
+			}
+
+			cert, err := pki.DecodeX509CertificateBytes(resp.Certificate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cert.PublicKeyAlgorithm != test.expPublicKeyAlgorithm {
+				t.Errorf("unexpected public key algorithm, exp=%s got=%s", test.expPublicKeyAlgorithm, cert.PublicKeyAlgorithm)
+			}
+			if cert.SignatureAlgorithm != test.expSignatureAlgorithm {
+				t.Errorf("unexpected signature algorithm, exp=%s got=%s", test.expSignatureAlgorithm, cert.SignatureAlgorithm)
+			}
+			if err := cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature); err != nil {
+				t.Errorf("expected the certificate to be self signed: %v", err)
+			}
+		})
+	}
+}
+
+// TestSign_CertificatePolicies tests that the certificate policies configured
+// on the issuer are encoded in the certificate policies extension of the self
+// signed certificate, and that policies requested in the CSR are only
+// included if the issuer allows them.
+func TestSign_CertificatePolicies(t *testing.T) {
+	sk, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM, err := pki.EncodePKCS8PrivateKey(sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-key",
+			Namespace: gen.DefaultTestNamespace,
+		},
+		Data: map[string][]byte{
+			corev1.TLSPrivateKeyKey: keyPEM,
+		},
+	}
+
+	csrTemplate, err := pki.GenerateCSR(&cmapi.Certificate{Spec: cmapi.CertificateSpec{
+		CommonName: "test",
+		PrivateKey: &cmapi.CertificatePrivateKey{Algorithm: cmapi.ECDSAKeyAlgorithm},
+		CertificatePolicies: []cmapi.CertificatePolicy{
+			{PolicyID: "2.23.140.1.2.1", CPSURI: "https://requester.example.com/cps"},
+		},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	csrDER, err := pki.EncodeCSR(csrTemplate, sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		allowed []cmapi.CertificatePolicy
+		exp     []cmapi.CertificatePolicy
+	}{
+		"requested policy not allowed by the issuer": {
+			exp: []cmapi.CertificatePolicy{
+				{PolicyID: "0.4.0.194112.1.2", CPSURI: "https://pki.example.com/cps"},
+			},
+		},
+		"requested policy allowed by the issuer": {
+			allowed: []cmapi.CertificatePolicy{
+				{PolicyID: "2.23.140.1.2.1"},
+			},
+			exp: []cmapi.CertificatePolicy{
+				{PolicyID: "0.4.0.194112.1.2", CPSURI: "https://pki.example.com/cps"},
+				{PolicyID: "2.23.140.1.2.1"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := &testpkg.FakeRecorder{}
+			s := &SelfSigned{
+				issuerOptions: controllerpkg.IssuerOptions{},
+				secretsLister: listersfake.FakeSecretListerFrom(listersfake.NewFakeSecretLister(),
+					listersfake.SetFakeSecretNamespaceListerGet(keySecret, nil),
+				),
+				reporter:  crutil.NewReporter(fixedClock, rec),
+				recorder:  rec,
+				signingFn: pki.SignCertificate,
+			}
+
+			cr := gen.CertificateRequest("test-cr",
+				gen.SetCertificateRequestAnnotations(map[string]string{
+					cmapi.CertificateRequestPrivateKeyAnnotationKey: keySecret.Name,
+				}),
+				gen.SetCertificateRequestCSR(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})),
+			)
+			issuer := gen.Issuer("test-issuer", gen.SetIssuerSelfSigned(cmapi.SelfSignedIssuer{
+				CertificatePolicies: []cmapi.CertificatePolicy{
+					{PolicyID: "0.4.0.194112.1.2", CPSURI: "https://pki.example.com/cps"},
+				},
+				AllowedCertificatePolicies: test.allowed,
+			}))
+
+			resp, err := s.Sign(context.Background(), cr, issuer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp == nil {
+				t.Fatalf("expected the request to be signed, got events: %v", rec.Events)
+			}
+
+			cert, err := pki.DecodeX509CertificateBytes(resp.Certificate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(cert.PolicyIdentifiers) != len(test.exp) {
+				t.Fatalf("unexpected policy identifiers, exp=%v got=%v", test.exp, cert.PolicyIdentifiers)
+			}
+			for i, policy := range test.exp {
+				if cert.PolicyIdentifiers[i].String() != policy.PolicyID {
+					t.Errorf("unexpected policy identifiers, exp=%v got=%v", test.exp, cert.PolicyIdentifiers)
+				}
+			}
+
+			policies, err := pki.CertificatePoliciesFromExtensions(cert.Extensions)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(policies, test.exp) {
+				t.Errorf("unexpected certificate policies, exp=%v got=%v", test.exp, policies)
+			}
+		})
+	}
+}
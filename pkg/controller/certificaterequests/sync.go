@@ -18,8 +18,11 @@ package certificaterequests
 
 import (
 	"context"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/kr/pretty"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
@@ -39,6 +42,16 @@ var (
 	certificateRequestGvk = cmapi.SchemeGroupVersion.WithKind(cmapi.CertificateRequestKind)
 )
 
+// issuedCertificateNotBeforeTolerance is how far in the future the notBefore
+// time of an issued certificate may be before the certificate is rejected.
+// This allows for a small amount of clock skew between cert-manager and the
+// issuer.
+const issuedCertificateNotBeforeTolerance = time.Hour
+
+// issuerBusyRetryDelay is how long to wait before retrying a request whose
+// issuer already has the maximum number of requests being signed.
+const issuerBusyRetryDelay = 5 * time.Second
+
 func (c *Controller) Sync(ctx context.Context, cr *cmapi.CertificateRequest) (err error) {
 	log := logf.FromContext(ctx)
 	dbg := log.V(logf.DebugLevel)
@@ -50,9 +63,18 @@ func (c *Controller) Sync(ctx context.Context, cr *cmapi.CertificateRequest) (er
 
 	crCopy := cr.DeepCopy()
 
+	// set once the referenced issuer is known to be handled by this
+	// controller, so that only one controller records the issuance duration
+	// of each request
+	var issuerTypeMatched bool
+
 	defer func() {
 		if _, saveErr := c.updateCertificateRequestStatusAndAnnotations(ctx, cr, crCopy); saveErr != nil {
 			err = utilerrors.NewAggregate([]error{saveErr, err})
+			return
+		}
+		if issuerTypeMatched {
+			c.observeIssuanceDuration(cr, crCopy)
 		}
 	}()
 
@@ -110,6 +132,7 @@ func (c *Controller) Sync(ctx context.Context, cr *cmapi.CertificateRequest) (er
 		).V(logf.DebugLevel).Info("issuer reference type does not match controller resource kind, ignoring")
 		return nil
 	}
+	issuerTypeMatched = true
 
 	// check ready condition
 	if !apiutil.IssuerHasCondition(issuerObj, cmapi.IssuerCondition{
@@ -128,6 +151,20 @@ func (c *Controller) Sync(ctx context.Context, cr *cmapi.CertificateRequest) (er
 		return nil
 	}
 
+	// Limit the number of requests being signed by this issuer at once, so
+	// that a slow issuer cannot occupy all of the workers of this controller
+	// and delay requests to other issuers.
+	if !c.issuerBulkhead.tryAcquire(cr) {
+		dbg.Info("maximum number of concurrent requests to issuer reached, retrying later", "retry_delay", issuerBusyRetryDelay)
+		key, err := keyFunc(cr)
+		if err != nil {
+			return err
+		}
+		c.queue.AddAfter(key, issuerBusyRetryDelay)
+		return nil
+	}
+	defer c.issuerBulkhead.release(cr)
+
 	dbg.Info("invoking sign function as existing certificate does not exist")
 
 	// Attempt to call the Sign function on our issuer
@@ -144,23 +181,109 @@ func (c *Controller) Sync(ctx context.Context, cr *cmapi.CertificateRequest) (er
 		return nil
 	}
 
+	// The issuer has asked for the request to be signed again later.
+	if len(resp.Certificate) == 0 && resp.RequeueAfter > 0 {
+		dbg.Info("issuer asked for the request to be retried later", "retry_delay", resp.RequeueAfter)
+		key, err := keyFunc(cr)
+		if err != nil {
+			return err
+		}
+		c.queue.AddAfter(key, resp.RequeueAfter)
+		return nil
+	}
+
 	// Update to status with the new given response.
 	crCopy.Status.Certificate = resp.Certificate
 	crCopy.Status.CA = resp.CA
 
 	// invalid cert
-	_, err = pki.DecodeX509CertificateBytes(crCopy.Status.Certificate)
+	x509Cert, err := pki.DecodeX509CertificateBytes(crCopy.Status.Certificate)
 	if err != nil {
 		c.reporter.Failed(crCopy, err, "DecodeError", "Failed to decode returned certificate")
 		return nil
 	}
 
+	// Storing a certificate that is not currently valid would cause it to be
+	// immediately re-issued, so fail the request instead. The owning
+	// Certificate will back off before creating a new request.
+	if !c.skipIssuedCertificateValidityCheck {
+		if reason, err := c.checkIssuedCertificateValidity(x509Cert); err != nil {
+			c.reporter.Failed(crCopy, err, reason, "Issuer returned a certificate that is not currently valid")
+			return nil
+		}
+	}
+
+	// Reject certificates whose chain does not build to one of the trust
+	// anchors configured on the issuer.
+	if err := verifyIssuedCertificateChain(issuerObj, crCopy.Status.Certificate, crCopy.Status.CA, c.clock.Now()); err != nil {
+		c.reporter.Failed(crCopy, err, "ChainVerificationFailed", "Issuer returned a certificate chain that does not build to a trust anchor of the issuer")
+		return nil
+	}
+
+	if c.enableIssuanceRecords {
+		c.createIssuanceRecord(ctx, crCopy, x509Cert)
+	}
+
 	// Set condition to Ready.
 	c.reporter.Ready(crCopy)
 
 	return nil
 }
 
+// checkIssuedCertificateValidity returns an error, and the reason to report it
+// with, if the given certificate has already expired or is not valid until
+// after issuedCertificateNotBeforeTolerance from now.
+func (c *Controller) checkIssuedCertificateValidity(cert *x509.Certificate) (string, error) {
+	now := c.clock.Now()
+	if !now.Before(cert.NotAfter) {
+		return "CertificateExpired", fmt.Errorf("certificate expired at %s", cert.NotAfter.UTC().Format(time.RFC3339))
+	}
+	if cert.NotBefore.After(now.Add(issuedCertificateNotBeforeTolerance)) {
+		return "CertificateNotYetValid", fmt.Errorf("certificate is not valid until %s", cert.NotBefore.UTC().Format(time.RFC3339))
+	}
+	return "", nil
+}
+
+// verifyIssuedCertificateChain returns an error if the issuer has chain
+// validation configured and the given PEM encoded certificate chain cannot
+// be verified up to one of its trust anchors. Any certificates in the given
+// PEM encoded CA are used as intermediates, as issuers may return the
+// issuing CA separately from the chain.
+func verifyIssuedCertificateChain(issuer cmapi.GenericIssuer, chainPEM, caPEM []byte, now time.Time) error {
+	cv := issuer.GetSpec().ChainValidation
+	if cv == nil {
+		return nil
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(cv.TrustAnchors) {
+		return errors.New("no valid trust anchors are configured for chain validation")
+	}
+
+	// The validity period of the certificate is checked separately.
+	return pki.VerifyCertificateChain(chainPEM, caPEM, roots, now)
+}
+
+// observeIssuanceDuration records the time taken since the creation of the
+// CertificateRequest if it has become Issued or Failed during this sync.
+func (c *Controller) observeIssuanceDuration(old, new *cmapi.CertificateRequest) {
+	reason := apiutil.CertificateRequestReadyReason(new)
+	if reason != cmapi.CertificateRequestReasonIssued && reason != cmapi.CertificateRequestReasonFailed {
+		return
+	}
+	if reason == apiutil.CertificateRequestReadyReason(old) {
+		return
+	}
+
+	group := new.Spec.IssuerRef.Group
+	if group == "" {
+		group = certmanager.GroupName
+	}
+
+	c.metrics.ObserveCertificateRequestIssuanceDuration(c.clock.Since(new.CreationTimestamp.Time),
+		apiutil.IssuerKind(new.Spec.IssuerRef), group, c.issuerType)
+}
+
 func (c *Controller) updateCertificateRequestStatusAndAnnotations(ctx context.Context, old, new *cmapi.CertificateRequest) (*cmapi.CertificateRequest, error) {
 	log := logf.FromContext(ctx, "updateStatus")
 
@@ -142,11 +142,56 @@ func TestSync(t *testing.T) {
 	)
 
 	certRSAPEM := generateSelfSignedCert(t, baseCR, skRSA, fixedClockStart, fixedClockStart.Add(time.Hour*12))
+	certRSA, err := pki.DecodeX509CertificateBytes(certRSAPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseCRWithCertificateName := gen.CertificateRequestFrom(baseCR,
+		gen.AddCertificateRequestAnnotations(map[string]string{cmapi.CertificateNameKey: "test-cert"}),
+	)
+	issuanceRecord := &cmapi.IssuanceRecord{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      baseCR.Name,
+			Namespace: baseCR.Namespace,
+		},
+		Spec: cmapi.IssuanceRecordSpec{
+			CertificateRequestName: baseCR.Name,
+			CertificateName:        "test-cert",
+			IssuerRef:              baseCR.Spec.IssuerRef,
+			SerialNumber:           certRSA.SerialNumber.Text(16),
+			CommonName:             "test",
+			NotBefore:              &metav1.Time{Time: certRSA.NotBefore},
+			NotAfter:               &metav1.Time{Time: certRSA.NotAfter},
+			IssuedAt:               &nowMetaTime,
+		},
+	}
+
 	certRSAPEMExpired := generateSelfSignedCert(t, baseCR, skRSA, fixedClockStart.Add(-time.Hour*13), fixedClockStart.Add(-time.Hour*12))
 
 	certECPEM := generateSelfSignedCert(t, baseCR, skEC, fixedClockStart, fixedClockStart.Add(time.Hour*12))
 	certECPEMExpired := generateSelfSignedCert(t, baseCR, skEC, fixedClockStart.Add(-time.Hour*13), fixedClockStart.Add(-time.Hour*12))
 
+	certRSAPEMNotYetValid := generateSelfSignedCert(t, baseCR, skRSA, fixedClockStart.Add(time.Hour*2), fixedClockStart.Add(time.Hour*12))
+
+	expiredMessage := "certificate expired at " + fixedClockStart.Add(-time.Hour*12).UTC().Truncate(time.Second).Format(time.RFC3339)
+	notYetValidMessage := "certificate is not valid until " + fixedClockStart.Add(time.Hour*2).UTC().Truncate(time.Second).Format(time.RFC3339)
+
+	rootCA, rootCAKey := generateCACert(t, "root", nil, nil)
+	intermediateCA, intermediateCAKey := generateCACert(t, "intermediate", rootCA, rootCAKey)
+	otherRootCA, _ := generateCACert(t, "other-root", nil, nil)
+	certChainPEM := append(generateCertSignedBy(t, baseCR, intermediateCA, intermediateCAKey), encodeCerts(intermediateCA)...)
+	issuerTrustingRoot := gen.IssuerFrom(baseIssuer,
+		gen.SetIssuerChainValidation(cmapi.IssuerChainValidation{TrustAnchors: encodeCerts(rootCA)}),
+	)
+	issuerTrustingOtherRoot := gen.IssuerFrom(baseIssuer,
+		gen.SetIssuerChainValidation(cmapi.IssuerChainValidation{TrustAnchors: encodeCerts(otherRootCA)}),
+	)
+	chainErr := verifyIssuedCertificateChain(issuerTrustingOtherRoot, certChainPEM, nil, fixedClockStart)
+	if chainErr == nil {
+		t.Fatal("expected the certificate chain to not build to the other root")
+	}
+	chainErrMessage := "Issuer returned a certificate chain that does not build to a trust anchor of the issuer: " + chainErr.Error()
+
 	tests := map[string]testT{
 		"should return nil (no action) if group name if not 'cert-manager.io' or ''": {
 			certificateRequest: gen.CertificateRequestFrom(baseCR,
@@ -540,6 +585,20 @@ func TestSync(t *testing.T) {
 			},
 			expectedErr: false,
 		},
+		"if calling sign returns a response asking to retry later then we should return nil with no-op": {
+			certificateRequest: gen.CertificateRequestFrom(baseCR),
+			issuerImpl: &fake.Issuer{
+				FakeSign: func(context.Context, *cmapi.CertificateRequest, cmapi.GenericIssuer) (*issuer.IssueResponse, error) {
+					return &issuer.IssueResponse{RequeueAfter: time.Minute}, nil
+				},
+			},
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{baseCR, baseIssuer},
+				ExpectedEvents:     []string{},
+				ExpectedActions:    []testpkg.Action{},
+			},
+			expectedErr: false,
+		},
 		"if calling sign returns a response but the certificate is badly formed then we fail": {
 			certificateRequest: baseCR.DeepCopy(),
 			issuerImpl: &fake.Issuer{
@@ -610,7 +669,7 @@ func TestSync(t *testing.T) {
 				},
 			},
 		},
-		"if calling sign returns a response with an expired RSA certificate then set condition Ready": {
+		"if calling sign returns a response with an expired RSA certificate then fail": {
 			certificateRequest: baseCR.DeepCopy(),
 			issuerImpl: &fake.Issuer{
 				FakeSign: func(context.Context, *cmapi.CertificateRequest, cmapi.GenericIssuer) (*issuer.IssueResponse, error) {
@@ -622,7 +681,7 @@ func TestSync(t *testing.T) {
 			builder: &testpkg.Builder{
 				CertManagerObjects: []runtime.Object{baseIssuer, baseCR.DeepCopy()},
 				ExpectedEvents: []string{
-					"Normal CertificateIssued Certificate fetched from issuer successfully",
+					"Warning CertificateExpired Issuer returned a certificate that is not currently valid: " + expiredMessage,
 				},
 				ExpectedActions: []testpkg.Action{
 					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
@@ -631,6 +690,40 @@ func TestSync(t *testing.T) {
 						gen.DefaultTestNamespace,
 						gen.CertificateRequestFrom(baseCR,
 							gen.SetCertificateRequestCertificate(certRSAPEMExpired),
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             "Failed",
+								Message:            "Issuer returned a certificate that is not currently valid: " + expiredMessage,
+								LastTransitionTime: &nowMetaTime,
+							}),
+							gen.SetCertificateRequestFailureTime(nowMetaTime),
+						),
+					)),
+				},
+			},
+		},
+		"if calling sign returns a response with a chain that builds to a trust anchor of the issuer then set condition Ready": {
+			certificateRequest: baseCR.DeepCopy(),
+			issuerImpl: &fake.Issuer{
+				FakeSign: func(context.Context, *cmapi.CertificateRequest, cmapi.GenericIssuer) (*issuer.IssueResponse, error) {
+					return &issuer.IssueResponse{
+						Certificate: certChainPEM,
+					}, nil
+				},
+			},
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{issuerTrustingRoot, baseCR.DeepCopy()},
+				ExpectedEvents: []string{
+					"Normal CertificateIssued Certificate fetched from issuer successfully",
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCR,
+							gen.SetCertificateRequestCertificate(certChainPEM),
 							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
 								Type:               cmapi.CertificateRequestConditionReady,
 								Status:             cmmeta.ConditionTrue,
@@ -643,6 +736,40 @@ func TestSync(t *testing.T) {
 				},
 			},
 		},
+		"if calling sign returns a response with a chain to an unexpected root then fail": {
+			certificateRequest: baseCR.DeepCopy(),
+			issuerImpl: &fake.Issuer{
+				FakeSign: func(context.Context, *cmapi.CertificateRequest, cmapi.GenericIssuer) (*issuer.IssueResponse, error) {
+					return &issuer.IssueResponse{
+						Certificate: certChainPEM,
+					}, nil
+				},
+			},
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{issuerTrustingOtherRoot, baseCR.DeepCopy()},
+				ExpectedEvents: []string{
+					"Warning ChainVerificationFailed " + chainErrMessage,
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCR,
+							gen.SetCertificateRequestCertificate(certChainPEM),
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             "Failed",
+								Message:            chainErrMessage,
+								LastTransitionTime: &nowMetaTime,
+							}),
+							gen.SetCertificateRequestFailureTime(nowMetaTime),
+						),
+					)),
+				},
+			},
+		},
 		"if calling sign returns a response with a valid EC signed certificate then set condition Ready": {
 			certificateRequest: baseCR.DeepCopy(),
 			issuerImpl: &fake.Issuer{
@@ -676,7 +803,7 @@ func TestSync(t *testing.T) {
 				},
 			},
 		},
-		"if calling sign returns a response with an expired EC certificate then set condition Ready": {
+		"if calling sign returns a response with an expired EC certificate then fail": {
 			certificateRequest: baseCR.DeepCopy(),
 			issuerImpl: &fake.Issuer{
 				FakeSign: func(context.Context, *cmapi.CertificateRequest, cmapi.GenericIssuer) (*issuer.IssueResponse, error) {
@@ -688,7 +815,7 @@ func TestSync(t *testing.T) {
 			builder: &testpkg.Builder{
 				CertManagerObjects: []runtime.Object{baseIssuer, baseCR.DeepCopy()},
 				ExpectedEvents: []string{
-					"Normal CertificateIssued Certificate fetched from issuer successfully",
+					"Warning CertificateExpired Issuer returned a certificate that is not currently valid: " + expiredMessage,
 				},
 				ExpectedActions: []testpkg.Action{
 					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
@@ -697,6 +824,154 @@ func TestSync(t *testing.T) {
 						gen.DefaultTestNamespace,
 						gen.CertificateRequestFrom(baseCR,
 							gen.SetCertificateRequestCertificate(certECPEMExpired),
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             "Failed",
+								Message:            "Issuer returned a certificate that is not currently valid: " + expiredMessage,
+								LastTransitionTime: &nowMetaTime,
+							}),
+							gen.SetCertificateRequestFailureTime(nowMetaTime),
+						),
+					)),
+				},
+			},
+		},
+		"if calling sign returns a response with a certificate that is not valid until far in the future then fail": {
+			certificateRequest: baseCR.DeepCopy(),
+			issuerImpl: &fake.Issuer{
+				FakeSign: func(context.Context, *cmapi.CertificateRequest, cmapi.GenericIssuer) (*issuer.IssueResponse, error) {
+					return &issuer.IssueResponse{
+						Certificate: certRSAPEMNotYetValid,
+					}, nil
+				},
+			},
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{baseIssuer, baseCR.DeepCopy()},
+				ExpectedEvents: []string{
+					"Warning CertificateNotYetValid Issuer returned a certificate that is not currently valid: " + notYetValidMessage,
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCR,
+							gen.SetCertificateRequestCertificate(certRSAPEMNotYetValid),
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             "Failed",
+								Message:            "Issuer returned a certificate that is not currently valid: " + notYetValidMessage,
+								LastTransitionTime: &nowMetaTime,
+							}),
+							gen.SetCertificateRequestFailureTime(nowMetaTime),
+						),
+					)),
+				},
+			},
+		},
+		"if calling sign returns a response with an expired certificate and the validity check is skipped then set condition Ready": {
+			certificateRequest:                 baseCR.DeepCopy(),
+			skipIssuedCertificateValidityCheck: true,
+			issuerImpl: &fake.Issuer{
+				FakeSign: func(context.Context, *cmapi.CertificateRequest, cmapi.GenericIssuer) (*issuer.IssueResponse, error) {
+					return &issuer.IssueResponse{
+						Certificate: certRSAPEMExpired,
+					}, nil
+				},
+			},
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{baseIssuer, baseCR.DeepCopy()},
+				ExpectedEvents: []string{
+					"Normal CertificateIssued Certificate fetched from issuer successfully",
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCR,
+							gen.SetCertificateRequestCertificate(certRSAPEMExpired),
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionTrue,
+								Reason:             "Issued",
+								Message:            "Certificate fetched from issuer successfully",
+								LastTransitionTime: &nowMetaTime,
+							}),
+						),
+					)),
+				},
+			},
+		},
+
+		"if issuance records are enabled then create an IssuanceRecord for the issued certificate and set condition Ready": {
+			certificateRequest:    baseCRWithCertificateName.DeepCopy(),
+			enableIssuanceRecords: true,
+			issuerImpl: &fake.Issuer{
+				FakeSign: func(context.Context, *cmapi.CertificateRequest, cmapi.GenericIssuer) (*issuer.IssueResponse, error) {
+					return &issuer.IssueResponse{
+						Certificate: certRSAPEM,
+					}, nil
+				},
+			},
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{baseIssuer, baseCRWithCertificateName.DeepCopy()},
+				ExpectedEvents: []string{
+					"Normal CertificateIssued Certificate fetched from issuer successfully",
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewCreateAction(
+						cmapi.SchemeGroupVersion.WithResource("issuancerecords"),
+						gen.DefaultTestNamespace,
+						issuanceRecord,
+					)),
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCRWithCertificateName,
+							gen.SetCertificateRequestCertificate(certRSAPEM),
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionTrue,
+								Reason:             "Issued",
+								Message:            "Certificate fetched from issuer successfully",
+								LastTransitionTime: &nowMetaTime,
+							}),
+						),
+					)),
+				},
+			},
+		},
+		"if issuance records are enabled and the IssuanceRecord already exists then set condition Ready": {
+			certificateRequest:    baseCRWithCertificateName.DeepCopy(),
+			enableIssuanceRecords: true,
+			issuerImpl: &fake.Issuer{
+				FakeSign: func(context.Context, *cmapi.CertificateRequest, cmapi.GenericIssuer) (*issuer.IssueResponse, error) {
+					return &issuer.IssueResponse{
+						Certificate: certRSAPEM,
+					}, nil
+				},
+			},
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{baseIssuer, baseCRWithCertificateName.DeepCopy(), issuanceRecord.DeepCopy()},
+				ExpectedEvents: []string{
+					"Normal CertificateIssued Certificate fetched from issuer successfully",
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewCreateAction(
+						cmapi.SchemeGroupVersion.WithResource("issuancerecords"),
+						gen.DefaultTestNamespace,
+						issuanceRecord,
+					)),
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCRWithCertificateName,
+							gen.SetCertificateRequestCertificate(certRSAPEM),
 							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
 								Type:               cmapi.CertificateRequestConditionReady,
 								Status:             cmmeta.ConditionTrue,
@@ -719,12 +994,146 @@ func TestSync(t *testing.T) {
 	}
 }
 
+// generateCACert returns a CA certificate and its private key, signed by the
+// given parent or self-signed if parent is nil.
+func generateCACert(t *testing.T, cn string, parent *x509.Certificate, parentKey crypto.Signer) (*x509.Certificate, crypto.Signer) {
+	key, err := pki.GenerateECPrivateKey(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serialNumber, err := pki.GenerateSerialNumber(pki.DefaultSerialNumberBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             fixedClockStart.Add(-time.Hour),
+		NotAfter:              fixedClockStart.Add(time.Hour * 24),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	derBytes, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(derBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert, key
+}
+
+// generateCertSignedBy returns a PEM encoded certificate for the given
+// CertificateRequest, signed by the given CA.
+func generateCertSignedBy(t *testing.T, cr *cmapi.CertificateRequest, ca *x509.Certificate, caKey crypto.Signer) []byte {
+	template, err := pki.GenerateTemplateFromCertificateRequest(cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template.NotBefore = fixedClockStart
+	template.NotAfter = fixedClockStart.Add(time.Hour * 12)
+
+	derBytes, err := x509.CreateCertificate(rand.Reader, template, ca, template.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: derBytes})
+}
+
+func encodeCerts(certs ...*x509.Certificate) []byte {
+	var out []byte
+	for _, cert := range certs {
+		out = append(out, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+	}
+	return out
+}
+
+func TestVerifyIssuedCertificateChain(t *testing.T) {
+	sk, err := pki.GenerateECPrivateKey(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cr := gen.CertificateRequest("test-cr", gen.SetCertificateRequestCSR(generateCSR(t, sk, x509.ECDSAWithSHA256)))
+
+	root, rootKey := generateCACert(t, "root", nil, nil)
+	intermediate, intermediateKey := generateCACert(t, "intermediate", root, rootKey)
+	otherRoot, _ := generateCACert(t, "other-root", nil, nil)
+	leafPEM := generateCertSignedBy(t, cr, intermediate, intermediateKey)
+
+	tests := map[string]struct {
+		trustAnchors []byte
+		chain        []byte
+		ca           []byte
+		expectErr    bool
+	}{
+		"any chain is accepted if chain validation is not configured": {
+			chain: leafPEM,
+		},
+		"a chain that builds to a trust anchor is accepted": {
+			trustAnchors: encodeCerts(root),
+			chain:        append(leafPEM, encodeCerts(intermediate)...),
+		},
+		"a chain that builds to one of several trust anchors is accepted": {
+			trustAnchors: encodeCerts(otherRoot, root),
+			chain:        append(leafPEM, encodeCerts(intermediate, root)...),
+		},
+		"an intermediate returned as the CA is used to build the chain": {
+			trustAnchors: encodeCerts(root),
+			chain:        leafPEM,
+			ca:           encodeCerts(intermediate),
+		},
+		"a broken chain that is missing its intermediate is rejected": {
+			trustAnchors: encodeCerts(root),
+			chain:        leafPEM,
+			expectErr:    true,
+		},
+		"a chain to an unexpected root is rejected": {
+			trustAnchors: encodeCerts(otherRoot),
+			chain:        append(leafPEM, encodeCerts(intermediate, root)...),
+			expectErr:    true,
+		},
+		"a chain is rejected if no valid trust anchors are configured": {
+			trustAnchors: []byte("invalid"),
+			chain:        append(leafPEM, encodeCerts(intermediate)...),
+			expectErr:    true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			iss := gen.Issuer("test-issuer")
+			if test.trustAnchors != nil {
+				iss.Spec.ChainValidation = &cmapi.IssuerChainValidation{TrustAnchors: test.trustAnchors}
+			}
+
+			err := verifyIssuedCertificateChain(iss, test.chain, test.ca, fixedClockStart)
+			if test.expectErr != (err != nil) {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expectErr, err)
+			}
+		})
+	}
+}
+
 type testT struct {
 	builder            *testpkg.Builder
 	issuerImpl         Issuer
 	certificateRequest *cmapi.CertificateRequest
 	helper             *issuerfake.Helper
 	expectedErr        bool
+
+	skipIssuedCertificateValidityCheck bool
+	enableIssuanceRecords              bool
 }
 
 func runTest(t *testing.T, test testT) {
@@ -748,6 +1157,8 @@ func runTest(t *testing.T, test testT) {
 	if test.helper != nil {
 		c.helper = test.helper
 	}
+	c.skipIssuedCertificateValidityCheck = test.skipIssuedCertificateValidityCheck
+	c.enableIssuanceRecords = test.enableIssuanceRecords
 
 	test.builder.Start()
 
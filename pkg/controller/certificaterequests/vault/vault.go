@@ -18,9 +18,11 @@ package vault
 
 import (
 	"context"
+	"crypto/x509"
 
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/utils/clock"
 
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
@@ -30,6 +32,7 @@ import (
 	vaultinternal "github.com/jetstack/cert-manager/pkg/internal/vault"
 	"github.com/jetstack/cert-manager/pkg/issuer"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
 )
 
 const (
@@ -40,6 +43,7 @@ type Vault struct {
 	issuerOptions controllerpkg.IssuerOptions
 	secretsLister corelisters.SecretLister
 	reporter      *crutil.Reporter
+	clock         clock.Clock
 
 	vaultClientBuilder vaultinternal.VaultClientBuilder
 }
@@ -58,7 +62,8 @@ func NewVault(ctx *controllerpkg.Context) *Vault {
 		issuerOptions:      ctx.IssuerOptions,
 		secretsLister:      ctx.KubeSharedInformerFactory.Core().V1().Secrets().Lister(),
 		reporter:           crutil.NewReporter(ctx.Clock, ctx.Recorder),
-		vaultClientBuilder: vaultinternal.New,
+		clock:              ctx.Clock,
+		vaultClientBuilder: vaultinternal.NewBuilder(ctx.IssuerOptions.IssuerBackendCABundle, ctx.IssuerOptions.CanUseAmbientCredentials),
 	}
 }
 
@@ -96,6 +101,19 @@ func (v *Vault) Sign(ctx context.Context, cr *v1.CertificateRequest, issuerObj v
 		return nil, nil
 	}
 
+	if expectedRoots := issuerObj.GetSpec().Vault.ExpectedRootCA; len(expectedRoots) > 0 {
+		roots := x509.NewCertPool()
+		roots.AppendCertsFromPEM(expectedRoots)
+		if err := pki.VerifyCertificateChain(certPem, caPem, roots, v.clock.Now()); err != nil {
+			message := "Vault returned a certificate chain that does not build to the expected root CA"
+
+			v.reporter.Failed(cr, err, "UnexpectedRootCA", message)
+			log.Error(err, message)
+
+			return nil, nil
+		}
+	}
+
 	log.V(logf.DebugLevel).Info("certificate issued")
 
 	return &issuer.IssueResponse{
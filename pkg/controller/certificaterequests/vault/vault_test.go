@@ -148,6 +148,27 @@ func TestSign(t *testing.T) {
 		t.FailNow()
 	}
 
+	otherRootSK, err := pki.GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	otherRootCR := gen.CertificateRequestFrom(baseCR,
+		gen.SetCertificateRequestCSR(generateCSR(t, otherRootSK)),
+	)
+	otherRootPEM, err := generateSelfSignedCertFromCR(otherRootCR, otherRootSK, time.Hour*24*60)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+	otherRoots := x509.NewCertPool()
+	otherRoots.AppendCertsFromPEM(otherRootPEM)
+	unexpectedRootErr := pki.VerifyCertificateChain(rsaPEMCert, rsaPEMCert, otherRoots, fixedClockStart)
+	if unexpectedRootErr == nil {
+		t.Fatal("expected the certificate chain to not build to the other root")
+	}
+	unexpectedRootMessage := "Vault returned a certificate chain that does not build to the expected root CA: " + unexpectedRootErr.Error()
+
 	tokenSecret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: gen.DefaultTestNamespace,
@@ -497,6 +518,88 @@ func TestSign(t *testing.T) {
 			},
 			fakeVault: fakevault.New().WithSign(rsaPEMCert, rsaPEMCert, nil),
 		},
+
+		"a client that returns a chain to an unexpected root CA should report fail": {
+			certificateRequest: baseCR.DeepCopy(),
+			builder: &testpkg.Builder{
+				KubeObjects: []runtime.Object{tokenSecret},
+				CertManagerObjects: []runtime.Object{baseCR.DeepCopy(), gen.IssuerFrom(baseIssuer,
+					gen.SetIssuerVault(cmapi.VaultIssuer{
+						Auth: cmapi.VaultAuth{
+							TokenSecretRef: &cmmeta.SecretKeySelector{
+								Key: "my-token-key",
+								LocalObjectReference: cmmeta.LocalObjectReference{
+									Name: "token-secret",
+								},
+							},
+						},
+						ExpectedRootCA: otherRootPEM,
+					}),
+				)},
+				ExpectedEvents: []string{
+					"Warning UnexpectedRootCA " + unexpectedRootMessage,
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCR,
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             cmapi.CertificateRequestReasonFailed,
+								Message:            unexpectedRootMessage,
+								LastTransitionTime: &metaFixedClockStart,
+							}),
+							gen.SetCertificateRequestFailureTime(metaFixedClockStart),
+						),
+					)),
+				},
+			},
+			fakeVault: fakevault.New().WithSign(rsaPEMCert, rsaPEMCert, nil),
+		},
+		"a client that returns a chain to the expected root CA should return certificate": {
+			certificateRequest: baseCR,
+			builder: &testpkg.Builder{
+				KubeObjects: []runtime.Object{tokenSecret},
+				CertManagerObjects: []runtime.Object{baseCR.DeepCopy(), gen.IssuerFrom(baseIssuer,
+					gen.SetIssuerVault(cmapi.VaultIssuer{
+						Auth: cmapi.VaultAuth{
+							TokenSecretRef: &cmmeta.SecretKeySelector{
+								Key: "my-token-key",
+								LocalObjectReference: cmmeta.LocalObjectReference{
+									Name: "token-secret",
+								},
+							},
+						},
+						ExpectedRootCA: append(otherRootPEM, rsaPEMCert...),
+					}),
+				)},
+				ExpectedEvents: []string{
+					"Normal CertificateIssued Certificate fetched from issuer successfully",
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(baseCR,
+							gen.SetCertificateRequestCertificate(rsaPEMCert),
+							gen.SetCertificateRequestCA(rsaPEMCert),
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionTrue,
+								Reason:             cmapi.CertificateRequestReasonIssued,
+								Message:            "Certificate fetched from issuer successfully",
+								LastTransitionTime: &metaFixedClockStart,
+							}),
+						),
+					)),
+				},
+			},
+			fakeVault: fakevault.New().WithSign(rsaPEMCert, rsaPEMCert, nil),
+		},
 	}
 
 	for name, test := range tests {
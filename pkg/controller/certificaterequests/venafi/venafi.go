@@ -19,8 +19,10 @@ package venafi
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
+	"golang.org/x/time/rate"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
@@ -37,6 +39,7 @@ import (
 	venaficlient "github.com/jetstack/cert-manager/pkg/issuer/venafi/client"
 	"github.com/jetstack/cert-manager/pkg/issuer/venafi/client/api"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	cmerrors "github.com/jetstack/cert-manager/pkg/util/errors"
 	utilpki "github.com/jetstack/cert-manager/pkg/util/pki"
 )
 
@@ -44,6 +47,12 @@ const (
 	CRControllerName = "certificaterequests-issuer-venafi"
 )
 
+// errRateLimited is reported on CertificateRequests that are delayed by the
+// rate limiter.
+var errRateLimited = errors.New("the rate limit for requests to Venafi has been reached")
+
+const rateLimitedMessage = "Too many requests are being made to Venafi, the request will be retried"
+
 type Venafi struct {
 	issuerOptions controllerpkg.IssuerOptions
 	secretsLister corelisters.SecretLister
@@ -51,6 +60,11 @@ type Venafi struct {
 	cmClient      clientset.Interface
 
 	clientBuilder venaficlient.VenafiClientBuilder
+
+	// limiter limits the rate of requests made to Venafi by all
+	// CertificateRequests signed by this controller. If nil, requests are
+	// not limited.
+	limiter *rate.Limiter
 }
 
 func init() {
@@ -67,15 +81,48 @@ func NewVenafi(ctx *controllerpkg.Context) *Venafi {
 		issuerOptions: ctx.IssuerOptions,
 		secretsLister: ctx.KubeSharedInformerFactory.Core().V1().Secrets().Lister(),
 		reporter:      crutil.NewReporter(ctx.Clock, ctx.Recorder),
-		clientBuilder: venaficlient.New,
+		clientBuilder: venaficlient.NewBuilder(ctx.IssuerOptions.IssuerBackendCABundle, ctx.Client),
 		cmClient:      ctx.CMClient,
+		limiter:       newLimiter(ctx.IssuerOptions.VenafiRequestsPerSecond, ctx.IssuerOptions.VenafiRequestsBurst),
+	}
+}
+
+// newLimiter returns a rate limiter allowing the given number of requests per
+// second, with bursts of up to the given size, or nil if requestsPerSecond is
+// zero.
+func newLimiter(requestsPerSecond float64, burst int) *rate.Limiter {
+	if requestsPerSecond <= 0 {
+		return nil
 	}
+	return rate.NewLimiter(rate.Limit(requestsPerSecond), burst)
 }
 
 func (v *Venafi) Sign(ctx context.Context, cr *cmapi.CertificateRequest, issuerObj cmapi.GenericIssuer) (*issuerpkg.IssueResponse, error) {
 	log := logf.FromContext(ctx, "sign")
 	log = logf.WithRelatedResource(log, issuerObj)
 
+	// Building the client may authenticate with Venafi, so the rate limit
+	// is checked before any requests are made. A rate limited request is
+	// retried once the limiter allows it, rather than backing off as if it
+	// had failed.
+	if v.limiter != nil {
+		reservation := v.limiter.Reserve()
+		if delay := reservation.Delay(); delay > 0 {
+			// Give the token back, as it is reserved again when the request
+			// is retried.
+			reservation.Cancel()
+
+			// Only report the request as pending the first time it is rate
+			// limited, so that retries do not update it again.
+			if !isRateLimited(cr) {
+				v.reporter.Pending(cr, errRateLimited, "RateLimited", rateLimitedMessage)
+			}
+			log.V(logf.DebugLevel).Info(rateLimitedMessage, "retry_delay", delay)
+
+			return &issuerpkg.IssueResponse{RequeueAfter: delay}, nil
+		}
+	}
+
 	client, err := v.clientBuilder(v.issuerOptions.ResourceNamespace(issuerObj), v.secretsLister, issuerObj)
 	if k8sErrors.IsNotFound(err) {
 		message := "Required secret resource not found"
@@ -107,6 +154,7 @@ func (v *Venafi) Sign(ctx context.Context, cr *cmapi.CertificateRequest, issuerO
 			return nil, nil
 		}
 	}
+	customFields = append(customFields, annotationCustomFields(cr, issuerObj.GetSpec().Venafi.CustomFieldAnnotations)...)
 
 	duration := apiutil.DefaultCertDuration(cr.Spec.Duration)
 	pickupID := cr.ObjectMeta.Annotations[cmapi.VenafiPickupIDAnnotationKey]
@@ -130,7 +178,7 @@ func (v *Venafi) Sign(ctx context.Context, cr *cmapi.CertificateRequest, issuerO
 				v.reporter.Failed(cr, err, "RequestError", message)
 				log.Error(err, message)
 
-				return nil, err
+				return nil, cmerrors.NewPermanent(err)
 			}
 		}
 
@@ -157,7 +205,7 @@ func (v *Venafi) Sign(ctx context.Context, cr *cmapi.CertificateRequest, issuerO
 			v.reporter.Failed(cr, err, "RetrieveError", message)
 			log.Error(err, message)
 
-			return nil, err
+			return nil, cmerrors.NewPermanent(err)
 		}
 	}
 
@@ -168,7 +216,7 @@ func (v *Venafi) Sign(ctx context.Context, cr *cmapi.CertificateRequest, issuerO
 		message := "Failed to parse returned certificate bundle"
 		v.reporter.Failed(cr, err, "ParseError", message)
 		log.Error(err, message)
-		return nil, err
+		return nil, cmerrors.NewPermanent(err)
 	}
 
 	return &issuerpkg.IssueResponse{
@@ -176,3 +224,30 @@ func (v *Venafi) Sign(ctx context.Context, cr *cmapi.CertificateRequest, issuerO
 		CA:          bundle.CAPEM,
 	}, nil
 }
+
+// isRateLimited returns true if the CertificateRequest has already been
+// marked as pending because of the rate limit.
+func isRateLimited(cr *cmapi.CertificateRequest) bool {
+	cond := apiutil.GetCertificateRequestCondition(cr, cmapi.CertificateRequestConditionReady)
+	return cond != nil && cond.Reason == cmapi.CertificateRequestReasonPending &&
+		cond.Message == fmt.Sprintf("%s: %v", rateLimitedMessage, errRateLimited)
+}
+
+// annotationCustomFields returns the Venafi custom fields that the issuer maps
+// from annotations on the CertificateRequest. Annotations that are not present
+// on the CertificateRequest are skipped.
+func annotationCustomFields(cr *cmapi.CertificateRequest, mappings []cmapi.VenafiCustomFieldAnnotation) []api.CustomField {
+	var customFields []api.CustomField
+	for _, m := range mappings {
+		value, ok := cr.GetAnnotations()[m.Annotation]
+		if !ok {
+			continue
+		}
+		customFields = append(customFields, api.CustomField{
+			Type:  api.CustomFieldTypePlain,
+			Name:  m.CustomField,
+			Value: value,
+		})
+	}
+	return customFields
+}
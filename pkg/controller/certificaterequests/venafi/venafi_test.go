@@ -24,11 +24,14 @@ import (
 	"crypto/x509/pkix"
 	"encoding/pem"
 	"errors"
+	"fmt"
 	"math/big"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/Venafi/vcert/v4/pkg/endpoint"
+	"golang.org/x/time/rate"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -152,6 +155,16 @@ func TestSign(t *testing.T) {
 		}),
 	)
 
+	tppIssuerWithCustomFieldAnnotations := gen.IssuerFrom(tppIssuer,
+		gen.SetIssuerVenafi(cmapi.VenafiIssuer{
+			TPP: tppIssuer.Spec.Venafi.TPP,
+			CustomFieldAnnotations: []cmapi.VenafiCustomFieldAnnotation{
+				{Annotation: "example.com/requestor", CustomField: "Requestor"},
+				{Annotation: "example.com/cost-centre", CustomField: "Cost Centre"},
+			},
+		}),
+	)
+
 	cloudIssuer := gen.IssuerFrom(baseIssuer,
 		gen.SetIssuerVenafi(cmapi.VenafiIssuer{
 			Cloud: &cmapi.VenafiCloud{
@@ -196,6 +209,11 @@ func TestSign(t *testing.T) {
 
 	tppCRWithCustomFields := gen.CertificateRequestFrom(tppCR, gen.SetCertificateRequestAnnotations(map[string]string{"venafi.cert-manager.io/custom-fields": `[{"name": "cert-manager-test", "value": "test ok"}]`}))
 
+	tppCRWithMappedAnnotations := gen.CertificateRequestFrom(tppCR, gen.SetCertificateRequestAnnotations(map[string]string{
+		"venafi.cert-manager.io/custom-fields": `[{"name": "cert-manager-test", "value": "test ok"}]`,
+		"example.com/requestor":                "jane@example.com",
+	}))
+
 	tppCRWithInvalidCustomFields := gen.CertificateRequestFrom(tppCR, gen.SetCertificateRequestAnnotations(map[string]string{"venafi.cert-manager.io/custom-fields": `[{"name": cert-manager-test}]`}))
 
 	tppCRWithInvalidCustomFieldType := gen.CertificateRequestFrom(tppCR, gen.SetCertificateRequestAnnotations(map[string]string{"venafi.cert-manager.io/custom-fields": `[{"name": "cert-manager-test", "value": "test ok", "type": "Bool"}]`}))
@@ -265,12 +283,32 @@ func TestSign(t *testing.T) {
 		},
 	}
 
+	clientReturnsCertIfMappedCustomFields := &internalvenafifake.Venafi{
+		RequestCertificateFn: func(csrPEM []byte, duration time.Duration, fields []api.CustomField) (string, error) {
+			expected := []api.CustomField{
+				{Name: "cert-manager-test", Value: "test ok"},
+				{Type: api.CustomFieldTypePlain, Name: "Requestor", Value: "jane@example.com"},
+			}
+			if !reflect.DeepEqual(fields, expected) {
+				return "", fmt.Errorf("unexpected custom fields: %v", fields)
+			}
+			return "test", nil
+		},
+		RetrieveCertificateFn: func(string, []byte, time.Duration, []api.CustomField) ([]byte, error) {
+			return append(certPEM, rootPEM...), nil
+		},
+	}
+
 	clientReturnsInvalidCustomFieldType := &internalvenafifake.Venafi{
 		RequestCertificateFn: func(csrPEM []byte, duration time.Duration, fields []api.CustomField) (string, error) {
 			return "", client.ErrCustomFieldsType{Type: fields[0].Type}
 		},
 	}
 
+	// exhaustedLimiter has no requests left for the next hour
+	exhaustedLimiter := rate.NewLimiter(rate.Every(time.Hour), 1)
+	exhaustedLimiter.Allow()
+
 	tests := map[string]testT{
 		"a CertificateRequest without an approved condition should do nothing": {
 			certificateRequest: baseCRNotApproved.DeepCopy(),
@@ -529,6 +567,55 @@ func TestSign(t *testing.T) {
 			fakeClient:       clientReturnsPending,
 			expectedErr:      true,
 		},
+		"tpp: if the rate limit for requests to venafi is reached then set pending and retry later": {
+			certificateRequest: tppCR.DeepCopy(),
+			builder: &controllertest.Builder{
+				KubeObjects:        []runtime.Object{tppSecret},
+				CertManagerObjects: []runtime.Object{tppCR.DeepCopy(), tppIssuer.DeepCopy()},
+				ExpectedEvents: []string{
+					"Normal RateLimited Too many requests are being made to Venafi, the request will be retried: the rate limit for requests to Venafi has been reached",
+				},
+				ExpectedActions: []controllertest.Action{
+					controllertest.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(tppCR,
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             cmapi.CertificateRequestReasonPending,
+								Message:            "Too many requests are being made to Venafi, the request will be retried: the rate limit for requests to Venafi has been reached",
+								LastTransitionTime: &metaFixedClockStart,
+							}),
+						),
+					)),
+				},
+			},
+			fakeClient:         clientReturnsPending,
+			limiter:            exhaustedLimiter,
+			skipSecondSignCall: true,
+		},
+		"tpp: if the request is already pending because of the rate limit then do not report it again": {
+			certificateRequest: gen.CertificateRequestFrom(tppCR,
+				gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+					Type:               cmapi.CertificateRequestConditionReady,
+					Status:             cmmeta.ConditionFalse,
+					Reason:             cmapi.CertificateRequestReasonPending,
+					Message:            "Too many requests are being made to Venafi, the request will be retried: the rate limit for requests to Venafi has been reached",
+					LastTransitionTime: &metaFixedClockStart,
+				}),
+			),
+			builder: &controllertest.Builder{
+				KubeObjects:        []runtime.Object{tppSecret},
+				CertManagerObjects: []runtime.Object{tppCR.DeepCopy(), tppIssuer.DeepCopy()},
+				ExpectedEvents:     []string{},
+				ExpectedActions:    []controllertest.Action{},
+			},
+			fakeClient:         clientReturnsPending,
+			limiter:            exhaustedLimiter,
+			skipSecondSignCall: true,
+		},
 		"tpp: if sign returns generic error then set pending and return error": {
 			certificateRequest: tppCR.DeepCopy(),
 			builder: &controllertest.Builder{
@@ -732,6 +819,53 @@ func TestSign(t *testing.T) {
 			fakeClient:       clientReturnsCertIfCustomField,
 			expectedErr:      false,
 		},
+		"annotations: Custom Fields mapped from annotations by the issuer": {
+			certificateRequest: tppCRWithMappedAnnotations.DeepCopy(),
+			builder: &controllertest.Builder{
+				CertManagerObjects: []runtime.Object{tppCRWithMappedAnnotations.DeepCopy(), tppIssuerWithCustomFieldAnnotations.DeepCopy()},
+				ExpectedEvents: []string{
+					"Normal IssuancePending Venafi certificate is requested",
+					"Normal CertificateIssued Certificate fetched from issuer successfully",
+				},
+				ExpectedActions: []controllertest.Action{
+					controllertest.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(tppCRWithMappedAnnotations,
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             cmapi.CertificateRequestReasonPending,
+								Message:            "Venafi certificate is requested",
+								LastTransitionTime: &metaFixedClockStart,
+							}),
+							gen.AddCertificateRequestAnnotations(map[string]string{cmapi.VenafiPickupIDAnnotationKey: "test"}),
+						),
+					)),
+					controllertest.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificaterequests"),
+						"status",
+						gen.DefaultTestNamespace,
+						gen.CertificateRequestFrom(tppCRWithMappedAnnotations,
+							gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+								Type:               cmapi.CertificateRequestConditionReady,
+								Status:             cmmeta.ConditionTrue,
+								Reason:             cmapi.CertificateRequestReasonIssued,
+								Message:            "Certificate fetched from issuer successfully",
+								LastTransitionTime: &metaFixedClockStart,
+							}),
+							gen.SetCertificateRequestCertificate(certPEM),
+							gen.SetCertificateRequestCA(rootPEM),
+							gen.AddCertificateRequestAnnotations(map[string]string{cmapi.VenafiPickupIDAnnotationKey: "test"}),
+						),
+					)),
+				},
+			},
+			fakeSecretLister: failGetSecretLister,
+			fakeClient:       clientReturnsCertIfMappedCustomFields,
+			expectedErr:      false,
+		},
 		"annotations: Error on invalid JSON in custom fields": {
 			certificateRequest: tppCRWithInvalidCustomFields.DeepCopy(),
 			builder: &controllertest.Builder{
@@ -813,6 +947,8 @@ type testT struct {
 	skipSecondSignCall bool
 
 	fakeSecretLister *testlisters.FakeSecretLister
+
+	limiter *rate.Limiter
 }
 
 func runTest(t *testing.T, test testT) {
@@ -826,6 +962,10 @@ func runTest(t *testing.T, test testT) {
 		v.secretsLister = test.fakeSecretLister
 	}
 
+	if test.limiter != nil {
+		v.limiter = test.limiter
+	}
+
 	if test.fakeClient != nil {
 		v.clientBuilder = func(namespace string, secretsLister corelisters.SecretLister,
 			issuer cmapi.GenericIssuer) (client.Interface, error) {
@@ -0,0 +1,376 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"bytes"
+	"context"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	cminformers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+const (
+	ControllerName = "certificates-canary"
+
+	reasonIssued  = "Issued"
+	reasonPending = "Pending"
+	reasonFailed  = "Failed"
+
+	reasonCanaryIssued = "CanaryIssued"
+	reasonCanaryFailed = "CanaryFailed"
+)
+
+var (
+	certificateGvk = cmapi.SchemeGroupVersion.WithKind("Certificate")
+)
+
+// controller issues a CertificateRequest from the canary issuer named by
+// the `cert-manager.io/canary-issuer-name` annotation of a Certificate, and
+// records the result in the CanaryIssued condition of the Certificate.
+// The canary CertificateRequest uses a throwaway private key and is not
+// controlled by the Certificate, so it is ignored by the controllers that
+// issue the Certificate itself and its certificate is never stored.
+type controller struct {
+	certificateLister        cmlisters.CertificateLister
+	certificateRequestLister cmlisters.CertificateRequestLister
+	client                   cmclient.Interface
+	recorder                 record.EventRecorder
+}
+
+func NewController(
+	log logr.Logger,
+	client cmclient.Interface,
+	cmFactory cminformers.SharedInformerFactory,
+	recorder record.EventRecorder,
+) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
+	// create a queue used to queue up items to be processed
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
+
+	// obtain references to all the informers used by this controller
+	certificateInformer := cmFactory.Certmanager().V1().Certificates()
+	certificateRequestInformer := cmFactory.Certmanager().V1().CertificateRequests()
+
+	certificateInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: queue})
+	// When a canary CertificateRequest changes, enqueue the Certificate it
+	// was created for.
+	certificateRequestInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: enqueueCanaryCertificate(log, queue),
+	})
+
+	// build a list of InformerSynced functions that will be returned by the Register method.
+	// the controller will only begin processing items once all of these informers have synced.
+	mustSync := []cache.InformerSynced{
+		certificateInformer.Informer().HasSynced,
+		certificateRequestInformer.Informer().HasSynced,
+	}
+
+	return &controller{
+		certificateLister:        certificateInformer.Lister(),
+		certificateRequestLister: certificateRequestInformer.Lister(),
+		client:                   client,
+		recorder:                 recorder,
+	}, queue, mustSync
+}
+
+// enqueueCanaryCertificate returns a function that enqueues the Certificate
+// named by the annotations of a canary CertificateRequest.
+func enqueueCanaryCertificate(log logr.Logger, queue workqueue.Interface) func(obj interface{}) {
+	return func(obj interface{}) {
+		req, ok := obj.(*cmapi.CertificateRequest)
+		if !ok {
+			log.Error(nil, "Non-CertificateRequest object passed to enqueueCanaryCertificate")
+			return
+		}
+		if !isCanaryRequest(req) || req.Annotations[cmapi.CertificateNameKey] == "" {
+			return
+		}
+		queue.Add(req.Namespace + "/" + req.Annotations[cmapi.CertificateNameKey])
+	}
+}
+
+func isCanaryRequest(req *cmapi.CertificateRequest) bool {
+	return req.Annotations[cmapi.CertificateRequestCanaryAnnotationKey] == "true"
+}
+
+// canaryIssuerRef returns a reference to the canary issuer of the given
+// Certificate, or nil if it does not have one.
+func canaryIssuerRef(crt *cmapi.Certificate) *cmmeta.ObjectReference {
+	name := crt.Annotations[cmapi.CanaryIssuerNameAnnotationKey]
+	if name == "" {
+		return nil
+	}
+	ref := &cmmeta.ObjectReference{
+		Name:  name,
+		Kind:  crt.Annotations[cmapi.CanaryIssuerKindAnnotationKey],
+		Group: crt.Annotations[cmapi.CanaryIssuerGroupAnnotationKey],
+	}
+	if ref.Kind == "" {
+		ref.Kind = cmapi.IssuerKind
+	}
+	if ref.Group == "" {
+		ref.Group = "cert-manager.io"
+	}
+	return ref
+}
+
+// canaryRequestName returns the name of the canary CertificateRequest of the
+// given Certificate.
+func canaryRequestName(crt *cmapi.Certificate) string {
+	return apiutil.DNSSafeShortenTo52Characters(crt.Name) + "-canary"
+}
+
+// ProcessItem ensures that a canary CertificateRequest exists for the
+// Certificate with the given key if it has a canary issuer, and updates the
+// CanaryIssued condition of the Certificate to reflect its state.
+func (c *controller) ProcessItem(ctx context.Context, key string) error {
+	log := logf.FromContext(ctx).WithValues("key", key)
+
+	ctx = logf.NewContext(ctx, log)
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		log.Error(err, "invalid resource key passed to ProcessItem")
+		return nil
+	}
+
+	crt, err := c.certificateLister.Certificates(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		log.V(logf.DebugLevel).Info("certificate not found for key")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if crt.DeletionTimestamp != nil {
+		return nil
+	}
+
+	log = logf.WithResource(log, crt)
+	ctx = logf.NewContext(ctx, log)
+
+	req, err := c.certificateRequestLister.CertificateRequests(crt.Namespace).Get(canaryRequestName(crt))
+	if apierrors.IsNotFound(err) {
+		req = nil
+	} else if err != nil {
+		return err
+	}
+	if req != nil && (!isCanaryRequest(req) || req.Annotations[cmapi.CertificateNameKey] != crt.Name) {
+		log.V(logf.DebugLevel).Info("ignoring CertificateRequest with the canary name that was not created for the certificate", "request", req.Name)
+		req = nil
+	}
+
+	ref := canaryIssuerRef(crt)
+	if ref == nil {
+		if req != nil {
+			log.V(logf.DebugLevel).Info("deleting canary CertificateRequest as the certificate no longer has a canary issuer", "request", req.Name)
+			if err := c.client.CertmanagerV1().CertificateRequests(req.Namespace).Delete(ctx, req.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+		}
+		if apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionCanaryIssued) == nil {
+			return nil
+		}
+		crt = crt.DeepCopy()
+		apiutil.RemoveCertificateCondition(crt, cmapi.CertificateConditionCanaryIssued)
+		return c.updateStatus(ctx, crt)
+	}
+
+	// The canary request is re-issued whenever it no longer matches the
+	// Certificate spec, so that the canary issuer is validated against the
+	// current configuration.
+	if req != nil {
+		spec := *crt.Spec.DeepCopy()
+		spec.IssuerRef = *ref
+		violations, err := certificates.RequestMatchesSpec(req, spec)
+		if err != nil {
+			log.Error(err, "failed to compare canary CertificateRequest to certificate spec", "request", req.Name)
+		}
+		if err != nil || len(violations) > 0 {
+			log.V(logf.InfoLevel).Info("deleting canary CertificateRequest as it does not match the certificate spec", "request", req.Name, "violations", violations)
+			if err := c.client.CertmanagerV1().CertificateRequests(req.Namespace).Delete(ctx, req.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+			// the request will be re-created once the deletion has been
+			// observed by the informer.
+			return nil
+		}
+	}
+
+	if req == nil {
+		req, err = c.createCanaryRequest(ctx, crt, *ref)
+		if err != nil {
+			return err
+		}
+		if req == nil {
+			return nil
+		}
+	}
+
+	status, reason, message := canaryResult(req)
+
+	existing := apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionCanaryIssued)
+	if existing != nil && existing.Status == status && existing.Reason == reason &&
+		existing.Message == message && existing.ObservedGeneration == crt.Generation {
+		return nil
+	}
+
+	switch {
+	case reason == reasonIssued && (existing == nil || existing.Reason != reasonIssued):
+		c.recorder.Eventf(crt, corev1.EventTypeNormal, reasonCanaryIssued, "Canary issuer %s %q issued a certificate", ref.Kind, ref.Name)
+	case reason == reasonFailed && (existing == nil || existing.Reason != reasonFailed || existing.Message != message):
+		c.recorder.Eventf(crt, corev1.EventTypeWarning, reasonCanaryFailed, "Canary issuer %s %q failed to issue a certificate: %s", ref.Kind, ref.Name, message)
+	}
+
+	crt = crt.DeepCopy()
+	apiutil.SetCertificateCondition(crt, crt.Generation, cmapi.CertificateConditionCanaryIssued, status, reason, message)
+	return c.updateStatus(ctx, crt)
+}
+
+// createCanaryRequest creates a CertificateRequest for the given Certificate
+// using the given canary issuer and a newly generated private key, which is
+// not stored.
+func (c *controller) createCanaryRequest(ctx context.Context, crt *cmapi.Certificate, ref cmmeta.ObjectReference) (*cmapi.CertificateRequest, error) {
+	log := logf.FromContext(ctx)
+
+	pk, err := pki.GeneratePrivateKeyForCertificate(crt)
+	if err != nil {
+		log.Error(err, "Failed to generate private key for canary CertificateRequest - will not retry")
+		return nil, nil
+	}
+	x509CSR, err := pki.GenerateCSR(crt)
+	if err != nil {
+		log.Error(err, "Failed to generate CSR for canary CertificateRequest - will not retry")
+		return nil, nil
+	}
+	csrDER, err := pki.EncodeCSR(x509CSR, pk)
+	if err != nil {
+		return nil, err
+	}
+
+	csrPEM := bytes.NewBuffer([]byte{})
+	err = pem.Encode(csrPEM, &pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})
+	if err != nil {
+		return nil, err
+	}
+
+	// The Certificate is added as a non-controlling owner so that the canary
+	// request is garbage collected along with it, without being treated as
+	// a revision of the Certificate.
+	owner := metav1.NewControllerRef(crt, certificateGvk)
+	owner.Controller = nil
+
+	req := &cmapi.CertificateRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: crt.Namespace,
+			Name:      canaryRequestName(crt),
+			Annotations: map[string]string{
+				cmapi.CertificateNameKey:                    crt.Name,
+				cmapi.CertificateRequestCanaryAnnotationKey: "true",
+			},
+			Labels:          crt.Labels,
+			OwnerReferences: []metav1.OwnerReference{*owner},
+		},
+		Spec: cmapi.CertificateRequestSpec{
+			Duration:  crt.Spec.Duration,
+			IssuerRef: ref,
+			Request:   csrPEM.Bytes(),
+			IsCA:      crt.Spec.IsCA,
+			Usages:    crt.Spec.Usages,
+		},
+	}
+
+	req, err = c.client.CertmanagerV1().CertificateRequests(req.Namespace).Create(ctx, req, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	log.V(logf.InfoLevel).Info("created canary CertificateRequest", "request", req.Name, "issuer", ref.Name)
+
+	return req, nil
+}
+
+// canaryResult returns the status, reason and message of the CanaryIssued
+// condition for the given canary CertificateRequest.
+func canaryResult(req *cmapi.CertificateRequest) (cmmeta.ConditionStatus, string, string) {
+	ready := apiutil.GetCertificateRequestCondition(req, cmapi.CertificateRequestConditionReady)
+
+	switch {
+	case ready != nil && ready.Status == cmmeta.ConditionTrue:
+		return cmmeta.ConditionTrue, reasonIssued,
+			fmt.Sprintf("Canary CertificateRequest %q has been issued", req.Name)
+	case apiutil.CertificateRequestHasInvalidRequest(req):
+		return cmmeta.ConditionFalse, reasonFailed,
+			fmt.Sprintf("Canary CertificateRequest %q is invalid: %s", req.Name, apiutil.CertificateRequestInvalidRequestMessage(req))
+	case ready != nil && (ready.Reason == cmapi.CertificateRequestReasonFailed || ready.Reason == cmapi.CertificateRequestReasonDenied):
+		return cmmeta.ConditionFalse, reasonFailed,
+			fmt.Sprintf("Canary CertificateRequest %q failed: %s", req.Name, ready.Message)
+	default:
+		return cmmeta.ConditionFalse, reasonPending,
+			fmt.Sprintf("Waiting for canary CertificateRequest %q to be issued", req.Name)
+	}
+}
+
+func (c *controller) updateStatus(ctx context.Context, crt *cmapi.Certificate) error {
+	_, err := c.client.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
+	return err
+}
+
+// controllerWrapper wraps the `controller` structure to make it implement
+// the controllerpkg.queueingController interface
+type controllerWrapper struct {
+	*controller
+}
+
+func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
+	// construct a new named logger to be reused throughout the controller
+	log := logf.FromContext(ctx.RootContext, ControllerName)
+
+	ctrl, queue, mustSync := NewController(log,
+		ctx.CMClient,
+		ctx.SharedInformerFactory,
+		ctx.Recorder,
+	)
+	c.controller = ctrl
+
+	return queue, mustSync, nil
+}
+
+func init() {
+	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		return controllerpkg.NewBuilder(ctx, ControllerName).
+			For(&controllerWrapper{}).
+			Complete()
+	})
+}
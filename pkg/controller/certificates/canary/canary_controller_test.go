@@ -0,0 +1,255 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coretesting "k8s.io/client-go/testing"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	internaltest "github.com/jetstack/cert-manager/pkg/controller/certificates/internal/test"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestProcessItem(t *testing.T) {
+	fixedNow := metav1.NewTime(time.Now())
+	fixedClock := fakeclock.NewFakeClock(fixedNow.Time)
+
+	stagingRef := cmmeta.ObjectReference{Name: "staging", Kind: cmapi.IssuerKind, Group: "cert-manager.io"}
+	baseCrt := gen.Certificate("test-cert",
+		gen.SetCertificateNamespace("testns"),
+		gen.SetCertificateSecretName("test-secret"),
+		gen.SetCertificateDNSNames("example.com"),
+		gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "production", Kind: cmapi.IssuerKind, Group: "cert-manager.io"}),
+		gen.SetCertificateGeneration(2),
+		gen.AddCertificateAnnotations(map[string]string{cmapi.CanaryIssuerNameAnnotationKey: "staging"}),
+	)
+	pk := internaltest.MustCreatePEMPrivateKey(t)
+	canaryRequest := func(crt *cmapi.Certificate, mods ...gen.CertificateRequestModifier) *cmapi.CertificateRequest {
+		return gen.CertificateRequest("test-cert-canary", append([]gen.CertificateRequestModifier{
+			gen.SetCertificateRequestNamespace("testns"),
+			gen.SetCertificateRequestIssuer(stagingRef),
+			gen.SetCertificateRequestCSR(internaltest.MustGenerateCSRImpl(t, pk, crt)),
+			gen.AddCertificateRequestAnnotations(map[string]string{
+				cmapi.CertificateNameKey:                    "test-cert",
+				cmapi.CertificateRequestCanaryAnnotationKey: "true",
+			}),
+		}, mods...)...)
+	}
+	readyCondition := func(status cmmeta.ConditionStatus, reason, message string) gen.CertificateRequestModifier {
+		return gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+			Type:    cmapi.CertificateRequestConditionReady,
+			Status:  status,
+			Reason:  reason,
+			Message: message,
+		})
+	}
+	canaryCondition := func(status cmmeta.ConditionStatus, reason, message string) cmapi.CertificateCondition {
+		return cmapi.CertificateCondition{
+			Type:               cmapi.CertificateConditionCanaryIssued,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+			LastTransitionTime: &fixedNow,
+			ObservedGeneration: 2,
+		}
+	}
+	// the CertificateRequest for the real issuance of the Certificate, which
+	// must never be modified by the canary controller.
+	realRequest := gen.CertificateRequest("test-cert-1",
+		gen.SetCertificateRequestNamespace("testns"),
+		gen.SetCertificateRequestCSR(internaltest.MustGenerateCSRImpl(t, pk, baseCrt)),
+		gen.SetCertificateRequestIssuer(baseCrt.Spec.IssuerRef),
+		gen.SetCertificateRequestRevision("1"),
+		gen.AddCertificateRequestOwnerReferences(*metav1.NewControllerRef(baseCrt, certificateGvk)),
+	)
+
+	tests := map[string]struct {
+		certificate *cmapi.Certificate
+		requests    []runtime.Object
+
+		expectedActions []testpkg.Action
+		expectedEvents  []string
+	}{
+		"set CanaryIssued to true once the canary request has been issued": {
+			certificate: baseCrt,
+			requests: []runtime.Object{
+				realRequest,
+				canaryRequest(baseCrt, readyCondition(cmmeta.ConditionTrue, cmapi.CertificateRequestReasonIssued, "issued")),
+			},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmapi.SchemeGroupVersion.WithResource("certificates"), "status", "testns",
+					gen.CertificateFrom(baseCrt, gen.SetCertificateStatusCondition(
+						canaryCondition(cmmeta.ConditionTrue, reasonIssued, `Canary CertificateRequest "test-cert-canary" has been issued`),
+					)))),
+			},
+			expectedEvents: []string{`Normal CanaryIssued Canary issuer Issuer "staging" issued a certificate`},
+		},
+		"set CanaryIssued to false if the canary request failed without affecting the real issuance": {
+			certificate: gen.CertificateFrom(baseCrt, gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+				Type: cmapi.CertificateConditionReady, Status: cmmeta.ConditionTrue, Reason: "Ready",
+			})),
+			requests: []runtime.Object{
+				realRequest,
+				canaryRequest(baseCrt, readyCondition(cmmeta.ConditionFalse, cmapi.CertificateRequestReasonFailed, "staging CA unavailable")),
+			},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmapi.SchemeGroupVersion.WithResource("certificates"), "status", "testns",
+					gen.CertificateFrom(baseCrt,
+						gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+							Type: cmapi.CertificateConditionReady, Status: cmmeta.ConditionTrue, Reason: "Ready",
+						}),
+						gen.SetCertificateStatusCondition(canaryCondition(cmmeta.ConditionFalse, reasonFailed,
+							`Canary CertificateRequest "test-cert-canary" failed: staging CA unavailable`)),
+					))),
+			},
+			expectedEvents: []string{`Warning CanaryFailed Canary issuer Issuer "staging" failed to issue a certificate: Canary CertificateRequest "test-cert-canary" failed: staging CA unavailable`},
+		},
+		"set CanaryIssued to false whilst the canary request is pending": {
+			certificate: baseCrt,
+			requests: []runtime.Object{
+				canaryRequest(baseCrt, readyCondition(cmmeta.ConditionFalse, cmapi.CertificateRequestReasonPending, "pending")),
+			},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmapi.SchemeGroupVersion.WithResource("certificates"), "status", "testns",
+					gen.CertificateFrom(baseCrt, gen.SetCertificateStatusCondition(
+						canaryCondition(cmmeta.ConditionFalse, reasonPending, `Waiting for canary CertificateRequest "test-cert-canary" to be issued`),
+					)))),
+			},
+		},
+		"do nothing if the CanaryIssued condition is up to date": {
+			certificate: gen.CertificateFrom(baseCrt, gen.SetCertificateStatusCondition(
+				canaryCondition(cmmeta.ConditionTrue, reasonIssued, `Canary CertificateRequest "test-cert-canary" has been issued`),
+			)),
+			requests: []runtime.Object{
+				canaryRequest(baseCrt, readyCondition(cmmeta.ConditionTrue, cmapi.CertificateRequestReasonIssued, "issued")),
+			},
+		},
+		"delete the canary request if it no longer matches the certificate spec": {
+			certificate: gen.CertificateFrom(baseCrt, gen.SetCertificateDNSNames("example.com", "www.example.com")),
+			requests: []runtime.Object{
+				realRequest,
+				canaryRequest(baseCrt, readyCondition(cmmeta.ConditionTrue, cmapi.CertificateRequestReasonIssued, "issued")),
+			},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewDeleteAction(cmapi.SchemeGroupVersion.WithResource("certificaterequests"), "testns", "test-cert-canary")),
+			},
+		},
+		"delete the canary request and condition once the canary annotation is removed": {
+			certificate: gen.Certificate("test-cert",
+				gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateDNSNames("example.com"),
+				gen.SetCertificateStatusCondition(canaryCondition(cmmeta.ConditionTrue, reasonIssued, "issued")),
+			),
+			requests: []runtime.Object{
+				realRequest,
+				canaryRequest(baseCrt, readyCondition(cmmeta.ConditionTrue, cmapi.CertificateRequestReasonIssued, "issued")),
+			},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewDeleteAction(cmapi.SchemeGroupVersion.WithResource("certificaterequests"), "testns", "test-cert-canary")),
+				testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmapi.SchemeGroupVersion.WithResource("certificates"), "status", "testns",
+					gen.Certificate("test-cert",
+						gen.SetCertificateNamespace("testns"),
+						gen.SetCertificateDNSNames("example.com"),
+					))),
+			},
+		},
+		"do nothing for a certificate without a canary issuer": {
+			certificate: gen.Certificate("test-cert",
+				gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateDNSNames("example.com"),
+			),
+			requests: []runtime.Object{realRequest},
+		},
+		"create a canary request for a certificate with a canary issuer": {
+			certificate: baseCrt,
+			requests:    []runtime.Object{realRequest},
+			expectedActions: []testpkg.Action{
+				testpkg.NewCustomMatch(coretesting.NewCreateAction(cmapi.SchemeGroupVersion.WithResource("certificaterequests"), "testns", nil),
+					func(exp, actual coretesting.Action) error {
+						req := actual.(coretesting.CreateAction).GetObject().(*cmapi.CertificateRequest)
+						if req.Name != "test-cert-canary" {
+							return fmt.Errorf("unexpected name %q", req.Name)
+						}
+						if req.Spec.IssuerRef != stagingRef {
+							return fmt.Errorf("unexpected issuerRef %v", req.Spec.IssuerRef)
+						}
+						if !isCanaryRequest(req) || req.Annotations[cmapi.CertificateNameKey] != "test-cert" {
+							return fmt.Errorf("unexpected annotations %v", req.Annotations)
+						}
+						if len(req.OwnerReferences) != 1 || metav1.GetControllerOf(req) != nil {
+							return fmt.Errorf("expected a single non-controlling owner reference but got %v", req.OwnerReferences)
+						}
+						return nil
+					}),
+				testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmapi.SchemeGroupVersion.WithResource("certificates"), "status", "testns",
+					gen.CertificateFrom(baseCrt, gen.SetCertificateStatusCondition(
+						canaryCondition(cmmeta.ConditionFalse, reasonPending, `Waiting for canary CertificateRequest "test-cert-canary" to be issued`),
+					)))),
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Create and initialise a new unit test builder
+			builder := &testpkg.Builder{
+				T:                  t,
+				Clock:              fixedClock,
+				CertManagerObjects: append([]runtime.Object{test.certificate}, test.requests...),
+				ExpectedActions:    test.expectedActions,
+				ExpectedEvents:     test.expectedEvents,
+			}
+			builder.Init()
+
+			// Register informers used by the controller using the registration wrapper
+			w := &controllerWrapper{}
+			_, _, err := w.Register(builder.Context)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Start the informers and begin processing updates
+			builder.Start()
+			defer builder.Stop()
+
+			key, err := controllerpkg.KeyFunc(test.certificate)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := w.controller.ProcessItem(context.Background(), key); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if err := builder.AllEventsCalled(); err != nil {
+				builder.T.Error(err)
+			}
+			if err := builder.AllActionsExecuted(); err != nil {
+				builder.T.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+// DeprecatedAPIVersions is the list of cert-manager API versions that are
+// deprecated in favour of cmapi.SchemeGroupVersion.
+var DeprecatedAPIVersions = []string{
+	"cert-manager.io/v1alpha2",
+	"cert-manager.io/v1alpha3",
+	"cert-manager.io/v1beta1",
+}
+
+// FieldDeprecation describes a Certificate field that only exists in
+// deprecated API versions, along with the field that replaces it in
+// cmapi.SchemeGroupVersion.
+type FieldDeprecation struct {
+	// Path is the path to the deprecated field, e.g. spec.keySize.
+	Path string
+	// Replacement is the path to the field that should be used instead.
+	Replacement string
+}
+
+// DeprecatedFields is the registry of deprecated Certificate fields that are
+// surfaced to users.
+var DeprecatedFields = []FieldDeprecation{
+	{Path: "spec.organization", Replacement: "spec.subject.organizations"},
+	{Path: "spec.keySize", Replacement: "spec.privateKey.size"},
+	{Path: "spec.keyAlgorithm", Replacement: "spec.privateKey.algorithm"},
+	{Path: "spec.keyEncoding", Replacement: "spec.privateKey.encoding"},
+	{Path: "spec.uriSANs", Replacement: "spec.uris"},
+	{Path: "spec.emailSANs", Replacement: "spec.emailAddresses"},
+}
+
+// DeprecationWarnings returns a human readable warning for each deprecated
+// API version or field that has been used to write the given Certificate.
+// Deprecated fields do not exist in the storage version of the resource, so
+// usage is determined from the Certificate's managed fields, which record the
+// API version and the set of fields written by each field manager.
+func DeprecationWarnings(crt *cmapi.Certificate) []string {
+	var warnings []string
+	seenVersions := make(map[string]bool)
+	seenFields := make(map[string]bool)
+	for _, entry := range crt.ManagedFields {
+		if !isDeprecatedAPIVersion(entry.APIVersion) {
+			continue
+		}
+
+		if !seenVersions[entry.APIVersion] {
+			seenVersions[entry.APIVersion] = true
+			warnings = append(warnings, fmt.Sprintf("Certificate was written using the deprecated API version %s, use %s instead",
+				entry.APIVersion, cmapi.SchemeGroupVersion.String()))
+		}
+
+		fields := managedFieldPaths(entry)
+		for _, d := range DeprecatedFields {
+			if seenFields[d.Path] || !fields[d.Path] {
+				continue
+			}
+			seenFields[d.Path] = true
+			warnings = append(warnings, fmt.Sprintf("Certificate field %s is deprecated, use %s instead", d.Path, d.Replacement))
+		}
+	}
+
+	return warnings
+}
+
+func isDeprecatedAPIVersion(apiVersion string) bool {
+	for _, v := range DeprecatedAPIVersions {
+		if v == apiVersion {
+			return true
+		}
+	}
+	return false
+}
+
+// managedFieldPaths returns the set of dot separated field paths recorded in
+// the given managed fields entry. Entries that cannot be decoded are treated
+// as managing no fields.
+func managedFieldPaths(entry metav1.ManagedFieldsEntry) map[string]bool {
+	paths := make(map[string]bool)
+	if entry.FieldsType != "FieldsV1" || entry.FieldsV1 == nil {
+		return paths
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+		return paths
+	}
+
+	collectFieldPaths("", fields, paths)
+	return paths
+}
+
+func collectFieldPaths(prefix string, fields map[string]interface{}, paths map[string]bool) {
+	for k, v := range fields {
+		// only plain field names are of interest, list items ("i:", "k:",
+		// "v:") and the "." entry are skipped.
+		if !strings.HasPrefix(k, "f:") {
+			continue
+		}
+		path := strings.TrimPrefix(k, "f:")
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+		paths[path] = true
+		if children, ok := v.(map[string]interface{}); ok {
+			collectFieldPaths(path, children, paths)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+func managedFieldsEntry(apiVersion, fields string) metav1.ManagedFieldsEntry {
+	return metav1.ManagedFieldsEntry{
+		Manager:    "kubectl",
+		Operation:  metav1.ManagedFieldsOperationUpdate,
+		APIVersion: apiVersion,
+		FieldsType: "FieldsV1",
+		FieldsV1:   &metav1.FieldsV1{Raw: []byte(fields)},
+	}
+}
+
+func TestDeprecationWarnings(t *testing.T) {
+	tests := map[string]struct {
+		managedFields []metav1.ManagedFieldsEntry
+		expected      []string
+	}{
+		"a Certificate without managed fields has no warnings": {},
+		"a Certificate written using cert-manager.io/v1 has no warnings": {
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry("cert-manager.io/v1", `{"f:spec":{"f:dnsNames":{},"f:privateKey":{"f:algorithm":{}},"f:secretName":{}}}`),
+			},
+		},
+		"a Certificate written using a deprecated API version has a warning": {
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry("cert-manager.io/v1beta1", `{"f:spec":{"f:dnsNames":{},"f:secretName":{}}}`),
+			},
+			expected: []string{
+				"Certificate was written using the deprecated API version cert-manager.io/v1beta1, use cert-manager.io/v1 instead",
+			},
+		},
+		"a Certificate setting deprecated fields has a warning for each field": {
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry("cert-manager.io/v1alpha2", `{"f:spec":{"f:keyAlgorithm":{},"f:keySize":{},"f:organization":{},"f:secretName":{}}}`),
+			},
+			expected: []string{
+				"Certificate was written using the deprecated API version cert-manager.io/v1alpha2, use cert-manager.io/v1 instead",
+				"Certificate field spec.organization is deprecated, use spec.subject.organizations instead",
+				"Certificate field spec.keySize is deprecated, use spec.privateKey.size instead",
+				"Certificate field spec.keyAlgorithm is deprecated, use spec.privateKey.algorithm instead",
+			},
+		},
+		"a deprecated field name in a nested object does not produce a warning": {
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry("cert-manager.io/v1alpha3", `{"f:metadata":{"f:labels":{"f:keySize":{}}}}`),
+			},
+			expected: []string{
+				"Certificate was written using the deprecated API version cert-manager.io/v1alpha3, use cert-manager.io/v1 instead",
+			},
+		},
+		"an entry with undecodable fields only warns about the API version": {
+			managedFields: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry("cert-manager.io/v1alpha2", `not-json`),
+			},
+			expected: []string{
+				"Certificate was written using the deprecated API version cert-manager.io/v1alpha2, use cert-manager.io/v1 instead",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			crt := &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:     "testns",
+					Name:          "test",
+					ManagedFields: test.managedFields,
+				},
+			}
+			assert.Equal(t, test.expected, DeprecationWarnings(crt))
+		})
+	}
+}
@@ -18,11 +18,16 @@ package certificates
 
 import (
 	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
@@ -67,3 +72,232 @@ func EnqueueCertificatesForResourceUsingPredicates(log logr.Logger, queue workqu
 		}
 	}
 }
+
+// EnqueueCertificatesForGenericIssuer will return a function that can be used
+// as a handler for Issuer and ClusterIssuer SharedIndexInformers. It enqueues
+// the Certificate resources that reference the given issuer in their
+// `spec.issuerRef`.
+func EnqueueCertificatesForGenericIssuer(log logr.Logger, queue workqueue.Interface, lister cmlisters.CertificateLister) func(obj interface{}) {
+	return func(obj interface{}) {
+		iss, ok := obj.(cmapi.GenericIssuer)
+		if !ok {
+			log.V(logf.ErrorLevel).Info("Non-GenericIssuer type resource passed to EnqueueCertificatesForGenericIssuer")
+			return
+		}
+
+		_, isClusterIssuer := iss.(*cmapi.ClusterIssuer)
+		var certs []*cmapi.Certificate
+		var err error
+		if isClusterIssuer {
+			certs, err = lister.List(labels.Everything())
+		} else {
+			certs, err = lister.Certificates(iss.GetObjectMeta().Namespace).List(labels.Everything())
+		}
+		if err != nil {
+			log.Error(err, "Failed listing Certificate resources")
+			return
+		}
+
+		for _, cert := range certs {
+			ref := cert.Spec.IssuerRef
+			if ref.Name != iss.GetObjectMeta().Name {
+				continue
+			}
+			if ref.Group != "" && ref.Group != certmanager.GroupName {
+				continue
+			}
+			if isClusterIssuer != (ref.Kind == cmapi.ClusterIssuerKind) {
+				continue
+			}
+			key, err := controllerpkg.KeyFunc(cert)
+			if err != nil {
+				log.Error(err, "Error determining 'key' for resource")
+				continue
+			}
+			queue.Add(key)
+		}
+	}
+}
+
+// SecretNameIndex is the name of the index added to Certificate informers by
+// AddSecretNameIndex, which indexes Certificate resources by the namespace and
+// name of the Secret named in their `spec.secretName`.
+const SecretNameIndex = "spec.secretName"
+
+// AddSecretNameIndex adds the SecretNameIndex to the given Certificate
+// informer, unless it has already been added. It must be called before the
+// informer has been started.
+func AddSecretNameIndex(informer cache.SharedIndexInformer) error {
+	if _, ok := informer.GetIndexer().GetIndexers()[SecretNameIndex]; ok {
+		return nil
+	}
+	return informer.AddIndexers(cache.Indexers{SecretNameIndex: secretNameIndexFunc})
+}
+
+func secretNameIndexFunc(obj interface{}) ([]string, error) {
+	crt, ok := obj.(*cmapi.Certificate)
+	if !ok || crt.Spec.SecretName == "" {
+		return nil, nil
+	}
+	return []string{crt.Namespace + "/" + crt.Spec.SecretName}, nil
+}
+
+// CertificatesForSecretName returns all Certificate resources in the given
+// namespace that name the given Secret in their `spec.secretName`, using an
+// indexer that has the SecretNameIndex.
+func CertificatesForSecretName(indexer cache.Indexer, namespace, secretName string) ([]*cmapi.Certificate, error) {
+	objs, err := indexer.ByIndex(SecretNameIndex, namespace+"/"+secretName)
+	if err != nil {
+		return nil, err
+	}
+	crts := make([]*cmapi.Certificate, 0, len(objs))
+	for _, obj := range objs {
+		if crt, ok := obj.(*cmapi.Certificate); ok {
+			crts = append(crts, crt)
+		}
+	}
+	return crts, nil
+}
+
+// SecretOwner returns the Certificate that owns the Secret named in the
+// `spec.secretName` of the given Certificate, using an indexer that has the
+// SecretNameIndex. If several Certificates use the same secretName, the
+// Secret is owned by the Certificate named in its
+// `cert-manager.io/certificate-name` annotation. If the Secret does not exist
+// or was not issued for any of them, the Certificate with the oldest
+// creationTimestamp owns the Secret, falling back to the lowest name for
+// Certificates created at the same time. As only the owner is issued, the
+// annotation does not change once a Certificate owns the Secret.
+func SecretOwner(indexer cache.Indexer, secretLister corelisters.SecretLister, crt *cmapi.Certificate) (*cmapi.Certificate, error) {
+	if crt.Spec.SecretName == "" {
+		return crt, nil
+	}
+
+	crts, err := CertificatesForSecretName(indexer, crt.Namespace, crt.Spec.SecretName)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := secretLister.Secrets(crt.Namespace).Get(crt.Spec.SecretName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	if secret != nil {
+		name := secret.Annotations[cmapi.CertificateNameKey]
+		if name == crt.Name {
+			return crt, nil
+		}
+		for _, other := range crts {
+			if other.Name == name {
+				return other, nil
+			}
+		}
+	}
+
+	owner := crt
+	for _, other := range crts {
+		if olderCertificate(other, owner) {
+			owner = other
+		}
+	}
+	return owner, nil
+}
+
+// olderCertificate returns true if a was created before b, or at the same
+// time as b and has a lower name.
+func olderCertificate(a, b *cmapi.Certificate) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
+
+// EnqueueCertificatesWithSameSecretName will return a function that can be
+// used as a handler for a Certificate SharedIndexInformer. It enqueues all
+// other Certificate resources with the same `spec.secretName` as the given
+// Certificate, using an indexer that has the SecretNameIndex.
+func EnqueueCertificatesWithSameSecretName(log logr.Logger, queue workqueue.Interface, indexer cache.Indexer) func(obj interface{}) {
+	return func(obj interface{}) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		crt, ok := obj.(*cmapi.Certificate)
+		if !ok {
+			log.V(logf.ErrorLevel).Info("Non-Certificate type resource passed to EnqueueCertificatesWithSameSecretName")
+			return
+		}
+		if crt.Spec.SecretName == "" {
+			return
+		}
+
+		crts, err := CertificatesForSecretName(indexer, crt.Namespace, crt.Spec.SecretName)
+		if err != nil {
+			log.Error(err, "Failed listing Certificate resources")
+			return
+		}
+
+		for _, other := range crts {
+			if other.Name == crt.Name {
+				continue
+			}
+			key, err := controllerpkg.KeyFunc(other)
+			if err != nil {
+				log.Error(err, "Error determining 'key' for resource")
+				continue
+			}
+			queue.Add(key)
+		}
+	}
+}
+
+// EnqueueCertificatesForSecretAnnotationChange returns a handler for a Secret
+// SharedIndexInformer that enqueues the Certificate resources that own a
+// Secret whenever the value of the given annotation on the Secret changes.
+// This allows tooling that manages Secrets to signal that a Certificate
+// should be reconciled.
+// A Secret is owned by the Certificate named in its
+// `cert-manager.io/certificate-name` annotation, or otherwise by any
+// Certificates that name it in their `spec.secretName`, found using an
+// indexer that has the SecretNameIndex.
+func EnqueueCertificatesForSecretAnnotationChange(log logr.Logger, queue workqueue.Interface, indexer cache.Indexer, annotation string) cache.ResourceEventHandler {
+	enqueue := func(secret metav1.Object) {
+		if name, ok := secret.GetAnnotations()[cmapi.CertificateNameKey]; ok {
+			queue.Add(secret.GetNamespace() + "/" + name)
+			return
+		}
+
+		crts, err := CertificatesForSecretName(indexer, secret.GetNamespace(), secret.GetName())
+		if err != nil {
+			log.Error(err, "Failed listing Certificate resources")
+			return
+		}
+		for _, crt := range crts {
+			key, err := controllerpkg.KeyFunc(crt)
+			if err != nil {
+				log.Error(err, "Error determining 'key' for resource")
+				continue
+			}
+			queue.Add(key)
+		}
+	}
+
+	return cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			oldSecret, ok := old.(metav1.Object)
+			if !ok {
+				log.V(logf.ErrorLevel).Info("Non-Object type resource passed to EnqueueCertificatesForSecretAnnotationChange")
+				return
+			}
+			newSecret, ok := new.(metav1.Object)
+			if !ok {
+				log.V(logf.ErrorLevel).Info("Non-Object type resource passed to EnqueueCertificatesForSecretAnnotationChange")
+				return
+			}
+			oldValue, oldOK := oldSecret.GetAnnotations()[annotation]
+			newValue, newOK := newSecret.GetAnnotations()[annotation]
+			if oldOK != newOK || oldValue != newValue {
+				enqueue(newSecret)
+			}
+		},
+	}
+}
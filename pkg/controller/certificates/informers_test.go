@@ -0,0 +1,239 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestEnqueueCertificatesForSecretAnnotationChange(t *testing.T) {
+	const annotation = "example.com/reconcile"
+
+	secret := func(annotations map[string]string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "testns",
+			Name:        "secret-1",
+			Annotations: annotations,
+		}}
+	}
+
+	tests := map[string]struct {
+		oldSecret, newSecret *corev1.Secret
+		expectedKeys         []string
+	}{
+		"enqueues the Certificates naming the Secret when the annotation is added": {
+			oldSecret:    secret(nil),
+			newSecret:    secret(map[string]string{annotation: "1"}),
+			expectedKeys: []string{"testns/cert-1", "testns/cert-2"},
+		},
+		"enqueues the Certificates naming the Secret when the annotation is changed": {
+			oldSecret:    secret(map[string]string{annotation: "1"}),
+			newSecret:    secret(map[string]string{annotation: "2"}),
+			expectedKeys: []string{"testns/cert-1", "testns/cert-2"},
+		},
+		"enqueues the Certificates naming the Secret when the annotation is removed": {
+			oldSecret:    secret(map[string]string{annotation: "1"}),
+			newSecret:    secret(nil),
+			expectedKeys: []string{"testns/cert-1", "testns/cert-2"},
+		},
+		"enqueues only the Certificate named in the certificate-name annotation": {
+			oldSecret:    secret(map[string]string{cmapi.CertificateNameKey: "cert-2"}),
+			newSecret:    secret(map[string]string{cmapi.CertificateNameKey: "cert-2", annotation: "1"}),
+			expectedKeys: []string{"testns/cert-2"},
+		},
+		"does nothing if the annotation is unchanged": {
+			oldSecret: secret(map[string]string{annotation: "1", "other": "a"}),
+			newSecret: secret(map[string]string{annotation: "1", "other": "b"}),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{SecretNameIndex: secretNameIndexFunc})
+			for _, crt := range []*cmapi.Certificate{
+				gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"), gen.SetCertificateSecretName("secret-1")),
+				gen.Certificate("cert-2", gen.SetCertificateNamespace("testns"), gen.SetCertificateSecretName("secret-1")),
+				gen.Certificate("cert-3", gen.SetCertificateNamespace("testns"), gen.SetCertificateSecretName("secret-2")),
+				gen.Certificate("cert-4", gen.SetCertificateNamespace("otherns"), gen.SetCertificateSecretName("secret-1")),
+			} {
+				if err := indexer.Add(crt); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			queue := workqueue.New()
+			defer queue.ShutDown()
+
+			handler := EnqueueCertificatesForSecretAnnotationChange(logf.Log, queue, indexer, annotation)
+			handler.OnUpdate(test.oldSecret, test.newSecret)
+
+			var gotKeys []string
+			for queue.Len() > 0 {
+				item, _ := queue.Get()
+				gotKeys = append(gotKeys, item.(string))
+				queue.Done(item)
+			}
+			assert.ElementsMatch(t, test.expectedKeys, gotKeys)
+		})
+	}
+}
+
+func TestEnqueueCertificatesForGenericIssuer(t *testing.T) {
+	tests := map[string]struct {
+		issuer       cmapi.GenericIssuer
+		expectedKeys []string
+	}{
+		"enqueues the Certificates in the same namespace referencing an Issuer": {
+			issuer:       gen.Issuer("issuer-1", gen.SetIssuerNamespace("testns")),
+			expectedKeys: []string{"testns/cert-1", "testns/cert-2"},
+		},
+		"enqueues the Certificates in all namespaces referencing a ClusterIssuer": {
+			issuer:       gen.ClusterIssuer("issuer-1"),
+			expectedKeys: []string{"testns/cert-3", "otherns/cert-5"},
+		},
+		"does nothing if no Certificates reference the issuer": {
+			issuer: gen.Issuer("issuer-2", gen.SetIssuerNamespace("testns")),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			for _, crt := range []*cmapi.Certificate{
+				gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"), gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "issuer-1"})),
+				gen.Certificate("cert-2", gen.SetCertificateNamespace("testns"), gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "issuer-1", Kind: cmapi.IssuerKind, Group: "cert-manager.io"})),
+				gen.Certificate("cert-3", gen.SetCertificateNamespace("testns"), gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "issuer-1", Kind: cmapi.ClusterIssuerKind})),
+				gen.Certificate("cert-4", gen.SetCertificateNamespace("otherns"), gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "issuer-1"})),
+				gen.Certificate("cert-5", gen.SetCertificateNamespace("otherns"), gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "issuer-1", Kind: cmapi.ClusterIssuerKind})),
+				gen.Certificate("cert-6", gen.SetCertificateNamespace("testns"), gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "issuer-1", Kind: cmapi.IssuerKind, Group: "external.example.com"})),
+			} {
+				if err := indexer.Add(crt); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			queue := workqueue.New()
+			defer queue.ShutDown()
+
+			handler := EnqueueCertificatesForGenericIssuer(logf.Log, queue, cmlisters.NewCertificateLister(indexer))
+			handler(test.issuer)
+
+			var gotKeys []string
+			for queue.Len() > 0 {
+				item, _ := queue.Get()
+				gotKeys = append(gotKeys, item.(string))
+				queue.Done(item)
+			}
+			assert.ElementsMatch(t, test.expectedKeys, gotKeys)
+		})
+	}
+}
+
+func TestSecretOwner(t *testing.T) {
+	now := time.Now()
+	older := metav1.NewTime(now.Add(-time.Hour))
+	newer := metav1.NewTime(now)
+
+	tests := map[string]struct {
+		certificates  []*cmapi.Certificate
+		secret        *corev1.Secret
+		expectedOwner string
+	}{
+		"the oldest Certificate owns the Secret": {
+			certificates: []*cmapi.Certificate{
+				gen.Certificate("cert-a", gen.SetCertificateNamespace("testns"), gen.SetCertificateSecretName("secret-1"), gen.SetCertificateCreationTimestamp(newer)),
+				gen.Certificate("cert-b", gen.SetCertificateNamespace("testns"), gen.SetCertificateSecretName("secret-1"), gen.SetCertificateCreationTimestamp(older)),
+			},
+			expectedOwner: "cert-b",
+		},
+		"the Certificate with the lowest name owns the Secret if created at the same time": {
+			certificates: []*cmapi.Certificate{
+				gen.Certificate("cert-b", gen.SetCertificateNamespace("testns"), gen.SetCertificateSecretName("secret-1"), gen.SetCertificateCreationTimestamp(older)),
+				gen.Certificate("cert-a", gen.SetCertificateNamespace("testns"), gen.SetCertificateSecretName("secret-1"), gen.SetCertificateCreationTimestamp(older)),
+			},
+			expectedOwner: "cert-a",
+		},
+		"Certificates in other namespaces do not own the Secret": {
+			certificates: []*cmapi.Certificate{
+				gen.Certificate("cert-a", gen.SetCertificateNamespace("otherns"), gen.SetCertificateSecretName("secret-1"), gen.SetCertificateCreationTimestamp(older)),
+				gen.Certificate("cert-b", gen.SetCertificateNamespace("testns"), gen.SetCertificateSecretName("secret-1"), gen.SetCertificateCreationTimestamp(newer)),
+			},
+			expectedOwner: "cert-b",
+		},
+		"the Certificate the Secret was issued for owns the Secret even if it is newer": {
+			certificates: []*cmapi.Certificate{
+				gen.Certificate("cert-a", gen.SetCertificateNamespace("testns"), gen.SetCertificateSecretName("secret-1"), gen.SetCertificateCreationTimestamp(newer)),
+				gen.Certificate("cert-b", gen.SetCertificateNamespace("testns"), gen.SetCertificateSecretName("secret-1"), gen.SetCertificateCreationTimestamp(older)),
+			},
+			secret:        gen.Secret("secret-1", gen.SetSecretNamespace("testns"), gen.SetSecretAnnotations(map[string]string{cmapi.CertificateNameKey: "cert-a"})),
+			expectedOwner: "cert-a",
+		},
+		"the oldest Certificate owns the Secret if it was issued for a Certificate that does not use it": {
+			certificates: []*cmapi.Certificate{
+				gen.Certificate("cert-a", gen.SetCertificateNamespace("testns"), gen.SetCertificateSecretName("secret-1"), gen.SetCertificateCreationTimestamp(newer)),
+				gen.Certificate("cert-b", gen.SetCertificateNamespace("testns"), gen.SetCertificateSecretName("secret-1"), gen.SetCertificateCreationTimestamp(older)),
+				gen.Certificate("cert-c", gen.SetCertificateNamespace("testns"), gen.SetCertificateSecretName("secret-2"), gen.SetCertificateCreationTimestamp(older)),
+			},
+			secret:        gen.Secret("secret-1", gen.SetSecretNamespace("testns"), gen.SetSecretAnnotations(map[string]string{cmapi.CertificateNameKey: "cert-c"})),
+			expectedOwner: "cert-b",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{SecretNameIndex: secretNameIndexFunc})
+			for _, crt := range test.certificates {
+				if err := indexer.Add(crt); err != nil {
+					t.Fatal(err)
+				}
+			}
+			secretIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if test.secret != nil {
+				if err := secretIndexer.Add(test.secret); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// Every Certificate in the namespace must agree on the owner,
+			// so that only one of them is ever issued.
+			for _, crt := range test.certificates {
+				if crt.Namespace != "testns" || crt.Spec.SecretName != "secret-1" {
+					continue
+				}
+				owner, err := SecretOwner(indexer, corelisters.NewSecretLister(secretIndexer), crt)
+				if err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t, test.expectedOwner, owner.Name, "owner computed for %q", crt.Name)
+			}
+		})
+	}
+}
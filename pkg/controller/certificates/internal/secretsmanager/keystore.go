@@ -24,13 +24,15 @@ package secretsmanager
 
 import (
 	"bytes"
-	"crypto/rand"
 	"crypto/x509"
+	"errors"
+	"fmt"
 	"time"
 
 	jks "github.com/pavel-v-chernykh/keystore-go"
 	"software.sslmate.com/src/go-pkcs12"
 
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
 )
 
@@ -46,14 +48,41 @@ const (
 	jksSecretKey = "keystore.jks"
 	// Data Entry Name in the Secret resource for JKS containing Certificate Authority
 	jksTruststoreKey = "truststore.jks"
+
+	// jksDefaultAlias is the alias of the private key entry in JKS keystores
+	// if none is configured on the Certificate.
+	jksDefaultAlias = "certificate"
 )
 
-// encodePKCS12Keystore will encode a PKCS12 keystore using the password provided.
+// pkcs12EncoderForProfile returns the encoder that produces PKCS12 files
+// encrypted with the algorithms of the given profile. An empty profile
+// defaults to LegacyRC2, which is the most widely compatible.
+func pkcs12EncoderForProfile(profile cmapi.PKCS12Profile) (pkcs12Encoder, error) {
+	switch profile {
+	case "", cmapi.LegacyRC2PKCS12Profile:
+		return legacyRC2Encoder{}, nil
+	case cmapi.LegacyDESPKCS12Profile:
+		return legacyDESEncoder, nil
+	case cmapi.Modern3DESPKCS12Profile:
+		return modern3DESEncoder, nil
+	case cmapi.AES256PKCS12Profile:
+		return aes256Encoder, nil
+	default:
+		return nil, fmt.Errorf("unknown PKCS12 profile %q", profile)
+	}
+}
+
+// encodePKCS12Keystore will encode a PKCS12 keystore using the profile and
+// password provided.
 // The key, certificate and CA data must be provided in PKCS1 or PKCS8 PEM format.
 // If the certificate data contains multiple certificates, the first will be used
 // as the keystores 'certificate' and the remaining certificates will be prepended
 // to the list of CAs in the resulting keystore.
-func encodePKCS12Keystore(password string, rawKey []byte, certPem []byte, caPem []byte) ([]byte, error) {
+func encodePKCS12Keystore(profile cmapi.PKCS12Profile, password string, rawKey []byte, certPem []byte, caPem []byte) ([]byte, error) {
+	encoder, err := pkcs12EncoderForProfile(profile)
+	if err != nil {
+		return nil, err
+	}
 	key, err := pki.DecodePrivateKeyBytes(rawKey)
 	if err != nil {
 		return nil, err
@@ -74,20 +103,39 @@ func encodePKCS12Keystore(password string, rawKey []byte, certPem []byte, caPem
 	if len(certs) > 1 {
 		cas = append(certs[1:], cas...)
 	}
-	return pkcs12.Encode(rand.Reader, key, certs[0], cas, password)
+	return encoder.Encode(key, certs[0], cas, password)
+}
+
+// pkcs12PasswordChanged returns true if the given PKCS12 keystore or
+// truststore was encrypted using a password other than the one provided.
+// It returns false if the data is empty or cannot otherwise be decoded.
+func pkcs12PasswordChanged(data []byte, password string) bool {
+	if len(data) == 0 {
+		return false
+	}
+	return errors.Is(verifyPKCS12MAC(data, password), pkcs12.ErrIncorrectPassword)
 }
 
-func encodePKCS12Truststore(password string, caPem []byte) ([]byte, error) {
+func encodePKCS12Truststore(profile cmapi.PKCS12Profile, password string, caPem []byte) ([]byte, error) {
+	encoder, err := pkcs12EncoderForProfile(profile)
+	if err != nil {
+		return nil, err
+	}
 	ca, err := pki.DecodeX509CertificateBytes(caPem)
 	if err != nil {
 		return nil, err
 	}
 
 	var cas = []*x509.Certificate{ca}
-	return pkcs12.EncodeTrustStore(rand.Reader, cas, password)
+	return encoder.EncodeTrustStore(cas, password)
 }
 
-func encodeJKSKeystore(password []byte, rawKey []byte, certPem []byte, caPem []byte) ([]byte, error) {
+// encodeJKSKeystore will encode a JKS keystore using the password provided,
+// storing the private key and certificate chain under the given alias, or
+// jksDefaultAlias if empty.
+// If includeChain is true, every certificate in the CA data is stored as a
+// trusted certificate entry, otherwise only the first one is.
+func encodeJKSKeystore(password []byte, alias string, includeChain bool, rawKey []byte, certPem []byte, caPem []byte) ([]byte, error) {
 	// encode the private key to PKCS8
 	key, err := pki.DecodePrivateKeyBytes(rawKey)
 	if err != nil {
@@ -111,8 +159,11 @@ func encodeJKSKeystore(password []byte, rawKey []byte, certPem []byte, caPem []b
 		}
 	}
 
+	if alias == "" {
+		alias = jksDefaultAlias
+	}
 	ks := jks.KeyStore{
-		"certificate": &jks.PrivateKeyEntry{
+		alias: &jks.PrivateKeyEntry{
 			Entry: jks.Entry{
 				CreationDate: time.Now(),
 			},
@@ -122,20 +173,27 @@ func encodeJKSKeystore(password []byte, rawKey []byte, certPem []byte, caPem []b
 	}
 	// add the CA certificate, if set
 	if len(caPem) > 0 {
-		ca, err := pki.DecodeX509CertificateBytes(caPem)
-		if err != nil {
+		if err := addJKSTrustedCertificates(ks, includeChain, caPem); err != nil {
 			return nil, err
 		}
+	}
 
-		ks["ca"] = &jks.TrustedCertificateEntry{
-			Entry: jks.Entry{
-				CreationDate: time.Now(),
-			},
-			Certificate: jks.Certificate{
-				Type:    "X509",
-				Content: ca.Raw,
-			},
-		}
+	buf := &bytes.Buffer{}
+	if err := jks.Encode(buf, ks, password); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeJKSTruststore will encode a JKS truststore using the password
+// provided. The truststore contains only trusted certificate entries for the
+// CA data, and never the private key.
+// If includeChain is true, every certificate in the CA data is stored,
+// otherwise only the first one is.
+func encodeJKSTruststore(password []byte, includeChain bool, caPem []byte) ([]byte, error) {
+	ks := jks.KeyStore{}
+	if err := addJKSTrustedCertificates(ks, includeChain, caPem); err != nil {
+		return nil, err
 	}
 
 	buf := &bytes.Buffer{}
@@ -145,14 +203,24 @@ func encodeJKSKeystore(password []byte, rawKey []byte, certPem []byte, caPem []b
 	return buf.Bytes(), nil
 }
 
-func encodeJKSTruststore(password []byte, caPem []byte) ([]byte, error) {
-	ca, err := pki.DecodeX509CertificateBytes(caPem)
+// addJKSTrustedCertificates adds the certificates in the CA data to the
+// keystore as trusted certificate entries, aliased `ca`, `ca-1`, `ca-2` and
+// so on. If includeChain is false, only the first certificate is added.
+func addJKSTrustedCertificates(ks jks.KeyStore, includeChain bool, caPem []byte) error {
+	cas, err := pki.DecodeX509CertificateChainBytes(caPem)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if !includeChain {
+		cas = cas[:1]
 	}
 
-	ks := jks.KeyStore{
-		"ca": &jks.TrustedCertificateEntry{
+	for i, ca := range cas {
+		caAlias := "ca"
+		if i > 0 {
+			caAlias = fmt.Sprintf("ca-%d", i)
+		}
+		ks[caAlias] = &jks.TrustedCertificateEntry{
 			Entry: jks.Entry{
 				CreationDate: time.Now(),
 			},
@@ -160,12 +228,7 @@ func encodeJKSTruststore(password []byte, caPem []byte) ([]byte, error) {
 				Type:    "X509",
 				Content: ca.Raw,
 			},
-		},
-	}
-
-	buf := &bytes.Buffer{}
-	if err := jks.Encode(buf, ks, password); err != nil {
-		return nil, err
+		}
 	}
-	return buf.Bytes(), nil
+	return nil
 }
@@ -19,13 +19,20 @@ package secretsmanager
 import (
 	"bytes"
 	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/sha256"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
 	"fmt"
 	"testing"
 
 	jks "github.com/pavel-v-chernykh/keystore-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/pbkdf2"
 	"software.sslmate.com/src/go-pkcs12"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
@@ -145,6 +152,8 @@ func mustLeafWithChain(t *testing.T) leafWithChain {
 func TestEncodeJKSKeystore(t *testing.T) {
 	tests := map[string]struct {
 		password               string
+		alias                  string
+		includeChain           bool
 		rawKey, certPEM, caPEM []byte
 		verify                 func(t *testing.T, out []byte, err error)
 	}{
@@ -216,10 +225,79 @@ func TestEncodeJKSKeystore(t *testing.T) {
 				}
 			},
 		},
+		"encode a JKS bundle with a custom alias": {
+			password: "password",
+			alias:    "example.com",
+			rawKey:   mustGeneratePrivateKey(t, cmapi.PKCS8),
+			certPEM:  mustSelfSignCertificate(t, nil),
+			verify: func(t *testing.T, out []byte, err error) {
+				if err != nil {
+					t.Errorf("expected no error but got: %v", err)
+				}
+				buf := bytes.NewBuffer(out)
+				ks, err := jks.Decode(buf, []byte("password"))
+				if err != nil {
+					t.Errorf("error decoding keystore: %v", err)
+					return
+				}
+				if _, ok := ks["example.com"].(*jks.PrivateKeyEntry); !ok {
+					t.Errorf("no private key entry found in keystore with alias %q", "example.com")
+				}
+				if ks["certificate"] != nil {
+					t.Errorf("unexpected entry found in keystore with the default alias")
+				}
+			},
+		},
+		"encode a JKS bundle with only the first ca if includeChain is false": {
+			password: "password",
+			rawKey:   mustGeneratePrivateKey(t, cmapi.PKCS8),
+			certPEM:  mustSelfSignCertificate(t, nil),
+			caPEM:    append(mustSelfSignCertificate(t, nil), mustSelfSignCertificate(t, nil)...),
+			verify: func(t *testing.T, out []byte, err error) {
+				if err != nil {
+					t.Errorf("expected no error but got: %v", err)
+				}
+				buf := bytes.NewBuffer(out)
+				ks, err := jks.Decode(buf, []byte("password"))
+				if err != nil {
+					t.Errorf("error decoding keystore: %v", err)
+					return
+				}
+				if ks["ca"] == nil {
+					t.Errorf("no ca data found in keystore")
+				}
+				if ks["ca-1"] != nil {
+					t.Errorf("unexpected chain ca data found in keystore")
+				}
+			},
+		},
+		"encode a JKS bundle with every ca if includeChain is true": {
+			password:     "password",
+			includeChain: true,
+			rawKey:       mustGeneratePrivateKey(t, cmapi.PKCS8),
+			certPEM:      mustSelfSignCertificate(t, nil),
+			caPEM:        append(mustSelfSignCertificate(t, nil), mustSelfSignCertificate(t, nil)...),
+			verify: func(t *testing.T, out []byte, err error) {
+				if err != nil {
+					t.Errorf("expected no error but got: %v", err)
+				}
+				buf := bytes.NewBuffer(out)
+				ks, err := jks.Decode(buf, []byte("password"))
+				if err != nil {
+					t.Errorf("error decoding keystore: %v", err)
+					return
+				}
+				for _, alias := range []string{"ca", "ca-1"} {
+					if _, ok := ks[alias].(*jks.TrustedCertificateEntry); !ok {
+						t.Errorf("no trusted certificate entry found in keystore with alias %q", alias)
+					}
+				}
+			},
+		},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			out, err := encodeJKSKeystore([]byte(test.password), test.rawKey, test.certPEM, test.caPEM)
+			out, err := encodeJKSKeystore([]byte(test.password), test.alias, test.includeChain, test.rawKey, test.certPEM, test.caPEM)
 			test.verify(t, out, err)
 		})
 	}
@@ -302,7 +380,7 @@ func TestEncodePKCS12Keystore(t *testing.T) {
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			out, err := encodePKCS12Keystore(test.password, test.rawKey, test.certPEM, test.caPEM)
+			out, err := encodePKCS12Keystore("", test.password, test.rawKey, test.certPEM, test.caPEM)
 			test.verify(t, out, err)
 		})
 	}
@@ -311,7 +389,7 @@ func TestEncodePKCS12Keystore(t *testing.T) {
 		var emptyCAChain []byte = nil
 
 		chain := mustLeafWithChain(t)
-		out, err := encodePKCS12Keystore(password, chain.leaf.keyPEM, chain.all.certsToPEM(), emptyCAChain)
+		out, err := encodePKCS12Keystore("", password, chain.leaf.keyPEM, chain.all.certsToPEM(), emptyCAChain)
 		require.NoError(t, err)
 
 		pkOut, certOut, caChain, err := pkcs12.DecodeChain(out, password)
@@ -330,7 +408,7 @@ func TestEncodePKCS12Keystore(t *testing.T) {
 		require.NoError(t, err)
 
 		chain := mustLeafWithChain(t)
-		out, err := encodePKCS12Keystore(password, chain.leaf.keyPEM, chain.all.certsToPEM(), caChainInPEM)
+		out, err := encodePKCS12Keystore("", password, chain.leaf.keyPEM, chain.all.certsToPEM(), caChainInPEM)
 		require.NoError(t, err)
 
 		pkOut, certOut, caChainOut, err := pkcs12.DecodeChain(out, password)
@@ -345,6 +423,313 @@ func TestEncodePKCS12Keystore(t *testing.T) {
 	})
 }
 
+var oidPBEWithSHAAnd40RC2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 6}
+
+// pkcs12Algorithms returns the MAC algorithm, certificate encryption
+// algorithm and, if the file contains a private key, the private key
+// encryption algorithm used by the given PKCS12 file.
+func pkcs12Algorithms(t *testing.T, pfxData []byte) (mac, cert, key pkix.AlgorithmIdentifier) {
+	var pfx pfxPdu
+	_, err := asn1.Unmarshal(pfxData, &pfx)
+	require.NoError(t, err)
+	mac = pfx.MacData.Mac.Algorithm
+
+	for _, ci := range pkcs12AuthSafe(t, pfx) {
+		switch {
+		case ci.ContentType.Equal(oidEncryptedDataContentType):
+			var ed encryptedData
+			_, err := asn1.Unmarshal(ci.Content.Bytes, &ed)
+			require.NoError(t, err)
+			cert = ed.EncryptedContentInfo.ContentEncryptionAlgorithm
+		case ci.ContentType.Equal(oidDataContentType):
+			for _, bag := range pkcs12SafeBags(t, ci.Content.Bytes) {
+				if !bag.ID.Equal(oidPKCS8ShroudedKeyBag) {
+					continue
+				}
+				var info encryptedPrivateKeyInfo
+				_, err := asn1.Unmarshal(bag.Value.Bytes, &info)
+				require.NoError(t, err)
+				key = info.Algorithm
+			}
+		}
+	}
+	return mac, cert, key
+}
+
+// decodePBES2PKCS12 returns the private key and certificates stored in a
+// PKCS12 file encrypted using PBES2, which the go-pkcs12 library is unable
+// to decode. The expected cipher is verified while decrypting.
+func decodePBES2PKCS12(t *testing.T, pfxData []byte, password string, expCipher asn1.ObjectIdentifier) (key crypto.PrivateKey, certs []*x509.Certificate) {
+	var pfx pfxPdu
+	_, err := asn1.Unmarshal(pfxData, &pfx)
+	require.NoError(t, err)
+
+	for _, ci := range pkcs12AuthSafe(t, pfx) {
+		var bags []safeBag
+		switch {
+		case ci.ContentType.Equal(oidEncryptedDataContentType):
+			var ed encryptedData
+			_, err := asn1.Unmarshal(ci.Content.Bytes, &ed)
+			require.NoError(t, err)
+			data := decryptPBES2(t, ed.EncryptedContentInfo.ContentEncryptionAlgorithm, password, ed.EncryptedContentInfo.EncryptedContent, expCipher)
+			_, err = asn1.Unmarshal(data, &bags)
+			require.NoError(t, err)
+		case ci.ContentType.Equal(oidDataContentType):
+			bags = pkcs12SafeBags(t, ci.Content.Bytes)
+		}
+
+		for _, bag := range bags {
+			switch {
+			case bag.ID.Equal(oidCertBag):
+				var cb certBag
+				_, err := asn1.Unmarshal(bag.Value.Bytes, &cb)
+				require.NoError(t, err)
+				cert, err := x509.ParseCertificate(cb.Data)
+				require.NoError(t, err)
+				certs = append(certs, cert)
+			case bag.ID.Equal(oidPKCS8ShroudedKeyBag):
+				var info encryptedPrivateKeyInfo
+				_, err := asn1.Unmarshal(bag.Value.Bytes, &info)
+				require.NoError(t, err)
+				der := decryptPBES2(t, info.Algorithm, password, info.EncryptedData, expCipher)
+				key, err = x509.ParsePKCS8PrivateKey(der)
+				require.NoError(t, err)
+			}
+		}
+	}
+	return key, certs
+}
+
+func pkcs12AuthSafe(t *testing.T, pfx pfxPdu) []contentInfo {
+	var authSafeData []byte
+	_, err := asn1.Unmarshal(pfx.AuthSafe.Content.Bytes, &authSafeData)
+	require.NoError(t, err)
+	var authSafe []contentInfo
+	_, err = asn1.Unmarshal(authSafeData, &authSafe)
+	require.NoError(t, err)
+	return authSafe
+}
+
+func pkcs12SafeBags(t *testing.T, content []byte) []safeBag {
+	var data []byte
+	_, err := asn1.Unmarshal(content, &data)
+	require.NoError(t, err)
+	var bags []safeBag
+	_, err = asn1.Unmarshal(data, &bags)
+	require.NoError(t, err)
+	return bags
+}
+
+// decryptPBES2 decrypts data encrypted using PBES2 with PBKDF2-HMAC-SHA-256
+// independently of the encoder, using the parameters stored in the file.
+func decryptPBES2(t *testing.T, algorithm pkix.AlgorithmIdentifier, password string, ciphertext []byte, expCipher asn1.ObjectIdentifier) []byte {
+	require.Equal(t, oidPBES2, algorithm.Algorithm)
+	var params pbes2Params
+	_, err := asn1.Unmarshal(algorithm.Parameters.FullBytes, &params)
+	require.NoError(t, err)
+	require.Equal(t, oidPBKDF2, params.KeyDerivationFunc.Algorithm)
+	var kdfParams pbkdf2Params
+	_, err = asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdfParams)
+	require.NoError(t, err)
+	require.Equal(t, oidHMACWithSHA256, kdfParams.PRF.Algorithm)
+	require.Equal(t, expCipher, params.EncryptionScheme.Algorithm)
+	var iv []byte
+	_, err = asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv)
+	require.NoError(t, err)
+
+	var block cipher.Block
+	switch {
+	case expCipher.Equal(oidDESEDE3CBC):
+		block, err = des.NewTripleDESCipher(pbkdf2.Key([]byte(password), kdfParams.Salt, kdfParams.Iterations, 24, sha256.New))
+	case expCipher.Equal(oidAES256CBC):
+		block, err = aes.NewCipher(pbkdf2.Key([]byte(password), kdfParams.Salt, kdfParams.Iterations, 32, sha256.New))
+	default:
+		t.Fatalf("unexpected cipher %v", expCipher)
+	}
+	require.NoError(t, err)
+
+	require.Equal(t, 0, len(ciphertext)%block.BlockSize(), "ciphertext is not a multiple of the block size")
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+	padLen := int(plaintext[len(plaintext)-1])
+	require.True(t, padLen > 0 && padLen <= block.BlockSize(), "invalid padding")
+	return plaintext[:len(plaintext)-padLen]
+}
+
+func TestEncodePKCS12Profiles(t *testing.T) {
+	const password = "password"
+	tests := map[string]struct {
+		profile   cmapi.PKCS12Profile
+		expMac    asn1.ObjectIdentifier
+		expCert   asn1.ObjectIdentifier
+		expKey    asn1.ObjectIdentifier
+		expCipher asn1.ObjectIdentifier
+		expErr    bool
+	}{
+		"if no profile is set, LegacyRC2 algorithms are used": {
+			profile: "",
+			expMac:  oidSHA1,
+			expCert: oidPBEWithSHAAnd40RC2,
+			expKey:  oidPBEWithSHAAnd3DES,
+		},
+		"if the LegacyRC2 profile is set, RC2 and 3DES are used": {
+			profile: cmapi.LegacyRC2PKCS12Profile,
+			expMac:  oidSHA1,
+			expCert: oidPBEWithSHAAnd40RC2,
+			expKey:  oidPBEWithSHAAnd3DES,
+		},
+		"if the LegacyDES profile is set, 3DES is used": {
+			profile: cmapi.LegacyDESPKCS12Profile,
+			expMac:  oidSHA1,
+			expCert: oidPBEWithSHAAnd3DES,
+			expKey:  oidPBEWithSHAAnd3DES,
+		},
+		"if the Modern3DES profile is set, PBES2 with 3DES and SHA-256 are used": {
+			profile:   cmapi.Modern3DESPKCS12Profile,
+			expMac:    oidSHA256,
+			expCert:   oidPBES2,
+			expKey:    oidPBES2,
+			expCipher: oidDESEDE3CBC,
+		},
+		"if the AES256 profile is set, PBES2 with AES-256 and SHA-256 are used": {
+			profile:   cmapi.AES256PKCS12Profile,
+			expMac:    oidSHA256,
+			expCert:   oidPBES2,
+			expKey:    oidPBES2,
+			expCipher: oidAES256CBC,
+		},
+		"if an unknown profile is set, an error is returned": {
+			profile: "Unknown",
+			expErr:  true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rawKey := mustGeneratePrivateKey(t, cmapi.PKCS8)
+			certPEM := mustSelfSignCertificate(t, rawKey)
+			caPEM := mustSelfSignCertificate(t, nil)
+
+			keystore, err := encodePKCS12Keystore(test.profile, password, rawKey, certPEM, caPEM)
+			if test.expErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			mac, certAlg, keyAlg := pkcs12Algorithms(t, keystore)
+			assert.Equal(t, test.expMac, mac.Algorithm, "unexpected keystore MAC algorithm")
+			assert.Equal(t, test.expCert, certAlg.Algorithm, "unexpected keystore certificate encryption algorithm")
+			assert.Equal(t, test.expKey, keyAlg.Algorithm, "unexpected keystore private key encryption algorithm")
+			assert.False(t, pkcs12PasswordChanged(keystore, password), "keystore MAC does not match the password")
+			assert.True(t, pkcs12PasswordChanged(keystore, "not-"+password), "keystore MAC matches the wrong password")
+
+			expKey, err := pki.DecodePrivateKeyBytes(rawKey)
+			require.NoError(t, err)
+			expCert, err := pki.DecodeX509CertificateBytes(certPEM)
+			require.NoError(t, err)
+			expCA, err := pki.DecodeX509CertificateBytes(caPEM)
+			require.NoError(t, err)
+
+			if test.expCipher != nil {
+				pk, certs := decodePBES2PKCS12(t, keystore, password, test.expCipher)
+				assert.Equal(t, expKey.Public(), pk.(crypto.Signer).Public(), "private key does not match")
+				assert.Equal(t, []*x509.Certificate{expCert, expCA}, certs)
+			} else {
+				pk, cert, cas, err := pkcs12.DecodeChain(keystore, password)
+				require.NoError(t, err)
+				assert.Equal(t, expKey.Public(), pk.(crypto.Signer).Public(), "private key does not match")
+				assert.Equal(t, expCert, cert)
+				assert.Equal(t, []*x509.Certificate{expCA}, cas)
+			}
+
+			truststore, err := encodePKCS12Truststore(test.profile, password, caPEM)
+			require.NoError(t, err)
+
+			mac, certAlg, _ = pkcs12Algorithms(t, truststore)
+			assert.Equal(t, test.expMac, mac.Algorithm, "unexpected truststore MAC algorithm")
+			assert.Equal(t, test.expCert, certAlg.Algorithm, "unexpected truststore certificate encryption algorithm")
+			assert.False(t, pkcs12PasswordChanged(truststore, password), "truststore MAC does not match the password")
+			assert.True(t, pkcs12PasswordChanged(truststore, "not-"+password), "truststore MAC matches the wrong password")
+
+			if test.expCipher != nil {
+				_, certs := decodePBES2PKCS12(t, truststore, password, test.expCipher)
+				assert.Equal(t, []*x509.Certificate{expCA}, certs)
+			} else {
+				certs, err := pkcs12.DecodeTrustStore(truststore, password)
+				require.NoError(t, err)
+				assert.Equal(t, []*x509.Certificate{expCA}, certs)
+			}
+		})
+	}
+}
+
+func TestEncodeJKSTruststore(t *testing.T) {
+	ca1 := mustSelfSignCertificate(t, nil)
+	ca2 := mustSelfSignCertificate(t, nil)
+	chain := append(append([]byte{}, ca1...), ca2...)
+
+	tests := map[string]struct {
+		includeChain bool
+		caPEM        []byte
+		expectedCAs  map[string][]byte
+		expectErr    bool
+	}{
+		"encode a JKS truststore for a single ca": {
+			caPEM:       ca1,
+			expectedCAs: map[string][]byte{"ca": ca1},
+		},
+		"encode a JKS truststore with only the first ca if includeChain is false": {
+			caPEM:       chain,
+			expectedCAs: map[string][]byte{"ca": ca1},
+		},
+		"encode a JKS truststore with every ca if includeChain is true": {
+			includeChain: true,
+			caPEM:        chain,
+			expectedCAs:  map[string][]byte{"ca": ca1, "ca-1": ca2},
+		},
+		"error if the ca data is invalid": {
+			caPEM:     []byte("not a certificate"),
+			expectErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := encodeJKSTruststore([]byte("password"), test.includeChain, test.caPEM)
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			_, err = jks.Decode(bytes.NewBuffer(out), []byte("wrong-password"))
+			assert.Error(t, err, "truststore should be password protected")
+
+			ts, err := jks.Decode(bytes.NewBuffer(out), []byte("password"))
+			require.NoError(t, err)
+			assert.Len(t, ts, len(test.expectedCAs))
+			for alias, entry := range ts {
+				if _, ok := entry.(*jks.PrivateKeyEntry); ok {
+					t.Errorf("unexpected private key entry %q found in truststore", alias)
+					continue
+				}
+				trusted, ok := entry.(*jks.TrustedCertificateEntry)
+				if !ok {
+					t.Errorf("unexpected entry %q of type %T found in truststore", alias, entry)
+					continue
+				}
+				caPEM, ok := test.expectedCAs[alias]
+				if !ok {
+					t.Errorf("unexpected entry %q found in truststore", alias)
+					continue
+				}
+				ca, err := pki.DecodeX509CertificateBytes(caPEM)
+				require.NoError(t, err)
+				assert.Equal(t, ca.Raw, trusted.Certificate.Content, "trusted certificate %q does not match", alias)
+			}
+		})
+	}
+}
+
 func TestEncodePKCS12Truststore(t *testing.T) {
 	tests := map[string]struct {
 		password string
@@ -377,7 +762,7 @@ func TestEncodePKCS12Truststore(t *testing.T) {
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			out, err := encodePKCS12Truststore(test.password, test.caPEM)
+			out, err := encodePKCS12Truststore("", test.password, test.caPEM)
 			test.verify(t, test.caPEM, out, err)
 		})
 	}
@@ -0,0 +1,604 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsmanager
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"hash"
+	"unicode/utf16"
+
+	"golang.org/x/crypto/pbkdf2"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// pkcs12Encoder encodes keystores and truststores in the PKCS12 format.
+type pkcs12Encoder interface {
+	Encode(key crypto.PrivateKey, cert *x509.Certificate, caCerts []*x509.Certificate, password string) ([]byte, error)
+	EncodeTrustStore(certs []*x509.Certificate, password string) ([]byte, error)
+}
+
+// legacyRC2Encoder encrypts certificates using 40-bit RC2 and private keys
+// using 3DES, authenticated with a SHA-1 MAC. This is the only format the
+// go-pkcs12 library is able to produce.
+type legacyRC2Encoder struct{}
+
+func (legacyRC2Encoder) Encode(key crypto.PrivateKey, cert *x509.Certificate, caCerts []*x509.Certificate, password string) ([]byte, error) {
+	return pkcs12.Encode(rand.Reader, key, cert, caCerts, password)
+}
+
+func (legacyRC2Encoder) EncodeTrustStore(certs []*x509.Certificate, password string) ([]byte, error) {
+	return pkcs12.EncodeTrustStore(rand.Reader, certs, password)
+}
+
+// pbeEncoder encodes PKCS12 files using the given password based encryption
+// and MAC algorithms. It produces files laid out the same way as the
+// go-pkcs12 library, so that consumers see no difference other than the
+// algorithms used. See RFC 7292.
+type pbeEncoder struct {
+	// encrypt encrypts data using a key derived from the password, returning
+	// the algorithm identifier to store alongside the ciphertext.
+	encrypt func(password string, data []byte) (pkix.AlgorithmIdentifier, []byte, error)
+
+	macAlgorithm asn1.ObjectIdentifier
+	macHash      func() hash.Hash
+}
+
+var (
+	// legacyDESEncoder encrypts both certificates and private keys using
+	// pbeWithSHAAnd3-KeyTripleDES-CBC, authenticated with a SHA-1 MAC.
+	legacyDESEncoder pkcs12Encoder = &pbeEncoder{
+		encrypt:      pbeWithSHAAnd3DESEncrypt,
+		macAlgorithm: oidSHA1,
+		macHash:      sha1.New,
+	}
+
+	// modern3DESEncoder encrypts both certificates and private keys using
+	// PBES2 with PBKDF2-HMAC-SHA-256 and 3DES, authenticated with a SHA-256
+	// MAC.
+	modern3DESEncoder pkcs12Encoder = &pbeEncoder{
+		encrypt:      pbes2Encrypter(oidDESEDE3CBC, 24, des.NewTripleDESCipher),
+		macAlgorithm: oidSHA256,
+		macHash:      sha256.New,
+	}
+
+	// aes256Encoder encrypts both certificates and private keys using PBES2
+	// with PBKDF2-HMAC-SHA-256 and AES-256-CBC, authenticated with a SHA-256
+	// MAC.
+	aes256Encoder pkcs12Encoder = &pbeEncoder{
+		encrypt:      pbes2Encrypter(oidAES256CBC, 32, aes.NewCipher),
+		macAlgorithm: oidSHA256,
+		macHash:      sha256.New,
+	}
+)
+
+// pkcs12Iterations is the number of iterations used when deriving keys from
+// the password, matching the go-pkcs12 library.
+const pkcs12Iterations = 2048
+
+var (
+	oidDataContentType          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidEncryptedDataContentType = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 6}
+
+	oidCertBag             = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 3}
+	oidPKCS8ShroudedKeyBag = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 2}
+	oidCertTypeX509        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 22, 1}
+
+	oidLocalKeyID          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 21}
+	oidJavaTrustStore      = asn1.ObjectIdentifier{2, 16, 840, 1, 113894, 746875, 1, 1}
+	oidAnyExtendedKeyUsage = asn1.ObjectIdentifier{2, 5, 29, 37, 0}
+
+	oidSHA1              = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	oidSHA256            = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidPBEWithSHAAnd3DES = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 3}
+	oidPBES2             = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2            = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA256    = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidDESEDE3CBC        = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+	oidAES256CBC         = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+type pfxPdu struct {
+	Version  int
+	AuthSafe contentInfo
+	MacData  macData `asn1:"optional"`
+}
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"tag:0,explicit,optional"`
+}
+
+type encryptedData struct {
+	Version              int
+	EncryptedContentInfo encryptedContentInfo
+}
+
+type encryptedContentInfo struct {
+	ContentType                asn1.ObjectIdentifier
+	ContentEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedContent           []byte `asn1:"tag:0,optional"`
+}
+
+type macData struct {
+	Mac        digestInfo
+	MacSalt    []byte
+	Iterations int `asn1:"optional,default:1"`
+}
+
+type digestInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	Digest    []byte
+}
+
+type safeBag struct {
+	ID         asn1.ObjectIdentifier
+	Value      asn1.RawValue     `asn1:"tag:0,explicit"`
+	Attributes []pkcs12Attribute `asn1:"set,optional"`
+}
+
+type pkcs12Attribute struct {
+	ID    asn1.ObjectIdentifier
+	Value asn1.RawValue `asn1:"set"`
+}
+
+type certBag struct {
+	ID   asn1.ObjectIdentifier
+	Data []byte `asn1:"tag:0,explicit"`
+}
+
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+type pbeParams struct {
+	Salt       []byte
+	Iterations int
+}
+
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+type pbkdf2Params struct {
+	Salt       []byte
+	Iterations int
+	PRF        pkix.AlgorithmIdentifier
+}
+
+func (e *pbeEncoder) Encode(key crypto.PrivateKey, cert *x509.Certificate, caCerts []*x509.Certificate, password string) ([]byte, error) {
+	// The localKeyId attribute pairs the certificate with its private key.
+	localKeyID := sha1.Sum(cert.Raw)
+	localKeyIDValue, err := asn1.Marshal(localKeyID[:])
+	if err != nil {
+		return nil, err
+	}
+	localKeyIDAttr := newPKCS12Attribute(oidLocalKeyID, localKeyIDValue)
+
+	certBags := make([]safeBag, 0, len(caCerts)+1)
+	bag, err := newCertBag(cert, []pkcs12Attribute{localKeyIDAttr})
+	if err != nil {
+		return nil, err
+	}
+	certBags = append(certBags, bag)
+	for _, ca := range caCerts {
+		bag, err := newCertBag(ca, nil)
+		if err != nil {
+			return nil, err
+		}
+		certBags = append(certBags, bag)
+	}
+
+	pkcs8Key, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	keyAlgorithm, encryptedKey, err := e.encrypt(password, pkcs8Key)
+	if err != nil {
+		return nil, err
+	}
+	keyInfo, err := asn1.Marshal(encryptedPrivateKeyInfo{
+		Algorithm:     keyAlgorithm,
+		EncryptedData: encryptedKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	keyBag := safeBag{
+		ID:         oidPKCS8ShroudedKeyBag,
+		Value:      explicitTag0(keyInfo),
+		Attributes: []pkcs12Attribute{localKeyIDAttr},
+	}
+
+	certsContent, err := e.encryptedContentInfo(password, certBags)
+	if err != nil {
+		return nil, err
+	}
+	keyContent, err := dataContentInfo([]safeBag{keyBag})
+	if err != nil {
+		return nil, err
+	}
+	return e.encodePFX(password, []contentInfo{certsContent, keyContent})
+}
+
+func (e *pbeEncoder) EncodeTrustStore(certs []*x509.Certificate, password string) ([]byte, error) {
+	// Java only treats certificates carrying this attribute as trusted.
+	anyEKU, err := asn1.Marshal(oidAnyExtendedKeyUsage)
+	if err != nil {
+		return nil, err
+	}
+	trustedAttr := newPKCS12Attribute(oidJavaTrustStore, anyEKU)
+
+	certBags := make([]safeBag, 0, len(certs))
+	for _, cert := range certs {
+		bag, err := newCertBag(cert, []pkcs12Attribute{trustedAttr})
+		if err != nil {
+			return nil, err
+		}
+		certBags = append(certBags, bag)
+	}
+
+	certsContent, err := e.encryptedContentInfo(password, certBags)
+	if err != nil {
+		return nil, err
+	}
+	return e.encodePFX(password, []contentInfo{certsContent})
+}
+
+// encryptedContentInfo encrypts the given bags, storing them as
+// EncryptedData.
+func (e *pbeEncoder) encryptedContentInfo(password string, bags []safeBag) (contentInfo, error) {
+	data, err := asn1.Marshal(bags)
+	if err != nil {
+		return contentInfo{}, err
+	}
+	algorithm, ciphertext, err := e.encrypt(password, data)
+	if err != nil {
+		return contentInfo{}, err
+	}
+	content, err := asn1.Marshal(encryptedData{
+		Version: 0,
+		EncryptedContentInfo: encryptedContentInfo{
+			ContentType:                oidDataContentType,
+			ContentEncryptionAlgorithm: algorithm,
+			EncryptedContent:           ciphertext,
+		},
+	})
+	if err != nil {
+		return contentInfo{}, err
+	}
+	return contentInfo{
+		ContentType: oidEncryptedDataContentType,
+		Content:     explicitTag0(content),
+	}, nil
+}
+
+// dataContentInfo stores the given bags unencrypted. This is used for
+// private keys, which are encrypted individually within their bags.
+func dataContentInfo(bags []safeBag) (contentInfo, error) {
+	data, err := asn1.Marshal(bags)
+	if err != nil {
+		return contentInfo{}, err
+	}
+	content, err := asn1.Marshal(data)
+	if err != nil {
+		return contentInfo{}, err
+	}
+	return contentInfo{
+		ContentType: oidDataContentType,
+		Content:     explicitTag0(content),
+	}, nil
+}
+
+// encodePFX authenticates the given contents using a MAC derived from the
+// password and encodes the resulting PFX.
+func (e *pbeEncoder) encodePFX(password string, authSafe []contentInfo) ([]byte, error) {
+	authSafeData, err := asn1.Marshal(authSafe)
+	if err != nil {
+		return nil, err
+	}
+	salt, err := randomBytes(8)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := pkcs12MAC(e.macHash, password, salt, pkcs12Iterations, authSafeData)
+	if err != nil {
+		return nil, err
+	}
+	content, err := asn1.Marshal(authSafeData)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(pfxPdu{
+		Version: 3,
+		AuthSafe: contentInfo{
+			ContentType: oidDataContentType,
+			Content:     explicitTag0(content),
+		},
+		MacData: macData{
+			Mac: digestInfo{
+				Algorithm: pkix.AlgorithmIdentifier{Algorithm: e.macAlgorithm},
+				Digest:    digest,
+			},
+			MacSalt:    salt,
+			Iterations: pkcs12Iterations,
+		},
+	})
+}
+
+// verifyPKCS12MAC returns pkcs12.ErrIncorrectPassword if the MAC of the
+// given PKCS12 file does not match the password. Unlike the go-pkcs12
+// library it supports SHA-256 MACs, so it can be used for all profiles.
+func verifyPKCS12MAC(data []byte, password string) error {
+	var pfx pfxPdu
+	if _, err := asn1.Unmarshal(data, &pfx); err != nil {
+		return err
+	}
+	if !pfx.AuthSafe.ContentType.Equal(oidDataContentType) {
+		return errors.New("pkcs12: only password-protected PFX is supported")
+	}
+	var authSafeData []byte
+	if _, err := asn1.Unmarshal(pfx.AuthSafe.Content.Bytes, &authSafeData); err != nil {
+		return err
+	}
+
+	var macHash func() hash.Hash
+	switch algorithm := pfx.MacData.Mac.Algorithm.Algorithm; {
+	case algorithm.Equal(oidSHA1):
+		macHash = sha1.New
+	case algorithm.Equal(oidSHA256):
+		macHash = sha256.New
+	default:
+		return fmt.Errorf("pkcs12: unsupported MAC algorithm %v", algorithm)
+	}
+
+	digest, err := pkcs12MAC(macHash, password, pfx.MacData.MacSalt, pfx.MacData.Iterations, authSafeData)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal(digest, pfx.MacData.Mac.Digest) {
+		return pkcs12.ErrIncorrectPassword
+	}
+	return nil
+}
+
+// pkcs12MAC computes the HMAC of the given data using a key derived from the
+// password as described in RFC 7292, Appendix B.
+func pkcs12MAC(macHash func() hash.Hash, password string, salt []byte, iterations int, data []byte) ([]byte, error) {
+	bmpPassword, err := bmpString(password)
+	if err != nil {
+		return nil, err
+	}
+	key := pkcs12KDF(macHash, 3, bmpPassword, salt, iterations, macHash().Size())
+	mac := hmac.New(macHash, key)
+	mac.Write(data)
+	return mac.Sum(nil), nil
+}
+
+// pbeWithSHAAnd3DESEncrypt encrypts data using the PKCS12 password based
+// encryption scheme pbeWithSHAAnd3-KeyTripleDES-CBC.
+func pbeWithSHAAnd3DESEncrypt(password string, data []byte) (pkix.AlgorithmIdentifier, []byte, error) {
+	bmpPassword, err := bmpString(password)
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, nil, err
+	}
+	salt, err := randomBytes(8)
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, nil, err
+	}
+	key := pkcs12KDF(sha1.New, 1, bmpPassword, salt, pkcs12Iterations, 24)
+	iv := pkcs12KDF(sha1.New, 2, bmpPassword, salt, pkcs12Iterations, des.BlockSize)
+	block, err := des.NewTripleDESCipher(key)
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, nil, err
+	}
+	params, err := asn1.Marshal(pbeParams{Salt: salt, Iterations: pkcs12Iterations})
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, nil, err
+	}
+	algorithm := pkix.AlgorithmIdentifier{
+		Algorithm:  oidPBEWithSHAAnd3DES,
+		Parameters: asn1.RawValue{FullBytes: params},
+	}
+	return algorithm, cbcEncrypt(block, iv, data), nil
+}
+
+// pbes2Encrypter returns a function that encrypts data using PBES2, deriving
+// a key of keyLen bytes for the given CBC mode cipher using
+// PBKDF2-HMAC-SHA-256. See RFC 8018.
+func pbes2Encrypter(cipherAlgorithm asn1.ObjectIdentifier, keyLen int, newCipher func(key []byte) (cipher.Block, error)) func(string, []byte) (pkix.AlgorithmIdentifier, []byte, error) {
+	return func(password string, data []byte) (pkix.AlgorithmIdentifier, []byte, error) {
+		salt, err := randomBytes(16)
+		if err != nil {
+			return pkix.AlgorithmIdentifier{}, nil, err
+		}
+		key := pbkdf2.Key([]byte(password), salt, pkcs12Iterations, keyLen, sha256.New)
+		block, err := newCipher(key)
+		if err != nil {
+			return pkix.AlgorithmIdentifier{}, nil, err
+		}
+		iv, err := randomBytes(block.BlockSize())
+		if err != nil {
+			return pkix.AlgorithmIdentifier{}, nil, err
+		}
+
+		kdfParams, err := asn1.Marshal(pbkdf2Params{
+			Salt:       salt,
+			Iterations: pkcs12Iterations,
+			PRF: pkix.AlgorithmIdentifier{
+				Algorithm:  oidHMACWithSHA256,
+				Parameters: asn1.NullRawValue,
+			},
+		})
+		if err != nil {
+			return pkix.AlgorithmIdentifier{}, nil, err
+		}
+		ivParams, err := asn1.Marshal(iv)
+		if err != nil {
+			return pkix.AlgorithmIdentifier{}, nil, err
+		}
+		params, err := asn1.Marshal(pbes2Params{
+			KeyDerivationFunc: pkix.AlgorithmIdentifier{
+				Algorithm:  oidPBKDF2,
+				Parameters: asn1.RawValue{FullBytes: kdfParams},
+			},
+			EncryptionScheme: pkix.AlgorithmIdentifier{
+				Algorithm:  cipherAlgorithm,
+				Parameters: asn1.RawValue{FullBytes: ivParams},
+			},
+		})
+		if err != nil {
+			return pkix.AlgorithmIdentifier{}, nil, err
+		}
+		algorithm := pkix.AlgorithmIdentifier{
+			Algorithm:  oidPBES2,
+			Parameters: asn1.RawValue{FullBytes: params},
+		}
+		return algorithm, cbcEncrypt(block, iv, data), nil
+	}
+}
+
+// pkcs12KDF derives size bytes of key material from the password and salt as
+// described in RFC 7292, Appendix B.2. The id selects whether the output is
+// used as an encryption key (1), an IV (2) or a MAC key (3).
+func pkcs12KDF(hashFn func() hash.Hash, id byte, password, salt []byte, iterations, size int) []byte {
+	// v is the block size of both SHA-1 and SHA-256.
+	const v = 64
+	h := hashFn()
+
+	d := make([]byte, v)
+	for i := range d {
+		d[i] = id
+	}
+	in := append(fillWithRepeats(salt, v), fillWithRepeats(password, v)...)
+
+	var out []byte
+	for len(out) < size {
+		h.Reset()
+		h.Write(d)
+		h.Write(in)
+		a := h.Sum(nil)
+		for i := 1; i < iterations; i++ {
+			h.Reset()
+			h.Write(a)
+			a = h.Sum(nil)
+		}
+		out = append(out, a...)
+		if len(out) >= size {
+			break
+		}
+
+		// Treat each v byte block of the input as an integer and add B+1
+		// to it, where B is A repeated to fill v bytes.
+		b := fillWithRepeats(a, v)
+		for j := 0; j < len(in); j += v {
+			carry := 1
+			for k := v - 1; k >= 0; k-- {
+				sum := int(in[j+k]) + int(b[k]) + carry
+				in[j+k] = byte(sum)
+				carry = sum >> 8
+			}
+		}
+	}
+	return out[:size]
+}
+
+// fillWithRepeats repeats pattern to fill the smallest multiple of v bytes
+// that can hold it.
+func fillWithRepeats(pattern []byte, v int) []byte {
+	if len(pattern) == 0 {
+		return nil
+	}
+	out := make([]byte, v*((len(pattern)+v-1)/v))
+	for i := 0; i < len(out); i += len(pattern) {
+		copy(out[i:], pattern)
+	}
+	return out
+}
+
+// bmpString encodes s as a NULL terminated big-endian UCS-2 string, as
+// required for passwords by the PKCS12 key derivation function.
+func bmpString(s string) ([]byte, error) {
+	out := make([]byte, 0, 2*len(s)+2)
+	for _, r := range s {
+		if t, _ := utf16.EncodeRune(r); t != 0xfffd {
+			return nil, errors.New("pkcs12: password contains characters that cannot be encoded in UCS-2")
+		}
+		out = append(out, byte(r>>8), byte(r))
+	}
+	return append(out, 0, 0), nil
+}
+
+// cbcEncrypt encrypts data using the given block cipher in CBC mode after
+// applying PKCS#7 padding.
+func cbcEncrypt(block cipher.Block, iv, data []byte) []byte {
+	padLen := block.BlockSize() - len(data)%block.BlockSize()
+	out := make([]byte, len(data)+padLen)
+	copy(out, data)
+	for i := len(data); i < len(out); i++ {
+		out[i] = byte(padLen)
+	}
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(out, out)
+	return out
+}
+
+func newCertBag(cert *x509.Certificate, attributes []pkcs12Attribute) (safeBag, error) {
+	data, err := asn1.Marshal(certBag{ID: oidCertTypeX509, Data: cert.Raw})
+	if err != nil {
+		return safeBag{}, err
+	}
+	return safeBag{
+		ID:         oidCertBag,
+		Value:      explicitTag0(data),
+		Attributes: attributes,
+	}, nil
+}
+
+// newPKCS12Attribute returns an attribute holding the given DER encoded
+// value.
+func newPKCS12Attribute(id asn1.ObjectIdentifier, value []byte) pkcs12Attribute {
+	return pkcs12Attribute{
+		ID:    id,
+		Value: asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: value},
+	}
+}
+
+// explicitTag0 wraps the given DER encoded value in an explicit [0] tag.
+func explicitTag0(der []byte) asn1.RawValue {
+	return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: der}
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
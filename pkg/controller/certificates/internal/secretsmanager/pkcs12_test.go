@@ -0,0 +1,271 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretsmanager
+
+import (
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"software.sslmate.com/src/go-pkcs12"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+// The PKCS12 files in testdata were created by OpenSSL 3.0 from the key and
+// certificates alongside them, using the password "password":
+//
+//	openssl pkcs12 -export -legacy -in tls.crt -inkey tls.key -certfile ca.crt -out legacy-rc2.p12
+//	openssl pkcs12 -export -keypbe PBE-SHA1-3DES -certpbe PBE-SHA1-3DES -macalg sha1 -iter 2048 ... -out legacy-des.p12
+//	openssl pkcs12 -export -keypbe DES-EDE3-CBC -certpbe DES-EDE3-CBC -macalg sha256 -iter 2048 ... -out modern-3des.p12
+//	openssl pkcs12 -export -keypbe AES-256-CBC -certpbe AES-256-CBC -macalg sha256 -iter 2048 ... -out aes256.p12
+var pkcs12OpenSSLVectors = map[cmapi.PKCS12Profile]struct {
+	file string
+	// expInfo are the lines printed by 'openssl pkcs12 -info' that describe
+	// the algorithms we use for the profile.
+	expInfo []string
+	// expCipher is the cipher used by PBES2 profiles.
+	expCipher asn1.ObjectIdentifier
+}{
+	cmapi.LegacyRC2PKCS12Profile: {
+		file: "legacy-rc2.p12",
+		expInfo: []string{
+			// go-pkcs12 computes the MAC using a single iteration.
+			"MAC: sha1, Iteration 1",
+			"PKCS7 Encrypted data: pbeWithSHA1And40BitRC2-CBC, Iteration 2048",
+			"Shrouded Keybag: pbeWithSHA1And3-KeyTripleDES-CBC, Iteration 2048",
+		},
+	},
+	cmapi.LegacyDESPKCS12Profile: {
+		file: "legacy-des.p12",
+		expInfo: []string{
+			"MAC: sha1, Iteration 2048",
+			"PKCS7 Encrypted data: pbeWithSHA1And3-KeyTripleDES-CBC, Iteration 2048",
+			"Shrouded Keybag: pbeWithSHA1And3-KeyTripleDES-CBC, Iteration 2048",
+		},
+	},
+	cmapi.Modern3DESPKCS12Profile: {
+		file: "modern-3des.p12",
+		expInfo: []string{
+			"MAC: sha256, Iteration 2048",
+			"PKCS7 Encrypted data: PBES2, PBKDF2, DES-EDE3-CBC, Iteration 2048, PRF hmacWithSHA256",
+			"Shrouded Keybag: PBES2, PBKDF2, DES-EDE3-CBC, Iteration 2048, PRF hmacWithSHA256",
+		},
+		expCipher: oidDESEDE3CBC,
+	},
+	cmapi.AES256PKCS12Profile: {
+		file: "aes256.p12",
+		expInfo: []string{
+			"MAC: sha256, Iteration 2048",
+			"PKCS7 Encrypted data: PBES2, PBKDF2, AES-256-CBC, Iteration 2048, PRF hmacWithSHA256",
+			"Shrouded Keybag: PBES2, PBKDF2, AES-256-CBC, Iteration 2048, PRF hmacWithSHA256",
+		},
+		expCipher: oidAES256CBC,
+	},
+}
+
+type pkcs12TestData struct {
+	rawKey, certPEM, caPEM []byte
+
+	key  crypto.Signer
+	cert *x509.Certificate
+	ca   *x509.Certificate
+}
+
+func mustReadPKCS12TestData(t *testing.T) pkcs12TestData {
+	var d pkcs12TestData
+	var err error
+	d.rawKey, err = ioutil.ReadFile(filepath.Join("testdata", "tls.key"))
+	require.NoError(t, err)
+	d.certPEM, err = ioutil.ReadFile(filepath.Join("testdata", "tls.crt"))
+	require.NoError(t, err)
+	d.caPEM, err = ioutil.ReadFile(filepath.Join("testdata", "ca.crt"))
+	require.NoError(t, err)
+
+	d.key, err = pki.DecodePrivateKeyBytes(d.rawKey)
+	require.NoError(t, err)
+	d.cert, err = pki.DecodeX509CertificateBytes(d.certPEM)
+	require.NoError(t, err)
+	d.ca, err = pki.DecodeX509CertificateBytes(d.caPEM)
+	require.NoError(t, err)
+	return d
+}
+
+// pkcs12AlgorithmParams is the comparable part of a PKCS12 encryption
+// algorithm, excluding the random salt and IV.
+type pkcs12AlgorithmParams struct {
+	Algorithm  string
+	Iterations int
+	PRF        string
+	Cipher     string
+}
+
+func mustPKCS12AlgorithmParams(t *testing.T, alg pkix.AlgorithmIdentifier) pkcs12AlgorithmParams {
+	params := pkcs12AlgorithmParams{Algorithm: alg.Algorithm.String()}
+	if !alg.Algorithm.Equal(oidPBES2) {
+		var pbe pbeParams
+		_, err := asn1.Unmarshal(alg.Parameters.FullBytes, &pbe)
+		require.NoError(t, err)
+		params.Iterations = pbe.Iterations
+		return params
+	}
+
+	var pbes2 pbes2Params
+	_, err := asn1.Unmarshal(alg.Parameters.FullBytes, &pbes2)
+	require.NoError(t, err)
+	require.Equal(t, oidPBKDF2, pbes2.KeyDerivationFunc.Algorithm)
+	var kdf pbkdf2Params
+	_, err = asn1.Unmarshal(pbes2.KeyDerivationFunc.Parameters.FullBytes, &kdf)
+	require.NoError(t, err)
+	params.Iterations = kdf.Iterations
+	params.PRF = kdf.PRF.Algorithm.String()
+	params.Cipher = pbes2.EncryptionScheme.Algorithm.String()
+	return params
+}
+
+// TestPKCS12OpenSSLVectors checks that files created by OpenSSL for each
+// profile use the same algorithms and parameters as the files we create, and
+// that their MAC and contents can be verified by the same code used to
+// verify our own files.
+func TestPKCS12OpenSSLVectors(t *testing.T) {
+	const password = "password"
+	d := mustReadPKCS12TestData(t)
+
+	for profile, vector := range pkcs12OpenSSLVectors {
+		t.Run(string(profile), func(t *testing.T) {
+			expected, err := ioutil.ReadFile(filepath.Join("testdata", vector.file))
+			require.NoError(t, err)
+
+			assert.NoError(t, verifyPKCS12MAC(expected, password))
+			assert.Equal(t, pkcs12.ErrIncorrectPassword, verifyPKCS12MAC(expected, "not-"+password))
+
+			if vector.expCipher != nil {
+				pk, certs := decodePBES2PKCS12(t, expected, password, vector.expCipher)
+				assert.Equal(t, d.key.Public(), pk.(crypto.Signer).Public(), "private key does not match")
+				assert.Equal(t, []*x509.Certificate{d.cert, d.ca}, certs)
+			} else {
+				pk, cert, cas, err := pkcs12.DecodeChain(expected, password)
+				require.NoError(t, err)
+				assert.Equal(t, d.key.Public(), pk.(crypto.Signer).Public(), "private key does not match")
+				assert.Equal(t, d.cert, cert)
+				assert.Equal(t, []*x509.Certificate{d.ca}, cas)
+			}
+
+			actual, err := encodePKCS12Keystore(profile, password, d.rawKey, d.certPEM, d.caPEM)
+			require.NoError(t, err)
+
+			expMac, expCert, expKey := pkcs12Algorithms(t, expected)
+			mac, cert, key := pkcs12Algorithms(t, actual)
+			assert.Equal(t, expMac.Algorithm, mac.Algorithm, "unexpected MAC algorithm")
+			assert.Equal(t, mustPKCS12AlgorithmParams(t, expCert), mustPKCS12AlgorithmParams(t, cert), "unexpected certificate encryption algorithm")
+			assert.Equal(t, mustPKCS12AlgorithmParams(t, expKey), mustPKCS12AlgorithmParams(t, key), "unexpected private key encryption algorithm")
+		})
+	}
+}
+
+// TestPKCS12OpenSSLCanRead checks that OpenSSL is able to read the keystores
+// and truststores we create for each profile, and that it reports the
+// algorithms expected for the profile.
+func TestPKCS12OpenSSLCanRead(t *testing.T) {
+	const password = "password"
+	opensslPath, err := exec.LookPath("openssl")
+	if err != nil {
+		t.Skip("skipping test as openssl is not installed")
+	}
+	version, err := exec.Command(opensslPath, "version").Output()
+	require.NoError(t, err)
+	isOpenSSL3 := strings.HasPrefix(string(version), "OpenSSL 3")
+
+	d := mustReadPKCS12TestData(t)
+	dir := t.TempDir()
+
+	openssl := func(t *testing.T, profile cmapi.PKCS12Profile, args ...string) string {
+		args = append([]string{"pkcs12", "-passin", "pass:" + password}, args...)
+		// OpenSSL 3.0 only supports RC2 through its legacy provider.
+		if profile == cmapi.LegacyRC2PKCS12Profile && isOpenSSL3 {
+			args = append(args, "-legacy")
+		}
+		out, err := exec.Command(opensslPath, args...).CombinedOutput()
+		require.NoError(t, err, "openssl failed: %s", out)
+		return string(out)
+	}
+
+	for profile, vector := range pkcs12OpenSSLVectors {
+		t.Run(string(profile), func(t *testing.T) {
+			keystore, err := encodePKCS12Keystore(profile, password, d.rawKey, d.certPEM, d.caPEM)
+			require.NoError(t, err)
+			keystorePath := filepath.Join(dir, "keystore-"+vector.file)
+			require.NoError(t, ioutil.WriteFile(keystorePath, keystore, 0600))
+
+			// The expected -info output is in the format used by OpenSSL 3.0.
+			if isOpenSSL3 {
+				info := openssl(t, profile, "-in", keystorePath, "-info", "-noout")
+				for _, line := range vector.expInfo {
+					assert.Contains(t, info, line)
+				}
+			}
+
+			key, certs := mustDecodePEMKeyAndCerts(t, openssl(t, profile, "-in", keystorePath, "-nodes"))
+			assert.Equal(t, d.key.Public(), key.Public(), "private key does not match")
+			assert.ElementsMatch(t, []*x509.Certificate{d.cert, d.ca}, certs)
+
+			truststore, err := encodePKCS12Truststore(profile, password, d.caPEM)
+			require.NoError(t, err)
+			truststorePath := filepath.Join(dir, "truststore-"+vector.file)
+			require.NoError(t, ioutil.WriteFile(truststorePath, truststore, 0600))
+
+			key, certs = mustDecodePEMKeyAndCerts(t, openssl(t, profile, "-in", truststorePath, "-nokeys"))
+			assert.Nil(t, key)
+			assert.Equal(t, []*x509.Certificate{d.ca}, certs)
+		})
+	}
+}
+
+// mustDecodePEMKeyAndCerts decodes the private key and certificates printed
+// by 'openssl pkcs12', ignoring the bag attributes between them.
+func mustDecodePEMKeyAndCerts(t *testing.T, out string) (key crypto.Signer, certs []*x509.Certificate) {
+	rest := []byte(out)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return key, certs
+		}
+		switch block.Type {
+		case "CERTIFICATE":
+			cert, err := x509.ParseCertificate(block.Bytes)
+			require.NoError(t, err)
+			certs = append(certs, cert)
+		case "PRIVATE KEY":
+			var err error
+			key, err = pki.DecodePrivateKeyBytes(pem.EncodeToMemory(block))
+			require.NoError(t, err)
+		default:
+			t.Fatalf("unexpected PEM block %q in openssl output", block.Type)
+		}
+	}
+}
@@ -25,6 +25,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	corelisters "k8s.io/client-go/listers/core/v1"
 
@@ -95,6 +96,26 @@ func (s *SecretsManager) UpdateData(ctx context.Context, crt *cmapi.Certificate,
 			},
 			Type: corev1.SecretTypeTLS,
 		}
+	} else {
+		// avoid mutating the object in the lister's cache
+		secret = secret.DeepCopy()
+	}
+
+	// Immutable Secrets cannot have their data changed, nor be made mutable
+	// again, so we must record whether the existing resource needs to be
+	// recreated before it is modified.
+	existingImmutable := secretExists && isImmutable(secret)
+	existingData := secret.Data
+	secret.Data = make(map[string][]byte, len(existingData))
+	for k, v := range existingData {
+		secret.Data[k] = v
+	}
+
+	if certificateWantsImmutableSecret(crt) {
+		immutable := true
+		secret.Immutable = &immutable
+	} else if !existingImmutable {
+		secret.Immutable = nil
 	}
 
 	// secret will be overwritten by 'existingSecret' if existingSecret is non-nil
@@ -114,14 +135,265 @@ func (s *SecretsManager) UpdateData(ctx context.Context, crt *cmapi.Certificate,
 		return err
 	}
 
+	// If the existing secret is immutable and either its data has changed or
+	// it should no longer be immutable, it must be deleted and recreated.
+	if existingImmutable && (!certificateWantsImmutableSecret(crt) || !secretDataEqual(existingData, secret.Data)) {
+		return s.recreateSecret(ctx, crt, secret)
+	}
+
 	// Currently we are always updating. We should devise a way to not have to call an update if it is not necessary.
 	_, err = s.kubeClient.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
 	return err
 }
 
+// UpdateMetadata ensures the existing Secret resource has the labels and
+// annotations given in the Certificate's secretTemplate, without modifying
+// its data. This allows changes to the secretTemplate metadata to be applied
+// without re-issuing the certificate.
+// If the Secret resource does not exist, or already has the metadata, nothing
+// is done.
+func (s *SecretsManager) UpdateMetadata(ctx context.Context, crt *cmapi.Certificate) error {
+	secret, err := s.secretLister.Secrets(crt.Namespace).Get(crt.Spec.SecretName)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if SecretTemplateMatchesSecret(crt, secret) {
+		return nil
+	}
+
+	// avoid mutating the object in the lister's cache
+	secret = secret.DeepCopy()
+	setTemplateMetadata(crt, secret)
+
+	// Only metadata is modified, so this is permitted for immutable Secrets.
+	_, err = s.kubeClient.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	return err
+}
+
+// SecretTemplateMatchesSecret returns true if the given Secret has all of the
+// labels, annotations and owner references given in the Certificate's
+// secretTemplate.
+func SecretTemplateMatchesSecret(crt *cmapi.Certificate, secret *corev1.Secret) bool {
+	if crt.Spec.SecretTemplate == nil {
+		return true
+	}
+	for k, v := range crt.Spec.SecretTemplate.Labels {
+		if existing, ok := secret.Labels[k]; !ok || existing != v {
+			return false
+		}
+	}
+	for k, v := range crt.Spec.SecretTemplate.Annotations {
+		if existing, ok := secret.Annotations[k]; !ok || existing != v {
+			return false
+		}
+	}
+	for _, ref := range crt.Spec.SecretTemplate.OwnerReferences {
+		if !hasOwnerReference(secret, ref.UID) {
+			return false
+		}
+	}
+	return true
+}
+
+// setTemplateMetadata copies the labels, annotations and owner references
+// given in the Certificate's secretTemplate to the Secret resource. Existing
+// metadata that is not in the template is left unchanged.
+func setTemplateMetadata(crt *cmapi.Certificate, secret *corev1.Secret) {
+	if crt.Spec.SecretTemplate == nil {
+		return
+	}
+	if len(crt.Spec.SecretTemplate.Labels) > 0 && secret.Labels == nil {
+		secret.Labels = make(map[string]string)
+	}
+	for k, v := range crt.Spec.SecretTemplate.Labels {
+		secret.Labels[k] = v
+	}
+	if len(crt.Spec.SecretTemplate.Annotations) > 0 && secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	for k, v := range crt.Spec.SecretTemplate.Annotations {
+		secret.Annotations[k] = v
+	}
+	for _, ref := range crt.Spec.SecretTemplate.OwnerReferences {
+		if !hasOwnerReference(secret, ref.UID) {
+			secret.OwnerReferences = append(secret.OwnerReferences, ref)
+		}
+	}
+}
+
+// hasOwnerReference returns true if the Secret has an owner reference to the
+// object with the given UID.
+func hasOwnerReference(secret *corev1.Secret, uid types.UID) bool {
+	for _, ref := range secret.OwnerReferences {
+		if ref.UID == uid {
+			return true
+		}
+	}
+	return false
+}
+
+// recreateSecret deletes the existing Secret resource and creates it again
+// with the given contents. This is required to change the data of an
+// immutable Secret.
+func (s *SecretsManager) recreateSecret(ctx context.Context, crt *cmapi.Certificate, secret *corev1.Secret) error {
+	uid := secret.UID
+	err := s.kubeClient.CoreV1().Secrets(secret.Namespace).Delete(ctx, secret.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &uid},
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("deleting immutable Secret %q to apply new data: %w", secret.Name, err)
+	}
+
+	newSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            secret.Name,
+			Namespace:       secret.Namespace,
+			Labels:          secret.Labels,
+			Annotations:     secret.Annotations,
+			OwnerReferences: secret.OwnerReferences,
+		},
+		Immutable: secret.Immutable,
+		Data:      secret.Data,
+		Type:      secret.Type,
+	}
+	if !certificateWantsImmutableSecret(crt) {
+		newSecret.Immutable = nil
+	}
+
+	_, err = s.kubeClient.CoreV1().Secrets(newSecret.Namespace).Create(ctx, newSecret, metav1.CreateOptions{})
+	return err
+}
+
+// UpdateKeystores ensures the PKCS12 keystore in the existing Secret resource
+// is encrypted using the password currently stored in the Secret referenced
+// by the Certificate's `keystores.pkcs12.passwordSecretRef`. If it is not, the
+// keystore is re-encoded from the certificate and private key already stored
+// in the Secret, so that changing the password does not require the
+// certificate to be re-issued.
+// If the Secret resource does not exist, does not contain a certificate and
+// private key, or has no keystore encrypted with a different password,
+// nothing is done.
+func (s *SecretsManager) UpdateKeystores(ctx context.Context, crt *cmapi.Certificate) error {
+	if !certificateWantsPKCS12Keystore(crt) {
+		return nil
+	}
+
+	secret, err := s.secretLister.Secrets(crt.Namespace).Get(crt.Spec.SecretName)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	data := SecretData{
+		PrivateKey:  secret.Data[corev1.TLSPrivateKeyKey],
+		Certificate: secret.Data[corev1.TLSCertKey],
+		CA:          secret.Data[cmmeta.TLSCAKey],
+	}
+	if len(data.PrivateKey) == 0 || len(data.Certificate) == 0 {
+		return nil
+	}
+
+	pw, err := s.pkcs12KeystorePassword(crt)
+	if err != nil {
+		return err
+	}
+	if !pkcs12PasswordChanged(secret.Data[pkcs12SecretKey], string(pw)) &&
+		!pkcs12PasswordChanged(secret.Data[pkcs12TruststoreKey], string(pw)) {
+		return nil
+	}
+
+	// avoid mutating the object in the lister's cache
+	secret = secret.DeepCopy()
+	if err := setPKCS12Keystore(crt, secret, data, pw); err != nil {
+		return err
+	}
+
+	if isImmutable(secret) {
+		return s.recreateSecret(ctx, crt, secret)
+	}
+	_, err = s.kubeClient.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	return err
+}
+
+// certificateWantsPKCS12Keystore returns true if the Certificate requests
+// that a PKCS12 keystore is created in its Secret resource.
+func certificateWantsPKCS12Keystore(crt *cmapi.Certificate) bool {
+	return crt.Spec.Keystores != nil && crt.Spec.Keystores.PKCS12 != nil && crt.Spec.Keystores.PKCS12.Create
+}
+
+// pkcs12KeystorePassword returns the password stored in the Secret referenced
+// by the Certificate's `keystores.pkcs12.passwordSecretRef`.
+func (s *SecretsManager) pkcs12KeystorePassword(crt *cmapi.Certificate) ([]byte, error) {
+	ref := crt.Spec.Keystores.PKCS12.PasswordSecretRef
+	pwSecret, err := s.secretLister.Secrets(crt.Namespace).Get(ref.Name)
+	if err != nil {
+		return nil, fmt.Errorf("fetching PKCS12 keystore password from Secret: %v", err)
+	}
+	if pwSecret.Data == nil || len(pwSecret.Data[ref.Key]) == 0 {
+		return nil, fmt.Errorf("PKCS12 keystore password Secret contains no data for key %q", ref.Key)
+	}
+	return pwSecret.Data[ref.Key], nil
+}
+
+// setPKCS12Keystore encodes the given data as a PKCS12 keystore, and the CA
+// as a PKCS12 truststore if given, using the profile requested by the
+// Certificate, and stores them in the Secret resource.
+func setPKCS12Keystore(crt *cmapi.Certificate, secret *corev1.Secret, data SecretData, pw []byte) error {
+	profile := crt.Spec.Keystores.PKCS12.Profile
+	keystoreData, err := encodePKCS12Keystore(profile, string(pw), data.PrivateKey, data.Certificate, data.CA)
+	if err != nil {
+		return fmt.Errorf("error encoding PKCS12 bundle: %w", err)
+	}
+	// always overwrite the keystore entry for now
+	secret.Data[pkcs12SecretKey] = keystoreData
+
+	if len(data.CA) > 0 {
+		truststoreData, err := encodePKCS12Truststore(profile, string(pw), data.CA)
+		if err != nil {
+			return fmt.Errorf("error encoding PKCS12 trust store bundle: %w", err)
+		}
+		// always overwrite the truststore entry
+		secret.Data[pkcs12TruststoreKey] = truststoreData
+	}
+	return nil
+}
+
+// certificateWantsImmutableSecret returns true if the Certificate requests
+// that its Secret resource is created as immutable.
+func certificateWantsImmutableSecret(crt *cmapi.Certificate) bool {
+	return crt.Annotations[cmapi.ImmutableSecretAnnotationKey] == "true"
+}
+
+// isImmutable returns true if the given Secret is marked as immutable.
+func isImmutable(secret *corev1.Secret) bool {
+	return secret.Immutable != nil && *secret.Immutable
+}
+
+// secretDataEqual returns true if both maps contain exactly the same keys and
+// values.
+func secretDataEqual(a, b map[string][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		bv, ok := b[k]
+		if !ok || !bytes.Equal(v, bv) {
+			return false
+		}
+	}
+	return true
+}
+
 // setValues will update the Secret resource 'secret' with the data contained
 // in the given secretData.
-// It will update labels and annotations on the Secret resource appropriately.
+// It will update labels and annotations on the Secret resource appropriately,
+// including those given in the Certificate's secretTemplate.
 // The Secret resource 's' must be non-nil, although may be a resource that does
 // not exist in the Kubernetes apiserver yet.
 // setValues will NOT actually update the resource in the apiserver.
@@ -142,30 +414,13 @@ func (s *SecretsManager) setValues(crt *cmapi.Certificate, secret *corev1.Secret
 			!bytes.Equal(secret.Data[cmmeta.TLSCAKey], data.CA)) {
 
 		// Handle the experimental PKCS12 support
-		if crt.Spec.Keystores != nil && crt.Spec.Keystores.PKCS12 != nil && crt.Spec.Keystores.PKCS12.Create {
-			ref := crt.Spec.Keystores.PKCS12.PasswordSecretRef
-			pwSecret, err := s.secretLister.Secrets(crt.Namespace).Get(ref.Name)
+		if certificateWantsPKCS12Keystore(crt) {
+			pw, err := s.pkcs12KeystorePassword(crt)
 			if err != nil {
-				return fmt.Errorf("fetching PKCS12 keystore password from Secret: %v", err)
+				return err
 			}
-			if pwSecret.Data == nil || len(pwSecret.Data[ref.Key]) == 0 {
-				return fmt.Errorf("PKCS12 keystore password Secret contains no data for key %q", ref.Key)
-			}
-			pw := pwSecret.Data[ref.Key]
-			keystoreData, err := encodePKCS12Keystore(string(pw), data.PrivateKey, data.Certificate, data.CA)
-			if err != nil {
-				return fmt.Errorf("error encoding PKCS12 bundle: %w", err)
-			}
-			// always overwrite the keystore entry for now
-			secret.Data[pkcs12SecretKey] = keystoreData
-
-			if len(data.CA) > 0 {
-				truststoreData, err := encodePKCS12Truststore(string(pw), data.CA)
-				if err != nil {
-					return fmt.Errorf("error encoding PKCS12 trust store bundle: %w", err)
-				}
-				// always overwrite the truststore entry
-				secret.Data[pkcs12TruststoreKey] = truststoreData
+			if err := setPKCS12Keystore(crt, secret, data, pw); err != nil {
+				return err
 			}
 		} else {
 			delete(secret.Data, pkcs12SecretKey)
@@ -183,7 +438,8 @@ func (s *SecretsManager) setValues(crt *cmapi.Certificate, secret *corev1.Secret
 				return fmt.Errorf("JKS keystore password Secret contains no data for key %q", ref.Key)
 			}
 			pw := pwSecret.Data[ref.Key]
-			keystoreData, err := encodeJKSKeystore(pw, data.PrivateKey, data.Certificate, data.CA)
+			jksKeystore := crt.Spec.Keystores.JKS
+			keystoreData, err := encodeJKSKeystore(pw, jksKeystore.Alias, jksKeystore.IncludeChain, data.PrivateKey, data.Certificate, data.CA)
 			if err != nil {
 				return fmt.Errorf("error encoding JKS bundle: %w", err)
 			}
@@ -191,7 +447,7 @@ func (s *SecretsManager) setValues(crt *cmapi.Certificate, secret *corev1.Secret
 			secret.Data[jksSecretKey] = keystoreData
 
 			if len(data.CA) > 0 {
-				truststoreData, err := encodeJKSTruststore(pw, data.CA)
+				truststoreData, err := encodeJKSTruststore(pw, jksKeystore.IncludeChain, data.CA)
 				if err != nil {
 					return fmt.Errorf("error encoding JKS trust store bundle: %w", err)
 				}
@@ -204,6 +460,13 @@ func (s *SecretsManager) setValues(crt *cmapi.Certificate, secret *corev1.Secret
 		}
 	}
 
+	// An OCSP staple is only valid for the certificate it was fetched for,
+	// so remove it when the certificate changes. A new staple is fetched by
+	// the OCSP staple controller.
+	if !bytes.Equal(secret.Data[corev1.TLSCertKey], data.Certificate) {
+		delete(secret.Data, cmmeta.TLSOCSPStapleKey)
+	}
+
 	secret.Data[corev1.TLSPrivateKeyKey] = data.PrivateKey
 	secret.Data[corev1.TLSCertKey] = data.Certificate
 	if len(data.CA) > 0 {
@@ -212,6 +475,22 @@ func (s *SecretsManager) setValues(crt *cmapi.Certificate, secret *corev1.Secret
 		delete(secret.Data, cmmeta.TLSCAKey)
 	}
 
+	// Duplicate the certificate and private key under any additional keys
+	// requested, keeping the standard keys so the Secret remains a valid
+	// kubernetes.io/tls Secret.
+	if crt.Spec.SecretTemplate != nil {
+		for _, key := range crt.Spec.SecretTemplate.CertificateKeys {
+			secret.Data[key] = data.Certificate
+		}
+		for _, key := range crt.Spec.SecretTemplate.PrivateKeyKeys {
+			secret.Data[key] = data.PrivateKey
+		}
+	}
+
+	// Apply the template metadata first so that it cannot override the
+	// annotations managed by cert-manager.
+	setTemplateMetadata(crt, secret)
+
 	if secret.Annotations == nil {
 		secret.Annotations = make(map[string]string)
 	}
@@ -18,6 +18,7 @@ package secretsmanager
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -27,6 +28,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	coretesting "k8s.io/client-go/testing"
 	fakeclock "k8s.io/utils/clock/testing"
+	"software.sslmate.com/src/go-pkcs12"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
@@ -62,6 +64,35 @@ func TestSecretsManager(t *testing.T) {
 	exampleBundle := internaltest.MustCreateCryptoBundle(t, gen.CertificateFrom(baseCert,
 		gen.SetCertificateDNSNames("example.com"),
 	), fixedClock)
+	immutableCert := gen.CertificateFrom(exampleBundle.Certificate,
+		gen.AddCertificateAnnotations(map[string]string{
+			cmapi.ImmutableSecretAnnotationKey: "true",
+		}),
+	)
+	aliasedCert := gen.CertificateFrom(exampleBundle.Certificate,
+		gen.SetCertificateSecretTemplate(cmapi.CertificateSecretTemplate{
+			CertificateKeys: []string{"server.crt", "cert.pem"},
+			PrivateKeyKeys:  []string{"server.key"},
+		}),
+	)
+	appOwnerRef := metav1.OwnerReference{APIVersion: "example.com/v1", Kind: "App", Name: "my-app", UID: "app-uid"}
+	ownedCert := gen.CertificateFrom(exampleBundle.Certificate,
+		gen.SetCertificateSecretTemplate(cmapi.CertificateSecretTemplate{
+			OwnerReferences: []metav1.OwnerReference{appOwnerRef},
+		}),
+	)
+	immutable := true
+	expectedAnnotations := map[string]string{
+		cmapi.CertificateNameKey:       "test",
+		cmapi.IssuerGroupAnnotationKey: "foo.io",
+		cmapi.IssuerKindAnnotationKey:  "Issuer",
+		cmapi.IssuerNameAnnotationKey:  "ca-issuer",
+
+		cmapi.CommonNameAnnotationKey: exampleBundle.Cert.Subject.CommonName,
+		cmapi.AltNamesAnnotationKey:   strings.Join(exampleBundle.Cert.DNSNames, ","),
+		cmapi.IPSANAnnotationKey:      strings.Join(utilpki.IPAddressesToString(exampleBundle.Cert.IPAddresses), ","),
+		cmapi.URISANAnnotationKey:     strings.Join(utilpki.URLsToString(exampleBundle.Cert.URIs), ","),
+	}
 
 	tests := map[string]testT{
 		"if secret does not exists and unable to decode certificate, then error": {
@@ -275,6 +306,386 @@ func TestSecretsManager(t *testing.T) {
 			},
 			expectedErr: false,
 		},
+
+		"if the certificate in an existing Secret changes, remove the OCSP staple of the old certificate": {
+			certificate: exampleBundle.Certificate,
+			SecretData:  SecretData{Certificate: exampleBundle.CertBytes, CA: []byte("test-ca"), PrivateKey: []byte("test-key")},
+			builder: &testpkg.Builder{
+				KubeObjects: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: gen.DefaultTestNamespace,
+							Name:      "output",
+						},
+						Data: map[string][]byte{
+							corev1.TLSCertKey:       []byte("foo"),
+							corev1.TLSPrivateKeyKey: []byte("foo"),
+							cmmeta.TLSCAKey:         []byte("foo"),
+							cmmeta.TLSOCSPStapleKey: []byte("staple-for-foo"),
+						},
+						Type: corev1.SecretTypeTLS,
+					},
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						gen.DefaultTestNamespace,
+						&corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace:   gen.DefaultTestNamespace,
+								Name:        "output",
+								Annotations: expectedAnnotations,
+							},
+							Data: map[string][]byte{
+								corev1.TLSCertKey:       exampleBundle.CertBytes,
+								corev1.TLSPrivateKeyKey: []byte("test-key"),
+								cmmeta.TLSCAKey:         []byte("test-ca"),
+							},
+							Type: corev1.SecretTypeTLS,
+						},
+					)),
+				},
+			},
+			expectedErr: false,
+		},
+
+		"if secret does not exist and immutable Secret requested, create new immutable Secret": {
+			certificate: immutableCert,
+			SecretData:  SecretData{Certificate: exampleBundle.CertBytes, CA: []byte("test-ca"), PrivateKey: []byte("test-key")},
+			builder: &testpkg.Builder{
+				KubeObjects: []runtime.Object{},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewCreateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						gen.DefaultTestNamespace,
+						&corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace:   gen.DefaultTestNamespace,
+								Name:        "output",
+								Annotations: expectedAnnotations,
+							},
+							Immutable: &immutable,
+							Data: map[string][]byte{
+								corev1.TLSCertKey:       exampleBundle.CertBytes,
+								corev1.TLSPrivateKeyKey: []byte("test-key"),
+								cmmeta.TLSCAKey:         []byte("test-ca"),
+							},
+							Type: corev1.SecretTypeTLS,
+						},
+					)),
+				},
+			},
+			expectedErr: false,
+		},
+
+		"if immutable secret exists with different data, delete and recreate it": {
+			certificate: immutableCert,
+			SecretData:  SecretData{Certificate: exampleBundle.CertBytes, CA: []byte("test-ca"), PrivateKey: []byte("test-key")},
+			builder: &testpkg.Builder{
+				KubeObjects: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: gen.DefaultTestNamespace,
+							Name:      "output",
+							Annotations: map[string]string{
+								"my-custom": "annotation",
+							},
+						},
+						Immutable: &immutable,
+						Data: map[string][]byte{
+							corev1.TLSCertKey:       []byte("foo"),
+							corev1.TLSPrivateKeyKey: []byte("foo"),
+							cmmeta.TLSCAKey:         []byte("foo"),
+						},
+						Type: corev1.SecretTypeTLS,
+					},
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewDeleteAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						gen.DefaultTestNamespace,
+						"output",
+					)),
+					testpkg.NewAction(coretesting.NewCreateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						gen.DefaultTestNamespace,
+						&corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace: gen.DefaultTestNamespace,
+								Name:      "output",
+								Annotations: map[string]string{
+									"my-custom": "annotation",
+
+									cmapi.CertificateNameKey:       "test",
+									cmapi.IssuerGroupAnnotationKey: "foo.io",
+									cmapi.IssuerKindAnnotationKey:  "Issuer",
+									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
+
+									cmapi.CommonNameAnnotationKey: exampleBundle.Cert.Subject.CommonName,
+									cmapi.AltNamesAnnotationKey:   strings.Join(exampleBundle.Cert.DNSNames, ","),
+									cmapi.IPSANAnnotationKey:      strings.Join(utilpki.IPAddressesToString(exampleBundle.Cert.IPAddresses), ","),
+									cmapi.URISANAnnotationKey:     strings.Join(utilpki.URLsToString(exampleBundle.Cert.URIs), ","),
+								},
+							},
+							Immutable: &immutable,
+							Data: map[string][]byte{
+								corev1.TLSCertKey:       exampleBundle.CertBytes,
+								corev1.TLSPrivateKeyKey: []byte("test-key"),
+								cmmeta.TLSCAKey:         []byte("test-ca"),
+							},
+							Type: corev1.SecretTypeTLS,
+						},
+					)),
+				},
+			},
+			expectedErr: false,
+		},
+
+		"if immutable secret exists with the same data, update its metadata only": {
+			certificate: immutableCert,
+			SecretData:  SecretData{Certificate: exampleBundle.CertBytes, CA: []byte("test-ca"), PrivateKey: []byte("test-key")},
+			builder: &testpkg.Builder{
+				KubeObjects: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: gen.DefaultTestNamespace,
+							Name:      "output",
+						},
+						Immutable: &immutable,
+						Data: map[string][]byte{
+							corev1.TLSCertKey:       exampleBundle.CertBytes,
+							corev1.TLSPrivateKeyKey: []byte("test-key"),
+							cmmeta.TLSCAKey:         []byte("test-ca"),
+						},
+						Type: corev1.SecretTypeTLS,
+					},
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						gen.DefaultTestNamespace,
+						&corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace:   gen.DefaultTestNamespace,
+								Name:        "output",
+								Annotations: expectedAnnotations,
+							},
+							Immutable: &immutable,
+							Data: map[string][]byte{
+								corev1.TLSCertKey:       exampleBundle.CertBytes,
+								corev1.TLSPrivateKeyKey: []byte("test-key"),
+								cmmeta.TLSCAKey:         []byte("test-ca"),
+							},
+							Type: corev1.SecretTypeTLS,
+						},
+					)),
+				},
+			},
+			expectedErr: false,
+		},
+
+		"if secret does not exist, create new Secret with the certificate and key duplicated under additional keys": {
+			certificate: aliasedCert,
+			SecretData:  SecretData{Certificate: exampleBundle.CertBytes, CA: []byte("test-ca"), PrivateKey: []byte("test-key")},
+			builder: &testpkg.Builder{
+				KubeObjects: []runtime.Object{},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewCreateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						gen.DefaultTestNamespace,
+						&corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace:   gen.DefaultTestNamespace,
+								Name:        "output",
+								Annotations: expectedAnnotations,
+							},
+							Data: map[string][]byte{
+								corev1.TLSCertKey:       exampleBundle.CertBytes,
+								corev1.TLSPrivateKeyKey: []byte("test-key"),
+								cmmeta.TLSCAKey:         []byte("test-ca"),
+								"server.crt":            exampleBundle.CertBytes,
+								"cert.pem":              exampleBundle.CertBytes,
+								"server.key":            []byte("test-key"),
+							},
+							Type: corev1.SecretTypeTLS,
+						},
+					)),
+				},
+			},
+			expectedErr: false,
+		},
+
+		"if secret does exist, update the additional keys with the renewed certificate and key": {
+			certificate: aliasedCert,
+			SecretData:  SecretData{Certificate: exampleBundle.CertBytes, CA: []byte("test-ca"), PrivateKey: []byte("test-key")},
+			builder: &testpkg.Builder{
+				KubeObjects: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace:   gen.DefaultTestNamespace,
+							Name:        "output",
+							Annotations: expectedAnnotations,
+						},
+						Data: map[string][]byte{
+							corev1.TLSCertKey:       []byte("foo"),
+							corev1.TLSPrivateKeyKey: []byte("foo"),
+							cmmeta.TLSCAKey:         []byte("foo"),
+							"server.crt":            []byte("foo"),
+							"cert.pem":              []byte("foo"),
+							"server.key":            []byte("foo"),
+						},
+						Type: corev1.SecretTypeTLS,
+					},
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						gen.DefaultTestNamespace,
+						&corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace:   gen.DefaultTestNamespace,
+								Name:        "output",
+								Annotations: expectedAnnotations,
+							},
+							Data: map[string][]byte{
+								corev1.TLSCertKey:       exampleBundle.CertBytes,
+								corev1.TLSPrivateKeyKey: []byte("test-key"),
+								cmmeta.TLSCAKey:         []byte("test-ca"),
+								"server.crt":            exampleBundle.CertBytes,
+								"cert.pem":              exampleBundle.CertBytes,
+								"server.key":            []byte("test-key"),
+							},
+							Type: corev1.SecretTypeTLS,
+						},
+					)),
+				},
+			},
+			expectedErr: false,
+		},
+
+		"if secret does not exist, create new Secret with the secretTemplate labels and annotations": {
+			certificate: gen.CertificateFrom(exampleBundle.Certificate,
+				gen.SetCertificateSecretTemplate(cmapi.CertificateSecretTemplate{
+					Labels:      map[string]string{"foo": "bar"},
+					Annotations: map[string]string{"example.com/owner": "team-a"},
+				}),
+			),
+			SecretData: SecretData{Certificate: exampleBundle.CertBytes, CA: []byte("test-ca"), PrivateKey: []byte("test-key")},
+			builder: &testpkg.Builder{
+				KubeObjects: []runtime.Object{},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewCreateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						gen.DefaultTestNamespace,
+						&corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace: gen.DefaultTestNamespace,
+								Name:      "output",
+								Labels:    map[string]string{"foo": "bar"},
+								Annotations: func() map[string]string {
+									annotations := map[string]string{"example.com/owner": "team-a"}
+									for k, v := range expectedAnnotations {
+										annotations[k] = v
+									}
+									return annotations
+								}(),
+							},
+							Data: map[string][]byte{
+								corev1.TLSCertKey:       exampleBundle.CertBytes,
+								corev1.TLSPrivateKeyKey: []byte("test-key"),
+								cmmeta.TLSCAKey:         []byte("test-ca"),
+							},
+							Type: corev1.SecretTypeTLS,
+						},
+					)),
+				},
+			},
+			expectedErr: false,
+		},
+
+		"if secret does not exist, create new Secret with the secretTemplate owner references in addition to the Certificate owner": {
+			certificate: ownedCert,
+			certificateOptions: controllerpkg.CertificateOptions{
+				EnableOwnerRef: true,
+			},
+			SecretData: SecretData{Certificate: exampleBundle.CertBytes, CA: []byte("test-ca"), PrivateKey: []byte("test-key")},
+			builder: &testpkg.Builder{
+				KubeObjects: []runtime.Object{},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewCreateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						gen.DefaultTestNamespace,
+						&corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace:   gen.DefaultTestNamespace,
+								Name:        "output",
+								Annotations: expectedAnnotations,
+								OwnerReferences: []metav1.OwnerReference{
+									*metav1.NewControllerRef(ownedCert, certificateGvk),
+									appOwnerRef,
+								},
+							},
+							Data: map[string][]byte{
+								corev1.TLSCertKey:       exampleBundle.CertBytes,
+								corev1.TLSPrivateKeyKey: []byte("test-key"),
+								cmmeta.TLSCAKey:         []byte("test-ca"),
+							},
+							Type: corev1.SecretTypeTLS,
+						},
+					)),
+				},
+			},
+			expectedErr: false,
+		},
+
+		"if secret does exist, keep its owner references and do not duplicate the secretTemplate owner references": {
+			certificate: ownedCert,
+			SecretData:  SecretData{Certificate: exampleBundle.CertBytes, CA: []byte("test-ca"), PrivateKey: []byte("test-key")},
+			builder: &testpkg.Builder{
+				KubeObjects: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: gen.DefaultTestNamespace,
+							Name:      "output",
+							OwnerReferences: []metav1.OwnerReference{
+								{APIVersion: "example.com/v1", Kind: "Other", Name: "other", UID: "other-uid"},
+								appOwnerRef,
+							},
+						},
+						Data: map[string][]byte{
+							corev1.TLSCertKey:       []byte("foo"),
+							corev1.TLSPrivateKeyKey: []byte("foo"),
+							cmmeta.TLSCAKey:         []byte("foo"),
+						},
+						Type: corev1.SecretTypeTLS,
+					},
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						gen.DefaultTestNamespace,
+						&corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace:   gen.DefaultTestNamespace,
+								Name:        "output",
+								Annotations: expectedAnnotations,
+								OwnerReferences: []metav1.OwnerReference{
+									{APIVersion: "example.com/v1", Kind: "Other", Name: "other", UID: "other-uid"},
+									appOwnerRef,
+								},
+							},
+							Data: map[string][]byte{
+								corev1.TLSCertKey:       exampleBundle.CertBytes,
+								corev1.TLSPrivateKeyKey: []byte("test-key"),
+								cmmeta.TLSCAKey:         []byte("test-ca"),
+							},
+							Type: corev1.SecretTypeTLS,
+						},
+					)),
+				},
+			},
+			expectedErr: false,
+		},
 	}
 
 	// TODO: add to these tests once the JKS/PKCS12 support is updated
@@ -309,3 +720,118 @@ func TestSecretsManager(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateKeystores(t *testing.T) {
+	baseCert := gen.Certificate("test",
+		gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "ca-issuer", Kind: "Issuer", Group: "foo.io"}),
+		gen.SetCertificateSecretName("output"),
+		gen.SetCertificateDNSNames("example.com"),
+	)
+	exampleBundle := internaltest.MustCreateCryptoBundle(t, baseCert, fixedClock)
+	pkcs12Cert := gen.CertificateFrom(exampleBundle.Certificate, func(crt *cmapi.Certificate) {
+		crt.Spec.Keystores = &cmapi.CertificateKeystores{
+			PKCS12: &cmapi.PKCS12Keystore{
+				Create: true,
+				PasswordSecretRef: cmmeta.SecretKeySelector{
+					LocalObjectReference: cmmeta.LocalObjectReference{Name: "keystore-password"},
+					Key:                  "password",
+				},
+			},
+		}
+	})
+
+	passwordSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: gen.DefaultTestNamespace, Name: "keystore-password"},
+		Data:       map[string][]byte{"password": []byte("new-password")},
+	}
+	secretWithKeystore := func(password string) *corev1.Secret {
+		keystore, err := encodePKCS12Keystore("", password, exampleBundle.PrivateKeyBytes, exampleBundle.CertBytes, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: gen.DefaultTestNamespace, Name: "output"},
+			Data: map[string][]byte{
+				corev1.TLSCertKey:       exampleBundle.CertBytes,
+				corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+				pkcs12SecretKey:         keystore,
+			},
+			Type: corev1.SecretTypeTLS,
+		}
+	}
+
+	tests := map[string]struct {
+		certificate     *cmapi.Certificate
+		existingObjects []runtime.Object
+		expectedActions []testpkg.Action
+		expectedErr     bool
+	}{
+		"if the Certificate does not request a PKCS12 keystore, do nothing": {
+			certificate:     exampleBundle.Certificate,
+			existingObjects: []runtime.Object{secretWithKeystore("old-password"), passwordSecret},
+		},
+		"if the Secret does not exist, do nothing": {
+			certificate:     pkcs12Cert,
+			existingObjects: []runtime.Object{passwordSecret},
+		},
+		"if the keystore is encrypted with the current password, do nothing": {
+			certificate:     pkcs12Cert,
+			existingObjects: []runtime.Object{secretWithKeystore("new-password"), passwordSecret},
+		},
+		"if the password Secret does not exist, return an error": {
+			certificate:     pkcs12Cert,
+			existingObjects: []runtime.Object{secretWithKeystore("old-password")},
+			expectedErr:     true,
+		},
+		"if the keystore is encrypted with a previous password, re-encode it with the current password": {
+			certificate:     pkcs12Cert,
+			existingObjects: []runtime.Object{secretWithKeystore("old-password"), passwordSecret},
+			expectedActions: []testpkg.Action{
+				testpkg.NewCustomMatch(coretesting.NewUpdateAction(
+					corev1.SchemeGroupVersion.WithResource("secrets"),
+					gen.DefaultTestNamespace,
+					secretWithKeystore("new-password"),
+				), func(exp, act coretesting.Action) error {
+					if !exp.Matches(act.GetVerb(), act.GetResource().Resource) {
+						return fmt.Errorf("unexpected action %s %s", act.GetVerb(), act.GetResource().Resource)
+					}
+					secret := act.(coretesting.UpdateAction).GetObject().(*corev1.Secret)
+					if _, _, err := pkcs12.Decode(secret.Data[pkcs12SecretKey], "new-password"); err != nil {
+						return fmt.Errorf("failed to decode keystore with the current password: %v", err)
+					}
+					return nil
+				}),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := &testpkg.Builder{
+				T:               t,
+				Clock:           fixedClock,
+				KubeObjects:     test.existingObjects,
+				ExpectedActions: test.expectedActions,
+			}
+			builder.Init()
+			defer builder.Stop()
+
+			testManager := New(
+				builder.Client,
+				builder.KubeSharedInformerFactory.Core().V1().Secrets().Lister(),
+				false,
+			)
+
+			builder.Start()
+
+			err := testManager.UpdateKeystores(context.Background(), test.certificate)
+			if err != nil && !test.expectedErr {
+				t.Errorf("expected to not get an error, but got: %v", err)
+			}
+			if err == nil && test.expectedErr {
+				t.Errorf("expected to get an error but did not get one")
+			}
+			builder.CheckAndFinish(err)
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"crypto"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -52,6 +53,9 @@ import (
 
 const (
 	ControllerName = "certificates-issuing"
+
+	reasonSecretSizeExceeded = "SecretSizeExceeded"
+	reasonIssuanceTimeout    = "IssuanceTimeout"
 )
 
 type localTemporarySignerFn func(crt *cmapi.Certificate, pk []byte) ([]byte, error)
@@ -68,10 +72,32 @@ type controller struct {
 
 	client cmclient.Interface
 
+	// queue is used to re-sync a Certificate once its issuance timeout
+	// elapses.
+	queue workqueue.RateLimitingInterface
+
 	// secretManager is used to create and update Secrets with certificate and key data
 	secretsManager *secretsmanager.SecretsManager
 	// localTemporarySigner signs a certificate that is stored temporarily
 	localTemporarySigner localTemporarySignerFn
+
+	// renewalHistoryLimit is the maximum number of records kept in the
+	// status.renewalHistory of a Certificate. If zero, no history is kept.
+	renewalHistoryLimit int
+
+	// warnSecretSize is the total size in bytes of the data stored in an
+	// issued Secret above which a Warning event is recorded. If zero, no
+	// warning is recorded.
+	warnSecretSize int
+
+	// additionalTrustBundle is a PEM encoded bundle of CA certificates that
+	// is appended to the CA data of every issued Secret, if set.
+	additionalTrustBundle []byte
+
+	// defaultIssuanceTimeout is how long an issuance may remain in progress
+	// before it is marked as failed, for Certificates that do not set
+	// spec.issuanceTimeout. If zero, such issuances never time out.
+	defaultIssuanceTimeout time.Duration
 }
 
 func NewController(
@@ -108,6 +134,11 @@ func NewController(
 		WorkFunc: certificates.EnqueueCertificatesForResourceUsingPredicates(log, queue, certificateInformer.Lister(), labels.Everything(),
 			predicate.ExtractResourceName(predicate.CertificateSecretName)),
 	})
+	secretsInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		// Issuer reconciles on changes to the Secret named `spec.keystores.pkcs12.passwordSecretRef.name`
+		WorkFunc: certificates.EnqueueCertificatesForResourceUsingPredicates(log, queue, certificateInformer.Lister(), labels.Everything(),
+			predicate.ExtractResourceName(predicate.CertificatePKCS12KeystorePasswordSecretName)),
+	})
 
 	// build a list of InformerSynced functions that will be returned by the Register method.
 	// the controller will only begin processing items once all of these informers have synced.
@@ -120,7 +151,9 @@ func NewController(
 	secretsManager := secretsmanager.New(
 		kubeClient,
 		secretsInformer.Lister(),
-		certificateControllerOptions.EnableOwnerRef,
+		// The Secret is deleted by the secretcleanup controller rather than
+		// by the garbage collector if a deletion grace period is configured.
+		certificateControllerOptions.EnableOwnerRef && certificateControllerOptions.SecretDeletionGracePeriod == 0,
 	)
 
 	return &controller{
@@ -128,10 +161,15 @@ func NewController(
 		certificateRequestLister: certificateRequestInformer.Lister(),
 		secretLister:             secretsInformer.Lister(),
 		client:                   client,
+		queue:                    queue,
 		recorder:                 recorder,
 		clock:                    clock,
 		secretsManager:           secretsManager,
 		localTemporarySigner:     certificates.GenerateLocallySignedTemporaryCertificate,
+		renewalHistoryLimit:      certificateControllerOptions.RenewalHistoryLimit,
+		warnSecretSize:           certificateControllerOptions.WarnSecretSize,
+		additionalTrustBundle:    certificateControllerOptions.AdditionalTrustBundle,
+		defaultIssuanceTimeout:   certificateControllerOptions.DefaultIssuanceTimeout,
 	}, queue, mustSync
 }
 
@@ -163,8 +201,14 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		Type:   cmapi.CertificateConditionIssuing,
 		Status: cmmeta.ConditionTrue,
 	}) {
-		// Do nothing if an issuance is not in progress.
-		return nil
+		// If an issuance is not in progress, only ensure the Secret's metadata
+		// matches the secretTemplate and its PKCS12 keystore is encrypted with
+		// the current password. Neither requires the certificate to be
+		// re-issued.
+		if err := c.secretsManager.UpdateMetadata(ctx, crt); err != nil {
+			return err
+		}
+		return c.secretsManager.UpdateKeystores(ctx, crt)
 	}
 
 	if crt.Status.NextPrivateKeySecretName == nil ||
@@ -249,7 +293,11 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 	cond := apiutil.GetCertificateRequestCondition(req, cmapi.CertificateRequestConditionReady)
 	if cond == nil {
 		if apiutil.CertificateRequestIsDenied(req) {
-			return c.failIssueCertificate(ctx, log, crt, apiutil.GetCertificateRequestCondition(req, cmapi.CertificateRequestConditionDenied))
+			return c.failIssueCertificate(ctx, log, crt, req, apiutil.GetCertificateRequestCondition(req, cmapi.CertificateRequestConditionDenied))
+		}
+
+		if timedOut, err := c.failIssuanceIfTimedOut(ctx, log, crt, req); err != nil || timedOut {
+			return err
 		}
 
 		log.V(logf.DebugLevel).Info("CertificateRequest does not have Ready condition, waiting...")
@@ -259,7 +307,7 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 	// If the certificate request has failed, set the last failure time to now,
 	// and set the Issuing status condition to False with reason.
 	if cond.Reason == cmapi.CertificateRequestReasonFailed {
-		return c.failIssueCertificate(ctx, log, crt, apiutil.GetCertificateRequestCondition(req, cmapi.CertificateRequestConditionReady))
+		return c.failIssueCertificate(ctx, log, crt, req, apiutil.GetCertificateRequestCondition(req, cmapi.CertificateRequestConditionReady))
 	}
 
 	// If public key does not match, do nothing (requestmanager will handle this).
@@ -282,6 +330,12 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		return c.issueCertificate(ctx, nextRevision, crt, req, pk)
 	}
 
+	// If the issuance has been in progress for longer than the issuance
+	// timeout, mark it as failed so that it is retried later with backoff.
+	if timedOut, err := c.failIssuanceIfTimedOut(ctx, log, crt, req); err != nil || timedOut {
+		return err
+	}
+
 	// Issue temporary certificate if needed. If a certificate was issued, then
 	// return early - we will sync again since the target Secret has been
 	// updated.
@@ -297,7 +351,7 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 // failIssueCertificate will mark the Issuing condition of this Certificate as
 // failed, and log an appropriate event. The reason and message of the
 // condition will be that of the CertificateRequest condition passed.
-func (c *controller) failIssueCertificate(ctx context.Context, log logr.Logger, crt *cmapi.Certificate, condition *cmapi.CertificateRequestCondition) error {
+func (c *controller) failIssueCertificate(ctx context.Context, log logr.Logger, crt *cmapi.Certificate, req *cmapi.CertificateRequest, condition *cmapi.CertificateRequestCondition) error {
 	nowTime := metav1.NewTime(c.clock.Now())
 	crt.Status.LastFailureTime = &nowTime
 
@@ -310,6 +364,7 @@ func (c *controller) failIssueCertificate(ctx context.Context, log logr.Logger,
 
 	crt = crt.DeepCopy()
 	apiutil.SetCertificateCondition(crt, crt.Generation, cmapi.CertificateConditionIssuing, cmmeta.ConditionFalse, reason, message)
+	c.recordRenewal(crt, req, cmapi.CertificateRenewalResultFailed)
 
 	_, err := c.client.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
 	if err != nil {
@@ -321,6 +376,41 @@ func (c *controller) failIssueCertificate(ctx context.Context, log logr.Logger,
 	return nil
 }
 
+// failIssuanceIfTimedOut marks the Issuing condition of this Certificate as
+// failed if the issuance has been in progress for longer than its issuance
+// timeout, and returns true if it did so. If the timeout has not yet elapsed,
+// the Certificate is re-queued for when it will.
+func (c *controller) failIssuanceIfTimedOut(ctx context.Context, log logr.Logger, crt *cmapi.Certificate, req *cmapi.CertificateRequest) (bool, error) {
+	timeout := c.defaultIssuanceTimeout
+	if crt.Spec.IssuanceTimeout != nil {
+		timeout = crt.Spec.IssuanceTimeout.Duration
+	}
+	if timeout <= 0 {
+		return false, nil
+	}
+
+	cond := apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionIssuing)
+	if cond == nil || cond.LastTransitionTime == nil {
+		return false, nil
+	}
+
+	if remaining := cond.LastTransitionTime.Add(timeout).Sub(c.clock.Now()); remaining > 0 {
+		key, err := controllerpkg.KeyFunc(crt)
+		if err != nil {
+			return false, err
+		}
+		c.queue.AddAfter(key, remaining)
+		return false, nil
+	}
+
+	log.V(logf.DebugLevel).Info("issuance has exceeded its timeout", "timeout", timeout)
+
+	return true, c.failIssueCertificate(ctx, log, crt, req, &cmapi.CertificateRequestCondition{
+		Reason:  reasonIssuanceTimeout,
+		Message: fmt.Sprintf("CertificateRequest %q was not completed within the issuance timeout of %s", req.Name, timeout),
+	})
+}
+
 // issueCertificate will ensure the public key of the CSR matches the signed
 // certificate, and then store the certificate, CA and private key into the
 // Secret in the appropriate format type.
@@ -337,7 +427,7 @@ func (c *controller) issueCertificate(ctx context.Context, nextRevision int, crt
 	secretData := secretsmanager.SecretData{
 		PrivateKey:  pkData,
 		Certificate: req.Status.Certificate,
-		CA:          req.Status.CA,
+		CA:          c.caWithTrustBundle(ctx, req.Status.CA),
 	}
 
 	err = c.secretsManager.UpdateData(ctx, crt, secretData)
@@ -354,6 +444,8 @@ func (c *controller) issueCertificate(ctx context.Context, nextRevision int, crt
 	//Clear status.lastFailureTime (if set)
 	crt.Status.LastFailureTime = nil
 
+	c.recordRenewal(crt, req, cmapi.CertificateRenewalResultIssued)
+
 	_, err = c.client.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
 	if err != nil {
 		return err
@@ -362,9 +454,72 @@ func (c *controller) issueCertificate(ctx context.Context, nextRevision int, crt
 	message := "The certificate has been successfully issued"
 	c.recorder.Event(crt, corev1.EventTypeNormal, "Issuing", message)
 
+	c.warnIfSecretTooLarge(crt, secretData)
+
 	return nil
 }
 
+// caWithTrustBundle returns the given CA data returned by the issuer with the
+// additional trust bundle appended, omitting any certificates that the issuer
+// already returned. If the issuer's CA data cannot be parsed it is returned
+// unchanged, so that a malformed CA does not prevent issuance.
+func (c *controller) caWithTrustBundle(ctx context.Context, ca []byte) []byte {
+	if len(c.additionalTrustBundle) == 0 {
+		return ca
+	}
+
+	withBundle, err := utilpki.AppendCertificatesPEM(ca, c.additionalTrustBundle)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "failed to append additional trust bundle to CA data, storing the issuer's CA data only")
+		return ca
+	}
+	return withBundle
+}
+
+// warnIfSecretTooLarge records a Warning event on the Certificate if the
+// total size of the data stored in its Secret exceeds warnSecretSize, as some
+// ingress controllers fail to load large certificates, such as those with
+// long chains. The Secret has already been updated, so issuance is not
+// affected.
+func (c *controller) warnIfSecretTooLarge(crt *cmapi.Certificate, data secretsmanager.SecretData) {
+	if c.warnSecretSize <= 0 {
+		return
+	}
+
+	size := len(data.PrivateKey) + len(data.Certificate) + len(data.CA)
+	if size <= c.warnSecretSize {
+		return
+	}
+
+	c.recorder.Eventf(crt, corev1.EventTypeWarning, reasonSecretSizeExceeded,
+		"The certificate data stored in Secret %q is %d bytes, which exceeds the configured warning threshold of %d bytes and may not be loaded by some ingress controllers",
+		crt.Spec.SecretName, size, c.warnSecretSize)
+}
+
+// recordRenewal appends a record of the outcome of the given
+// CertificateRequest to the renewal history of the Certificate, dropping the
+// oldest records so that at most renewalHistoryLimit are kept.
+func (c *controller) recordRenewal(crt *cmapi.Certificate, req *cmapi.CertificateRequest, result cmapi.CertificateRenewalResult) {
+	if c.renewalHistoryLimit <= 0 {
+		return
+	}
+
+	record := cmapi.CertificateRenewalRecord{
+		Time:      metav1.NewTime(c.clock.Now()),
+		Result:    result,
+		IssuerRef: req.Spec.IssuerRef,
+	}
+	if revision, err := strconv.Atoi(req.Annotations[cmapi.CertificateRequestRevisionAnnotationKey]); err == nil {
+		record.Revision = &revision
+	}
+
+	history := append(crt.Status.RenewalHistory, record)
+	if len(history) > c.renewalHistoryLimit {
+		history = history[len(history)-c.renewalHistoryLimit:]
+	}
+	crt.Status.RenewalHistory = history
+}
+
 // controllerWrapper wraps the `controller` structure to make it implement
 // the controllerpkg.queueingController interface
 type controllerWrapper struct {
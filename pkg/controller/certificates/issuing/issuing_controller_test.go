@@ -53,6 +53,12 @@ func TestIssuingController(t *testing.T) {
 
 		certificate *cmapi.Certificate
 
+		renewalHistoryLimit   int
+		warnSecretSize        int
+		additionalTrustBundle []byte
+
+		defaultIssuanceTimeout time.Duration
+
 		expectedErr bool
 	}
 
@@ -71,6 +77,11 @@ func TestIssuingController(t *testing.T) {
 
 	exampleBundleAlt := internaltest.MustCreateCryptoBundle(t, baseCert.DeepCopy(), fixedClock)
 
+	// certificates used as the CA returned by the issuer and as the
+	// additional trust bundle configured on the controller
+	issuerCA := exampleBundleAlt.CertBytes
+	orgTrustBundle := internaltest.MustCreateCryptoBundle(t, baseCert.DeepCopy(), fixedClock).CertBytes
+
 	issuingCert := gen.CertificateFrom(baseCert.DeepCopy(),
 		gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
 			Type:               cmapi.CertificateConditionIssuing,
@@ -81,6 +92,49 @@ func TestIssuingController(t *testing.T) {
 
 	metaFixedClockStart := metav1.NewTime(fixedClockStart)
 
+	renewalRecord := func(revision int, result cmapi.CertificateRenewalResult, t metav1.Time) cmapi.CertificateRenewalRecord {
+		return cmapi.CertificateRenewalRecord{
+			Time:      t,
+			Result:    result,
+			IssuerRef: cmmeta.ObjectReference{Name: "ca-issuer", Kind: "Issuer", Group: "foo.io"},
+			Revision:  &revision,
+		}
+	}
+	earlierTime := metav1.NewTime(fixedClockStart.Add(-time.Hour))
+
+	// issuingSince returns a Certificate with an issuance that started d ago.
+	issuingSince := func(d time.Duration, mods ...gen.CertificateModifier) *cmapi.Certificate {
+		startTime := metav1.NewTime(fixedClockStart.Add(-d))
+		crt := gen.CertificateFrom(baseCert,
+			gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+				Type:               cmapi.CertificateConditionIssuing,
+				Status:             cmmeta.ConditionTrue,
+				LastTransitionTime: &startTime,
+				ObservedGeneration: 3,
+			}),
+		)
+		return gen.CertificateFrom(crt, mods...)
+	}
+	pendingRequest := gen.CertificateRequestFrom(exampleBundle.CertificateRequest,
+		gen.AddCertificateRequestAnnotations(map[string]string{
+			cmapi.CertificateRequestRevisionAnnotationKey: "2", // Current Certificate revision=1
+		}),
+		gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+			Type:   cmapi.CertificateRequestConditionReady,
+			Status: cmmeta.ConditionFalse,
+			Reason: cmapi.CertificateRequestReasonPending,
+		}),
+	)
+	nextPrivateKeySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nextPrivateKeySecretName,
+			Namespace: exampleBundle.Certificate.Namespace,
+		},
+		Data: map[string][]byte{
+			corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+		},
+	}
+
 	tests := map[string]testT{
 		"if certificate is not in Issuing state, then do nothing": {
 			certificate: exampleBundle.Certificate,
@@ -94,6 +148,93 @@ func TestIssuingController(t *testing.T) {
 			expectedErr: false,
 		},
 
+		"if certificate is not in Issuing state and the secretTemplate metadata has changed, update the Secret metadata without changing its data": {
+			certificate: exampleBundle.Certificate,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{
+					gen.CertificateFrom(baseCert,
+						gen.SetCertificateSecretTemplate(cmapi.CertificateSecretTemplate{
+							Labels:      map[string]string{"foo": "bar"},
+							Annotations: map[string]string{"example.com/owner": "team-b"},
+						}),
+					),
+				},
+				KubeObjects: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: exampleBundle.Certificate.Namespace,
+							Name:      "output",
+							Labels:    map[string]string{"existing": "label"},
+							Annotations: map[string]string{
+								cmapi.CertificateNameKey: "test",
+								"example.com/owner":      "team-a",
+							},
+						},
+						Data: map[string][]byte{
+							corev1.TLSCertKey:       exampleBundle.CertBytes,
+							corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+						},
+						Type: corev1.SecretTypeTLS,
+					},
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						exampleBundle.Certificate.Namespace,
+						&corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace: exampleBundle.Certificate.Namespace,
+								Name:      "output",
+								Labels: map[string]string{
+									"existing": "label",
+									"foo":      "bar",
+								},
+								Annotations: map[string]string{
+									cmapi.CertificateNameKey: "test",
+									"example.com/owner":      "team-b",
+								},
+							},
+							Data: map[string][]byte{
+								corev1.TLSCertKey:       exampleBundle.CertBytes,
+								corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+							},
+							Type: corev1.SecretTypeTLS,
+						},
+					)),
+				},
+			},
+			expectedErr: false,
+		},
+
+		"if certificate is not in Issuing state and the Secret matches the secretTemplate metadata, then do nothing": {
+			certificate: exampleBundle.Certificate,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{
+					gen.CertificateFrom(baseCert,
+						gen.SetCertificateSecretTemplate(cmapi.CertificateSecretTemplate{
+							Labels: map[string]string{"foo": "bar"},
+						}),
+					),
+				},
+				KubeObjects: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: exampleBundle.Certificate.Namespace,
+							Name:      "output",
+							Labels:    map[string]string{"foo": "bar"},
+						},
+						Data: map[string][]byte{
+							corev1.TLSCertKey:       exampleBundle.CertBytes,
+							corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+						},
+						Type: corev1.SecretTypeTLS,
+					},
+				},
+				ExpectedActions: []testpkg.Action{},
+			},
+			expectedErr: false,
+		},
+
 		"if certificate is an Issuing state but is set to False, then do nothing": {
 			certificate: exampleBundle.Certificate,
 			builder: &testpkg.Builder{
@@ -445,8 +586,9 @@ func TestIssuingController(t *testing.T) {
 			expectedErr: false,
 		},
 
-		"if certificate is in Issuing state, one CertificateRequests, and is ready, store the signed certificate, ca, and private key to an existing secret, and log an event": {
-			certificate: exampleBundle.Certificate,
+		"if certificate is in Issuing state, one CertificateRequests, and is ready, and the issued data exceeds the warning size, store it and log a warning event": {
+			certificate:    exampleBundle.Certificate,
+			warnSecretSize: 10,
 			builder: &testpkg.Builder{
 				CertManagerObjects: []runtime.Object{
 					gen.CertificateFrom(issuingCert),
@@ -465,16 +607,6 @@ func TestIssuingController(t *testing.T) {
 							corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
 						},
 					},
-					&corev1.Secret{
-						ObjectMeta: metav1.ObjectMeta{
-							Namespace: exampleBundle.Certificate.Namespace,
-							Name:      "output",
-							Annotations: map[string]string{
-								"my-custom": "annotation",
-							},
-						},
-						Type: corev1.SecretTypeTLS,
-					},
 				},
 				ExpectedActions: []testpkg.Action{
 					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
@@ -485,7 +617,7 @@ func TestIssuingController(t *testing.T) {
 							gen.SetCertificateRevision(2),
 						),
 					)),
-					testpkg.NewAction(coretesting.NewUpdateAction(
+					testpkg.NewAction(coretesting.NewCreateAction(
 						corev1.SchemeGroupVersion.WithResource("secrets"),
 						exampleBundle.Certificate.Namespace,
 						&corev1.Secret{
@@ -493,11 +625,10 @@ func TestIssuingController(t *testing.T) {
 								Namespace: exampleBundle.Certificate.Namespace,
 								Name:      "output",
 								Annotations: map[string]string{
-									"my-custom":                    "annotation",
 									cmapi.CertificateNameKey:       "test",
-									cmapi.IssuerGroupAnnotationKey: "foo.io",
 									cmapi.IssuerKindAnnotationKey:  "Issuer",
 									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
+									cmapi.IssuerGroupAnnotationKey: "foo.io",
 									cmapi.CommonNameAnnotationKey:  "",
 									cmapi.AltNamesAnnotationKey:    "example.com",
 									cmapi.IPSANAnnotationKey:       "",
@@ -514,21 +645,20 @@ func TestIssuingController(t *testing.T) {
 				},
 				ExpectedEvents: []string{
 					"Normal Issuing The certificate has been successfully issued",
+					fmt.Sprintf("Warning SecretSizeExceeded The certificate data stored in Secret \"output\" is %d bytes, which exceeds the configured warning threshold of 10 bytes and may not be loaded by some ingress controllers",
+						len(exampleBundle.CertificateRequestReady.Status.Certificate)+len(exampleBundle.PrivateKeyBytes)),
 				},
 			},
 			expectedErr: false,
 		},
 
-		"if certificate is in Issuing state with temp annotation, one CertificateRequest Pending, no target Secret, create target secret with temporary certificate": {
-			certificate: exampleBundle.Certificate,
+		"if an additional trust bundle is configured, store it as the ca of the issued secret": {
+			certificate:           exampleBundle.Certificate,
+			additionalTrustBundle: orgTrustBundle,
 			builder: &testpkg.Builder{
 				CertManagerObjects: []runtime.Object{
-					gen.CertificateFrom(issuingCert,
-						gen.AddCertificateAnnotations(map[string]string{
-							cmapi.IssueTemporaryCertificateAnnotation: "true",
-						}),
-					),
-					gen.CertificateRequestFrom(exampleBundle.CertificateRequestPending,
+					gen.CertificateFrom(issuingCert),
+					gen.CertificateRequestFrom(exampleBundle.CertificateRequestReady,
 						gen.AddCertificateRequestAnnotations(map[string]string{
 							cmapi.CertificateRequestRevisionAnnotationKey: "2", // Current Certificate revision=1
 						}),
@@ -545,6 +675,14 @@ func TestIssuingController(t *testing.T) {
 					},
 				},
 				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificates"),
+						"status",
+						exampleBundle.Certificate.Namespace,
+						gen.CertificateFrom(exampleBundle.Certificate,
+							gen.SetCertificateRevision(2),
+						),
+					)),
 					testpkg.NewAction(coretesting.NewCreateAction(
 						corev1.SchemeGroupVersion.WithResource("secrets"),
 						exampleBundle.Certificate.Namespace,
@@ -554,9 +692,9 @@ func TestIssuingController(t *testing.T) {
 								Name:      "output",
 								Annotations: map[string]string{
 									cmapi.CertificateNameKey:       "test",
-									cmapi.IssuerGroupAnnotationKey: "foo.io",
 									cmapi.IssuerKindAnnotationKey:  "Issuer",
 									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
+									cmapi.IssuerGroupAnnotationKey: "foo.io",
 									cmapi.CommonNameAnnotationKey:  "",
 									cmapi.AltNamesAnnotationKey:    "example.com",
 									cmapi.IPSANAnnotationKey:       "",
@@ -564,33 +702,32 @@ func TestIssuingController(t *testing.T) {
 								},
 							},
 							Data: map[string][]byte{
-								corev1.TLSCertKey:       exampleBundle.LocalTemporaryCertificateBytes,
+								corev1.TLSCertKey:       exampleBundle.CertificateRequestReady.Status.Certificate,
 								corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+								cmmeta.TLSCAKey:         orgTrustBundle,
 							},
 							Type: corev1.SecretTypeTLS,
 						},
 					)),
 				},
 				ExpectedEvents: []string{
-					"Normal Issuing Issued temporary certificate",
+					"Normal Issuing The certificate has been successfully issued",
 				},
 			},
 			expectedErr: false,
 		},
 
-		"if certificate is in Issuing state with temp annotation, one CertificateRequest Pending, a target Secret but with no data, issue temporary certificate to that Secret": {
-			certificate: exampleBundle.Certificate,
+		"if an additional trust bundle is configured, append it to the ca returned by the issuer": {
+			certificate:           exampleBundle.Certificate,
+			additionalTrustBundle: orgTrustBundle,
 			builder: &testpkg.Builder{
 				CertManagerObjects: []runtime.Object{
-					gen.CertificateFrom(issuingCert,
-						gen.AddCertificateAnnotations(map[string]string{
-							cmapi.IssueTemporaryCertificateAnnotation: "true",
-						}),
-					),
-					gen.CertificateRequestFrom(exampleBundle.CertificateRequestPending,
+					gen.CertificateFrom(issuingCert),
+					gen.CertificateRequestFrom(exampleBundle.CertificateRequestReady,
 						gen.AddCertificateRequestAnnotations(map[string]string{
 							cmapi.CertificateRequestRevisionAnnotationKey: "2", // Current Certificate revision=1
 						}),
+						gen.SetCertificateRequestCA(issuerCA),
 					)},
 				KubeObjects: []runtime.Object{
 					&corev1.Secret{
@@ -602,20 +739,17 @@ func TestIssuingController(t *testing.T) {
 							corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
 						},
 					},
-					&corev1.Secret{
-						ObjectMeta: metav1.ObjectMeta{
-							Name:      exampleBundle.Certificate.Spec.SecretName,
-							Namespace: exampleBundle.Certificate.Namespace,
-							Annotations: map[string]string{
-								"my-custom": "annotation",
-							},
-						},
-						Data: nil,
-						Type: corev1.SecretTypeTLS,
-					},
 				},
 				ExpectedActions: []testpkg.Action{
-					testpkg.NewAction(coretesting.NewUpdateAction(
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificates"),
+						"status",
+						exampleBundle.Certificate.Namespace,
+						gen.CertificateFrom(exampleBundle.Certificate,
+							gen.SetCertificateRevision(2),
+						),
+					)),
+					testpkg.NewAction(coretesting.NewCreateAction(
 						corev1.SchemeGroupVersion.WithResource("secrets"),
 						exampleBundle.Certificate.Namespace,
 						&corev1.Secret{
@@ -623,11 +757,10 @@ func TestIssuingController(t *testing.T) {
 								Namespace: exampleBundle.Certificate.Namespace,
 								Name:      "output",
 								Annotations: map[string]string{
-									"my-custom":                    "annotation",
 									cmapi.CertificateNameKey:       "test",
-									cmapi.IssuerGroupAnnotationKey: "foo.io",
 									cmapi.IssuerKindAnnotationKey:  "Issuer",
 									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
+									cmapi.IssuerGroupAnnotationKey: "foo.io",
 									cmapi.CommonNameAnnotationKey:  "",
 									cmapi.AltNamesAnnotationKey:    "example.com",
 									cmapi.IPSANAnnotationKey:       "",
@@ -635,33 +768,32 @@ func TestIssuingController(t *testing.T) {
 								},
 							},
 							Data: map[string][]byte{
-								corev1.TLSCertKey:       exampleBundle.LocalTemporaryCertificateBytes,
+								corev1.TLSCertKey:       exampleBundle.CertificateRequestReady.Status.Certificate,
 								corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+								cmmeta.TLSCAKey:         append(append([]byte{}, issuerCA...), orgTrustBundle...),
 							},
 							Type: corev1.SecretTypeTLS,
 						},
 					)),
 				},
 				ExpectedEvents: []string{
-					"Normal Issuing Issued temporary certificate",
+					"Normal Issuing The certificate has been successfully issued",
 				},
 			},
 			expectedErr: false,
 		},
 
-		"if certificate is in Issuing state with temp annotation, one CertificateRequest Pending, a target Secret but with a cert/key that is not the NextPrivateKey, do nothing": {
-			certificate: exampleBundle.Certificate,
+		"if an additional trust bundle is configured and the issuer already returned it, do not duplicate it in the ca of the issued secret": {
+			certificate:           exampleBundle.Certificate,
+			additionalTrustBundle: orgTrustBundle,
 			builder: &testpkg.Builder{
 				CertManagerObjects: []runtime.Object{
-					gen.CertificateFrom(issuingCert,
-						gen.AddCertificateAnnotations(map[string]string{
-							cmapi.IssueTemporaryCertificateAnnotation: "true",
-						}),
-					),
-					gen.CertificateRequestFrom(exampleBundle.CertificateRequestPending,
+					gen.CertificateFrom(issuingCert),
+					gen.CertificateRequestFrom(exampleBundle.CertificateRequestReady,
 						gen.AddCertificateRequestAnnotations(map[string]string{
 							cmapi.CertificateRequestRevisionAnnotationKey: "2", // Current Certificate revision=1
 						}),
+						gen.SetCertificateRequestCA(append(append([]byte{}, issuerCA...), orgTrustBundle...)),
 					)},
 				KubeObjects: []runtime.Object{
 					&corev1.Secret{
@@ -673,37 +805,56 @@ func TestIssuingController(t *testing.T) {
 							corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
 						},
 					},
-					&corev1.Secret{
-						ObjectMeta: metav1.ObjectMeta{
-							Name:      exampleBundle.Certificate.Spec.SecretName,
-							Namespace: exampleBundle.Certificate.Namespace,
-							Annotations: map[string]string{
-								"my-custom": "annotation",
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificates"),
+						"status",
+						exampleBundle.Certificate.Namespace,
+						gen.CertificateFrom(exampleBundle.Certificate,
+							gen.SetCertificateRevision(2),
+						),
+					)),
+					testpkg.NewAction(coretesting.NewCreateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						exampleBundle.Certificate.Namespace,
+						&corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace: exampleBundle.Certificate.Namespace,
+								Name:      "output",
+								Annotations: map[string]string{
+									cmapi.CertificateNameKey:       "test",
+									cmapi.IssuerKindAnnotationKey:  "Issuer",
+									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
+									cmapi.IssuerGroupAnnotationKey: "foo.io",
+									cmapi.CommonNameAnnotationKey:  "",
+									cmapi.AltNamesAnnotationKey:    "example.com",
+									cmapi.IPSANAnnotationKey:       "",
+									cmapi.URISANAnnotationKey:      "",
+								},
 							},
+							Data: map[string][]byte{
+								corev1.TLSCertKey:       exampleBundle.CertificateRequestReady.Status.Certificate,
+								corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+								cmmeta.TLSCAKey:         append(append([]byte{}, issuerCA...), orgTrustBundle...),
+							},
+							Type: corev1.SecretTypeTLS,
 						},
-						Data: map[string][]byte{
-							corev1.TLSCertKey:       exampleBundleAlt.CertBytes,
-							corev1.TLSPrivateKeyKey: exampleBundleAlt.PrivateKeyBytes,
-						},
-						Type: corev1.SecretTypeTLS,
-					},
+					)),
+				},
+				ExpectedEvents: []string{
+					"Normal Issuing The certificate has been successfully issued",
 				},
-				ExpectedActions: []testpkg.Action{},
-				ExpectedEvents:  []string{},
 			},
 			expectedErr: false,
 		},
 
-		"if certificate is in Issuing state with temp annotation, one CertificateRequest Pending, a target Secret with a cert/key that are garbage, issue temporary certificate": {
+		"if certificate is in Issuing state, one CertificateRequests, and is ready, store the signed certificate, ca, and private key to an existing secret, and log an event": {
 			certificate: exampleBundle.Certificate,
 			builder: &testpkg.Builder{
 				CertManagerObjects: []runtime.Object{
-					gen.CertificateFrom(issuingCert,
-						gen.AddCertificateAnnotations(map[string]string{
-							cmapi.IssueTemporaryCertificateAnnotation: "true",
-						}),
-					),
-					gen.CertificateRequestFrom(exampleBundle.CertificateRequestPending,
+					gen.CertificateFrom(issuingCert),
+					gen.CertificateRequestFrom(exampleBundle.CertificateRequestReady,
 						gen.AddCertificateRequestAnnotations(map[string]string{
 							cmapi.CertificateRequestRevisionAnnotationKey: "2", // Current Certificate revision=1
 						}),
@@ -720,19 +871,24 @@ func TestIssuingController(t *testing.T) {
 					},
 					&corev1.Secret{
 						ObjectMeta: metav1.ObjectMeta{
-							Name:      exampleBundle.Certificate.Spec.SecretName,
 							Namespace: exampleBundle.Certificate.Namespace,
+							Name:      "output",
 							Annotations: map[string]string{
 								"my-custom": "annotation",
 							},
 						},
-						Data: map[string][]byte{
-							corev1.TLSPrivateKeyKey: []byte("abc"),
-						},
 						Type: corev1.SecretTypeTLS,
 					},
 				},
 				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificates"),
+						"status",
+						exampleBundle.Certificate.Namespace,
+						gen.CertificateFrom(exampleBundle.Certificate,
+							gen.SetCertificateRevision(2),
+						),
+					)),
 					testpkg.NewAction(coretesting.NewUpdateAction(
 						corev1.SchemeGroupVersion.WithResource("secrets"),
 						exampleBundle.Certificate.Namespace,
@@ -753,7 +909,7 @@ func TestIssuingController(t *testing.T) {
 								},
 							},
 							Data: map[string][]byte{
-								corev1.TLSCertKey:       exampleBundle.LocalTemporaryCertificateBytes,
+								corev1.TLSCertKey:       exampleBundle.CertificateRequestReady.Status.Certificate,
 								corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
 							},
 							Type: corev1.SecretTypeTLS,
@@ -761,13 +917,13 @@ func TestIssuingController(t *testing.T) {
 					)),
 				},
 				ExpectedEvents: []string{
-					"Normal Issuing Issued temporary certificate",
+					"Normal Issuing The certificate has been successfully issued",
 				},
 			},
 			expectedErr: false,
 		},
 
-		"if certificate is in Issuing state with temp annotation, one CertificateRequest Pending, a target Secret with a cert/key with a matching key, do not issue temporary certificate": {
+		"if certificate is in Issuing state with temp annotation, one CertificateRequest Pending, no target Secret, create target secret with temporary certificate": {
 			certificate: exampleBundle.Certificate,
 			builder: &testpkg.Builder{
 				CertManagerObjects: []runtime.Object{
@@ -791,34 +947,612 @@ func TestIssuingController(t *testing.T) {
 							corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
 						},
 					},
-					&corev1.Secret{
-						ObjectMeta: metav1.ObjectMeta{
-							Name:      exampleBundle.Certificate.Spec.SecretName,
-							Namespace: exampleBundle.Certificate.Namespace,
-							Annotations: map[string]string{
-								"my-custom": "annotation",
-							},
-						},
-						Data: map[string][]byte{
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewCreateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						exampleBundle.Certificate.Namespace,
+						&corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace: exampleBundle.Certificate.Namespace,
+								Name:      "output",
+								Annotations: map[string]string{
+									cmapi.CertificateNameKey:       "test",
+									cmapi.IssuerGroupAnnotationKey: "foo.io",
+									cmapi.IssuerKindAnnotationKey:  "Issuer",
+									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
+									cmapi.CommonNameAnnotationKey:  "",
+									cmapi.AltNamesAnnotationKey:    "example.com",
+									cmapi.IPSANAnnotationKey:       "",
+									cmapi.URISANAnnotationKey:      "",
+								},
+							},
+							Data: map[string][]byte{
+								corev1.TLSCertKey:       exampleBundle.LocalTemporaryCertificateBytes,
+								corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+							},
+							Type: corev1.SecretTypeTLS,
+						},
+					)),
+				},
+				ExpectedEvents: []string{
+					"Normal Issuing Issued temporary certificate",
+				},
+			},
+			expectedErr: false,
+		},
+
+		"if certificate is in Issuing state with temp annotation, one CertificateRequest Pending, a target Secret but with no data, issue temporary certificate to that Secret": {
+			certificate: exampleBundle.Certificate,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{
+					gen.CertificateFrom(issuingCert,
+						gen.AddCertificateAnnotations(map[string]string{
+							cmapi.IssueTemporaryCertificateAnnotation: "true",
+						}),
+					),
+					gen.CertificateRequestFrom(exampleBundle.CertificateRequestPending,
+						gen.AddCertificateRequestAnnotations(map[string]string{
+							cmapi.CertificateRequestRevisionAnnotationKey: "2", // Current Certificate revision=1
+						}),
+					)},
+				KubeObjects: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      nextPrivateKeySecretName,
+							Namespace: exampleBundle.Certificate.Namespace,
+						},
+						Data: map[string][]byte{
+							corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      exampleBundle.Certificate.Spec.SecretName,
+							Namespace: exampleBundle.Certificate.Namespace,
+							Annotations: map[string]string{
+								"my-custom": "annotation",
+							},
+						},
+						Data: nil,
+						Type: corev1.SecretTypeTLS,
+					},
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						exampleBundle.Certificate.Namespace,
+						&corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace: exampleBundle.Certificate.Namespace,
+								Name:      "output",
+								Annotations: map[string]string{
+									"my-custom":                    "annotation",
+									cmapi.CertificateNameKey:       "test",
+									cmapi.IssuerGroupAnnotationKey: "foo.io",
+									cmapi.IssuerKindAnnotationKey:  "Issuer",
+									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
+									cmapi.CommonNameAnnotationKey:  "",
+									cmapi.AltNamesAnnotationKey:    "example.com",
+									cmapi.IPSANAnnotationKey:       "",
+									cmapi.URISANAnnotationKey:      "",
+								},
+							},
+							Data: map[string][]byte{
+								corev1.TLSCertKey:       exampleBundle.LocalTemporaryCertificateBytes,
+								corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+							},
+							Type: corev1.SecretTypeTLS,
+						},
+					)),
+				},
+				ExpectedEvents: []string{
+					"Normal Issuing Issued temporary certificate",
+				},
+			},
+			expectedErr: false,
+		},
+
+		"if certificate is in Issuing state with temp annotation, one CertificateRequest Pending, a target Secret but with a cert/key that is not the NextPrivateKey, do nothing": {
+			certificate: exampleBundle.Certificate,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{
+					gen.CertificateFrom(issuingCert,
+						gen.AddCertificateAnnotations(map[string]string{
+							cmapi.IssueTemporaryCertificateAnnotation: "true",
+						}),
+					),
+					gen.CertificateRequestFrom(exampleBundle.CertificateRequestPending,
+						gen.AddCertificateRequestAnnotations(map[string]string{
+							cmapi.CertificateRequestRevisionAnnotationKey: "2", // Current Certificate revision=1
+						}),
+					)},
+				KubeObjects: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      nextPrivateKeySecretName,
+							Namespace: exampleBundle.Certificate.Namespace,
+						},
+						Data: map[string][]byte{
+							corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      exampleBundle.Certificate.Spec.SecretName,
+							Namespace: exampleBundle.Certificate.Namespace,
+							Annotations: map[string]string{
+								"my-custom": "annotation",
+							},
+						},
+						Data: map[string][]byte{
+							corev1.TLSCertKey:       exampleBundleAlt.CertBytes,
+							corev1.TLSPrivateKeyKey: exampleBundleAlt.PrivateKeyBytes,
+						},
+						Type: corev1.SecretTypeTLS,
+					},
+				},
+				ExpectedActions: []testpkg.Action{},
+				ExpectedEvents:  []string{},
+			},
+			expectedErr: false,
+		},
+
+		"if certificate is in Issuing state with temp annotation, one CertificateRequest Pending, a target Secret with a cert/key that are garbage, issue temporary certificate": {
+			certificate: exampleBundle.Certificate,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{
+					gen.CertificateFrom(issuingCert,
+						gen.AddCertificateAnnotations(map[string]string{
+							cmapi.IssueTemporaryCertificateAnnotation: "true",
+						}),
+					),
+					gen.CertificateRequestFrom(exampleBundle.CertificateRequestPending,
+						gen.AddCertificateRequestAnnotations(map[string]string{
+							cmapi.CertificateRequestRevisionAnnotationKey: "2", // Current Certificate revision=1
+						}),
+					)},
+				KubeObjects: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      nextPrivateKeySecretName,
+							Namespace: exampleBundle.Certificate.Namespace,
+						},
+						Data: map[string][]byte{
+							corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      exampleBundle.Certificate.Spec.SecretName,
+							Namespace: exampleBundle.Certificate.Namespace,
+							Annotations: map[string]string{
+								"my-custom": "annotation",
+							},
+						},
+						Data: map[string][]byte{
+							corev1.TLSPrivateKeyKey: []byte("abc"),
+						},
+						Type: corev1.SecretTypeTLS,
+					},
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						exampleBundle.Certificate.Namespace,
+						&corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace: exampleBundle.Certificate.Namespace,
+								Name:      "output",
+								Annotations: map[string]string{
+									"my-custom":                    "annotation",
+									cmapi.CertificateNameKey:       "test",
+									cmapi.IssuerGroupAnnotationKey: "foo.io",
+									cmapi.IssuerKindAnnotationKey:  "Issuer",
+									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
+									cmapi.CommonNameAnnotationKey:  "",
+									cmapi.AltNamesAnnotationKey:    "example.com",
+									cmapi.IPSANAnnotationKey:       "",
+									cmapi.URISANAnnotationKey:      "",
+								},
+							},
+							Data: map[string][]byte{
+								corev1.TLSCertKey:       exampleBundle.LocalTemporaryCertificateBytes,
+								corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+							},
+							Type: corev1.SecretTypeTLS,
+						},
+					)),
+				},
+				ExpectedEvents: []string{
+					"Normal Issuing Issued temporary certificate",
+				},
+			},
+			expectedErr: false,
+		},
+
+		"if certificate is in Issuing state with temp annotation, one CertificateRequest Pending, a target Secret with a cert/key with a matching key, do not issue temporary certificate": {
+			certificate: exampleBundle.Certificate,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{
+					gen.CertificateFrom(issuingCert,
+						gen.AddCertificateAnnotations(map[string]string{
+							cmapi.IssueTemporaryCertificateAnnotation: "true",
+						}),
+					),
+					gen.CertificateRequestFrom(exampleBundle.CertificateRequestPending,
+						gen.AddCertificateRequestAnnotations(map[string]string{
+							cmapi.CertificateRequestRevisionAnnotationKey: "2", // Current Certificate revision=1
+						}),
+					)},
+				KubeObjects: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      nextPrivateKeySecretName,
+							Namespace: exampleBundle.Certificate.Namespace,
+						},
+						Data: map[string][]byte{
+							corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+						},
+					},
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      exampleBundle.Certificate.Spec.SecretName,
+							Namespace: exampleBundle.Certificate.Namespace,
+							Annotations: map[string]string{
+								"my-custom": "annotation",
+							},
+						},
+						Data: map[string][]byte{
 							corev1.TLSCertKey:       exampleBundle.LocalTemporaryCertificateBytes, // Cert not valid but still matches private key
 							corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
 						},
-						Type: corev1.SecretTypeTLS,
+						Type: corev1.SecretTypeTLS,
+					},
+				},
+				ExpectedActions: []testpkg.Action{},
+				ExpectedEvents:  []string{},
+			},
+			expectedErr: false,
+		},
+		"if certificate is in Issuing state with temp annotation, one CertificateRequest Ready, a target Secret does not exist, issue Certificate from CertificateRequest": {
+			certificate: exampleBundle.Certificate,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{
+					gen.CertificateFrom(issuingCert,
+						gen.AddCertificateAnnotations(map[string]string{
+							cmapi.IssueTemporaryCertificateAnnotation: "true",
+						}),
+					),
+					gen.CertificateRequestFrom(exampleBundle.CertificateRequestReady,
+						gen.AddCertificateRequestAnnotations(map[string]string{
+							cmapi.CertificateRequestRevisionAnnotationKey: "2", // Current Certificate revision=1
+						}),
+					)},
+				KubeObjects: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      nextPrivateKeySecretName,
+							Namespace: exampleBundle.Certificate.Namespace,
+						},
+						Data: map[string][]byte{
+							corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+						},
+					},
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificates"),
+						"status",
+						exampleBundle.Certificate.Namespace,
+						gen.CertificateFrom(exampleBundle.Certificate,
+							gen.AddCertificateAnnotations(map[string]string{
+								cmapi.IssueTemporaryCertificateAnnotation: "true",
+							}),
+							gen.SetCertificateRevision(2),
+						),
+					)),
+					testpkg.NewAction(coretesting.NewCreateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						exampleBundle.Certificate.Namespace,
+						&corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace: exampleBundle.Certificate.Namespace,
+								Name:      "output",
+								Annotations: map[string]string{
+									cmapi.CertificateNameKey:       "test",
+									cmapi.IssuerGroupAnnotationKey: "foo.io",
+									cmapi.IssuerKindAnnotationKey:  "Issuer",
+									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
+									cmapi.CommonNameAnnotationKey:  "",
+									cmapi.AltNamesAnnotationKey:    "example.com",
+									cmapi.IPSANAnnotationKey:       "",
+									cmapi.URISANAnnotationKey:      "",
+								},
+							},
+							Data: map[string][]byte{
+								corev1.TLSCertKey:       exampleBundle.CertificateRequestReady.Status.Certificate,
+								corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+							},
+							Type: corev1.SecretTypeTLS,
+						},
+					)),
+				},
+				ExpectedEvents: []string{
+					"Normal Issuing The certificate has been successfully issued",
+				},
+			},
+			expectedErr: false,
+		},
+		"if certificate is in Issuing state with temp annotation, one CertificateRequest Failed, a target Secret does not exist, mark the Certificate as failed": {
+			certificate: exampleBundle.Certificate,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{
+					gen.CertificateFrom(issuingCert,
+						gen.AddCertificateAnnotations(map[string]string{
+							cmapi.IssueTemporaryCertificateAnnotation: "true",
+						}),
+					),
+					gen.CertificateRequestFrom(exampleBundle.CertificateRequestFailed,
+						gen.AddCertificateRequestAnnotations(map[string]string{
+							cmapi.CertificateRequestRevisionAnnotationKey: "2", // Current Certificate revision=1
+						}),
+						gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+							Type:    cmapi.CertificateRequestConditionReady,
+							Status:  cmmeta.ConditionFalse,
+							Reason:  cmapi.CertificateRequestReasonFailed,
+							Message: "The certificate request failed because of reasons",
+						}),
+					)},
+				KubeObjects: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      nextPrivateKeySecretName,
+							Namespace: exampleBundle.Certificate.Namespace,
+						},
+						Data: map[string][]byte{
+							corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+						},
+					},
+				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificates"),
+						"status",
+						exampleBundle.Certificate.Namespace,
+						gen.CertificateFrom(exampleBundle.Certificate,
+							gen.AddCertificateAnnotations(map[string]string{
+								cmapi.IssueTemporaryCertificateAnnotation: "true",
+							}),
+							gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+								Type:               cmapi.CertificateConditionIssuing,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             "Failed",
+								Message:            "The certificate request has failed to complete and will be retried: The certificate request failed because of reasons",
+								LastTransitionTime: &metaFixedClockStart,
+								ObservedGeneration: 3,
+							}),
+							gen.SetCertificateLastFailureTime(metaFixedClockStart),
+						),
+					)),
+				},
+				ExpectedEvents: []string{
+					"Warning Failed The certificate request has failed to complete and will be retried: The certificate request failed because of reasons",
+				},
+			},
+			expectedErr: false,
+		},
+		"if certificate is in Issuing state, one CertificateRequest, but has been denied, report denial and set last failed time": {
+			certificate: exampleBundle.Certificate,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{
+					gen.CertificateFrom(issuingCert),
+					gen.CertificateRequestFrom(exampleBundle.CertificateRequest,
+						gen.AddCertificateRequestAnnotations(map[string]string{
+							cmapi.CertificateRequestRevisionAnnotationKey: "2", // Current Certificate revision=1
+						}),
+						gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+							Type:    cmapi.CertificateRequestConditionDenied,
+							Status:  cmmeta.ConditionTrue,
+							Reason:  "DeniedReason",
+							Message: "The certificate request has been denied",
+						}),
+					)},
+				KubeObjects: []runtime.Object{
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      nextPrivateKeySecretName,
+							Namespace: exampleBundle.Certificate.Namespace,
+						},
+						Data: map[string][]byte{
+							corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+						},
 					},
 				},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificates"),
+						"status",
+						exampleBundle.Certificate.Namespace,
+						gen.CertificateFrom(exampleBundle.Certificate,
+							gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+								Type:               cmapi.CertificateConditionIssuing,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             "DeniedReason",
+								Message:            "The certificate request has failed to complete and will be retried: The certificate request has been denied",
+								LastTransitionTime: &metaFixedClockStart,
+								ObservedGeneration: 3,
+							}),
+							gen.SetCertificateLastFailureTime(metaFixedClockStart),
+						),
+					)),
+				},
+				ExpectedEvents: []string{
+					"Warning DeniedReason The certificate request has failed to complete and will be retried: The certificate request has been denied",
+				},
+			},
+			expectedErr: false,
+		},
+
+		"if the issuance has exceeded the issuance timeout of the certificate, set failed state and log event": {
+			certificate: exampleBundle.Certificate,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{
+					issuingSince(2*time.Hour, gen.SetCertificateIssuanceTimeout(time.Hour)),
+					pendingRequest.DeepCopy(),
+				},
+				KubeObjects: []runtime.Object{nextPrivateKeySecret.DeepCopy()},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificates"),
+						"status",
+						exampleBundle.Certificate.Namespace,
+						gen.CertificateFrom(exampleBundle.Certificate,
+							gen.SetCertificateIssuanceTimeout(time.Hour),
+							gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+								Type:               cmapi.CertificateConditionIssuing,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             "IssuanceTimeout",
+								Message:            fmt.Sprintf("The certificate request has failed to complete and will be retried: CertificateRequest %q was not completed within the issuance timeout of 1h0m0s", pendingRequest.Name),
+								LastTransitionTime: &metaFixedClockStart,
+								ObservedGeneration: 3,
+							}),
+							gen.SetCertificateLastFailureTime(metaFixedClockStart),
+						),
+					)),
+				},
+				ExpectedEvents: []string{
+					fmt.Sprintf("Warning IssuanceTimeout The certificate request has failed to complete and will be retried: CertificateRequest %q was not completed within the issuance timeout of 1h0m0s", pendingRequest.Name),
+				},
+			},
+			expectedErr: false,
+		},
+
+		"if the issuance has exceeded the default issuance timeout, set failed state and log event": {
+			certificate:            exampleBundle.Certificate,
+			defaultIssuanceTimeout: time.Hour,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{
+					issuingSince(2 * time.Hour),
+					pendingRequest.DeepCopy(),
+				},
+				KubeObjects: []runtime.Object{nextPrivateKeySecret.DeepCopy()},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificates"),
+						"status",
+						exampleBundle.Certificate.Namespace,
+						gen.CertificateFrom(exampleBundle.Certificate,
+							gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+								Type:               cmapi.CertificateConditionIssuing,
+								Status:             cmmeta.ConditionFalse,
+								Reason:             "IssuanceTimeout",
+								Message:            fmt.Sprintf("The certificate request has failed to complete and will be retried: CertificateRequest %q was not completed within the issuance timeout of 1h0m0s", pendingRequest.Name),
+								LastTransitionTime: &metaFixedClockStart,
+								ObservedGeneration: 3,
+							}),
+							gen.SetCertificateLastFailureTime(metaFixedClockStart),
+						),
+					)),
+				},
+				ExpectedEvents: []string{
+					fmt.Sprintf("Warning IssuanceTimeout The certificate request has failed to complete and will be retried: CertificateRequest %q was not completed within the issuance timeout of 1h0m0s", pendingRequest.Name),
+				},
+			},
+			expectedErr: false,
+		},
+
+		"if the issuance timeout of the certificate is 0s, never time out the issuance even if a default is configured": {
+			certificate:            exampleBundle.Certificate,
+			defaultIssuanceTimeout: time.Hour,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{
+					issuingSince(2*time.Hour, gen.SetCertificateIssuanceTimeout(0)),
+					pendingRequest.DeepCopy(),
+				},
+				KubeObjects:     []runtime.Object{nextPrivateKeySecret.DeepCopy()},
 				ExpectedActions: []testpkg.Action{},
-				ExpectedEvents:  []string{},
 			},
 			expectedErr: false,
 		},
-		"if certificate is in Issuing state with temp annotation, one CertificateRequest Ready, a target Secret does not exist, issue Certificate from CertificateRequest": {
+
+		"if the issuance is within the issuance timeout of the certificate, do nothing": {
 			certificate: exampleBundle.Certificate,
 			builder: &testpkg.Builder{
 				CertManagerObjects: []runtime.Object{
-					gen.CertificateFrom(issuingCert,
-						gen.AddCertificateAnnotations(map[string]string{
-							cmapi.IssueTemporaryCertificateAnnotation: "true",
+					issuingSince(30*time.Minute, gen.SetCertificateIssuanceTimeout(time.Hour)),
+					pendingRequest.DeepCopy(),
+				},
+				KubeObjects:     []runtime.Object{nextPrivateKeySecret.DeepCopy()},
+				ExpectedActions: []testpkg.Action{},
+			},
+			expectedErr: false,
+		},
+
+		"if the CertificateRequest is ready within the issuance timeout of the certificate, store the signed certificate": {
+			certificate: exampleBundle.Certificate,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{
+					issuingSince(30*time.Minute, gen.SetCertificateIssuanceTimeout(time.Hour)),
+					gen.CertificateRequestFrom(exampleBundle.CertificateRequestReady,
+						gen.AddCertificateRequestAnnotations(map[string]string{
+							cmapi.CertificateRequestRevisionAnnotationKey: "2", // Current Certificate revision=1
 						}),
+					)},
+				KubeObjects: []runtime.Object{nextPrivateKeySecret.DeepCopy()},
+				ExpectedActions: []testpkg.Action{
+					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+						cmapi.SchemeGroupVersion.WithResource("certificates"),
+						"status",
+						exampleBundle.Certificate.Namespace,
+						gen.CertificateFrom(exampleBundle.Certificate,
+							gen.SetCertificateIssuanceTimeout(time.Hour),
+							gen.SetCertificateRevision(2),
+						),
+					)),
+					testpkg.NewAction(coretesting.NewCreateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						exampleBundle.Certificate.Namespace,
+						&corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace: exampleBundle.Certificate.Namespace,
+								Name:      "output",
+								Annotations: map[string]string{
+									cmapi.CertificateNameKey:       "test",
+									cmapi.IssuerKindAnnotationKey:  "Issuer",
+									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
+									cmapi.IssuerGroupAnnotationKey: "foo.io",
+									cmapi.CommonNameAnnotationKey:  "",
+									cmapi.AltNamesAnnotationKey:    "example.com",
+									cmapi.IPSANAnnotationKey:       "",
+									cmapi.URISANAnnotationKey:      "",
+								},
+							},
+							Data: map[string][]byte{
+								corev1.TLSCertKey:       exampleBundle.CertificateRequestReady.Status.Certificate,
+								corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+							},
+							Type: corev1.SecretTypeTLS,
+						},
+					)),
+				},
+				ExpectedEvents: []string{
+					"Normal Issuing The certificate has been successfully issued",
+				},
+			},
+			expectedErr: false,
+		},
+
+		"if a renewal history limit is set, record a successful issuance in the renewal history": {
+			certificate:         exampleBundle.Certificate,
+			renewalHistoryLimit: 3,
+			builder: &testpkg.Builder{
+				CertManagerObjects: []runtime.Object{
+					gen.CertificateFrom(issuingCert,
+						gen.SetCertificateRenewalHistory(
+							renewalRecord(0, cmapi.CertificateRenewalResultFailed, earlierTime),
+							renewalRecord(1, cmapi.CertificateRenewalResultIssued, earlierTime),
+						),
 					),
 					gen.CertificateRequestFrom(exampleBundle.CertificateRequestReady,
 						gen.AddCertificateRequestAnnotations(map[string]string{
@@ -842,10 +1576,12 @@ func TestIssuingController(t *testing.T) {
 						"status",
 						exampleBundle.Certificate.Namespace,
 						gen.CertificateFrom(exampleBundle.Certificate,
-							gen.AddCertificateAnnotations(map[string]string{
-								cmapi.IssueTemporaryCertificateAnnotation: "true",
-							}),
 							gen.SetCertificateRevision(2),
+							gen.SetCertificateRenewalHistory(
+								renewalRecord(0, cmapi.CertificateRenewalResultFailed, earlierTime),
+								renewalRecord(1, cmapi.CertificateRenewalResultIssued, earlierTime),
+								renewalRecord(2, cmapi.CertificateRenewalResultIssued, metaFixedClockStart),
+							),
 						),
 					)),
 					testpkg.NewAction(coretesting.NewCreateAction(
@@ -857,9 +1593,9 @@ func TestIssuingController(t *testing.T) {
 								Name:      "output",
 								Annotations: map[string]string{
 									cmapi.CertificateNameKey:       "test",
-									cmapi.IssuerGroupAnnotationKey: "foo.io",
 									cmapi.IssuerKindAnnotationKey:  "Issuer",
 									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
+									cmapi.IssuerGroupAnnotationKey: "foo.io",
 									cmapi.CommonNameAnnotationKey:  "",
 									cmapi.AltNamesAnnotationKey:    "example.com",
 									cmapi.IPSANAnnotationKey:       "",
@@ -880,25 +1616,21 @@ func TestIssuingController(t *testing.T) {
 			},
 			expectedErr: false,
 		},
-		"if certificate is in Issuing state with temp annotation, one CertificateRequest Failed, a target Secret does not exist, mark the Certificate as failed": {
-			certificate: exampleBundle.Certificate,
+		"if the renewal history is at its limit, drop the oldest record when recording an issuance": {
+			certificate:         exampleBundle.Certificate,
+			renewalHistoryLimit: 2,
 			builder: &testpkg.Builder{
 				CertManagerObjects: []runtime.Object{
 					gen.CertificateFrom(issuingCert,
-						gen.AddCertificateAnnotations(map[string]string{
-							cmapi.IssueTemporaryCertificateAnnotation: "true",
-						}),
+						gen.SetCertificateRenewalHistory(
+							renewalRecord(0, cmapi.CertificateRenewalResultFailed, earlierTime),
+							renewalRecord(1, cmapi.CertificateRenewalResultIssued, earlierTime),
+						),
 					),
-					gen.CertificateRequestFrom(exampleBundle.CertificateRequestFailed,
+					gen.CertificateRequestFrom(exampleBundle.CertificateRequestReady,
 						gen.AddCertificateRequestAnnotations(map[string]string{
 							cmapi.CertificateRequestRevisionAnnotationKey: "2", // Current Certificate revision=1
 						}),
-						gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
-							Type:    cmapi.CertificateRequestConditionReady,
-							Status:  cmmeta.ConditionFalse,
-							Reason:  cmapi.CertificateRequestReasonFailed,
-							Message: "The certificate request failed because of reasons",
-						}),
 					)},
 				KubeObjects: []runtime.Object{
 					&corev1.Secret{
@@ -917,41 +1649,62 @@ func TestIssuingController(t *testing.T) {
 						"status",
 						exampleBundle.Certificate.Namespace,
 						gen.CertificateFrom(exampleBundle.Certificate,
-							gen.AddCertificateAnnotations(map[string]string{
-								cmapi.IssueTemporaryCertificateAnnotation: "true",
-							}),
-							gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
-								Type:               cmapi.CertificateConditionIssuing,
-								Status:             cmmeta.ConditionFalse,
-								Reason:             "Failed",
-								Message:            "The certificate request has failed to complete and will be retried: The certificate request failed because of reasons",
-								LastTransitionTime: &metaFixedClockStart,
-								ObservedGeneration: 3,
-							}),
-							gen.SetCertificateLastFailureTime(metaFixedClockStart),
+							gen.SetCertificateRevision(2),
+							gen.SetCertificateRenewalHistory(
+								renewalRecord(1, cmapi.CertificateRenewalResultIssued, earlierTime),
+								renewalRecord(2, cmapi.CertificateRenewalResultIssued, metaFixedClockStart),
+							),
 						),
 					)),
+					testpkg.NewAction(coretesting.NewCreateAction(
+						corev1.SchemeGroupVersion.WithResource("secrets"),
+						exampleBundle.Certificate.Namespace,
+						&corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace: exampleBundle.Certificate.Namespace,
+								Name:      "output",
+								Annotations: map[string]string{
+									cmapi.CertificateNameKey:       "test",
+									cmapi.IssuerKindAnnotationKey:  "Issuer",
+									cmapi.IssuerNameAnnotationKey:  "ca-issuer",
+									cmapi.IssuerGroupAnnotationKey: "foo.io",
+									cmapi.CommonNameAnnotationKey:  "",
+									cmapi.AltNamesAnnotationKey:    "example.com",
+									cmapi.IPSANAnnotationKey:       "",
+									cmapi.URISANAnnotationKey:      "",
+								},
+							},
+							Data: map[string][]byte{
+								corev1.TLSCertKey:       exampleBundle.CertificateRequestReady.Status.Certificate,
+								corev1.TLSPrivateKeyKey: exampleBundle.PrivateKeyBytes,
+							},
+							Type: corev1.SecretTypeTLS,
+						},
+					)),
 				},
 				ExpectedEvents: []string{
-					"Warning Failed The certificate request has failed to complete and will be retried: The certificate request failed because of reasons",
+					"Normal Issuing The certificate has been successfully issued",
 				},
 			},
 			expectedErr: false,
 		},
-		"if certificate is in Issuing state, one CertificateRequest, but has been denied, report denial and set last failed time": {
-			certificate: exampleBundle.Certificate,
+		"if a renewal history limit is set, record a failed issuance in the renewal history": {
+			certificate:         exampleBundle.Certificate,
+			renewalHistoryLimit: 3,
 			builder: &testpkg.Builder{
 				CertManagerObjects: []runtime.Object{
-					gen.CertificateFrom(issuingCert),
-					gen.CertificateRequestFrom(exampleBundle.CertificateRequest,
+					gen.CertificateFrom(issuingCert,
+						gen.SetCertificateRenewalHistory(renewalRecord(1, cmapi.CertificateRenewalResultIssued, earlierTime)),
+					),
+					gen.CertificateRequestFrom(exampleBundle.CertificateRequestFailed,
 						gen.AddCertificateRequestAnnotations(map[string]string{
 							cmapi.CertificateRequestRevisionAnnotationKey: "2", // Current Certificate revision=1
 						}),
 						gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
-							Type:    cmapi.CertificateRequestConditionDenied,
-							Status:  cmmeta.ConditionTrue,
-							Reason:  "DeniedReason",
-							Message: "The certificate request has been denied",
+							Type:    cmapi.CertificateRequestConditionReady,
+							Status:  cmmeta.ConditionFalse,
+							Reason:  cmapi.CertificateRequestReasonFailed,
+							Message: "The certificate request failed because of reasons",
 						}),
 					)},
 				KubeObjects: []runtime.Object{
@@ -974,17 +1727,21 @@ func TestIssuingController(t *testing.T) {
 							gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
 								Type:               cmapi.CertificateConditionIssuing,
 								Status:             cmmeta.ConditionFalse,
-								Reason:             "DeniedReason",
-								Message:            "The certificate request has failed to complete and will be retried: The certificate request has been denied",
+								Reason:             "Failed",
+								Message:            "The certificate request has failed to complete and will be retried: The certificate request failed because of reasons",
 								LastTransitionTime: &metaFixedClockStart,
 								ObservedGeneration: 3,
 							}),
 							gen.SetCertificateLastFailureTime(metaFixedClockStart),
+							gen.SetCertificateRenewalHistory(
+								renewalRecord(1, cmapi.CertificateRenewalResultIssued, earlierTime),
+								renewalRecord(2, cmapi.CertificateRenewalResultFailed, metaFixedClockStart),
+							),
 						),
 					)),
 				},
 				ExpectedEvents: []string{
-					"Warning DeniedReason The certificate request has failed to complete and will be retried: The certificate request has been denied",
+					"Warning Failed The certificate request has failed to complete and will be retried: The certificate request failed because of reasons",
 				},
 			},
 			expectedErr: false,
@@ -1003,6 +1760,10 @@ func TestIssuingController(t *testing.T) {
 			w := controllerWrapper{}
 			w.Register(test.builder.Context)
 			w.controller.localTemporarySigner = testLocalTemporarySignerFn(exampleBundle.LocalTemporaryCertificateBytes)
+			w.controller.renewalHistoryLimit = test.renewalHistoryLimit
+			w.controller.warnSecretSize = test.warnSecretSize
+			w.controller.additionalTrustBundle = test.additionalTrustBundle
+			w.controller.defaultIssuanceTimeout = test.defaultIssuanceTimeout
 
 			// Start the unit test builder
 			test.builder.Start()
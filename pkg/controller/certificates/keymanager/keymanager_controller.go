@@ -36,6 +36,7 @@ import (
 	"k8s.io/client-go/util/workqueue"
 
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
@@ -43,7 +44,10 @@ import (
 	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	"github.com/jetstack/cert-manager/pkg/issuer"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	cmerrors "github.com/jetstack/cert-manager/pkg/util/errors"
+	"github.com/jetstack/cert-manager/pkg/util/kube"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
 	"github.com/jetstack/cert-manager/pkg/util/predicate"
 )
@@ -52,6 +56,8 @@ const (
 	ControllerName     = "certificates-key-manager"
 	reasonDecodeFailed = "DecodeFailed"
 	reasonDeleted      = "Deleted"
+	reasonKeySeed      = "KeySeedFailed"
+	reasonRegenerated  = "Regenerated"
 )
 
 var (
@@ -64,6 +70,11 @@ type controller struct {
 	client            cmclient.Interface
 	coreClient        kubernetes.Interface
 	recorder          record.EventRecorder
+
+	// issuerHelper and issuerOptions are used to find SelfSigned issuers
+	// that derive private keys from a seed.
+	issuerHelper  issuer.Helper
+	issuerOptions controllerpkg.IssuerOptions
 }
 
 func NewController(
@@ -73,6 +84,8 @@ func NewController(
 	factory informers.SharedInformerFactory,
 	cmFactory cminformers.SharedInformerFactory,
 	recorder record.EventRecorder,
+	namespace string,
+	issuerOptions controllerpkg.IssuerOptions,
 ) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
 	// create a queue used to queue up items to be processed
 	queue := workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
@@ -80,6 +93,7 @@ func NewController(
 	// obtain references to all the informers used by this controller
 	certificateInformer := cmFactory.Certmanager().V1().Certificates()
 	secretsInformer := factory.Core().V1().Secrets()
+	issuerInformer := cmFactory.Certmanager().V1().Issuers()
 
 	certificateInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: queue})
 
@@ -101,6 +115,16 @@ func NewController(
 	mustSync := []cache.InformerSynced{
 		secretsInformer.Informer().HasSynced,
 		certificateInformer.Informer().HasSynced,
+		issuerInformer.Informer().HasSynced,
+	}
+
+	// if we are running in non-namespaced mode (i.e. --namespace=""), we also
+	// obtain a lister for clusterissuers.
+	var clusterIssuerLister cmlisters.ClusterIssuerLister
+	if namespace == "" {
+		clusterIssuerInformer := cmFactory.Certmanager().V1().ClusterIssuers()
+		clusterIssuerLister = clusterIssuerInformer.Lister()
+		mustSync = append(mustSync, clusterIssuerInformer.Informer().HasSynced)
 	}
 
 	return &controller{
@@ -109,6 +133,8 @@ func NewController(
 		client:            client,
 		coreClient:        coreClient,
 		recorder:          recorder,
+		issuerHelper:      issuer.NewHelper(issuerInformer.Lister(), clusterIssuerLister),
+		issuerOptions:     issuerOptions,
 	}, queue, mustSync
 }
 
@@ -249,8 +275,12 @@ func (c *controller) createNextPrivateKeyRotationPolicyNever(ctx context.Context
 		return c.createAndSetNextPrivateKey(ctx, crt)
 	}
 	if len(violations) > 0 {
-		c.recorder.Eventf(crt, corev1.EventTypeWarning, reasonDecodeFailed, "Existing private key in Secret %q does not match requirements on Certificate resource, mismatching fields: %v", crt.Spec.SecretName, violations)
-		return nil
+		// The private key algorithm or size has been changed on the
+		// Certificate, so the existing key cannot be reused even though the
+		// rotation policy is Never.
+		log.V(logf.DebugLevel).Info("Creating new nextPrivateKeySecretName Secret because existing private key does not match the spec", "violations", violations)
+		c.recorder.Eventf(crt, corev1.EventTypeNormal, reasonRegenerated, "Existing private key in Secret %q does not match requirements on Certificate resource, generating new key. Mismatching fields: %v", crt.Spec.SecretName, violations)
+		return c.createAndSetNextPrivateKey(ctx, crt)
 	}
 
 	nextPkSecret, err := c.createNewPrivateKeySecret(ctx, crt, pk)
@@ -264,11 +294,30 @@ func (c *controller) createNextPrivateKeyRotationPolicyNever(ctx context.Context
 }
 
 func (c *controller) createAndSetNextPrivateKey(ctx context.Context, crt *cmapi.Certificate) error {
-	pk, err := pki.GeneratePrivateKeyForCertificate(crt)
+	seed, err := c.keySeedForCertificate(ctx, crt)
+	if cmerrors.IsInvalidData(err) {
+		c.recorder.Eventf(crt, corev1.EventTypeWarning, reasonKeySeed, "Failed to read key seed of issuer %q: %v", crt.Spec.IssuerRef.Name, err)
+		return nil
+	}
 	if err != nil {
 		return err
 	}
 
+	var pk crypto.Signer
+	if seed != nil {
+		pk, err = pki.GeneratePrivateKeyForCertificateFromSeed(crt, seed)
+		if err != nil {
+			// retrying will not help until the Certificate or issuer is changed
+			c.recorder.Eventf(crt, corev1.EventTypeWarning, reasonKeySeed, "Failed to derive private key from key seed of issuer %q: %v", crt.Spec.IssuerRef.Name, err)
+			return nil
+		}
+	} else {
+		pk, err = pki.GeneratePrivateKeyForCertificate(crt)
+		if err != nil {
+			return err
+		}
+	}
+
 	s, err := c.createNewPrivateKeySecret(ctx, crt, pk)
 	if err != nil {
 		return err
@@ -279,6 +328,33 @@ func (c *controller) createAndSetNextPrivateKey(ctx context.Context, crt *cmapi.
 	return c.setNextPrivateKeySecretName(ctx, crt, &s.Name)
 }
 
+// keySeedForCertificate returns the seed that the private key for the given
+// Certificate should be derived from, if the Certificate's issuer is a
+// SelfSigned issuer with a keySeedSecretRef. Otherwise it returns nil, in which
+// case a random private key should be generated. An InvalidData error is
+// returned if the referenced seed is missing from its Secret or is too short.
+func (c *controller) keySeedForCertificate(ctx context.Context, crt *cmapi.Certificate) ([]byte, error) {
+	// external issuers cannot be SelfSigned issuers
+	if !(crt.Spec.IssuerRef.Group == "" || crt.Spec.IssuerRef.Group == certmanager.GroupName) {
+		return nil, nil
+	}
+
+	iss, err := c.issuerHelper.GetGenericIssuer(crt.Spec.IssuerRef, crt.Namespace)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	selfSigned := iss.GetSpec().SelfSigned
+	if selfSigned == nil || selfSigned.KeySeedSecretRef == nil {
+		return nil, nil
+	}
+
+	return kube.SecretKeySeed(ctx, c.secretLister, c.issuerOptions.ResourceNamespace(iss), *selfSigned.KeySeedSecretRef)
+}
+
 // deleteSecretResources will delete the given secret resources
 func (c *controller) deleteSecretResources(ctx context.Context, secrets []*corev1.Secret) error {
 	log := logf.FromContext(ctx)
@@ -360,6 +436,8 @@ func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.Rate
 		ctx.KubeSharedInformerFactory,
 		ctx.SharedInformerFactory,
 		ctx.Recorder,
+		ctx.Namespace,
+		ctx.IssuerOptions,
 	)
 	c.controller = ctrl
 
@@ -17,7 +17,9 @@ limitations under the License.
 package keymanager
 
 import (
+	"bytes"
 	"context"
+	"crypto/ecdsa"
 	"fmt"
 	"reflect"
 	"testing"
@@ -61,6 +63,18 @@ func mustGenerateECDSA(t *testing.T, keySize int) []byte {
 	return d
 }
 
+func mustDeriveECDSA(t *testing.T, keySize int, seed []byte) []byte {
+	pk, err := pki.GenerateECPrivateKeyFromSeed(keySize, seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := pki.EncodePKCS8PrivateKey(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
 func relaxedSecretMatcher(l coretesting.Action, r coretesting.Action) error {
 	objL := l.(coretesting.CreateAction).GetObject().(*corev1.Secret).DeepCopy()
 	objR := r.(coretesting.CreateAction).GetObject().(*corev1.Secret).DeepCopy()
@@ -76,6 +90,27 @@ func relaxedSecretMatcher(l coretesting.Action, r coretesting.Action) error {
 	return nil
 }
 
+// ecdsaP256SecretMatcher matches like relaxedSecretMatcher, but additionally
+// requires the created Secret to contain an ECDSA P-256 private key.
+func ecdsaP256SecretMatcher(l coretesting.Action, r coretesting.Action) error {
+	if err := relaxedSecretMatcher(l, r); err != nil {
+		return err
+	}
+	obj := r.(coretesting.CreateAction).GetObject().(*corev1.Secret)
+	pk, err := pki.DecodePrivateKeyBytes(obj.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return err
+	}
+	ecdsaPk, ok := pk.(*ecdsa.PrivateKey)
+	if !ok {
+		return fmt.Errorf("expected an ECDSA private key but got %T", pk)
+	}
+	if bitSize := ecdsaPk.Curve.Params().BitSize; bitSize != pki.ECCurve256 {
+		return fmt.Errorf("expected a P-256 private key but got a %d bit curve", bitSize)
+	}
+	return nil
+}
+
 func TestProcessItem(t *testing.T) {
 	ownedSecretWithName := func(namespace, name, owner string, data map[string][]byte) *corev1.Secret {
 		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
@@ -93,6 +128,43 @@ func TestProcessItem(t *testing.T) {
 			Data: data,
 		}
 	}
+	rsa2048Key := mustGenerateRSA(t, 2048)
+	keySeed := bytes.Repeat([]byte{1}, pki.MinKeySeedSize)
+	keySeedSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "key-seed"},
+		Data:       map[string][]byte{"seed": keySeed},
+	}
+	seededIssuer := &cmapi.Issuer{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "seeded"},
+		Spec: cmapi.IssuerSpec{
+			IssuerConfig: cmapi.IssuerConfig{
+				SelfSigned: &cmapi.SelfSignedIssuer{
+					KeySeedSecretRef: &cmmeta.SecretKeySelector{
+						LocalObjectReference: cmmeta.LocalObjectReference{Name: "key-seed"},
+						Key:                  "seed",
+					},
+				},
+			},
+		},
+	}
+	seededCertificate := func(keyAlgorithm cmapi.PrivateKeyAlgorithm) *cmapi.Certificate {
+		return &cmapi.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
+			Spec: cmapi.CertificateSpec{
+				IssuerRef:  cmmeta.ObjectReference{Name: "seeded"},
+				PrivateKey: &cmapi.CertificatePrivateKey{Algorithm: keyAlgorithm},
+			},
+			Status: cmapi.CertificateStatus{
+				NextPrivateKeySecretName: pointer.StringPtr("fixed-name"),
+				Conditions: []cmapi.CertificateCondition{
+					{
+						Type:   cmapi.CertificateConditionIssuing,
+						Status: cmmeta.ConditionTrue,
+					},
+				},
+			},
+		}
+	}
 	tests := map[string]struct {
 		// key that should be passed to ProcessItem.
 		// if not set, the 'namespace/name' of the 'Certificate' field will be used.
@@ -105,6 +177,9 @@ func TestProcessItem(t *testing.T) {
 
 		secrets []runtime.Object
 
+		// Issuers, if set, will exist in the apiserver before the test is run.
+		issuers []runtime.Object
+
 		// Request, if set, will exist in the apiserver before the test is run.
 		requests []*cmapi.CertificateRequest
 
@@ -461,6 +536,125 @@ func TestProcessItem(t *testing.T) {
 				ownedSecretWithName("testns", "fixed-name", "test", map[string][]byte{"tls.key": mustGenerateRSA(t, 2048)}),
 			},
 		},
+		"reuse the private key in the existing Secret if it matches the spec and the rotation policy is Never": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
+				Spec: cmapi.CertificateSpec{
+					SecretName: "output",
+					PrivateKey: &cmapi.CertificatePrivateKey{RotationPolicy: cmapi.RotationPolicyNever},
+				},
+				Status: cmapi.CertificateStatus{
+					NextPrivateKeySecretName: pointer.StringPtr("fixed-name"),
+					Conditions: []cmapi.CertificateCondition{
+						{
+							Type:   cmapi.CertificateConditionIssuing,
+							Status: cmmeta.ConditionTrue,
+						},
+					},
+				},
+			},
+			secrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "output"},
+				Data:       map[string][]byte{corev1.TLSPrivateKeyKey: rsa2048Key},
+			}},
+			expectedEvents: []string{`Normal Reused Reusing private key stored in existing Secret resource "output"`},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewCreateAction(
+					corev1.SchemeGroupVersion.WithResource("secrets"),
+					"testns",
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace:       "testns",
+							Name:            "fixed-name",
+							Labels:          map[string]string{cmapi.IsNextPrivateKeySecretLabelKey: "true"},
+							OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(&cmapi.Certificate{ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"}}, certificateGvk)},
+						},
+						Data: map[string][]byte{corev1.TLSPrivateKeyKey: rsa2048Key},
+					},
+				)),
+			},
+		},
+		"generate a new private key if the algorithm changed from RSA 2048 to ECDSA P-256 even if the rotation policy is Never": {
+			certificate: &cmapi.Certificate{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
+				Spec: cmapi.CertificateSpec{
+					SecretName: "output",
+					PrivateKey: &cmapi.CertificatePrivateKey{
+						RotationPolicy: cmapi.RotationPolicyNever,
+						Algorithm:      cmapi.ECDSAKeyAlgorithm,
+						Size:           pki.ECCurve256,
+					},
+				},
+				Status: cmapi.CertificateStatus{
+					NextPrivateKeySecretName: pointer.StringPtr("fixed-name"),
+					Conditions: []cmapi.CertificateCondition{
+						{
+							Type:   cmapi.CertificateConditionIssuing,
+							Status: cmmeta.ConditionTrue,
+						},
+					},
+				},
+			},
+			secrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "output"},
+				Data:       map[string][]byte{corev1.TLSPrivateKeyKey: rsa2048Key},
+			}},
+			expectedEvents: []string{
+				`Normal Regenerated Existing private key in Secret "output" does not match requirements on Certificate resource, generating new key. Mismatching fields: [spec.keyAlgorithm]`,
+				`Normal Generated Stored new private key in temporary Secret resource "fixed-name"`,
+			},
+			expectedActions: []testpkg.Action{
+				testpkg.NewCustomMatch(coretesting.NewCreateAction(
+					corev1.SchemeGroupVersion.WithResource("secrets"),
+					"testns",
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace:       "testns",
+							Name:            "fixed-name",
+							Labels:          map[string]string{cmapi.IsNextPrivateKeySecretLabelKey: "true"},
+							OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(&cmapi.Certificate{ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"}}, certificateGvk)},
+						},
+						Data: map[string][]byte{corev1.TLSPrivateKeyKey: nil},
+					},
+				), ecdsaP256SecretMatcher),
+			},
+		},
+		"derive the private key from the key seed of a SelfSigned issuer": {
+			certificate:    seededCertificate(cmapi.ECDSAKeyAlgorithm),
+			secrets:        []runtime.Object{keySeedSecret},
+			issuers:        []runtime.Object{seededIssuer},
+			expectedEvents: []string{`Normal Generated Stored new private key in temporary Secret resource "fixed-name"`},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewCreateAction(
+					corev1.SchemeGroupVersion.WithResource("secrets"),
+					"testns",
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace:       "testns",
+							Name:            "fixed-name",
+							Labels:          map[string]string{cmapi.IsNextPrivateKeySecretLabelKey: "true"},
+							OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(&cmapi.Certificate{ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"}}, certificateGvk)},
+						},
+						Data: map[string][]byte{"tls.key": mustDeriveECDSA(t, pki.ECCurve256, keySeed)},
+					},
+				)),
+			},
+		},
+		"do not create a secret if the key seed of a SelfSigned issuer cannot be used for the private key algorithm": {
+			certificate:    seededCertificate(cmapi.RSAKeyAlgorithm),
+			secrets:        []runtime.Object{keySeedSecret},
+			issuers:        []runtime.Object{seededIssuer},
+			expectedEvents: []string{`Warning KeySeedFailed Failed to derive private key from key seed of issuer "seeded": unsupported private key algorithm for deriving a key from a seed: "RSA", only "ECDSA" is supported`},
+		},
+		"do not create a secret if the key seed of a SelfSigned issuer is too short": {
+			certificate: seededCertificate(cmapi.ECDSAKeyAlgorithm),
+			secrets: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "key-seed"},
+				Data:       map[string][]byte{"seed": keySeed[:pki.MinKeySeedSize-1]},
+			}},
+			issuers:        []runtime.Object{seededIssuer},
+			expectedEvents: []string{fmt.Sprintf(`Warning KeySeedFailed Failed to read key seed of issuer "seeded": key seed in secret 'testns/key-seed' is too short: %d bytes. minimum seed size: %d bytes`, pki.MinKeySeedSize-1, pki.MinKeySeedSize)},
+		},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -477,6 +671,7 @@ func TestProcessItem(t *testing.T) {
 			if test.secrets != nil {
 				builder.KubeObjects = append(builder.KubeObjects, test.secrets...)
 			}
+			builder.CertManagerObjects = append(builder.CertManagerObjects, test.issuers...)
 			for _, req := range test.requests {
 				builder.CertManagerObjects = append(builder.CertManagerObjects, req)
 			}
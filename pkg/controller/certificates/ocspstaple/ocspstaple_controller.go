@@ -0,0 +1,326 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocspstaple
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/crypto/ocsp"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cminformers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/pkg/util/predicate"
+)
+
+const (
+	ControllerName = "certificates-ocsp-staple"
+
+	// defaultRefreshInterval is how long after it was produced an OCSP
+	// response without a nextUpdate time is refreshed.
+	defaultRefreshInterval = time.Hour
+
+	requestTimeout = time.Second * 30
+)
+
+// controller stores an OCSP response for the issued certificate in the
+// Secret of Certificates that have OCSP stapling enabled, and refreshes it
+// halfway through its validity period.
+type controller struct {
+	certificateLister cmlisters.CertificateLister
+	secretLister      corelisters.SecretLister
+	kubeClient        kubernetes.Interface
+	clock             clock.Clock
+	queue             workqueue.RateLimitingInterface
+
+	// httpClient is used to request OCSP responses from OCSP servers.
+	httpClient *http.Client
+}
+
+func NewController(
+	log logr.Logger,
+	kubeClient kubernetes.Interface,
+	factory informers.SharedInformerFactory,
+	cmFactory cminformers.SharedInformerFactory,
+	clock clock.Clock,
+) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
+	// create a queue used to queue up items to be processed
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Minute*5), ControllerName)
+
+	// obtain references to all the informers used by this controller
+	certificateInformer := cmFactory.Certmanager().V1().Certificates()
+	secretsInformer := factory.Core().V1().Secrets()
+
+	certificateInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: queue})
+	// When a Secret resource changes, enqueue any Certificate resources that name it as spec.secretName.
+	secretsInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: certificates.EnqueueCertificatesForResourceUsingPredicates(log, queue, certificateInformer.Lister(), labels.Everything(),
+			predicate.ExtractResourceName(predicate.CertificateSecretName)),
+	})
+
+	// build a list of InformerSynced functions that will be returned by the Register method.
+	// the controller will only begin processing items once all of these informers have synced.
+	mustSync := []cache.InformerSynced{
+		certificateInformer.Informer().HasSynced,
+		secretsInformer.Informer().HasSynced,
+	}
+
+	return &controller{
+		certificateLister: certificateInformer.Lister(),
+		secretLister:      secretsInformer.Lister(),
+		kubeClient:        kubeClient,
+		clock:             clock,
+		queue:             queue,
+		httpClient:        &http.Client{Timeout: requestTimeout},
+	}, queue, mustSync
+}
+
+// ProcessItem ensures that the Secret of a Certificate with OCSP stapling
+// enabled contains a current OCSP response for its certificate, and
+// schedules the response to be refreshed. The OCSP response is removed from
+// the Secret if OCSP stapling is disabled.
+func (c *controller) ProcessItem(ctx context.Context, key string) error {
+	log := logf.FromContext(ctx).WithValues("key", key)
+
+	ctx = logf.NewContext(ctx, log)
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		log.Error(err, "invalid resource key passed to ProcessItem")
+		return nil
+	}
+
+	crt, err := c.certificateLister.Certificates(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		log.V(logf.DebugLevel).Info("certificate not found for key")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	log = logf.WithResource(log, crt)
+
+	secret, err := c.secretLister.Secrets(crt.Namespace).Get(crt.Spec.SecretName)
+	if apierrors.IsNotFound(err) {
+		log.V(logf.DebugLevel).Info("secret not found for certificate")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	log = logf.WithRelatedResource(log, secret)
+	ctx = logf.NewContext(ctx, log)
+
+	// Only manage the staple in Secrets that were issued for this Certificate.
+	if secret.Annotations[cmapi.CertificateNameKey] != crt.Name {
+		log.V(logf.DebugLevel).Info("secret does not belong to the certificate")
+		return nil
+	}
+	if secret.Immutable != nil && *secret.Immutable {
+		log.V(logf.DebugLevel).Info("not managing OCSP staple of immutable secret")
+		return nil
+	}
+
+	if crt.Spec.OCSPStapling == nil || !crt.Spec.OCSPStapling.Enabled {
+		if _, ok := secret.Data[cmmeta.TLSOCSPStapleKey]; !ok {
+			return nil
+		}
+		log.V(logf.InfoLevel).Info("removing OCSP staple from secret as OCSP stapling is disabled")
+		secret = secret.DeepCopy()
+		delete(secret.Data, cmmeta.TLSOCSPStapleKey)
+		_, err := c.kubeClient.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+		return err
+	}
+
+	cert, issuer, err := certificateAndIssuer(secret)
+	if err != nil {
+		// The Secret will be updated once the certificate has been issued.
+		log.V(logf.DebugLevel).Info("not fetching OCSP staple", "reason", err.Error())
+		return nil
+	}
+	if len(cert.OCSPServer) == 0 {
+		log.V(logf.DebugLevel).Info("not fetching OCSP staple as the certificate does not name an OCSP server")
+		return nil
+	}
+
+	now := c.clock.Now()
+	if staple, ok := secret.Data[cmmeta.TLSOCSPStapleKey]; ok {
+		resp, err := ocsp.ParseResponseForCert(staple, cert, issuer)
+		// ParseResponseForCert fails if the staple is for another certificate.
+		if err == nil {
+			if refresh := refreshTime(resp); now.Before(refresh) {
+				log.V(logf.DebugLevel).Info("OCSP staple is current, scheduling refresh", "refresh_time", refresh)
+				c.queue.AddAfter(key, refresh.Sub(now))
+				return nil
+			}
+		}
+	}
+
+	staple, resp, err := c.fetchOCSPResponse(ctx, cert, issuer)
+	if err != nil {
+		return fmt.Errorf("failed to fetch OCSP staple: %w", err)
+	}
+
+	log.V(logf.InfoLevel).Info("storing OCSP staple in secret", "status", ocspStatus(resp.Status), "next_update", resp.NextUpdate)
+	secret = secret.DeepCopy()
+	secret.Data[cmmeta.TLSOCSPStapleKey] = staple
+	if _, err := c.kubeClient.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+
+	c.queue.AddAfter(key, refreshTime(resp).Sub(now))
+
+	return nil
+}
+
+// fetchOCSPResponse requests an OCSP response for the given certificate from
+// the first OCSP server named in it. The DER encoded response is returned
+// along with the parsed response.
+func (c *controller) fetchOCSPResponse(ctx context.Context, cert, issuer *x509.Certificate) ([]byte, *ocsp.Response, error) {
+	req, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, cert.OCSPServer[0], bytes.NewReader(req))
+	if err != nil {
+		return nil, nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/ocsp-request")
+	httpReq.Header.Set("Accept", "application/ocsp-response")
+	httpReq.Header.Set("User-Agent", util.CertManagerUserAgent)
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("unexpected status code %d from OCSP server %q", httpResp.StatusCode, cert.OCSPServer[0])
+	}
+
+	staple, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := ocsp.ParseResponseForCert(staple, cert, issuer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return staple, resp, nil
+}
+
+// certificateAndIssuer returns the certificate stored in the given Secret and
+// the certificate of its issuer, which is the next certificate in the chain
+// or, if the chain does not include it, the CA certificate.
+func certificateAndIssuer(secret *corev1.Secret) (*x509.Certificate, *x509.Certificate, error) {
+	chain, err := pki.DecodeX509CertificateChainBytes(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return nil, nil, err
+	}
+	cert := chain[0]
+
+	candidates := chain[1:]
+	if ca, err := pki.DecodeX509CertificateChainBytes(secret.Data[cmmeta.TLSCAKey]); err == nil {
+		candidates = append(candidates, ca...)
+	}
+	for _, issuer := range candidates {
+		if cert.CheckSignatureFrom(issuer) == nil {
+			return cert, issuer, nil
+		}
+	}
+
+	return nil, nil, errors.New("the certificate of the issuer is not stored in the secret")
+}
+
+// refreshTime returns the time at which the given OCSP response should be
+// refreshed, which is halfway through its validity period.
+func refreshTime(resp *ocsp.Response) time.Time {
+	if resp.NextUpdate.IsZero() {
+		return resp.ThisUpdate.Add(defaultRefreshInterval)
+	}
+	return resp.ThisUpdate.Add(resp.NextUpdate.Sub(resp.ThisUpdate) / 2)
+}
+
+func ocspStatus(status int) string {
+	switch status {
+	case ocsp.Good:
+		return "Good"
+	case ocsp.Revoked:
+		return "Revoked"
+	default:
+		return "Unknown"
+	}
+}
+
+// controllerWrapper wraps the `controller` structure to make it implement
+// the controllerpkg.queueingController interface
+type controllerWrapper struct {
+	*controller
+}
+
+func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
+	// construct a new named logger to be reused throughout the controller
+	log := logf.FromContext(ctx.RootContext, ControllerName)
+
+	ctrl, queue, mustSync := NewController(
+		log,
+		ctx.Client,
+		ctx.KubeSharedInformerFactory,
+		ctx.SharedInformerFactory,
+		ctx.Clock,
+	)
+	c.controller = ctrl
+
+	return queue, mustSync, nil
+}
+
+func init() {
+	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		return controllerpkg.NewBuilder(ctx, ControllerName).
+			For(&controllerWrapper{}).
+			Complete()
+	})
+}
@@ -0,0 +1,311 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocspstaple
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coretesting "k8s.io/client-go/testing"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func mustGenerateCert(t *testing.T, template *x509.Certificate, parent *x509.Certificate, parentKey crypto.Signer) (*x509.Certificate, crypto.Signer) {
+	key, err := pki.GenerateECPrivateKey(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(nil, template, parent, key.Public(), parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func mustEncodeCert(t *testing.T, cert *x509.Certificate) []byte {
+	pem, err := pki.EncodeX509(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem
+}
+
+func mustCreateResponse(t *testing.T, issuer *x509.Certificate, issuerKey crypto.Signer, serial *big.Int, thisUpdate, nextUpdate time.Time) []byte {
+	resp, err := ocsp.CreateResponse(issuer, issuer, ocsp.Response{
+		Status:       ocsp.Good,
+		SerialNumber: serial,
+		ThisUpdate:   thisUpdate,
+		NextUpdate:   nextUpdate,
+	}, issuerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestProcessItem(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+
+	// responder serves the response for the current test case.
+	var (
+		responseStatus int
+		response       []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/ocsp-request" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if _, err := ocsp.ParseRequest(mustReadAll(t, r)); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(responseStatus)
+		w.Write(response)
+	}))
+	defer server.Close()
+
+	ca, caKey := mustGenerateCert(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour * 24 * 365),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}, nil, nil)
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour * 24 * 90),
+		OCSPServer:   []string{server.URL},
+	}
+	leaf, _ := mustGenerateCert(t, leafTemplate, ca, caKey)
+	leafTemplate.OCSPServer = nil
+	leafWithoutOCSP, _ := mustGenerateCert(t, leafTemplate, ca, caKey)
+
+	currentStaple := mustCreateResponse(t, ca, caKey, leaf.SerialNumber, now.Add(-time.Hour), now.Add(time.Hour*24))
+	staleStaple := mustCreateResponse(t, ca, caKey, leaf.SerialNumber, now.Add(-time.Hour*48), now.Add(time.Hour))
+	otherStaple := mustCreateResponse(t, ca, caKey, big.NewInt(3), now.Add(-time.Hour), now.Add(time.Hour*24))
+
+	baseCrt := gen.Certificate("test-cert",
+		gen.SetCertificateNamespace("testns"),
+		gen.SetCertificateSecretName("test-secret"),
+		gen.SetCertificateOCSPStapling(true),
+	)
+	baseSecret := gen.Secret("test-secret",
+		gen.SetSecretNamespace("testns"),
+		gen.SetSecretAnnotations(map[string]string{cmapi.CertificateNameKey: "test-cert"}),
+		gen.SetSecretData(map[string][]byte{
+			corev1.TLSCertKey: mustEncodeCert(t, leaf),
+			cmmeta.TLSCAKey:   mustEncodeCert(t, ca),
+		}),
+	)
+	withStaple := func(staple []byte) gen.SecretModifier {
+		return func(secret *corev1.Secret) {
+			secret.Data[cmmeta.TLSOCSPStapleKey] = staple
+		}
+	}
+	updateSecret := func(secret *corev1.Secret) testpkg.Action {
+		return testpkg.NewAction(coretesting.NewUpdateAction(corev1.SchemeGroupVersion.WithResource("secrets"), "testns", secret))
+	}
+
+	tests := map[string]struct {
+		// certificate to be synced for the test.
+		certificate *cmapi.Certificate
+		// secret, if set, will exist in the apiserver before the test is run.
+		secret *corev1.Secret
+
+		// responseStatus and response are returned by the OCSP server.
+		responseStatus int
+		response       []byte
+
+		expectedActions []testpkg.Action
+		expectedErr     bool
+	}{
+		"store an OCSP staple in the Secret": {
+			certificate:     baseCrt,
+			secret:          baseSecret,
+			responseStatus:  http.StatusOK,
+			response:        currentStaple,
+			expectedActions: []testpkg.Action{updateSecret(gen.SecretFrom(baseSecret, withStaple(currentStaple)))},
+		},
+		"do nothing if the Secret contains a current OCSP staple": {
+			certificate: baseCrt,
+			secret:      gen.SecretFrom(baseSecret, withStaple(currentStaple)),
+		},
+		"refresh an OCSP staple that is past its refresh time": {
+			certificate:     baseCrt,
+			secret:          gen.SecretFrom(baseSecret, withStaple(staleStaple)),
+			responseStatus:  http.StatusOK,
+			response:        currentStaple,
+			expectedActions: []testpkg.Action{updateSecret(gen.SecretFrom(baseSecret, withStaple(currentStaple)))},
+		},
+		"replace an OCSP staple for a different certificate": {
+			certificate:     baseCrt,
+			secret:          gen.SecretFrom(baseSecret, withStaple(otherStaple)),
+			responseStatus:  http.StatusOK,
+			response:        currentStaple,
+			expectedActions: []testpkg.Action{updateSecret(gen.SecretFrom(baseSecret, withStaple(currentStaple)))},
+		},
+		"return an error if the OCSP server returns a response for a different certificate": {
+			certificate:    baseCrt,
+			secret:         baseSecret,
+			responseStatus: http.StatusOK,
+			response:       otherStaple,
+			expectedErr:    true,
+		},
+		"return an error if the OCSP server fails": {
+			certificate:    baseCrt,
+			secret:         baseSecret,
+			responseStatus: http.StatusInternalServerError,
+			expectedErr:    true,
+		},
+		"do nothing if the certificate does not name an OCSP server": {
+			certificate: baseCrt,
+			secret: gen.SecretFrom(baseSecret, gen.SetSecretData(map[string][]byte{
+				corev1.TLSCertKey: mustEncodeCert(t, leafWithoutOCSP),
+				cmmeta.TLSCAKey:   mustEncodeCert(t, ca),
+			})),
+		},
+		"do nothing if the Secret does not contain a certificate": {
+			certificate: baseCrt,
+			secret:      gen.SecretFrom(baseSecret, gen.SetSecretData(map[string][]byte{})),
+		},
+		"do nothing if the Secret does not exist": {
+			certificate: baseCrt,
+		},
+		"do nothing if the Secret does not belong to the Certificate": {
+			certificate: baseCrt,
+			secret: gen.SecretFrom(baseSecret,
+				gen.SetSecretAnnotations(map[string]string{cmapi.CertificateNameKey: "another-cert"}),
+			),
+		},
+		"remove the OCSP staple if OCSP stapling is disabled": {
+			certificate:     gen.CertificateFrom(baseCrt, gen.SetCertificateOCSPStapling(false)),
+			secret:          gen.SecretFrom(baseSecret, withStaple(currentStaple)),
+			expectedActions: []testpkg.Action{updateSecret(baseSecret)},
+		},
+		"do nothing if OCSP stapling is disabled and the Secret has no OCSP staple": {
+			certificate: gen.CertificateFrom(baseCrt, gen.SetCertificateOCSPStapling(false)),
+			secret:      baseSecret,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			responseStatus, response = test.responseStatus, test.response
+
+			// Create and initialise a new unit test builder
+			builder := &testpkg.Builder{
+				T:                  t,
+				Clock:              fakeclock.NewFakeClock(now),
+				CertManagerObjects: []runtime.Object{test.certificate},
+				ExpectedActions:    test.expectedActions,
+			}
+			if test.secret != nil {
+				builder.KubeObjects = append(builder.KubeObjects, test.secret)
+			}
+			builder.Init()
+
+			// Register informers used by the controller using the registration wrapper
+			w := &controllerWrapper{}
+			_, _, err := w.Register(builder.Context)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Start the informers and begin processing updates
+			builder.Start()
+			defer builder.Stop()
+
+			key, err := controllerpkg.KeyFunc(test.certificate)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = w.controller.ProcessItem(context.Background(), key)
+			if test.expectedErr != (err != nil) {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expectedErr, err)
+			}
+
+			if err := builder.AllEventsCalled(); err != nil {
+				builder.T.Error(err)
+			}
+			if err := builder.AllActionsExecuted(); err != nil {
+				builder.T.Error(err)
+			}
+		})
+	}
+}
+
+func TestRefreshTime(t *testing.T) {
+	thisUpdate := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		nextUpdate time.Time
+		expected   time.Time
+	}{
+		"refresh halfway through the validity period": {
+			nextUpdate: thisUpdate.Add(time.Hour * 24),
+			expected:   thisUpdate.Add(time.Hour * 12),
+		},
+		"refresh after the default interval if no nextUpdate is set": {
+			expected: thisUpdate.Add(defaultRefreshInterval),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := refreshTime(&ocsp.Response{ThisUpdate: thisUpdate, NextUpdate: test.nextUpdate})
+			if !got.Equal(test.expected) {
+				t.Errorf("unexpected refresh time, exp=%s got=%s", test.expected, got)
+			}
+		})
+	}
+}
+
+func mustReadAll(t *testing.T, r *http.Request) []byte {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
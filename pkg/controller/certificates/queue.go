@@ -0,0 +1,251 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
+
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+)
+
+// NewExpiryPriorityQueue returns a rate limiting work queue of Certificate
+// keys that hands out the key of the Certificate that expires soonest first,
+// rather than the key that was queued first. Certificates that have not yet
+// been issued, or that no longer exist, are handed out before any others.
+// Certificates with the same expiry are handed out in the order they were
+// queued.
+// This allows controllers to handle the most urgent Certificates first when
+// a backlog has built up, e.g. after an outage.
+func NewExpiryPriorityQueue(rateLimiter workqueue.RateLimiter, lister cmlisters.CertificateLister, clock clock.Clock) workqueue.RateLimitingInterface {
+	return newPriorityQueue(certificateExpiry(lister), rateLimiter, clock)
+}
+
+// certificateExpiry returns a function that returns the expiry time of the
+// Certificate with the given key, or the zero time if it is not known.
+func certificateExpiry(lister cmlisters.CertificateLister) func(item interface{}) time.Time {
+	return func(item interface{}) time.Time {
+		key, ok := item.(string)
+		if !ok {
+			return time.Time{}
+		}
+		namespace, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			return time.Time{}
+		}
+		crt, err := lister.Certificates(namespace).Get(name)
+		if err != nil || crt.Status.NotAfter == nil {
+			return time.Time{}
+		}
+		return crt.Status.NotAfter.Time
+	}
+}
+
+// priorityQueue is a workqueue.RateLimitingInterface that hands out items in
+// order of a deadline, earliest first. Like the queues in client-go, an item
+// is only queued once however many times it is added, and an item that is
+// added while being processed is queued again once it is marked as done.
+type priorityQueue struct {
+	deadline    func(item interface{}) time.Time
+	rateLimiter workqueue.RateLimiter
+	clock       clock.Clock
+
+	cond *sync.Cond
+	// queue holds the items waiting to be processed.
+	queue priorityHeap
+	// seq is the sequence number given to the next item that is queued, used
+	// to preserve the queued order of items with the same deadline.
+	seq uint64
+	// dirty holds the items that need to be processed.
+	dirty map[interface{}]struct{}
+	// processing holds the items that are currently being processed.
+	processing map[interface{}]struct{}
+
+	shuttingDown bool
+	stopCh       chan struct{}
+}
+
+func newPriorityQueue(deadline func(item interface{}) time.Time, rateLimiter workqueue.RateLimiter, clock clock.Clock) *priorityQueue {
+	return &priorityQueue{
+		deadline:    deadline,
+		rateLimiter: rateLimiter,
+		clock:       clock,
+		cond:        sync.NewCond(&sync.Mutex{}),
+		dirty:       make(map[interface{}]struct{}),
+		processing:  make(map[interface{}]struct{}),
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// Add marks item as needing processing.
+func (q *priorityQueue) Add(item interface{}) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	if q.shuttingDown {
+		return
+	}
+	if _, ok := q.dirty[item]; ok {
+		return
+	}
+
+	q.dirty[item] = struct{}{}
+	if _, ok := q.processing[item]; ok {
+		return
+	}
+
+	q.push(item)
+	q.cond.Signal()
+}
+
+// push must be called with the lock held.
+func (q *priorityQueue) push(item interface{}) {
+	heap.Push(&q.queue, &priorityItem{item: item, deadline: q.deadline(item), seq: q.seq})
+	q.seq++
+}
+
+// Len returns the number of items waiting to be processed.
+func (q *priorityQueue) Len() int {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.queue.Len()
+}
+
+// Get blocks until it can return the item with the earliest deadline. If
+// shutdown is true, the caller should end their goroutine. Done must be
+// called with the item once it has been processed.
+func (q *priorityQueue) Get() (item interface{}, shutdown bool) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	for q.queue.Len() == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	if q.queue.Len() == 0 {
+		// We must be shutting down.
+		return nil, true
+	}
+
+	item = heap.Pop(&q.queue).(*priorityItem).item
+	q.processing[item] = struct{}{}
+	delete(q.dirty, item)
+
+	return item, false
+}
+
+// Done marks item as done processing, and if it has been marked as dirty
+// again while it was being processed, it will be queued again.
+func (q *priorityQueue) Done(item interface{}) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	delete(q.processing, item)
+	if _, ok := q.dirty[item]; ok {
+		q.push(item)
+		q.cond.Signal()
+	}
+}
+
+// ShutDown causes Get to return shutdown once all queued items have been
+// handed out, and causes items that are added afterwards to be ignored.
+func (q *priorityQueue) ShutDown() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	if q.shuttingDown {
+		return
+	}
+	q.shuttingDown = true
+	close(q.stopCh)
+	q.cond.Broadcast()
+}
+
+func (q *priorityQueue) ShuttingDown() bool {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.shuttingDown
+}
+
+// AddAfter adds item to the queue once the given duration has passed.
+func (q *priorityQueue) AddAfter(item interface{}, duration time.Duration) {
+	if q.ShuttingDown() {
+		return
+	}
+	if duration <= 0 {
+		q.Add(item)
+		return
+	}
+
+	go func() {
+		select {
+		case <-q.clock.After(duration):
+			q.Add(item)
+		case <-q.stopCh:
+		}
+	}()
+}
+
+// AddRateLimited adds item to the queue once the rate limiter says it is ok.
+func (q *priorityQueue) AddRateLimited(item interface{}) {
+	q.AddAfter(item, q.rateLimiter.When(item))
+}
+
+// Forget indicates that an item is finished being retried.
+func (q *priorityQueue) Forget(item interface{}) {
+	q.rateLimiter.Forget(item)
+}
+
+// NumRequeues returns how many times the item was requeued.
+func (q *priorityQueue) NumRequeues(item interface{}) int {
+	return q.rateLimiter.NumRequeues(item)
+}
+
+type priorityItem struct {
+	item     interface{}
+	deadline time.Time
+	seq      uint64
+}
+
+// priorityHeap implements heap.Interface, ordering items by their deadline
+// and then by the order in which they were queued.
+type priorityHeap []*priorityItem
+
+func (h priorityHeap) Len() int { return len(h) }
+
+func (h priorityHeap) Less(i, j int) bool {
+	if h[i].deadline.Equal(h[j].deadline) {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].deadline.Before(h[j].deadline)
+}
+
+func (h priorityHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *priorityHeap) Push(x interface{}) {
+	*h = append(*h, x.(*priorityItem))
+}
+
+func (h *priorityHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestExpiryPriorityQueue(t *testing.T) {
+	now := time.Now()
+	notAfter := func(t time.Time) gen.CertificateModifier {
+		return func(crt *cmapi.Certificate) {
+			crt.Status.NotAfter = &metav1.Time{Time: t}
+		}
+	}
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, crt := range []*cmapi.Certificate{
+		gen.Certificate("expires-later", gen.SetCertificateNamespace("testns"), notAfter(now.Add(time.Hour*24*30))),
+		gen.Certificate("also-expires-later", gen.SetCertificateNamespace("testns"), notAfter(now.Add(time.Hour*24*30))),
+		gen.Certificate("expires-soon", gen.SetCertificateNamespace("testns"), notAfter(now.Add(time.Hour*24))),
+		gen.Certificate("expired", gen.SetCertificateNamespace("testns"), notAfter(now.Add(-time.Hour))),
+		gen.Certificate("not-issued", gen.SetCertificateNamespace("testns")),
+	} {
+		if err := indexer.Add(crt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	queue := NewExpiryPriorityQueue(workqueue.DefaultControllerRateLimiter(), cmlisters.NewCertificateLister(indexer), fakeclock.NewFakeClock(now))
+	defer queue.ShutDown()
+	for _, key := range []string{
+		"testns/expires-later",
+		"testns/expires-soon",
+		"testns/also-expires-later",
+		"testns/expired",
+		"testns/not-issued",
+		"testns/does-not-exist",
+	} {
+		queue.Add(key)
+	}
+
+	expected := []string{
+		"testns/not-issued",
+		"testns/does-not-exist",
+		"testns/expired",
+		"testns/expires-soon",
+		"testns/expires-later",
+		"testns/also-expires-later",
+	}
+	for _, exp := range expected {
+		item, shutdown := queue.Get()
+		if shutdown {
+			t.Fatal("unexpected shutdown of queue")
+		}
+		if item != exp {
+			t.Errorf("unexpected item processed, exp=%q got=%q", exp, item)
+		}
+		queue.Done(item)
+	}
+	if l := queue.Len(); l != 0 {
+		t.Errorf("expected queue to be empty, got %d items", l)
+	}
+}
+
+func TestPriorityQueueDeduplicatesItems(t *testing.T) {
+	queue := newPriorityQueue(func(interface{}) time.Time { return time.Time{} },
+		workqueue.DefaultControllerRateLimiter(), fakeclock.NewFakeClock(time.Now()))
+	defer queue.ShutDown()
+
+	queue.Add("a")
+	queue.Add("a")
+	if l := queue.Len(); l != 1 {
+		t.Fatalf("expected 1 queued item, got %d", l)
+	}
+
+	item, _ := queue.Get()
+	// Adding an item that is being processed should only queue it again
+	// once it has been processed.
+	queue.Add(item)
+	if l := queue.Len(); l != 0 {
+		t.Fatalf("expected 0 queued items while processing, got %d", l)
+	}
+	queue.Done(item)
+	if l := queue.Len(); l != 1 {
+		t.Fatalf("expected 1 queued item after processing, got %d", l)
+	}
+}
+
+func TestPriorityQueueAddAfter(t *testing.T) {
+	clock := fakeclock.NewFakeClock(time.Now())
+	queue := newPriorityQueue(func(interface{}) time.Time { return time.Time{} },
+		workqueue.DefaultControllerRateLimiter(), clock)
+	defer queue.ShutDown()
+
+	queue.AddAfter("a", time.Minute)
+	if err := waitFor(clock.HasWaiters); err != nil {
+		t.Fatal(err)
+	}
+	if l := queue.Len(); l != 0 {
+		t.Fatalf("expected 0 queued items before the delay has passed, got %d", l)
+	}
+
+	clock.Step(time.Minute)
+	if err := waitFor(func() bool { return queue.Len() == 1 }); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPriorityQueueShutDown(t *testing.T) {
+	queue := newPriorityQueue(func(interface{}) time.Time { return time.Time{} },
+		workqueue.DefaultControllerRateLimiter(), fakeclock.NewFakeClock(time.Now()))
+
+	queue.Add("a")
+	queue.ShutDown()
+	queue.Add("b")
+
+	if item, shutdown := queue.Get(); shutdown || item != "a" {
+		t.Errorf("expected queued item to be processed after shutdown, got item=%v shutdown=%t", item, shutdown)
+	}
+	if item, shutdown := queue.Get(); !shutdown {
+		t.Errorf("expected shutdown once queue is empty, got item=%v", item)
+	}
+}
+
+func waitFor(condition func() bool) error {
+	return wait.PollImmediate(time.Millisecond*10, time.Second*5, func() (bool, error) {
+		return condition(), nil
+	})
+}
@@ -18,6 +18,8 @@ package readiness
 
 import (
 	"context"
+	"crypto/x509"
+	"fmt"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -29,10 +31,12 @@ import (
 	"k8s.io/client-go/informers"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/utils/clock"
 
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
@@ -41,6 +45,7 @@ import (
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/trigger/policies"
+	"github.com/jetstack/cert-manager/pkg/issuer"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
 	"github.com/jetstack/cert-manager/pkg/util/predicate"
@@ -49,6 +54,22 @@ import (
 const (
 	ControllerName = "certificates-readiness"
 	ReadyReason    = "Ready"
+	// DeprecatedReason is the reason of the Warning Events emitted when a
+	// Certificate makes use of a deprecated API version or field
+	DeprecatedReason = "Deprecated"
+	// RenewBeforeExceedsDurationReason is the reason of the
+	// RenewBeforeAdjusted condition and the Warning Event emitted when the
+	// issued certificate is valid for no longer than the requested
+	// renewBefore.
+	RenewBeforeExceedsDurationReason = "RenewBeforeExceedsDuration"
+	// IssuerNotFoundReason is the reason of the IssuerNotFound condition, and
+	// of the Ready condition if it is set to False, when the issuer
+	// referenced by a Certificate does not exist.
+	IssuerNotFoundReason = "IssuerNotFound"
+	// IssuerNotReadyReason is the reason of the IssuerNotReady condition, and
+	// of the Warning Event emitted when it is set, when the issuer referenced
+	// by a Certificate has not been Ready for longer than the threshold.
+	IssuerNotReadyReason = "IssuerNotReady"
 )
 
 type controller struct {
@@ -58,11 +79,29 @@ type controller struct {
 	certificateRequestLister cmlisters.CertificateRequestLister
 	secretLister             corelisters.SecretLister
 	client                   cmclient.Interface
+	recorder                 record.EventRecorder
 	gatherer                 *policies.Gatherer
 	// policyEvaluator builds Ready condition of a Certificate based on policy evaluation
 	policyEvaluator policyEvaluatorFunc
 	// renewalTimeCalculator calculates renewal time of a certificate
 	renewalTimeCalculator certificates.RenewalTimeFunc
+	// defaultRenewBefore and defaultRenewBeforePercentage are the renewBefore
+	// used for Certificates that do not set spec.renewBefore, as given to
+	// the renewalTimeCalculator
+	defaultRenewBefore           time.Duration
+	defaultRenewBeforePercentage int
+	issuerHelper                 issuer.Helper
+	// keepReadyWithoutIssuer, if true, leaves the Ready condition of a
+	// Certificate whose issuer does not exist to be determined by its policy
+	// chain, so that an existing valid certificate continues to be reported
+	// as Ready until the issuer is recreated.
+	keepReadyWithoutIssuer bool
+	// issuerNotReadyThreshold is how long the issuer referenced by a
+	// Certificate must have not been Ready before the IssuerNotReady
+	// condition is set. If zero, the condition is never set.
+	issuerNotReadyThreshold time.Duration
+	clock                   clock.Clock
+	queue                   workqueue.RateLimitingInterface
 }
 
 // readyConditionFunc is custom function type that builds certificate's Ready condition
@@ -71,11 +110,18 @@ type policyEvaluatorFunc func(policies.Chain, policies.Input) cmapi.CertificateC
 func NewController(
 	log logr.Logger,
 	client cmclient.Interface,
+	recorder record.EventRecorder,
 	factory informers.SharedInformerFactory,
 	cmFactory cminformers.SharedInformerFactory,
 	chain policies.Chain,
 	renewalTimeCalculator certificates.RenewalTimeFunc,
+	defaultRenewBefore time.Duration,
+	defaultRenewBeforePercentage int,
 	policyEvaluator policyEvaluatorFunc,
+	namespace string,
+	keepReadyWithoutIssuer bool,
+	issuerNotReadyThreshold time.Duration,
+	clock clock.Clock,
 ) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
 	// create a queue used to queue up items to be processed
 	queue := workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
@@ -84,6 +130,7 @@ func NewController(
 	certificateInformer := cmFactory.Certmanager().V1().Certificates()
 	certificateRequestInformer := cmFactory.Certmanager().V1().CertificateRequests()
 	secretsInformer := factory.Core().V1().Secrets()
+	issuerInformer := cmFactory.Certmanager().V1().Issuers()
 
 	certificateInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: queue})
 
@@ -97,6 +144,10 @@ func NewController(
 		WorkFunc: certificates.EnqueueCertificatesForResourceUsingPredicates(log, queue, certificateInformer.Lister(), labels.Everything(),
 			predicate.ExtractResourceName(predicate.CertificateSecretName)),
 	})
+	// When an Issuer is created, updated or deleted, enqueue any Certificate resources that reference it.
+	issuerInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: certificates.EnqueueCertificatesForGenericIssuer(log, queue, certificateInformer.Lister()),
+	})
 
 	// build a list of InformerSynced functions that will be returned by the Register method.
 	// the controller will only begin processing items once all of these informers have synced.
@@ -104,6 +155,19 @@ func NewController(
 		certificateRequestInformer.Informer().HasSynced,
 		secretsInformer.Informer().HasSynced,
 		certificateInformer.Informer().HasSynced,
+		issuerInformer.Informer().HasSynced,
+	}
+
+	// if we are running in non-namespaced mode (i.e. --namespace=""), we also
+	// watch clusterissuers.
+	var clusterIssuerLister cmlisters.ClusterIssuerLister
+	if namespace == "" {
+		clusterIssuerInformer := cmFactory.Certmanager().V1().ClusterIssuers()
+		clusterIssuerInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+			WorkFunc: certificates.EnqueueCertificatesForGenericIssuer(log, queue, certificateInformer.Lister()),
+		})
+		clusterIssuerLister = clusterIssuerInformer.Lister()
+		mustSync = append(mustSync, clusterIssuerInformer.Informer().HasSynced)
 	}
 
 	return &controller{
@@ -112,12 +176,20 @@ func NewController(
 		certificateRequestLister: certificateRequestInformer.Lister(),
 		secretLister:             secretsInformer.Lister(),
 		client:                   client,
+		recorder:                 recorder,
 		gatherer: &policies.Gatherer{
 			CertificateRequestLister: certificateRequestInformer.Lister(),
 			SecretLister:             secretsInformer.Lister(),
 		},
-		policyEvaluator:       policyEvaluator,
-		renewalTimeCalculator: renewalTimeCalculator,
+		policyEvaluator:              policyEvaluator,
+		renewalTimeCalculator:        renewalTimeCalculator,
+		defaultRenewBefore:           defaultRenewBefore,
+		defaultRenewBeforePercentage: defaultRenewBeforePercentage,
+		issuerHelper:                 issuer.NewHelper(issuerInformer.Lister(), clusterIssuerLister),
+		keepReadyWithoutIssuer:       keepReadyWithoutIssuer,
+		issuerNotReadyThreshold:      issuerNotReadyThreshold,
+		clock:                        clock,
+		queue:                        queue,
 	}, queue, mustSync
 }
 
@@ -140,15 +212,50 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		return err
 	}
 
+	// surface any use of deprecated API versions or fields to the user so
+	// that they are visible when describing the Certificate
+	for _, warning := range certificates.DeprecationWarnings(crt) {
+		c.recorder.Event(crt, corev1.EventTypeWarning, DeprecatedReason, warning)
+	}
+
+	if crt.Spec.Replicas != nil {
+		// The Ready condition of Certificates with replicas is managed by
+		// the replicas controller, as they are never issued themselves.
+		return nil
+	}
+
 	input, err := c.gatherer.DataForCertificate(ctx, crt)
 	if err != nil {
 		return err
 	}
 
+	issuerNotFound := c.issuerNotFound(crt)
+	issuerNotReady, recheckAfter := c.issuerNotReady(crt)
+
 	condition := c.policyEvaluator(c.policyChain, input)
+	if issuerNotFound != "" && !c.keepReadyWithoutIssuer {
+		condition.Status = cmmeta.ConditionFalse
+		condition.Reason = IssuerNotFoundReason
+		condition.Message = issuerNotFound
+	}
 	oldCrt := crt
 	crt = crt.DeepCopy()
 	apiutil.SetCertificateCondition(crt, crt.Generation, condition.Type, condition.Status, condition.Reason, condition.Message)
+	if issuerNotFound != "" {
+		apiutil.SetCertificateCondition(crt, crt.Generation, cmapi.CertificateConditionIssuerNotFound, cmmeta.ConditionTrue, IssuerNotFoundReason, issuerNotFound)
+	} else {
+		apiutil.RemoveCertificateCondition(crt, cmapi.CertificateConditionIssuerNotFound)
+	}
+	// the Ready condition is left unchanged when the issuer is not ready, as
+	// the existing certificate can still be served
+	if issuerNotReady != "" {
+		if !apiutil.CertificateHasCondition(crt, cmapi.CertificateCondition{Type: cmapi.CertificateConditionIssuerNotReady, Status: cmmeta.ConditionTrue}) {
+			c.recorder.Event(crt, corev1.EventTypeWarning, IssuerNotReadyReason, issuerNotReady)
+		}
+		apiutil.SetCertificateCondition(crt, crt.Generation, cmapi.CertificateConditionIssuerNotReady, cmmeta.ConditionTrue, IssuerNotReadyReason, issuerNotReady)
+	} else {
+		apiutil.RemoveCertificateCondition(crt, cmapi.CertificateConditionIssuerNotReady)
+	}
 
 	switch {
 	case input.Secret != nil && input.Secret.Data != nil:
@@ -158,6 +265,7 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 			crt.Status.NotAfter = nil
 			crt.Status.NotBefore = nil
 			crt.Status.RenewalTime = nil
+			apiutil.RemoveCertificateCondition(crt, cmapi.CertificateConditionRenewBeforeAdjusted)
 			break
 		}
 
@@ -170,11 +278,14 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		crt.Status.NotAfter = &notAfter
 		crt.Status.RenewalTime = renewalTime
 
+		c.setRenewBeforeAdjustedCondition(crt, x509cert)
+
 	default:
 		// clear status fields if the secret does not have any data
 		crt.Status.NotAfter = nil
 		crt.Status.NotBefore = nil
 		crt.Status.RenewalTime = nil
+		apiutil.RemoveCertificateCondition(crt, cmapi.CertificateConditionRenewBeforeAdjusted)
 	}
 	if !apiequality.Semantic.DeepEqual(oldCrt.Status, crt.Status) {
 		log.V(logf.DebugLevel).Info("updating status fields", "notAfter",
@@ -185,10 +296,115 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 			return err
 		}
 	}
+	if recheckAfter > 0 {
+		// check the Certificate again once its issuer will have not been
+		// ready for longer than the threshold
+		c.queue.AddAfter(key, recheckAfter)
+	}
 	return nil
 
 }
 
+// setRenewBeforeAdjustedCondition sets the RenewBeforeAdjusted condition on
+// the given Certificate if the issued certificate is valid for no longer than
+// the requested spec.renewBefore, or the default renewBefore if it is not set
+// and no default percentage of the certificate lifetime is configured.
+// As validation requires spec.duration to be longer than renewBefore, this
+// only happens if the issuer enforces a shorter duration than requested. In
+// that case the renewal time has been adjusted so that the certificate is not
+// immediately renewed again, and the user is warned that renewBefore is not
+// being honoured. Otherwise the condition is removed.
+func (c *controller) setRenewBeforeAdjustedCondition(crt *cmapi.Certificate, x509cert *x509.Certificate) {
+	actualDuration := x509cert.NotAfter.Sub(x509cert.NotBefore)
+	requested, requestedDesc := c.defaultRenewBefore, "default"
+	if crt.Spec.RenewBefore != nil {
+		requested, requestedDesc = crt.Spec.RenewBefore.Duration, "requested"
+	} else if c.defaultRenewBeforePercentage > 0 {
+		// a percentage of the lifetime never needs to be adjusted
+		apiutil.RemoveCertificateCondition(crt, cmapi.CertificateConditionRenewBeforeAdjusted)
+		return
+	}
+	if requested < actualDuration {
+		apiutil.RemoveCertificateCondition(crt, cmapi.CertificateConditionRenewBeforeAdjusted)
+		return
+	}
+
+	renewBefore := certificates.RenewBeforeExpiryDuration(x509cert.NotBefore, x509cert.NotAfter, crt.Spec.RenewBefore, c.defaultRenewBefore, c.defaultRenewBeforePercentage)
+	message := fmt.Sprintf("The issued certificate is valid for %s, which is not longer than the %s renewBefore of %s, so it will be renewed %s before it expires instead",
+		actualDuration, requestedDesc, requested, renewBefore)
+
+	existing := apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionRenewBeforeAdjusted)
+	if existing == nil || existing.Message != message {
+		c.recorder.Event(crt, corev1.EventTypeWarning, RenewBeforeExceedsDurationReason, message)
+	}
+	apiutil.SetCertificateCondition(crt, crt.Generation, cmapi.CertificateConditionRenewBeforeAdjusted, cmmeta.ConditionTrue, RenewBeforeExceedsDurationReason, message)
+}
+
+// issuerNotFound returns a message describing the missing issuer if the
+// cert-manager issuer referenced by the given Certificate does not exist, or
+// an empty string if it exists or is an external issuer, which cannot be
+// looked up. Other errors, such as an invalid issuerRef kind, are reported
+// by the issuing controllers and are ignored here.
+func (c *controller) issuerNotFound(crt *cmapi.Certificate) string {
+	ref := crt.Spec.IssuerRef
+	if !(ref.Group == "" || ref.Group == certmanager.GroupName) {
+		return ""
+	}
+
+	_, err := c.issuerHelper.GetGenericIssuer(ref, crt.Namespace)
+	if !apierrors.IsNotFound(err) {
+		return ""
+	}
+	kind := ref.Kind
+	if kind == "" {
+		kind = cmapi.IssuerKind
+	}
+	return fmt.Sprintf("Referenced %s %q does not exist", kind, ref.Name)
+}
+
+// issuerNotReady returns a message describing the issuer if the cert-manager
+// issuer referenced by the given Certificate has not been Ready for at least
+// issuerNotReadyThreshold, or an empty string otherwise. If the issuer is not
+// Ready but the threshold has not yet elapsed, the time remaining until it
+// will have is also returned so that the Certificate can be checked again.
+// Issuers that have no Ready condition yet have not been processed by their
+// controller, and are not considered to be not Ready.
+func (c *controller) issuerNotReady(crt *cmapi.Certificate) (string, time.Duration) {
+	if c.issuerNotReadyThreshold <= 0 {
+		return "", 0
+	}
+	ref := crt.Spec.IssuerRef
+	if !(ref.Group == "" || ref.Group == certmanager.GroupName) {
+		return "", 0
+	}
+
+	issuerObj, err := c.issuerHelper.GetGenericIssuer(ref, crt.Namespace)
+	if err != nil {
+		return "", 0
+	}
+	var ready *cmapi.IssuerCondition
+	for i, cond := range issuerObj.GetStatus().Conditions {
+		if cond.Type == cmapi.IssuerConditionReady {
+			ready = &issuerObj.GetStatus().Conditions[i]
+			break
+		}
+	}
+	if ready == nil || ready.Status == cmmeta.ConditionTrue || ready.LastTransitionTime == nil {
+		return "", 0
+	}
+
+	notReadyFor := c.clock.Since(ready.LastTransitionTime.Time)
+	if notReadyFor < c.issuerNotReadyThreshold {
+		return "", c.issuerNotReadyThreshold - notReadyFor
+	}
+	kind := ref.Kind
+	if kind == "" {
+		kind = cmapi.IssuerKind
+	}
+	return fmt.Sprintf("Referenced %s %q has not been ready since %s: %s", kind, ref.Name,
+		ready.LastTransitionTime.UTC().Format(time.RFC3339), ready.Message), 0
+}
+
 // policyEvaluator builds Certificate's Ready condition using the result of policy chain evaluation
 func policyEvaluator(chain policies.Chain, input policies.Input) cmapi.CertificateCondition {
 	reason, message, violationsFound := chain.Evaluate(input)
@@ -230,13 +446,24 @@ func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.Rate
 	// construct a new named logger to be reused throughout the controller
 	log := logf.FromContext(ctx.RootContext, ControllerName)
 
+	renewBefore := ctx.CertificateOptions.RenewBeforeExpiryDuration
+	if renewBefore == 0 {
+		renewBefore = cmapi.DefaultRenewBefore
+	}
 	ctrl, queue, mustSync := NewController(log,
 		ctx.CMClient,
+		ctx.Recorder,
 		ctx.KubeSharedInformerFactory,
 		ctx.SharedInformerFactory,
 		NewReadinessPolicyChain(ctx.Clock),
-		certificates.RenewalTimeWrapper(cmapi.DefaultRenewBefore),
+		certificates.RenewalTimeWrapper(renewBefore, ctx.CertificateOptions.RenewBeforeExpiryPercentage),
+		renewBefore,
+		ctx.CertificateOptions.RenewBeforeExpiryPercentage,
 		policyEvaluator,
+		ctx.Namespace,
+		ctx.CertificateOptions.KeepReadyWithoutIssuer,
+		ctx.CertificateOptions.IssuerNotReadyThreshold,
+		ctx.Clock,
 	)
 	c.controller = ctrl
 
@@ -26,6 +26,7 @@ import (
 	coretesting "k8s.io/client-go/testing"
 	fakeclock "k8s.io/utils/clock/testing"
 
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
@@ -61,8 +62,18 @@ func TestProcessItem(t *testing.T) {
 		Spec: cmapi.CertificateSpec{
 			SecretName: "test-secret",
 			DNSNames:   []string{"example.com"},
+			IssuerRef:  cmmeta.ObjectReference{Name: "testissuer"},
+			// shorter than the certificates issued in these tests, so that
+			// the default renewBefore does not need to be adjusted
+			RenewBefore: &metav1.Duration{Duration: time.Hour},
 		},
 	}
+	issuerNotFoundMessage := `Referenced Issuer "testissuer" does not exist`
+	issuerNotReadySince := metav1.NewTime(now.Add(-20 * time.Minute))
+	issuerNotReadyMessage := `Referenced Issuer "testissuer" has not been ready since ` + issuerNotReadySince.UTC().Format(time.RFC3339) + `: secret not found`
+	renewBeforeAdjustedMessage := "The issued certificate is valid for 2h0m0s, which is not longer than the requested renewBefore of 3h0m0s, so it will be renewed 40m0s before it expires instead"
+	shortCertRequestedMessage := "The issued certificate is valid for 168h0m0s, which is not longer than the requested renewBefore of 720h0m0s, so it will be renewed 56h0m0s before it expires instead"
+	shortCertDefaultMessage := "The issued certificate is valid for 168h0m0s, which is not longer than the default renewBefore of 720h0m0s, so it will be renewed 56h0m0s before it expires instead"
 	// base Secret to be used in tests
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -85,6 +96,29 @@ func TestProcessItem(t *testing.T) {
 		// Certificate's Ready condition to be applied with the update
 		condition cmapi.CertificateCondition
 
+		// the Ready condition returned by the policy evaluator, if it differs
+		// from the condition expected to be applied with the update
+		policyCondition *cmapi.CertificateCondition
+
+		// whether the Issuer referenced by the Certificate should be missing
+		// from the fake clientset
+		issuerShouldNotExist bool
+
+		// whether the controller keeps Certificates whose issuer does not
+		// exist Ready
+		keepReadyWithoutIssuer bool
+
+		// conditions of the Issuer referenced by the Certificate
+		issuerConditions []cmapi.IssuerCondition
+
+		// how long the Issuer must have not been Ready before the
+		// IssuerNotReady condition is set
+		issuerNotReadyThreshold time.Duration
+
+		// the default percentage of the certificate lifetime to renew
+		// before expiry
+		renewBeforePercentage int
+
 		// whether secret should be loaded into the fake clientset
 		// if notAfter, notBefore and renewalTime are set, an X509 cert will also be built and
 		// added as tls.crt value to the secret data
@@ -101,6 +135,13 @@ func TestProcessItem(t *testing.T) {
 		// renewalTime will be the updated Certificate's status.renewalTime
 		renewalTime *metav1.Time
 
+		// additional conditions expected on the updated Certificate, after
+		// its Ready condition
+		extraConditions []cmapi.CertificateCondition
+
+		// events that are expected to be emitted
+		expectedEvents []string
+
 		wantsErr bool
 	}{
 		"do nothing if an empty 'key' is used": {},
@@ -235,6 +276,368 @@ func TestProcessItem(t *testing.T) {
 			secretShouldExist: true,
 			certShouldUpdate:  false,
 		},
+		"set RenewBeforeAdjusted and emit a warning Event if the issued certificate is shorter than renewBefore": {
+			condition: cmapi.CertificateCondition{
+				Type:               cmapi.CertificateConditionReady,
+				Status:             cmmeta.ConditionTrue,
+				Reason:             ReadyReason,
+				Message:            "ready message",
+				LastTransitionTime: &metaNow,
+			},
+			cert:              gen.CertificateFrom(cert, gen.SetCertificateRenewBefore(time.Hour*3)),
+			certShouldUpdate:  true,
+			secretShouldExist: true,
+			notAfter:          func(m metav1.Time) *metav1.Time { return &m }(metav1.NewTime(now.Add(time.Hour * 2).Truncate(time.Second))),
+			notBefore:         func(m metav1.Time) *metav1.Time { return &m }(metav1.NewTime(now.Truncate(time.Second))),
+			renewalTime:       func(m metav1.Time) *metav1.Time { return &m }(metav1.NewTime(now.Add(time.Hour))),
+			extraConditions: []cmapi.CertificateCondition{{
+				Type:               cmapi.CertificateConditionRenewBeforeAdjusted,
+				Status:             cmmeta.ConditionTrue,
+				Reason:             RenewBeforeExceedsDurationReason,
+				Message:            renewBeforeAdjustedMessage,
+				LastTransitionTime: &metaNow,
+			}},
+			expectedEvents: []string{"Warning RenewBeforeExceedsDuration " + renewBeforeAdjustedMessage},
+		},
+		"clamp renewBefore and set RenewBeforeAdjusted if a 7 day certificate is issued with a 30 day renewBefore": {
+			condition: cmapi.CertificateCondition{
+				Type:               cmapi.CertificateConditionReady,
+				Status:             cmmeta.ConditionTrue,
+				Reason:             ReadyReason,
+				Message:            "ready message",
+				LastTransitionTime: &metaNow,
+			},
+			cert:              gen.CertificateFrom(cert, gen.SetCertificateRenewBefore(time.Hour*24*30)),
+			certShouldUpdate:  true,
+			secretShouldExist: true,
+			notAfter:          func(m metav1.Time) *metav1.Time { return &m }(metav1.NewTime(now.Add(time.Hour * 24 * 7).Truncate(time.Second))),
+			notBefore:         func(m metav1.Time) *metav1.Time { return &m }(metav1.NewTime(now.Truncate(time.Second))),
+			renewalTime:       func(m metav1.Time) *metav1.Time { return &m }(metav1.NewTime(now.Add(time.Hour * 24 * 7).Add(-time.Hour * 56).Truncate(time.Second))),
+			extraConditions: []cmapi.CertificateCondition{{
+				Type:               cmapi.CertificateConditionRenewBeforeAdjusted,
+				Status:             cmmeta.ConditionTrue,
+				Reason:             RenewBeforeExceedsDurationReason,
+				Message:            shortCertRequestedMessage,
+				LastTransitionTime: &metaNow,
+			}},
+			expectedEvents: []string{"Warning RenewBeforeExceedsDuration " + shortCertRequestedMessage},
+		},
+		"clamp renewBefore and set RenewBeforeAdjusted if a 7 day certificate is issued with the default renewBefore": {
+			condition: cmapi.CertificateCondition{
+				Type:               cmapi.CertificateConditionReady,
+				Status:             cmmeta.ConditionTrue,
+				Reason:             ReadyReason,
+				Message:            "ready message",
+				LastTransitionTime: &metaNow,
+			},
+			cert: gen.CertificateFrom(cert, func(crt *cmapi.Certificate) {
+				crt.Spec.RenewBefore = nil
+			}),
+			certShouldUpdate:  true,
+			secretShouldExist: true,
+			notAfter:          func(m metav1.Time) *metav1.Time { return &m }(metav1.NewTime(now.Add(time.Hour * 24 * 7).Truncate(time.Second))),
+			notBefore:         func(m metav1.Time) *metav1.Time { return &m }(metav1.NewTime(now.Truncate(time.Second))),
+			renewalTime:       func(m metav1.Time) *metav1.Time { return &m }(metav1.NewTime(now.Add(time.Hour * 24 * 7).Add(-time.Hour * 56).Truncate(time.Second))),
+			extraConditions: []cmapi.CertificateCondition{{
+				Type:               cmapi.CertificateConditionRenewBeforeAdjusted,
+				Status:             cmmeta.ConditionTrue,
+				Reason:             RenewBeforeExceedsDurationReason,
+				Message:            shortCertDefaultMessage,
+				LastTransitionTime: &metaNow,
+			}},
+			expectedEvents: []string{"Warning RenewBeforeExceedsDuration " + shortCertDefaultMessage},
+		},
+		"do not set RenewBeforeAdjusted if a 7 day certificate is issued with a default renewBefore percentage": {
+			condition: cmapi.CertificateCondition{
+				Type:               cmapi.CertificateConditionReady,
+				Status:             cmmeta.ConditionTrue,
+				Reason:             ReadyReason,
+				Message:            "ready message",
+				LastTransitionTime: &metaNow,
+			},
+			cert: gen.CertificateFrom(cert, func(crt *cmapi.Certificate) {
+				crt.Spec.RenewBefore = nil
+			}),
+			renewBeforePercentage: 25,
+			certShouldUpdate:      true,
+			secretShouldExist:     true,
+			notAfter:              func(m metav1.Time) *metav1.Time { return &m }(metav1.NewTime(now.Add(time.Hour * 24 * 7).Truncate(time.Second))),
+			notBefore:             func(m metav1.Time) *metav1.Time { return &m }(metav1.NewTime(now.Truncate(time.Second))),
+			renewalTime:           func(m metav1.Time) *metav1.Time { return &m }(metav1.NewTime(now.Add(time.Hour * 24 * 7).Add(-time.Hour * 42).Truncate(time.Second))),
+		},
+		"remove RenewBeforeAdjusted if the issued certificate is longer than renewBefore": {
+			condition: cmapi.CertificateCondition{
+				Type:               cmapi.CertificateConditionReady,
+				Status:             cmmeta.ConditionTrue,
+				Reason:             ReadyReason,
+				Message:            "ready message",
+				LastTransitionTime: &metaNow,
+			},
+			cert: gen.CertificateFrom(cert, gen.SetCertificateRenewBefore(time.Hour),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+					Type:    cmapi.CertificateConditionRenewBeforeAdjusted,
+					Status:  cmmeta.ConditionTrue,
+					Reason:  RenewBeforeExceedsDurationReason,
+					Message: renewBeforeAdjustedMessage,
+				})),
+			certShouldUpdate:  true,
+			secretShouldExist: true,
+			notAfter:          func(m metav1.Time) *metav1.Time { return &m }(metav1.NewTime(now.Add(time.Hour * 2).Truncate(time.Second))),
+			notBefore:         func(m metav1.Time) *metav1.Time { return &m }(metav1.NewTime(now.Truncate(time.Second))),
+			renewalTime:       func(m metav1.Time) *metav1.Time { return &m }(metav1.NewTime(now.Add(time.Hour))),
+		},
+		"emit a warning Event for a Certificate that was written using a deprecated API version and field": {
+			condition: cmapi.CertificateCondition{
+				Type:               cmapi.CertificateConditionReady,
+				Status:             cmmeta.ConditionFalse,
+				Reason:             "some reason",
+				Message:            "some message",
+				LastTransitionTime: &metaNow,
+			},
+			cert: gen.CertificateFrom(cert, func(crt *cmapi.Certificate) {
+				crt.ManagedFields = []metav1.ManagedFieldsEntry{
+					{
+						Manager:    "kubectl",
+						Operation:  metav1.ManagedFieldsOperationUpdate,
+						APIVersion: "cert-manager.io/v1alpha2",
+						FieldsType: "FieldsV1",
+						FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:keySize":{},"f:secretName":{}}}`)},
+					},
+				}
+			}),
+			certShouldUpdate: true,
+			expectedEvents: []string{
+				"Warning Deprecated Certificate was written using the deprecated API version cert-manager.io/v1alpha2, use cert-manager.io/v1 instead",
+				"Warning Deprecated Certificate field spec.keySize is deprecated, use spec.privateKey.size instead",
+			},
+		},
+		"set Ready to False and IssuerNotFound for a Certificate whose Issuer has been deleted": {
+			policyCondition: &cmapi.CertificateCondition{
+				Type:    cmapi.CertificateConditionReady,
+				Status:  cmmeta.ConditionTrue,
+				Reason:  ReadyReason,
+				Message: "ready message",
+			},
+			condition: cmapi.CertificateCondition{
+				Type:               cmapi.CertificateConditionReady,
+				Status:             cmmeta.ConditionFalse,
+				Reason:             IssuerNotFoundReason,
+				Message:            issuerNotFoundMessage,
+				LastTransitionTime: &metaNow,
+			},
+			cert: gen.CertificateFrom(cert, gen.SetCertificateStatusCondition(
+				cmapi.CertificateCondition{
+					Type:               cmapi.CertificateConditionReady,
+					Status:             cmmeta.ConditionTrue,
+					Reason:             ReadyReason,
+					Message:            "ready message",
+					LastTransitionTime: &metaNow,
+				})),
+			issuerShouldNotExist: true,
+			certShouldUpdate:     true,
+			secretShouldExist:    true,
+			extraConditions: []cmapi.CertificateCondition{{
+				Type:               cmapi.CertificateConditionIssuerNotFound,
+				Status:             cmmeta.ConditionTrue,
+				Reason:             IssuerNotFoundReason,
+				Message:            issuerNotFoundMessage,
+				LastTransitionTime: &metaNow,
+			}},
+		},
+		"keep Ready and set IssuerNotFound for a Certificate whose Issuer has been deleted if keepReadyWithoutIssuer is set": {
+			condition: cmapi.CertificateCondition{
+				Type:               cmapi.CertificateConditionReady,
+				Status:             cmmeta.ConditionTrue,
+				Reason:             ReadyReason,
+				Message:            "ready message",
+				LastTransitionTime: &metaNow,
+			},
+			cert: gen.CertificateFrom(cert, gen.SetCertificateStatusCondition(
+				cmapi.CertificateCondition{
+					Type:               cmapi.CertificateConditionReady,
+					Status:             cmmeta.ConditionTrue,
+					Reason:             ReadyReason,
+					Message:            "ready message",
+					LastTransitionTime: &metaNow,
+				})),
+			issuerShouldNotExist:   true,
+			keepReadyWithoutIssuer: true,
+			certShouldUpdate:       true,
+			secretShouldExist:      true,
+			extraConditions: []cmapi.CertificateCondition{{
+				Type:               cmapi.CertificateConditionIssuerNotFound,
+				Status:             cmmeta.ConditionTrue,
+				Reason:             IssuerNotFoundReason,
+				Message:            issuerNotFoundMessage,
+				LastTransitionTime: &metaNow,
+			}},
+		},
+		"set Ready and remove IssuerNotFound for a Certificate whose Issuer has been recreated": {
+			condition: cmapi.CertificateCondition{
+				Type:               cmapi.CertificateConditionReady,
+				Status:             cmmeta.ConditionTrue,
+				Reason:             ReadyReason,
+				Message:            "ready message",
+				LastTransitionTime: &metaNow,
+			},
+			cert: gen.CertificateFrom(cert,
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+					Type:    cmapi.CertificateConditionReady,
+					Status:  cmmeta.ConditionFalse,
+					Reason:  IssuerNotFoundReason,
+					Message: issuerNotFoundMessage,
+				}),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+					Type:    cmapi.CertificateConditionIssuerNotFound,
+					Status:  cmmeta.ConditionTrue,
+					Reason:  IssuerNotFoundReason,
+					Message: issuerNotFoundMessage,
+				})),
+			certShouldUpdate:  true,
+			secretShouldExist: true,
+		},
+		"do not set IssuerNotFound for a Certificate that references an external issuer": {
+			condition: cmapi.CertificateCondition{
+				Type:               cmapi.CertificateConditionReady,
+				Status:             cmmeta.ConditionTrue,
+				Reason:             ReadyReason,
+				Message:            "ready message",
+				LastTransitionTime: &metaNow,
+			},
+			cert: gen.CertificateFrom(cert,
+				gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "testissuer", Kind: "ExternalIssuer", Group: "external.example.com"}),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+					Type:               cmapi.CertificateConditionReady,
+					Status:             cmmeta.ConditionTrue,
+					Reason:             ReadyReason,
+					Message:            "ready message",
+					LastTransitionTime: &metaNow,
+				})),
+			issuerShouldNotExist: true,
+			secretShouldExist:    true,
+			certShouldUpdate:     false,
+		},
+		"keep Ready and set IssuerNotReady for a Certificate whose Issuer has not been ready for longer than the threshold": {
+			condition: cmapi.CertificateCondition{
+				Type:               cmapi.CertificateConditionReady,
+				Status:             cmmeta.ConditionTrue,
+				Reason:             ReadyReason,
+				Message:            "ready message",
+				LastTransitionTime: &metaNow,
+			},
+			cert: gen.CertificateFrom(cert, gen.SetCertificateStatusCondition(
+				cmapi.CertificateCondition{
+					Type:               cmapi.CertificateConditionReady,
+					Status:             cmmeta.ConditionTrue,
+					Reason:             ReadyReason,
+					Message:            "ready message",
+					LastTransitionTime: &metaNow,
+				})),
+			issuerConditions: []cmapi.IssuerCondition{{
+				Type:               cmapi.IssuerConditionReady,
+				Status:             cmmeta.ConditionFalse,
+				Reason:             "ErrGetKeyPair",
+				Message:            "secret not found",
+				LastTransitionTime: &issuerNotReadySince,
+			}},
+			issuerNotReadyThreshold: 10 * time.Minute,
+			certShouldUpdate:        true,
+			secretShouldExist:       true,
+			extraConditions: []cmapi.CertificateCondition{{
+				Type:               cmapi.CertificateConditionIssuerNotReady,
+				Status:             cmmeta.ConditionTrue,
+				Reason:             IssuerNotReadyReason,
+				Message:            issuerNotReadyMessage,
+				LastTransitionTime: &metaNow,
+			}},
+			expectedEvents: []string{"Warning IssuerNotReady " + issuerNotReadyMessage},
+		},
+		"do not set IssuerNotReady for a Certificate whose Issuer has not been ready for less than the threshold": {
+			condition: cmapi.CertificateCondition{
+				Type:               cmapi.CertificateConditionReady,
+				Status:             cmmeta.ConditionTrue,
+				Reason:             ReadyReason,
+				Message:            "ready message",
+				LastTransitionTime: &metaNow,
+			},
+			cert: gen.CertificateFrom(cert, gen.SetCertificateStatusCondition(
+				cmapi.CertificateCondition{
+					Type:               cmapi.CertificateConditionReady,
+					Status:             cmmeta.ConditionTrue,
+					Reason:             ReadyReason,
+					Message:            "ready message",
+					LastTransitionTime: &metaNow,
+				})),
+			issuerConditions: []cmapi.IssuerCondition{{
+				Type:               cmapi.IssuerConditionReady,
+				Status:             cmmeta.ConditionFalse,
+				Reason:             "ErrGetKeyPair",
+				Message:            "secret not found",
+				LastTransitionTime: &issuerNotReadySince,
+			}},
+			issuerNotReadyThreshold: time.Hour,
+			certShouldUpdate:        false,
+			secretShouldExist:       true,
+		},
+		"do not set IssuerNotReady if the threshold is zero": {
+			condition: cmapi.CertificateCondition{
+				Type:               cmapi.CertificateConditionReady,
+				Status:             cmmeta.ConditionTrue,
+				Reason:             ReadyReason,
+				Message:            "ready message",
+				LastTransitionTime: &metaNow,
+			},
+			cert: gen.CertificateFrom(cert, gen.SetCertificateStatusCondition(
+				cmapi.CertificateCondition{
+					Type:               cmapi.CertificateConditionReady,
+					Status:             cmmeta.ConditionTrue,
+					Reason:             ReadyReason,
+					Message:            "ready message",
+					LastTransitionTime: &metaNow,
+				})),
+			issuerConditions: []cmapi.IssuerCondition{{
+				Type:               cmapi.IssuerConditionReady,
+				Status:             cmmeta.ConditionFalse,
+				Reason:             "ErrGetKeyPair",
+				Message:            "secret not found",
+				LastTransitionTime: &issuerNotReadySince,
+			}},
+			certShouldUpdate:  false,
+			secretShouldExist: true,
+		},
+		"remove IssuerNotReady for a Certificate whose Issuer is ready again": {
+			condition: cmapi.CertificateCondition{
+				Type:               cmapi.CertificateConditionReady,
+				Status:             cmmeta.ConditionTrue,
+				Reason:             ReadyReason,
+				Message:            "ready message",
+				LastTransitionTime: &metaNow,
+			},
+			cert: gen.CertificateFrom(cert,
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+					Type:               cmapi.CertificateConditionReady,
+					Status:             cmmeta.ConditionTrue,
+					Reason:             ReadyReason,
+					Message:            "ready message",
+					LastTransitionTime: &metaNow,
+				}),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+					Type:    cmapi.CertificateConditionIssuerNotReady,
+					Status:  cmmeta.ConditionTrue,
+					Reason:  IssuerNotReadyReason,
+					Message: issuerNotReadyMessage,
+				})),
+			issuerConditions: []cmapi.IssuerCondition{{
+				Type:               cmapi.IssuerConditionReady,
+				Status:             cmmeta.ConditionTrue,
+				Reason:             "KeyPairVerified",
+				Message:            "Signing CA verified",
+				LastTransitionTime: &metaNow,
+			}},
+			issuerNotReadyThreshold: 10 * time.Minute,
+			certShouldUpdate:        true,
+			secretShouldExist:       true,
+		},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -242,12 +645,21 @@ func TestProcessItem(t *testing.T) {
 			builder := &testpkg.Builder{
 				T: t,
 				// Fix the clock to be able to set lastTransitionTime on Certificate's Ready condition.
-				Clock: fakeclock.NewFakeClock(now),
+				Clock:          fakeclock.NewFakeClock(now),
+				ExpectedEvents: test.expectedEvents,
 			}
 			if test.cert != nil {
 				// Ensures cert is loaded into the builder's fake clientset.
 				builder.CertManagerObjects = append(builder.CertManagerObjects, test.cert)
 			}
+			if !test.issuerShouldNotExist {
+				mods := []gen.IssuerModifier{gen.SetIssuerNamespace("testns")}
+				for _, cond := range test.issuerConditions {
+					mods = append(mods, gen.AddIssuerCondition(cond))
+				}
+				builder.CertManagerObjects = append(builder.CertManagerObjects,
+					gen.Issuer("testissuer", mods...))
+			}
 
 			if test.secretShouldExist {
 				mods := make([]gen.SecretModifier, 0)
@@ -273,8 +685,16 @@ func TestProcessItem(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			// Override controller's readyCondition func with a fake that returns test.condition.
-			w.controller.policyEvaluator = policyEvaluatorBuilder(test.condition)
+			// Override controller's readyCondition func with a fake that returns test.condition,
+			// or test.policyCondition if set.
+			policyCondition := test.condition
+			if test.policyCondition != nil {
+				policyCondition = *test.policyCondition
+			}
+			w.controller.policyEvaluator = policyEvaluatorBuilder(policyCondition)
+			w.controller.keepReadyWithoutIssuer = test.keepReadyWithoutIssuer
+			w.controller.issuerNotReadyThreshold = test.issuerNotReadyThreshold
+			w.controller.defaultRenewBeforePercentage = test.renewBeforePercentage
 
 			// Override controller's renewalTime func with a fake that returns test.renewalTime.
 			w.controller.renewalTimeCalculator = renewalTimeBuilder(test.renewalTime)
@@ -284,6 +704,14 @@ func TestProcessItem(t *testing.T) {
 			if test.certShouldUpdate {
 				c := gen.CertificateFrom(test.cert,
 					gen.SetCertificateStatusCondition(test.condition))
+				// the RenewBeforeAdjusted, IssuerNotFound and IssuerNotReady
+				// conditions are only expected if listed in test.extraConditions
+				apiutil.RemoveCertificateCondition(c, cmapi.CertificateConditionRenewBeforeAdjusted)
+				apiutil.RemoveCertificateCondition(c, cmapi.CertificateConditionIssuerNotFound)
+				apiutil.RemoveCertificateCondition(c, cmapi.CertificateConditionIssuerNotReady)
+				for _, cond := range test.extraConditions {
+					c = gen.CertificateFrom(c, gen.SetCertificateStatusCondition(cond))
+				}
 
 				// gen package functions don't accept pointers- we need to test setting these values to nil in some scenarios.
 				c.Status.NotAfter = test.notAfter
@@ -322,6 +750,9 @@ func TestProcessItem(t *testing.T) {
 			if err := builder.AllReactorsCalled(); err != nil {
 				builder.T.Error(err)
 			}
+			if err := builder.AllEventsCalled(); err != nil {
+				builder.T.Error(err)
+			}
 		})
 	}
 }
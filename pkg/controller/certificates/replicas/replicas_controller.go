@@ -0,0 +1,302 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replicas
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	cminformers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+const (
+	ControllerName = "certificates-replicas"
+
+	reasonReplicasReady    = "ReplicasReady"
+	reasonReplicasNotReady = "ReplicasNotReady"
+)
+
+var (
+	certificateGvk = cmapi.SchemeGroupVersion.WithKind("Certificate")
+)
+
+// controller expands Certificates that have `spec.replicas` set into one
+// replica Certificate per ordinal, each controlled by the template
+// Certificate, and deletes the replica Certificates of ordinals that are no
+// longer wanted. The replica Certificates are issued like any other
+// Certificate, and the Ready condition of the template reflects whether all
+// of its replicas are ready.
+type controller struct {
+	certificateLister cmlisters.CertificateLister
+	client            cmclient.Interface
+}
+
+func NewController(
+	log logr.Logger,
+	client cmclient.Interface,
+	cmFactory cminformers.SharedInformerFactory,
+) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
+	// create a queue used to queue up items to be processed
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
+
+	// obtain references to all the informers used by this controller
+	certificateInformer := cmFactory.Certmanager().V1().Certificates()
+
+	certificateInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: queue})
+	// When a replica Certificate changes, enqueue the template Certificate
+	// that controls it.
+	certificateInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: controllerpkg.HandleOwnedResourceNamespacedFunc(log, queue, certificateGvk, certificateGetter(certificateInformer.Lister())),
+	})
+
+	// build a list of InformerSynced functions that will be returned by the Register method.
+	// the controller will only begin processing items once all of these informers have synced.
+	mustSync := []cache.InformerSynced{
+		certificateInformer.Informer().HasSynced,
+	}
+
+	return &controller{
+		certificateLister: certificateInformer.Lister(),
+		client:            client,
+	}, queue, mustSync
+}
+
+func certificateGetter(lister cmlisters.CertificateLister) func(namespace, name string) (interface{}, error) {
+	return func(namespace, name string) (interface{}, error) {
+		return lister.Certificates(namespace).Get(name)
+	}
+}
+
+// ProcessItem creates, updates and deletes the replica Certificates of the
+// Certificate with the given key so that there is exactly one for each of
+// its ordinals, and updates the Ready condition of the Certificate.
+func (c *controller) ProcessItem(ctx context.Context, key string) error {
+	log := logf.FromContext(ctx).WithValues("key", key)
+
+	ctx = logf.NewContext(ctx, log)
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		log.Error(err, "invalid resource key passed to ProcessItem")
+		return nil
+	}
+
+	crt, err := c.certificateLister.Certificates(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		log.V(logf.DebugLevel).Info("certificate not found for key")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if crt.DeletionTimestamp != nil {
+		// replica Certificates are garbage collected along with the
+		// template through their owner references
+		return nil
+	}
+
+	log = logf.WithResource(log, crt)
+	ctx = logf.NewContext(ctx, log)
+
+	existing, err := c.certificateLister.Certificates(crt.Namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	owned := make(map[string]*cmapi.Certificate)
+	for _, other := range existing {
+		if metav1.IsControlledBy(other, crt) {
+			owned[other.Name] = other
+		}
+	}
+
+	// A Certificate that no longer has replicas set is issued itself, so
+	// only the replica Certificates it used to have are deleted.
+	var replicas int32
+	if crt.Spec.Replicas != nil {
+		replicas = *crt.Spec.Replicas
+	}
+
+	var ready int32
+	var conflicts []string
+	for ordinal := int32(0); ordinal < replicas; ordinal++ {
+		desired := replicaCertificate(crt, int(ordinal))
+
+		current, ok := owned[desired.Name]
+		delete(owned, desired.Name)
+		if !ok {
+			if _, err := c.certificateLister.Certificates(crt.Namespace).Get(desired.Name); err == nil {
+				// never take over a Certificate that was not created by
+				// this controller
+				conflicts = append(conflicts, desired.Name)
+				continue
+			} else if !apierrors.IsNotFound(err) {
+				return err
+			}
+
+			log.V(logf.InfoLevel).Info("creating replica certificate", "replica", desired.Name)
+			if _, err := c.client.CertmanagerV1().Certificates(crt.Namespace).Create(ctx, desired, metav1.CreateOptions{}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !apiequality.Semantic.DeepEqual(current.Spec, desired.Spec) || !apiequality.Semantic.DeepEqual(current.Labels, desired.Labels) {
+			log.V(logf.InfoLevel).Info("updating replica certificate to match template", "replica", desired.Name)
+			updated := current.DeepCopy()
+			updated.Labels = desired.Labels
+			updated.Spec = desired.Spec
+			if _, err := c.client.CertmanagerV1().Certificates(crt.Namespace).Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if apiutil.CertificateHasCondition(current, cmapi.CertificateCondition{
+			Type:   cmapi.CertificateConditionReady,
+			Status: cmmeta.ConditionTrue,
+		}) {
+			ready++
+		}
+	}
+
+	// any remaining replica Certificates have an ordinal that is no longer
+	// wanted
+	for _, extra := range owned {
+		log.V(logf.InfoLevel).Info("deleting replica certificate", "replica", extra.Name)
+		err := c.client.CertmanagerV1().Certificates(crt.Namespace).Delete(ctx, extra.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	if crt.Spec.Replicas == nil {
+		return nil
+	}
+
+	return c.updateReadyCondition(ctx, crt, ready, replicas, conflicts)
+}
+
+// updateReadyCondition sets the Ready condition of the template Certificate
+// to reflect how many of its replica Certificates are ready.
+func (c *controller) updateReadyCondition(ctx context.Context, crt *cmapi.Certificate, ready, replicas int32, conflicts []string) error {
+	status, reason := cmmeta.ConditionFalse, reasonReplicasNotReady
+	if ready == replicas {
+		status, reason = cmmeta.ConditionTrue, reasonReplicasReady
+	}
+	message := fmt.Sprintf("%d/%d replica Certificates are ready", ready, replicas)
+	if len(conflicts) > 0 {
+		message += fmt.Sprintf("; Certificates %s already exist and are not replicas of this Certificate", strings.Join(conflicts, ", "))
+	}
+
+	existing := apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionReady)
+	if existing != nil && existing.Status == status && existing.Reason == reason &&
+		existing.Message == message && existing.ObservedGeneration == crt.Generation {
+		return nil
+	}
+
+	crt = crt.DeepCopy()
+	apiutil.SetCertificateCondition(crt, crt.Generation, cmapi.CertificateConditionReady, status, reason, message)
+	_, err := c.client.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
+	return err
+}
+
+// replicaCertificate returns the replica Certificate of the given ordinal
+// for a template Certificate.
+func replicaCertificate(crt *cmapi.Certificate, ordinal int) *cmapi.Certificate {
+	suffix := "-" + strconv.Itoa(ordinal)
+
+	spec := *crt.Spec.DeepCopy()
+	spec.Replicas = nil
+	spec.SecretName = crt.Spec.SecretName + suffix
+	spec.CommonName = substituteOrdinal(spec.CommonName, ordinal)
+	for i := range spec.DNSNames {
+		spec.DNSNames[i] = substituteOrdinal(spec.DNSNames[i], ordinal)
+	}
+	for i := range spec.URIs {
+		spec.URIs[i] = substituteOrdinal(spec.URIs[i], ordinal)
+	}
+	for i := range spec.EmailAddresses {
+		spec.EmailAddresses[i] = substituteOrdinal(spec.EmailAddresses[i], ordinal)
+	}
+
+	var replicaLabels map[string]string
+	if len(crt.Labels) > 0 {
+		replicaLabels = make(map[string]string, len(crt.Labels))
+		for k, v := range crt.Labels {
+			replicaLabels[k] = v
+		}
+	}
+
+	return &cmapi.Certificate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            crt.Name + suffix,
+			Namespace:       crt.Namespace,
+			Labels:          replicaLabels,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(crt, certificateGvk)},
+		},
+		Spec: spec,
+	}
+}
+
+func substituteOrdinal(s string, ordinal int) string {
+	return strings.ReplaceAll(s, cmapi.CertificateReplicaOrdinalPlaceholder, strconv.Itoa(ordinal))
+}
+
+// controllerWrapper wraps the `controller` structure to make it implement
+// the controllerpkg.queueingController interface
+type controllerWrapper struct {
+	*controller
+}
+
+func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
+	// construct a new named logger to be reused throughout the controller
+	log := logf.FromContext(ctx.RootContext, ControllerName)
+
+	ctrl, queue, mustSync := NewController(log,
+		ctx.CMClient,
+		ctx.SharedInformerFactory,
+	)
+	c.controller = ctrl
+
+	return queue, mustSync, nil
+}
+
+func init() {
+	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		return controllerpkg.NewBuilder(ctx, ControllerName).
+			For(&controllerWrapper{}).
+			Complete()
+	})
+}
@@ -0,0 +1,279 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replicas
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coretesting "k8s.io/client-go/testing"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	policiestest "github.com/jetstack/cert-manager/pkg/controller/certificates/trigger/policies/test"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestProcessItem(t *testing.T) {
+	fixedNow := metav1.NewTime(time.Now())
+	fixedClock := fakeclock.NewFakeClock(fixedNow.Time)
+
+	baseCrt := gen.Certificate("test-cert",
+		gen.SetCertificateNamespace("testns"),
+		gen.SetCertificateUID("test-uid"),
+		gen.SetCertificateSecretName("test-secret"),
+		gen.SetCertificateCommonName("pod-$(ORDINAL)"),
+		gen.SetCertificateDNSNames("pod-$(ORDINAL).svc", "example.com"),
+		gen.SetCertificateGeneration(2),
+		gen.AddCertificateLabels(map[string]string{"app": "db"}),
+		gen.SetCertificateReplicas(2),
+	)
+	ownerRef := metav1.NewControllerRef(baseCrt, certificateGvk)
+	replica := func(ordinal string, mods ...gen.CertificateModifier) *cmapi.Certificate {
+		crt := gen.Certificate("test-cert-"+ordinal, append([]gen.CertificateModifier{
+			gen.SetCertificateNamespace("testns"),
+			gen.SetCertificateSecretName("test-secret-" + ordinal),
+			gen.SetCertificateCommonName("pod-" + ordinal),
+			gen.SetCertificateDNSNames("pod-"+ordinal+".svc", "example.com"),
+			gen.AddCertificateLabels(map[string]string{"app": "db"}),
+		}, mods...)...)
+		crt.OwnerReferences = []metav1.OwnerReference{*ownerRef}
+		return crt
+	}
+	readyReplica := func(ordinal string) *cmapi.Certificate {
+		return replica(ordinal, gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+			Type: cmapi.CertificateConditionReady, Status: cmmeta.ConditionTrue, Reason: "Ready",
+		}))
+	}
+	readyCondition := func(status cmmeta.ConditionStatus, reason, message string) cmapi.CertificateCondition {
+		return cmapi.CertificateCondition{
+			Type:               cmapi.CertificateConditionReady,
+			Status:             status,
+			Reason:             reason,
+			Message:            message,
+			LastTransitionTime: &fixedNow,
+			ObservedGeneration: 2,
+		}
+	}
+
+	tests := map[string]struct {
+		// certificate to be synced for the test.
+		certificate *cmapi.Certificate
+		// existing Certificates other than the one being synced.
+		existing []runtime.Object
+
+		expectedActions []testpkg.Action
+	}{
+		"create a replica Certificate with distinct SANs for each ordinal": {
+			certificate: baseCrt,
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewCreateAction(cmapi.SchemeGroupVersion.WithResource("certificates"), "testns",
+					replica("0"))),
+				testpkg.NewAction(coretesting.NewCreateAction(cmapi.SchemeGroupVersion.WithResource("certificates"), "testns",
+					replica("1"))),
+				testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmapi.SchemeGroupVersion.WithResource("certificates"), "status", "testns",
+					gen.CertificateFrom(baseCrt, gen.SetCertificateStatusCondition(
+						readyCondition(cmmeta.ConditionFalse, reasonReplicasNotReady, "0/2 replica Certificates are ready"),
+					)))),
+			},
+		},
+		"update replica Certificates that no longer match the template": {
+			certificate: baseCrt,
+			existing: []runtime.Object{
+				replica("0", gen.SetCertificateDNSNames("old.svc")),
+				readyReplica("1"),
+			},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateAction(cmapi.SchemeGroupVersion.WithResource("certificates"), "testns",
+					replica("0"))),
+				testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmapi.SchemeGroupVersion.WithResource("certificates"), "status", "testns",
+					gen.CertificateFrom(baseCrt, gen.SetCertificateStatusCondition(
+						readyCondition(cmmeta.ConditionFalse, reasonReplicasNotReady, "1/2 replica Certificates are ready"),
+					)))),
+			},
+		},
+		"delete replica Certificates of ordinals that are no longer wanted": {
+			certificate: gen.CertificateFrom(baseCrt, gen.SetCertificateReplicas(1)),
+			existing: []runtime.Object{
+				readyReplica("0"),
+				readyReplica("1"),
+			},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewDeleteAction(cmapi.SchemeGroupVersion.WithResource("certificates"), "testns", "test-cert-1")),
+				testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmapi.SchemeGroupVersion.WithResource("certificates"), "status", "testns",
+					gen.CertificateFrom(baseCrt, gen.SetCertificateReplicas(1), gen.SetCertificateStatusCondition(
+						readyCondition(cmmeta.ConditionTrue, reasonReplicasReady, "1/1 replica Certificates are ready"),
+					)))),
+			},
+		},
+		"do nothing if all replica Certificates are up to date and the Ready condition is set": {
+			certificate: gen.CertificateFrom(baseCrt, gen.SetCertificateStatusCondition(
+				readyCondition(cmmeta.ConditionTrue, reasonReplicasReady, "2/2 replica Certificates are ready"),
+			)),
+			existing: []runtime.Object{
+				readyReplica("0"),
+				readyReplica("1"),
+			},
+		},
+		"do not take over a Certificate that is not a replica": {
+			certificate: gen.CertificateFrom(baseCrt, gen.SetCertificateReplicas(1)),
+			existing: []runtime.Object{
+				gen.Certificate("test-cert-0", gen.SetCertificateNamespace("testns"), gen.SetCertificateSecretName("other")),
+			},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmapi.SchemeGroupVersion.WithResource("certificates"), "status", "testns",
+					gen.CertificateFrom(baseCrt, gen.SetCertificateReplicas(1), gen.SetCertificateStatusCondition(
+						readyCondition(cmmeta.ConditionFalse, reasonReplicasNotReady,
+							"0/1 replica Certificates are ready; Certificates test-cert-0 already exist and are not replicas of this Certificate"),
+					)))),
+			},
+		},
+		"delete the replica Certificates of a Certificate that no longer has replicas": {
+			certificate: gen.CertificateFrom(baseCrt, func(crt *cmapi.Certificate) { crt.Spec.Replicas = nil }),
+			existing:    []runtime.Object{readyReplica("0")},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewDeleteAction(cmapi.SchemeGroupVersion.WithResource("certificates"), "testns", "test-cert-0")),
+			},
+		},
+		"do nothing for a Certificate without replicas": {
+			certificate: gen.Certificate("test-cert",
+				gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateSecretName("test-secret"),
+				gen.SetCertificateDNSNames("example.com"),
+			),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Create and initialise a new unit test builder
+			builder := &testpkg.Builder{
+				T:                  t,
+				Clock:              fixedClock,
+				CertManagerObjects: append([]runtime.Object{test.certificate}, test.existing...),
+				ExpectedActions:    test.expectedActions,
+			}
+			builder.Init()
+
+			// Register informers used by the controller using the registration wrapper
+			w := &controllerWrapper{}
+			_, _, err := w.Register(builder.Context)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Start the informers and begin processing updates
+			builder.Start()
+			defer builder.Stop()
+
+			key, err := controllerpkg.KeyFunc(test.certificate)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := w.controller.ProcessItem(context.Background(), key); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if err := builder.AllEventsCalled(); err != nil {
+				builder.T.Error(err)
+			}
+			if err := builder.AllActionsExecuted(); err != nil {
+				builder.T.Error(err)
+			}
+		})
+	}
+}
+
+// TestTemplateChangesRenewReplicas ensures that changing the template
+// Certificate causes each replica Certificate to no longer match the request
+// for its current certificate, which triggers a re-issuance of every replica.
+func TestTemplateChangesRenewReplicas(t *testing.T) {
+	crt := gen.Certificate("test-cert",
+		gen.SetCertificateNamespace("testns"),
+		gen.SetCertificateUID("test-uid"),
+		gen.SetCertificateSecretName("test-secret"),
+		gen.SetCertificateCommonName("pod-$(ORDINAL)"),
+		gen.SetCertificateDNSNames("pod-$(ORDINAL).svc"),
+		gen.SetCertificateReplicas(3),
+	)
+	replicas := syncReplicas(t, crt)
+	if len(replicas) != 3 {
+		t.Fatalf("expected 3 replica certificates but got %d", len(replicas))
+	}
+
+	seen := make(map[string]bool)
+	for _, replica := range replicas {
+		if len(replica.Spec.DNSNames) != 1 || seen[replica.Spec.DNSNames[0]] {
+			t.Errorf("expected replica %q to have a distinct dnsName but got %v", replica.Name, replica.Spec.DNSNames)
+		}
+		seen[replica.Spec.DNSNames[0]] = true
+		policiestest.AssertReissue(t, replica, replica, false)
+	}
+
+	changed := gen.CertificateFrom(crt, gen.SetCertificateDNSNames("pod-$(ORDINAL).svc", "pod-$(ORDINAL).example.com"))
+	updated := syncReplicas(t, changed, replicas...)
+	for i, replica := range replicas {
+		policiestest.AssertReissue(t, replica, updated[i], true)
+	}
+}
+
+// syncReplicas runs the controller for the given template Certificate with
+// the given replica Certificates existing, and returns the replica
+// Certificates as stored afterwards, in order of ordinal.
+func syncReplicas(t *testing.T, crt *cmapi.Certificate, existing ...*cmapi.Certificate) []*cmapi.Certificate {
+	cmObjects := []runtime.Object{crt}
+	for _, replica := range existing {
+		cmObjects = append(cmObjects, replica)
+	}
+
+	builder := &testpkg.Builder{
+		T:                  t,
+		CertManagerObjects: cmObjects,
+	}
+	builder.Init()
+
+	w := &controllerWrapper{}
+	if _, _, err := w.Register(builder.Context); err != nil {
+		t.Fatal(err)
+	}
+	builder.Start()
+	defer builder.Stop()
+
+	key, err := controllerpkg.KeyFunc(crt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.controller.ProcessItem(context.Background(), key); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var replicas []*cmapi.Certificate
+	for ordinal := 0; ordinal < int(*crt.Spec.Replicas); ordinal++ {
+		name := replicaCertificate(crt, ordinal).Name
+		replica, err := builder.CMClient.CertmanagerV1().Certificates(crt.Namespace).Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		replicas = append(replicas, replica)
+	}
+	return replicas
+}
@@ -23,6 +23,7 @@ import (
 	"encoding/pem"
 	"fmt"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -30,6 +31,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
 	corelisters "k8s.io/client-go/listers/core/v1"
@@ -38,6 +40,7 @@ import (
 	"k8s.io/client-go/util/workqueue"
 
 	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
@@ -45,15 +48,22 @@ import (
 	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	"github.com/jetstack/cert-manager/pkg/issuer"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
 	"github.com/jetstack/cert-manager/pkg/util/predicate"
 )
 
 const (
-	ControllerName      = "certificates-request-manager"
-	reasonRequestFailed = "RequestFailed"
-	reasonRequested     = "Requested"
+	ControllerName       = "certificates-request-manager"
+	reasonRequestFailed  = "RequestFailed"
+	reasonRequested      = "Requested"
+	reasonInFlight       = "RequestsInFlight"
+	reasonIssuerNotReady = "IssuerNotReady"
+
+	// the initial delay before checking again whether the issuer of a
+	// Certificate has become ready, which doubles each time it is not.
+	issuerReadinessBackoffBase = 5 * time.Second
 )
 
 var (
@@ -66,6 +76,30 @@ type controller struct {
 	secretLister             corelisters.SecretLister
 	client                   cmclient.Interface
 	recorder                 record.EventRecorder
+
+	// maxInFlightRequests is the maximum number of CertificateRequests owned
+	// by a Certificate that may be pending at once. If zero, there is no
+	// limit.
+	maxInFlightRequests int
+
+	// inFlightBlocked holds the keys of Certificates that are waiting for
+	// in-flight CertificateRequests to complete, so that an event is only
+	// emitted when a Certificate first becomes blocked rather than on every
+	// sync.
+	inFlightBlocked     sets.String
+	inFlightBlockedLock sync.Mutex
+
+	// issuerHelper is used to look up the issuer referenced by a Certificate
+	// to check whether it is ready.
+	issuerHelper issuer.Helper
+
+	// issuerReadinessBackoff is used to compute how long to wait before
+	// checking again whether the issuer of a Certificate is ready. If nil,
+	// CertificateRequests are created regardless of whether the issuer is
+	// ready.
+	issuerReadinessBackoff workqueue.RateLimiter
+
+	queue workqueue.RateLimitingInterface
 }
 
 func NewController(
@@ -74,6 +108,9 @@ func NewController(
 	factory informers.SharedInformerFactory,
 	cmFactory cminformers.SharedInformerFactory,
 	recorder record.EventRecorder,
+	maxInFlightRequests int,
+	namespace string,
+	issuerReadinessBackoffMax time.Duration,
 ) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
 	// create a queue used to queue up items to be processed
 	queue := workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
@@ -82,6 +119,7 @@ func NewController(
 	certificateInformer := cmFactory.Certmanager().V1().Certificates()
 	certificateRequestInformer := cmFactory.Certmanager().V1().CertificateRequests()
 	secretsInformer := factory.Core().V1().Secrets()
+	issuerInformer := cmFactory.Certmanager().V1().Issuers()
 
 	certificateInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: queue})
 	certificateRequestInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
@@ -97,12 +135,36 @@ func NewController(
 		),
 	})
 
+	// When an Issuer changes, such as when it becomes ready, enqueue any
+	// Certificate resources that reference it.
+	issuerInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: certificates.EnqueueCertificatesForGenericIssuer(log, queue, certificateInformer.Lister()),
+	})
+
 	// build a list of InformerSynced functions that will be returned by the Register method.
 	// the controller will only begin processing items once all of these informers have synced.
 	mustSync := []cache.InformerSynced{
 		secretsInformer.Informer().HasSynced,
 		certificateRequestInformer.Informer().HasSynced,
 		certificateInformer.Informer().HasSynced,
+		issuerInformer.Informer().HasSynced,
+	}
+
+	// if we are running in non-namespaced mode (i.e. --namespace=""), we also
+	// watch clusterissuers.
+	var clusterIssuerLister cmlisters.ClusterIssuerLister
+	if namespace == "" {
+		clusterIssuerInformer := cmFactory.Certmanager().V1().ClusterIssuers()
+		clusterIssuerInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+			WorkFunc: certificates.EnqueueCertificatesForGenericIssuer(log, queue, certificateInformer.Lister()),
+		})
+		clusterIssuerLister = clusterIssuerInformer.Lister()
+		mustSync = append(mustSync, clusterIssuerInformer.Informer().HasSynced)
+	}
+
+	var issuerReadinessBackoff workqueue.RateLimiter
+	if issuerReadinessBackoffMax > 0 {
+		issuerReadinessBackoff = workqueue.NewItemExponentialFailureRateLimiter(issuerReadinessBackoffBase, issuerReadinessBackoffMax)
 	}
 
 	return &controller{
@@ -111,6 +173,11 @@ func NewController(
 		secretLister:             secretsInformer.Lister(),
 		client:                   client,
 		recorder:                 recorder,
+		maxInFlightRequests:      maxInFlightRequests,
+		inFlightBlocked:          sets.NewString(),
+		issuerHelper:             issuer.NewHelper(issuerInformer.Lister(), clusterIssuerLister),
+		issuerReadinessBackoff:   issuerReadinessBackoff,
+		queue:                    queue,
 	}, queue, mustSync
 }
 
@@ -127,6 +194,7 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 	crt, err := c.certificateLister.Certificates(namespace).Get(name)
 	if apierrors.IsNotFound(err) {
 		log.Error(err, "certificate not found for key")
+		c.setInFlightBlocked(key, false)
 		return nil
 	}
 	if err != nil {
@@ -137,6 +205,7 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		Type:   cmapi.CertificateConditionIssuing,
 		Status: cmmeta.ConditionTrue,
 	}) {
+		c.setInFlightBlocked(key, false)
 		return nil
 	}
 
@@ -175,6 +244,8 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		return err
 	}
 
+	allRequests := requests
+
 	currentCertificateRevision := 0
 	if crt.Status.Revision != nil {
 		currentCertificateRevision = *crt.Status.Revision
@@ -205,9 +276,150 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 		return nil
 	}
 
+	// Refuse to create another CertificateRequest whilst too many earlier
+	// requests are still pending, so that a loop creating requests cannot
+	// amplify load on the issuer. The Certificate is re-queued once one of
+	// its requests changes.
+	var inFlight []*cmapi.CertificateRequest
+	if c.maxInFlightRequests > 0 {
+		inFlight = inFlightRequests(allRequests, nextRevision)
+	}
+	blocked := c.maxInFlightRequests > 0 && len(inFlight) >= c.maxInFlightRequests
+	if c.setInFlightBlocked(key, blocked) && blocked {
+		c.recorder.Eventf(crt, corev1.EventTypeNormal, reasonInFlight, "Waiting for %d pending CertificateRequest(s) to complete before creating a new one", len(inFlight))
+	}
+	if blocked {
+		log.V(logf.InfoLevel).Info("Not creating CertificateRequest as the maximum number of in-flight CertificateRequests has been reached", "limit", c.maxInFlightRequests)
+		return nil
+	}
+
+	// Wait for the issuer to become ready rather than creating a
+	// CertificateRequest that would immediately fail. The Certificate is
+	// re-queued once the issuer changes, or after a backoff which doubles
+	// each time the issuer is found not to be ready.
+	if notReady := c.issuerNotReady(crt); notReady != "" {
+		delay := c.issuerReadinessBackoff.When(key)
+		log.V(logf.InfoLevel).Info("Not creating CertificateRequest as the issuer is not ready", "retry_delay", delay)
+		c.queue.AddAfter(key, delay)
+		return c.setIssuerNotReadyCondition(ctx, crt, notReady)
+	}
+	if c.issuerReadinessBackoff != nil {
+		c.issuerReadinessBackoff.Forget(key)
+	}
+
+	crt, err = c.removeIssuerNotReadyCondition(ctx, crt)
+	if err != nil {
+		return err
+	}
+
 	return c.createNewCertificateRequest(ctx, crt, pk, nextRevision, nextPrivateKeySecret.Name)
 }
 
+// issuerNotReady returns a message describing the cert-manager issuer
+// referenced by the given Certificate if it is not Ready, or an empty string
+// otherwise. Issuers that do not exist or have no Ready condition yet are not
+// considered to be not Ready, so that the CertificateRequest reports the
+// problem instead. It always returns an empty string if the controller is not
+// configured to wait for issuers to be ready.
+func (c *controller) issuerNotReady(crt *cmapi.Certificate) string {
+	if c.issuerReadinessBackoff == nil {
+		return ""
+	}
+	ref := crt.Spec.IssuerRef
+	if !(ref.Group == "" || ref.Group == certmanager.GroupName) {
+		return ""
+	}
+
+	issuerObj, err := c.issuerHelper.GetGenericIssuer(ref, crt.Namespace)
+	if err != nil {
+		return ""
+	}
+	for _, cond := range issuerObj.GetStatus().Conditions {
+		if cond.Type != cmapi.IssuerConditionReady || cond.Status == cmmeta.ConditionTrue {
+			continue
+		}
+		kind := ref.Kind
+		if kind == "" {
+			kind = cmapi.IssuerKind
+		}
+		return fmt.Sprintf("Waiting for %s %q to become ready: %s", kind, ref.Name, cond.Message)
+	}
+	return ""
+}
+
+// setIssuerNotReadyCondition sets the IssuanceDeferred condition on the given
+// Certificate to record that no CertificateRequest will be created until its
+// issuer is ready.
+func (c *controller) setIssuerNotReadyCondition(ctx context.Context, crt *cmapi.Certificate, message string) error {
+	existing := apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionIssuanceDeferred)
+	if existing != nil && existing.Status == cmmeta.ConditionTrue && existing.Message == message {
+		return nil
+	}
+
+	crt = crt.DeepCopy()
+	apiutil.SetCertificateCondition(crt, crt.Generation, cmapi.CertificateConditionIssuanceDeferred, cmmeta.ConditionTrue, reasonIssuerNotReady, message)
+	_, err := c.client.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	c.recorder.Event(crt, corev1.EventTypeNormal, reasonIssuerNotReady, message)
+
+	return nil
+}
+
+// removeIssuerNotReadyCondition removes the IssuanceDeferred condition from
+// the given Certificate if it was set because its issuer was not ready, and
+// returns the updated Certificate.
+func (c *controller) removeIssuerNotReadyCondition(ctx context.Context, crt *cmapi.Certificate) (*cmapi.Certificate, error) {
+	existing := apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionIssuanceDeferred)
+	if existing == nil || existing.Reason != reasonIssuerNotReady {
+		return crt, nil
+	}
+
+	crt = crt.DeepCopy()
+	apiutil.RemoveCertificateCondition(crt, cmapi.CertificateConditionIssuanceDeferred)
+	return c.client.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
+}
+
+// setInFlightBlocked records whether the Certificate with the given key is
+// waiting for in-flight CertificateRequests to complete, returning true if
+// this differs from what was previously recorded.
+func (c *controller) setInFlightBlocked(key string, blocked bool) bool {
+	c.inFlightBlockedLock.Lock()
+	defer c.inFlightBlockedLock.Unlock()
+	if c.inFlightBlocked.Has(key) == blocked {
+		return false
+	}
+	if blocked {
+		c.inFlightBlocked.Insert(key)
+	} else {
+		c.inFlightBlocked.Delete(key)
+	}
+	return true
+}
+
+// inFlightRequests returns the given CertificateRequests that have not yet
+// reached a final state, i.e. that have not been issued, failed, been denied
+// or been marked as invalid. Requests for the given revision are ignored, as
+// any that exist when a new request is created have already been deleted.
+func inFlightRequests(reqs []*cmapi.CertificateRequest, revision int) []*cmapi.CertificateRequest {
+	var inFlight []*cmapi.CertificateRequest
+	for _, req := range reqs {
+		if reqRevision, err := strconv.Atoi(req.Annotations[cmapi.CertificateRequestRevisionAnnotationKey]); err == nil && reqRevision == revision {
+			continue
+		}
+		switch apiutil.CertificateRequestReadyReason(req) {
+		case cmapi.CertificateRequestReasonIssued, cmapi.CertificateRequestReasonFailed, cmapi.CertificateRequestReasonDenied:
+			continue
+		}
+		if apiutil.CertificateRequestHasInvalidRequest(req) {
+			continue
+		}
+		inFlight = append(inFlight, req)
+	}
+	return inFlight
+}
+
 func (c *controller) deleteRequestsWithoutRevision(ctx context.Context, reqs ...*cmapi.CertificateRequest) ([]*cmapi.CertificateRequest, error) {
 	log := logf.FromContext(ctx)
 	var remaining []*cmapi.CertificateRequest
@@ -320,6 +532,9 @@ func (c *controller) createNewCertificateRequest(ctx context.Context, crt *cmapi
 	annotations[cmapi.CertificateRequestRevisionAnnotationKey] = strconv.Itoa(nextRevision)
 	annotations[cmapi.CertificateRequestPrivateKeyAnnotationKey] = nextPrivateKeySecretName
 	annotations[cmapi.CertificateNameKey] = crt.Name
+	if cond := apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionIssuing); cond != nil && cond.Reason != "" {
+		annotations[cmapi.CertificateRequestIssuingReasonAnnotationKey] = cond.Reason
+	}
 
 	cr := &cmapi.CertificateRequest{
 		ObjectMeta: metav1.ObjectMeta{
@@ -378,6 +593,9 @@ func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.Rate
 		ctx.KubeSharedInformerFactory,
 		ctx.SharedInformerFactory,
 		ctx.Recorder,
+		ctx.CertificateOptions.MaxInFlightRequests,
+		ctx.Namespace,
+		ctx.CertificateOptions.IssuerReadinessBackoffMax,
 	)
 	c.controller = ctrl
 
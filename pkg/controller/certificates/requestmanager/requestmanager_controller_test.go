@@ -21,12 +21,14 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/kr/pretty"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	coretesting "k8s.io/client-go/testing"
+	fakeclock "k8s.io/utils/clock/testing"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
@@ -76,6 +78,25 @@ func TestProcessItem(t *testing.T) {
 		},
 		Spec: cmapi.CertificateSpec{CommonName: "test-bundle-2"}},
 	)
+	fixedNow := metav1.NewTime(time.Now())
+	fixedClock := fakeclock.NewFakeClock(fixedNow.Time)
+
+	issuerRef := cmmeta.ObjectReference{Name: "testissuer", Kind: "Issuer"}
+	notReadyIssuer := gen.Issuer("testissuer", gen.SetIssuerNamespace("testns"),
+		gen.AddIssuerCondition(cmapi.IssuerCondition{
+			Type:    cmapi.IssuerConditionReady,
+			Status:  cmmeta.ConditionFalse,
+			Message: "Vault is unreachable",
+		}),
+	)
+	readyIssuer := gen.Issuer("testissuer", gen.SetIssuerNamespace("testns"),
+		gen.AddIssuerCondition(cmapi.IssuerCondition{
+			Type:   cmapi.IssuerConditionReady,
+			Status: cmmeta.ConditionTrue,
+		}),
+	)
+	issuerNotReadyMessage := `Waiting for Issuer "testissuer" to become ready: Vault is unreachable`
+
 	tests := map[string]struct {
 		// key that should be passed to ProcessItem.
 		// if not set, the 'namespace/name' of the 'Certificate' field will be used.
@@ -91,6 +112,19 @@ func TestProcessItem(t *testing.T) {
 		// Request, if set, will exist in the apiserver before the test is run.
 		requests []runtime.Object
 
+		// maxInFlightRequests configured on the controller.
+		maxInFlightRequests int
+
+		// inFlightBlocked, if true, records that the Certificate was
+		// already waiting for in-flight requests before the test is run.
+		inFlightBlocked bool
+
+		// issuer, if set, will exist in the apiserver before the test is run.
+		issuer *cmapi.Issuer
+
+		// issuerReadinessBackoffMax configured on the controller.
+		issuerReadinessBackoffMax time.Duration
+
 		expectedActions []testpkg.Action
 
 		expectedEvents []string
@@ -169,6 +203,78 @@ func TestProcessItem(t *testing.T) {
 					)), relaxedCertificateRequestMatcher),
 			},
 		},
+		"annotate the created CertificateRequest with the reason for a new issuance": {
+			secrets: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: bundle1.certificate.Namespace, Name: "exists"},
+					Data:       map[string][]byte{corev1.TLSPrivateKeyKey: bundle1.privateKeyBytes},
+				},
+			},
+			certificate: gen.CertificateFrom(bundle1.certificate,
+				gen.SetCertificateNextPrivateKeySecretName("exists"),
+				// issuing because the Secret does not exist yet
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{Type: cmapi.CertificateConditionIssuing, Status: cmmeta.ConditionTrue, Reason: "DoesNotExist"}),
+			),
+			expectedEvents: []string{`Normal Requested Created new CertificateRequest resource "test-notrandom"`},
+			expectedActions: []testpkg.Action{
+				testpkg.NewCustomMatch(coretesting.NewCreateAction(cmapi.SchemeGroupVersion.WithResource("certificaterequests"), "testns",
+					gen.CertificateRequestFrom(bundle1.certificateRequest,
+						gen.SetCertificateRequestAnnotations(map[string]string{
+							cmapi.CertificateRequestPrivateKeyAnnotationKey:    "exists",
+							cmapi.CertificateRequestRevisionAnnotationKey:      "1",
+							cmapi.CertificateRequestIssuingReasonAnnotationKey: "DoesNotExist",
+						}),
+					)), relaxedCertificateRequestMatcher),
+			},
+		},
+		"annotate the created CertificateRequest with the reason for a renewal": {
+			secrets: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: bundle1.certificate.Namespace, Name: "exists"},
+					Data:       map[string][]byte{corev1.TLSPrivateKeyKey: bundle1.privateKeyBytes},
+				},
+			},
+			certificate: gen.CertificateFrom(bundle1.certificate,
+				gen.SetCertificateNextPrivateKeySecretName("exists"),
+				// issuing because the renewal time has been reached
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{Type: cmapi.CertificateConditionIssuing, Status: cmmeta.ConditionTrue, Reason: "Renewing"}),
+			),
+			expectedEvents: []string{`Normal Requested Created new CertificateRequest resource "test-notrandom"`},
+			expectedActions: []testpkg.Action{
+				testpkg.NewCustomMatch(coretesting.NewCreateAction(cmapi.SchemeGroupVersion.WithResource("certificaterequests"), "testns",
+					gen.CertificateRequestFrom(bundle1.certificateRequest,
+						gen.SetCertificateRequestAnnotations(map[string]string{
+							cmapi.CertificateRequestPrivateKeyAnnotationKey:    "exists",
+							cmapi.CertificateRequestRevisionAnnotationKey:      "1",
+							cmapi.CertificateRequestIssuingReasonAnnotationKey: "Renewing",
+						}),
+					)), relaxedCertificateRequestMatcher),
+			},
+		},
+		"annotate the created CertificateRequest with the reason for a change to the spec": {
+			secrets: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: bundle1.certificate.Namespace, Name: "exists"},
+					Data:       map[string][]byte{corev1.TLSPrivateKeyKey: bundle1.privateKeyBytes},
+				},
+			},
+			certificate: gen.CertificateFrom(bundle1.certificate,
+				gen.SetCertificateNextPrivateKeySecretName("exists"),
+				// issuing because the spec no longer matches the last request
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{Type: cmapi.CertificateConditionIssuing, Status: cmmeta.ConditionTrue, Reason: "RequestChanged"}),
+			),
+			expectedEvents: []string{`Normal Requested Created new CertificateRequest resource "test-notrandom"`},
+			expectedActions: []testpkg.Action{
+				testpkg.NewCustomMatch(coretesting.NewCreateAction(cmapi.SchemeGroupVersion.WithResource("certificaterequests"), "testns",
+					gen.CertificateRequestFrom(bundle1.certificateRequest,
+						gen.SetCertificateRequestAnnotations(map[string]string{
+							cmapi.CertificateRequestPrivateKeyAnnotationKey:    "exists",
+							cmapi.CertificateRequestRevisionAnnotationKey:      "1",
+							cmapi.CertificateRequestIssuingReasonAnnotationKey: "RequestChanged",
+						}),
+					)), relaxedCertificateRequestMatcher),
+			},
+		},
 		"delete the owned CertificateRequest and create a new one if existing one does not have the annotation": {
 			secrets: []runtime.Object{
 				&corev1.Secret{
@@ -320,6 +426,228 @@ func TestProcessItem(t *testing.T) {
 					)), relaxedCertificateRequestMatcher),
 			},
 		},
+		"should not create a CertificateRequest and set IssuanceDeferred if the issuer is not ready": {
+			secrets: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "exists"},
+					Data:       map[string][]byte{corev1.TLSPrivateKeyKey: bundle1.privateKeyBytes},
+				},
+			},
+			certificate: gen.CertificateFrom(bundle1.certificate,
+				gen.SetCertificateIssuer(issuerRef),
+				gen.SetCertificateNextPrivateKeySecretName("exists"),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{Type: cmapi.CertificateConditionIssuing, Status: cmmeta.ConditionTrue}),
+			),
+			issuer:                    notReadyIssuer,
+			issuerReadinessBackoffMax: time.Minute,
+			expectedEvents:            []string{"Normal IssuerNotReady " + issuerNotReadyMessage},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmapi.SchemeGroupVersion.WithResource("certificates"), "status", "testns",
+					gen.CertificateFrom(bundle1.certificate,
+						gen.SetCertificateIssuer(issuerRef),
+						gen.SetCertificateNextPrivateKeySecretName("exists"),
+						gen.SetCertificateStatusCondition(cmapi.CertificateCondition{Type: cmapi.CertificateConditionIssuing, Status: cmmeta.ConditionTrue}),
+						gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+							Type:               cmapi.CertificateConditionIssuanceDeferred,
+							Status:             cmmeta.ConditionTrue,
+							Reason:             "IssuerNotReady",
+							Message:            issuerNotReadyMessage,
+							LastTransitionTime: &fixedNow,
+						}),
+					),
+				)),
+			},
+		},
+		"should do nothing if IssuanceDeferred is already set for the issuer that is not ready": {
+			secrets: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "exists"},
+					Data:       map[string][]byte{corev1.TLSPrivateKeyKey: bundle1.privateKeyBytes},
+				},
+			},
+			certificate: gen.CertificateFrom(bundle1.certificate,
+				gen.SetCertificateIssuer(issuerRef),
+				gen.SetCertificateNextPrivateKeySecretName("exists"),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{Type: cmapi.CertificateConditionIssuing, Status: cmmeta.ConditionTrue}),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+					Type:    cmapi.CertificateConditionIssuanceDeferred,
+					Status:  cmmeta.ConditionTrue,
+					Reason:  "IssuerNotReady",
+					Message: issuerNotReadyMessage,
+				}),
+			),
+			issuer:                    notReadyIssuer,
+			issuerReadinessBackoffMax: time.Minute,
+		},
+		"should remove IssuanceDeferred and create a CertificateRequest once the issuer is ready": {
+			secrets: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "exists"},
+					Data:       map[string][]byte{corev1.TLSPrivateKeyKey: bundle1.privateKeyBytes},
+				},
+			},
+			certificate: gen.CertificateFrom(bundle1.certificate,
+				gen.SetCertificateIssuer(issuerRef),
+				gen.SetCertificateNextPrivateKeySecretName("exists"),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{Type: cmapi.CertificateConditionIssuing, Status: cmmeta.ConditionTrue}),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+					Type:    cmapi.CertificateConditionIssuanceDeferred,
+					Status:  cmmeta.ConditionTrue,
+					Reason:  "IssuerNotReady",
+					Message: issuerNotReadyMessage,
+				}),
+			),
+			issuer:                    readyIssuer,
+			issuerReadinessBackoffMax: time.Minute,
+			expectedEvents:            []string{`Normal Requested Created new CertificateRequest resource "test-notrandom"`},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateSubresourceAction(cmapi.SchemeGroupVersion.WithResource("certificates"), "status", "testns",
+					gen.CertificateFrom(bundle1.certificate,
+						gen.SetCertificateIssuer(issuerRef),
+						gen.SetCertificateNextPrivateKeySecretName("exists"),
+						gen.SetCertificateStatusCondition(cmapi.CertificateCondition{Type: cmapi.CertificateConditionIssuing, Status: cmmeta.ConditionTrue}),
+					),
+				)),
+				testpkg.NewCustomMatch(coretesting.NewCreateAction(cmapi.SchemeGroupVersion.WithResource("certificaterequests"), "testns",
+					gen.CertificateRequestFrom(bundle1.certificateRequest,
+						gen.SetCertificateRequestIssuer(issuerRef),
+						gen.SetCertificateRequestAnnotations(map[string]string{
+							cmapi.CertificateRequestPrivateKeyAnnotationKey: "exists",
+							cmapi.CertificateRequestRevisionAnnotationKey:   "1",
+						}),
+					)), relaxedCertificateRequestMatcher),
+			},
+		},
+		"should create a CertificateRequest if the issuer is not ready but readiness is not checked": {
+			secrets: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "exists"},
+					Data:       map[string][]byte{corev1.TLSPrivateKeyKey: bundle1.privateKeyBytes},
+				},
+			},
+			certificate: gen.CertificateFrom(bundle1.certificate,
+				gen.SetCertificateIssuer(issuerRef),
+				gen.SetCertificateNextPrivateKeySecretName("exists"),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{Type: cmapi.CertificateConditionIssuing, Status: cmmeta.ConditionTrue}),
+			),
+			issuer:         notReadyIssuer,
+			expectedEvents: []string{`Normal Requested Created new CertificateRequest resource "test-notrandom"`},
+			expectedActions: []testpkg.Action{
+				testpkg.NewCustomMatch(coretesting.NewCreateAction(cmapi.SchemeGroupVersion.WithResource("certificaterequests"), "testns",
+					gen.CertificateRequestFrom(bundle1.certificateRequest,
+						gen.SetCertificateRequestIssuer(issuerRef),
+						gen.SetCertificateRequestAnnotations(map[string]string{
+							cmapi.CertificateRequestPrivateKeyAnnotationKey: "exists",
+							cmapi.CertificateRequestRevisionAnnotationKey:   "1",
+						}),
+					)), relaxedCertificateRequestMatcher),
+			},
+		},
+		"should not create a CertificateRequest whilst the maximum number of requests are in flight": {
+			secrets: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "exists"},
+					Data:       map[string][]byte{corev1.TLSPrivateKeyKey: mustGenerateRSA(t, 2048)},
+				},
+			},
+			certificate: gen.CertificateFrom(bundle1.certificate,
+				gen.SetCertificateNextPrivateKeySecretName("exists"),
+				gen.SetCertificateRevision(4),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{Type: cmapi.CertificateConditionIssuing, Status: cmmeta.ConditionTrue}),
+			),
+			requests: []runtime.Object{
+				gen.CertificateRequestFrom(bundle1.certificateRequest,
+					gen.SetCertificateRequestAnnotations(map[string]string{
+						cmapi.CertificateRequestPrivateKeyAnnotationKey: "exists",
+						cmapi.CertificateRequestRevisionAnnotationKey:   "4",
+					}),
+					gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+						Type:   cmapi.CertificateRequestConditionReady,
+						Status: cmmeta.ConditionFalse,
+						Reason: cmapi.CertificateRequestReasonPending,
+					}),
+				),
+			},
+			maxInFlightRequests: 1,
+			expectedEvents:      []string{`Normal RequestsInFlight Waiting for 1 pending CertificateRequest(s) to complete before creating a new one`},
+		},
+		"should not emit another event if the Certificate was already waiting for requests in flight": {
+			secrets: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "exists"},
+					Data:       map[string][]byte{corev1.TLSPrivateKeyKey: mustGenerateRSA(t, 2048)},
+				},
+			},
+			certificate: gen.CertificateFrom(bundle1.certificate,
+				gen.SetCertificateNextPrivateKeySecretName("exists"),
+				gen.SetCertificateRevision(4),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{Type: cmapi.CertificateConditionIssuing, Status: cmmeta.ConditionTrue}),
+			),
+			requests: []runtime.Object{
+				gen.CertificateRequestFrom(bundle1.certificateRequest,
+					gen.SetCertificateRequestAnnotations(map[string]string{
+						cmapi.CertificateRequestPrivateKeyAnnotationKey: "exists",
+						cmapi.CertificateRequestRevisionAnnotationKey:   "4",
+					}),
+					gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+						Type:   cmapi.CertificateRequestConditionReady,
+						Status: cmmeta.ConditionFalse,
+						Reason: cmapi.CertificateRequestReasonPending,
+					}),
+				),
+			},
+			maxInFlightRequests: 1,
+			inFlightBlocked:     true,
+		},
+		"should create a CertificateRequest if earlier requests are no longer in flight": {
+			secrets: []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "exists"},
+					Data:       map[string][]byte{corev1.TLSPrivateKeyKey: mustGenerateRSA(t, 2048)},
+				},
+			},
+			certificate: gen.CertificateFrom(bundle1.certificate,
+				gen.SetCertificateNextPrivateKeySecretName("exists"),
+				gen.SetCertificateRevision(4),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{Type: cmapi.CertificateConditionIssuing, Status: cmmeta.ConditionTrue}),
+			),
+			requests: []runtime.Object{
+				gen.CertificateRequestFrom(bundle1.certificateRequest,
+					gen.SetCertificateRequestAnnotations(map[string]string{
+						cmapi.CertificateRequestPrivateKeyAnnotationKey: "exists",
+						cmapi.CertificateRequestRevisionAnnotationKey:   "3",
+					}),
+					gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+						Type:   cmapi.CertificateRequestConditionReady,
+						Status: cmmeta.ConditionTrue,
+						Reason: cmapi.CertificateRequestReasonIssued,
+					}),
+				),
+				gen.CertificateRequestFrom(bundle1.certificateRequest,
+					gen.SetCertificateRequestName("testing-number-2"),
+					gen.SetCertificateRequestAnnotations(map[string]string{
+						cmapi.CertificateRequestPrivateKeyAnnotationKey: "exists",
+						cmapi.CertificateRequestRevisionAnnotationKey:   "4",
+					}),
+					gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+						Type:   cmapi.CertificateRequestConditionReady,
+						Status: cmmeta.ConditionFalse,
+						Reason: cmapi.CertificateRequestReasonFailed,
+					}),
+				),
+			},
+			maxInFlightRequests: 1,
+			expectedEvents:      []string{`Normal Requested Created new CertificateRequest resource "test-notrandom"`},
+			expectedActions: []testpkg.Action{
+				testpkg.NewCustomMatch(coretesting.NewCreateAction(cmapi.SchemeGroupVersion.WithResource("certificaterequests"), "testns",
+					gen.CertificateRequestFrom(bundle1.certificateRequest,
+						gen.SetCertificateRequestAnnotations(map[string]string{
+							cmapi.CertificateRequestPrivateKeyAnnotationKey: "exists",
+							cmapi.CertificateRequestRevisionAnnotationKey:   "5",
+						}),
+					)), relaxedCertificateRequestMatcher),
+			},
+		},
 		"should delete request for the current revision if public keys do not match": {
 			secrets: []runtime.Object{
 				&corev1.Secret{
@@ -524,6 +852,7 @@ func TestProcessItem(t *testing.T) {
 				ExpectedEvents:  test.expectedEvents,
 				ExpectedActions: test.expectedActions,
 				StringGenerator: func(i int) string { return "notrandom" },
+				Clock:           fixedClock,
 			}
 			if test.certificate != nil {
 				builder.CertManagerObjects = append(builder.CertManagerObjects, test.certificate)
@@ -534,7 +863,11 @@ func TestProcessItem(t *testing.T) {
 			for _, req := range test.requests {
 				builder.CertManagerObjects = append(builder.CertManagerObjects, req)
 			}
+			if test.issuer != nil {
+				builder.CertManagerObjects = append(builder.CertManagerObjects, test.issuer)
+			}
 			builder.Init()
+			builder.Context.CertificateOptions.IssuerReadinessBackoffMax = test.issuerReadinessBackoffMax
 
 			// Register informers used by the controller using the registration wrapper
 			w := &controllerWrapper{}
@@ -542,6 +875,7 @@ func TestProcessItem(t *testing.T) {
 			if err != nil {
 				t.Fatal(err)
 			}
+			w.controller.maxInFlightRequests = test.maxInFlightRequests
 			// Start the informers and begin processing updates
 			builder.Start()
 			defer builder.Stop()
@@ -553,6 +887,9 @@ func TestProcessItem(t *testing.T) {
 					t.Fatal(err)
 				}
 			}
+			if test.inFlightBlocked {
+				w.controller.inFlightBlocked.Insert(key)
+			}
 
 			// Call ProcessItem
 			err = w.controller.ProcessItem(context.Background(), key)
@@ -0,0 +1,253 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretcleanup
+
+import (
+	"context"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	cminformers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+const (
+	ControllerName = "certificates-secret-cleanup"
+)
+
+// controller deletes the Secret of a deleted Certificate once the configured
+// grace period has passed. A finalizer is added to Certificates so that they
+// are kept until the Secret has been deleted.
+type controller struct {
+	certificateLister cmlisters.CertificateLister
+	secretLister      corelisters.SecretLister
+	client            cmclient.Interface
+	kubeClient        kubernetes.Interface
+	clock             clock.Clock
+	queue             workqueue.RateLimitingInterface
+
+	// gracePeriod is the duration after a Certificate is deleted that its
+	// Secret will be deleted. If zero, this controller only removes the
+	// finalizer from Certificates that have it.
+	gracePeriod time.Duration
+}
+
+func NewController(
+	kubeClient kubernetes.Interface,
+	client cmclient.Interface,
+	factory informers.SharedInformerFactory,
+	cmFactory cminformers.SharedInformerFactory,
+	clock clock.Clock,
+	certificateControllerOptions controllerpkg.CertificateOptions,
+) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
+	// create a queue used to queue up items to be processed
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
+
+	// obtain references to all the informers used by this controller
+	certificateInformer := cmFactory.Certmanager().V1().Certificates()
+	secretsInformer := factory.Core().V1().Secrets()
+
+	certificateInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: queue})
+
+	// build a list of InformerSynced functions that will be returned by the Register method.
+	// the controller will only begin processing items once all of these informers have synced.
+	mustSync := []cache.InformerSynced{
+		certificateInformer.Informer().HasSynced,
+		secretsInformer.Informer().HasSynced,
+	}
+
+	// The grace period only applies if Secrets would otherwise be deleted
+	// by the garbage collector.
+	var gracePeriod time.Duration
+	if certificateControllerOptions.EnableOwnerRef {
+		gracePeriod = certificateControllerOptions.SecretDeletionGracePeriod
+	}
+
+	return &controller{
+		certificateLister: certificateInformer.Lister(),
+		secretLister:      secretsInformer.Lister(),
+		client:            client,
+		kubeClient:        kubeClient,
+		clock:             clock,
+		queue:             queue,
+		gracePeriod:       gracePeriod,
+	}, queue, mustSync
+}
+
+// ProcessItem ensures Certificates have the secret cleanup finalizer if a
+// grace period is configured. Once a Certificate with the finalizer has been
+// deleted for longer than the grace period, its Secret is deleted and the
+// finalizer removed.
+func (c *controller) ProcessItem(ctx context.Context, key string) error {
+	log := logf.FromContext(ctx).WithValues("key", key)
+
+	ctx = logf.NewContext(ctx, log)
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		log.Error(err, "invalid resource key passed to ProcessItem")
+		return nil
+	}
+
+	crt, err := c.certificateLister.Certificates(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		log.V(logf.DebugLevel).Info("certificate not found for key")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	log = logf.WithResource(log, crt)
+	ctx = logf.NewContext(ctx, log)
+
+	enabled := c.gracePeriod > 0
+	hasFinalizer := hasSecretCleanupFinalizer(crt)
+
+	if crt.DeletionTimestamp == nil {
+		switch {
+		case enabled && !hasFinalizer:
+			log.V(logf.DebugLevel).Info("adding secret cleanup finalizer to certificate")
+			crt = crt.DeepCopy()
+			crt.Finalizers = append(crt.Finalizers, cmapi.CertificateSecretCleanupFinalizer)
+			_, err := c.client.CertmanagerV1().Certificates(crt.Namespace).Update(ctx, crt, metav1.UpdateOptions{})
+			return err
+		case !enabled && hasFinalizer:
+			return c.removeFinalizer(ctx, crt)
+		}
+		return nil
+	}
+
+	if !hasFinalizer {
+		return nil
+	}
+
+	// If the grace period has since been disabled, the finalizer is removed
+	// without deleting the Secret.
+	if !enabled {
+		return c.removeFinalizer(ctx, crt)
+	}
+
+	remaining := crt.DeletionTimestamp.Add(c.gracePeriod).Sub(c.clock.Now())
+	if remaining > 0 {
+		log.V(logf.DebugLevel).Info("certificate is being deleted, waiting for grace period before deleting secret", "after", remaining)
+		c.queue.AddAfter(key, remaining)
+		return nil
+	}
+
+	if err := c.deleteSecret(ctx, crt); err != nil {
+		return err
+	}
+
+	return c.removeFinalizer(ctx, crt)
+}
+
+// deleteSecret deletes the Secret named by the Certificate, if it exists and
+// was last written by cert-manager for this Certificate.
+func (c *controller) deleteSecret(ctx context.Context, crt *cmapi.Certificate) error {
+	log := logf.FromContext(ctx)
+
+	secret, err := c.secretLister.Secrets(crt.Namespace).Get(crt.Spec.SecretName)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	log = logf.WithRelatedResource(log, secret)
+
+	if secret.Annotations[cmapi.CertificateNameKey] != crt.Name {
+		log.V(logf.DebugLevel).Info("not deleting secret as it does not belong to the certificate")
+		return nil
+	}
+
+	log.V(logf.InfoLevel).Info("deleting secret of deleted certificate as grace period has passed")
+	err = c.kubeClient.CoreV1().Secrets(secret.Namespace).Delete(ctx, secret.Name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (c *controller) removeFinalizer(ctx context.Context, crt *cmapi.Certificate) error {
+	logf.FromContext(ctx).V(logf.DebugLevel).Info("removing secret cleanup finalizer from certificate")
+
+	crt = crt.DeepCopy()
+	var finalizers []string
+	for _, f := range crt.Finalizers {
+		if f != cmapi.CertificateSecretCleanupFinalizer {
+			finalizers = append(finalizers, f)
+		}
+	}
+	crt.Finalizers = finalizers
+
+	_, err := c.client.CertmanagerV1().Certificates(crt.Namespace).Update(ctx, crt, metav1.UpdateOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func hasSecretCleanupFinalizer(crt *cmapi.Certificate) bool {
+	for _, f := range crt.Finalizers {
+		if f == cmapi.CertificateSecretCleanupFinalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// controllerWrapper wraps the `controller` structure to make it implement
+// the controllerpkg.queueingController interface
+type controllerWrapper struct {
+	*controller
+}
+
+func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
+	ctrl, queue, mustSync := NewController(
+		ctx.Client,
+		ctx.CMClient,
+		ctx.KubeSharedInformerFactory,
+		ctx.SharedInformerFactory,
+		ctx.Clock,
+		ctx.CertificateOptions,
+	)
+	c.controller = ctrl
+
+	return queue, mustSync, nil
+}
+
+func init() {
+	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		return controllerpkg.NewBuilder(ctx, ControllerName).
+			For(&controllerWrapper{}).
+			Complete()
+	})
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretcleanup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coretesting "k8s.io/client-go/testing"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestProcessItem(t *testing.T) {
+	now := time.Now()
+	gracePeriod := time.Minute * 5
+
+	withFinalizer := func(crt *cmapi.Certificate) {
+		crt.Finalizers = []string{cmapi.CertificateSecretCleanupFinalizer}
+	}
+	deletedAt := func(ts time.Time) gen.CertificateModifier {
+		return func(crt *cmapi.Certificate) {
+			deletionTimestamp := metav1.NewTime(ts)
+			crt.DeletionTimestamp = &deletionTimestamp
+		}
+	}
+
+	baseCrt := gen.Certificate("test-cert",
+		gen.SetCertificateNamespace("testns"),
+		gen.SetCertificateSecretName("test-secret"),
+	)
+	baseSecret := gen.Secret("test-secret",
+		gen.SetSecretNamespace("testns"),
+		gen.SetSecretAnnotations(map[string]string{cmapi.CertificateNameKey: "test-cert"}),
+	)
+
+	tests := map[string]struct {
+		// certificate to be synced for the test.
+		certificate *cmapi.Certificate
+		// secret, if set, will exist in the apiserver before the test is run.
+		secret *corev1.Secret
+
+		enableOwnerRef bool
+		gracePeriod    time.Duration
+
+		expectedActions []testpkg.Action
+	}{
+		"add the finalizer to a Certificate if a grace period is configured": {
+			certificate:    baseCrt,
+			enableOwnerRef: true,
+			gracePeriod:    gracePeriod,
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateAction(cmapi.SchemeGroupVersion.WithResource("certificates"), "testns",
+					gen.CertificateFrom(baseCrt, withFinalizer))),
+			},
+		},
+		"do nothing if no grace period is configured": {
+			certificate:    baseCrt,
+			enableOwnerRef: true,
+		},
+		"do nothing if owner references are not enabled": {
+			certificate: baseCrt,
+			gracePeriod: gracePeriod,
+		},
+		"remove the finalizer if a grace period is no longer configured": {
+			certificate:    gen.CertificateFrom(baseCrt, withFinalizer),
+			enableOwnerRef: true,
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateAction(cmapi.SchemeGroupVersion.WithResource("certificates"), "testns", baseCrt)),
+			},
+		},
+		"keep the Secret if the Certificate was deleted within the grace period": {
+			certificate:    gen.CertificateFrom(baseCrt, withFinalizer, deletedAt(now.Add(-gracePeriod+time.Second))),
+			secret:         baseSecret,
+			enableOwnerRef: true,
+			gracePeriod:    gracePeriod,
+		},
+		"delete the Secret and remove the finalizer once the grace period has passed": {
+			certificate:    gen.CertificateFrom(baseCrt, withFinalizer, deletedAt(now.Add(-gracePeriod))),
+			secret:         baseSecret,
+			enableOwnerRef: true,
+			gracePeriod:    gracePeriod,
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewDeleteAction(corev1.SchemeGroupVersion.WithResource("secrets"), "testns", "test-secret")),
+				testpkg.NewAction(coretesting.NewUpdateAction(cmapi.SchemeGroupVersion.WithResource("certificates"), "testns",
+					gen.CertificateFrom(baseCrt, deletedAt(now.Add(-gracePeriod))))),
+			},
+		},
+		"do not delete a Secret that does not belong to the Certificate": {
+			certificate: gen.CertificateFrom(baseCrt, withFinalizer, deletedAt(now.Add(-gracePeriod))),
+			secret: gen.SecretFrom(baseSecret,
+				gen.SetSecretAnnotations(map[string]string{cmapi.CertificateNameKey: "another-cert"}),
+			),
+			enableOwnerRef: true,
+			gracePeriod:    gracePeriod,
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateAction(cmapi.SchemeGroupVersion.WithResource("certificates"), "testns",
+					gen.CertificateFrom(baseCrt, deletedAt(now.Add(-gracePeriod))))),
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Create and initialise a new unit test builder
+			builder := &testpkg.Builder{
+				T:                  t,
+				Clock:              fakeclock.NewFakeClock(now),
+				CertManagerObjects: []runtime.Object{test.certificate},
+				ExpectedActions:    test.expectedActions,
+			}
+			if test.secret != nil {
+				builder.KubeObjects = append(builder.KubeObjects, test.secret)
+			}
+			builder.Init()
+			builder.Context.CertificateOptions = controllerpkg.CertificateOptions{
+				EnableOwnerRef:            test.enableOwnerRef,
+				SecretDeletionGracePeriod: test.gracePeriod,
+			}
+
+			// Register informers used by the controller using the registration wrapper
+			w := &controllerWrapper{}
+			_, _, err := w.Register(builder.Context)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Start the informers and begin processing updates
+			builder.Start()
+			defer builder.Stop()
+
+			key, err := controllerpkg.KeyFunc(test.certificate)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := w.controller.ProcessItem(context.Background(), key); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if err := builder.AllEventsCalled(); err != nil {
+				builder.T.Error(err)
+			}
+			if err := builder.AllActionsExecuted(); err != nil {
+				builder.T.Error(err)
+			}
+		})
+	}
+}
+
+func TestSecretSurvivesGracePeriod(t *testing.T) {
+	now := time.Now()
+	gracePeriod := time.Minute * 5
+	clock := fakeclock.NewFakeClock(now)
+
+	deletionTimestamp := metav1.NewTime(now)
+	crt := gen.Certificate("test-cert",
+		gen.SetCertificateNamespace("testns"),
+		gen.SetCertificateSecretName("test-secret"),
+	)
+	crt.Finalizers = []string{cmapi.CertificateSecretCleanupFinalizer}
+	crt.DeletionTimestamp = &deletionTimestamp
+
+	builder := &testpkg.Builder{
+		T:                  t,
+		Clock:              clock,
+		CertManagerObjects: []runtime.Object{crt},
+		KubeObjects: []runtime.Object{
+			gen.Secret("test-secret",
+				gen.SetSecretNamespace("testns"),
+				gen.SetSecretAnnotations(map[string]string{cmapi.CertificateNameKey: "test-cert"}),
+			),
+		},
+	}
+	builder.Init()
+	builder.Context.CertificateOptions = controllerpkg.CertificateOptions{
+		EnableOwnerRef:            true,
+		SecretDeletionGracePeriod: gracePeriod,
+	}
+
+	w := &controllerWrapper{}
+	if _, _, err := w.Register(builder.Context); err != nil {
+		t.Fatal(err)
+	}
+	builder.Start()
+	defer builder.Stop()
+
+	secretExists := func() bool {
+		_, err := builder.FakeKubeClient().CoreV1().Secrets("testns").Get(context.TODO(), "test-secret", metav1.GetOptions{})
+		return err == nil
+	}
+
+	// the Secret is kept whilst within the grace period
+	for _, elapsed := range []time.Duration{0, gracePeriod / 2, gracePeriod - time.Second} {
+		clock.SetTime(now.Add(elapsed))
+		if err := w.controller.ProcessItem(context.Background(), "testns/test-cert"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !secretExists() {
+			t.Fatalf("expected secret to exist %s after the certificate was deleted", elapsed)
+		}
+	}
+
+	// the Secret is deleted once the grace period has passed
+	clock.SetTime(now.Add(gracePeriod))
+	if err := w.controller.ProcessItem(context.Background(), "testns/test-cert"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if secretExists() {
+		t.Errorf("expected secret to be deleted once the grace period had passed")
+	}
+
+	updated, err := builder.FakeCMClient().CertmanagerV1().Certificates("testns").Get(context.TODO(), "test-cert", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hasSecretCleanupFinalizer(updated) {
+		t.Errorf("expected secret cleanup finalizer to be removed")
+	}
+}
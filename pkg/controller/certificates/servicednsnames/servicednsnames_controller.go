@@ -0,0 +1,209 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicednsnames
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	cminformers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util"
+	"github.com/jetstack/cert-manager/pkg/util/predicate"
+)
+
+const (
+	ControllerName = "certificates-service-dns-names"
+)
+
+// controller sets the `spec.dnsNames` of Certificates that have a
+// `spec.serviceSelector` to the cluster DNS names of the Services matching
+// the selector. Changing the dnsNames causes the Certificate to be re-issued
+// by the trigger controller.
+type controller struct {
+	certificateLister cmlisters.CertificateLister
+	serviceLister     corelisters.ServiceLister
+	client            cmclient.Interface
+
+	// clusterDomain is the DNS domain of the cluster.
+	clusterDomain string
+}
+
+func NewController(
+	log logr.Logger,
+	client cmclient.Interface,
+	factory informers.SharedInformerFactory,
+	cmFactory cminformers.SharedInformerFactory,
+	certificateControllerOptions controllerpkg.CertificateOptions,
+) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
+	// create a queue used to queue up items to be processed
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
+
+	// obtain references to all the informers used by this controller
+	certificateInformer := cmFactory.Certmanager().V1().Certificates()
+	servicesInformer := factory.Core().V1().Services()
+
+	certificateInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: queue})
+	// When a Service resource changes, enqueue any Certificate resources in
+	// the same namespace that select Services.
+	servicesInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: certificates.EnqueueCertificatesForResourceUsingPredicates(log, queue, certificateInformer.Lister(), labels.Everything(), hasServiceSelector),
+	})
+
+	// build a list of InformerSynced functions that will be returned by the Register method.
+	// the controller will only begin processing items once all of these informers have synced.
+	mustSync := []cache.InformerSynced{
+		certificateInformer.Informer().HasSynced,
+		servicesInformer.Informer().HasSynced,
+	}
+
+	return &controller{
+		certificateLister: certificateInformer.Lister(),
+		serviceLister:     servicesInformer.Lister(),
+		client:            client,
+		clusterDomain:     certificateControllerOptions.ClusterDomain,
+	}, queue, mustSync
+}
+
+// hasServiceSelector is a predicate.ExtractorFunc that matches Certificates
+// with a `spec.serviceSelector`, regardless of the given object.
+func hasServiceSelector(_ runtime.Object) predicate.Func {
+	return func(obj runtime.Object) bool {
+		crt, ok := obj.(*cmapi.Certificate)
+		return ok && crt.Spec.ServiceSelector != nil
+	}
+}
+
+// ProcessItem updates the dnsNames of the Certificate with the given key if
+// they differ from the cluster DNS names of the Services it selects.
+func (c *controller) ProcessItem(ctx context.Context, key string) error {
+	log := logf.FromContext(ctx).WithValues("key", key)
+
+	ctx = logf.NewContext(ctx, log)
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		log.Error(err, "invalid resource key passed to ProcessItem")
+		return nil
+	}
+
+	crt, err := c.certificateLister.Certificates(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		log.V(logf.DebugLevel).Info("certificate not found for key")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if crt.Spec.ServiceSelector == nil || crt.DeletionTimestamp != nil {
+		return nil
+	}
+
+	log = logf.WithResource(log, crt)
+
+	selector, err := metav1.LabelSelectorAsSelector(crt.Spec.ServiceSelector)
+	if err != nil {
+		// the webhook validates the selector, so this is not retried
+		log.Error(err, "invalid serviceSelector on certificate")
+		return nil
+	}
+
+	services, err := c.serviceLister.Services(crt.Namespace).List(selector)
+	if err != nil {
+		return err
+	}
+
+	dnsNames := serviceDNSNames(services, c.clusterDomain)
+	if util.EqualSorted(crt.Spec.DNSNames, dnsNames) {
+		return nil
+	}
+
+	log.V(logf.InfoLevel).Info("updating dnsNames of certificate to match selected services", "dnsNames", dnsNames)
+
+	crt = crt.DeepCopy()
+	crt.Spec.DNSNames = dnsNames
+	_, err = c.client.CertmanagerV1().Certificates(crt.Namespace).Update(ctx, crt, metav1.UpdateOptions{})
+	return err
+}
+
+// serviceDNSNames returns the cluster DNS names of the given Services, in
+// order of Service name so that the result does not depend on the order the
+// Services are listed in.
+func serviceDNSNames(services []*corev1.Service, clusterDomain string) []string {
+	services = append([]*corev1.Service(nil), services...)
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Name < services[j].Name
+	})
+
+	var dnsNames []string
+	for _, svc := range services {
+		name := fmt.Sprintf("%s.%s.svc", svc.Name, svc.Namespace)
+		dnsNames = append(dnsNames, name)
+		if clusterDomain != "" {
+			dnsNames = append(dnsNames, name+"."+clusterDomain)
+		}
+	}
+
+	return dnsNames
+}
+
+// controllerWrapper wraps the `controller` structure to make it implement
+// the controllerpkg.queueingController interface
+type controllerWrapper struct {
+	*controller
+}
+
+func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
+	// construct a new named logger to be reused throughout the controller
+	log := logf.FromContext(ctx.RootContext, ControllerName)
+
+	ctrl, queue, mustSync := NewController(log,
+		ctx.CMClient,
+		ctx.KubeSharedInformerFactory,
+		ctx.SharedInformerFactory,
+		ctx.CertificateOptions,
+	)
+	c.controller = ctrl
+
+	return queue, mustSync, nil
+}
+
+func init() {
+	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		return controllerpkg.NewBuilder(ctx, ControllerName).
+			For(&controllerWrapper{}).
+			Complete()
+	})
+}
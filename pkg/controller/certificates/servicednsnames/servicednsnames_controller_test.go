@@ -0,0 +1,230 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicednsnames
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coretesting "k8s.io/client-go/testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	policiestest "github.com/jetstack/cert-manager/pkg/controller/certificates/trigger/policies/test"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func service(name string, labels map[string]string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "testns",
+			Labels:    labels,
+		},
+	}
+}
+
+func setServiceSelector(matchLabels map[string]string) gen.CertificateModifier {
+	return func(crt *cmapi.Certificate) {
+		crt.Spec.ServiceSelector = &metav1.LabelSelector{MatchLabels: matchLabels}
+	}
+}
+
+func TestProcessItem(t *testing.T) {
+	meshLabels := map[string]string{"app": "mesh"}
+	baseCrt := gen.Certificate("test-cert",
+		gen.SetCertificateNamespace("testns"),
+		gen.SetCertificateSecretName("test-secret"),
+		setServiceSelector(meshLabels),
+	)
+
+	tests := map[string]struct {
+		// certificate to be synced for the test.
+		certificate *cmapi.Certificate
+		// services that exist in the apiserver before the test is run.
+		services []runtime.Object
+
+		clusterDomain string
+
+		expectedActions []testpkg.Action
+	}{
+		"set the dnsNames of a Certificate to the names of matching Services": {
+			certificate: baseCrt,
+			services: []runtime.Object{
+				service("b", meshLabels),
+				service("a", meshLabels),
+				service("other", map[string]string{"app": "other"}),
+			},
+			clusterDomain: "cluster.local",
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateAction(cmapi.SchemeGroupVersion.WithResource("certificates"), "testns",
+					gen.CertificateFrom(baseCrt, gen.SetCertificateDNSNames(
+						"a.testns.svc", "a.testns.svc.cluster.local",
+						"b.testns.svc", "b.testns.svc.cluster.local",
+					)))),
+			},
+		},
+		"only set the short names of Services if no cluster domain is configured": {
+			certificate: baseCrt,
+			services:    []runtime.Object{service("a", meshLabels)},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateAction(cmapi.SchemeGroupVersion.WithResource("certificates"), "testns",
+					gen.CertificateFrom(baseCrt, gen.SetCertificateDNSNames("a.testns.svc")))),
+			},
+		},
+		"do nothing if the dnsNames already match the Services": {
+			certificate:   gen.CertificateFrom(baseCrt, gen.SetCertificateDNSNames("a.testns.svc", "a.testns.svc.cluster.local")),
+			services:      []runtime.Object{service("a", meshLabels)},
+			clusterDomain: "cluster.local",
+		},
+		"remove the names of Services that no longer match": {
+			certificate: gen.CertificateFrom(baseCrt, gen.SetCertificateDNSNames(
+				"a.testns.svc", "a.testns.svc.cluster.local",
+				"b.testns.svc", "b.testns.svc.cluster.local",
+			)),
+			services: []runtime.Object{
+				service("a", meshLabels),
+				service("b", map[string]string{"app": "other"}),
+			},
+			clusterDomain: "cluster.local",
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewUpdateAction(cmapi.SchemeGroupVersion.WithResource("certificates"), "testns",
+					gen.CertificateFrom(baseCrt, gen.SetCertificateDNSNames("a.testns.svc", "a.testns.svc.cluster.local")))),
+			},
+		},
+		"do nothing if the Certificate does not have a serviceSelector": {
+			certificate: gen.Certificate("test-cert",
+				gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateDNSNames("example.com"),
+			),
+			services:      []runtime.Object{service("a", meshLabels)},
+			clusterDomain: "cluster.local",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Create and initialise a new unit test builder
+			builder := &testpkg.Builder{
+				T:                  t,
+				CertManagerObjects: []runtime.Object{test.certificate},
+				KubeObjects:        test.services,
+				ExpectedActions:    test.expectedActions,
+			}
+			builder.Init()
+			builder.Context.CertificateOptions = controllerpkg.CertificateOptions{
+				ClusterDomain: test.clusterDomain,
+			}
+
+			// Register informers used by the controller using the registration wrapper
+			w := &controllerWrapper{}
+			_, _, err := w.Register(builder.Context)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Start the informers and begin processing updates
+			builder.Start()
+			defer builder.Stop()
+
+			key, err := controllerpkg.KeyFunc(test.certificate)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := w.controller.ProcessItem(context.Background(), key); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if err := builder.AllEventsCalled(); err != nil {
+				builder.T.Error(err)
+			}
+			if err := builder.AllActionsExecuted(); err != nil {
+				builder.T.Error(err)
+			}
+		})
+	}
+}
+
+// TestServiceChangesTriggerReissuance ensures that adding or removing a
+// Service matching the serviceSelector of a Certificate causes the
+// Certificate to no longer match the request for its current certificate,
+// which triggers a re-issuance.
+func TestServiceChangesTriggerReissuance(t *testing.T) {
+	meshLabels := map[string]string{"app": "mesh"}
+
+	crt := gen.Certificate("test-cert",
+		gen.SetCertificateNamespace("testns"),
+		gen.SetCertificateSecretName("test-secret"),
+		gen.SetCertificateCommonName("mesh"),
+		setServiceSelector(meshLabels),
+	)
+	crt = syncDNSNames(t, crt, service("a", meshLabels))
+	policiestest.AssertReissue(t, crt, crt, false)
+
+	added := syncDNSNames(t, crt, service("a", meshLabels), service("b", meshLabels))
+	policiestest.AssertReissue(t, crt, added, true)
+
+	removed := syncDNSNames(t, added, service("a", meshLabels))
+	policiestest.AssertReissue(t, added, removed, true)
+
+	if len(removed.Spec.DNSNames) != 2 || removed.Spec.DNSNames[0] != "a.testns.svc" {
+		t.Errorf("unexpected dnsNames after removing a Service: %v", removed.Spec.DNSNames)
+	}
+}
+
+// syncDNSNames runs the controller for the given Certificate with the given
+// Services existing, and returns the Certificate as stored afterwards.
+func syncDNSNames(t *testing.T, crt *cmapi.Certificate, services ...*corev1.Service) *cmapi.Certificate {
+	var kubeObjects []runtime.Object
+	for _, svc := range services {
+		kubeObjects = append(kubeObjects, svc)
+	}
+
+	builder := &testpkg.Builder{
+		T:                  t,
+		CertManagerObjects: []runtime.Object{crt},
+		KubeObjects:        kubeObjects,
+	}
+	builder.Init()
+	builder.Context.CertificateOptions = controllerpkg.CertificateOptions{
+		ClusterDomain: "cluster.local",
+	}
+
+	w := &controllerWrapper{}
+	if _, _, err := w.Register(builder.Context); err != nil {
+		t.Fatal(err)
+	}
+	builder.Start()
+	defer builder.Stop()
+
+	key, err := controllerpkg.KeyFunc(crt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.controller.ProcessItem(context.Background(), key); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	updated, err := builder.CMClient.CertmanagerV1().Certificates(crt.Namespace).Get(context.Background(), crt.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return updated
+}
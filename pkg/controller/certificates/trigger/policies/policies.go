@@ -19,6 +19,7 @@ package policies
 
 import (
 	"crypto/tls"
+	"encoding/pem"
 	"fmt"
 	"time"
 
@@ -70,15 +71,16 @@ func (c Chain) Evaluate(input Input) (string, string, bool) {
 	return "", "", false
 }
 
-func NewTriggerPolicyChain(c clock.Clock, defaultRenewBeforeExpiryDuration time.Duration) Chain {
+func NewTriggerPolicyChain(c clock.Clock, defaultRenewBeforeExpiryDuration time.Duration, defaultRenewBeforeExpiryPercentage int) Chain {
 	return Chain{
 		SecretDoesNotExist,
 		SecretIsMissingData,
 		SecretPublicKeysDiffer,
 		SecretPrivateKeyMatchesSpec,
+		SecretPrivateKeyEncodingMatchesSpec,
 		SecretIssuerAnnotationsNotUpToDate,
 		CurrentCertificateRequestNotValidForSpec,
-		CurrentCertificateNearingExpiry(c, defaultRenewBeforeExpiryDuration),
+		CurrentCertificateNearingExpiry(c, defaultRenewBeforeExpiryDuration, defaultRenewBeforeExpiryPercentage),
 	}
 }
 
@@ -137,6 +139,35 @@ func SecretPrivateKeyMatchesSpec(input Input) (string, string, bool) {
 	return "", "", false
 }
 
+// SecretPrivateKeyEncodingMatchesSpec triggers a re-issuance if the
+// Certificate explicitly sets a private key encoding and the private key in
+// the Secret is encoded differently, so that the key is stored again using
+// the requested encoding.
+func SecretPrivateKeyEncodingMatchesSpec(input Input) (string, string, bool) {
+	if input.Certificate.Spec.PrivateKey == nil || input.Certificate.Spec.PrivateKey.Encoding == "" {
+		return "", "", false
+	}
+	encoding := input.Certificate.Spec.PrivateKey.Encoding
+
+	pkBytes := input.Secret.Data[corev1.TLSPrivateKeyKey]
+	pk, err := pki.DecodePrivateKeyBytes(pkBytes)
+	if err != nil {
+		return SecretMismatch, fmt.Sprintf("Existing issued Secret contains invalid private key data: %v", err), true
+	}
+
+	expectedBytes, err := pki.EncodePrivateKey(pk, encoding)
+	if err != nil {
+		return SecretMismatch, fmt.Sprintf("Failed to check private key encoding is up to date: %v", err), true
+	}
+
+	block, _ := pem.Decode(pkBytes)
+	expectedBlock, _ := pem.Decode(expectedBytes)
+	if block.Type != expectedBlock.Type {
+		return SecretMismatch, fmt.Sprintf("Existing private key is not %s encoded", encoding), true
+	}
+	return "", "", false
+}
+
 func SecretIssuerAnnotationsNotUpToDate(input Input) (string, string, bool) {
 	name := input.Secret.Annotations[cmapi.IssuerNameAnnotationKey]
 	kind := input.Secret.Annotations[cmapi.IssuerKindAnnotationKey]
@@ -195,7 +226,10 @@ func currentSecretValidForSpec(input Input) (string, string, bool) {
 // CurrentCertificateNearingExpiry returns a policy function that can be used to
 // check whether an X.509 cert currently issued for a Certificate should be
 // renewed.
-func CurrentCertificateNearingExpiry(c clock.Clock, defaultRenewBeforeExpiryDuration time.Duration) Func {
+// A certificate whose renewal time is already in the past, for example because
+// it was issued with less than the renewBefore duration remaining, is renewed
+// immediately.
+func CurrentCertificateNearingExpiry(c clock.Clock, defaultRenewBeforeExpiryDuration time.Duration, defaultRenewBeforeExpiryPercentage int) Func {
 
 	return func(input Input) (string, string, bool) {
 
@@ -213,7 +247,7 @@ func CurrentCertificateNearingExpiry(c clock.Clock, defaultRenewBeforeExpiryDura
 		notBefore := metav1.NewTime(x509cert.NotBefore)
 		notAfter := metav1.NewTime(x509cert.NotAfter)
 		crt := input.Certificate
-		renewBefore := certificates.RenewBeforeExpiryDuration(notBefore.Time, notAfter.Time, crt.Spec.RenewBefore, defaultRenewBeforeExpiryDuration)
+		renewBefore := certificates.RenewBeforeExpiryDuration(notBefore.Time, notAfter.Time, crt.Spec.RenewBefore, defaultRenewBeforeExpiryDuration, defaultRenewBeforeExpiryPercentage)
 		renewalTime := metav1.NewTime(notAfter.Add(-1 * renewBefore))
 
 		renewIn := renewalTime.Time.Sub(c.Now())
@@ -130,6 +130,24 @@ func TestDefaultPolicyChain(t *testing.T) {
 			message: "Issuing certificate as Secret contains an invalid key-pair: tls: private key does not match public key",
 			reissue: true,
 		},
+		"trigger issuance as Secret private key is not stored in the requested encoding": {
+			certificate: &cmapi.Certificate{Spec: cmapi.CertificateSpec{
+				SecretName: "something",
+				PrivateKey: &cmapi.CertificatePrivateKey{Encoding: cmapi.PKCS1},
+			}},
+			secret: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "something"},
+				Data: map[string][]byte{
+					// staticFixedPrivateKey is PKCS8 encoded
+					corev1.TLSPrivateKeyKey: staticFixedPrivateKey,
+					corev1.TLSCertKey: internaltest.MustCreateCert(t, staticFixedPrivateKey,
+						&cmapi.Certificate{Spec: cmapi.CertificateSpec{CommonName: "example.com"}},
+					),
+				},
+			},
+			reason:  SecretMismatch,
+			message: "Existing private key is not PKCS1 encoded",
+			reissue: true,
+		},
 		"trigger issuance as Secret has old/incorrect 'issuer name' annotation": {
 			certificate: &cmapi.Certificate{Spec: cmapi.CertificateSpec{
 				SecretName: "something",
@@ -251,6 +269,50 @@ func TestDefaultPolicyChain(t *testing.T) {
 			message: "Fields on existing CertificateRequest resource not up to date: [spec.commonName]",
 			reissue: true,
 		},
+		"trigger issuance when certificate policies have changed": {
+			certificate: &cmapi.Certificate{Spec: cmapi.CertificateSpec{
+				CommonName: "example.com",
+				IssuerRef: cmmeta.ObjectReference{
+					Name:  "testissuer",
+					Kind:  "IssuerKind",
+					Group: "group.example.com",
+				},
+				CertificatePolicies: []cmapi.CertificatePolicy{
+					{PolicyID: "0.4.0.194112.1.2", CPSURI: "https://pki.example.com/cps"},
+				},
+			}},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "something",
+					Annotations: map[string]string{
+						cmapi.IssuerNameAnnotationKey:  "testissuer",
+						cmapi.IssuerKindAnnotationKey:  "IssuerKind",
+						cmapi.IssuerGroupAnnotationKey: "group.example.com",
+					},
+				},
+				Data: map[string][]byte{
+					corev1.TLSPrivateKeyKey: staticFixedPrivateKey,
+					corev1.TLSCertKey: internaltest.MustCreateCert(t, staticFixedPrivateKey,
+						&cmapi.Certificate{Spec: cmapi.CertificateSpec{CommonName: "example.com"}},
+					),
+				},
+			},
+			request: &cmapi.CertificateRequest{Spec: cmapi.CertificateRequestSpec{
+				IssuerRef: cmmeta.ObjectReference{
+					Name:  "testissuer",
+					Kind:  "IssuerKind",
+					Group: "group.example.com",
+				},
+				Request: internaltest.MustGenerateCSRImpl(t, staticFixedPrivateKey, &cmapi.Certificate{Spec: cmapi.CertificateSpec{
+					CommonName: "example.com",
+					CertificatePolicies: []cmapi.CertificatePolicy{
+						{PolicyID: "0.4.0.194112.1.2"},
+					},
+				}}),
+			}},
+			reason:  RequestChanged,
+			message: "Fields on existing CertificateRequest resource not up to date: [spec.certificatePolicies]",
+			reissue: true,
+		},
 		"do nothing if CertificateRequest matches spec": {
 			certificate: &cmapi.Certificate{Spec: cmapi.CertificateSpec{
 				CommonName: "example.com",
@@ -289,6 +351,42 @@ func TestDefaultPolicyChain(t *testing.T) {
 				}}),
 			}},
 		},
+		"do nothing if Secret private key is stored in the requested encoding": {
+			certificate: &cmapi.Certificate{Spec: cmapi.CertificateSpec{
+				CommonName: "example.com",
+				IssuerRef: cmmeta.ObjectReference{
+					Name:  "testissuer",
+					Kind:  "IssuerKind",
+					Group: "group.example.com",
+				},
+				PrivateKey: &cmapi.CertificatePrivateKey{Encoding: cmapi.PKCS8},
+			}},
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "something",
+					Annotations: map[string]string{
+						cmapi.IssuerNameAnnotationKey:  "testissuer",
+						cmapi.IssuerKindAnnotationKey:  "IssuerKind",
+						cmapi.IssuerGroupAnnotationKey: "group.example.com",
+					},
+				},
+				Data: map[string][]byte{
+					corev1.TLSPrivateKeyKey: staticFixedPrivateKey,
+					corev1.TLSCertKey: internaltest.MustCreateCert(t, staticFixedPrivateKey,
+						&cmapi.Certificate{Spec: cmapi.CertificateSpec{CommonName: "example.com"}},
+					),
+				},
+			},
+			request: &cmapi.CertificateRequest{Spec: cmapi.CertificateRequestSpec{
+				IssuerRef: cmmeta.ObjectReference{
+					Name:  "testissuer",
+					Kind:  "IssuerKind",
+					Group: "group.example.com",
+				},
+				Request: internaltest.MustGenerateCSRImpl(t, staticFixedPrivateKey, &cmapi.Certificate{Spec: cmapi.CertificateSpec{
+					CommonName: "example.com",
+				}}),
+			}},
+		},
 		"compare signed x509 certificate in Secret with spec if CertificateRequest does not exist": {
 			certificate: &cmapi.Certificate{Spec: cmapi.CertificateSpec{
 				CommonName: "new.example.com",
@@ -487,7 +585,7 @@ func TestDefaultPolicyChain(t *testing.T) {
 	}
 	// we don't really test default renewal time here, it's just passed through
 	someDefaultRenewalTime := time.Hour * 5
-	policyChain := NewTriggerPolicyChain(clock, someDefaultRenewalTime)
+	policyChain := NewTriggerPolicyChain(clock, someDefaultRenewalTime, 0)
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
 			reason, message, reissue := policyChain.Evaluate(Input{
@@ -508,3 +606,57 @@ func TestDefaultPolicyChain(t *testing.T) {
 		})
 	}
 }
+
+func TestCurrentCertificateNearingExpiryPercentage(t *testing.T) {
+	clock := &fakeclock.FakeClock{}
+	staticFixedPrivateKey := internaltest.MustCreatePEMPrivateKey(t)
+	// the certificate is valid for 10 hours, 3 of which remain
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "something"},
+		Data: map[string][]byte{
+			corev1.TLSPrivateKeyKey: staticFixedPrivateKey,
+			corev1.TLSCertKey: internaltest.MustCreateCertWithNotBeforeAfter(t, staticFixedPrivateKey,
+				&cmapi.Certificate{Spec: cmapi.CertificateSpec{CommonName: "example.com"}},
+				clock.Now().Add(time.Hour*-7),
+				clock.Now().Add(time.Hour*3),
+			),
+		},
+	}
+	tests := map[string]struct {
+		renewBefore *metav1.Duration
+		percentage  int
+		reissue     bool
+	}{
+		"does not renew when more than the percentage of the lifetime remains": {
+			percentage: 25,
+			reissue:    false,
+		},
+		"renews when less than the percentage of the lifetime remains": {
+			percentage: 33,
+			reissue:    true,
+		},
+		"renewBefore of the Certificate takes precedence over the percentage": {
+			renewBefore: &metav1.Duration{Duration: time.Hour},
+			percentage:  33,
+			reissue:     false,
+		},
+		"uses the default duration if no percentage is set": {
+			reissue: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			policy := CurrentCertificateNearingExpiry(clock, time.Hour*5, test.percentage)
+			_, _, reissue := policy(Input{
+				Certificate: &cmapi.Certificate{Spec: cmapi.CertificateSpec{
+					CommonName:  "example.com",
+					RenewBefore: test.renewBefore,
+				}},
+				Secret: secret,
+			})
+			if test.reissue != reissue {
+				t.Errorf("unexpected 'reissue' exp=%v, got=%v", test.reissue, reissue)
+			}
+		})
+	}
+}
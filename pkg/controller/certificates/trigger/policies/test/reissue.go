@@ -0,0 +1,41 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package test
+
+import (
+	"testing"
+	"time"
+
+	fakeclock "k8s.io/utils/clock/testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	internaltest "github.com/jetstack/cert-manager/pkg/controller/certificates/internal/test"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates/trigger/policies"
+)
+
+// AssertReissue checks whether the trigger policies re-issue crt if its
+// current certificate was requested for the Certificate issued.
+func AssertReissue(t *testing.T, issued, crt *cmapi.Certificate, expectReissue bool) {
+	bundle := internaltest.MustCreateCryptoBundle(t, issued, fakeclock.NewFakeClock(time.Now()))
+	reason, message, reissue := policies.CurrentCertificateRequestNotValidForSpec(policies.Input{
+		Certificate:            crt,
+		CurrentRevisionRequest: bundle.CertificateRequest,
+	})
+	if reissue != expectReissue {
+		t.Errorf("expected reissue=%v but got reissue=%v (reason=%q, message=%q)", expectReissue, reissue, reason, message)
+	}
+}
@@ -18,6 +18,7 @@ package trigger
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -43,12 +44,22 @@ import (
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/trigger/policies"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	"github.com/jetstack/cert-manager/pkg/scheduler"
+	"github.com/jetstack/cert-manager/pkg/util/issuerallowlist"
+	"github.com/jetstack/cert-manager/pkg/util/maintenance"
 	"github.com/jetstack/cert-manager/pkg/util/predicate"
 )
 
 const (
 	ControllerName = "certificates-trigger"
 
+	reasonSecretOwnedByAnother = "SecretOwnedByAnother"
+	reasonIssuerNotAllowed     = "IssuerNotAllowed"
+	reasonMaintenanceWindow    = "MaintenanceWindow"
+	reasonRenewalSchedule      = "RenewalSchedule"
+	reasonNamespaceLimit       = "NamespaceIssuanceLimit"
+	reasonAdopted              = "Adopted"
+	reasonNamespaceTerminating = "NamespaceTerminating"
+
 	// the amount of time after the LastFailureTime of a Certificate
 	// before the request should be retried.
 	// In future this should be replaced with a more dynamic exponential
@@ -63,11 +74,27 @@ const (
 // certificate is required.
 type controller struct {
 	certificateLister        cmlisters.CertificateLister
+	certificateIndexer       cache.Indexer
 	certificateRequestLister cmlisters.CertificateRequestLister
 	secretLister             corelisters.SecretLister
 	client                   cmclient.Interface
 	recorder                 record.EventRecorder
 	scheduledWorkQueue       scheduler.ScheduledWorkQueue
+	maintenanceWindows       maintenance.Windows
+
+	// maxIssuancesPerNamespace is the maximum number of Certificates in a
+	// namespace that may have the Issuing condition at once. If zero, there
+	// is no limit.
+	maxIssuancesPerNamespace int
+
+	// allowedIssuers are the issuers that Certificates may reference.
+	// Certificates referencing any other issuer are not issued.
+	allowedIssuers issuerallowlist.Allowlist
+
+	// namespaceLister is used to find the phase of the namespace of a
+	// Certificate. If nil, Certificates in terminating namespaces are issued
+	// as usual.
+	namespaceLister corelisters.NamespaceLister
 
 	// The following are used for testing purposes.
 	clock              clock.Clock
@@ -83,17 +110,54 @@ func NewController(
 	recorder record.EventRecorder,
 	clock clock.Clock,
 	shouldReissue policies.Func,
+	maintenanceWindows maintenance.Windows,
+	prioritizeByExpiry bool,
+	maxIssuancesPerNamespace int,
+	triggerOnSecretAnnotation string,
+	allowedIssuers issuerallowlist.Allowlist,
+	skipTerminatingNamespaces bool,
 ) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
-	// create a queue used to queue up items to be processed
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
-
 	// obtain references to all the informers used by this controller
 	certificateInformer := cmFactory.Certmanager().V1().Certificates()
 	certificateRequestInformer := cmFactory.Certmanager().V1().CertificateRequests()
 	secretsInformer := factory.Core().V1().Secrets()
 
+	// create a queue used to queue up items to be processed
+	rateLimiter := workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30)
+	var queue workqueue.RateLimitingInterface
+	if prioritizeByExpiry {
+		// process the Certificates that are most urgently in need of
+		// renewal first if a backlog has built up.
+		queue = certificates.NewExpiryPriorityQueue(rateLimiter, certificateInformer.Lister(), clock)
+	} else {
+		queue = workqueue.NewNamedRateLimitingQueue(rateLimiter, ControllerName)
+	}
+
 	certificateInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: queue})
 
+	// When a Certificate resource changes, enqueue any other Certificate
+	// resources using the same spec.secretName, so that ownership of the
+	// Secret is re-evaluated and they can be unblocked once they own it.
+	if err := certificates.AddSecretNameIndex(certificateInformer.Informer()); err != nil {
+		log.Error(err, "failed to add secretName index to Certificate informer")
+	}
+	enqueueSameSecretName := certificates.EnqueueCertificatesWithSameSecretName(log, queue, certificateInformer.Informer().GetIndexer())
+	certificateInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: enqueueSameSecretName,
+		UpdateFunc: func(old, new interface{}) {
+			enqueueSameSecretName(old)
+			enqueueSameSecretName(new)
+		},
+		DeleteFunc: enqueueSameSecretName,
+	})
+
+	// When an issuance completes, enqueue any Certificates in the same
+	// namespace whose issuance was deferred by the per-namespace limit, so
+	// that one of them can take its place.
+	if maxIssuancesPerNamespace > 0 {
+		certificateInformer.Informer().AddEventHandler(enqueueNamespaceLimitedCertificates(log, queue, certificateInformer.Lister()))
+	}
+
 	// When a CertificateRequest resource changes, enqueue the Certificate resource that owns it.
 	certificateRequestInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
 		WorkFunc: certificates.EnqueueCertificatesForResourceUsingPredicates(log, queue, certificateInformer.Lister(), labels.Everything(), predicate.ResourceOwnerOf),
@@ -104,6 +168,11 @@ func NewController(
 		WorkFunc: certificates.EnqueueCertificatesForResourceUsingPredicates(log, queue, certificateInformer.Lister(), labels.Everything(),
 			predicate.ExtractResourceName(predicate.CertificateSecretName)),
 	})
+	// When the configured annotation on a Secret changes, enqueue the
+	// Certificate resources that own it.
+	if triggerOnSecretAnnotation != "" {
+		secretsInformer.Informer().AddEventHandler(certificates.EnqueueCertificatesForSecretAnnotationChange(log, queue, certificateInformer.Informer().GetIndexer(), triggerOnSecretAnnotation))
+	}
 
 	// build a list of InformerSynced functions that will be returned by the Register method.
 	// the controller will only begin processing items once all of these informers have synced.
@@ -113,13 +182,28 @@ func NewController(
 		certificateInformer.Informer().HasSynced,
 	}
 
+	// When a namespace starts terminating, enqueue the Certificates in it so
+	// that any issuance in progress is abandoned.
+	var namespaceLister corelisters.NamespaceLister
+	if skipTerminatingNamespaces {
+		namespaceInformer := factory.Core().V1().Namespaces()
+		namespaceInformer.Informer().AddEventHandler(enqueueTerminatingNamespaceCertificates(log, queue, certificateInformer.Lister()))
+		namespaceLister = namespaceInformer.Lister()
+		mustSync = append(mustSync, namespaceInformer.Informer().HasSynced)
+	}
+
 	return &controller{
 		certificateLister:        certificateInformer.Lister(),
+		certificateIndexer:       certificateInformer.Informer().GetIndexer(),
 		certificateRequestLister: certificateRequestInformer.Lister(),
 		secretLister:             secretsInformer.Lister(),
 		client:                   client,
 		recorder:                 recorder,
 		scheduledWorkQueue:       scheduler.NewScheduledWorkQueue(clock, queue.Add),
+		maintenanceWindows:       maintenanceWindows,
+		maxIssuancesPerNamespace: maxIssuancesPerNamespace,
+		allowedIssuers:           allowedIssuers,
+		namespaceLister:          namespaceLister,
 
 		// The following are used for testing purposes.
 		clock:         clock,
@@ -148,10 +232,35 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 	if err != nil {
 		return err
 	}
-	if apiutil.CertificateHasCondition(crt, cmapi.CertificateCondition{
-		Type:   cmapi.CertificateConditionIssuing,
-		Status: cmmeta.ConditionTrue,
-	}) {
+
+	if crt.Spec.Replicas != nil {
+		// Certificates with replicas are templates for the Certificates
+		// created by the replicas controller, and are never issued themselves.
+		return nil
+	}
+
+	crt, terminating, err := c.updateNamespaceTerminatingCondition(ctx, crt)
+	if err != nil || terminating {
+		// Do nothing if the namespace of the Certificate is being deleted,
+		// as any resources created for the issuance would be rejected.
+		return err
+	}
+
+	crt, allowed, err := c.updateIssuerNotAllowedCondition(ctx, crt)
+	if err != nil || !allowed {
+		// Do nothing if the Certificate references an issuer that the
+		// cluster administrator has not allowed.
+		return err
+	}
+
+	crt, ownedByAnother, err := c.updateSecretOwnedByAnotherCondition(ctx, crt)
+	if err != nil || ownedByAnother {
+		// Do nothing if another Certificate owns the Secret, as the
+		// Certificates would otherwise overwrite each other's Secret.
+		return err
+	}
+
+	if certificateIsIssuing(crt) {
 		// Do nothing if an issuance is already in progress.
 		return nil
 	}
@@ -179,7 +288,46 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 	reason, message, reissue := c.shouldReissue(input)
 	if !reissue {
 		// no re-issuance required, return early
-		return nil
+		if shouldAdoptExistingSecret(crt, input) {
+			crt, err = c.adoptExistingSecret(ctx, crt)
+			if err != nil {
+				return err
+			}
+		}
+		return c.removeIssuanceDeferredCondition(ctx, crt)
+	}
+
+	// Defer the re-issuance until the end of the maintenance window if one
+	// is in progress.
+	now := c.clock.Now()
+	if end, ok := c.maintenanceWindows.End(now); ok {
+		log.V(logf.InfoLevel).Info("Not re-issuing certificate during maintenance window", "reason", reason, "window_end", end)
+		c.scheduleRecheckOfCertificateIfRequired(log, key, end.Sub(now))
+		return c.setIssuanceDeferredCondition(ctx, crt, reasonMaintenanceWindow,
+			fmt.Sprintf("Issuance is deferred until the maintenance window ends at %s: %s", end.UTC().Format(time.RFC3339), message))
+	}
+
+	// Defer a renewal until the next time allowed by the renewal schedule
+	// of the Certificate.
+	if next, ok := nextScheduledRenewal(log, crt, reason, now); ok {
+		log.V(logf.InfoLevel).Info("Not renewing certificate outside of its renewal schedule", "next_renewal", next)
+		c.scheduleRecheckOfCertificateIfRequired(log, key, next.Sub(now))
+		return c.setIssuanceDeferredCondition(ctx, crt, reasonRenewalSchedule,
+			fmt.Sprintf("Renewal is deferred until %s as allowed by the renewal schedule: %s", next.UTC().Format(time.RFC3339), message))
+	}
+
+	// Defer the issuance if the maximum number of Certificates are already
+	// being issued in the namespace, so that a namespace with many
+	// Certificates due for issuance cannot starve issuance in others. The
+	// Certificate is re-queued once an issuance in the namespace completes.
+	issuing, err := c.issuingInNamespace(crt.Namespace)
+	if err != nil {
+		return err
+	}
+	if c.maxIssuancesPerNamespace > 0 && issuing >= c.maxIssuancesPerNamespace {
+		log.V(logf.InfoLevel).Info("Not issuing certificate as the maximum number of concurrent issuances in the namespace has been reached", "limit", c.maxIssuancesPerNamespace)
+		return c.setIssuanceDeferredCondition(ctx, crt, reasonNamespaceLimit,
+			fmt.Sprintf("Issuance is deferred as %d Certificates are already being issued in this namespace: %s", c.maxIssuancesPerNamespace, message))
 	}
 
 	// Although the below recorder.Event already logs the event, the log
@@ -189,6 +337,7 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 	log.V(logf.InfoLevel).Info("Certificate must be re-issued", "reason", reason, "message", message)
 
 	crt = crt.DeepCopy()
+	apiutil.RemoveCertificateCondition(crt, cmapi.CertificateConditionIssuanceDeferred)
 	apiutil.SetCertificateCondition(crt, crt.Generation, cmapi.CertificateConditionIssuing, cmmeta.ConditionTrue, reason, message)
 	_, err = c.client.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
 	if err != nil {
@@ -199,6 +348,349 @@ func (c *controller) ProcessItem(ctx context.Context, key string) error {
 	return nil
 }
 
+// updateSecretOwnedByAnotherCondition sets the SecretOwnedByAnother condition
+// on the given Certificate if another Certificate owns the Secret named in its
+// `spec.secretName`, removing the Issuing condition so that any issuance in
+// progress is abandoned, or removes the condition if it no longer applies. It
+// returns the updated Certificate and whether the Secret is owned by another
+// Certificate.
+func (c *controller) updateSecretOwnedByAnotherCondition(ctx context.Context, crt *cmapi.Certificate) (*cmapi.Certificate, bool, error) {
+	owner, err := certificates.SecretOwner(c.certificateIndexer, c.secretLister, crt)
+	if err != nil {
+		return nil, false, err
+	}
+
+	existing := apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionSecretOwnedByAnother)
+	if owner.Name == crt.Name {
+		if existing == nil {
+			return crt, false, nil
+		}
+		crt = crt.DeepCopy()
+		apiutil.RemoveCertificateCondition(crt, cmapi.CertificateConditionSecretOwnedByAnother)
+		crt, err = c.client.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
+		if err != nil {
+			return nil, false, err
+		}
+		return crt, false, nil
+	}
+
+	message := fmt.Sprintf("Secret %q is already used by Certificate %q", crt.Spec.SecretName, owner.Name)
+	if existing != nil && existing.Status == cmmeta.ConditionTrue && existing.Message == message && !certificateIsIssuing(crt) {
+		return crt, true, nil
+	}
+
+	logf.FromContext(ctx).V(logf.InfoLevel).Info("Not issuing certificate as its Secret is owned by another Certificate", "owner", owner.Name)
+
+	crt = crt.DeepCopy()
+	apiutil.RemoveCertificateCondition(crt, cmapi.CertificateConditionIssuing)
+	apiutil.SetCertificateCondition(crt, crt.Generation, cmapi.CertificateConditionSecretOwnedByAnother, cmmeta.ConditionTrue, reasonSecretOwnedByAnother, message)
+	_, err = c.client.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, true, err
+	}
+	c.recorder.Event(crt, corev1.EventTypeWarning, reasonSecretOwnedByAnother, message)
+
+	return crt, true, nil
+}
+
+// updateIssuerNotAllowedCondition sets the IssuerNotAllowed condition on the
+// given Certificate if its `spec.issuerRef` is not in the list of allowed
+// issuers, removing the Issuing condition so that any issuance in progress is
+// abandoned, or removes the condition if it no longer applies. It returns the
+// updated Certificate and whether its issuer is allowed.
+func (c *controller) updateIssuerNotAllowedCondition(ctx context.Context, crt *cmapi.Certificate) (*cmapi.Certificate, bool, error) {
+	existing := apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionIssuerNotAllowed)
+	if c.allowedIssuers.Allows(crt.Spec.IssuerRef) {
+		if existing == nil {
+			return crt, true, nil
+		}
+		crt = crt.DeepCopy()
+		apiutil.RemoveCertificateCondition(crt, cmapi.CertificateConditionIssuerNotAllowed)
+		updated, err := c.client.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
+		if err != nil {
+			return nil, false, err
+		}
+		return updated, true, nil
+	}
+
+	ref := crt.Spec.IssuerRef
+	message := fmt.Sprintf("Issuer %q of kind %q in group %q is not in the list of issuers allowed by the cluster administrator", ref.Name, ref.Kind, ref.Group)
+	if existing != nil && existing.Status == cmmeta.ConditionTrue && existing.Message == message && !certificateIsIssuing(crt) {
+		return crt, false, nil
+	}
+
+	logf.FromContext(ctx).V(logf.InfoLevel).Info("Not issuing certificate as its issuer is not allowed", "issuer", ref.Name, "kind", ref.Kind, "group", ref.Group)
+
+	crt = crt.DeepCopy()
+	apiutil.RemoveCertificateCondition(crt, cmapi.CertificateConditionIssuing)
+	apiutil.SetCertificateCondition(crt, crt.Generation, cmapi.CertificateConditionIssuerNotAllowed, cmmeta.ConditionTrue, reasonIssuerNotAllowed, message)
+	_, err := c.client.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, false, err
+	}
+	c.recorder.Event(crt, corev1.EventTypeWarning, reasonIssuerNotAllowed, message)
+
+	return crt, false, nil
+}
+
+// updateNamespaceTerminatingCondition sets the IssuanceDeferred condition on
+// the given Certificate if its namespace is terminating, removing the Issuing
+// condition so that any issuance in progress is abandoned. It returns the
+// updated Certificate and whether its namespace is terminating. Namespaces are
+// only checked if the controller is configured to skip terminating namespaces.
+func (c *controller) updateNamespaceTerminatingCondition(ctx context.Context, crt *cmapi.Certificate) (*cmapi.Certificate, bool, error) {
+	if c.namespaceLister == nil {
+		return crt, false, nil
+	}
+
+	ns, err := c.namespaceLister.Get(crt.Namespace)
+	if apierrors.IsNotFound(err) {
+		return crt, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if ns.Status.Phase != corev1.NamespaceTerminating {
+		return crt, false, nil
+	}
+
+	message := fmt.Sprintf("Issuance is skipped as namespace %q is terminating", crt.Namespace)
+	existing := apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionIssuanceDeferred)
+	if existing != nil && existing.Status == cmmeta.ConditionTrue && existing.Message == message && !certificateIsIssuing(crt) {
+		return crt, true, nil
+	}
+
+	logf.FromContext(ctx).V(logf.InfoLevel).Info("Not issuing certificate as its namespace is terminating")
+
+	crt = crt.DeepCopy()
+	apiutil.RemoveCertificateCondition(crt, cmapi.CertificateConditionIssuing)
+	apiutil.SetCertificateCondition(crt, crt.Generation, cmapi.CertificateConditionIssuanceDeferred, cmmeta.ConditionTrue, reasonNamespaceTerminating, message)
+	_, err = c.client.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, true, err
+	}
+	c.recorder.Event(crt, corev1.EventTypeNormal, reasonNamespaceTerminating, message)
+
+	return crt, true, nil
+}
+
+// issuingInNamespace returns the number of Certificates in the given
+// namespace that are currently being issued. It is only computed if the
+// number of issuances per namespace is limited.
+func (c *controller) issuingInNamespace(namespace string) (int, error) {
+	if c.maxIssuancesPerNamespace <= 0 {
+		return 0, nil
+	}
+
+	crts, err := c.certificateLister.Certificates(namespace).List(labels.Everything())
+	if err != nil {
+		return 0, err
+	}
+
+	issuing := 0
+	for _, crt := range crts {
+		if certificateIsIssuing(crt) {
+			issuing++
+		}
+	}
+
+	return issuing, nil
+}
+
+// enqueueNamespaceLimitedCertificates returns an event handler that, when a
+// Certificate stops being issued, enqueues the Certificates in its namespace
+// whose issuance was deferred by the per-namespace issuance limit.
+func enqueueNamespaceLimitedCertificates(log logr.Logger, queue workqueue.Interface, lister cmlisters.CertificateLister) cache.ResourceEventHandler {
+	enqueue := func(namespace string) {
+		crts, err := lister.Certificates(namespace).List(labels.Everything())
+		if err != nil {
+			log.Error(err, "failed to list certificates", "namespace", namespace)
+			return
+		}
+
+		for _, crt := range crts {
+			cond := apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionIssuanceDeferred)
+			if cond == nil || cond.Status != cmmeta.ConditionTrue || cond.Reason != reasonNamespaceLimit {
+				continue
+			}
+
+			key, err := controllerpkg.KeyFunc(crt)
+			if err != nil {
+				log.Error(err, "error computing key for resource")
+				continue
+			}
+			queue.Add(key)
+		}
+	}
+
+	return cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			oldCrt, ok := old.(*cmapi.Certificate)
+			if !ok {
+				return
+			}
+			newCrt, ok := new.(*cmapi.Certificate)
+			if !ok {
+				return
+			}
+			if certificateIsIssuing(oldCrt) && !certificateIsIssuing(newCrt) {
+				enqueue(newCrt.Namespace)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			crt, ok := obj.(*cmapi.Certificate)
+			if !ok {
+				return
+			}
+			if certificateIsIssuing(crt) {
+				enqueue(crt.Namespace)
+			}
+		},
+	}
+}
+
+// enqueueTerminatingNamespaceCertificates returns an event handler that, when
+// a namespace starts terminating, enqueues the Certificates in it.
+func enqueueTerminatingNamespaceCertificates(log logr.Logger, queue workqueue.Interface, lister cmlisters.CertificateLister) cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			oldNs, ok := old.(*corev1.Namespace)
+			if !ok {
+				return
+			}
+			newNs, ok := new.(*corev1.Namespace)
+			if !ok {
+				return
+			}
+			if oldNs.Status.Phase == corev1.NamespaceTerminating || newNs.Status.Phase != corev1.NamespaceTerminating {
+				return
+			}
+
+			crts, err := lister.Certificates(newNs.Name).List(labels.Everything())
+			if err != nil {
+				log.Error(err, "failed to list certificates", "namespace", newNs.Name)
+				return
+			}
+			for _, crt := range crts {
+				key, err := controllerpkg.KeyFunc(crt)
+				if err != nil {
+					log.Error(err, "error computing key for resource")
+					continue
+				}
+				queue.Add(key)
+			}
+		},
+	}
+}
+
+// certificateIsIssuing returns true if the given Certificate has the Issuing
+// condition.
+func certificateIsIssuing(crt *cmapi.Certificate) bool {
+	return apiutil.CertificateHasCondition(crt, cmapi.CertificateCondition{
+		Type:   cmapi.CertificateConditionIssuing,
+		Status: cmmeta.ConditionTrue,
+	})
+}
+
+// nextScheduledRenewal returns the next time at which the renewal schedule
+// of the given Certificate allows it to be renewed, and true, if a renewal
+// for the given reason must be deferred until then. Renewals are not
+// deferred if the certificate would expire before the next allowed time.
+func nextScheduledRenewal(log logr.Logger, crt *cmapi.Certificate, reason string, now time.Time) (time.Time, bool) {
+	rs := crt.Spec.RenewalSchedule
+	if rs == nil || reason != policies.Renewing {
+		return time.Time{}, false
+	}
+
+	schedule, err := maintenance.ParseSchedule(rs.Days, rs.StartHour, rs.EndHour, rs.TimeZone)
+	if err != nil {
+		// The schedule is validated by the webhook, so this should not
+		// happen. Renew anyway rather than risk the certificate expiring.
+		log.Error(err, "ignoring invalid renewal schedule")
+		return time.Time{}, false
+	}
+
+	next := schedule.Next(now)
+	if !next.After(now) {
+		return time.Time{}, false
+	}
+	if crt.Status.NotAfter != nil && !next.Before(crt.Status.NotAfter.Time) {
+		log.V(logf.InfoLevel).Info("Renewing certificate outside of its renewal schedule as it would expire before the next allowed time", "next_renewal", next, "not_after", crt.Status.NotAfter.Time)
+		return time.Time{}, false
+	}
+
+	return next, true
+}
+
+// shouldAdoptExistingSecret returns true if the Certificate has never been
+// issued, but its Secret already contains a certificate that does not need to
+// be re-issued. This is the case when a Certificate is deleted and re-created
+// with the same spec, such as by a GitOps tool, and the Secret issued for the
+// original Certificate was kept.
+// The Secret is not adopted if a CertificateRequest has already been created
+// for the first revision, as that request would then be mistaken for the one
+// that issued the Secret.
+func shouldAdoptExistingSecret(crt *cmapi.Certificate, input policies.Input) bool {
+	return crt.Status.Revision == nil &&
+		input.Secret != nil &&
+		input.NextRevisionRequest == nil
+}
+
+// adoptExistingSecret records that the certificate stored in the Secret of
+// the given Certificate is its first revision, rather than issuing a new one.
+// The Ready condition is then set by the readiness controller as for any
+// other issued certificate.
+func (c *controller) adoptExistingSecret(ctx context.Context, crt *cmapi.Certificate) (*cmapi.Certificate, error) {
+	logf.FromContext(ctx).V(logf.InfoLevel).Info("Adopting the existing certificate in the Secret as it is up to date", "secret", crt.Spec.SecretName)
+
+	crt = crt.DeepCopy()
+	revision := 1
+	crt.Status.Revision = &revision
+	crt, err := c.client.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	c.recorder.Eventf(crt, corev1.EventTypeNormal, reasonAdopted, "Adopted the existing certificate in Secret %q as it is up to date for this Certificate", crt.Spec.SecretName)
+
+	return crt, nil
+}
+
+// setIssuanceDeferredCondition sets the IssuanceDeferred condition on the
+// given Certificate to record that its issuance has been deferred, for the
+// given reason and with the given message.
+func (c *controller) setIssuanceDeferredCondition(ctx context.Context, crt *cmapi.Certificate, reason, message string) error {
+	existing := apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionIssuanceDeferred)
+	if existing != nil && existing.Status == cmmeta.ConditionTrue && existing.Message == message {
+		return nil
+	}
+
+	crt = crt.DeepCopy()
+	apiutil.SetCertificateCondition(crt, crt.Generation, cmapi.CertificateConditionIssuanceDeferred, cmmeta.ConditionTrue, reason, message)
+	_, err := c.client.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	c.recorder.Event(crt, corev1.EventTypeNormal, reason, message)
+
+	return nil
+}
+
+// removeIssuanceDeferredCondition removes the IssuanceDeferred condition from
+// the given Certificate, if it is set.
+func (c *controller) removeIssuanceDeferredCondition(ctx context.Context, crt *cmapi.Certificate) error {
+	if apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionIssuanceDeferred) == nil {
+		return nil
+	}
+
+	crt = crt.DeepCopy()
+	apiutil.RemoveCertificateCondition(crt, cmapi.CertificateConditionIssuanceDeferred)
+	_, err := c.client.CertmanagerV1().Certificates(crt.Namespace).UpdateStatus(ctx, crt, metav1.UpdateOptions{})
+	return err
+}
+
 // shouldBackoffReissuingOnFailure tells us if we should back-off re-issuing for
 // an hour or not. Notably, it returns no back-off when the certificate doesn't
 // match the "next" certificate (since a mismatch means that this certificate
@@ -275,13 +767,23 @@ func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.Rate
 	// construct a new named logger to be reused throughout the controller
 	log := logf.FromContext(ctx.RootContext, ControllerName)
 
+	renewBefore := ctx.CertificateOptions.RenewBeforeExpiryDuration
+	if renewBefore == 0 {
+		renewBefore = cmapi.DefaultRenewBefore
+	}
 	ctrl, queue, mustSync := NewController(log,
 		ctx.CMClient,
 		ctx.KubeSharedInformerFactory,
 		ctx.SharedInformerFactory,
 		ctx.Recorder,
 		ctx.Clock,
-		policies.NewTriggerPolicyChain(ctx.Clock, cmapi.DefaultRenewBefore).Evaluate,
+		policies.NewTriggerPolicyChain(ctx.Clock, renewBefore, ctx.CertificateOptions.RenewBeforeExpiryPercentage).Evaluate,
+		ctx.CertificateOptions.MaintenanceWindows,
+		ctx.CertificateOptions.PrioritizeByExpiry,
+		ctx.CertificateOptions.MaxIssuancesPerNamespace,
+		ctx.CertificateOptions.TriggerOnSecretAnnotation,
+		ctx.CertificateOptions.AllowedIssuers,
+		ctx.CertificateOptions.SkipTerminatingNamespaces,
 	)
 	c.controller = ctrl
 
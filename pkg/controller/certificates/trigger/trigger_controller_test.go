@@ -24,15 +24,20 @@ import (
 
 	logtest "github.com/go-logr/logr/testing"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	coretesting "k8s.io/client-go/testing"
 	fakeclock "k8s.io/utils/clock/testing"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	internaltest "github.com/jetstack/cert-manager/pkg/controller/certificates/internal/test"
 	"github.com/jetstack/cert-manager/pkg/controller/certificates/trigger/policies"
 	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/pkg/util/issuerallowlist"
+	"github.com/jetstack/cert-manager/pkg/util/maintenance"
 	"github.com/jetstack/cert-manager/test/unit/gen"
 )
 
@@ -40,6 +45,30 @@ func Test_controller_ProcessItem(t *testing.T) {
 	fixedNow := metav1.NewTime(time.Now())
 	fixedClock := fakeclock.NewFakeClock(fixedNow.Time)
 
+	// maintenance windows are configured with a precision of one second
+	windowEnd := fixedNow.Add(2 * time.Hour).UTC().Truncate(time.Second)
+	activeWindow := fmt.Sprintf("%s/%s", fixedNow.Add(-time.Hour).UTC().Format(time.RFC3339), windowEnd.Format(time.RFC3339))
+	pastWindow := fmt.Sprintf("%s/%s", fixedNow.Add(-3*time.Hour).UTC().Format(time.RFC3339), fixedNow.Add(-time.Hour).UTC().Format(time.RFC3339))
+	deferredMessage := fmt.Sprintf("Issuance is deferred until the maintenance window ends at %s: Re-issuance forced by unit test case", windowEnd.Format(time.RFC3339))
+
+	// renewals are only allowed between 09:00 and 17:00 UTC on the day after
+	// fixedNow, so a renewal that is due now is deferred until tomorrow.
+	tomorrow := fixedNow.UTC().AddDate(0, 0, 1)
+	renewalSchedule := cmapi.CertificateRenewalSchedule{
+		Days:      []string{tomorrow.Weekday().String()},
+		StartHour: 9,
+		EndHour:   17,
+	}
+	nextRenewal := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 9, 0, 0, 0, time.UTC)
+	namespaceLimitMessage := "Issuance is deferred as 1 Certificates are already being issued in this namespace: Re-issuance forced by unit test case"
+	notAllowedMessage := `Issuer "selfsigned" of kind "ClusterIssuer" in group "" is not in the list of issuers allowed by the cluster administrator`
+	terminatingMessage := `Issuance is skipped as namespace "testns" is terminating`
+	terminatingNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "testns"},
+		Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating},
+	}
+	renewalDeferredMessage := fmt.Sprintf("Renewal is deferred until %s as allowed by the renewal schedule: Renewing certificate as renewal was scheduled", nextRenewal.Format(time.RFC3339))
+
 	// We don't need to full bundle, just a simple CertificateRequest.
 	createCertificateRequestOrPanic := func(crt *cmapi.Certificate) *cmapi.CertificateRequest {
 		return internaltest.MustCreateCryptoBundle(t, crt, fixedClock).CertificateRequest
@@ -55,6 +84,26 @@ func Test_controller_ProcessItem(t *testing.T) {
 		// passed to ProcessItem instead.
 		existingCertificate *cmapi.Certificate
 
+		// additional Certificate and Secret resources that exist in the
+		// apiserver when the test runs.
+		otherCertificates []*cmapi.Certificate
+		existingSecret    *corev1.Secret
+
+		// maintenanceWindows configured on the controller.
+		maintenanceWindows []string
+
+		// maxIssuancesPerNamespace configured on the controller.
+		maxIssuancesPerNamespace int
+
+		// allowedIssuers configured on the controller.
+		allowedIssuers []string
+
+		// existingNamespace, if set, is the namespace of the Certificate.
+		existingNamespace *corev1.Namespace
+
+		// skipTerminatingNamespaces configured on the controller.
+		skipTerminatingNamespaces bool
+
 		mockDataForCertificateReturn    policies.Input
 		mockDataForCertificateReturnErr error
 		wantDataForCertificateCalled    bool
@@ -74,6 +123,10 @@ func Test_controller_ProcessItem(t *testing.T) {
 		// If empty, an update to the empty set/nil is expected.
 		wantConditions []cmapi.CertificateCondition
 
+		// wantRevision is the expected revision of the Certificate resource if
+		// an Update is made. If zero, the revision is expected to be unchanged.
+		wantRevision int
+
 		// wantErr is the expected error text returned by the controller, if any.
 		wantErr string
 	}{
@@ -130,6 +183,17 @@ func Test_controller_ProcessItem(t *testing.T) {
 				}
 			},
 		},
+		"should do nothing if the Certificate is a template for replicas": {
+			existingCertificate: gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateGeneration(42),
+				gen.SetCertificateReplicas(2),
+			),
+			mockShouldReissue: func(*testing.T) policies.Func {
+				return func(policies.Input) (string, string, bool) {
+					return "ForceTriggered", "Re-issuance forced by unit test case", true
+				}
+			},
+		},
 		"should log error when dataForCertificate errors": {
 			existingCertificate:             gen.Certificate("cert-1", gen.SetCertificateNamespace("testns")),
 			wantDataForCertificateCalled:    true,
@@ -230,6 +294,614 @@ func Test_controller_ProcessItem(t *testing.T) {
 				ObservedGeneration: 42,
 			}},
 		},
+		"should set SecretOwnedByAnother=True and not reissue if an older Certificate uses the same secretName": {
+			existingCertificate: gen.Certificate("cert-2", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateSecretName("secret-1"),
+				gen.SetCertificateGeneration(42),
+				gen.SetCertificateCreationTimestamp(fixedNow),
+			),
+			otherCertificates: []*cmapi.Certificate{
+				gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+					gen.SetCertificateSecretName("secret-1"),
+					gen.SetCertificateCreationTimestamp(metav1.NewTime(fixedNow.Add(-time.Hour))),
+				),
+			},
+			wantEvent: `Warning SecretOwnedByAnother Secret "secret-1" is already used by Certificate "cert-1"`,
+			wantConditions: []cmapi.CertificateCondition{{
+				Type:               "SecretOwnedByAnother",
+				Status:             "True",
+				Reason:             "SecretOwnedByAnother",
+				Message:            `Secret "secret-1" is already used by Certificate "cert-1"`,
+				LastTransitionTime: &fixedNow,
+				ObservedGeneration: 42,
+			}},
+		},
+		"should set SecretOwnedByAnother=True on an older Certificate if the Secret was issued for another Certificate": {
+			existingCertificate: gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateSecretName("secret-1"),
+				gen.SetCertificateGeneration(42),
+				gen.SetCertificateCreationTimestamp(metav1.NewTime(fixedNow.Add(-time.Hour))),
+			),
+			otherCertificates: []*cmapi.Certificate{
+				gen.Certificate("cert-2", gen.SetCertificateNamespace("testns"),
+					gen.SetCertificateSecretName("secret-1"),
+					gen.SetCertificateCreationTimestamp(fixedNow),
+				),
+			},
+			existingSecret: gen.Secret("secret-1", gen.SetSecretNamespace("testns"),
+				gen.SetSecretAnnotations(map[string]string{cmapi.CertificateNameKey: "cert-2"}),
+			),
+			wantEvent: `Warning SecretOwnedByAnother Secret "secret-1" is already used by Certificate "cert-2"`,
+			wantConditions: []cmapi.CertificateCondition{{
+				Type:               "SecretOwnedByAnother",
+				Status:             "True",
+				Reason:             "SecretOwnedByAnother",
+				Message:            `Secret "secret-1" is already used by Certificate "cert-2"`,
+				LastTransitionTime: &fixedNow,
+				ObservedGeneration: 42,
+			}},
+		},
+		"should do nothing if Certificate already has 'SecretOwnedByAnother' condition and is still a duplicate": {
+			existingCertificate: gen.Certificate("cert-2", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateSecretName("secret-1"),
+				gen.SetCertificateCreationTimestamp(fixedNow),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+					Type:    "SecretOwnedByAnother",
+					Status:  "True",
+					Reason:  "SecretOwnedByAnother",
+					Message: `Secret "secret-1" is already used by Certificate "cert-1"`,
+				}),
+			),
+			otherCertificates: []*cmapi.Certificate{
+				gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+					gen.SetCertificateSecretName("secret-1"),
+					gen.SetCertificateCreationTimestamp(metav1.NewTime(fixedNow.Add(-time.Hour))),
+				),
+			},
+		},
+		"should call shouldReissue for the oldest Certificate using a secretName": {
+			existingCertificate: gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateSecretName("secret-1"),
+				gen.SetCertificateCreationTimestamp(metav1.NewTime(fixedNow.Add(-time.Hour))),
+			),
+			otherCertificates: []*cmapi.Certificate{
+				gen.Certificate("cert-2", gen.SetCertificateNamespace("testns"),
+					gen.SetCertificateSecretName("secret-1"),
+					gen.SetCertificateCreationTimestamp(fixedNow),
+				),
+			},
+			wantDataForCertificateCalled: true,
+			mockDataForCertificateReturn: policies.Input{},
+			wantShouldReissueCalled:      true,
+			mockShouldReissue: func(*testing.T) policies.Func {
+				return func(policies.Input) (string, string, bool) {
+					return "", "", false
+				}
+			},
+		},
+		"should remove SecretOwnedByAnother once the Certificate is the only one using its secretName": {
+			existingCertificate: gen.Certificate("cert-2", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateSecretName("secret-1"),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+					Type:    "SecretOwnedByAnother",
+					Status:  "True",
+					Reason:  "SecretOwnedByAnother",
+					Message: `Secret "secret-1" is already used by Certificate "cert-1"`,
+				}),
+			),
+			wantDataForCertificateCalled: true,
+			mockDataForCertificateReturn: policies.Input{},
+			wantShouldReissueCalled:      true,
+			mockShouldReissue: func(*testing.T) policies.Func {
+				return func(policies.Input) (string, string, bool) {
+					return "", "", false
+				}
+			},
+			wantConditions: []cmapi.CertificateCondition{},
+		},
+		"should remove Issuing and set SecretOwnedByAnother=True if an older Certificate uses the same secretName": {
+			existingCertificate: gen.Certificate("cert-2", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateSecretName("secret-1"),
+				gen.SetCertificateGeneration(42),
+				gen.SetCertificateCreationTimestamp(fixedNow),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+					Type:   cmapi.CertificateConditionIssuing,
+					Status: cmmeta.ConditionTrue,
+				}),
+			),
+			otherCertificates: []*cmapi.Certificate{
+				gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+					gen.SetCertificateSecretName("secret-1"),
+					gen.SetCertificateCreationTimestamp(metav1.NewTime(fixedNow.Add(-time.Hour))),
+				),
+			},
+			wantEvent: `Warning SecretOwnedByAnother Secret "secret-1" is already used by Certificate "cert-1"`,
+			wantConditions: []cmapi.CertificateCondition{{
+				Type:               "SecretOwnedByAnother",
+				Status:             "True",
+				Reason:             "SecretOwnedByAnother",
+				Message:            `Secret "secret-1" is already used by Certificate "cert-1"`,
+				LastTransitionTime: &fixedNow,
+				ObservedGeneration: 42,
+			}},
+		},
+		"should set SecretOwnedByAnother=True on a newer Certificate if the Secret was issued for a Certificate that does not use it": {
+			existingCertificate: gen.Certificate("cert-2", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateSecretName("secret-1"),
+				gen.SetCertificateGeneration(42),
+				gen.SetCertificateCreationTimestamp(fixedNow),
+			),
+			otherCertificates: []*cmapi.Certificate{
+				gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+					gen.SetCertificateSecretName("secret-1"),
+					gen.SetCertificateCreationTimestamp(metav1.NewTime(fixedNow.Add(-time.Hour))),
+				),
+			},
+			existingSecret: gen.Secret("secret-1", gen.SetSecretNamespace("testns"),
+				gen.SetSecretAnnotations(map[string]string{cmapi.CertificateNameKey: "cert-3"}),
+			),
+			wantEvent: `Warning SecretOwnedByAnother Secret "secret-1" is already used by Certificate "cert-1"`,
+			wantConditions: []cmapi.CertificateCondition{{
+				Type:               "SecretOwnedByAnother",
+				Status:             "True",
+				Reason:             "SecretOwnedByAnother",
+				Message:            `Secret "secret-1" is already used by Certificate "cert-1"`,
+				LastTransitionTime: &fixedNow,
+				ObservedGeneration: 42,
+			}},
+		},
+		"should call shouldReissue for a newer Certificate if the Secret was issued for it": {
+			existingCertificate: gen.Certificate("cert-2", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateSecretName("secret-1"),
+				gen.SetCertificateCreationTimestamp(fixedNow),
+			),
+			otherCertificates: []*cmapi.Certificate{
+				gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+					gen.SetCertificateSecretName("secret-1"),
+					gen.SetCertificateCreationTimestamp(metav1.NewTime(fixedNow.Add(-time.Hour))),
+				),
+			},
+			existingSecret: gen.Secret("secret-1", gen.SetSecretNamespace("testns"),
+				gen.SetSecretAnnotations(map[string]string{cmapi.CertificateNameKey: "cert-2"}),
+			),
+			wantDataForCertificateCalled: true,
+			mockDataForCertificateReturn: policies.Input{},
+			wantShouldReissueCalled:      true,
+			mockShouldReissue: func(*testing.T) policies.Func {
+				return func(policies.Input) (string, string, bool) {
+					return "", "", false
+				}
+			},
+		},
+		"should set IssuerNotAllowed=True and not reissue if the issuer is not allowed": {
+			existingCertificate: gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "selfsigned", Kind: "ClusterIssuer"}),
+				gen.SetCertificateGeneration(42),
+			),
+			allowedIssuers: []string{"ClusterIssuer/letsencrypt-*"},
+			wantEvent:      `Warning IssuerNotAllowed ` + notAllowedMessage,
+			wantConditions: []cmapi.CertificateCondition{{
+				Type:               "IssuerNotAllowed",
+				Status:             "True",
+				Reason:             "IssuerNotAllowed",
+				Message:            notAllowedMessage,
+				LastTransitionTime: &fixedNow,
+				ObservedGeneration: 42,
+			}},
+		},
+		"should remove the Issuing condition if the issuer is not allowed": {
+			existingCertificate: gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "selfsigned", Kind: "ClusterIssuer"}),
+				gen.SetCertificateGeneration(42),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+					Type:   "Issuing",
+					Status: "True",
+				}),
+			),
+			allowedIssuers: []string{"ClusterIssuer/letsencrypt-*"},
+			wantEvent:      `Warning IssuerNotAllowed ` + notAllowedMessage,
+			wantConditions: []cmapi.CertificateCondition{{
+				Type:               "IssuerNotAllowed",
+				Status:             "True",
+				Reason:             "IssuerNotAllowed",
+				Message:            notAllowedMessage,
+				LastTransitionTime: &fixedNow,
+				ObservedGeneration: 42,
+			}},
+		},
+		"should do nothing if Certificate already has 'IssuerNotAllowed' condition and the issuer is still not allowed": {
+			existingCertificate: gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "selfsigned", Kind: "ClusterIssuer"}),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+					Type:    "IssuerNotAllowed",
+					Status:  "True",
+					Reason:  "IssuerNotAllowed",
+					Message: notAllowedMessage,
+				}),
+			),
+			allowedIssuers: []string{"ClusterIssuer/letsencrypt-*"},
+		},
+		"should remove IssuerNotAllowed once the issuer is allowed": {
+			existingCertificate: gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateSecretName("secret-1"),
+				gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "letsencrypt-prod", Kind: "ClusterIssuer"}),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+					Type:    "IssuerNotAllowed",
+					Status:  "True",
+					Reason:  "IssuerNotAllowed",
+					Message: notAllowedMessage,
+				}),
+			),
+			allowedIssuers:               []string{"ClusterIssuer/letsencrypt-*"},
+			wantDataForCertificateCalled: true,
+			mockDataForCertificateReturn: policies.Input{},
+			wantShouldReissueCalled:      true,
+			mockShouldReissue: func(*testing.T) policies.Func {
+				return func(policies.Input) (string, string, bool) {
+					return "", "", false
+				}
+			},
+			wantConditions: []cmapi.CertificateCondition{},
+		},
+		"should set IssuanceDeferred=True and abandon the issuance if the namespace is terminating": {
+			existingCertificate: gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateGeneration(42),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+					Type:   "Issuing",
+					Status: "True",
+				}),
+			),
+			existingNamespace:         terminatingNamespace,
+			skipTerminatingNamespaces: true,
+			wantEvent:                 "Normal NamespaceTerminating " + terminatingMessage,
+			wantConditions: []cmapi.CertificateCondition{{
+				Type:               "IssuanceDeferred",
+				Status:             "True",
+				Reason:             "NamespaceTerminating",
+				Message:            terminatingMessage,
+				LastTransitionTime: &fixedNow,
+				ObservedGeneration: 42,
+			}},
+		},
+		"should do nothing if IssuanceDeferred is already set for the terminating namespace": {
+			existingCertificate: gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+					Type:    "IssuanceDeferred",
+					Status:  "True",
+					Reason:  "NamespaceTerminating",
+					Message: terminatingMessage,
+				}),
+			),
+			existingNamespace:         terminatingNamespace,
+			skipTerminatingNamespaces: true,
+		},
+		"should set Issuing=True in a terminating namespace if terminating namespaces are not skipped": {
+			existingCertificate: gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateGeneration(42),
+			),
+			existingNamespace:            terminatingNamespace,
+			wantDataForCertificateCalled: true,
+			mockDataForCertificateReturn: policies.Input{},
+			wantShouldReissueCalled:      true,
+			mockShouldReissue: func(*testing.T) policies.Func {
+				return func(policies.Input) (string, string, bool) {
+					return "ForceTriggered", "Re-issuance forced by unit test case", true
+				}
+			},
+			wantEvent: "Normal Issuing Re-issuance forced by unit test case",
+			wantConditions: []cmapi.CertificateCondition{{
+				Type:               "Issuing",
+				Status:             "True",
+				Reason:             "ForceTriggered",
+				Message:            "Re-issuance forced by unit test case",
+				LastTransitionTime: &fixedNow,
+				ObservedGeneration: 42,
+			}},
+		},
+		"should set Issuing=True if terminating namespaces are skipped but the namespace is active": {
+			existingCertificate: gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateGeneration(42),
+			),
+			existingNamespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "testns"},
+				Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+			},
+			skipTerminatingNamespaces:    true,
+			wantDataForCertificateCalled: true,
+			mockDataForCertificateReturn: policies.Input{},
+			wantShouldReissueCalled:      true,
+			mockShouldReissue: func(*testing.T) policies.Func {
+				return func(policies.Input) (string, string, bool) {
+					return "ForceTriggered", "Re-issuance forced by unit test case", true
+				}
+			},
+			wantEvent: "Normal Issuing Re-issuance forced by unit test case",
+			wantConditions: []cmapi.CertificateCondition{{
+				Type:               "Issuing",
+				Status:             "True",
+				Reason:             "ForceTriggered",
+				Message:            "Re-issuance forced by unit test case",
+				LastTransitionTime: &fixedNow,
+				ObservedGeneration: 42,
+			}},
+		},
+		"should set IssuanceDeferred=True and not reissue during a maintenance window": {
+			existingCertificate: gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateGeneration(42),
+			),
+			maintenanceWindows:           []string{pastWindow, activeWindow},
+			wantDataForCertificateCalled: true,
+			mockDataForCertificateReturn: policies.Input{},
+			wantShouldReissueCalled:      true,
+			mockShouldReissue: func(*testing.T) policies.Func {
+				return func(policies.Input) (string, string, bool) {
+					return "ForceTriggered", "Re-issuance forced by unit test case", true
+				}
+			},
+			wantEvent: "Normal MaintenanceWindow " + deferredMessage,
+			wantConditions: []cmapi.CertificateCondition{{
+				Type:               "IssuanceDeferred",
+				Status:             "True",
+				Reason:             "MaintenanceWindow",
+				Message:            deferredMessage,
+				LastTransitionTime: &fixedNow,
+				ObservedGeneration: 42,
+			}},
+		},
+		"should set IssuanceDeferred=True and not reissue if the namespace issuance limit has been reached": {
+			existingCertificate: gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateGeneration(42),
+			),
+			otherCertificates: []*cmapi.Certificate{
+				gen.Certificate("cert-2", gen.SetCertificateNamespace("testns"),
+					gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+						Type:   "Issuing",
+						Status: "True",
+					}),
+				),
+			},
+			maxIssuancesPerNamespace:     1,
+			wantDataForCertificateCalled: true,
+			mockDataForCertificateReturn: policies.Input{},
+			wantShouldReissueCalled:      true,
+			mockShouldReissue: func(*testing.T) policies.Func {
+				return func(policies.Input) (string, string, bool) {
+					return "ForceTriggered", "Re-issuance forced by unit test case", true
+				}
+			},
+			wantEvent: "Normal NamespaceIssuanceLimit " + namespaceLimitMessage,
+			wantConditions: []cmapi.CertificateCondition{{
+				Type:               "IssuanceDeferred",
+				Status:             "True",
+				Reason:             "NamespaceIssuanceLimit",
+				Message:            namespaceLimitMessage,
+				LastTransitionTime: &fixedNow,
+				ObservedGeneration: 42,
+			}},
+		},
+		"should set Issuing=True if the issuance limit has only been reached in another namespace": {
+			existingCertificate: gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateGeneration(42),
+			),
+			otherCertificates: []*cmapi.Certificate{
+				gen.Certificate("cert-2", gen.SetCertificateNamespace("otherns"),
+					gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+						Type:   "Issuing",
+						Status: "True",
+					}),
+				),
+			},
+			maxIssuancesPerNamespace:     1,
+			wantDataForCertificateCalled: true,
+			mockDataForCertificateReturn: policies.Input{},
+			wantShouldReissueCalled:      true,
+			mockShouldReissue: func(*testing.T) policies.Func {
+				return func(policies.Input) (string, string, bool) {
+					return "ForceTriggered", "Re-issuance forced by unit test case", true
+				}
+			},
+			wantEvent: "Normal Issuing Re-issuance forced by unit test case",
+			wantConditions: []cmapi.CertificateCondition{{
+				Type:               "Issuing",
+				Status:             "True",
+				Reason:             "ForceTriggered",
+				Message:            "Re-issuance forced by unit test case",
+				LastTransitionTime: &fixedNow,
+				ObservedGeneration: 42,
+			}},
+		},
+		"should do nothing if IssuanceDeferred is already set for the current maintenance window": {
+			existingCertificate: gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+					Type:    "IssuanceDeferred",
+					Status:  "True",
+					Reason:  "MaintenanceWindow",
+					Message: deferredMessage,
+				}),
+			),
+			maintenanceWindows:           []string{activeWindow},
+			wantDataForCertificateCalled: true,
+			mockDataForCertificateReturn: policies.Input{},
+			wantShouldReissueCalled:      true,
+			mockShouldReissue: func(*testing.T) policies.Func {
+				return func(policies.Input) (string, string, bool) {
+					return "ForceTriggered", "Re-issuance forced by unit test case", true
+				}
+			},
+		},
+		"should set Issuing=True and remove IssuanceDeferred once the maintenance window has ended": {
+			existingCertificate: gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateGeneration(42),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+					Type:    "IssuanceDeferred",
+					Status:  "True",
+					Reason:  "MaintenanceWindow",
+					Message: deferredMessage,
+				}),
+			),
+			maintenanceWindows:           []string{pastWindow},
+			wantDataForCertificateCalled: true,
+			mockDataForCertificateReturn: policies.Input{},
+			wantShouldReissueCalled:      true,
+			mockShouldReissue: func(*testing.T) policies.Func {
+				return func(policies.Input) (string, string, bool) {
+					return "ForceTriggered", "Re-issuance forced by unit test case", true
+				}
+			},
+			wantEvent: "Normal Issuing Re-issuance forced by unit test case",
+			wantConditions: []cmapi.CertificateCondition{{
+				Type:               "Issuing",
+				Status:             "True",
+				Reason:             "ForceTriggered",
+				Message:            "Re-issuance forced by unit test case",
+				LastTransitionTime: &fixedNow,
+				ObservedGeneration: 42,
+			}},
+		},
+		"should set IssuanceDeferred=True when a renewal is due outside of the renewal schedule": {
+			existingCertificate: gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateGeneration(42),
+				gen.SetCertificateRenewalSchedule(renewalSchedule),
+				gen.SetCertificateNotAfter(metav1.NewTime(fixedNow.Add(30*24*time.Hour))),
+			),
+			wantDataForCertificateCalled: true,
+			mockDataForCertificateReturn: policies.Input{},
+			wantShouldReissueCalled:      true,
+			mockShouldReissue: func(*testing.T) policies.Func {
+				return func(policies.Input) (string, string, bool) {
+					return policies.Renewing, "Renewing certificate as renewal was scheduled", true
+				}
+			},
+			wantEvent: "Normal RenewalSchedule " + renewalDeferredMessage,
+			wantConditions: []cmapi.CertificateCondition{{
+				Type:               "IssuanceDeferred",
+				Status:             "True",
+				Reason:             "RenewalSchedule",
+				Message:            renewalDeferredMessage,
+				LastTransitionTime: &fixedNow,
+				ObservedGeneration: 42,
+			}},
+		},
+		"should renew outside of the renewal schedule if the certificate would expire before the next allowed time": {
+			existingCertificate: gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateGeneration(42),
+				gen.SetCertificateRenewalSchedule(renewalSchedule),
+				gen.SetCertificateNotAfter(metav1.NewTime(fixedNow.Add(time.Hour))),
+			),
+			wantDataForCertificateCalled: true,
+			mockDataForCertificateReturn: policies.Input{},
+			wantShouldReissueCalled:      true,
+			mockShouldReissue: func(*testing.T) policies.Func {
+				return func(policies.Input) (string, string, bool) {
+					return policies.Renewing, "Renewing certificate as renewal was scheduled", true
+				}
+			},
+			wantEvent: "Normal Issuing Renewing certificate as renewal was scheduled",
+			wantConditions: []cmapi.CertificateCondition{{
+				Type:               "Issuing",
+				Status:             "True",
+				Reason:             "Renewing",
+				Message:            "Renewing certificate as renewal was scheduled",
+				LastTransitionTime: &fixedNow,
+				ObservedGeneration: 42,
+			}},
+		},
+		"should not defer re-issuance for reasons other than renewal outside of the renewal schedule": {
+			existingCertificate: gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateGeneration(42),
+				gen.SetCertificateRenewalSchedule(renewalSchedule),
+				gen.SetCertificateNotAfter(metav1.NewTime(fixedNow.Add(30*24*time.Hour))),
+			),
+			wantDataForCertificateCalled: true,
+			mockDataForCertificateReturn: policies.Input{},
+			wantShouldReissueCalled:      true,
+			mockShouldReissue: func(*testing.T) policies.Func {
+				return func(policies.Input) (string, string, bool) {
+					return "ForceTriggered", "Re-issuance forced by unit test case", true
+				}
+			},
+			wantEvent: "Normal Issuing Re-issuance forced by unit test case",
+			wantConditions: []cmapi.CertificateCondition{{
+				Type:               "Issuing",
+				Status:             "True",
+				Reason:             "ForceTriggered",
+				Message:            "Re-issuance forced by unit test case",
+				LastTransitionTime: &fixedNow,
+				ObservedGeneration: 42,
+			}},
+		},
+		"should remove IssuanceDeferred if re-issuance is no longer required": {
+			existingCertificate: gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+					Type:    "IssuanceDeferred",
+					Status:  "True",
+					Reason:  "MaintenanceWindow",
+					Message: deferredMessage,
+				}),
+			),
+			maintenanceWindows:           []string{activeWindow},
+			wantDataForCertificateCalled: true,
+			mockDataForCertificateReturn: policies.Input{},
+			wantShouldReissueCalled:      true,
+			mockShouldReissue: func(*testing.T) policies.Func {
+				return func(policies.Input) (string, string, bool) {
+					return "", "", false
+				}
+			},
+			wantConditions: []cmapi.CertificateCondition{},
+		},
+		"should adopt an existing Secret that is up to date for a Certificate that has not been issued": {
+			existingCertificate: gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateSecretName("secret-1"),
+			),
+			wantDataForCertificateCalled: true,
+			mockDataForCertificateReturn: policies.Input{
+				Secret: gen.Secret("secret-1", gen.SetSecretNamespace("testns")),
+			},
+			wantShouldReissueCalled: true,
+			mockShouldReissue: func(*testing.T) policies.Func {
+				return func(policies.Input) (string, string, bool) {
+					return "", "", false
+				}
+			},
+			wantEvent:      `Normal Adopted Adopted the existing certificate in Secret "secret-1" as it is up to date for this Certificate`,
+			wantConditions: []cmapi.CertificateCondition{},
+			wantRevision:   1,
+		},
+		"should not adopt an existing Secret if the Certificate has already been issued": {
+			existingCertificate: gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateSecretName("secret-1"),
+				gen.SetCertificateRevision(1),
+			),
+			wantDataForCertificateCalled: true,
+			mockDataForCertificateReturn: policies.Input{
+				Secret: gen.Secret("secret-1", gen.SetSecretNamespace("testns")),
+			},
+			wantShouldReissueCalled: true,
+			mockShouldReissue: func(*testing.T) policies.Func {
+				return func(policies.Input) (string, string, bool) {
+					return "", "", false
+				}
+			},
+		},
+		"should not adopt an existing Secret if a CertificateRequest has already been created for the first revision": {
+			existingCertificate: gen.Certificate("cert-1", gen.SetCertificateNamespace("testns"),
+				gen.SetCertificateSecretName("secret-1"),
+			),
+			wantDataForCertificateCalled: true,
+			mockDataForCertificateReturn: policies.Input{
+				Secret: gen.Secret("secret-1", gen.SetSecretNamespace("testns")),
+				NextRevisionRequest: gen.CertificateRequest("cr-1", gen.SetCertificateRequestNamespace("testns"),
+					gen.SetCertificateRequestAnnotations(map[string]string{"cert-manager.io/certificate-revision": "1"}),
+				),
+			},
+			wantShouldReissueCalled: true,
+			mockShouldReissue: func(*testing.T) policies.Func {
+				return func(policies.Input) (string, string, bool) {
+					return "", "", false
+				}
+			},
+		},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -240,7 +912,17 @@ func Test_controller_ProcessItem(t *testing.T) {
 			if test.existingCertificate != nil {
 				builder.CertManagerObjects = append(builder.CertManagerObjects, test.existingCertificate)
 			}
+			for _, crt := range test.otherCertificates {
+				builder.CertManagerObjects = append(builder.CertManagerObjects, crt)
+			}
+			if test.existingSecret != nil {
+				builder.KubeObjects = append(builder.KubeObjects, test.existingSecret)
+			}
+			if test.existingNamespace != nil {
+				builder.KubeObjects = append(builder.KubeObjects, test.existingNamespace)
+			}
 			builder.Init()
+			builder.Context.CertificateOptions.SkipTerminatingNamespaces = test.skipTerminatingNamespaces
 
 			w := &controllerWrapper{}
 			_, _, err := w.Register(builder.Context)
@@ -248,6 +930,16 @@ func Test_controller_ProcessItem(t *testing.T) {
 				t.Fatal(err)
 			}
 
+			w.maintenanceWindows, err = maintenance.ParseWindows(test.maintenanceWindows)
+			if err != nil {
+				t.Fatal(err)
+			}
+			w.maxIssuancesPerNamespace = test.maxIssuancesPerNamespace
+			w.allowedIssuers, err = issuerallowlist.ParseAllowlist(test.allowedIssuers)
+			if err != nil {
+				t.Fatal(err)
+			}
+
 			gotShouldReissueCalled := false
 			w.shouldReissue = func(i policies.Input) (string, string, bool) {
 				gotShouldReissueCalled = true
@@ -275,6 +967,12 @@ func Test_controller_ProcessItem(t *testing.T) {
 				}
 				expectedCert := test.existingCertificate.DeepCopy()
 				expectedCert.Status.Conditions = test.wantConditions
+				if len(test.wantConditions) == 0 {
+					expectedCert.Status.Conditions = nil
+				}
+				if test.wantRevision != 0 {
+					expectedCert.Status.Revision = &test.wantRevision
+				}
 				builder.ExpectedActions = append(builder.ExpectedActions,
 					testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
 						cmapi.SchemeGroupVersion.WithResource("certificates"),
@@ -319,6 +1017,76 @@ func Test_controller_ProcessItem(t *testing.T) {
 	}
 }
 
+// Test_controller_ProcessItem_adoptExistingSecret checks that a re-created
+// Certificate adopts the Secret issued for the original Certificate, using
+// the real policy chain rather than a mock, and that no issuance is
+// triggered.
+func Test_controller_ProcessItem_adoptExistingSecret(t *testing.T) {
+	fixedClock := fakeclock.NewFakeClock(time.Now())
+
+	crt := gen.Certificate("cert-1",
+		gen.SetCertificateNamespace("testns"),
+		gen.SetCertificateSecretName("secret-1"),
+		gen.SetCertificateDNSNames("example.com"),
+		gen.SetCertificateIssuer(cmmeta.ObjectReference{Name: "ca-issuer", Kind: "Issuer"}),
+	)
+	bundle := internaltest.MustCreateCryptoBundle(t, crt, fixedClock)
+	secret := gen.Secret("secret-1",
+		gen.SetSecretNamespace("testns"),
+		gen.SetSecretAnnotations(map[string]string{
+			cmapi.CertificateNameKey:       "cert-1",
+			cmapi.IssuerNameAnnotationKey:  "ca-issuer",
+			cmapi.IssuerKindAnnotationKey:  "Issuer",
+			cmapi.IssuerGroupAnnotationKey: "",
+		}),
+		gen.SetSecretData(map[string][]byte{
+			corev1.TLSPrivateKeyKey: bundle.PrivateKeyBytes,
+			corev1.TLSCertKey:       bundle.CertBytes,
+		}),
+	)
+
+	builder := &testpkg.Builder{
+		T:                  t,
+		Clock:              fixedClock,
+		CertManagerObjects: []runtime.Object{crt},
+		KubeObjects:        []runtime.Object{secret},
+	}
+	builder.Init()
+
+	w := &controllerWrapper{}
+	if _, _, err := w.Register(builder.Context); err != nil {
+		t.Fatal(err)
+	}
+
+	// The Certificate must only be updated to record the Secret as its first
+	// revision. A re-issuance would instead set the Issuing condition.
+	expectedCrt := crt.DeepCopy()
+	revision := 1
+	expectedCrt.Status.Revision = &revision
+	builder.ExpectedActions = []testpkg.Action{
+		testpkg.NewAction(coretesting.NewUpdateSubresourceAction(
+			cmapi.SchemeGroupVersion.WithResource("certificates"),
+			"status",
+			"testns",
+			expectedCrt,
+		)),
+	}
+	builder.ExpectedEvents = []string{`Normal Adopted Adopted the existing certificate in Secret "secret-1" as it is up to date for this Certificate`}
+
+	builder.Start()
+	defer builder.Stop()
+
+	key, err := controllerpkg.KeyFunc(crt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.controller.ProcessItem(context.Background(), key); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	builder.CheckAndFinish()
+}
+
 func Test_shouldBackoffReissuingOnFailure(t *testing.T) {
 	clock := fakeclock.NewFakeClock(time.Date(2020, 11, 20, 16, 05, 00, 0000, time.Local))
 
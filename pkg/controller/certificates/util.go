@@ -113,7 +113,7 @@ func RequestMatchesSpec(req *cmapi.CertificateRequest, spec cmapi.CertificateSpe
 	if x509req.Subject.CommonName != spec.CommonName {
 		violations = append(violations, "spec.commonName")
 	}
-	if !util.EqualUnsorted(x509req.DNSNames, spec.DNSNames) {
+	if !util.EqualUnsorted(x509req.DNSNames, pki.DNSNamesForCertificateSpec(spec)) {
 		violations = append(violations, "spec.dnsNames")
 	}
 	if !util.EqualUnsorted(pki.IPAddressesToString(x509req.IPAddresses), spec.IPAddresses) {
@@ -162,10 +162,38 @@ func RequestMatchesSpec(req *cmapi.CertificateRequest, spec cmapi.CertificateSpe
 	if !reflect.DeepEqual(spec.IssuerRef, req.Spec.IssuerRef) {
 		violations = append(violations, "spec.issuerRef")
 	}
+	policies, err := pki.CertificatePoliciesFromExtensions(x509req.Extensions)
+	if err != nil {
+		return nil, err
+	}
+	if !certificatePoliciesEqualUnsorted(policies, spec.CertificatePolicies) {
+		violations = append(violations, "spec.certificatePolicies")
+	}
 
 	return violations, nil
 }
 
+// certificatePoliciesEqualUnsorted returns true if a and b contain the same
+// certificate policies, regardless of their order.
+func certificatePoliciesEqualUnsorted(a, b []cmapi.CertificatePolicy) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, p := range a {
+		found := false
+		for _, q := range b {
+			if p == q {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
 // SecretDataAltNamesMatchSpec will compare a Secret resource containing certificate
 // data to a CertificateSpec and return a list of 'violations' for any fields that
 // do not match their counterparts.
@@ -226,8 +254,9 @@ const staticTemporarySerialNumber = "1234567890"
 // predictable serial number and weak MD5 hashing algorithms.
 // In practice, this shouldn't really be a concern anyway.
 func GenerateLocallySignedTemporaryCertificate(crt *cmapi.Certificate, pkData []byte) ([]byte, error) {
-	// generate a throwaway self-signed root CA
-	caPk, err := pki.GenerateECPrivateKey(pki.ECCurve521)
+	// generate a throwaway self-signed root CA, using a curve that is also
+	// permitted when FIPS mode is enabled
+	caPk, err := pki.GenerateECPrivateKey(pki.ECCurve384)
 	if err != nil {
 		return nil, err
 	}
@@ -270,9 +299,9 @@ type RenewalTimeFunc func(time.Time, time.Time, *cmapi.Certificate) *metav1.Time
 
 // RenewalTimeWrapper returns RenewalTimeFunc implementation
 // TODO: potentially merge RenewBeforeExpiryDuration into this function and rewrite the tests accordingly
-func RenewalTimeWrapper(defaultRenewBeforeExpiryDuration time.Duration) RenewalTimeFunc {
+func RenewalTimeWrapper(defaultRenewBeforeExpiryDuration time.Duration, defaultRenewBeforeExpiryPercentage int) RenewalTimeFunc {
 	return func(notBefore, notAfter time.Time, cert *cmapi.Certificate) *metav1.Time {
-		renewBefore := RenewBeforeExpiryDuration(notBefore, notAfter, cert.Spec.RenewBefore, defaultRenewBeforeExpiryDuration)
+		renewBefore := RenewBeforeExpiryDuration(notBefore, notAfter, cert.Spec.RenewBefore, defaultRenewBeforeExpiryDuration, defaultRenewBeforeExpiryPercentage)
 		rt := metav1.NewTime(notAfter.Add(-1 * renewBefore))
 		return &rt
 	}
@@ -281,12 +310,21 @@ func RenewalTimeWrapper(defaultRenewBeforeExpiryDuration time.Duration) RenewalT
 
 // RenewBeforeExpiryDuration will return the amount of time before the given
 // NotAfter time that the certificate should be renewed.
-func RenewBeforeExpiryDuration(notBefore, notAfter time.Time, specRenewBefore *metav1.Duration, defaultRenewBeforeExpiryDuration time.Duration) time.Duration {
+// The renewBefore requested in the Certificate spec is used if set. Otherwise,
+// if defaultRenewBeforeExpiryPercentage is non-zero, the certificate is
+// renewed when that percentage of its lifetime remains, or else
+// defaultRenewBeforeExpiryDuration before it expires. If the certificate is
+// not valid for longer than the requested or default duration, it is renewed
+// when a third of its lifetime remains.
+func RenewBeforeExpiryDuration(notBefore, notAfter time.Time, specRenewBefore *metav1.Duration, defaultRenewBeforeExpiryDuration time.Duration, defaultRenewBeforeExpiryPercentage int) time.Duration {
+	actualDuration := notAfter.Sub(notBefore)
 	renewBefore := defaultRenewBeforeExpiryDuration
-	if specRenewBefore != nil {
+	switch {
+	case specRenewBefore != nil:
 		renewBefore = specRenewBefore.Duration
+	case defaultRenewBeforeExpiryPercentage > 0:
+		return actualDuration / 100 * time.Duration(defaultRenewBeforeExpiryPercentage)
 	}
-	actualDuration := notAfter.Sub(notBefore)
 	if renewBefore >= actualDuration {
 		renewBefore = actualDuration / 3
 	}
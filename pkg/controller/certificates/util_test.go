@@ -288,7 +288,9 @@ func TestRenewBeforeExpiryDuration(t *testing.T) {
 		notAfter                         time.Time
 		specRenewBefore                  *metav1.Duration
 		defaultRenewBeforeExpiryDuration time.Duration
-		expected                         time.Duration
+		// percentage of the certificate lifetime to renew before expiry
+		defaultRenewBeforeExpiryPercentage int
+		expected                           time.Duration
 	}
 	now := time.Now()
 	tests := map[string]testCase{
@@ -334,6 +336,37 @@ func TestRenewBeforeExpiryDuration(t *testing.T) {
 			defaultRenewBeforeExpiryDuration: time.Hour,
 			expected:                         time.Hour * 8,
 		},
+		"30 day spec with a 7 day issued certificate": {
+			notBefore:                        now,
+			notAfter:                         now.Add(time.Hour * 24 * 7),
+			specRenewBefore:                  &metav1.Duration{Duration: time.Hour * 24 * 30},
+			defaultRenewBeforeExpiryDuration: time.Hour * 24 * 30,
+			expected:                         time.Hour * 56,
+		},
+		"percentage overrides default": {
+			notBefore:                          now,
+			notAfter:                           now.Add(time.Hour * 24),
+			specRenewBefore:                    nil,
+			defaultRenewBeforeExpiryDuration:   time.Hour,
+			defaultRenewBeforeExpiryPercentage: 25,
+			expected:                           time.Hour * 6,
+		},
+		"percentage of a short lived certificate": {
+			notBefore:                          now,
+			notAfter:                           now.Add(time.Minute * 90),
+			specRenewBefore:                    nil,
+			defaultRenewBeforeExpiryDuration:   time.Hour * 24 * 30,
+			defaultRenewBeforeExpiryPercentage: 33,
+			expected:                           time.Second * 54 * 33,
+		},
+		"spec overrides percentage": {
+			notBefore:                          now,
+			notAfter:                           now.Add(time.Hour * 24),
+			specRenewBefore:                    &metav1.Duration{Duration: time.Hour * 2},
+			defaultRenewBeforeExpiryDuration:   time.Hour,
+			defaultRenewBeforeExpiryPercentage: 25,
+			expected:                           time.Hour * 2,
+		},
 	}
 
 	for name, tc := range tests {
@@ -341,7 +374,7 @@ func TestRenewBeforeExpiryDuration(t *testing.T) {
 			assert.Equal(
 				t,
 				tc.expected,
-				RenewBeforeExpiryDuration(tc.notBefore, tc.notAfter, tc.specRenewBefore, tc.defaultRenewBeforeExpiryDuration),
+				RenewBeforeExpiryDuration(tc.notBefore, tc.notAfter, tc.specRenewBefore, tc.defaultRenewBeforeExpiryDuration, tc.defaultRenewBeforeExpiryPercentage),
 			)
 		})
 	}
@@ -0,0 +1,368 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificatesigningrequests
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	certificateslisters "k8s.io/client-go/listers/certificates/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	cminformers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+const (
+	ControllerName = "certificatesigningrequests"
+
+	// Domains of the signerNames of CertificateSigningRequests that are
+	// signed by cert-manager. The signerName of a CertificateSigningRequest
+	// to be signed by an Issuer is of the form
+	// `issuers.cert-manager.io/<namespace>.<name>`, and by a ClusterIssuer
+	// `clusterissuers.cert-manager.io/<name>`.
+	SignerIssuerDomain        = "issuers.cert-manager.io"
+	SignerClusterIssuerDomain = "clusterissuers.cert-manager.io"
+)
+
+// This controller signs Kubernetes CertificateSigningRequests whose
+// signerName names a cert-manager Issuer or ClusterIssuer. Once a
+// CertificateSigningRequest has been approved, a CertificateRequest for the
+// same CSR is created referencing the issuer. The CertificateRequest is then
+// approved and signed like any other, and the resulting certificate is
+// stored in the status of the CertificateSigningRequest.
+type controller struct {
+	csrLister                certificateslisters.CertificateSigningRequestLister
+	certificateRequestLister cmlisters.CertificateRequestLister
+	kubeClient               kubernetes.Interface
+	cmClient                 cmclient.Interface
+	recorder                 record.EventRecorder
+	clock                    clock.Clock
+	queue                    workqueue.RateLimitingInterface
+
+	// clusterResourceNamespace is the namespace CertificateRequests for
+	// ClusterIssuers are created in.
+	clusterResourceNamespace string
+}
+
+func NewController(
+	log logr.Logger,
+	kubeClient kubernetes.Interface,
+	cmClient cmclient.Interface,
+	factory informers.SharedInformerFactory,
+	cmFactory cminformers.SharedInformerFactory,
+	recorder record.EventRecorder,
+	clock clock.Clock,
+	clusterResourceNamespace string,
+) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
+	// create a queue used to queue up items to be processed
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Minute*5), ControllerName)
+
+	// obtain references to all the informers used by this controller
+	csrInformer := factory.Certificates().V1().CertificateSigningRequests()
+	certificateRequestInformer := cmFactory.Certmanager().V1().CertificateRequests()
+
+	csrInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: queue})
+	// When a CertificateRequest resource changes, enqueue the CertificateSigningRequest it was created for.
+	certificateRequestInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{
+		WorkFunc: func(obj interface{}) {
+			cr, ok := obj.(*cmapi.CertificateRequest)
+			if !ok {
+				log.Error(nil, "object was not a CertificateRequest object")
+				return
+			}
+			if name, ok := cr.Annotations[cmapi.CertificateRequestCSRNameAnnotationKey]; ok {
+				queue.Add(name)
+			}
+		},
+	})
+
+	// build a list of InformerSynced functions that will be returned by the Register method.
+	// the controller will only begin processing items once all of these informers have synced.
+	mustSync := []cache.InformerSynced{
+		csrInformer.Informer().HasSynced,
+		certificateRequestInformer.Informer().HasSynced,
+	}
+
+	return &controller{
+		csrLister:                csrInformer.Lister(),
+		certificateRequestLister: certificateRequestInformer.Lister(),
+		kubeClient:               kubeClient,
+		cmClient:                 cmClient,
+		recorder:                 recorder,
+		clock:                    clock,
+		queue:                    queue,
+		clusterResourceNamespace: clusterResourceNamespace,
+	}, queue, mustSync
+}
+
+func (c *controller) ProcessItem(ctx context.Context, key string) error {
+	log := logf.FromContext(ctx).WithValues("key", key)
+
+	ctx = logf.NewContext(ctx, log)
+	_, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		log.Error(err, "invalid resource key passed to ProcessItem")
+		return nil
+	}
+
+	csr, err := c.csrLister.Get(name)
+	if apierrors.IsNotFound(err) {
+		log.V(logf.DebugLevel).Info("certificatesigningrequest not found for key")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	issuerRef, ok := issuerRefForSignerName(csr.Spec.SignerName)
+	if !ok {
+		// not a CertificateSigningRequest for cert-manager to sign
+		return nil
+	}
+
+	log = logf.WithResource(log, csr).WithValues("signer_name", csr.Spec.SignerName)
+	ctx = logf.NewContext(ctx, log)
+
+	if len(csr.Status.Certificate) > 0 || csrHasCondition(csr, certificatesv1.CertificateFailed) {
+		log.V(logf.DebugLevel).Info("certificatesigningrequest has already been signed or has failed")
+		return nil
+	}
+	if csrHasCondition(csr, certificatesv1.CertificateDenied) || !csrHasCondition(csr, certificatesv1.CertificateApproved) {
+		log.V(logf.DebugLevel).Info("certificatesigningrequest has not been approved")
+		return nil
+	}
+
+	namespace := issuerRef.namespace
+	if issuerRef.Kind == cmapi.ClusterIssuerKind {
+		namespace = c.clusterResourceNamespace
+	}
+
+	cr, err := c.certificateRequestLister.CertificateRequests(namespace).Get(csr.Name)
+	if apierrors.IsNotFound(err) {
+		return c.createCertificateRequest(ctx, csr, namespace, issuerRef.ObjectReference)
+	}
+	if err != nil {
+		return err
+	}
+
+	log = logf.WithRelatedResource(log, cr)
+	ctx = logf.NewContext(ctx, log)
+
+	if cr.Annotations[cmapi.CertificateRequestCSRNameAnnotationKey] != csr.Name {
+		message := fmt.Sprintf("CertificateRequest %s/%s already exists and was not created for this CertificateSigningRequest", cr.Namespace, cr.Name)
+		return c.failCSR(ctx, csr, "CertificateRequestConflict", message)
+	}
+
+	if apiutil.CertificateRequestHasInvalidRequest(cr) {
+		message := fmt.Sprintf("CertificateRequest %s/%s is invalid: %s", cr.Namespace, cr.Name, apiutil.CertificateRequestInvalidRequestMessage(cr))
+		return c.failCSR(ctx, csr, "InvalidRequest", message)
+	}
+
+	switch apiutil.CertificateRequestReadyReason(cr) {
+	case cmapi.CertificateRequestReasonIssued:
+		if len(cr.Status.Certificate) == 0 {
+			return nil
+		}
+		log.V(logf.InfoLevel).Info("storing signed certificate in certificatesigningrequest")
+		csr = csr.DeepCopy()
+		csr.Status.Certificate = cr.Status.Certificate
+		if _, err := c.kubeClient.CertificatesV1().CertificateSigningRequests().UpdateStatus(ctx, csr, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		c.recorder.Eventf(csr, corev1.EventTypeNormal, "CertificateIssued", "Certificate issued by %s %q", issuerRef.Kind, issuerRef.Name)
+		return nil
+
+	case cmapi.CertificateRequestReasonFailed, cmapi.CertificateRequestReasonDenied:
+		message := fmt.Sprintf("CertificateRequest %s/%s was not signed", cr.Namespace, cr.Name)
+		if cond := apiutil.GetCertificateRequestCondition(cr, cmapi.CertificateRequestConditionReady); cond != nil {
+			message = fmt.Sprintf("%s: %s", message, cond.Message)
+		}
+		return c.failCSR(ctx, csr, "SigningFailed", message)
+	}
+
+	log.V(logf.DebugLevel).Info("waiting for certificaterequest to be signed")
+	return nil
+}
+
+// createCertificateRequest creates a CertificateRequest for the CSR of the
+// given CertificateSigningRequest, to be signed by the referenced issuer.
+func (c *controller) createCertificateRequest(ctx context.Context, csr *certificatesv1.CertificateSigningRequest, namespace string, issuerRef cmmeta.ObjectReference) error {
+	log := logf.FromContext(ctx)
+
+	var usages []cmapi.KeyUsage
+	for _, usage := range csr.Spec.Usages {
+		usages = append(usages, cmapi.KeyUsage(usage))
+	}
+
+	cr := &cmapi.CertificateRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      csr.Name,
+			Namespace: namespace,
+			Annotations: map[string]string{
+				cmapi.CertificateRequestCSRNameAnnotationKey: csr.Name,
+			},
+			// The CertificateRequest is garbage collected along with the
+			// CertificateSigningRequest.
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: certificatesv1.SchemeGroupVersion.String(),
+				Kind:       "CertificateSigningRequest",
+				Name:       csr.Name,
+				UID:        csr.UID,
+			}},
+		},
+		Spec: cmapi.CertificateRequestSpec{
+			Request:   csr.Spec.Request,
+			IssuerRef: issuerRef,
+			Usages:    usages,
+		},
+	}
+
+	cr, err := c.cmClient.CertmanagerV1().CertificateRequests(namespace).Create(ctx, cr, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+
+	log.V(logf.InfoLevel).Info("created certificaterequest for certificatesigningrequest", "certificaterequest", cr.Namespace+"/"+cr.Name)
+	c.recorder.Eventf(csr, corev1.EventTypeNormal, "CertificateRequestCreated", "Created CertificateRequest %s/%s", cr.Namespace, cr.Name)
+
+	return nil
+}
+
+// failCSR adds a Failed condition to the given CertificateSigningRequest.
+func (c *controller) failCSR(ctx context.Context, csr *certificatesv1.CertificateSigningRequest, reason, message string) error {
+	log := logf.FromContext(ctx)
+	log.V(logf.InfoLevel).Info("failing certificatesigningrequest", "reason", reason, "message", message)
+
+	now := metav1.NewTime(c.clock.Now())
+	csr = csr.DeepCopy()
+	csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+		Type:               certificatesv1.CertificateFailed,
+		Status:             corev1.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		LastUpdateTime:     now,
+		LastTransitionTime: now,
+	})
+	if _, err := c.kubeClient.CertificatesV1().CertificateSigningRequests().UpdateStatus(ctx, csr, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+
+	c.recorder.Event(csr, corev1.EventTypeWarning, reason, message)
+
+	return nil
+}
+
+func csrHasCondition(csr *certificatesv1.CertificateSigningRequest, conditionType certificatesv1.RequestConditionType) bool {
+	for _, cond := range csr.Status.Conditions {
+		if cond.Type == conditionType && cond.Status != corev1.ConditionFalse {
+			return true
+		}
+	}
+	return false
+}
+
+// signerIssuerRef is a reference to the issuer named by a signerName.
+type signerIssuerRef struct {
+	cmmeta.ObjectReference
+	// namespace is the namespace of the issuer, if it is an Issuer.
+	namespace string
+}
+
+// issuerRefForSignerName returns a reference to the issuer named by the
+// given signerName, or false if the signerName does not name a cert-manager
+// issuer.
+func issuerRefForSignerName(signerName string) (signerIssuerRef, bool) {
+	parts := strings.SplitN(signerName, "/", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return signerIssuerRef{}, false
+	}
+	domain, name := parts[0], parts[1]
+
+	switch domain {
+	case SignerIssuerDomain:
+		// Namespaces cannot contain dots, so the namespace is everything
+		// before the first dot.
+		nameParts := strings.SplitN(name, ".", 2)
+		if len(nameParts) != 2 || nameParts[0] == "" || nameParts[1] == "" {
+			return signerIssuerRef{}, false
+		}
+		return signerIssuerRef{
+			ObjectReference: cmmeta.ObjectReference{Name: nameParts[1], Kind: cmapi.IssuerKind, Group: certmanager.GroupName},
+			namespace:       nameParts[0],
+		}, true
+
+	case SignerClusterIssuerDomain:
+		return signerIssuerRef{
+			ObjectReference: cmmeta.ObjectReference{Name: name, Kind: cmapi.ClusterIssuerKind, Group: certmanager.GroupName},
+		}, true
+	}
+
+	return signerIssuerRef{}, false
+}
+
+// controllerWrapper wraps the `controller` structure to make it implement
+// the controllerpkg.queueingController interface
+type controllerWrapper struct {
+	*controller
+}
+
+func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
+	// construct a new named logger to be reused throughout the controller
+	log := logf.FromContext(ctx.RootContext, ControllerName)
+
+	ctrl, queue, mustSync := NewController(
+		log,
+		ctx.Client,
+		ctx.CMClient,
+		ctx.KubeSharedInformerFactory,
+		ctx.SharedInformerFactory,
+		ctx.Recorder,
+		ctx.Clock,
+		ctx.IssuerOptions.ClusterResourceNamespace,
+	)
+	c.controller = ctrl
+
+	return queue, mustSync, nil
+}
+
+func init() {
+	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		return controllerpkg.NewBuilder(ctx, ControllerName).
+			For(&controllerWrapper{}).
+			Complete()
+	})
+}
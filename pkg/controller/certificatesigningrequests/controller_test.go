@@ -0,0 +1,286 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificatesigningrequests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coretesting "k8s.io/client-go/testing"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+type csrModifier func(*certificatesv1.CertificateSigningRequest)
+
+func csrFrom(csr *certificatesv1.CertificateSigningRequest, mods ...csrModifier) *certificatesv1.CertificateSigningRequest {
+	csr = csr.DeepCopy()
+	for _, mod := range mods {
+		mod(csr)
+	}
+	return csr
+}
+
+func withCSRCondition(conditionType certificatesv1.RequestConditionType) csrModifier {
+	return func(csr *certificatesv1.CertificateSigningRequest) {
+		csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+			Type:   conditionType,
+			Status: corev1.ConditionTrue,
+		})
+	}
+}
+
+func withFailedCondition(reason, message string, ts time.Time) csrModifier {
+	return func(csr *certificatesv1.CertificateSigningRequest) {
+		csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+			Type:               certificatesv1.CertificateFailed,
+			Status:             corev1.ConditionTrue,
+			Reason:             reason,
+			Message:            message,
+			LastUpdateTime:     metav1.NewTime(ts),
+			LastTransitionTime: metav1.NewTime(ts),
+		})
+	}
+}
+
+func withSignerName(signerName string) csrModifier {
+	return func(csr *certificatesv1.CertificateSigningRequest) {
+		csr.Spec.SignerName = signerName
+	}
+}
+
+func withCSRCertificate(cert []byte) csrModifier {
+	return func(csr *certificatesv1.CertificateSigningRequest) {
+		csr.Status.Certificate = cert
+	}
+}
+
+func TestProcessItem(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+
+	baseCSR := &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-csr", UID: "test-uid"},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Request:    []byte("request"),
+			SignerName: "issuers.cert-manager.io/testns.test-issuer",
+			Usages:     []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageServerAuth},
+		},
+	}
+	approvedCSR := csrFrom(baseCSR, withCSRCondition(certificatesv1.CertificateApproved))
+
+	baseCR := gen.CertificateRequest("test-csr",
+		gen.SetCertificateRequestNamespace("testns"),
+		gen.SetCertificateRequestCSR([]byte("request")),
+		gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{Name: "test-issuer", Kind: "Issuer", Group: "cert-manager.io"}),
+		gen.SetCertificateRequestKeyUsages(cmapi.UsageDigitalSignature, cmapi.UsageServerAuth),
+		gen.SetCertificateRequestAnnotations(map[string]string{cmapi.CertificateRequestCSRNameAnnotationKey: "test-csr"}),
+		gen.AddCertificateRequestOwnerReferences(metav1.OwnerReference{
+			APIVersion: "certificates.k8s.io/v1",
+			Kind:       "CertificateSigningRequest",
+			Name:       "test-csr",
+			UID:        "test-uid",
+		}),
+	)
+
+	csrGVR := certificatesv1.SchemeGroupVersion.WithResource("certificatesigningrequests")
+	crGVR := cmapi.SchemeGroupVersion.WithResource("certificaterequests")
+
+	tests := map[string]struct {
+		csr *certificatesv1.CertificateSigningRequest
+		// existingCR, if set, will exist in the apiserver before the test is run.
+		existingCR *cmapi.CertificateRequest
+
+		expectedActions []testpkg.Action
+		expectedEvents  []string
+	}{
+		"create a CertificateRequest for an approved CSR with an Issuer signerName": {
+			csr: approvedCSR,
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewCreateAction(crGVR, "testns", baseCR)),
+			},
+			expectedEvents: []string{"Normal CertificateRequestCreated Created CertificateRequest testns/test-csr"},
+		},
+		"create a CertificateRequest in the cluster resource namespace for an approved CSR with a ClusterIssuer signerName": {
+			csr: csrFrom(approvedCSR, withSignerName("clusterissuers.cert-manager.io/test-issuer")),
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewCreateAction(crGVR, "cert-manager", gen.CertificateRequestFrom(baseCR,
+					gen.SetCertificateRequestNamespace("cert-manager"),
+					gen.SetCertificateRequestIssuer(cmmeta.ObjectReference{Name: "test-issuer", Kind: "ClusterIssuer", Group: "cert-manager.io"}),
+				))),
+			},
+			expectedEvents: []string{"Normal CertificateRequestCreated Created CertificateRequest cert-manager/test-csr"},
+		},
+		"do nothing for a CSR with a signerName of another signer": {
+			csr: csrFrom(approvedCSR, withSignerName("kubernetes.io/kube-apiserver-client")),
+		},
+		"do nothing for a CSR that has not been approved": {
+			csr: baseCSR,
+		},
+		"do nothing for a CSR that has been denied": {
+			csr: csrFrom(baseCSR, withCSRCondition(certificatesv1.CertificateDenied)),
+		},
+		"do nothing while the CertificateRequest has not been signed": {
+			csr:        approvedCSR,
+			existingCR: baseCR,
+		},
+		"store the signed certificate in the CSR once the CertificateRequest has been signed": {
+			csr: approvedCSR,
+			existingCR: gen.CertificateRequestFrom(baseCR,
+				gen.SetCertificateRequestCertificate([]byte("certificate")),
+				gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+					Type:   cmapi.CertificateRequestConditionReady,
+					Status: cmmeta.ConditionTrue,
+					Reason: cmapi.CertificateRequestReasonIssued,
+				}),
+			),
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewRootUpdateSubresourceAction(csrGVR, "status",
+					csrFrom(approvedCSR, withCSRCertificate([]byte("certificate"))))),
+			},
+			expectedEvents: []string{`Normal CertificateIssued Certificate issued by Issuer "test-issuer"`},
+		},
+		"fail the CSR if the CertificateRequest failed": {
+			csr: approvedCSR,
+			existingCR: gen.CertificateRequestFrom(baseCR,
+				gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+					Type:    cmapi.CertificateRequestConditionReady,
+					Status:  cmmeta.ConditionFalse,
+					Reason:  cmapi.CertificateRequestReasonFailed,
+					Message: "signing failed",
+				}),
+			),
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewRootUpdateSubresourceAction(csrGVR, "status",
+					csrFrom(approvedCSR, withFailedCondition("SigningFailed", "CertificateRequest testns/test-csr was not signed: signing failed", now)))),
+			},
+			expectedEvents: []string{"Warning SigningFailed CertificateRequest testns/test-csr was not signed: signing failed"},
+		},
+		"fail the CSR if a CertificateRequest with the same name was not created for it": {
+			csr:        approvedCSR,
+			existingCR: gen.CertificateRequestFrom(baseCR, gen.DeleteCertificateRequestAnnotation(cmapi.CertificateRequestCSRNameAnnotationKey)),
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewRootUpdateSubresourceAction(csrGVR, "status",
+					csrFrom(approvedCSR, withFailedCondition("CertificateRequestConflict",
+						"CertificateRequest testns/test-csr already exists and was not created for this CertificateSigningRequest", now)))),
+			},
+			expectedEvents: []string{"Warning CertificateRequestConflict CertificateRequest testns/test-csr already exists and was not created for this CertificateSigningRequest"},
+		},
+		"do nothing for a CSR that has already been signed": {
+			csr: csrFrom(approvedCSR, withCSRCertificate([]byte("certificate"))),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Create and initialise a new unit test builder
+			builder := &testpkg.Builder{
+				T:               t,
+				Clock:           fakeclock.NewFakeClock(now),
+				KubeObjects:     []runtime.Object{test.csr},
+				ExpectedActions: test.expectedActions,
+				ExpectedEvents:  test.expectedEvents,
+			}
+			if test.existingCR != nil {
+				builder.CertManagerObjects = append(builder.CertManagerObjects, test.existingCR)
+			}
+			builder.Init()
+			builder.Context.IssuerOptions = controllerpkg.IssuerOptions{ClusterResourceNamespace: "cert-manager"}
+
+			// Register informers used by the controller using the registration wrapper
+			w := &controllerWrapper{}
+			_, _, err := w.Register(builder.Context)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Start the informers and begin processing updates
+			builder.Start()
+			defer builder.Stop()
+
+			key, err := controllerpkg.KeyFunc(test.csr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := w.controller.ProcessItem(context.Background(), key); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if err := builder.AllEventsCalled(); err != nil {
+				builder.T.Error(err)
+			}
+			if err := builder.AllActionsExecuted(); err != nil {
+				builder.T.Error(err)
+			}
+		})
+	}
+}
+
+func TestIssuerRefForSignerName(t *testing.T) {
+	tests := map[string]struct {
+		signerName        string
+		expectedRef       cmmeta.ObjectReference
+		expectedNamespace string
+		expectedOK        bool
+	}{
+		"an Issuer": {
+			signerName:        "issuers.cert-manager.io/testns.test-issuer",
+			expectedRef:       cmmeta.ObjectReference{Name: "test-issuer", Kind: "Issuer", Group: "cert-manager.io"},
+			expectedNamespace: "testns",
+			expectedOK:        true,
+		},
+		"an Issuer with a dot in its name": {
+			signerName:        "issuers.cert-manager.io/testns.test.issuer",
+			expectedRef:       cmmeta.ObjectReference{Name: "test.issuer", Kind: "Issuer", Group: "cert-manager.io"},
+			expectedNamespace: "testns",
+			expectedOK:        true,
+		},
+		"an Issuer without a namespace": {
+			signerName: "issuers.cert-manager.io/test-issuer",
+		},
+		"a ClusterIssuer": {
+			signerName:  "clusterissuers.cert-manager.io/test-issuer",
+			expectedRef: cmmeta.ObjectReference{Name: "test-issuer", Kind: "ClusterIssuer", Group: "cert-manager.io"},
+			expectedOK:  true,
+		},
+		"a ClusterIssuer without a name": {
+			signerName: "clusterissuers.cert-manager.io/",
+		},
+		"another signer": {
+			signerName: "kubernetes.io/kube-apiserver-client",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ref, ok := issuerRefForSignerName(test.signerName)
+			if ok != test.expectedOK {
+				t.Fatalf("unexpected ok, exp=%t got=%t", test.expectedOK, ok)
+			}
+			if ref.ObjectReference != test.expectedRef || ref.namespace != test.expectedNamespace {
+				t.Errorf("unexpected issuer reference, exp=%+v/%q got=%+v/%q", test.expectedRef, test.expectedNamespace, ref.ObjectReference, ref.namespace)
+			}
+		})
+	}
+}
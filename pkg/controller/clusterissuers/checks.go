@@ -33,7 +33,7 @@ func (c *controller) issuersForSecret(secret *corev1.Secret) ([]*v1.ClusterIssue
 
 	var affected []*v1.ClusterIssuer
 	for _, iss := range issuers {
-		if secret.Namespace != c.clusterResourceNamespace {
+		if secret.Namespace != c.issuerOptions.ResourceNamespace(iss) {
 			continue
 		}
 		switch {
@@ -66,6 +66,11 @@ func (c *controller) issuersForSecret(secret *corev1.Secret) ([]*v1.ClusterIssue
 					continue
 				}
 			}
+		case iss.Spec.ADCSWebEnrollment != nil:
+			if iss.Spec.ADCSWebEnrollment.CredentialsRef.Name == secret.Name {
+				affected = append(affected, iss)
+				continue
+			}
 		case iss.Spec.Vault != nil:
 			if iss.Spec.Vault.Auth.TokenSecretRef != nil {
 				if iss.Spec.Vault.Auth.TokenSecretRef.Name == secret.Name {
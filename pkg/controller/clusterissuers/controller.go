@@ -22,6 +22,7 @@ import (
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
@@ -48,6 +49,9 @@ type controller struct {
 	// clientset used to update cert-manager API resources
 	cmClient cmclient.Interface
 
+	// clientset used to check that resource namespaces exist
+	kubeClient kubernetes.Interface
+
 	// used to record Events about resources to the API
 	recorder record.EventRecorder
 
@@ -55,9 +59,10 @@ type controller struct {
 	// for each ClusterIssuer resource
 	issuerFactory issuer.Factory
 
-	// clusterResourceNamespace is the namespace used to store resources
-	// referenced by ClusterIssuer resources, e.g. acme account secrets
-	clusterResourceNamespace string
+	// issuerOptions is used to determine the namespace used to store
+	// resources referenced by ClusterIssuer resources, e.g. acme account
+	// secrets
+	issuerOptions controllerpkg.IssuerOptions
 }
 
 // Register registers and constructs the controller using the provided context.
@@ -91,8 +96,9 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 	// instantiate additional helpers used by this controller
 	c.issuerFactory = issuer.NewFactory(ctx)
 	c.cmClient = ctx.CMClient
+	c.kubeClient = ctx.Client
 	c.recorder = ctx.Recorder
-	c.clusterResourceNamespace = ctx.IssuerOptions.ClusterResourceNamespace
+	c.issuerOptions = ctx.IssuerOptions
 
 	return c.queue, mustSync, nil
 }
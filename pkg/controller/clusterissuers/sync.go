@@ -18,21 +18,27 @@ package clusterissuers
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/errors"
 
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 )
 
 const (
-	errorInitIssuer = "ErrInitIssuer"
+	errorInitIssuer                = "ErrInitIssuer"
+	errorResourceNamespaceNotFound = "ResourceNamespaceNotFound"
 
-	messageErrorInitIssuer = "Error initializing issuer: "
+	messageErrorInitIssuer                = "Error initializing issuer: "
+	messageErrorResourceNamespaceNotFound = "Resource namespace %q named by the %s annotation does not exist"
 )
 
 func (c *controller) Sync(ctx context.Context, iss *cmapi.ClusterIssuer) (err error) {
@@ -49,6 +55,10 @@ func (c *controller) Sync(ctx context.Context, iss *cmapi.ClusterIssuer) (err er
 		}
 	}()
 
+	if err := c.validateResourceNamespace(ctx, issuerCopy); err != nil {
+		return err
+	}
+
 	i, err := c.issuerFactory.IssuerFor(issuerCopy)
 	if err != nil {
 		return err
@@ -65,6 +75,27 @@ func (c *controller) Sync(ctx context.Context, iss *cmapi.ClusterIssuer) (err er
 	return nil
 }
 
+// validateResourceNamespace checks that the resource namespace of the
+// ClusterIssuer exists if it has been overridden using the
+// cert-manager.io/cluster-resource-namespace annotation, and marks the
+// ClusterIssuer as not ready if it does not.
+func (c *controller) validateResourceNamespace(ctx context.Context, iss *cmapi.ClusterIssuer) error {
+	ns := c.issuerOptions.ResourceNamespace(iss)
+	if ns == c.issuerOptions.ClusterResourceNamespace {
+		return nil
+	}
+
+	_, err := c.kubeClient.CoreV1().Namespaces().Get(ctx, ns, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		s := fmt.Sprintf(messageErrorResourceNamespaceNotFound, ns, cmapi.ClusterIssuerResourceNamespaceAnnotationKey)
+		logf.FromContext(ctx).Error(err, "resource namespace of issuer does not exist", "resource_namespace", ns)
+		c.recorder.Event(iss, corev1.EventTypeWarning, errorResourceNamespaceNotFound, s)
+		apiutil.SetIssuerCondition(iss, iss.Generation, cmapi.IssuerConditionReady, cmmeta.ConditionFalse, errorResourceNamespaceNotFound, s)
+	}
+
+	return err
+}
+
 func (c *controller) updateIssuerStatus(ctx context.Context, old, new *cmapi.ClusterIssuer) (*cmapi.ClusterIssuer, error) {
 	if apiequality.Semantic.DeepEqual(old.Status, new.Status) {
 		return nil, nil
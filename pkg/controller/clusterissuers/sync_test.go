@@ -22,13 +22,16 @@ import (
 	"runtime/debug"
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgotesting "k8s.io/client-go/testing"
 
 	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
 	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/test/unit/gen"
 )
 
 func newFakeIssuerWithStatus(name string, status v1.IssuerStatus) *v1.ClusterIssuer {
@@ -44,6 +47,92 @@ func TestSync(t *testing.T) {
 
 }
 
+func TestValidateResourceNamespace(t *testing.T) {
+	withResourceNamespace := func(ns string) gen.IssuerModifier {
+		return gen.AddIssuerAnnotations(map[string]string{v1.ClusterIssuerResourceNamespaceAnnotationKey: ns})
+	}
+	getNamespace := func(ns string) testpkg.Action {
+		return testpkg.NewAction(clientgotesting.NewRootGetAction(corev1.SchemeGroupVersion.WithResource("namespaces"), ns))
+	}
+
+	tests := map[string]struct {
+		issuer                            *v1.ClusterIssuer
+		perClusterIssuerResourceNamespace bool
+
+		expectedActions []testpkg.Action
+		expectedEvents  []string
+		expectedReason  string
+		expectedErr     bool
+	}{
+		"use the cluster resource namespace if the issuer does not name a resource namespace": {
+			issuer:                            gen.ClusterIssuer("test"),
+			perClusterIssuerResourceNamespace: true,
+		},
+		"ignore the resource namespace of the issuer if per-ClusterIssuer resource namespaces are disabled": {
+			issuer: gen.ClusterIssuer("test", withResourceNamespace("does-not-exist")),
+		},
+		"accept a resource namespace of the issuer that exists": {
+			issuer:                            gen.ClusterIssuer("test", withResourceNamespace("team-a")),
+			perClusterIssuerResourceNamespace: true,
+			expectedActions:                   []testpkg.Action{getNamespace("team-a")},
+		},
+		"mark the issuer as not ready if its resource namespace does not exist": {
+			issuer:                            gen.ClusterIssuer("test", withResourceNamespace("does-not-exist")),
+			perClusterIssuerResourceNamespace: true,
+			expectedActions:                   []testpkg.Action{getNamespace("does-not-exist")},
+			expectedEvents: []string{`Warning ResourceNamespaceNotFound Resource namespace "does-not-exist" named by the ` +
+				`cert-manager.io/cluster-resource-namespace annotation does not exist`},
+			expectedReason: errorResourceNamespaceNotFound,
+			expectedErr:    true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			b := &testpkg.Builder{
+				T: t,
+				KubeObjects: []runtime.Object{
+					&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+				},
+				ExpectedActions: test.expectedActions,
+				ExpectedEvents:  test.expectedEvents,
+			}
+			b.Init()
+			defer b.Stop()
+			b.Context.IssuerOptions = controllerpkg.IssuerOptions{
+				ClusterResourceNamespace:          "cert-manager",
+				PerClusterIssuerResourceNamespace: test.perClusterIssuerResourceNamespace,
+			}
+
+			c := &controller{}
+			c.Register(b.Context)
+			b.Start()
+
+			issuer := test.issuer.DeepCopy()
+			err := c.validateResourceNamespace(context.TODO(), issuer)
+			if test.expectedErr != (err != nil) {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expectedErr, err)
+			}
+
+			reason := ""
+			for _, cond := range issuer.Status.Conditions {
+				if cond.Type == v1.IssuerConditionReady {
+					reason = cond.Reason
+				}
+			}
+			if reason != test.expectedReason {
+				t.Errorf("unexpected Ready condition reason, exp=%q got=%q", test.expectedReason, reason)
+			}
+
+			if err := b.AllEventsCalled(); err != nil {
+				t.Error(err)
+			}
+			if err := b.AllActionsExecuted(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
 func TestUpdateIssuerStatus(t *testing.T) {
 	b := &testpkg.Builder{
 		T: t,
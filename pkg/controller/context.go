@@ -20,7 +20,9 @@ import (
 	"context"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -30,7 +32,10 @@ import (
 	"github.com/jetstack/cert-manager/pkg/acme/accounts"
 	clientset "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
 	informers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
+	dnsutil "github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
 	"github.com/jetstack/cert-manager/pkg/metrics"
+	"github.com/jetstack/cert-manager/pkg/util/issuerallowlist"
+	"github.com/jetstack/cert-manager/pkg/util/maintenance"
 )
 
 // Context contains various types that are used by controller implementations.
@@ -59,6 +64,10 @@ type Context struct {
 	// SharedInformerFactory can be used to obtain shared SharedIndexInformer
 	// instances
 	SharedInformerFactory informers.SharedInformerFactory
+	// DynamicSharedInformerFactory can be used to obtain shared
+	// SharedIndexInformer instances for resources that cert-manager has no
+	// typed clients for, such as Gateway API resources
+	DynamicSharedInformerFactory dynamicinformer.DynamicSharedInformerFactory
 
 	// Namespace is the namespace to operate within.
 	// If unset, operates on all namespaces
@@ -71,11 +80,19 @@ type Context struct {
 	// Metrics is used for exposing Prometheus metrics across the controllers
 	Metrics *metrics.Metrics
 
+	// PermanentErrorRequeueDelay is how long controllers wait before
+	// processing a resource again after it failed with a permanent error,
+	// such as a 4xx response or invalid configuration. Transient errors are
+	// retried with a short exponential backoff. If zero, resources are not
+	// processed again after a permanent error until they are next changed.
+	PermanentErrorRequeueDelay time.Duration
+
 	IssuerOptions
 	ACMEOptions
 	IngressShimOptions
 	CertificateOptions
 	SchedulerOptions
+	WebhookBootstrapOptions
 }
 
 type IssuerOptions struct {
@@ -83,6 +100,12 @@ type IssuerOptions struct {
 	// non-namespaced resources (e.g. ClusterIssuer) in.
 	ClusterResourceNamespace string
 
+	// PerClusterIssuerResourceNamespace allows ClusterIssuers to store
+	// their resources in the namespace named by the
+	// cert-manager.io/cluster-resource-namespace annotation instead of
+	// ClusterResourceNamespace.
+	PerClusterIssuerResourceNamespace bool
+
 	// ClusterIssuerAmbientCredentials controls whether a cluster issuer should
 	// pick up ambient credentials, such as those from metadata services, to
 	// construct clients.
@@ -91,6 +114,51 @@ type IssuerOptions struct {
 	// IssuerAmbientCredentials controls whether an issuer should pick up ambient
 	// credentials, such as those from metadata services, to construct clients.
 	IssuerAmbientCredentials bool
+
+	// AmbientCredentialProviders restricts ambient credentials to only be
+	// used by the named providers, even if ClusterIssuerAmbientCredentials or
+	// IssuerAmbientCredentials is set. If empty, all providers may use
+	// ambient credentials.
+	AmbientCredentialProviders []string
+
+	// IssuerBackendCABundle is a PEM encoded CA bundle that, if set, is
+	// trusted exclusively (ignoring the system root CAs) when connecting to
+	// ACME, Vault and Venafi servers.
+	IssuerBackendCABundle []byte
+
+	// SkipIssuedCertificateValidityCheck disables the check that certificates
+	// returned by issuers are currently valid, i.e. that they have not already
+	// expired and that their notBefore time is not far in the future.
+	SkipIssuedCertificateValidityCheck bool
+
+	// EnableIssuanceRecords controls whether an IssuanceRecord is created for
+	// every certificate issued by the CertificateRequest controllers.
+	EnableIssuanceRecords bool
+
+	// IssuanceRecordRetention is the duration IssuanceRecords are kept before
+	// being deleted. If zero, records are never deleted.
+	IssuanceRecordRetention time.Duration
+
+	// SerialNumberBits is the number of random bits in the serial numbers of
+	// certificates signed by the CA and SelfSigned issuers. If zero,
+	// pki.DefaultSerialNumberBits is used.
+	SerialNumberBits int
+
+	// MaxConcurrentSignsPerIssuer is the maximum number of
+	// CertificateRequests that each CertificateRequest controller may be
+	// signing with a single issuer at once, so that a slow issuer cannot
+	// occupy all of the workers. If zero, there is no limit.
+	MaxConcurrentSignsPerIssuer int
+
+	// VenafiRequestsPerSecond is the maximum rate at which the Venafi
+	// CertificateRequest controller makes requests to Venafi TPP and Venafi
+	// Cloud, shared across all Venafi issuers. If zero, there is no limit.
+	VenafiRequestsPerSecond float64
+
+	// VenafiRequestsBurst is the maximum number of requests the Venafi
+	// CertificateRequest controller may make at once before being limited
+	// by VenafiRequestsPerSecond.
+	VenafiRequestsBurst int
 }
 
 type ACMEOptions struct {
@@ -98,6 +166,10 @@ type ACMEOptions struct {
 	// challenges
 	HTTP01SolverImage string
 
+	// HTTP01SolverImagePullSecrets are the names of Secrets, in the namespace
+	// of each HTTP01 solver pod, used to pull the HTTP01 solver image.
+	HTTP01SolverImagePullSecrets []string
+
 	// HTTP01SolverResourceRequestCPU defines the ACME pod's resource request CPU size
 	HTTP01SolverResourceRequestCPU resource.Quantity
 
@@ -110,13 +182,36 @@ type ACMEOptions struct {
 	// HTTP01SolverResourceLimitsMemory defines the ACME pod's resource limits Memory size
 	HTTP01SolverResourceLimitsMemory resource.Quantity
 
+	// HTTP01SolverNodeSelector is the node selector of HTTP01 solver pods
+	HTTP01SolverNodeSelector map[string]string
+
+	// HTTP01SolverTolerations are the tolerations of HTTP01 solver pods
+	HTTP01SolverTolerations []corev1.Toleration
+
+	// HTTP01SolverSharedDeployment configures HTTP01 challenges to be solved
+	// by a single long-lived solver Deployment per namespace, rather than a
+	// solver pod per challenge.
+	HTTP01SolverSharedDeployment bool
+
+	// HTTP01SolverSharedIngress configures HTTP01 challenges to be routed
+	// through a single shared Ingress per namespace and ingress class, with a
+	// path per challenge, rather than an Ingress per challenge.
+	HTTP01SolverSharedIngress bool
+
+	// DeferChallengeCleanup delays the clean up of presented challenge
+	// records until the Order that owns the challenge has reached a final
+	// state, rather than as soon as the challenge itself has. This allows
+	// the records to be reused if finalizing the Order fails and is retried.
+	DeferChallengeCleanup bool
+
 	// DNS01CheckAuthoritative is a flag for controlling if auth nss are used
 	// for checking propagation of an RR. This is the ideal scenario
 	DNS01CheckAuthoritative bool
 
-	// DNS01Nameservers is a list of nameservers to use when performing self-checks
-	// for ACME DNS01 validations.
-	DNS01Nameservers []string
+	// DNS01Nameservers is the list of nameservers to use when performing
+	// self-checks for ACME DNS01 validations. It may be replaced whilst the
+	// controller is running if the nameservers are read from a file.
+	DNS01Nameservers *dnsutil.NameserverList
 
 	// AccountRegistry is used as a cache of ACME accounts between various
 	// components of cert-manager
@@ -124,6 +219,32 @@ type ACMEOptions struct {
 
 	// DNS01CheckRetryPeriod is the time the controller should wait between checking if a ACME dns entry exists.
 	DNS01CheckRetryPeriod time.Duration
+
+	// DNS01CheckConcurrency is the maximum number of DNS01 self checks that
+	// may be run in parallel. If zero, self checks are run synchronously by
+	// the challenges controller's workers.
+	DNS01CheckConcurrency int
+
+	// DNS01ProviderAPIRetries is the number of times a failed DNS01 provider
+	// API call to present or clean up a challenge record is retried before
+	// the error is surfaced on the Challenge.
+	DNS01ProviderAPIRetries int
+
+	// DNS01ProviderAPIRetryBackoff is the initial time to wait before
+	// retrying a failed DNS01 provider API call. It is doubled after each
+	// failed attempt.
+	DNS01ProviderAPIRetryBackoff time.Duration
+
+	// DNS01ProviderBatchWindow is the time to wait for other DNS01 records
+	// in the same zone to be presented or cleaned up, so that they can be
+	// changed by a single API call of providers that support it. Records are
+	// changed individually if it is zero.
+	DNS01ProviderBatchWindow time.Duration
+
+	// DNS01SelfCheckWarnDuration is the duration after which a DNS01 self
+	// check is reported as slow by a Warning event on the Challenge. Slow
+	// self checks are not reported if it is zero.
+	DNS01SelfCheckWarnDuration time.Duration
 }
 
 type IngressShimOptions struct {
@@ -132,16 +253,130 @@ type IngressShimOptions struct {
 	DefaultIssuerKind                 string
 	DefaultIssuerGroup                string
 	DefaultAutoCertificateAnnotations []string
+
+	// DryRun, if true, stops ingress-shim from creating, updating or
+	// deleting Certificates. The changes it would have made are logged and
+	// recorded as events on the Ingress instead.
+	DryRun bool
 }
 
 type CertificateOptions struct {
 	// EnableOwnerRef controls whether the certificate is configured as an owner of
 	// secret where the effective TLS certificate is stored.
 	EnableOwnerRef bool
+
+	// SecretDeletionGracePeriod, if non-zero and EnableOwnerRef is true, is
+	// the duration after a Certificate is deleted that its Secret will be
+	// deleted by cert-manager. A finalizer is used to keep the Certificate
+	// until then, instead of an owner reference on the Secret.
+	SecretDeletionGracePeriod time.Duration
+
+	// ClusterDomain is the DNS domain of the cluster, used to compute the
+	// dnsNames of Certificates that select Services.
+	ClusterDomain string
+
+	// MaintenanceWindows are the periods of time during which the issuance
+	// of Certificates is deferred.
+	MaintenanceWindows maintenance.Windows
+
+	// PrioritizeByExpiry, if true, configures controllers to process the
+	// Certificates that expire soonest first, rather than in the order they
+	// were queued.
+	PrioritizeByExpiry bool
+
+	// MaxIssuancesPerNamespace is the maximum number of Certificates in a
+	// single namespace that may be issued at once. If zero, there is no limit.
+	MaxIssuancesPerNamespace int
+
+	// TriggerOnSecretAnnotation, if set, is the name of an annotation on
+	// Secrets which, when its value changes, causes the Certificates that own
+	// the Secret to be reconciled.
+	TriggerOnSecretAnnotation string
+
+	// MaxInFlightRequests is the maximum number of CertificateRequests
+	// owned by a single Certificate that may be pending at once. A new
+	// CertificateRequest is not created until earlier ones complete. If
+	// zero, there is no limit.
+	MaxInFlightRequests int
+
+	// RenewalHistoryLimit is the maximum number of records kept in the
+	// renewal history of each Certificate. If zero, no history is kept.
+	RenewalHistoryLimit int
+
+	// WarnSecretSize is the total size in bytes of the certificate, CA and
+	// private key data stored in an issued Secret above which a Warning event
+	// is recorded on the Certificate. If zero, no warning is recorded.
+	WarnSecretSize int
+
+	// KeepReadyWithoutIssuer, if true, keeps a Certificate whose issuer does
+	// not exist Ready for as long as its existing certificate remains valid.
+	// The IssuerNotFound condition is set on the Certificate either way.
+	KeepReadyWithoutIssuer bool
+
+	// IssuerNotReadyThreshold is how long the issuer referenced by a
+	// Certificate must have not been Ready before the IssuerNotReady
+	// condition is set on the Certificate. If zero, the condition is never
+	// set.
+	IssuerNotReadyThreshold time.Duration
+
+	// RenewBeforeExpiryDuration is how long before they expire certificates
+	// are renewed if their Certificate does not set spec.renewBefore. If
+	// zero, cmapi.DefaultRenewBefore is used.
+	RenewBeforeExpiryDuration time.Duration
+
+	// RenewBeforeExpiryPercentage, if non-zero, is the percentage of their
+	// lifetime that must remain before certificates are renewed if their
+	// Certificate does not set spec.renewBefore. It takes precedence over
+	// RenewBeforeExpiryDuration.
+	RenewBeforeExpiryPercentage int
+
+	// AdditionalTrustBundle is a PEM encoded bundle of CA certificates that,
+	// if set, is appended to the CA data stored in the `ca.crt` key of every
+	// issued Secret, omitting any certificates already provided by the issuer.
+	AdditionalTrustBundle []byte
+
+	// AllowedIssuers are the issuers that Certificates may reference.
+	// Certificates referencing any other issuer are not issued.
+	AllowedIssuers issuerallowlist.Allowlist
+
+	// SkipTerminatingNamespaces, if true, stops the issuance of Certificates
+	// in namespaces that are being deleted.
+	SkipTerminatingNamespaces bool
+
+	// IssuerReadinessBackoffMax is the maximum delay between checks of
+	// whether the issuer of a Certificate is ready before a
+	// CertificateRequest is created for it. If zero, CertificateRequests are
+	// created regardless of whether the issuer is ready.
+	IssuerReadinessBackoffMax time.Duration
+
+	// DefaultIssuanceTimeout is how long an issuance may remain in progress
+	// before it is marked as failed, for Certificates that do not set
+	// spec.issuanceTimeout. If zero, such issuances never time out.
+	DefaultIssuanceTimeout time.Duration
 }
 
 type SchedulerOptions struct {
 	// MaxConcurrentChallenges determines the maximum number of challenges that can be
 	// scheduled as 'processing' at once.
 	MaxConcurrentChallenges int
+
+	// MaxConcurrentHTTP01Challenges and MaxConcurrentDNS01Challenges determine
+	// the maximum number of challenges of each type that can be scheduled as
+	// 'processing' at once. If zero, only MaxConcurrentChallenges applies.
+	MaxConcurrentHTTP01Challenges int
+	MaxConcurrentDNS01Challenges  int
+}
+
+type WebhookBootstrapOptions struct {
+	// SecretNamespace and SecretName identify the Secret that the serving
+	// certificate of the webhook is stored in by the webhook-bootstrap
+	// controller. If SecretName is empty, the controller does not run.
+	SecretNamespace string
+	SecretName      string
+
+	// DNSNames are the DNS names of the webhook serving certificate.
+	DNSNames []string
+
+	// Duration is the duration of the webhook serving certificate.
+	Duration time.Duration
 }
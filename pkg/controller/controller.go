@@ -23,6 +23,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/util/wait"
 	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
 	"k8s.io/client-go/tools/cache"
@@ -30,6 +31,7 @@ import (
 
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 	"github.com/jetstack/cert-manager/pkg/metrics"
+	"github.com/jetstack/cert-manager/pkg/util/errors"
 )
 
 type runFunc func(context.Context)
@@ -52,15 +54,17 @@ func NewController(
 	mustSync []cache.InformerSynced,
 	runDurationFuncs []runDurationFunc,
 	queue workqueue.RateLimitingInterface,
+	permanentErrorRequeueDelay time.Duration,
 ) Interface {
 	return &controller{
-		ctx:              ctx,
-		name:             name,
-		metrics:          metrics,
-		syncHandler:      syncFunc,
-		mustSync:         mustSync,
-		runDurationFuncs: runDurationFuncs,
-		queue:            queue,
+		ctx:                        ctx,
+		name:                       name,
+		metrics:                    metrics,
+		syncHandler:                syncFunc,
+		mustSync:                   mustSync,
+		runDurationFuncs:           runDurationFuncs,
+		queue:                      queue,
+		permanentErrorRequeueDelay: permanentErrorRequeueDelay,
 	}
 }
 
@@ -91,6 +95,11 @@ type controller struct {
 
 	// metrics is used to expose Prometheus, shared by all controllers
 	metrics *metrics.Metrics
+
+	// permanentErrorRequeueDelay is how long to wait before processing an
+	// item again after it failed with a permanent error. If zero, the item
+	// is not processed again until it is next enqueued.
+	permanentErrorRequeueDelay time.Duration
 }
 
 // Run starts the controller loop
@@ -158,13 +167,7 @@ func (c *controller) worker(ctx context.Context) {
 
 			err := c.syncHandler(ctx, key)
 			if err != nil {
-				if strings.Contains(err.Error(), genericregistry.OptimisticLockErrorMsg) {
-					log.Info("re-queuing item due to optimistic locking on resource", "error", err.Error())
-				} else {
-					log.Error(err, "re-queuing item due to error processing")
-				}
-
-				c.queue.AddRateLimited(obj)
+				c.handleErr(log, obj, err)
 				return
 			}
 			log.V(logf.DebugLevel).Info("finished processing work item")
@@ -173,3 +176,30 @@ func (c *controller) worker(ctx context.Context) {
 	}
 	log.V(logf.DebugLevel).Info("exiting worker loop")
 }
+
+// handleErr re-queues an item that failed to be processed. Transient errors
+// are retried using the queue's rate limiter, whereas permanent errors are
+// retried after permanentErrorRequeueDelay, if at all.
+func (c *controller) handleErr(log logr.Logger, obj interface{}, err error) {
+	if strings.Contains(err.Error(), genericregistry.OptimisticLockErrorMsg) {
+		log.Info("re-queuing item due to optimistic locking on resource", "error", err.Error())
+		c.queue.AddRateLimited(obj)
+		return
+	}
+
+	if errors.Classify(err) == errors.ClassPermanent {
+		// reset the rate limiter so that transient errors after the resource
+		// has been fixed are retried quickly again
+		c.queue.Forget(obj)
+		if c.permanentErrorRequeueDelay == 0 {
+			log.Error(err, "not re-queuing item due to permanent error processing")
+			return
+		}
+		log.Error(err, "re-queuing item due to permanent error processing", "after", c.permanentErrorRequeueDelay)
+		c.queue.AddAfter(obj, c.permanentErrorRequeueDelay)
+		return
+	}
+
+	log.Error(err, "re-queuing item due to error processing")
+	c.queue.AddRateLimited(obj)
+}
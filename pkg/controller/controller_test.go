@@ -0,0 +1,120 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	acmeapi "golang.org/x/crypto/acme"
+	genericregistry "k8s.io/apiserver/pkg/registry/generic/registry"
+	"k8s.io/client-go/util/workqueue"
+
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	cmerrors "github.com/jetstack/cert-manager/pkg/util/errors"
+)
+
+// fakeQueue records how items are re-queued.
+type fakeQueue struct {
+	workqueue.RateLimitingInterface
+
+	rateLimited bool
+	forgotten   bool
+	after       time.Duration
+}
+
+func (q *fakeQueue) AddRateLimited(item interface{}) {
+	q.rateLimited = true
+}
+
+func (q *fakeQueue) AddAfter(item interface{}, duration time.Duration) {
+	q.after = duration
+}
+
+func (q *fakeQueue) Forget(item interface{}) {
+	q.forgotten = true
+}
+
+func TestHandleErr(t *testing.T) {
+	const delay = time.Minute * 10
+
+	tests := map[string]struct {
+		err        error
+		retryDelay time.Duration
+
+		expectRateLimited bool
+		expectForgotten   bool
+		expectAfter       time.Duration
+	}{
+		"a transient error is retried with the rate limiter": {
+			err:               errors.New("connection refused"),
+			retryDelay:        delay,
+			expectRateLimited: true,
+		},
+		"a 5xx error is retried with the rate limiter": {
+			err:               fmt.Errorf("error finalizing order: %w", &acmeapi.Error{StatusCode: http.StatusInternalServerError}),
+			retryDelay:        delay,
+			expectRateLimited: true,
+		},
+		"an optimistic locking error is retried with the rate limiter": {
+			err:               errors.New(genericregistry.OptimisticLockErrorMsg),
+			retryDelay:        delay,
+			expectRateLimited: true,
+		},
+		"a 4xx error is retried after the permanent error delay": {
+			err:             &acmeapi.Error{StatusCode: http.StatusBadRequest},
+			retryDelay:      delay,
+			expectForgotten: true,
+			expectAfter:     delay,
+		},
+		"a permanent error is retried after the permanent error delay": {
+			err:             cmerrors.NewPermanent(errors.New("bad config")),
+			retryDelay:      delay,
+			expectForgotten: true,
+			expectAfter:     delay,
+		},
+		"a permanent error is not retried if the delay is zero": {
+			err:             cmerrors.NewPermanent(errors.New("bad config")),
+			expectForgotten: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			queue := &fakeQueue{}
+			c := &controller{
+				queue:                      queue,
+				permanentErrorRequeueDelay: test.retryDelay,
+			}
+
+			c.handleErr(logf.Log, "testns/test", test.err)
+
+			if queue.rateLimited != test.expectRateLimited {
+				t.Errorf("expected rate limited re-queue %t but got %t", test.expectRateLimited, queue.rateLimited)
+			}
+			if queue.forgotten != test.expectForgotten {
+				t.Errorf("expected item forgotten %t but got %t", test.expectForgotten, queue.forgotten)
+			}
+			if queue.after != test.expectAfter {
+				t.Errorf("expected item re-queued after %v but got %v", test.expectAfter, queue.after)
+			}
+		})
+	}
+}
@@ -21,15 +21,41 @@ import (
 )
 
 // ResourceNamespace returns the Kubernetes namespace where resources
-// created or read by `iss` are located.
+// created or read by `iss` are located. For a ClusterIssuer, this is the
+// namespace named by its cert-manager.io/cluster-resource-namespace
+// annotation if PerClusterIssuerResourceNamespace is set, otherwise
+// ClusterResourceNamespace.
 func (o IssuerOptions) ResourceNamespace(iss cmapi.GenericIssuer) string {
 	ns := iss.GetObjectMeta().Namespace
 	if ns == "" {
 		ns = o.ClusterResourceNamespace
+		if o.PerClusterIssuerResourceNamespace {
+			if override := iss.GetObjectMeta().Annotations[cmapi.ClusterIssuerResourceNamespaceAnnotationKey]; override != "" {
+				ns = override
+			}
+		}
 	}
 	return ns
 }
 
+// Names of the providers that may be configured from ambient credentials,
+// used to restrict which providers can do so with AmbientCredentialProviders.
+const (
+	AmbientCredentialProviderAzureDNS = "azuredns"
+	AmbientCredentialProviderCloudDNS = "clouddns"
+	AmbientCredentialProviderRoute53  = "route53"
+	AmbientCredentialProviderWebhook  = "webhook"
+)
+
+// KnownAmbientCredentialProviders is the list of all providers that may be
+// configured from ambient credentials.
+var KnownAmbientCredentialProviders = []string{
+	AmbientCredentialProviderAzureDNS,
+	AmbientCredentialProviderCloudDNS,
+	AmbientCredentialProviderRoute53,
+	AmbientCredentialProviderWebhook,
+}
+
 // CanUseAmbientCredentials returns whether `iss` will attempt to configure itself
 // from ambient credentials (e.g. from a cloud metadata service).
 func (o IssuerOptions) CanUseAmbientCredentials(iss cmapi.GenericIssuer) bool {
@@ -41,3 +67,23 @@ func (o IssuerOptions) CanUseAmbientCredentials(iss cmapi.GenericIssuer) bool {
 	}
 	return false
 }
+
+// CanUseAmbientCredentialsForProvider returns whether `iss` will attempt to
+// configure the named provider from ambient credentials. This is only the
+// case if `iss` can use ambient credentials at all, and the provider is in
+// AmbientCredentialProviders. If AmbientCredentialProviders is empty, all
+// providers are allowed.
+func (o IssuerOptions) CanUseAmbientCredentialsForProvider(iss cmapi.GenericIssuer, provider string) bool {
+	if !o.CanUseAmbientCredentials(iss) {
+		return false
+	}
+	if len(o.AmbientCredentialProviders) == 0 {
+		return true
+	}
+	for _, p := range o.AmbientCredentialProviders {
+		if p == provider {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+func TestResourceNamespace(t *testing.T) {
+	annotated := metav1.ObjectMeta{
+		Name:        "test",
+		Annotations: map[string]string{cmapi.ClusterIssuerResourceNamespaceAnnotationKey: "team-a"},
+	}
+
+	tests := map[string]struct {
+		issuer                            cmapi.GenericIssuer
+		perClusterIssuerResourceNamespace bool
+		expected                          string
+	}{
+		"an Issuer uses its own namespace": {
+			issuer:   &cmapi.Issuer{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}},
+			expected: "default",
+		},
+		"an Issuer ignores the resource namespace annotation": {
+			issuer: &cmapi.Issuer{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default",
+				Annotations: annotated.Annotations}},
+			perClusterIssuerResourceNamespace: true,
+			expected:                          "default",
+		},
+		"a ClusterIssuer uses the cluster resource namespace by default": {
+			issuer:                            &cmapi.ClusterIssuer{ObjectMeta: metav1.ObjectMeta{Name: "test"}},
+			perClusterIssuerResourceNamespace: true,
+			expected:                          "cert-manager",
+		},
+		"a ClusterIssuer uses its annotated resource namespace if enabled": {
+			issuer:                            &cmapi.ClusterIssuer{ObjectMeta: annotated},
+			perClusterIssuerResourceNamespace: true,
+			expected:                          "team-a",
+		},
+		"a ClusterIssuer ignores its annotated resource namespace if not enabled": {
+			issuer:   &cmapi.ClusterIssuer{ObjectMeta: annotated},
+			expected: "cert-manager",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := IssuerOptions{
+				ClusterResourceNamespace:          "cert-manager",
+				PerClusterIssuerResourceNamespace: test.perClusterIssuerResourceNamespace,
+			}
+			if ns := o.ResourceNamespace(test.issuer); ns != test.expected {
+				t.Errorf("unexpected resource namespace, exp=%q got=%q", test.expected, ns)
+			}
+		})
+	}
+}
@@ -41,6 +41,16 @@ func (c *controller) ingressesForCertificate(crt *v1.Certificate) ([]*networking
 
 		if metav1.IsControlledBy(crt, ing) {
 			affected = append(affected, ing)
+			continue
+		}
+
+		// ingresses sharing the secretName of the certificate may need to
+		// take over the certificate if it is deleted along with its owner
+		for _, tls := range ing.Spec.TLS {
+			if tls.SecretName == crt.Spec.SecretName {
+				affected = append(affected, ing)
+				break
+			}
 		}
 	}
 
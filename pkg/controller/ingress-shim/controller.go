@@ -21,7 +21,9 @@ import (
 	"fmt"
 
 	"github.com/go-logr/logr"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes"
 	networkinglisters "k8s.io/client-go/listers/networking/v1beta1"
@@ -65,6 +67,11 @@ type controller struct {
 
 	helper   issuer.Helper
 	defaults defaults
+
+	// dryRun, if true, stops the controller from creating, updating or
+	// deleting Certificates. The changes it would have made are logged and
+	// recorded as events on the Ingress instead.
+	dryRun bool
 }
 
 // Register registers and constructs the controller using the provided context.
@@ -105,6 +112,14 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 
 	// register handler functions
 	ingressInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: c.queue})
+	ingressInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.ingressSecretNamesChanged,
+		UpdateFunc: func(old, new interface{}) {
+			c.ingressSecretNamesChanged(old)
+			c.ingressSecretNamesChanged(new)
+		},
+		DeleteFunc: c.ingressSecretNamesChanged,
+	})
 	certificatesInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{WorkFunc: c.certificateDeleted})
 
 	c.helper = issuer.NewHelper(c.issuerLister, c.clusterIssuerLister)
@@ -117,6 +132,7 @@ func (c *controller) Register(ctx *controllerpkg.Context) (workqueue.RateLimitin
 		ctx.DefaultIssuerKind,
 		ctx.DefaultIssuerGroup,
 	}
+	c.dryRun = ctx.IngressShimOptions.DryRun
 
 	return c.queue, mustSync, nil
 }
@@ -142,6 +158,32 @@ func (c *controller) certificateDeleted(obj interface{}) {
 	}
 }
 
+// ingressSecretNamesChanged enqueues the Ingresses controlling the
+// Certificates for each of the TLS secretNames of the given Ingress, so that
+// hosts added to or removed from an Ingress sharing a secretName with another
+// Ingress are reflected in the shared Certificate.
+func (c *controller) ingressSecretNamesChanged(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	ing, ok := obj.(*networkingv1beta1.Ingress)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("Object is not an ingress object %#v", obj))
+		return
+	}
+	for _, tls := range ing.Spec.TLS {
+		crt, err := c.certificateLister.Certificates(ing.Namespace).Get(tls.SecretName)
+		if err != nil {
+			continue
+		}
+		ref := metav1.GetControllerOf(crt)
+		if ref == nil || ref.Kind != ingressGVK.Kind || ref.UID == ing.UID {
+			continue
+		}
+		c.queue.Add(ing.Namespace + "/" + ref.Name)
+	}
+}
+
 func (c *controller) ProcessItem(ctx context.Context, key string) error {
 	namespace, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
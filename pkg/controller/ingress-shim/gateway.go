@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Gateways are watched using a dynamic informer, so that cert-manager does not
+// depend on the Gateway API client libraries and runs on clusters that do not
+// have the Gateway API CRDs installed when the gateway-shim is disabled.
+var (
+	gatewayGVR = schema.GroupVersionResource{Group: "networking.x-k8s.io", Version: "v1alpha1", Resource: "gateways"}
+	gatewayGVK = gatewayGVR.GroupVersion().WithKind("Gateway")
+)
+
+const (
+	// gatewayTLSModePassthrough is the TLS mode of Gateway listeners that
+	// pass TLS connections through to the backend, and so do not need a
+	// certificate.
+	gatewayTLSModePassthrough = "Passthrough"
+)
+
+// gateway is the subset of a Gateway API Gateway that is read by the
+// gateway-shim.
+type gateway struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec gatewaySpec `json:"spec"`
+}
+
+type gatewaySpec struct {
+	Listeners []gatewayListener `json:"listeners,omitempty"`
+}
+
+type gatewayListener struct {
+	Hostname *string           `json:"hostname,omitempty"`
+	Port     int32             `json:"port"`
+	Protocol string            `json:"protocol"`
+	TLS      *gatewayTLSConfig `json:"tls,omitempty"`
+}
+
+type gatewayTLSConfig struct {
+	Mode           string                 `json:"mode,omitempty"`
+	CertificateRef *gatewayCertificateRef `json:"certificateRef,omitempty"`
+}
+
+type gatewayCertificateRef struct {
+	Group string `json:"group"`
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+}
+
+// isSecret returns true if the reference is to a Secret, which is the only
+// kind of certificate reference that the gateway-shim creates Certificates
+// for. The Gateway API uses the "core" group to refer to the core API group.
+func (r *gatewayCertificateRef) isSecret() bool {
+	return (r.Group == "" || r.Group == "core") && (r.Kind == "" || r.Kind == "Secret")
+}
+
+func gatewayFromUnstructured(obj *unstructured.Unstructured) (*gateway, error) {
+	gw := new(gateway)
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.UnstructuredContent(), gw); err != nil {
+		return nil, err
+	}
+	return gw, nil
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	clientset "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+const (
+	// GatewayControllerName is the name of the gateway-shim controller, which
+	// creates Certificates for the TLS listeners of annotated Gateway API
+	// Gateways in the same way as the ingress-shim does for Ingresses.
+	GatewayControllerName = "gateway-shim"
+)
+
+type gatewayController struct {
+	// maintain a reference to the workqueue for this controller
+	// so the certificateChanged method can enqueue resources
+	queue workqueue.RateLimitingInterface
+
+	// logger to be used by this controller
+	log logr.Logger
+
+	cmClient clientset.Interface
+	recorder record.EventRecorder
+
+	gatewayLister     cache.GenericLister
+	certificateLister cmlisters.CertificateLister
+
+	defaults defaults
+}
+
+// Register registers and constructs the controller using the provided context.
+// It returns the workqueue to be used to enqueue items, a list of
+// InformerSynced functions that must be synced, or an error.
+func (c *gatewayController) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
+	// construct a new named logger to be reused throughout the controller
+	c.log = logf.FromContext(ctx.RootContext, GatewayControllerName)
+
+	// create a queue used to queue up items to be processed
+	c.queue = workqueue.NewNamedRateLimitingQueue(controllerpkg.DefaultItemBasedRateLimiter(), GatewayControllerName)
+
+	// obtain references to all the informers used by this controller
+	gatewayInformer := ctx.DynamicSharedInformerFactory.ForResource(gatewayGVR)
+	certificatesInformer := ctx.SharedInformerFactory.Certmanager().V1().Certificates()
+	// build a list of InformerSynced functions that will be returned by the Register method.
+	// the controller will only begin processing items once all of these informers have synced.
+	mustSync := []cache.InformerSynced{
+		gatewayInformer.Informer().HasSynced,
+		certificatesInformer.Informer().HasSynced,
+	}
+
+	// set all the references to the listers for used by the Sync function
+	c.gatewayLister = gatewayInformer.Lister()
+	c.certificateLister = certificatesInformer.Lister()
+
+	// register handler functions
+	gatewayInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: c.queue})
+	certificatesInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{WorkFunc: c.certificateChanged})
+
+	c.cmClient = ctx.CMClient
+	c.recorder = ctx.Recorder
+	c.defaults = defaults{
+		ctx.DefaultAutoCertificateAnnotations,
+		ctx.DefaultIssuerName,
+		ctx.DefaultIssuerKind,
+		ctx.DefaultIssuerGroup,
+	}
+
+	return c.queue, mustSync, nil
+}
+
+// certificateChanged enqueues the Gateway controlling the given Certificate,
+// so that Certificates that are modified or deleted are restored.
+func (c *gatewayController) certificateChanged(obj interface{}) {
+	crt, ok := obj.(*cmapi.Certificate)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("Object is not a certificate object %#v", obj))
+		return
+	}
+	ref := metav1.GetControllerOf(crt)
+	if ref == nil || ref.APIVersion != gatewayGVK.GroupVersion().String() || ref.Kind != gatewayGVK.Kind {
+		return
+	}
+	c.queue.Add(crt.Namespace + "/" + ref.Name)
+}
+
+func (c *gatewayController) ProcessItem(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		runtime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	obj, err := c.gatewayLister.ByNamespace(namespace).Get(name)
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			runtime.HandleError(fmt.Errorf("gateway '%s' in work queue no longer exists", key))
+			return nil
+		}
+
+		return err
+	}
+
+	gw, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("Object is not a gateway object %#v", obj))
+		return nil
+	}
+
+	return c.Sync(ctx, gw)
+}
+
+func init() {
+	controllerpkg.Register(GatewayControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		return controllerpkg.NewBuilder(ctx, GatewayControllerName).
+			For(&gatewayController{}).
+			Complete()
+	})
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util"
+)
+
+// Sync creates a Certificate for each Secret referenced by the TLS listeners
+// of the given Gateway, requesting the hostnames of all the listeners that
+// reference that Secret. Certificates controlled by the Gateway for Secrets
+// that are no longer referenced by any of its listeners are deleted.
+func (c *gatewayController) Sync(ctx context.Context, obj *unstructured.Unstructured) error {
+	log := logf.WithResource(logf.FromContext(ctx), obj)
+	ctx = logf.NewContext(ctx, log)
+
+	gw, err := gatewayFromUnstructured(obj)
+	if err != nil {
+		log.Error(err, "failed to decode gateway resource")
+		c.recorder.Eventf(obj, corev1.EventTypeWarning, reasonBadConfig, "Could not decode gateway: %s", err)
+		return nil
+	}
+
+	if !shouldSync(gw, c.defaults.autoCertificateAnnotations) {
+		logf.V(logf.DebugLevel).Infof("not syncing gateway resource as it does not contain a %q or %q annotation",
+			cmapi.IngressIssuerNameAnnotationKey, cmapi.IngressClusterIssuerNameAnnotationKey)
+		return nil
+	}
+
+	issuerName, issuerKind, issuerGroup, err := issuerForAnnotations(c.defaults, gw.Annotations)
+	if err != nil {
+		log.Error(err, "failed to determine issuer to be used for gateway resource")
+		c.recorder.Eventf(obj, corev1.EventTypeWarning, reasonBadConfig, "Could not determine issuer for gateway due to bad annotations: %s",
+			err)
+		return nil
+	}
+
+	secretNames, hosts := c.hostsForGatewaySecrets(obj, gw)
+
+	for _, secretName := range secretNames {
+		crt := &cmapi.Certificate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            secretName,
+				Namespace:       gw.Namespace,
+				Labels:          gw.Labels,
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(gw, gatewayGVK)},
+			},
+			Spec: cmapi.CertificateSpec{
+				DNSNames:   hosts[secretName],
+				SecretName: secretName,
+				IssuerRef: cmmeta.ObjectReference{
+					Name:  issuerName,
+					Kind:  issuerKind,
+					Group: issuerGroup,
+				},
+				Usages: cmapi.DefaultKeyUsages(),
+			},
+		}
+		if err := translateIngressAnnotations(crt, gw.Annotations); err != nil {
+			return err
+		}
+
+		existingCrt, err := c.certificateLister.Certificates(gw.Namespace).Get(secretName)
+		if apierrors.IsNotFound(err) {
+			_, err := c.cmClient.CertmanagerV1().Certificates(crt.Namespace).Create(ctx, crt, metav1.CreateOptions{})
+			if err != nil {
+				return err
+			}
+			c.recorder.Eventf(obj, corev1.EventTypeNormal, reasonCreateCertificate, "Successfully created Certificate %q", crt.Name)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		log := logf.WithRelatedResource(log, existingCrt)
+		if !metav1.IsControlledBy(existingCrt, gw) {
+			log.V(logf.InfoLevel).Info("certificate resource is not owned by this gateway. refusing to update non-owned certificate resource for gateway")
+			continue
+		}
+		if !certNeedsUpdate(existingCrt, crt) {
+			log.V(logf.DebugLevel).Info("certificate resource is already up to date for gateway")
+			continue
+		}
+
+		updateCrt := existingCrt.DeepCopy()
+		updateCrt.Spec = crt.Spec
+		updateCrt.Labels = crt.Labels
+		_, err = c.cmClient.CertmanagerV1().Certificates(updateCrt.Namespace).Update(ctx, updateCrt, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+		c.recorder.Eventf(obj, corev1.EventTypeNormal, reasonUpdateCertificate, "Successfully updated Certificate %q", updateCrt.Name)
+	}
+
+	// TODO: investigate selector which filters for certificates controlled by the gateway
+	crts, err := c.certificateLister.Certificates(gw.Namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, crt := range crts {
+		if _, required := hosts[crt.Spec.SecretName]; required || !metav1.IsControlledBy(crt, gw) {
+			continue
+		}
+		err = c.cmClient.CertmanagerV1().Certificates(crt.Namespace).Delete(ctx, crt.Name, metav1.DeleteOptions{})
+		if err != nil {
+			return err
+		}
+		c.recorder.Eventf(obj, corev1.EventTypeNormal, reasonDeleteCertificate, "Successfully deleted unrequired Certificate %q", crt.Name)
+	}
+
+	return nil
+}
+
+// hostsForGatewaySecrets returns the names of the Secrets referenced by the
+// TLS listeners of the given Gateway, in the order they are first referenced,
+// along with the hostnames of the listeners referencing each of them.
+// Listeners that pass TLS connections through or reference something other
+// than a Secret do not need a Certificate and are skipped, and an event is
+// recorded for listeners that are missing the certificateRef or hostname
+// needed to create one.
+func (c *gatewayController) hostsForGatewaySecrets(obj *unstructured.Unstructured, gw *gateway) ([]string, map[string][]string) {
+	var secretNames []string
+	hosts := make(map[string][]string)
+	for i, l := range gw.Spec.Listeners {
+		if l.TLS == nil || l.TLS.Mode == gatewayTLSModePassthrough {
+			continue
+		}
+		ref := l.TLS.CertificateRef
+		if ref == nil || ref.Name == "" {
+			c.recorder.Eventf(obj, corev1.EventTypeWarning, reasonBadConfig, "TLS listener %d is invalid: no certificateRef specified", i)
+			continue
+		}
+		if !ref.isSecret() {
+			continue
+		}
+		if l.Hostname == nil || *l.Hostname == "" {
+			c.recorder.Eventf(obj, corev1.EventTypeWarning, reasonBadConfig, "TLS listener %d is invalid: secret %q for listener has no hostname specified", i, ref.Name)
+			continue
+		}
+
+		existing, ok := hosts[ref.Name]
+		if !ok {
+			secretNames = append(secretNames, ref.Name)
+		}
+		if !util.Contains(existing, *l.Hostname) {
+			hosts[ref.Name] = append(existing, *l.Hostname)
+		}
+	}
+	return secretNames, hosts
+}
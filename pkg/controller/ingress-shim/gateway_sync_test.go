@@ -0,0 +1,280 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	coretesting "k8s.io/client-go/testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestGatewaySync(t *testing.T) {
+	clusterIssuerAnnotations := map[string]string{cmapi.IngressClusterIssuerNameAnnotationKey: "issuer-name"}
+	buildCertificate := func(secretName string, dnsNames ...string) *cmapi.Certificate {
+		return &cmapi.Certificate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            secretName,
+				Namespace:       gen.DefaultTestNamespace,
+				OwnerReferences: buildGatewayOwnerReferences("gateway-name"),
+			},
+			Spec: cmapi.CertificateSpec{
+				DNSNames:   dnsNames,
+				SecretName: secretName,
+				IssuerRef: cmmeta.ObjectReference{
+					Name: "issuer-name",
+					Kind: "ClusterIssuer",
+				},
+				Usages: cmapi.DefaultKeyUsages(),
+			},
+		}
+	}
+
+	type testT struct {
+		Name              string
+		Gateway           *unstructured.Unstructured
+		DefaultIssuerName string
+		DefaultIssuerKind string
+		CertificateLister []runtime.Object
+		ExpectedCreate    []*cmapi.Certificate
+		ExpectedUpdate    []*cmapi.Certificate
+		ExpectedDelete    []*cmapi.Certificate
+		ExpectedEvents    []string
+	}
+	tests := []testT{
+		{
+			Name: "not create Certificates for a gateway without annotations",
+			Gateway: buildGateway("gateway-name", nil,
+				buildGatewayListener("example.com", "example-com-tls")),
+		},
+		{
+			Name: "create a Certificate for each secret referenced by the TLS listeners of a gateway",
+			Gateway: buildGateway("gateway-name", clusterIssuerAnnotations,
+				buildGatewayListener("a.example.com", "example-com-tls"),
+				buildGatewayListener("b.example.com", "example-com-tls"),
+				buildGatewayListener("foo.example.org", "example-org-tls"),
+				gatewayListener{Hostname: stringPtr("plain.example.com"), Port: 80, Protocol: "HTTP"},
+			),
+			ExpectedCreate: []*cmapi.Certificate{
+				buildCertificate("example-com-tls", "a.example.com", "b.example.com"),
+				buildCertificate("example-org-tls", "foo.example.org"),
+			},
+			ExpectedEvents: []string{
+				`Normal CreateCertificate Successfully created Certificate "example-com-tls"`,
+				`Normal CreateCertificate Successfully created Certificate "example-org-tls"`,
+			},
+		},
+		{
+			Name: "use the default issuer for a gateway with the auto certificate annotation",
+			Gateway: buildGateway("gateway-name", map[string]string{testAcmeTLSAnnotation: "true"},
+				buildGatewayListener("example.com", "example-com-tls")),
+			DefaultIssuerName: "issuer-name",
+			DefaultIssuerKind: "ClusterIssuer",
+			ExpectedCreate: []*cmapi.Certificate{
+				buildCertificate("example-com-tls", "example.com"),
+			},
+			ExpectedEvents: []string{
+				`Normal CreateCertificate Successfully created Certificate "example-com-tls"`,
+			},
+		},
+		{
+			Name: "skip listeners that pass TLS through or have no hostname",
+			Gateway: buildGateway("gateway-name", clusterIssuerAnnotations,
+				func() gatewayListener {
+					l := buildGatewayListener("passthrough.example.com", "passthrough-tls")
+					l.TLS.Mode = gatewayTLSModePassthrough
+					return l
+				}(),
+				gatewayListener{Port: 443, Protocol: "HTTPS", TLS: &gatewayTLSConfig{
+					CertificateRef: &gatewayCertificateRef{Group: "core", Kind: "Secret", Name: "no-hostname-tls"},
+				}},
+				buildGatewayListener("example.com", "example-com-tls"),
+			),
+			ExpectedCreate: []*cmapi.Certificate{
+				buildCertificate("example-com-tls", "example.com"),
+			},
+			ExpectedEvents: []string{
+				`Warning BadConfig TLS listener 1 is invalid: secret "no-hostname-tls" for listener has no hostname specified`,
+				`Normal CreateCertificate Successfully created Certificate "example-com-tls"`,
+			},
+		},
+		{
+			Name: "update a Certificate controlled by the gateway when a listener is added",
+			Gateway: buildGateway("gateway-name", clusterIssuerAnnotations,
+				buildGatewayListener("a.example.com", "example-com-tls"),
+				buildGatewayListener("b.example.com", "example-com-tls"),
+			),
+			CertificateLister: []runtime.Object{
+				buildCertificate("example-com-tls", "a.example.com"),
+			},
+			ExpectedUpdate: []*cmapi.Certificate{
+				buildCertificate("example-com-tls", "a.example.com", "b.example.com"),
+			},
+			ExpectedEvents: []string{
+				`Normal UpdateCertificate Successfully updated Certificate "example-com-tls"`,
+			},
+		},
+		{
+			Name: "not update a Certificate that is not controlled by the gateway",
+			Gateway: buildGateway("gateway-name", clusterIssuerAnnotations,
+				buildGatewayListener("a.example.com", "example-com-tls"),
+			),
+			CertificateLister: []runtime.Object{
+				func() *cmapi.Certificate {
+					crt := buildCertificate("example-com-tls", "other.example.com")
+					crt.OwnerReferences = nil
+					return crt
+				}(),
+			},
+		},
+		{
+			Name: "delete Certificates controlled by the gateway for secrets no longer referenced by its listeners",
+			Gateway: buildGateway("gateway-name", clusterIssuerAnnotations,
+				buildGatewayListener("example.com", "example-com-tls"),
+			),
+			CertificateLister: []runtime.Object{
+				buildCertificate("example-com-tls", "example.com"),
+				buildCertificate("example-org-tls", "example.org"),
+			},
+			ExpectedDelete: []*cmapi.Certificate{
+				buildCertificate("example-org-tls", "example.org"),
+			},
+			ExpectedEvents: []string{
+				`Normal DeleteCertificate Successfully deleted unrequired Certificate "example-org-tls"`,
+			},
+		},
+	}
+	testFn := func(test testT) func(t *testing.T) {
+		return func(t *testing.T) {
+			var expectedActions []testpkg.Action
+			for _, cr := range test.ExpectedCreate {
+				expectedActions = append(expectedActions,
+					testpkg.NewAction(coretesting.NewCreateAction(
+						cmapi.SchemeGroupVersion.WithResource("certificates"),
+						cr.Namespace,
+						cr,
+					)),
+				)
+			}
+			for _, cr := range test.ExpectedUpdate {
+				expectedActions = append(expectedActions,
+					testpkg.NewAction(coretesting.NewUpdateAction(
+						cmapi.SchemeGroupVersion.WithResource("certificates"),
+						cr.Namespace,
+						cr,
+					)),
+				)
+			}
+			for _, cr := range test.ExpectedDelete {
+				expectedActions = append(expectedActions,
+					testpkg.NewAction(coretesting.NewDeleteAction(
+						cmapi.SchemeGroupVersion.WithResource("certificates"),
+						cr.Namespace,
+						cr.Name,
+					)))
+			}
+			b := &testpkg.Builder{
+				T:                  t,
+				CertManagerObjects: test.CertificateLister,
+				ExpectedActions:    expectedActions,
+				ExpectedEvents:     test.ExpectedEvents,
+			}
+			b.Init()
+			defer b.Stop()
+			c := &gatewayController{
+				cmClient:          b.CMClient,
+				recorder:          b.Recorder,
+				certificateLister: b.SharedInformerFactory.Certmanager().V1().Certificates().Lister(),
+				defaults: defaults{
+					issuerName:                 test.DefaultIssuerName,
+					issuerKind:                 test.DefaultIssuerKind,
+					autoCertificateAnnotations: []string{testAcmeTLSAnnotation},
+				},
+			}
+			b.Start()
+
+			if err := c.Sync(context.Background(), test.Gateway); err != nil {
+				t.Errorf("Expected no error, but got: %v", err)
+			}
+
+			if err := b.AllEventsCalled(); err != nil {
+				t.Error(err)
+			}
+			if err := b.AllReactorsCalled(); err != nil {
+				t.Errorf("Not all expected reactors were called: %v", err)
+			}
+			if err := b.AllActionsExecuted(); err != nil {
+				t.Errorf(err.Error())
+			}
+		}
+	}
+	for _, test := range tests {
+		t.Run(test.Name, testFn(test))
+	}
+}
+
+func buildGateway(name string, annotations map[string]string, listeners ...gatewayListener) *unstructured.Unstructured {
+	gw := &gateway{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: gatewayGVK.GroupVersion().String(),
+			Kind:       gatewayGVK.Kind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   gen.DefaultTestNamespace,
+			Annotations: annotations,
+			UID:         types.UID(name),
+		},
+		Spec: gatewaySpec{Listeners: listeners},
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(gw)
+	if err != nil {
+		panic(err)
+	}
+	return &unstructured.Unstructured{Object: obj}
+}
+
+func buildGatewayListener(hostname, secretName string) gatewayListener {
+	return gatewayListener{
+		Hostname: stringPtr(hostname),
+		Port:     443,
+		Protocol: "HTTPS",
+		TLS: &gatewayTLSConfig{
+			Mode:           "Terminate",
+			CertificateRef: &gatewayCertificateRef{Group: "core", Kind: "Secret", Name: secretName},
+		},
+	}
+}
+
+func buildGatewayOwnerReferences(name string) []metav1.OwnerReference {
+	return []metav1.OwnerReference{
+		*metav1.NewControllerRef(&metav1.ObjectMeta{Name: name, UID: types.UID(name)}, gatewayGVK),
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
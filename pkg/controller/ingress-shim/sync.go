@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -35,6 +36,7 @@ import (
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 	"github.com/jetstack/cert-manager/pkg/logs"
 	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 )
 
@@ -81,6 +83,10 @@ func (c *controller) Sync(ctx context.Context, ing *networkingv1beta1.Ingress) e
 	}
 
 	for _, crt := range newCrts {
+		if c.dryRun {
+			c.recordDryRun(ctx, ing, reasonCreateCertificate, "create", crt)
+			continue
+		}
 		_, err := c.cmClient.CertmanagerV1().Certificates(crt.Namespace).Create(ctx, crt, metav1.CreateOptions{})
 		if err != nil {
 			return err
@@ -89,6 +95,10 @@ func (c *controller) Sync(ctx context.Context, ing *networkingv1beta1.Ingress) e
 	}
 
 	for _, crt := range updateCrts {
+		if c.dryRun {
+			c.recordDryRun(ctx, ing, reasonUpdateCertificate, "update", crt)
+			continue
+		}
 		_, err := c.cmClient.CertmanagerV1().Certificates(crt.Namespace).Update(ctx, crt, metav1.UpdateOptions{})
 		if err != nil {
 			return err
@@ -102,6 +112,10 @@ func (c *controller) Sync(ctx context.Context, ing *networkingv1beta1.Ingress) e
 	}
 
 	for _, crt := range unrequiredCrts {
+		if c.dryRun {
+			c.recordDryRun(ctx, ing, reasonDeleteCertificate, "delete", crt)
+			continue
+		}
 		err = c.cmClient.CertmanagerV1().Certificates(crt.Namespace).Delete(ctx, crt.Name, metav1.DeleteOptions{})
 		if err != nil {
 			return err
@@ -112,6 +126,22 @@ func (c *controller) Sync(ctx context.Context, ing *networkingv1beta1.Ingress) e
 	return nil
 }
 
+// recordDryRun logs and records an event on the given Ingress for a
+// Certificate that would have been created, updated or deleted had the
+// controller not been running in dry-run mode.
+func (c *controller) recordDryRun(ctx context.Context, ing *networkingv1beta1.Ingress, reason, verb string, crt *cmapi.Certificate) {
+	ref := crt.Spec.IssuerRef
+	issuer := ref.Kind
+	if len(ref.Group) > 0 {
+		issuer += "." + ref.Group
+	}
+	issuer += "/" + ref.Name
+
+	logf.WithRelatedResource(logf.FromContext(ctx), crt).Info("dry run: would "+verb+" certificate for ingress",
+		"issuer", issuer, "dnsNames", crt.Spec.DNSNames)
+	c.recorder.Eventf(ing, corev1.EventTypeNormal, reason, "Dry run: would %s Certificate %q with issuer %s", verb, crt.Name, issuer)
+}
+
 func (c *controller) validateIngress(ing *networkingv1beta1.Ingress) []error {
 	// check for duplicate values of networkingv1beta1.IngressTLS.SecretName
 	var errs []error
@@ -160,6 +190,11 @@ func (c *controller) buildCertificates(ctx context.Context, ing *networkingv1bet
 			return nil, nil, err
 		}
 
+		hosts, err := c.hostsForSecretName(ing, tls.SecretName)
+		if err != nil {
+			return nil, nil, err
+		}
+
 		crt := &cmapi.Certificate{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:            tls.SecretName,
@@ -168,7 +203,7 @@ func (c *controller) buildCertificates(ctx context.Context, ing *networkingv1bet
 				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(ing, ingressGVK)},
 			},
 			Spec: cmapi.CertificateSpec{
-				DNSNames:   tls.Hosts,
+				DNSNames:   hosts,
 				SecretName: tls.SecretName,
 				IssuerRef: cmmeta.ObjectReference{
 					Name:  issuerName,
@@ -196,7 +231,31 @@ func (c *controller) buildCertificates(ctx context.Context, ing *networkingv1bet
 			}
 
 			if !metav1.IsControlledBy(existingCrt, ing) {
-				log.V(logf.InfoLevel).Info("certificate resource is not owned by this ingress. refusing to update non-owned certificate resource for ingress")
+				owner, err := c.sharingIngress(existingCrt, tls.SecretName)
+				if err != nil {
+					return nil, nil, err
+				}
+				if owner == nil {
+					log.V(logf.InfoLevel).Info("certificate resource is not owned by this ingress. refusing to update non-owned certificate resource for ingress")
+					continue
+				}
+
+				// the certificate is owned by another ingress using the same
+				// secretName. Only the hosts are merged into the certificate,
+				// the rest of its spec is left to the owning ingress.
+				hosts, err := c.hostsForSecretName(owner, tls.SecretName)
+				if err != nil {
+					return nil, nil, err
+				}
+				if util.EqualSorted(existingCrt.Spec.DNSNames, hosts) {
+					log.V(logf.DebugLevel).Info("certificate resource owned by another ingress already contains the hosts of this ingress")
+					continue
+				}
+
+				log.V(logf.DebugLevel).Info("merging hosts into certificate resource owned by another ingress using the same secretName")
+				updateCrt := existingCrt.DeepCopy()
+				updateCrt.Spec.DNSNames = hosts
+				updateCrts = append(updateCrts, updateCrt)
 				continue
 			}
 
@@ -218,6 +277,79 @@ func (c *controller) buildCertificates(ctx context.Context, ing *networkingv1bet
 	return newCrts, updateCrts, nil
 }
 
+// hostsForSecretName returns the DNS names that should be requested by the
+// Certificate for the given secretName, which is controlled by owner.
+// Ingresses in the same namespace may share a secretName, in which case the
+// hosts of all of them are merged into the single Certificate. The hosts of
+// owner are listed first followed by those of the other Ingresses in name
+// order, so that the result is the same regardless of which of the Ingresses
+// is being synced.
+func (c *controller) hostsForSecretName(owner *networkingv1beta1.Ingress, secretName string) ([]string, error) {
+	ings, err := c.ingressLister.Ingresses(owner.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ings, func(i, j int) bool {
+		return ings[i].Name < ings[j].Name
+	})
+
+	var hosts []string
+	seen := make(map[string]bool)
+	addHosts := func(ing *networkingv1beta1.Ingress) {
+		for _, tls := range ing.Spec.TLS {
+			if tls.SecretName != secretName {
+				continue
+			}
+			for _, host := range tls.Hosts {
+				if !seen[host] {
+					seen[host] = true
+					hosts = append(hosts, host)
+				}
+			}
+		}
+	}
+
+	addHosts(owner)
+	for _, ing := range ings {
+		if ing.UID == owner.UID || !shouldSync(ing, c.defaults.autoCertificateAnnotations) {
+			continue
+		}
+		addHosts(ing)
+	}
+
+	return hosts, nil
+}
+
+// sharingIngress returns the Ingress controlling the given Certificate if that
+// Ingress still uses the given secretName, meaning that the Certificate is
+// shared with any other Ingress using the same secretName. It returns nil if
+// the Certificate is not controlled by such an Ingress.
+func (c *controller) sharingIngress(crt *cmapi.Certificate, secretName string) (*networkingv1beta1.Ingress, error) {
+	ref := metav1.GetControllerOf(crt)
+	if ref == nil || ref.Kind != ingressGVK.Kind {
+		return nil, nil
+	}
+
+	owner, err := c.ingressLister.Ingresses(crt.Namespace).Get(ref.Name)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if owner.UID != ref.UID || !shouldSync(owner, c.defaults.autoCertificateAnnotations) {
+		return nil, nil
+	}
+	for _, tls := range owner.Spec.TLS {
+		if tls.SecretName == secretName {
+			return owner, nil
+		}
+	}
+
+	return nil, nil
+}
+
 func (c *controller) findUnrequiredCertificates(ing *networkingv1beta1.Ingress) ([]*cmapi.Certificate, error) {
 	var unrequired []*cmapi.Certificate
 	// TODO: investigate selector which filters for certificates controlled by the ingress
@@ -319,10 +451,10 @@ func setIssuerSpecificConfig(crt *cmapi.Certificate, ing *networkingv1beta1.Ingr
 	}
 }
 
-// shouldSync returns true if this ingress should have a Certificate resource
-// created for it
-func shouldSync(ing *networkingv1beta1.Ingress, autoCertificateAnnotations []string) bool {
-	annotations := ing.Annotations
+// shouldSync returns true if this ingress, or gateway, should have a
+// Certificate resource created for it
+func shouldSync(obj metav1.Object, autoCertificateAnnotations []string) bool {
+	annotations := obj.GetAnnotations()
 	if annotations == nil {
 		annotations = map[string]string{}
 	}
@@ -346,12 +478,18 @@ func shouldSync(ing *networkingv1beta1.Ingress, autoCertificateAnnotations []str
 // Certificate created for the given Ingress resource. If one is not set, the
 // default issuer given to the controller will be used.
 func (c *controller) issuerForIngress(ing *networkingv1beta1.Ingress) (name, kind, group string, err error) {
+	return issuerForAnnotations(c.defaults, ing.Annotations)
+}
+
+// issuerForAnnotations will determine the issuer that should be specified on
+// a Certificate created for a resource with the given annotations, falling
+// back to the given defaults if one is not set.
+func issuerForAnnotations(defaults defaults, annotations map[string]string) (name, kind, group string, err error) {
 	var errs []string
 
-	name = c.defaults.issuerName
-	kind = c.defaults.issuerKind
-	group = c.defaults.issuerGroup
-	annotations := ing.Annotations
+	name = defaults.issuerName
+	kind = defaults.issuerKind
+	group = defaults.issuerGroup
 
 	if annotations == nil {
 		annotations = map[string]string{}
@@ -90,9 +90,11 @@ func TestSync(t *testing.T) {
 		IssuerLister        []runtime.Object
 		ClusterIssuerLister []runtime.Object
 		CertificateLister   []runtime.Object
+		IngressLister       []runtime.Object
 		DefaultIssuerName   string
 		DefaultIssuerKind   string
 		DefaultIssuerGroup  string
+		DryRun              bool
 		Err                 bool
 		ExpectedCreate      []*cmapi.Certificate
 		ExpectedUpdate      []*cmapi.Certificate
@@ -1106,6 +1108,199 @@ func TestSync(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name:    "return a single Certificate with the hosts of all ingresses sharing a secretName",
+			Issuer:  acmeClusterIssuer,
+			Ingress: buildSharedSecretIngress("ingress-a", []string{"a.example.com", "example.com"}),
+			IngressLister: []runtime.Object{
+				buildSharedSecretIngress("ingress-b", []string{"b.example.com", "example.com"}),
+			},
+			ClusterIssuerLister: []runtime.Object{acmeClusterIssuer},
+			ExpectedEvents:      []string{`Normal CreateCertificate Successfully created Certificate "example-com-tls"`},
+			ExpectedCreate: []*cmapi.Certificate{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "example-com-tls",
+						Namespace:       gen.DefaultTestNamespace,
+						OwnerReferences: buildOwnerReferences("ingress-a", gen.DefaultTestNamespace),
+					},
+					Spec: cmapi.CertificateSpec{
+						DNSNames:   []string{"a.example.com", "example.com", "b.example.com"},
+						SecretName: "example-com-tls",
+						IssuerRef: cmmeta.ObjectReference{
+							Name: "issuer-name",
+							Kind: "ClusterIssuer",
+						},
+						Usages: cmapi.DefaultKeyUsages(),
+					},
+				},
+			},
+		},
+		{
+			Name:    "merge the hosts of an ingress into a Certificate owned by another ingress sharing the same secretName",
+			Issuer:  acmeClusterIssuer,
+			Ingress: buildSharedSecretIngress("ingress-b", []string{"b.example.com", "example.com"}),
+			IngressLister: []runtime.Object{
+				buildSharedSecretIngress("ingress-a", []string{"a.example.com", "example.com"}),
+				buildSharedSecretIngress("ingress-b", []string{"b.example.com", "example.com"}),
+			},
+			ClusterIssuerLister: []runtime.Object{acmeClusterIssuer},
+			CertificateLister: []runtime.Object{
+				&cmapi.Certificate{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "example-com-tls",
+						Namespace:       gen.DefaultTestNamespace,
+						OwnerReferences: buildOwnerReferences("ingress-a", gen.DefaultTestNamespace),
+					},
+					Spec: cmapi.CertificateSpec{
+						DNSNames:   []string{"a.example.com", "example.com"},
+						SecretName: "example-com-tls",
+						IssuerRef: cmmeta.ObjectReference{
+							Name: "issuer-name",
+							Kind: "ClusterIssuer",
+						},
+						Usages: cmapi.DefaultKeyUsages(),
+					},
+				},
+			},
+			ExpectedEvents: []string{`Normal UpdateCertificate Successfully updated Certificate "example-com-tls"`},
+			ExpectedUpdate: []*cmapi.Certificate{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "example-com-tls",
+						Namespace:       gen.DefaultTestNamespace,
+						OwnerReferences: buildOwnerReferences("ingress-a", gen.DefaultTestNamespace),
+					},
+					Spec: cmapi.CertificateSpec{
+						DNSNames:   []string{"a.example.com", "example.com", "b.example.com"},
+						SecretName: "example-com-tls",
+						IssuerRef: cmmeta.ObjectReference{
+							Name: "issuer-name",
+							Kind: "ClusterIssuer",
+						},
+						Usages: cmapi.DefaultKeyUsages(),
+					},
+				},
+			},
+		},
+		{
+			Name:                "in dry-run mode, record the Certificate that would be created with the default issuer without creating it",
+			Issuer:              clusterIssuer,
+			DefaultIssuerName:   "issuer-name",
+			DefaultIssuerKind:   "ClusterIssuer",
+			DefaultIssuerGroup:  "cert-manager.io",
+			DryRun:              true,
+			ClusterIssuerLister: []runtime.Object{clusterIssuer},
+			Ingress: &networkingv1beta1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress-name",
+					Namespace: gen.DefaultTestNamespace,
+					Annotations: map[string]string{
+						testAcmeTLSAnnotation: "true",
+					},
+					UID: types.UID("ingress-name"),
+				},
+				Spec: networkingv1beta1.IngressSpec{
+					TLS: []networkingv1beta1.IngressTLS{
+						{
+							Hosts:      []string{"example.com", "www.example.com"},
+							SecretName: "example-com-tls",
+						},
+					},
+				},
+			},
+			ExpectedEvents: []string{`Normal CreateCertificate Dry run: would create Certificate "example-com-tls" with issuer ClusterIssuer.cert-manager.io/issuer-name`},
+		},
+		{
+			Name:         "in dry-run mode, record the Certificates that would be updated and deleted without changing them",
+			Issuer:       acmeIssuer,
+			IssuerLister: []runtime.Object{acmeIssuer},
+			DryRun:       true,
+			Ingress: &networkingv1beta1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ingress-name",
+					Namespace: gen.DefaultTestNamespace,
+					Annotations: map[string]string{
+						cmapi.IngressIssuerNameAnnotationKey: "issuer-name",
+					},
+					UID: types.UID("ingress-name"),
+				},
+				Spec: networkingv1beta1.IngressSpec{
+					TLS: []networkingv1beta1.IngressTLS{
+						{
+							Hosts:      []string{"example.com", "www.example.com"},
+							SecretName: "example-com-tls",
+						},
+					},
+				},
+			},
+			CertificateLister: []runtime.Object{
+				&cmapi.Certificate{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "example-com-tls",
+						Namespace:       gen.DefaultTestNamespace,
+						OwnerReferences: buildOwnerReferences("ingress-name", gen.DefaultTestNamespace),
+					},
+					Spec: cmapi.CertificateSpec{
+						DNSNames:   []string{"example.com"},
+						SecretName: "example-com-tls",
+						IssuerRef: cmmeta.ObjectReference{
+							Name: "issuer-name",
+							Kind: "Issuer",
+						},
+						Usages: cmapi.DefaultKeyUsages(),
+					},
+				},
+				&cmapi.Certificate{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "existing-crt",
+						Namespace:       gen.DefaultTestNamespace,
+						OwnerReferences: buildOwnerReferences("ingress-name", gen.DefaultTestNamespace),
+					},
+					Spec: cmapi.CertificateSpec{
+						DNSNames:   []string{"example.com"},
+						SecretName: "existing-crt",
+						IssuerRef: cmmeta.ObjectReference{
+							Name: "issuer-name",
+							Kind: "Issuer",
+						},
+						Usages: cmapi.DefaultKeyUsages(),
+					},
+				},
+			},
+			ExpectedEvents: []string{
+				`Normal UpdateCertificate Dry run: would update Certificate "example-com-tls" with issuer Issuer/issuer-name`,
+				`Normal DeleteCertificate Dry run: would delete Certificate "existing-crt" with issuer Issuer/issuer-name`,
+			},
+		},
+		{
+			Name:    "not update a shared Certificate that already contains the hosts of all ingresses sharing its secretName",
+			Issuer:  acmeClusterIssuer,
+			Ingress: buildSharedSecretIngress("ingress-b", []string{"b.example.com", "example.com"}),
+			IngressLister: []runtime.Object{
+				buildSharedSecretIngress("ingress-a", []string{"a.example.com", "example.com"}),
+				buildSharedSecretIngress("ingress-b", []string{"b.example.com", "example.com"}),
+			},
+			ClusterIssuerLister: []runtime.Object{acmeClusterIssuer},
+			CertificateLister: []runtime.Object{
+				&cmapi.Certificate{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "example-com-tls",
+						Namespace:       gen.DefaultTestNamespace,
+						OwnerReferences: buildOwnerReferences("ingress-a", gen.DefaultTestNamespace),
+					},
+					Spec: cmapi.CertificateSpec{
+						DNSNames:   []string{"a.example.com", "example.com", "b.example.com"},
+						SecretName: "example-com-tls",
+						IssuerRef: cmmeta.ObjectReference{
+							Name: "issuer-name",
+							Kind: "ClusterIssuer",
+						},
+						Usages: cmapi.DefaultKeyUsages(),
+					},
+				},
+			},
+		},
 	}
 	testFn := func(test testT) func(t *testing.T) {
 		return func(t *testing.T) {
@@ -1142,6 +1337,7 @@ func TestSync(t *testing.T) {
 			}
 			b := &testpkg.Builder{
 				T:                  t,
+				KubeObjects:        test.IngressLister,
 				CertManagerObjects: allCMObjects,
 				ExpectedActions:    expectedActions,
 				ExpectedEvents:     test.ExpectedEvents,
@@ -1155,6 +1351,7 @@ func TestSync(t *testing.T) {
 				issuerLister:        b.SharedInformerFactory.Certmanager().V1().Issuers().Lister(),
 				clusterIssuerLister: b.SharedInformerFactory.Certmanager().V1().ClusterIssuers().Lister(),
 				certificateLister:   b.SharedInformerFactory.Certmanager().V1().Certificates().Lister(),
+				ingressLister:       b.KubeSharedInformerFactory.Networking().V1beta1().Ingresses().Lister(),
 				defaults: defaults{
 					issuerName:                 test.DefaultIssuerName,
 					issuerKind:                 test.DefaultIssuerKind,
@@ -1162,6 +1359,7 @@ func TestSync(t *testing.T) {
 					autoCertificateAnnotations: []string{testAcmeTLSAnnotation},
 				},
 				helper: &fakeHelper{issuer: test.Issuer},
+				dryRun: test.DryRun,
 			}
 			b.Start()
 
@@ -1313,6 +1511,19 @@ func buildIngress(name, namespace string, annotations map[string]string) *networ
 	}
 }
 
+func buildSharedSecretIngress(name string, hosts []string) *networkingv1beta1.Ingress {
+	ing := buildIngress(name, gen.DefaultTestNamespace, map[string]string{
+		cmapi.IngressClusterIssuerNameAnnotationKey: "issuer-name",
+	})
+	ing.Spec.TLS = []networkingv1beta1.IngressTLS{
+		{
+			Hosts:      hosts,
+			SecretName: "example-com-tls",
+		},
+	}
+	return ing
+}
+
 func buildOwnerReferences(name, namespace string) []metav1.OwnerReference {
 	return []metav1.OwnerReference{
 		*metav1.NewControllerRef(buildIngress(name, namespace, nil), ingressGVK),
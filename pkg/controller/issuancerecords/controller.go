@@ -0,0 +1,146 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package issuancerecords implements a controller that garbage collects
+// IssuanceRecords once they are older than the configured retention period.
+package issuancerecords
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
+
+	cmclient "github.com/jetstack/cert-manager/pkg/client/clientset/versioned"
+	cminformers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+const (
+	ControllerName = "issuancerecords"
+)
+
+type controller struct {
+	issuanceRecordLister cmlisters.IssuanceRecordLister
+	client               cmclient.Interface
+	clock                clock.Clock
+	queue                workqueue.RateLimitingInterface
+
+	// retention is the duration IssuanceRecords are kept for after they
+	// have been created. If zero, records are never deleted.
+	retention time.Duration
+}
+
+func NewController(log logr.Logger, client cmclient.Interface, cmFactory cminformers.SharedInformerFactory,
+	clock clock.Clock, retention time.Duration) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
+	// create a queue used to queue up items to be processed
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Second*1, time.Second*30), ControllerName)
+
+	// obtain references to all the informers used by this controller
+	issuanceRecordInformer := cmFactory.Certmanager().V1().IssuanceRecords()
+
+	issuanceRecordInformer.Informer().AddEventHandler(&controllerpkg.QueuingEventHandler{Queue: queue})
+
+	// build a list of InformerSynced functions that will be returned by the Register method.
+	// the controller will only begin processing items once all of these informers have synced.
+	mustSync := []cache.InformerSynced{
+		issuanceRecordInformer.Informer().HasSynced,
+	}
+
+	return &controller{
+		issuanceRecordLister: issuanceRecordInformer.Lister(),
+		client:               client,
+		clock:                clock,
+		queue:                queue,
+		retention:            retention,
+	}, queue, mustSync
+}
+
+// ProcessItem will delete the IssuanceRecord with the given key if it was
+// created longer ago than the retention period. Otherwise, the record is
+// re-queued to be processed again once it has expired.
+func (c *controller) ProcessItem(ctx context.Context, key string) error {
+	log := logf.FromContext(ctx).WithValues("key", key)
+
+	ctx = logf.NewContext(ctx, log)
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		log.Error(err, "invalid resource key passed to ProcessItem")
+		return nil
+	}
+
+	// A retention of zero means IssuanceRecords are kept forever.
+	if c.retention == 0 {
+		return nil
+	}
+
+	record, err := c.issuanceRecordLister.IssuanceRecords(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		log.V(logf.DebugLevel).Info("issuance record not found for key")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	log = logf.WithResource(log, record)
+
+	remaining := record.CreationTimestamp.Add(c.retention).Sub(c.clock.Now())
+	if remaining > 0 {
+		log.V(logf.DebugLevel).Info("issuance record has not expired, scheduling garbage collection", "after", remaining)
+		c.queue.AddAfter(key, remaining)
+		return nil
+	}
+
+	log.V(logf.DebugLevel).Info("garbage collecting expired issuance record")
+	err = c.client.CertmanagerV1().IssuanceRecords(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// controllerWrapper wraps the `controller` structure to make it implement
+// the controllerpkg.queueingController interface
+type controllerWrapper struct {
+	*controller
+}
+
+func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
+	// construct a new named logger to be reused throughout the controller
+	log := logf.FromContext(ctx.RootContext, ControllerName)
+
+	ctrl, queue, mustSync := NewController(log, ctx.CMClient, ctx.SharedInformerFactory, ctx.Clock, ctx.IssuanceRecordRetention)
+	c.controller = ctrl
+
+	return queue, mustSync, nil
+}
+
+func init() {
+	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		return controllerpkg.NewBuilder(ctx, ControllerName).
+			For(&controllerWrapper{}).
+			Complete()
+	})
+}
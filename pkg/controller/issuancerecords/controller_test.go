@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package issuancerecords
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coretesting "k8s.io/client-go/testing"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+)
+
+func issuanceRecord(name string, created time.Time) *cmapi.IssuanceRecord {
+	return &cmapi.IssuanceRecord{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "testns",
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: cmapi.IssuanceRecordSpec{
+			CertificateRequestName: name,
+			IssuerRef:              cmmeta.ObjectReference{Name: "test-issuer"},
+			SerialNumber:           "1",
+		},
+	}
+}
+
+func TestProcessItem(t *testing.T) {
+	now := time.Now()
+	retention := time.Hour
+
+	tests := map[string]struct {
+		// key that should be passed to ProcessItem.
+		key string
+
+		// retention period the controller is configured with.
+		retention time.Duration
+
+		// records, if set, will exist in the apiserver before the test is run.
+		records []runtime.Object
+
+		expectedActions []testpkg.Action
+	}{
+		"do nothing if an invalid 'key' is used": {
+			key:       "abc/def/ghi",
+			retention: retention,
+		},
+		"do nothing if a key references an IssuanceRecord that does not exist": {
+			key:       "testns/name",
+			retention: retention,
+		},
+		"do nothing if the IssuanceRecord has not expired": {
+			key:       "testns/cr-1",
+			retention: retention,
+			records: []runtime.Object{
+				issuanceRecord("cr-1", now.Add(-retention/2)),
+			},
+		},
+		"do nothing if records are kept forever": {
+			key:       "testns/cr-1",
+			retention: 0,
+			records: []runtime.Object{
+				issuanceRecord("cr-1", now.Add(-retention*1000)),
+			},
+		},
+		"delete the IssuanceRecord if it is older than the retention period": {
+			key:       "testns/cr-1",
+			retention: retention,
+			records: []runtime.Object{
+				issuanceRecord("cr-1", now.Add(-retention-time.Minute)),
+				issuanceRecord("cr-2", now),
+			},
+			expectedActions: []testpkg.Action{
+				testpkg.NewAction(coretesting.NewDeleteAction(cmapi.SchemeGroupVersion.WithResource("issuancerecords"), "testns", "cr-1")),
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Create and initialise a new unit test builder
+			builder := &testpkg.Builder{
+				T:                  t,
+				Clock:              fakeclock.NewFakeClock(now),
+				CertManagerObjects: test.records,
+				ExpectedActions:    test.expectedActions,
+			}
+			builder.Init()
+			builder.Context.IssuanceRecordRetention = test.retention
+
+			// Register informers used by the controller using the registration wrapper
+			w := &controllerWrapper{}
+			_, _, err := w.Register(builder.Context)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Start the informers and begin processing updates
+			builder.Start()
+			defer builder.Stop()
+
+			if err := w.controller.ProcessItem(context.Background(), test.key); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if err := builder.AllEventsCalled(); err != nil {
+				builder.T.Error(err)
+			}
+			if err := builder.AllActionsExecuted(); err != nil {
+				builder.T.Error(err)
+			}
+		})
+	}
+}
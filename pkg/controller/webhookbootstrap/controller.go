@@ -0,0 +1,326 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhookbootstrap implements a controller that issues the serving
+// certificate of the cert-manager webhook from an internal selfsigned issuer.
+//
+// The webhook validates cert-manager resources, so a serving certificate
+// that is requested using an Issuer and Certificate cannot be issued while
+// the webhook itself is not yet serving, such as during installation. This
+// controller breaks that cycle: it signs the certificate itself and writes it
+// directly to the configured Secret using the core API, without creating any
+// cert-manager resources, so issuance never passes through the admission
+// webhook. The webhook can then mount the Secret and serve using
+// --tls-cert-file and --tls-private-key-file, and the contents of its `ca.crt`
+// key can be used as the caBundle of the webhook configurations. The
+// certificate is a self signed CA certificate so that clients can verify it
+// against that bundle.
+//
+// The Secret is watched using an informer scoped to its own namespace, so
+// the controller works even if it lives outside of --namespace.
+//
+// The controller only runs if --webhook-bootstrap-secret-name is set.
+package webhookbootstrap
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+)
+
+const (
+	ControllerName = "webhook-bootstrap"
+
+	// resyncPeriod is how often the configured Secret is checked even if it
+	// has not changed, so that it is created if it has never existed.
+	resyncPeriod = time.Hour
+)
+
+// controller maintains a self signed serving certificate for the webhook in
+// the configured Secret. The certificate is reissued if the Secret is
+// missing or invalid, if the configured DNS names change, or once two thirds
+// of its duration has passed.
+type controller struct {
+	secretLister corelisters.SecretLister
+	client       kubernetes.Interface
+	clock        clock.Clock
+	queue        workqueue.RateLimitingInterface
+	log          logr.Logger
+
+	opts controllerpkg.WebhookBootstrapOptions
+}
+
+func NewController(
+	log logr.Logger,
+	client kubernetes.Interface,
+	factory informers.SharedInformerFactory,
+	clock clock.Clock,
+	opts controllerpkg.WebhookBootstrapOptions,
+) (*controller, workqueue.RateLimitingInterface, []cache.InformerSynced) {
+	// create a queue used to queue up items to be processed
+	queue := workqueue.NewNamedRateLimitingQueue(controllerpkg.DefaultItemBasedRateLimiter(), ControllerName)
+
+	// obtain references to all the informers used by this controller
+	secretInformer := factory.Core().V1().Secrets()
+
+	c := &controller{
+		secretLister: secretInformer.Lister(),
+		client:       client,
+		clock:        clock,
+		queue:        queue,
+		log:          log,
+		opts:         opts,
+	}
+
+	secretInformer.Informer().AddEventHandler(&controllerpkg.BlockingEventHandler{WorkFunc: c.secretChanged})
+
+	// build a list of InformerSynced functions that will be returned by the Register method.
+	// the controller will only begin processing items once all of these informers have synced.
+	mustSync := []cache.InformerSynced{
+		secretInformer.Informer().HasSynced,
+	}
+
+	return c, queue, mustSync
+}
+
+// secretKey returns the key of the configured Secret.
+func (c *controller) secretKey() string {
+	return c.opts.SecretNamespace + "/" + c.opts.SecretName
+}
+
+// secretChanged enqueues the configured Secret if the given Secret is it.
+func (c *controller) secretChanged(obj interface{}) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		c.log.Error(nil, "object was not a secret object")
+		return
+	}
+	if secret.Namespace == c.opts.SecretNamespace && secret.Name == c.opts.SecretName {
+		c.queue.Add(c.secretKey())
+	}
+}
+
+// enqueueSecret enqueues the configured Secret, so that it is created if it
+// does not exist.
+func (c *controller) enqueueSecret(context.Context) {
+	c.queue.Add(c.secretKey())
+}
+
+// ProcessItem issues a new serving certificate for the webhook and stores it
+// in the configured Secret if the existing one is missing, invalid or due
+// for renewal.
+func (c *controller) ProcessItem(ctx context.Context, key string) error {
+	log := logf.FromContext(ctx).WithValues("key", key)
+
+	if key != c.secretKey() {
+		log.V(logf.DebugLevel).Info("ignoring secret that is not the webhook bootstrap secret")
+		return nil
+	}
+
+	secret, err := c.secretLister.Secrets(c.opts.SecretNamespace).Get(c.opts.SecretName)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	now := c.clock.Now()
+	if secret != nil {
+		renewalTime, err := c.renewalTime(secret)
+		if err != nil {
+			log.V(logf.InfoLevel).Info("reissuing webhook serving certificate", "reason", err.Error())
+		} else if renewalTime.After(now) {
+			log.V(logf.DebugLevel).Info("webhook serving certificate is up to date, scheduling renewal", "after", renewalTime.Sub(now))
+			c.queue.AddAfter(key, renewalTime.Sub(now))
+			return nil
+		}
+	}
+
+	certPEM, keyPEM, err := selfSign(c.opts.DNSNames, now, c.opts.Duration)
+	if err != nil {
+		// the configuration is validated on startup, so this is not retried
+		log.Error(err, "error issuing webhook serving certificate")
+		return nil
+	}
+	data := map[string][]byte{
+		corev1.TLSCertKey:       certPEM,
+		corev1.TLSPrivateKeyKey: keyPEM,
+		cmmeta.TLSCAKey:         certPEM,
+	}
+
+	if secret == nil {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      c.opts.SecretName,
+				Namespace: c.opts.SecretNamespace,
+			},
+			Type: corev1.SecretTypeTLS,
+			Data: data,
+		}
+		_, err = c.client.CoreV1().Secrets(c.opts.SecretNamespace).Create(ctx, secret, metav1.CreateOptions{})
+	} else {
+		secret = secret.DeepCopy()
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte)
+		}
+		for k, v := range data {
+			secret.Data[k] = v
+		}
+		_, err = c.client.CoreV1().Secrets(c.opts.SecretNamespace).Update(ctx, secret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+
+	log.V(logf.InfoLevel).Info("issued webhook serving certificate", "dnsNames", c.opts.DNSNames)
+	c.queue.AddAfter(key, c.opts.Duration*2/3)
+
+	return nil
+}
+
+// renewalTime returns the time at which the certificate stored in the given
+// Secret should be renewed, or an error if it must be reissued straight away
+// because it is invalid or does not match the configured DNS names.
+func (c *controller) renewalTime(secret *corev1.Secret) (time.Time, error) {
+	certPEM := secret.Data[corev1.TLSCertKey]
+	cert, err := pki.DecodeX509CertificateBytes(certPEM)
+	if err != nil {
+		return time.Time{}, err
+	}
+	key, err := pki.DecodePrivateKeyBytes(secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return time.Time{}, err
+	}
+	matches, err := pki.PublicKeyMatchesCertificate(key.Public(), cert)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !matches {
+		return time.Time{}, fmt.Errorf("private key does not match certificate")
+	}
+	if !bytes.Equal(secret.Data[cmmeta.TLSCAKey], certPEM) {
+		return time.Time{}, fmt.Errorf("CA certificate does not match certificate")
+	}
+	if !util.EqualUnsorted(cert.DNSNames, c.opts.DNSNames) {
+		return time.Time{}, fmt.Errorf("DNS names do not match %v", c.opts.DNSNames)
+	}
+
+	duration := cert.NotAfter.Sub(cert.NotBefore)
+	return cert.NotAfter.Add(-duration / 3), nil
+}
+
+// selfSign issues a self signed serving certificate for the given DNS names,
+// valid for the given duration from notBefore. It returns the PEM encoded
+// certificate and private key. The certificate is marked as a CA, so that it
+// can be used as its own trust anchor in the `ca.crt` key of the Secret.
+func selfSign(dnsNames []string, notBefore time.Time, duration time.Duration) ([]byte, []byte, error) {
+	crt := &cmapi.Certificate{
+		Spec: cmapi.CertificateSpec{
+			CommonName: dnsNames[0],
+			DNSNames:   dnsNames,
+			Duration:   &metav1.Duration{Duration: duration},
+			IsCA:       true,
+			PrivateKey: &cmapi.CertificatePrivateKey{Algorithm: cmapi.ECDSAKeyAlgorithm},
+			Usages:     []cmapi.KeyUsage{cmapi.UsageDigitalSignature, cmapi.UsageKeyEncipherment, cmapi.UsageServerAuth},
+		},
+	}
+
+	key, err := pki.GeneratePrivateKeyForCertificate(crt)
+	if err != nil {
+		return nil, nil, err
+	}
+	template, err := pki.GenerateTemplate(crt)
+	if err != nil {
+		return nil, nil, err
+	}
+	template.NotBefore = notBefore
+	template.NotAfter = notBefore.Add(duration)
+
+	certPEM, _, err := pki.SignCertificate(template, template, key.Public(), key)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM, err := pki.EncodePKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return certPEM, keyPEM, nil
+}
+
+// controllerWrapper wraps the `controller` structure to make it implement
+// the controllerpkg.queueingController interface
+type controllerWrapper struct {
+	*controller
+}
+
+func (c *controllerWrapper) Register(ctx *controllerpkg.Context) (workqueue.RateLimitingInterface, []cache.InformerSynced, error) {
+	// construct a new named logger to be reused throughout the controller
+	log := logf.FromContext(ctx.RootContext, ControllerName)
+
+	// The shared informer factory is restricted to --namespace, which may
+	// not contain the webhook Secret, so watch only that Secret using an
+	// informer factory of its own.
+	opts := ctx.WebhookBootstrapOptions
+	factory := informers.NewSharedInformerFactoryWithOptions(ctx.Client, resyncPeriod,
+		informers.WithNamespace(opts.SecretNamespace),
+		informers.WithTweakListOptions(func(listOpts *metav1.ListOptions) {
+			listOpts.FieldSelector = fields.OneTermEqualSelector("metadata.name", opts.SecretName).String()
+		}),
+	)
+
+	ctrl, queue, mustSync := NewController(log,
+		ctx.Client,
+		factory,
+		ctx.Clock,
+		opts,
+	)
+	c.controller = ctrl
+
+	factory.Start(ctx.StopCh)
+
+	return queue, mustSync, nil
+}
+
+func init() {
+	controllerpkg.Register(ControllerName, func(ctx *controllerpkg.Context) (controllerpkg.Interface, error) {
+		w := &controllerWrapper{}
+		return controllerpkg.NewBuilder(ctx, ControllerName).
+			For(w).
+			// the Secret is enqueued periodically so that it is created
+			// even if no Secret event is ever observed for it
+			With(func(ctx context.Context) { w.enqueueSecret(ctx) }, resyncPeriod).
+			Complete()
+	})
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhookbootstrap
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	coretesting "k8s.io/client-go/testing"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	controllerpkg "github.com/jetstack/cert-manager/pkg/controller"
+	testpkg "github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/pkg/util"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestProcessItem(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	dnsNames := []string{"cert-manager-webhook", "cert-manager-webhook.cert-manager.svc"}
+	duration := 365 * 24 * time.Hour
+
+	servingSecret := func(dnsNames []string, notBefore time.Time) *corev1.Secret {
+		certPEM, keyPEM, err := selfSign(dnsNames, notBefore, duration)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return gen.Secret("cert-manager-webhook-tls",
+			gen.SetSecretNamespace("cert-manager"),
+			gen.SetSecretData(map[string][]byte{
+				corev1.TLSCertKey:       certPEM,
+				corev1.TLSPrivateKeyKey: keyPEM,
+				cmmeta.TLSCAKey:         certPEM,
+			}),
+		)
+	}
+
+	// expectServingSecret matches an action that creates or updates the
+	// webhook serving Secret with a self signed certificate for dnsNames that
+	// was issued now and that verifies against the Secret's ca.crt.
+	expectServingSecret := func(action coretesting.Action) testpkg.Action {
+		return testpkg.NewCustomMatch(action, func(exp, actual coretesting.Action) error {
+			if exp.GetVerb() != actual.GetVerb() || exp.GetNamespace() != actual.GetNamespace() {
+				return fmt.Errorf("unexpected %s action in namespace %q", actual.GetVerb(), actual.GetNamespace())
+			}
+			secret := actual.(interface{ GetObject() runtime.Object }).GetObject().(*corev1.Secret)
+			if secret.Name != "cert-manager-webhook-tls" {
+				return fmt.Errorf("unexpected secret name %q", secret.Name)
+			}
+
+			cert, err := pki.DecodeX509CertificateBytes(secret.Data[corev1.TLSCertKey])
+			if err != nil {
+				return err
+			}
+			roots := x509.NewCertPool()
+			if !roots.AppendCertsFromPEM(secret.Data[cmmeta.TLSCAKey]) {
+				return fmt.Errorf("failed to parse ca.crt")
+			}
+			if _, err := cert.Verify(x509.VerifyOptions{
+				DNSName:     dnsNames[1],
+				Roots:       roots,
+				CurrentTime: now.Add(time.Minute),
+				KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+			}); err != nil {
+				return fmt.Errorf("certificate does not verify against ca.crt: %v", err)
+			}
+			if !util.EqualUnsorted(cert.DNSNames, dnsNames) {
+				return fmt.Errorf("expected DNS names %v but got %v", dnsNames, cert.DNSNames)
+			}
+			if !cert.NotBefore.Equal(now) || !cert.NotAfter.Equal(now.Add(duration)) {
+				return fmt.Errorf("unexpected validity %v to %v", cert.NotBefore, cert.NotAfter)
+			}
+			key, err := pki.DecodePrivateKeyBytes(secret.Data[corev1.TLSPrivateKeyKey])
+			if err != nil {
+				return err
+			}
+			if matches, err := pki.PublicKeyMatchesCertificate(key.Public(), cert); err != nil || !matches {
+				return fmt.Errorf("private key does not match certificate")
+			}
+			if string(secret.Data[cmmeta.TLSCAKey]) != string(secret.Data[corev1.TLSCertKey]) {
+				return fmt.Errorf("expected ca.crt to contain the self signed certificate")
+			}
+			return nil
+		})
+	}
+	secretsResource := corev1.SchemeGroupVersion.WithResource("secrets")
+
+	tests := map[string]struct {
+		key     string
+		secrets []runtime.Object
+		// namespace restricts the shared informers to a single namespace, as
+		// --namespace does
+		namespace string
+		// webhookDown causes all requests to the cert-manager API to fail,
+		// as they would if the webhook was not serving
+		webhookDown bool
+
+		expectedActions []testpkg.Action
+	}{
+		"do nothing for a Secret that is not the webhook bootstrap Secret": {
+			key:     "cert-manager/other",
+			secrets: []runtime.Object{gen.Secret("other", gen.SetSecretNamespace("cert-manager"))},
+		},
+		"create the Secret if it does not exist": {
+			key: "cert-manager/cert-manager-webhook-tls",
+			expectedActions: []testpkg.Action{
+				expectServingSecret(coretesting.NewCreateAction(secretsResource, "cert-manager", nil)),
+			},
+		},
+		"create the Secret without using the cert-manager API if the webhook is down": {
+			key:         "cert-manager/cert-manager-webhook-tls",
+			webhookDown: true,
+			expectedActions: []testpkg.Action{
+				expectServingSecret(coretesting.NewCreateAction(secretsResource, "cert-manager", nil)),
+			},
+		},
+		"do nothing if the certificate is up to date": {
+			key:     "cert-manager/cert-manager-webhook-tls",
+			secrets: []runtime.Object{servingSecret(dnsNames, now.Add(-time.Hour))},
+		},
+		"do nothing if the certificate is up to date and the shared informers are restricted to another namespace": {
+			key:       "cert-manager/cert-manager-webhook-tls",
+			secrets:   []runtime.Object{servingSecret(dnsNames, now.Add(-time.Hour))},
+			namespace: "default",
+		},
+		"reissue the certificate once two thirds of its duration has passed": {
+			key:     "cert-manager/cert-manager-webhook-tls",
+			secrets: []runtime.Object{servingSecret(dnsNames, now.Add(-duration*2/3))},
+			expectedActions: []testpkg.Action{
+				expectServingSecret(coretesting.NewUpdateAction(secretsResource, "cert-manager", nil)),
+			},
+		},
+		"reissue the certificate if the DNS names have changed": {
+			key:     "cert-manager/cert-manager-webhook-tls",
+			secrets: []runtime.Object{servingSecret(dnsNames[:1], now.Add(-time.Hour))},
+			expectedActions: []testpkg.Action{
+				expectServingSecret(coretesting.NewUpdateAction(secretsResource, "cert-manager", nil)),
+			},
+		},
+		"reissue the certificate if the Secret is invalid": {
+			key: "cert-manager/cert-manager-webhook-tls",
+			secrets: []runtime.Object{gen.Secret("cert-manager-webhook-tls",
+				gen.SetSecretNamespace("cert-manager"),
+				gen.SetSecretData(map[string][]byte{corev1.TLSCertKey: []byte("invalid")}),
+			)},
+			expectedActions: []testpkg.Action{
+				expectServingSecret(coretesting.NewUpdateAction(secretsResource, "cert-manager", nil)),
+			},
+		},
+		"reissue the certificate without using the cert-manager API if the webhook is down": {
+			key:         "cert-manager/cert-manager-webhook-tls",
+			secrets:     []runtime.Object{servingSecret(dnsNames, now.Add(-duration))},
+			webhookDown: true,
+			expectedActions: []testpkg.Action{
+				expectServingSecret(coretesting.NewUpdateAction(secretsResource, "cert-manager", nil)),
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Create and initialise a new unit test builder
+			builder := &testpkg.Builder{
+				T:               t,
+				Clock:           fakeclock.NewFakeClock(now),
+				KubeObjects:     test.secrets,
+				ExpectedActions: test.expectedActions,
+			}
+			builder.Init()
+			if test.namespace != "" {
+				builder.KubeSharedInformerFactory = kubeinformers.NewSharedInformerFactoryWithOptions(builder.Client, time.Hour, kubeinformers.WithNamespace(test.namespace))
+			}
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			builder.Context.StopCh = stopCh
+			builder.Context.WebhookBootstrapOptions = controllerpkg.WebhookBootstrapOptions{
+				SecretNamespace: "cert-manager",
+				SecretName:      "cert-manager-webhook-tls",
+				DNSNames:        dnsNames,
+				Duration:        duration,
+			}
+			if test.webhookDown {
+				for _, verb := range []string{"create", "update", "patch", "delete"} {
+					builder.FakeCMClient().PrependReactor(verb, "*", func(coretesting.Action) (bool, runtime.Object, error) {
+						return true, nil, errors.New("webhook unavailable")
+					})
+				}
+			}
+
+			// Register informers used by the controller using the registration wrapper
+			w := &controllerWrapper{}
+			_, mustSync, err := w.Register(builder.Context)
+			if err != nil {
+				t.Fatal(err)
+			}
+			builder.RegisterAdditionalSyncFuncs(mustSync...)
+			// Start the informers and begin processing updates
+			builder.Start()
+			defer builder.Stop()
+
+			if err := w.controller.ProcessItem(context.Background(), test.key); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if err := builder.AllActionsExecuted(); err != nil {
+				builder.T.Error(err)
+			}
+		})
+	}
+}
@@ -28,6 +28,18 @@ const (
 	//
 	// ValidateCAA enables CAA checking when issuing certificates
 	ValidateCAA featuregate.Feature = "ValidateCAA"
+
+	// alpha: v1.4
+	//
+	// ServiceDNSNames enables the `spec.serviceSelector` field on Certificates,
+	// which derives the dnsNames of a Certificate from the Services it selects.
+	ServiceDNSNames featuregate.Feature = "ServiceDNSNames"
+
+	// alpha: v1.4
+	//
+	// CertificateReplicas enables the `spec.replicas` field on Certificates,
+	// which expands a Certificate into one Certificate per ordinal.
+	CertificateReplicas featuregate.Feature = "CertificateReplicas"
 )
 
 func init() {
@@ -38,5 +50,7 @@ func init() {
 // To add a new feature, define a key for it above and add it here. The features will be
 // available throughout Kubernetes binaries.
 var defaultKubernetesFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
-	ValidateCAA: {Default: false, PreRelease: featuregate.Alpha},
+	ValidateCAA:         {Default: false, PreRelease: featuregate.Alpha},
+	ServiceDNSNames:     {Default: false, PreRelease: featuregate.Alpha},
+	CertificateReplicas: {Default: false, PreRelease: featuregate.Alpha},
 }
@@ -0,0 +1,179 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package healthz implements the readiness probe endpoint exposed by the
+// cert-manager controller.
+package healthz
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+const (
+	// ReadyzPath is the path the readiness endpoint is served on.
+	ReadyzPath = "/readyz"
+
+	serverShutdownTimeout = 5 * time.Second
+	serverReadTimeout     = 8 * time.Second
+	serverWriteTimeout    = 8 * time.Second
+	serverMaxHeaderBytes  = 1 << 20 // 1 MiB
+)
+
+// IssuerRef identifies the Issuer or ClusterIssuer that must be Ready for the
+// controller to report itself as ready. Namespace is ignored for
+// ClusterIssuers.
+type IssuerRef struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// ReadinessChecker reports the controller as ready to issue certificates
+// once it has acquired leadership and, if configured, the referenced issuer
+// has a Ready condition with status True.
+type ReadinessChecker struct {
+	log logr.Logger
+
+	// leading is set to 1 once this instance has acquired leadership.
+	leading int32
+
+	issuerRef           *IssuerRef
+	issuerLister        cmlisters.IssuerLister
+	clusterIssuerLister cmlisters.ClusterIssuerLister
+}
+
+// NewReadinessChecker returns a ReadinessChecker gating readiness on the
+// given issuer. If issuerRef is nil, readiness only depends on leadership.
+// Only the lister for the referenced kind of issuer is required.
+func NewReadinessChecker(log logr.Logger, issuerRef *IssuerRef, issuerLister cmlisters.IssuerLister, clusterIssuerLister cmlisters.ClusterIssuerLister) *ReadinessChecker {
+	return &ReadinessChecker{
+		log:                 log.WithName("readyz"),
+		issuerRef:           issuerRef,
+		issuerLister:        issuerLister,
+		clusterIssuerLister: clusterIssuerLister,
+	}
+}
+
+// SetLeading records that this instance has acquired leadership and is
+// running the controllers. Instances that do not perform leader election
+// should call this when starting the controllers.
+func (r *ReadinessChecker) SetLeading() {
+	atomic.StoreInt32(&r.leading, 1)
+}
+
+// Check returns an error describing why the controller is not ready, or nil
+// if it is ready.
+func (r *ReadinessChecker) Check() error {
+	if atomic.LoadInt32(&r.leading) == 0 {
+		return fmt.Errorf("leader election has not been acquired")
+	}
+
+	if r.issuerRef == nil {
+		return nil
+	}
+
+	var issuer cmapi.GenericIssuer
+	var err error
+	switch r.issuerRef.Kind {
+	case cmapi.ClusterIssuerKind:
+		issuer, err = r.clusterIssuerLister.Get(r.issuerRef.Name)
+	default:
+		issuer, err = r.issuerLister.Issuers(r.issuerRef.Namespace).Get(r.issuerRef.Name)
+	}
+	if err != nil {
+		return fmt.Errorf("error getting %s %q: %v", r.issuerRef.Kind, r.issuerRef.Name, err)
+	}
+
+	if !apiutil.IssuerHasCondition(issuer, cmapi.IssuerCondition{
+		Type:   cmapi.IssuerConditionReady,
+		Status: cmmeta.ConditionTrue,
+	}) {
+		return fmt.Errorf("%s %q is not Ready", r.issuerRef.Kind, r.issuerRef.Name)
+	}
+
+	return nil
+}
+
+// ServeHTTP responds with 200 if the controller is ready, and 503 with the
+// reason otherwise.
+func (r *ReadinessChecker) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	if err := r.Check(); err != nil {
+		r.log.V(logf.DebugLevel).Info("readiness check failed", "reason", err.Error())
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "ok")
+}
+
+// Start starts an HTTP server serving the readiness endpoint on the given
+// address.
+func (r *ReadinessChecker) Start(listenAddress string) (*http.Server, error) {
+	mux := http.NewServeMux()
+	mux.Handle(ReadyzPath, r)
+
+	ln, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	server := &http.Server{
+		Addr:           ln.Addr().String(),
+		ReadTimeout:    serverReadTimeout,
+		WriteTimeout:   serverWriteTimeout,
+		MaxHeaderBytes: serverMaxHeaderBytes,
+		Handler:        mux,
+	}
+
+	go func() {
+		log := r.log.WithValues("address", ln.Addr())
+		log.V(logf.InfoLevel).Info("listening for readiness probe connections")
+
+		if err := server.Serve(ln); err != nil && err != http.ErrServerClosed {
+			log.Error(err, "error running readiness probe server")
+		}
+	}()
+
+	return server, nil
+}
+
+// Shutdown gracefully stops the given readiness probe server.
+func (r *ReadinessChecker) Shutdown(server *http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), serverShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		r.log.Error(err, "readiness probe server shutdown failed")
+		return
+	}
+
+	r.log.V(logf.InfoLevel).Info("readiness probe server gracefully stopped")
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthz
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/client-go/tools/cache"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+	cminformers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
+	logtesting "github.com/jetstack/cert-manager/pkg/logs/testing"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func readyCondition(status cmmeta.ConditionStatus) gen.IssuerModifier {
+	return gen.AddIssuerCondition(cmapi.IssuerCondition{
+		Type:   cmapi.IssuerConditionReady,
+		Status: status,
+	})
+}
+
+func TestReadinessChecker(t *testing.T) {
+	tests := map[string]struct {
+		issuerRef   *IssuerRef
+		leading     bool
+		existing    []interface{}
+		expectReady bool
+	}{
+		"not ready before leader election is acquired": {
+			leading:     false,
+			expectReady: false,
+		},
+		"ready once leading if no issuer is configured": {
+			leading:     true,
+			expectReady: true,
+		},
+		"not ready if the issuer does not exist": {
+			issuerRef:   &IssuerRef{Kind: cmapi.IssuerKind, Namespace: gen.DefaultTestNamespace, Name: "default"},
+			leading:     true,
+			expectReady: false,
+		},
+		"not ready if the issuer is not Ready": {
+			issuerRef:   &IssuerRef{Kind: cmapi.IssuerKind, Namespace: gen.DefaultTestNamespace, Name: "default"},
+			leading:     true,
+			existing:    []interface{}{gen.Issuer("default", readyCondition(cmmeta.ConditionFalse))},
+			expectReady: false,
+		},
+		"not ready if the issuer is Ready but leader election has not been acquired": {
+			issuerRef:   &IssuerRef{Kind: cmapi.IssuerKind, Namespace: gen.DefaultTestNamespace, Name: "default"},
+			leading:     false,
+			existing:    []interface{}{gen.Issuer("default", readyCondition(cmmeta.ConditionTrue))},
+			expectReady: false,
+		},
+		"ready if leading and the issuer is Ready": {
+			issuerRef:   &IssuerRef{Kind: cmapi.IssuerKind, Namespace: gen.DefaultTestNamespace, Name: "default"},
+			leading:     true,
+			existing:    []interface{}{gen.Issuer("default", readyCondition(cmmeta.ConditionTrue))},
+			expectReady: true,
+		},
+		"not ready if only an issuer in another namespace is Ready": {
+			issuerRef:   &IssuerRef{Kind: cmapi.IssuerKind, Namespace: "other", Name: "default"},
+			leading:     true,
+			existing:    []interface{}{gen.Issuer("default", readyCondition(cmmeta.ConditionTrue))},
+			expectReady: false,
+		},
+		"ready if leading and the cluster issuer is Ready": {
+			issuerRef:   &IssuerRef{Kind: cmapi.ClusterIssuerKind, Name: "default"},
+			leading:     true,
+			existing:    []interface{}{gen.ClusterIssuer("default", readyCondition(cmmeta.ConditionTrue))},
+			expectReady: true,
+		},
+		"not ready if only an issuer with the same name as the cluster issuer is Ready": {
+			issuerRef:   &IssuerRef{Kind: cmapi.ClusterIssuerKind, Name: "default"},
+			leading:     true,
+			existing:    []interface{}{gen.Issuer("default", readyCondition(cmmeta.ConditionTrue))},
+			expectReady: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			checker, indexers := newTestChecker(t, test.issuerRef)
+			for _, obj := range test.existing {
+				indexer := indexers.issuers
+				if _, ok := obj.(*cmapi.ClusterIssuer); ok {
+					indexer = indexers.clusterIssuers
+				}
+				if err := indexer.Add(obj); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if test.leading {
+				checker.SetLeading()
+			}
+
+			err := checker.Check()
+			if test.expectReady && err != nil {
+				t.Errorf("expected to be ready but got: %v", err)
+			}
+			if !test.expectReady && err == nil {
+				t.Errorf("expected not to be ready")
+			}
+
+			rec := httptest.NewRecorder()
+			checker.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, ReadyzPath, nil))
+			expectedCode := http.StatusServiceUnavailable
+			if test.expectReady {
+				expectedCode = http.StatusOK
+			}
+			if rec.Code != expectedCode {
+				t.Errorf("expected status code %d but got %d", expectedCode, rec.Code)
+			}
+		})
+	}
+}
+
+func TestReadinessCheckerFollowsIssuerCondition(t *testing.T) {
+	issuerRef := &IssuerRef{Kind: cmapi.IssuerKind, Namespace: gen.DefaultTestNamespace, Name: "default"}
+	checker, indexers := newTestChecker(t, issuerRef)
+	checker.SetLeading()
+
+	issuer := gen.Issuer("default", readyCondition(cmmeta.ConditionFalse))
+	if err := indexers.issuers.Add(issuer); err != nil {
+		t.Fatal(err)
+	}
+	if err := checker.Check(); err == nil {
+		t.Fatalf("expected not to be ready while the issuer is not Ready")
+	}
+
+	issuer = gen.IssuerFrom(issuer, func(iss cmapi.GenericIssuer) {
+		iss.GetStatus().Conditions[0].Status = cmmeta.ConditionTrue
+	})
+	if err := indexers.issuers.Update(issuer); err != nil {
+		t.Fatal(err)
+	}
+	if err := checker.Check(); err != nil {
+		t.Fatalf("expected to be ready once the issuer is Ready but got: %v", err)
+	}
+
+	issuer = gen.IssuerFrom(issuer, func(iss cmapi.GenericIssuer) {
+		iss.GetStatus().Conditions[0].Status = cmmeta.ConditionFalse
+	})
+	if err := indexers.issuers.Update(issuer); err != nil {
+		t.Fatal(err)
+	}
+	if err := checker.Check(); err == nil {
+		t.Fatalf("expected not to be ready once the issuer is no longer Ready")
+	}
+}
+
+type testIndexers struct {
+	issuers        cache.Indexer
+	clusterIssuers cache.Indexer
+}
+
+func newTestChecker(t *testing.T, issuerRef *IssuerRef) (*ReadinessChecker, testIndexers) {
+	factory := cminformers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	issuers := factory.Certmanager().V1().Issuers()
+	clusterIssuers := factory.Certmanager().V1().ClusterIssuers()
+
+	checker := NewReadinessChecker(logtesting.TestLogger{T: t}, issuerRef, issuers.Lister(), clusterIssuers.Lister())
+	return checker, testIndexers{
+		issuers:        issuers.Informer().GetIndexer(),
+		clusterIssuers: clusterIssuers.Informer().GetIndexer(),
+	}
+}
@@ -0,0 +1,282 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package adcs implements a client for the Microsoft Active Directory
+// Certificate Services (ADCS) Certificate Authority Web Enrollment pages,
+// commonly served at https://<host>/certsrv.
+//
+// The client submits requests using the same forms as a browser
+// (certfnsh.asp) and retrieves issued certificates from certnew.cer,
+// authenticating using HTTP basic authentication. It relies on the structure
+// of these pages, which are not a documented API. The Certificate Enrollment
+// Web Service (MS-WSTEP), and Kerberos or NTLM authentication, are not
+// supported.
+package adcs
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	corelisters "k8s.io/client-go/listers/core/v1"
+
+	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/jetstack/cert-manager/pkg/util"
+)
+
+const (
+	usernameKey = "username"
+	passwordKey = "password"
+
+	// caCertRequestID is the request ID used by the web enrollment service to
+	// refer to the current certificate of the CA itself.
+	caCertRequestID = "CACert"
+
+	requestTimeout = time.Second * 30
+)
+
+var (
+	// ErrRequestPending is returned when a certificate request has been
+	// accepted by ADCS but is waiting for approval by a CA administrator.
+	ErrRequestPending = errors.New("certificate request is pending approval by a CA administrator")
+
+	// issuedRequestIDRegexp matches the link to download a certificate that
+	// has been issued.
+	issuedRequestIDRegexp = regexp.MustCompile(`certnew\.cer\?ReqID=(\d+)&`)
+	// pendingRequestIDRegexp matches the request ID of a certificate request
+	// that is waiting for approval.
+	pendingRequestIDRegexp = regexp.MustCompile(`Your Request Id is (\d+)`)
+	// dispositionRegexp matches the reason given by ADCS for denying a
+	// certificate request, or failing to process it.
+	dispositionRegexp = regexp.MustCompile(`The disposition message is "([^"]*)"`)
+)
+
+// ErrRequestDenied is returned when ADCS has denied a certificate request.
+// Retrying the same request will not succeed.
+type ErrRequestDenied struct {
+	Message string
+}
+
+func (e ErrRequestDenied) Error() string {
+	return fmt.Sprintf("certificate request was denied: %s", e.Message)
+}
+
+type ClientBuilder func(namespace string, secretsLister corelisters.SecretLister,
+	issuer v1.GenericIssuer) (Interface, error)
+
+type Interface interface {
+	// RequestCertificate submits the given PEM encoded CSR to ADCS and returns
+	// the ID of the request. If the certificate was issued immediately it is
+	// also returned, otherwise ErrRequestPending is returned and the
+	// certificate must later be collected using RetrieveCertificate.
+	RequestCertificate(ctx context.Context, csrPEM []byte) (requestID string, certPEM []byte, err error)
+
+	// RetrieveCertificate returns the PEM encoded certificate issued for the
+	// given request ID, or ErrRequestPending if it has not yet been issued.
+	RetrieveCertificate(ctx context.Context, requestID string) (certPEM []byte, err error)
+
+	// CACertificate returns the PEM encoded certificate of the issuing CA.
+	CACertificate(ctx context.Context) (caPEM []byte, err error)
+}
+
+var _ Interface = &ADCS{}
+
+type ADCS struct {
+	baseURL  string
+	template string
+	username string
+	password string
+
+	client *http.Client
+}
+
+// NewBuilder returns a ClientBuilder that builds clients which only trust the
+// certificates in the given PEM encoded CA bundle when connecting to ADCS,
+// unless the issuer specifies its own CA bundle. If both are empty, the
+// system root CAs are used.
+func NewBuilder(caBundle []byte) ClientBuilder {
+	return func(namespace string, secretsLister corelisters.SecretLister,
+		issuer v1.GenericIssuer) (Interface, error) {
+		return New(namespace, secretsLister, issuer, caBundle)
+	}
+}
+
+// New returns a client for the ADCS web enrollment service configured on the
+// given issuer, reading credentials from the given namespace.
+func New(namespace string, secretsLister corelisters.SecretLister,
+	issuer v1.GenericIssuer, backendCABundle []byte) (Interface, error) {
+	cfg := issuer.GetSpec().ADCSWebEnrollment
+	if cfg == nil {
+		return nil, fmt.Errorf("issuer %q does not have an ADCS configuration", issuer.GetObjectMeta().Name)
+	}
+
+	secret, err := secretsLister.Secrets(namespace).Get(cfg.CredentialsRef.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	username := string(secret.Data[usernameKey])
+	password := string(secret.Data[passwordKey])
+	if username == "" || password == "" {
+		return nil, fmt.Errorf("secret %s/%s must contain non-empty %q and %q keys",
+			namespace, cfg.CredentialsRef.Name, usernameKey, passwordKey)
+	}
+
+	caBundle := cfg.CABundle
+	if len(caBundle) == 0 {
+		caBundle = backendCABundle
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if len(caBundle) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("error loading ADCS CA bundle")
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return &ADCS{
+		baseURL:  strings.TrimSuffix(cfg.URL, "/"),
+		template: cfg.Template,
+		username: username,
+		password: password,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   requestTimeout,
+		},
+	}, nil
+}
+
+func (a *ADCS) RequestCertificate(ctx context.Context, csrPEM []byte) (string, []byte, error) {
+	form := url.Values{
+		"Mode":             {"newreq"},
+		"CertRequest":      {string(csrPEM)},
+		"CertAttrib":       {"CertificateTemplate:" + a.template},
+		"TargetStoreFlags": {"0"},
+		"SaveCert":         {"yes"},
+	}
+
+	body, err := a.do(ctx, http.MethodPost, "/certfnsh.asp", form)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if m := issuedRequestIDRegexp.FindSubmatch(body); m != nil {
+		requestID := string(m[1])
+		certPEM, err := a.RetrieveCertificate(ctx, requestID)
+		return requestID, certPEM, err
+	}
+
+	if m := pendingRequestIDRegexp.FindSubmatch(body); m != nil {
+		return string(m[1]), nil, ErrRequestPending
+	}
+
+	return "", nil, dispositionError(body)
+}
+
+func (a *ADCS) RetrieveCertificate(ctx context.Context, requestID string) ([]byte, error) {
+	return a.certificate(ctx, requestID)
+}
+
+func (a *ADCS) CACertificate(ctx context.Context) ([]byte, error) {
+	return a.certificate(ctx, caCertRequestID)
+}
+
+// certificate downloads the base64 (PEM) encoded certificate with the given
+// request ID. The web enrollment service responds with an HTML page rather
+// than a certificate if the request has not been issued.
+func (a *ADCS) certificate(ctx context.Context, requestID string) ([]byte, error) {
+	query := url.Values{
+		"ReqID": {requestID},
+		"Enc":   {"b64"},
+	}
+	if requestID == caCertRequestID {
+		query.Set("Renewal", "-1")
+	}
+
+	body, err := a.do(ctx, http.MethodGet, "/certnew.cer?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("-----BEGIN CERTIFICATE-----")) {
+		return body, nil
+	}
+
+	if bytes.Contains(body, []byte("Taken Under Submission")) || bytes.Contains(body, []byte("Certificate Pending")) {
+		return nil, ErrRequestPending
+	}
+
+	return nil, dispositionError(body)
+}
+
+// do performs a request against the web enrollment service. If form is not
+// nil it is sent as the URL encoded request body.
+func (a *ADCS) do(ctx context.Context, method, path string, form url.Values) ([]byte, error) {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, a.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.SetBasicAuth(a.username, a.password)
+	req.Header.Set("User-Agent", util.CertManagerUserAgent)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to perform request to ADCS: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response from ADCS: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from ADCS", resp.StatusCode)
+	}
+
+	return respBody, nil
+}
+
+// dispositionError returns an ErrRequestDenied if the given response contains
+// a disposition message from ADCS, or a generic error otherwise.
+func dispositionError(body []byte) error {
+	if m := dispositionRegexp.FindSubmatch(body); m != nil {
+		return ErrRequestDenied{Message: html.UnescapeString(string(m[1]))}
+	}
+	return errors.New("unexpected response from ADCS")
+}
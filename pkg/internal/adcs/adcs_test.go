@@ -0,0 +1,344 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adcs
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+	"github.com/jetstack/cert-manager/test/unit/listers"
+)
+
+const (
+	testUsername = "user"
+	testPassword = "pass"
+
+	// templates understood by the fake ADCS server that control how
+	// requests are handled.
+	templateIssue    = "Issue"
+	templateApproval = "Approval"
+	templateDeny     = "Deny"
+)
+
+type requestState int
+
+const (
+	statePending requestState = iota
+	stateIssued
+	stateDenied
+)
+
+// fakeADCS is a fake implementation of the ADCS web enrollment service.
+type fakeADCS struct {
+	t *testing.T
+
+	caPEM   []byte
+	certPEM []byte
+
+	lock     sync.Mutex
+	nextID   int
+	requests map[string]requestState
+}
+
+func newFakeADCS(t *testing.T) *fakeADCS {
+	return &fakeADCS{
+		t:        t,
+		caPEM:    generateTestCertificate(t, "ca"),
+		certPEM:  generateTestCertificate(t, "example.com"),
+		nextID:   1,
+		requests: make(map[string]requestState),
+	}
+}
+
+// approve simulates a CA administrator approving a pending request.
+func (f *fakeADCS) approve(requestID string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.requests[requestID] = stateIssued
+}
+
+func (f *fakeADCS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if user, pass, ok := r.BasicAuth(); !ok || user != testUsername || pass != testPassword {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/certsrv/certfnsh.asp":
+		if err := r.ParseForm(); err != nil {
+			f.t.Errorf("failed to parse form: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.PostForm.Get("Mode") != "newreq" || r.PostForm.Get("CertRequest") == "" {
+			f.t.Errorf("unexpected form: %v", r.PostForm)
+		}
+
+		id := strconv.Itoa(f.nextID)
+		f.nextID++
+
+		switch r.PostForm.Get("CertAttrib") {
+		case "CertificateTemplate:" + templateIssue:
+			f.requests[id] = stateIssued
+			fmt.Fprintf(w, `<html><a href="certnew.cer?ReqID=%s&amp;Enc=b64">Download certificate</a></html>`, id)
+		case "CertificateTemplate:" + templateApproval:
+			f.requests[id] = statePending
+			fmt.Fprintf(w, `<html><p>Your certificate request has been received. However, you must wait for an administrator to issue the certificate you requested.</p><p>Your Request Id is %s.</p></html>`, id)
+		default:
+			f.requests[id] = stateDenied
+			fmt.Fprint(w, `<html><p>The disposition message is "Denied by Policy Module  0x80094801, The request does not contain a certificate template extension or the CertificateTemplate request attribute.&quot;".</p></html>`)
+		}
+
+	case r.Method == http.MethodGet && r.URL.Path == "/certsrv/certnew.cer":
+		id := r.URL.Query().Get("ReqID")
+		if r.URL.Query().Get("Enc") != "b64" {
+			f.t.Errorf("expected base64 encoding to be requested")
+		}
+		if id == "CACert" {
+			w.Write(f.caPEM)
+			return
+		}
+
+		state, ok := f.requests[id]
+		switch {
+		case !ok:
+			fmt.Fprint(w, `<html><p>The disposition message is "Request not found".</p></html>`)
+		case state == statePending:
+			fmt.Fprint(w, `<html><h1>Certificate Pending</h1><p>Taken Under Submission</p></html>`)
+		case state == stateDenied:
+			fmt.Fprint(w, `<html><p>The disposition message is "Denied by Policy Module".</p></html>`)
+		default:
+			w.Write(f.certPEM)
+		}
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func generateTestCertificate(t *testing.T, cn string) []byte {
+	pk, err := pki.GenerateECPrivateKey(pki.ECCurve256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, pk.Public(), pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func newTestClient(serverURL, template string, secret *corev1.Secret) (Interface, error) {
+	issuer := gen.Issuer("adcs",
+		gen.SetIssuerADCSWebEnrollment(cmapi.ADCSWebEnrollmentIssuer{
+			URL:            serverURL + "/certsrv/",
+			Template:       template,
+			CredentialsRef: cmmeta.LocalObjectReference{Name: "adcs-credentials"},
+		}),
+	)
+	lister := listers.FakeSecretListerFrom(listers.NewFakeSecretLister(),
+		listers.SetFakeSecretNamespaceListerGet(secret, nil),
+	)
+	return New("test-namespace", lister, issuer, nil)
+}
+
+func credentialsSecret(username, password string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "adcs-credentials", Namespace: "test-namespace"},
+		Data: map[string][]byte{
+			usernameKey: []byte(username),
+			passwordKey: []byte(password),
+		},
+	}
+}
+
+func TestRequestCertificate(t *testing.T) {
+	csrPEM := []byte("-----BEGIN CERTIFICATE REQUEST-----\nMIIB\n-----END CERTIFICATE REQUEST-----\n")
+
+	tests := map[string]struct {
+		template          string
+		secret            *corev1.Secret
+		expectedRequestID string
+		expectCert        bool
+		expectErr         func(error) bool
+	}{
+		"a certificate issued immediately is returned": {
+			template:          templateIssue,
+			secret:            credentialsSecret(testUsername, testPassword),
+			expectedRequestID: "1",
+			expectCert:        true,
+		},
+		"a request requiring approval returns the request ID and a pending error": {
+			template:          templateApproval,
+			secret:            credentialsSecret(testUsername, testPassword),
+			expectedRequestID: "1",
+			expectErr: func(err error) bool {
+				return err == ErrRequestPending
+			},
+		},
+		"a denied request returns the disposition message": {
+			template: templateDeny,
+			secret:   credentialsSecret(testUsername, testPassword),
+			expectErr: func(err error) bool {
+				denied, ok := err.(ErrRequestDenied)
+				return ok && denied.Message == `Denied by Policy Module  0x80094801, The request does not contain a certificate template extension or the CertificateTemplate request attribute."`
+			},
+		},
+		"invalid credentials return an error": {
+			template: templateIssue,
+			secret:   credentialsSecret(testUsername, "wrong"),
+			expectErr: func(err error) bool {
+				_, denied := err.(ErrRequestDenied)
+				return err != nil && err != ErrRequestPending && !denied
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fake := newFakeADCS(t)
+			srv := httptest.NewServer(fake)
+			defer srv.Close()
+
+			client, err := newTestClient(srv.URL, test.template, test.secret)
+			if err != nil {
+				t.Fatalf("unexpected error building client: %v", err)
+			}
+
+			requestID, certPEM, err := client.RequestCertificate(context.TODO(), csrPEM)
+			if test.expectErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if test.expectErr != nil && !test.expectErr(err) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if requestID != test.expectedRequestID {
+				t.Errorf("expected request ID %q but got %q", test.expectedRequestID, requestID)
+			}
+			if test.expectCert && string(certPEM) != string(fake.certPEM) {
+				t.Errorf("expected certificate %q but got %q", fake.certPEM, certPEM)
+			}
+			if !test.expectCert && certPEM != nil {
+				t.Errorf("expected no certificate but got %q", certPEM)
+			}
+		})
+	}
+}
+
+func TestRetrieveCertificatePendingApproval(t *testing.T) {
+	fake := newFakeADCS(t)
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	client, err := newTestClient(srv.URL, templateApproval, credentialsSecret(testUsername, testPassword))
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	requestID, _, err := client.RequestCertificate(context.TODO(), []byte("csr"))
+	if err != ErrRequestPending {
+		t.Fatalf("expected request to be pending but got: %v", err)
+	}
+
+	// the certificate cannot be retrieved until it has been approved
+	if _, err := client.RetrieveCertificate(context.TODO(), requestID); err != ErrRequestPending {
+		t.Fatalf("expected request to still be pending but got: %v", err)
+	}
+
+	fake.approve(requestID)
+
+	certPEM, err := client.RetrieveCertificate(context.TODO(), requestID)
+	if err != nil {
+		t.Fatalf("unexpected error retrieving approved certificate: %v", err)
+	}
+	if string(certPEM) != string(fake.certPEM) {
+		t.Errorf("expected certificate %q but got %q", fake.certPEM, certPEM)
+	}
+
+	// requests that do not exist are reported as a disposition error
+	if _, err := client.RetrieveCertificate(context.TODO(), "100"); err == nil {
+		t.Errorf("expected an error retrieving an unknown request")
+	}
+}
+
+func TestCACertificate(t *testing.T) {
+	fake := newFakeADCS(t)
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	client, err := newTestClient(srv.URL, templateIssue, credentialsSecret(testUsername, testPassword))
+	if err != nil {
+		t.Fatalf("unexpected error building client: %v", err)
+	}
+
+	caPEM, err := client.CACertificate(context.TODO())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(caPEM) != string(fake.caPEM) {
+		t.Errorf("expected CA %q but got %q", fake.caPEM, caPEM)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := newTestClient("https://adcs.example.com", templateIssue, credentialsSecret("", "")); err == nil {
+		t.Errorf("expected an error when the credentials secret is missing keys")
+	}
+
+	issuer := gen.Issuer("adcs", gen.SetIssuerADCSWebEnrollment(cmapi.ADCSWebEnrollmentIssuer{
+		URL:            "https://adcs.example.com/certsrv",
+		Template:       templateIssue,
+		CredentialsRef: cmmeta.LocalObjectReference{Name: "adcs-credentials"},
+		CABundle:       []byte("not a certificate"),
+	}))
+	lister := listers.FakeSecretListerFrom(listers.NewFakeSecretLister(),
+		listers.SetFakeSecretNamespaceListerGet(credentialsSecret(testUsername, testPassword), nil),
+	)
+	if _, err := New("test-namespace", lister, issuer, nil); err == nil {
+		t.Errorf("expected an error when the CA bundle is invalid")
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+
+	"github.com/jetstack/cert-manager/pkg/internal/adcs"
+)
+
+var _ adcs.Interface = &ADCS{}
+
+type ADCS struct {
+	RequestCertificateFn  func(ctx context.Context, csrPEM []byte) (string, []byte, error)
+	RetrieveCertificateFn func(ctx context.Context, requestID string) ([]byte, error)
+	CACertificateFn       func(ctx context.Context) ([]byte, error)
+}
+
+func (a *ADCS) RequestCertificate(ctx context.Context, csrPEM []byte) (string, []byte, error) {
+	return a.RequestCertificateFn(ctx, csrPEM)
+}
+
+func (a *ADCS) RetrieveCertificate(ctx context.Context, requestID string) ([]byte, error) {
+	return a.RetrieveCertificateFn(ctx, requestID)
+}
+
+func (a *ADCS) CACertificate(ctx context.Context) ([]byte, error) {
+	return a.CACertificateFn(ctx)
+}
@@ -81,6 +81,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha2.ACMEChallengeSolverDNS01ZoneCredentials)(nil), (*acme.ACMEChallengeSolverDNS01ZoneCredentials)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ACMEChallengeSolverDNS01ZoneCredentials_To_acme_ACMEChallengeSolverDNS01ZoneCredentials(a.(*v1alpha2.ACMEChallengeSolverDNS01ZoneCredentials), b.(*acme.ACMEChallengeSolverDNS01ZoneCredentials), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*acme.ACMEChallengeSolverDNS01ZoneCredentials)(nil), (*v1alpha2.ACMEChallengeSolverDNS01ZoneCredentials)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_acme_ACMEChallengeSolverDNS01ZoneCredentials_To_v1alpha2_ACMEChallengeSolverDNS01ZoneCredentials(a.(*acme.ACMEChallengeSolverDNS01ZoneCredentials), b.(*v1alpha2.ACMEChallengeSolverDNS01ZoneCredentials), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha2.ACMEChallengeSolverHTTP01)(nil), (*acme.ACMEChallengeSolverHTTP01)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_ACMEChallengeSolverHTTP01_To_acme_ACMEChallengeSolverHTTP01(a.(*v1alpha2.ACMEChallengeSolverHTTP01), b.(*acme.ACMEChallengeSolverHTTP01), scope)
 	}); err != nil {
@@ -451,6 +461,11 @@ func autoConvert_v1alpha2_ACMEChallengeSolverDNS01_To_acme_ACMEChallengeSolverDN
 	out.AcmeDNS = (*acme.ACMEIssuerDNS01ProviderAcmeDNS)(unsafe.Pointer(in.AcmeDNS))
 	out.RFC2136 = (*acme.ACMEIssuerDNS01ProviderRFC2136)(unsafe.Pointer(in.RFC2136))
 	out.Webhook = (*acme.ACMEIssuerDNS01ProviderWebhook)(unsafe.Pointer(in.Webhook))
+	out.CleanupDelay = (*apismetav1.Duration)(unsafe.Pointer(in.CleanupDelay))
+	out.ValidateDNSSEC = in.ValidateDNSSEC
+	out.ZoneCredentials = *(*[]acme.ACMEChallengeSolverDNS01ZoneCredentials)(unsafe.Pointer(&in.ZoneCredentials))
+	out.PropagationThresholdPercent = (*int)(unsafe.Pointer(in.PropagationThresholdPercent))
+	out.ZoneName = in.ZoneName
 	return nil
 }
 
@@ -470,6 +485,11 @@ func autoConvert_acme_ACMEChallengeSolverDNS01_To_v1alpha2_ACMEChallengeSolverDN
 	out.AcmeDNS = (*v1alpha2.ACMEIssuerDNS01ProviderAcmeDNS)(unsafe.Pointer(in.AcmeDNS))
 	out.RFC2136 = (*v1alpha2.ACMEIssuerDNS01ProviderRFC2136)(unsafe.Pointer(in.RFC2136))
 	out.Webhook = (*v1alpha2.ACMEIssuerDNS01ProviderWebhook)(unsafe.Pointer(in.Webhook))
+	out.CleanupDelay = (*apismetav1.Duration)(unsafe.Pointer(in.CleanupDelay))
+	out.ValidateDNSSEC = in.ValidateDNSSEC
+	out.ZoneCredentials = *(*[]v1alpha2.ACMEChallengeSolverDNS01ZoneCredentials)(unsafe.Pointer(&in.ZoneCredentials))
+	out.PropagationThresholdPercent = (*int)(unsafe.Pointer(in.PropagationThresholdPercent))
+	out.ZoneName = in.ZoneName
 	return nil
 }
 
@@ -478,6 +498,28 @@ func Convert_acme_ACMEChallengeSolverDNS01_To_v1alpha2_ACMEChallengeSolverDNS01(
 	return autoConvert_acme_ACMEChallengeSolverDNS01_To_v1alpha2_ACMEChallengeSolverDNS01(in, out, s)
 }
 
+func autoConvert_v1alpha2_ACMEChallengeSolverDNS01ZoneCredentials_To_acme_ACMEChallengeSolverDNS01ZoneCredentials(in *v1alpha2.ACMEChallengeSolverDNS01ZoneCredentials, out *acme.ACMEChallengeSolverDNS01ZoneCredentials, s conversion.Scope) error {
+	out.DNSZones = *(*[]string)(unsafe.Pointer(&in.DNSZones))
+	out.SecretName = in.SecretName
+	return nil
+}
+
+// Convert_v1alpha2_ACMEChallengeSolverDNS01ZoneCredentials_To_acme_ACMEChallengeSolverDNS01ZoneCredentials is an autogenerated conversion function.
+func Convert_v1alpha2_ACMEChallengeSolverDNS01ZoneCredentials_To_acme_ACMEChallengeSolverDNS01ZoneCredentials(in *v1alpha2.ACMEChallengeSolverDNS01ZoneCredentials, out *acme.ACMEChallengeSolverDNS01ZoneCredentials, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ACMEChallengeSolverDNS01ZoneCredentials_To_acme_ACMEChallengeSolverDNS01ZoneCredentials(in, out, s)
+}
+
+func autoConvert_acme_ACMEChallengeSolverDNS01ZoneCredentials_To_v1alpha2_ACMEChallengeSolverDNS01ZoneCredentials(in *acme.ACMEChallengeSolverDNS01ZoneCredentials, out *v1alpha2.ACMEChallengeSolverDNS01ZoneCredentials, s conversion.Scope) error {
+	out.DNSZones = *(*[]string)(unsafe.Pointer(&in.DNSZones))
+	out.SecretName = in.SecretName
+	return nil
+}
+
+// Convert_acme_ACMEChallengeSolverDNS01ZoneCredentials_To_v1alpha2_ACMEChallengeSolverDNS01ZoneCredentials is an autogenerated conversion function.
+func Convert_acme_ACMEChallengeSolverDNS01ZoneCredentials_To_v1alpha2_ACMEChallengeSolverDNS01ZoneCredentials(in *acme.ACMEChallengeSolverDNS01ZoneCredentials, out *v1alpha2.ACMEChallengeSolverDNS01ZoneCredentials, s conversion.Scope) error {
+	return autoConvert_acme_ACMEChallengeSolverDNS01ZoneCredentials_To_v1alpha2_ACMEChallengeSolverDNS01ZoneCredentials(in, out, s)
+}
+
 func autoConvert_v1alpha2_ACMEChallengeSolverHTTP01_To_acme_ACMEChallengeSolverHTTP01(in *v1alpha2.ACMEChallengeSolverHTTP01, out *acme.ACMEChallengeSolverHTTP01, s conversion.Scope) error {
 	out.Ingress = (*acme.ACMEChallengeSolverHTTP01Ingress)(unsafe.Pointer(in.Ingress))
 	return nil
@@ -504,6 +546,7 @@ func autoConvert_v1alpha2_ACMEChallengeSolverHTTP01Ingress_To_acme_ACMEChallenge
 	out.Name = in.Name
 	out.PodTemplate = (*acme.ACMEChallengeSolverHTTP01IngressPodTemplate)(unsafe.Pointer(in.PodTemplate))
 	out.IngressTemplate = (*acme.ACMEChallengeSolverHTTP01IngressTemplate)(unsafe.Pointer(in.IngressTemplate))
+	out.ForceIPv6 = in.ForceIPv6
 	return nil
 }
 
@@ -518,6 +561,7 @@ func autoConvert_acme_ACMEChallengeSolverHTTP01Ingress_To_v1alpha2_ACMEChallenge
 	out.Name = in.Name
 	out.PodTemplate = (*v1alpha2.ACMEChallengeSolverHTTP01IngressPodTemplate)(unsafe.Pointer(in.PodTemplate))
 	out.IngressTemplate = (*v1alpha2.ACMEChallengeSolverHTTP01IngressTemplate)(unsafe.Pointer(in.IngressTemplate))
+	out.ForceIPv6 = in.ForceIPv6
 	return nil
 }
 
@@ -695,6 +739,9 @@ func autoConvert_v1alpha2_ACMEIssuer_To_acme_ACMEIssuer(in *v1alpha2.ACMEIssuer,
 	out.Solvers = *(*[]acme.ACMEChallengeSolver)(unsafe.Pointer(&in.Solvers))
 	out.DisableAccountKeyGeneration = in.DisableAccountKeyGeneration
 	out.EnableDurationFeature = in.EnableDurationFeature
+	out.JWSAlgorithm = acme.JWSAlgorithm(in.JWSAlgorithm)
+	out.CAContent = acme.ACMECAContent(in.CAContent)
+	out.NoncePoolSize = in.NoncePoolSize
 	return nil
 }
 
@@ -716,6 +763,9 @@ func autoConvert_acme_ACMEIssuer_To_v1alpha2_ACMEIssuer(in *acme.ACMEIssuer, out
 	out.Solvers = *(*[]v1alpha2.ACMEChallengeSolver)(unsafe.Pointer(&in.Solvers))
 	out.DisableAccountKeyGeneration = in.DisableAccountKeyGeneration
 	out.EnableDurationFeature = in.EnableDurationFeature
+	out.JWSAlgorithm = v1alpha2.JWSAlgorithm(in.JWSAlgorithm)
+	out.CAContent = v1alpha2.ACMECAContent(in.CAContent)
+	out.NoncePoolSize = in.NoncePoolSize
 	return nil
 }
 
@@ -149,6 +149,23 @@ func (in *ACMEChallengeSolverDNS01) DeepCopyInto(out *ACMEChallengeSolverDNS01)
 		*out = new(ACMEIssuerDNS01ProviderWebhook)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CleanupDelay != nil {
+		in, out := &in.CleanupDelay, &out.CleanupDelay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ZoneCredentials != nil {
+		in, out := &in.ZoneCredentials, &out.ZoneCredentials
+		*out = make([]ACMEChallengeSolverDNS01ZoneCredentials, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PropagationThresholdPercent != nil {
+		in, out := &in.PropagationThresholdPercent, &out.PropagationThresholdPercent
+		*out = new(int)
+		**out = **in
+	}
 	return
 }
 
@@ -162,6 +179,27 @@ func (in *ACMEChallengeSolverDNS01) DeepCopy() *ACMEChallengeSolverDNS01 {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ACMEChallengeSolverDNS01ZoneCredentials) DeepCopyInto(out *ACMEChallengeSolverDNS01ZoneCredentials) {
+	*out = *in
+	if in.DNSZones != nil {
+		in, out := &in.DNSZones, &out.DNSZones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ACMEChallengeSolverDNS01ZoneCredentials.
+func (in *ACMEChallengeSolverDNS01ZoneCredentials) DeepCopy() *ACMEChallengeSolverDNS01ZoneCredentials {
+	if in == nil {
+		return nil
+	}
+	out := new(ACMEChallengeSolverDNS01ZoneCredentials)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ACMEChallengeSolverHTTP01) DeepCopyInto(out *ACMEChallengeSolverHTTP01) {
 	*out = *in
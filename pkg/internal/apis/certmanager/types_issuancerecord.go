@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certmanager
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	cmmeta "github.com/jetstack/cert-manager/pkg/internal/apis/meta"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// An IssuanceRecord is an audit record of a certificate that has been issued
+// for a CertificateRequest.
+type IssuanceRecord struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+
+	// Details of the issued certificate.
+	Spec IssuanceRecordSpec
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// IssuanceRecordList is a list of IssuanceRecords
+type IssuanceRecordList struct {
+	metav1.TypeMeta
+	metav1.ListMeta
+
+	Items []IssuanceRecord
+}
+
+// IssuanceRecordSpec describes an issued certificate.
+type IssuanceRecordSpec struct {
+	// CertificateRequestName is the name of the CertificateRequest that the
+	// certificate was issued for.
+	CertificateRequestName string
+
+	// CertificateName is the name of the Certificate that created the
+	// CertificateRequest, if any.
+	CertificateName string
+
+	// IssuerRef is a reference to the issuer that issued the certificate.
+	IssuerRef cmmeta.ObjectReference
+
+	// SerialNumber is the serial number of the certificate, encoded as a
+	// hexadecimal string.
+	SerialNumber string
+
+	// CommonName is the common name of the certificate's subject.
+	CommonName string
+
+	// DNSNames is the list of DNS subjectAltNames of the certificate.
+	DNSNames []string
+
+	// IPAddresses is the list of IP address subjectAltNames of the certificate.
+	IPAddresses []string
+
+	// URIs is the list of URI subjectAltNames of the certificate.
+	URIs []string
+
+	// EmailAddresses is the list of email subjectAltNames of the certificate.
+	EmailAddresses []string
+
+	// NotBefore is the time at which the certificate becomes valid.
+	NotBefore *metav1.Time
+
+	// NotAfter is the time at which the certificate expires.
+	NotAfter *metav1.Time
+
+	// IssuedAt is the time at which cert-manager observed that the certificate
+	// had been issued.
+	IssuedAt *metav1.Time
+}
@@ -82,6 +82,21 @@ type IssuerList struct {
 // configuration required for the issuer.
 type IssuerSpec struct {
 	IssuerConfig
+
+	// ChainValidation configures cert-manager to verify that the certificate
+	// chain returned by the issuer builds to a trusted root before accepting an
+	// issued certificate. If not set, issued certificate chains are not verified.
+	ChainValidation *IssuerChainValidation
+}
+
+// IssuerChainValidation configures the verification of the certificate
+// chains returned by an issuer.
+type IssuerChainValidation struct {
+	// TrustAnchors is a PEM encoded bundle of CA certificates. A certificate
+	// returned by the issuer is only accepted if it can be verified up to one of
+	// these certificates, using the intermediate certificates returned with it.
+	// Otherwise the CertificateRequest is failed.
+	TrustAnchors []byte
 }
 
 type IssuerConfig struct {
@@ -105,6 +120,12 @@ type IssuerConfig struct {
 	// Venafi configures this issuer to sign certificates using a Venafi TPP
 	// or Venafi Cloud policy zone.
 	Venafi *VenafiIssuer
+
+	// ADCSWebEnrollment configures this issuer to request certificates from a
+	// Microsoft Active Directory Certificate Services (ADCS) certificate
+	// authority using its web enrollment pages. The ADCS Certificate Enrollment
+	// Web Service (MS-WSTEP) is not supported.
+	ADCSWebEnrollment *ADCSWebEnrollmentIssuer
 }
 
 // Configures an issuer to sign certificates using a Venafi TPP
@@ -123,6 +144,25 @@ type VenafiIssuer struct {
 	// Cloud specifies the Venafi cloud configuration settings.
 	// Only one of TPP or Cloud may be specified.
 	Cloud *VenafiCloud
+
+	// CustomFieldAnnotations maps annotations on CertificateRequests to Venafi
+	// custom fields, so that attributes such as the identity of the requestor
+	// are recorded with the certificate request in Venafi. Annotations on a
+	// Certificate are copied to its CertificateRequests. Annotations that are
+	// not present on a CertificateRequest are not sent.
+	CustomFieldAnnotations []VenafiCustomFieldAnnotation
+}
+
+// VenafiCustomFieldAnnotation maps an annotation on a CertificateRequest to
+// a Venafi custom field.
+type VenafiCustomFieldAnnotation struct {
+	// Annotation is the key of the annotation on the CertificateRequest whose
+	// value is used for the custom field.
+	Annotation string
+
+	// CustomField is the name of the Venafi custom field that is set to the
+	// value of the annotation.
+	CustomField string
 }
 
 // VenafiTPP defines connection configuration details for a Venafi TPP instance
@@ -155,6 +195,36 @@ type VenafiCloud struct {
 	APITokenSecretRef cmmeta.SecretKeySelector
 }
 
+// ADCSWebEnrollmentIssuer configures an issuer to request certificates from a
+// Microsoft Active Directory Certificate Services (ADCS) certificate authority
+// using its Certificate Authority Web Enrollment pages, by submitting the same
+// forms as a browser and authenticating using HTTP basic authentication.
+// The ADCS Certificate Enrollment Web Service (MS-WSTEP), and Kerberos or NTLM
+// authentication, are not supported.
+type ADCSWebEnrollmentIssuer struct {
+	// URL is the base URL of the ADCS web enrollment service, for example:
+	// "https://adcs.example.com/certsrv".
+	URL string
+
+	// Template is the name of the certificate template that certificates are
+	// requested with.
+	Template string
+
+	// CredentialsRef is a reference to a Secret containing the username and
+	// password used to authenticate to the web enrollment service using HTTP
+	// basic authentication.
+	// The secret must contain two keys, 'username' and 'password'.
+	CredentialsRef cmmeta.LocalObjectReference
+
+	// CABundle is a PEM encoded TLS certificate to use to verify connections to
+	// the web enrollment service.
+	// If specified, system roots will not be used and the issuing CA for the
+	// web enrollment service must be verifiable using the provided root.
+	// If not specified, the connection will be verified using the cert-manager
+	// system root certificates.
+	CABundle []byte
+}
+
 // Configures an issuer to 'self sign' certificates using the
 // private key used to create the CertificateRequest object.
 type SelfSignedIssuer struct {
@@ -162,8 +232,97 @@ type SelfSignedIssuer struct {
 	// the location of the CRL from which the revocation of this certificate can be checked.
 	// If not set certificate will be issued without CDP. Values are strings.
 	CRLDistributionPoints []string
+
+	// SubjectKeyIdentifierMethod selects how the subject key identifier of
+	// issued certificates is computed from their public key, as described in
+	// RFC 5280 section 4.2.1.2. Either `RFC5280Method1`, the SHA-1 hash of the
+	// public key, or `RFC5280Method2`, a 4-bit type field followed by the least
+	// significant 60 bits of the SHA-1 hash. If set, the subject key identifier
+	// is included in all issued certificates and the authority key identifier
+	// is set to the subject key identifier of the issuing certificate. If not
+	// set, only CA certificates are issued with a subject key identifier.
+	SubjectKeyIdentifierMethod SubjectKeyIdentifierMethod
+
+	// KeySeedSecretRef references a seed of at least 32 bytes stored in a
+	// Secret, from which the private keys of Certificates using this issuer are
+	// derived deterministically, so that re-creating a Certificate produces the
+	// same key and CA. Only ECDSA private keys are supported. Anyone with access
+	// to the seed can derive the private keys, so this is intended for
+	// reproducible test and bootstrap environments only and must not be used
+	// in production.
+	KeySeedSecretRef *cmmeta.SecretKeySelector
+
+	// CertificateProfile restricts the X.509 version and extensions of issued
+	// certificates for compatibility with clients, such as some embedded devices,
+	// that reject certificates with other extensions. Either `Minimal`, which
+	// issues v3 certificates with only the basic constraints, key usage and
+	// extended key usage extensions and a subject alternative name reduced to
+	// the DNS name matching the common name, or `V1`, which issues v1
+	// certificates without any extensions. If not set, certificates are issued
+	// with all extensions that apply.
+	CertificateProfile CertificateProfile
+
+	// CertificatePolicies is a list of certificate policies included in the
+	// certificatePolicies extension of all certificates issued by this issuer,
+	// regardless of the certificate policies requested by the Certificate.
+	CertificatePolicies []CertificatePolicy
+
+	// AllowedCertificatePolicies is a list of certificate policies that
+	// Certificates may request to be included in the certificatePolicies
+	// extension of the issued certificate. A requested policy is only issued
+	// if its policy ID is in this list, and it is issued as configured here,
+	// including its CPS URI. If not set, requested certificate policies are
+	// not issued.
+	AllowedCertificatePolicies []CertificatePolicy
 }
 
+// SubjectKeyIdentifierMethod is a method of computing the subject key
+// identifier of a certificate from its public key, as described in RFC 5280
+// section 4.2.1.2.
+type SubjectKeyIdentifierMethod string
+
+const (
+	// SubjectKeyIdentifierMethod1 computes the subject key identifier as the
+	// 160-bit SHA-1 hash of the value of the BIT STRING subjectPublicKey.
+	SubjectKeyIdentifierMethod1 SubjectKeyIdentifierMethod = "RFC5280Method1"
+
+	// SubjectKeyIdentifierMethod2 computes the subject key identifier as a
+	// four-bit type field with the value 0100 followed by the least
+	// significant 60 bits of the SHA-1 hash of the value of the BIT STRING
+	// subjectPublicKey.
+	SubjectKeyIdentifierMethod2 SubjectKeyIdentifierMethod = "RFC5280Method2"
+)
+
+// CertificateProfile selects the X.509 version and extensions of certificates
+// signed by an issuer, for compatibility with clients that only accept
+// certificates with a restricted set of extensions.
+type CertificateProfile string
+
+const (
+	// CertificateProfileMinimal issues X.509 v3 certificates that only include
+	// the basic constraints, key usage and extended key usage extensions, and
+	// a subject alternative name extension reduced to the DNS name matching the
+	// common name.
+	CertificateProfileMinimal CertificateProfile = "Minimal"
+
+	// CertificateProfileV1 issues X.509 v1 certificates without any
+	// extensions.
+	CertificateProfileV1 CertificateProfile = "V1"
+)
+
+// MaxLeafDurationPolicy selects how a CA issuer handles requests for
+// certificates longer than its maximum leaf certificate duration.
+type MaxLeafDurationPolicy string
+
+const (
+	// MaxLeafDurationPolicyClamp signs the certificate with the maximum
+	// duration instead of the requested one.
+	MaxLeafDurationPolicyClamp MaxLeafDurationPolicy = "Clamp"
+
+	// MaxLeafDurationPolicyReject fails the request.
+	MaxLeafDurationPolicyReject MaxLeafDurationPolicy = "Reject"
+)
+
 // Configures an issuer to sign certificates using a HashiCorp Vault
 // PKI backend.
 type VaultIssuer struct {
@@ -186,6 +345,12 @@ type VaultIssuer struct {
 	// plain HTTP protocol connection. If not set the system root certificates
 	// are used to validate the TLS connection.
 	CABundle []byte
+
+	// ExpectedRootCA is a PEM encoded bundle of root CA certificates. If set,
+	// the certificate chain returned by Vault for each signed certificate must
+	// build to one of these roots, otherwise issuance fails. This guards
+	// against the roots of the Vault PKI backend silently being changed.
+	ExpectedRootCA []byte
 }
 
 // Configuration used to authenticate with a Vault server.
@@ -198,8 +363,9 @@ type VaultAuth struct {
 	// with the role and secret stored in a Kubernetes Secret resource.
 	AppRole *VaultAppRole
 
-	// Kubernetes authenticates with Vault by passing the ServiceAccount
-	// token stored in the named Secret resource to the Vault server.
+	// Kubernetes authenticates with Vault by passing a ServiceAccount token,
+	// stored in the named Secret resource or read from a mounted file, to the
+	// Vault server.
 	Kubernetes *VaultKubernetesAuth
 }
 
@@ -222,7 +388,7 @@ type VaultAppRole struct {
 }
 
 // Authenticate against Vault using a Kubernetes ServiceAccount token stored in
-// a Secret.
+// a Secret, or read from a file mounted into the cert-manager controller pod.
 type VaultKubernetesAuth struct {
 	// The Vault mountPath here is the mount path to use when authenticating with
 	// Vault. For example, setting a value to `/v1/auth/foo`, will use the path
@@ -230,14 +396,24 @@ type VaultKubernetesAuth struct {
 	// default value "/v1/auth/kubernetes" will be used.
 	Path string
 
-	// The required Secret field containing a Kubernetes ServiceAccount JWT used
-	// for authenticating with Vault. Use of 'ambient credentials' is not
-	// supported.
+	// SecretRef is a reference to a key in a Secret resource containing a
+	// Kubernetes ServiceAccount JWT used for authenticating with Vault.
+	// Exactly one of secretRef or tokenPath must be specified.
 	SecretRef cmmeta.SecretKeySelector
 
 	// A required field containing the Vault Role to assume. A Role binds a
 	// Kubernetes ServiceAccount with a set of Vault policies.
 	Role string
+
+	// TokenPath is the path to a file containing a Kubernetes ServiceAccount
+	// JWT used for authenticating with Vault, such as a projected service
+	// account token volume mounted into the cert-manager controller pod. The
+	// file is read again each time cert-manager logs in to Vault, so rotated
+	// tokens are picked up. This is a form of 'ambient credentials', so it can
+	// only be used by Issuers if `--issuer-ambient-credentials` is set, and by
+	// ClusterIssuers unless `--cluster-issuer-ambient-credentials=false`.
+	// Exactly one of secretRef or tokenPath must be specified.
+	TokenPath string
 }
 
 type CAIssuer struct {
@@ -256,6 +432,93 @@ type CAIssuer struct {
 	// certificate will be issued with no OCSP servers set. For example, an
 	// OCSP server URL could be "http://ocsp.int-x3.letsencrypt.org".
 	OCSPServers []string
+
+	// AllowCAIssuance permits this issuer to sign certificates that are
+	// themselves CA certificates (i.e. requests with isCA set to true).
+	// If not set, CertificateRequests for CA certificates will be failed to
+	// prevent intermediate CAs being minted from this issuer unintentionally.
+	AllowCAIssuance bool
+
+	// CRL configures the issuer to maintain a certificate revocation list
+	// listing the certificates it has revoked, and publish it to a Secret.
+	// cert-manager does not serve the CRL itself, so `crlDistributionPoints`
+	// should be set to a URL at which the contents of the Secret are served.
+	// If not set, no CRL is maintained.
+	CRL *CACRL
+
+	// SubjectKeyIdentifierMethod selects how the subject key identifier of
+	// issued certificates is computed from their public key, as described in
+	// RFC 5280 section 4.2.1.2. Either `RFC5280Method1`, the SHA-1 hash of the
+	// public key, or `RFC5280Method2`, a 4-bit type field followed by the least
+	// significant 60 bits of the SHA-1 hash. If set, the subject key identifier
+	// is included in all issued certificates and the authority key identifier
+	// is set to the subject key identifier of the issuing certificate. If not
+	// set, only CA certificates are issued with a subject key identifier.
+	SubjectKeyIdentifierMethod SubjectKeyIdentifierMethod
+
+	// MaxLeafDuration is the maximum duration of the non-CA certificates signed
+	// by this issuer, regardless of the duration requested. Requests for longer
+	// certificates are handled according to `maxLeafDurationPolicy`. If not set,
+	// the requested duration is used.
+	MaxLeafDuration *metav1.Duration
+
+	// MaxLeafDurationPolicy selects how requests for certificates longer than
+	// `maxLeafDuration` are handled. Either `Clamp`, which signs the certificate
+	// with a duration of `maxLeafDuration` and records an event, or `Reject`,
+	// which fails the request. Defaults to `Clamp`.
+	MaxLeafDurationPolicy MaxLeafDurationPolicy
+
+	// CertificateProfile restricts the X.509 version and extensions of issued
+	// certificates for compatibility with clients, such as some embedded devices,
+	// that reject certificates with other extensions. Either `Minimal`, which
+	// issues v3 certificates with only the basic constraints, key usage and
+	// extended key usage extensions and a subject alternative name reduced to
+	// the DNS name matching the common name, or `V1`, which issues v1
+	// certificates without any extensions. If not set, certificates are issued
+	// with all extensions that apply.
+	CertificateProfile CertificateProfile
+
+	// CertificatePolicies is a list of certificate policies included in the
+	// certificatePolicies extension of all certificates issued by this issuer,
+	// regardless of the certificate policies requested by the Certificate.
+	CertificatePolicies []CertificatePolicy
+
+	// AllowedCertificatePolicies is a list of certificate policies that
+	// Certificates may request to be included in the certificatePolicies
+	// extension of the issued certificate. A requested policy is only issued
+	// if its policy ID is in this list, and it is issued as configured here,
+	// including its CPS URI. If not set, requested certificate policies are
+	// not issued.
+	AllowedCertificatePolicies []CertificatePolicy
+}
+
+// CACRL configures a certificate revocation list (CRL) maintained by a CA
+// issuer.
+type CACRL struct {
+	// SecretName is the name of the Secret that the PEM encoded CRL will be
+	// published to under the `ca.crl` key. For ClusterIssuers, the Secret is
+	// created in the cluster resource namespace.
+	SecretName string
+
+	// Validity is how long each generated CRL is valid for, i.e. the time
+	// between its thisUpdate and nextUpdate fields. The CRL is regenerated once
+	// half of this time has passed. Defaults to 24 hours.
+	Validity *metav1.Duration
+
+	// RevokedCertificates is the list of certificates signed by this issuer
+	// that have been revoked and will be listed in the CRL.
+	RevokedCertificates []CARevokedCertificate
+}
+
+// CARevokedCertificate identifies a revoked certificate to be listed in a
+// CA issuer's CRL.
+type CARevokedCertificate struct {
+	// SerialNumber is the hex encoded serial number of the revoked certificate,
+	// optionally separated by colons, e.g. "1f:2a:03".
+	SerialNumber string
+
+	// RevocationTime is the time at which the certificate was revoked.
+	RevocationTime metav1.Time
 }
 
 // IssuerStatus contains status information about an Issuer
@@ -41,6 +41,26 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*v1.ADCSWebEnrollmentIssuer)(nil), (*certmanager.ADCSWebEnrollmentIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_ADCSWebEnrollmentIssuer_To_certmanager_ADCSWebEnrollmentIssuer(a.(*v1.ADCSWebEnrollmentIssuer), b.(*certmanager.ADCSWebEnrollmentIssuer), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.ADCSWebEnrollmentIssuer)(nil), (*v1.ADCSWebEnrollmentIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_ADCSWebEnrollmentIssuer_To_v1_ADCSWebEnrollmentIssuer(a.(*certmanager.ADCSWebEnrollmentIssuer), b.(*v1.ADCSWebEnrollmentIssuer), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.CACRL)(nil), (*certmanager.CACRL)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_CACRL_To_certmanager_CACRL(a.(*v1.CACRL), b.(*certmanager.CACRL), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CACRL)(nil), (*v1.CACRL)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CACRL_To_v1_CACRL(a.(*certmanager.CACRL), b.(*v1.CACRL), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1.CAIssuer)(nil), (*certmanager.CAIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_CAIssuer_To_certmanager_CAIssuer(a.(*v1.CAIssuer), b.(*certmanager.CAIssuer), scope)
 	}); err != nil {
@@ -51,6 +71,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1.CARevokedCertificate)(nil), (*certmanager.CARevokedCertificate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_CARevokedCertificate_To_certmanager_CARevokedCertificate(a.(*v1.CARevokedCertificate), b.(*certmanager.CARevokedCertificate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CARevokedCertificate)(nil), (*v1.CARevokedCertificate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CARevokedCertificate_To_v1_CARevokedCertificate(a.(*certmanager.CARevokedCertificate), b.(*v1.CARevokedCertificate), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1.Certificate)(nil), (*certmanager.Certificate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_Certificate_To_certmanager_Certificate(a.(*v1.Certificate), b.(*certmanager.Certificate), scope)
 	}); err != nil {
@@ -91,6 +121,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1.CertificateOCSPStapling)(nil), (*certmanager.CertificateOCSPStapling)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_CertificateOCSPStapling_To_certmanager_CertificateOCSPStapling(a.(*v1.CertificateOCSPStapling), b.(*certmanager.CertificateOCSPStapling), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificateOCSPStapling)(nil), (*v1.CertificateOCSPStapling)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificateOCSPStapling_To_v1_CertificateOCSPStapling(a.(*certmanager.CertificateOCSPStapling), b.(*v1.CertificateOCSPStapling), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.CertificatePolicy)(nil), (*certmanager.CertificatePolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_CertificatePolicy_To_certmanager_CertificatePolicy(a.(*v1.CertificatePolicy), b.(*certmanager.CertificatePolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificatePolicy)(nil), (*v1.CertificatePolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificatePolicy_To_v1_CertificatePolicy(a.(*certmanager.CertificatePolicy), b.(*v1.CertificatePolicy), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1.CertificatePrivateKey)(nil), (*certmanager.CertificatePrivateKey)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_CertificatePrivateKey_To_certmanager_CertificatePrivateKey(a.(*v1.CertificatePrivateKey), b.(*certmanager.CertificatePrivateKey), scope)
 	}); err != nil {
@@ -101,6 +151,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1.CertificateRenewalRecord)(nil), (*certmanager.CertificateRenewalRecord)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_CertificateRenewalRecord_To_certmanager_CertificateRenewalRecord(a.(*v1.CertificateRenewalRecord), b.(*certmanager.CertificateRenewalRecord), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificateRenewalRecord)(nil), (*v1.CertificateRenewalRecord)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificateRenewalRecord_To_v1_CertificateRenewalRecord(a.(*certmanager.CertificateRenewalRecord), b.(*v1.CertificateRenewalRecord), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.CertificateRenewalSchedule)(nil), (*certmanager.CertificateRenewalSchedule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_CertificateRenewalSchedule_To_certmanager_CertificateRenewalSchedule(a.(*v1.CertificateRenewalSchedule), b.(*certmanager.CertificateRenewalSchedule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificateRenewalSchedule)(nil), (*v1.CertificateRenewalSchedule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificateRenewalSchedule_To_v1_CertificateRenewalSchedule(a.(*certmanager.CertificateRenewalSchedule), b.(*v1.CertificateRenewalSchedule), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1.CertificateRequest)(nil), (*certmanager.CertificateRequest)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_CertificateRequest_To_certmanager_CertificateRequest(a.(*v1.CertificateRequest), b.(*certmanager.CertificateRequest), scope)
 	}); err != nil {
@@ -151,6 +221,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1.CertificateSecretTemplate)(nil), (*certmanager.CertificateSecretTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_CertificateSecretTemplate_To_certmanager_CertificateSecretTemplate(a.(*v1.CertificateSecretTemplate), b.(*certmanager.CertificateSecretTemplate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificateSecretTemplate)(nil), (*v1.CertificateSecretTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificateSecretTemplate_To_v1_CertificateSecretTemplate(a.(*certmanager.CertificateSecretTemplate), b.(*v1.CertificateSecretTemplate), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1.CertificateStatus)(nil), (*certmanager.CertificateStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_CertificateStatus_To_certmanager_CertificateStatus(a.(*v1.CertificateStatus), b.(*certmanager.CertificateStatus), scope)
 	}); err != nil {
@@ -181,6 +261,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1.IssuanceRecord)(nil), (*certmanager.IssuanceRecord)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_IssuanceRecord_To_certmanager_IssuanceRecord(a.(*v1.IssuanceRecord), b.(*certmanager.IssuanceRecord), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.IssuanceRecord)(nil), (*v1.IssuanceRecord)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_IssuanceRecord_To_v1_IssuanceRecord(a.(*certmanager.IssuanceRecord), b.(*v1.IssuanceRecord), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.IssuanceRecordList)(nil), (*certmanager.IssuanceRecordList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_IssuanceRecordList_To_certmanager_IssuanceRecordList(a.(*v1.IssuanceRecordList), b.(*certmanager.IssuanceRecordList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.IssuanceRecordList)(nil), (*v1.IssuanceRecordList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_IssuanceRecordList_To_v1_IssuanceRecordList(a.(*certmanager.IssuanceRecordList), b.(*v1.IssuanceRecordList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1.IssuanceRecordSpec)(nil), (*certmanager.IssuanceRecordSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_IssuanceRecordSpec_To_certmanager_IssuanceRecordSpec(a.(*v1.IssuanceRecordSpec), b.(*certmanager.IssuanceRecordSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.IssuanceRecordSpec)(nil), (*v1.IssuanceRecordSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_IssuanceRecordSpec_To_v1_IssuanceRecordSpec(a.(*certmanager.IssuanceRecordSpec), b.(*v1.IssuanceRecordSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1.Issuer)(nil), (*certmanager.Issuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_Issuer_To_certmanager_Issuer(a.(*v1.Issuer), b.(*certmanager.Issuer), scope)
 	}); err != nil {
@@ -191,6 +301,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1.IssuerChainValidation)(nil), (*certmanager.IssuerChainValidation)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_IssuerChainValidation_To_certmanager_IssuerChainValidation(a.(*v1.IssuerChainValidation), b.(*certmanager.IssuerChainValidation), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.IssuerChainValidation)(nil), (*v1.IssuerChainValidation)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_IssuerChainValidation_To_v1_IssuerChainValidation(a.(*certmanager.IssuerChainValidation), b.(*v1.IssuerChainValidation), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1.IssuerCondition)(nil), (*certmanager.IssuerCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_IssuerCondition_To_certmanager_IssuerCondition(a.(*v1.IssuerCondition), b.(*certmanager.IssuerCondition), scope)
 	}); err != nil {
@@ -321,6 +441,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1.VenafiCustomFieldAnnotation)(nil), (*certmanager.VenafiCustomFieldAnnotation)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_VenafiCustomFieldAnnotation_To_certmanager_VenafiCustomFieldAnnotation(a.(*v1.VenafiCustomFieldAnnotation), b.(*certmanager.VenafiCustomFieldAnnotation), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.VenafiCustomFieldAnnotation)(nil), (*v1.VenafiCustomFieldAnnotation)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_VenafiCustomFieldAnnotation_To_v1_VenafiCustomFieldAnnotation(a.(*certmanager.VenafiCustomFieldAnnotation), b.(*v1.VenafiCustomFieldAnnotation), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1.VenafiIssuer)(nil), (*certmanager.VenafiIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_VenafiIssuer_To_certmanager_VenafiIssuer(a.(*v1.VenafiIssuer), b.(*certmanager.VenafiIssuer), scope)
 	}); err != nil {
@@ -364,10 +494,74 @@ func RegisterConversions(s *runtime.Scheme) error {
 	return nil
 }
 
+func autoConvert_v1_ADCSWebEnrollmentIssuer_To_certmanager_ADCSWebEnrollmentIssuer(in *v1.ADCSWebEnrollmentIssuer, out *certmanager.ADCSWebEnrollmentIssuer, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Template = in.Template
+	// TODO: Inefficient conversion - can we improve it?
+	if err := s.Convert(&in.CredentialsRef, &out.CredentialsRef, 0); err != nil {
+		return err
+	}
+	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	return nil
+}
+
+// Convert_v1_ADCSWebEnrollmentIssuer_To_certmanager_ADCSWebEnrollmentIssuer is an autogenerated conversion function.
+func Convert_v1_ADCSWebEnrollmentIssuer_To_certmanager_ADCSWebEnrollmentIssuer(in *v1.ADCSWebEnrollmentIssuer, out *certmanager.ADCSWebEnrollmentIssuer, s conversion.Scope) error {
+	return autoConvert_v1_ADCSWebEnrollmentIssuer_To_certmanager_ADCSWebEnrollmentIssuer(in, out, s)
+}
+
+func autoConvert_certmanager_ADCSWebEnrollmentIssuer_To_v1_ADCSWebEnrollmentIssuer(in *certmanager.ADCSWebEnrollmentIssuer, out *v1.ADCSWebEnrollmentIssuer, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Template = in.Template
+	// TODO: Inefficient conversion - can we improve it?
+	if err := s.Convert(&in.CredentialsRef, &out.CredentialsRef, 0); err != nil {
+		return err
+	}
+	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	return nil
+}
+
+// Convert_certmanager_ADCSWebEnrollmentIssuer_To_v1_ADCSWebEnrollmentIssuer is an autogenerated conversion function.
+func Convert_certmanager_ADCSWebEnrollmentIssuer_To_v1_ADCSWebEnrollmentIssuer(in *certmanager.ADCSWebEnrollmentIssuer, out *v1.ADCSWebEnrollmentIssuer, s conversion.Scope) error {
+	return autoConvert_certmanager_ADCSWebEnrollmentIssuer_To_v1_ADCSWebEnrollmentIssuer(in, out, s)
+}
+
+func autoConvert_v1_CACRL_To_certmanager_CACRL(in *v1.CACRL, out *certmanager.CACRL, s conversion.Scope) error {
+	out.SecretName = in.SecretName
+	out.Validity = (*metav1.Duration)(unsafe.Pointer(in.Validity))
+	out.RevokedCertificates = *(*[]certmanager.CARevokedCertificate)(unsafe.Pointer(&in.RevokedCertificates))
+	return nil
+}
+
+// Convert_v1_CACRL_To_certmanager_CACRL is an autogenerated conversion function.
+func Convert_v1_CACRL_To_certmanager_CACRL(in *v1.CACRL, out *certmanager.CACRL, s conversion.Scope) error {
+	return autoConvert_v1_CACRL_To_certmanager_CACRL(in, out, s)
+}
+
+func autoConvert_certmanager_CACRL_To_v1_CACRL(in *certmanager.CACRL, out *v1.CACRL, s conversion.Scope) error {
+	out.SecretName = in.SecretName
+	out.Validity = (*metav1.Duration)(unsafe.Pointer(in.Validity))
+	out.RevokedCertificates = *(*[]v1.CARevokedCertificate)(unsafe.Pointer(&in.RevokedCertificates))
+	return nil
+}
+
+// Convert_certmanager_CACRL_To_v1_CACRL is an autogenerated conversion function.
+func Convert_certmanager_CACRL_To_v1_CACRL(in *certmanager.CACRL, out *v1.CACRL, s conversion.Scope) error {
+	return autoConvert_certmanager_CACRL_To_v1_CACRL(in, out, s)
+}
+
 func autoConvert_v1_CAIssuer_To_certmanager_CAIssuer(in *v1.CAIssuer, out *certmanager.CAIssuer, s conversion.Scope) error {
 	out.SecretName = in.SecretName
 	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
 	out.OCSPServers = *(*[]string)(unsafe.Pointer(&in.OCSPServers))
+	out.AllowCAIssuance = in.AllowCAIssuance
+	out.CRL = (*certmanager.CACRL)(unsafe.Pointer(in.CRL))
+	out.SubjectKeyIdentifierMethod = certmanager.SubjectKeyIdentifierMethod(in.SubjectKeyIdentifierMethod)
+	out.MaxLeafDuration = (*metav1.Duration)(unsafe.Pointer(in.MaxLeafDuration))
+	out.MaxLeafDurationPolicy = certmanager.MaxLeafDurationPolicy(in.MaxLeafDurationPolicy)
+	out.CertificateProfile = certmanager.CertificateProfile(in.CertificateProfile)
+	out.CertificatePolicies = *(*[]certmanager.CertificatePolicy)(unsafe.Pointer(&in.CertificatePolicies))
+	out.AllowedCertificatePolicies = *(*[]certmanager.CertificatePolicy)(unsafe.Pointer(&in.AllowedCertificatePolicies))
 	return nil
 }
 
@@ -380,6 +574,14 @@ func autoConvert_certmanager_CAIssuer_To_v1_CAIssuer(in *certmanager.CAIssuer, o
 	out.SecretName = in.SecretName
 	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
 	out.OCSPServers = *(*[]string)(unsafe.Pointer(&in.OCSPServers))
+	out.AllowCAIssuance = in.AllowCAIssuance
+	out.CRL = (*v1.CACRL)(unsafe.Pointer(in.CRL))
+	out.SubjectKeyIdentifierMethod = v1.SubjectKeyIdentifierMethod(in.SubjectKeyIdentifierMethod)
+	out.MaxLeafDuration = (*metav1.Duration)(unsafe.Pointer(in.MaxLeafDuration))
+	out.MaxLeafDurationPolicy = v1.MaxLeafDurationPolicy(in.MaxLeafDurationPolicy)
+	out.CertificateProfile = v1.CertificateProfile(in.CertificateProfile)
+	out.CertificatePolicies = *(*[]v1.CertificatePolicy)(unsafe.Pointer(&in.CertificatePolicies))
+	out.AllowedCertificatePolicies = *(*[]v1.CertificatePolicy)(unsafe.Pointer(&in.AllowedCertificatePolicies))
 	return nil
 }
 
@@ -388,6 +590,28 @@ func Convert_certmanager_CAIssuer_To_v1_CAIssuer(in *certmanager.CAIssuer, out *
 	return autoConvert_certmanager_CAIssuer_To_v1_CAIssuer(in, out, s)
 }
 
+func autoConvert_v1_CARevokedCertificate_To_certmanager_CARevokedCertificate(in *v1.CARevokedCertificate, out *certmanager.CARevokedCertificate, s conversion.Scope) error {
+	out.SerialNumber = in.SerialNumber
+	out.RevocationTime = in.RevocationTime
+	return nil
+}
+
+// Convert_v1_CARevokedCertificate_To_certmanager_CARevokedCertificate is an autogenerated conversion function.
+func Convert_v1_CARevokedCertificate_To_certmanager_CARevokedCertificate(in *v1.CARevokedCertificate, out *certmanager.CARevokedCertificate, s conversion.Scope) error {
+	return autoConvert_v1_CARevokedCertificate_To_certmanager_CARevokedCertificate(in, out, s)
+}
+
+func autoConvert_certmanager_CARevokedCertificate_To_v1_CARevokedCertificate(in *certmanager.CARevokedCertificate, out *v1.CARevokedCertificate, s conversion.Scope) error {
+	out.SerialNumber = in.SerialNumber
+	out.RevocationTime = in.RevocationTime
+	return nil
+}
+
+// Convert_certmanager_CARevokedCertificate_To_v1_CARevokedCertificate is an autogenerated conversion function.
+func Convert_certmanager_CARevokedCertificate_To_v1_CARevokedCertificate(in *certmanager.CARevokedCertificate, out *v1.CARevokedCertificate, s conversion.Scope) error {
+	return autoConvert_certmanager_CARevokedCertificate_To_v1_CARevokedCertificate(in, out, s)
+}
+
 func autoConvert_v1_Certificate_To_certmanager_Certificate(in *v1.Certificate, out *certmanager.Certificate, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1_CertificateSpec_To_certmanager_CertificateSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -514,6 +738,48 @@ func Convert_certmanager_CertificateList_To_v1_CertificateList(in *certmanager.C
 	return autoConvert_certmanager_CertificateList_To_v1_CertificateList(in, out, s)
 }
 
+func autoConvert_v1_CertificateOCSPStapling_To_certmanager_CertificateOCSPStapling(in *v1.CertificateOCSPStapling, out *certmanager.CertificateOCSPStapling, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	return nil
+}
+
+// Convert_v1_CertificateOCSPStapling_To_certmanager_CertificateOCSPStapling is an autogenerated conversion function.
+func Convert_v1_CertificateOCSPStapling_To_certmanager_CertificateOCSPStapling(in *v1.CertificateOCSPStapling, out *certmanager.CertificateOCSPStapling, s conversion.Scope) error {
+	return autoConvert_v1_CertificateOCSPStapling_To_certmanager_CertificateOCSPStapling(in, out, s)
+}
+
+func autoConvert_certmanager_CertificateOCSPStapling_To_v1_CertificateOCSPStapling(in *certmanager.CertificateOCSPStapling, out *v1.CertificateOCSPStapling, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	return nil
+}
+
+// Convert_certmanager_CertificateOCSPStapling_To_v1_CertificateOCSPStapling is an autogenerated conversion function.
+func Convert_certmanager_CertificateOCSPStapling_To_v1_CertificateOCSPStapling(in *certmanager.CertificateOCSPStapling, out *v1.CertificateOCSPStapling, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificateOCSPStapling_To_v1_CertificateOCSPStapling(in, out, s)
+}
+
+func autoConvert_v1_CertificatePolicy_To_certmanager_CertificatePolicy(in *v1.CertificatePolicy, out *certmanager.CertificatePolicy, s conversion.Scope) error {
+	out.PolicyID = in.PolicyID
+	out.CPSURI = in.CPSURI
+	return nil
+}
+
+// Convert_v1_CertificatePolicy_To_certmanager_CertificatePolicy is an autogenerated conversion function.
+func Convert_v1_CertificatePolicy_To_certmanager_CertificatePolicy(in *v1.CertificatePolicy, out *certmanager.CertificatePolicy, s conversion.Scope) error {
+	return autoConvert_v1_CertificatePolicy_To_certmanager_CertificatePolicy(in, out, s)
+}
+
+func autoConvert_certmanager_CertificatePolicy_To_v1_CertificatePolicy(in *certmanager.CertificatePolicy, out *v1.CertificatePolicy, s conversion.Scope) error {
+	out.PolicyID = in.PolicyID
+	out.CPSURI = in.CPSURI
+	return nil
+}
+
+// Convert_certmanager_CertificatePolicy_To_v1_CertificatePolicy is an autogenerated conversion function.
+func Convert_certmanager_CertificatePolicy_To_v1_CertificatePolicy(in *certmanager.CertificatePolicy, out *v1.CertificatePolicy, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificatePolicy_To_v1_CertificatePolicy(in, out, s)
+}
+
 func autoConvert_v1_CertificatePrivateKey_To_certmanager_CertificatePrivateKey(in *v1.CertificatePrivateKey, out *certmanager.CertificatePrivateKey, s conversion.Scope) error {
 	out.RotationPolicy = certmanager.PrivateKeyRotationPolicy(in.RotationPolicy)
 	out.Encoding = certmanager.PrivateKeyEncoding(in.Encoding)
@@ -540,6 +806,62 @@ func Convert_certmanager_CertificatePrivateKey_To_v1_CertificatePrivateKey(in *c
 	return autoConvert_certmanager_CertificatePrivateKey_To_v1_CertificatePrivateKey(in, out, s)
 }
 
+func autoConvert_v1_CertificateRenewalRecord_To_certmanager_CertificateRenewalRecord(in *v1.CertificateRenewalRecord, out *certmanager.CertificateRenewalRecord, s conversion.Scope) error {
+	out.Time = in.Time
+	out.Result = certmanager.CertificateRenewalResult(in.Result)
+	if err := s.Convert(&in.IssuerRef, &out.IssuerRef, 0); err != nil {
+		return err
+	}
+	out.Revision = (*int)(unsafe.Pointer(in.Revision))
+	return nil
+}
+
+// Convert_v1_CertificateRenewalRecord_To_certmanager_CertificateRenewalRecord is an autogenerated conversion function.
+func Convert_v1_CertificateRenewalRecord_To_certmanager_CertificateRenewalRecord(in *v1.CertificateRenewalRecord, out *certmanager.CertificateRenewalRecord, s conversion.Scope) error {
+	return autoConvert_v1_CertificateRenewalRecord_To_certmanager_CertificateRenewalRecord(in, out, s)
+}
+
+func autoConvert_certmanager_CertificateRenewalRecord_To_v1_CertificateRenewalRecord(in *certmanager.CertificateRenewalRecord, out *v1.CertificateRenewalRecord, s conversion.Scope) error {
+	out.Time = in.Time
+	out.Result = v1.CertificateRenewalResult(in.Result)
+	if err := s.Convert(&in.IssuerRef, &out.IssuerRef, 0); err != nil {
+		return err
+	}
+	out.Revision = (*int)(unsafe.Pointer(in.Revision))
+	return nil
+}
+
+// Convert_certmanager_CertificateRenewalRecord_To_v1_CertificateRenewalRecord is an autogenerated conversion function.
+func Convert_certmanager_CertificateRenewalRecord_To_v1_CertificateRenewalRecord(in *certmanager.CertificateRenewalRecord, out *v1.CertificateRenewalRecord, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificateRenewalRecord_To_v1_CertificateRenewalRecord(in, out, s)
+}
+
+func autoConvert_v1_CertificateRenewalSchedule_To_certmanager_CertificateRenewalSchedule(in *v1.CertificateRenewalSchedule, out *certmanager.CertificateRenewalSchedule, s conversion.Scope) error {
+	out.Days = *(*[]string)(unsafe.Pointer(&in.Days))
+	out.StartHour = in.StartHour
+	out.EndHour = in.EndHour
+	out.TimeZone = in.TimeZone
+	return nil
+}
+
+// Convert_v1_CertificateRenewalSchedule_To_certmanager_CertificateRenewalSchedule is an autogenerated conversion function.
+func Convert_v1_CertificateRenewalSchedule_To_certmanager_CertificateRenewalSchedule(in *v1.CertificateRenewalSchedule, out *certmanager.CertificateRenewalSchedule, s conversion.Scope) error {
+	return autoConvert_v1_CertificateRenewalSchedule_To_certmanager_CertificateRenewalSchedule(in, out, s)
+}
+
+func autoConvert_certmanager_CertificateRenewalSchedule_To_v1_CertificateRenewalSchedule(in *certmanager.CertificateRenewalSchedule, out *v1.CertificateRenewalSchedule, s conversion.Scope) error {
+	out.Days = *(*[]string)(unsafe.Pointer(&in.Days))
+	out.StartHour = in.StartHour
+	out.EndHour = in.EndHour
+	out.TimeZone = in.TimeZone
+	return nil
+}
+
+// Convert_certmanager_CertificateRenewalSchedule_To_v1_CertificateRenewalSchedule is an autogenerated conversion function.
+func Convert_certmanager_CertificateRenewalSchedule_To_v1_CertificateRenewalSchedule(in *certmanager.CertificateRenewalSchedule, out *v1.CertificateRenewalSchedule, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificateRenewalSchedule_To_v1_CertificateRenewalSchedule(in, out, s)
+}
+
 func autoConvert_v1_CertificateRequest_To_certmanager_CertificateRequest(in *v1.CertificateRequest, out *certmanager.CertificateRequest, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1_CertificateRequestSpec_To_certmanager_CertificateRequestSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -690,6 +1012,34 @@ func Convert_certmanager_CertificateRequestStatus_To_v1_CertificateRequestStatus
 	return autoConvert_certmanager_CertificateRequestStatus_To_v1_CertificateRequestStatus(in, out, s)
 }
 
+func autoConvert_v1_CertificateSecretTemplate_To_certmanager_CertificateSecretTemplate(in *v1.CertificateSecretTemplate, out *certmanager.CertificateSecretTemplate, s conversion.Scope) error {
+	out.CertificateKeys = *(*[]string)(unsafe.Pointer(&in.CertificateKeys))
+	out.PrivateKeyKeys = *(*[]string)(unsafe.Pointer(&in.PrivateKeyKeys))
+	out.Annotations = *(*map[string]string)(unsafe.Pointer(&in.Annotations))
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.OwnerReferences = *(*[]metav1.OwnerReference)(unsafe.Pointer(&in.OwnerReferences))
+	return nil
+}
+
+// Convert_v1_CertificateSecretTemplate_To_certmanager_CertificateSecretTemplate is an autogenerated conversion function.
+func Convert_v1_CertificateSecretTemplate_To_certmanager_CertificateSecretTemplate(in *v1.CertificateSecretTemplate, out *certmanager.CertificateSecretTemplate, s conversion.Scope) error {
+	return autoConvert_v1_CertificateSecretTemplate_To_certmanager_CertificateSecretTemplate(in, out, s)
+}
+
+func autoConvert_certmanager_CertificateSecretTemplate_To_v1_CertificateSecretTemplate(in *certmanager.CertificateSecretTemplate, out *v1.CertificateSecretTemplate, s conversion.Scope) error {
+	out.CertificateKeys = *(*[]string)(unsafe.Pointer(&in.CertificateKeys))
+	out.PrivateKeyKeys = *(*[]string)(unsafe.Pointer(&in.PrivateKeyKeys))
+	out.Annotations = *(*map[string]string)(unsafe.Pointer(&in.Annotations))
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.OwnerReferences = *(*[]metav1.OwnerReference)(unsafe.Pointer(&in.OwnerReferences))
+	return nil
+}
+
+// Convert_certmanager_CertificateSecretTemplate_To_v1_CertificateSecretTemplate is an autogenerated conversion function.
+func Convert_certmanager_CertificateSecretTemplate_To_v1_CertificateSecretTemplate(in *certmanager.CertificateSecretTemplate, out *v1.CertificateSecretTemplate, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificateSecretTemplate_To_v1_CertificateSecretTemplate(in, out, s)
+}
+
 func autoConvert_v1_CertificateSpec_To_certmanager_CertificateSpec(in *v1.CertificateSpec, out *certmanager.CertificateSpec, s conversion.Scope) error {
 	out.Subject = (*certmanager.X509Subject)(unsafe.Pointer(in.Subject))
 	out.CommonName = in.CommonName
@@ -710,6 +1060,14 @@ func autoConvert_v1_CertificateSpec_To_certmanager_CertificateSpec(in *v1.Certif
 	out.PrivateKey = (*certmanager.CertificatePrivateKey)(unsafe.Pointer(in.PrivateKey))
 	out.EncodeUsagesInRequest = (*bool)(unsafe.Pointer(in.EncodeUsagesInRequest))
 	out.RevisionHistoryLimit = (*int32)(unsafe.Pointer(in.RevisionHistoryLimit))
+	out.SecretTemplate = (*certmanager.CertificateSecretTemplate)(unsafe.Pointer(in.SecretTemplate))
+	out.ServiceSelector = (*metav1.LabelSelector)(unsafe.Pointer(in.ServiceSelector))
+	out.RenewalSchedule = (*certmanager.CertificateRenewalSchedule)(unsafe.Pointer(in.RenewalSchedule))
+	out.OCSPStapling = (*certmanager.CertificateOCSPStapling)(unsafe.Pointer(in.OCSPStapling))
+	out.AddCommonNameToDNSNames = (*bool)(unsafe.Pointer(in.AddCommonNameToDNSNames))
+	out.Replicas = (*int32)(unsafe.Pointer(in.Replicas))
+	out.IssuanceTimeout = (*metav1.Duration)(unsafe.Pointer(in.IssuanceTimeout))
+	out.CertificatePolicies = *(*[]certmanager.CertificatePolicy)(unsafe.Pointer(&in.CertificatePolicies))
 	return nil
 }
 
@@ -733,6 +1091,14 @@ func autoConvert_certmanager_CertificateSpec_To_v1_CertificateSpec(in *certmanag
 	out.PrivateKey = (*v1.CertificatePrivateKey)(unsafe.Pointer(in.PrivateKey))
 	out.EncodeUsagesInRequest = (*bool)(unsafe.Pointer(in.EncodeUsagesInRequest))
 	out.RevisionHistoryLimit = (*int32)(unsafe.Pointer(in.RevisionHistoryLimit))
+	out.SecretTemplate = (*v1.CertificateSecretTemplate)(unsafe.Pointer(in.SecretTemplate))
+	out.ServiceSelector = (*metav1.LabelSelector)(unsafe.Pointer(in.ServiceSelector))
+	out.RenewalSchedule = (*v1.CertificateRenewalSchedule)(unsafe.Pointer(in.RenewalSchedule))
+	out.OCSPStapling = (*v1.CertificateOCSPStapling)(unsafe.Pointer(in.OCSPStapling))
+	out.AddCommonNameToDNSNames = (*bool)(unsafe.Pointer(in.AddCommonNameToDNSNames))
+	out.Replicas = (*int32)(unsafe.Pointer(in.Replicas))
+	out.IssuanceTimeout = (*metav1.Duration)(unsafe.Pointer(in.IssuanceTimeout))
+	out.CertificatePolicies = *(*[]v1.CertificatePolicy)(unsafe.Pointer(&in.CertificatePolicies))
 	return nil
 }
 
@@ -744,6 +1110,17 @@ func autoConvert_v1_CertificateStatus_To_certmanager_CertificateStatus(in *v1.Ce
 	out.RenewalTime = (*metav1.Time)(unsafe.Pointer(in.RenewalTime))
 	out.Revision = (*int)(unsafe.Pointer(in.Revision))
 	out.NextPrivateKeySecretName = (*string)(unsafe.Pointer(in.NextPrivateKeySecretName))
+	if in.RenewalHistory != nil {
+		in, out := &in.RenewalHistory, &out.RenewalHistory
+		*out = make([]certmanager.CertificateRenewalRecord, len(*in))
+		for i := range *in {
+			if err := Convert_v1_CertificateRenewalRecord_To_certmanager_CertificateRenewalRecord(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.RenewalHistory = nil
+	}
 	return nil
 }
 
@@ -760,6 +1137,17 @@ func autoConvert_certmanager_CertificateStatus_To_v1_CertificateStatus(in *certm
 	out.RenewalTime = (*metav1.Time)(unsafe.Pointer(in.RenewalTime))
 	out.Revision = (*int)(unsafe.Pointer(in.Revision))
 	out.NextPrivateKeySecretName = (*string)(unsafe.Pointer(in.NextPrivateKeySecretName))
+	if in.RenewalHistory != nil {
+		in, out := &in.RenewalHistory, &out.RenewalHistory
+		*out = make([]v1.CertificateRenewalRecord, len(*in))
+		for i := range *in {
+			if err := Convert_certmanager_CertificateRenewalRecord_To_v1_CertificateRenewalRecord(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.RenewalHistory = nil
+	}
 	return nil
 }
 
@@ -822,6 +1210,102 @@ func Convert_certmanager_ClusterIssuerList_To_v1_ClusterIssuerList(in *certmanag
 	return autoConvert_certmanager_ClusterIssuerList_To_v1_ClusterIssuerList(in, out, s)
 }
 
+func autoConvert_v1_IssuanceRecord_To_certmanager_IssuanceRecord(in *v1.IssuanceRecord, out *certmanager.IssuanceRecord, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1_IssuanceRecordSpec_To_certmanager_IssuanceRecordSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1_IssuanceRecord_To_certmanager_IssuanceRecord is an autogenerated conversion function.
+func Convert_v1_IssuanceRecord_To_certmanager_IssuanceRecord(in *v1.IssuanceRecord, out *certmanager.IssuanceRecord, s conversion.Scope) error {
+	return autoConvert_v1_IssuanceRecord_To_certmanager_IssuanceRecord(in, out, s)
+}
+
+func autoConvert_certmanager_IssuanceRecord_To_v1_IssuanceRecord(in *certmanager.IssuanceRecord, out *v1.IssuanceRecord, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_certmanager_IssuanceRecordSpec_To_v1_IssuanceRecordSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_certmanager_IssuanceRecord_To_v1_IssuanceRecord is an autogenerated conversion function.
+func Convert_certmanager_IssuanceRecord_To_v1_IssuanceRecord(in *certmanager.IssuanceRecord, out *v1.IssuanceRecord, s conversion.Scope) error {
+	return autoConvert_certmanager_IssuanceRecord_To_v1_IssuanceRecord(in, out, s)
+}
+
+func autoConvert_v1_IssuanceRecordList_To_certmanager_IssuanceRecordList(in *v1.IssuanceRecordList, out *certmanager.IssuanceRecordList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]certmanager.IssuanceRecord)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_v1_IssuanceRecordList_To_certmanager_IssuanceRecordList is an autogenerated conversion function.
+func Convert_v1_IssuanceRecordList_To_certmanager_IssuanceRecordList(in *v1.IssuanceRecordList, out *certmanager.IssuanceRecordList, s conversion.Scope) error {
+	return autoConvert_v1_IssuanceRecordList_To_certmanager_IssuanceRecordList(in, out, s)
+}
+
+func autoConvert_certmanager_IssuanceRecordList_To_v1_IssuanceRecordList(in *certmanager.IssuanceRecordList, out *v1.IssuanceRecordList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]v1.IssuanceRecord)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_certmanager_IssuanceRecordList_To_v1_IssuanceRecordList is an autogenerated conversion function.
+func Convert_certmanager_IssuanceRecordList_To_v1_IssuanceRecordList(in *certmanager.IssuanceRecordList, out *v1.IssuanceRecordList, s conversion.Scope) error {
+	return autoConvert_certmanager_IssuanceRecordList_To_v1_IssuanceRecordList(in, out, s)
+}
+
+func autoConvert_v1_IssuanceRecordSpec_To_certmanager_IssuanceRecordSpec(in *v1.IssuanceRecordSpec, out *certmanager.IssuanceRecordSpec, s conversion.Scope) error {
+	out.CertificateRequestName = in.CertificateRequestName
+	out.CertificateName = in.CertificateName
+	// TODO: Inefficient conversion - can we improve it?
+	if err := s.Convert(&in.IssuerRef, &out.IssuerRef, 0); err != nil {
+		return err
+	}
+	out.SerialNumber = in.SerialNumber
+	out.CommonName = in.CommonName
+	out.DNSNames = *(*[]string)(unsafe.Pointer(&in.DNSNames))
+	out.IPAddresses = *(*[]string)(unsafe.Pointer(&in.IPAddresses))
+	out.URIs = *(*[]string)(unsafe.Pointer(&in.URIs))
+	out.EmailAddresses = *(*[]string)(unsafe.Pointer(&in.EmailAddresses))
+	out.NotBefore = (*metav1.Time)(unsafe.Pointer(in.NotBefore))
+	out.NotAfter = (*metav1.Time)(unsafe.Pointer(in.NotAfter))
+	out.IssuedAt = (*metav1.Time)(unsafe.Pointer(in.IssuedAt))
+	return nil
+}
+
+// Convert_v1_IssuanceRecordSpec_To_certmanager_IssuanceRecordSpec is an autogenerated conversion function.
+func Convert_v1_IssuanceRecordSpec_To_certmanager_IssuanceRecordSpec(in *v1.IssuanceRecordSpec, out *certmanager.IssuanceRecordSpec, s conversion.Scope) error {
+	return autoConvert_v1_IssuanceRecordSpec_To_certmanager_IssuanceRecordSpec(in, out, s)
+}
+
+func autoConvert_certmanager_IssuanceRecordSpec_To_v1_IssuanceRecordSpec(in *certmanager.IssuanceRecordSpec, out *v1.IssuanceRecordSpec, s conversion.Scope) error {
+	out.CertificateRequestName = in.CertificateRequestName
+	out.CertificateName = in.CertificateName
+	// TODO: Inefficient conversion - can we improve it?
+	if err := s.Convert(&in.IssuerRef, &out.IssuerRef, 0); err != nil {
+		return err
+	}
+	out.SerialNumber = in.SerialNumber
+	out.CommonName = in.CommonName
+	out.DNSNames = *(*[]string)(unsafe.Pointer(&in.DNSNames))
+	out.IPAddresses = *(*[]string)(unsafe.Pointer(&in.IPAddresses))
+	out.URIs = *(*[]string)(unsafe.Pointer(&in.URIs))
+	out.EmailAddresses = *(*[]string)(unsafe.Pointer(&in.EmailAddresses))
+	out.NotBefore = (*metav1.Time)(unsafe.Pointer(in.NotBefore))
+	out.NotAfter = (*metav1.Time)(unsafe.Pointer(in.NotAfter))
+	out.IssuedAt = (*metav1.Time)(unsafe.Pointer(in.IssuedAt))
+	return nil
+}
+
+// Convert_certmanager_IssuanceRecordSpec_To_v1_IssuanceRecordSpec is an autogenerated conversion function.
+func Convert_certmanager_IssuanceRecordSpec_To_v1_IssuanceRecordSpec(in *certmanager.IssuanceRecordSpec, out *v1.IssuanceRecordSpec, s conversion.Scope) error {
+	return autoConvert_certmanager_IssuanceRecordSpec_To_v1_IssuanceRecordSpec(in, out, s)
+}
+
 func autoConvert_v1_Issuer_To_certmanager_Issuer(in *v1.Issuer, out *certmanager.Issuer, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1_IssuerSpec_To_certmanager_IssuerSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -854,6 +1338,26 @@ func Convert_certmanager_Issuer_To_v1_Issuer(in *certmanager.Issuer, out *v1.Iss
 	return autoConvert_certmanager_Issuer_To_v1_Issuer(in, out, s)
 }
 
+func autoConvert_v1_IssuerChainValidation_To_certmanager_IssuerChainValidation(in *v1.IssuerChainValidation, out *certmanager.IssuerChainValidation, s conversion.Scope) error {
+	out.TrustAnchors = *(*[]byte)(unsafe.Pointer(&in.TrustAnchors))
+	return nil
+}
+
+// Convert_v1_IssuerChainValidation_To_certmanager_IssuerChainValidation is an autogenerated conversion function.
+func Convert_v1_IssuerChainValidation_To_certmanager_IssuerChainValidation(in *v1.IssuerChainValidation, out *certmanager.IssuerChainValidation, s conversion.Scope) error {
+	return autoConvert_v1_IssuerChainValidation_To_certmanager_IssuerChainValidation(in, out, s)
+}
+
+func autoConvert_certmanager_IssuerChainValidation_To_v1_IssuerChainValidation(in *certmanager.IssuerChainValidation, out *v1.IssuerChainValidation, s conversion.Scope) error {
+	out.TrustAnchors = *(*[]byte)(unsafe.Pointer(&in.TrustAnchors))
+	return nil
+}
+
+// Convert_certmanager_IssuerChainValidation_To_v1_IssuerChainValidation is an autogenerated conversion function.
+func Convert_certmanager_IssuerChainValidation_To_v1_IssuerChainValidation(in *certmanager.IssuerChainValidation, out *v1.IssuerChainValidation, s conversion.Scope) error {
+	return autoConvert_certmanager_IssuerChainValidation_To_v1_IssuerChainValidation(in, out, s)
+}
+
 func autoConvert_v1_IssuerCondition_To_certmanager_IssuerCondition(in *v1.IssuerCondition, out *certmanager.IssuerCondition, s conversion.Scope) error {
 	out.Type = certmanager.IssuerConditionType(in.Type)
 	out.Status = meta.ConditionStatus(in.Status)
@@ -890,6 +1394,7 @@ func autoConvert_v1_IssuerConfig_To_certmanager_IssuerConfig(in *v1.IssuerConfig
 	out.Vault = (*certmanager.VaultIssuer)(unsafe.Pointer(in.Vault))
 	out.SelfSigned = (*certmanager.SelfSignedIssuer)(unsafe.Pointer(in.SelfSigned))
 	out.Venafi = (*certmanager.VenafiIssuer)(unsafe.Pointer(in.Venafi))
+	out.ADCSWebEnrollment = (*certmanager.ADCSWebEnrollmentIssuer)(unsafe.Pointer(in.ADCSWebEnrollment))
 	return nil
 }
 
@@ -904,6 +1409,7 @@ func autoConvert_certmanager_IssuerConfig_To_v1_IssuerConfig(in *certmanager.Iss
 	out.Vault = (*v1.VaultIssuer)(unsafe.Pointer(in.Vault))
 	out.SelfSigned = (*v1.SelfSignedIssuer)(unsafe.Pointer(in.SelfSigned))
 	out.Venafi = (*v1.VenafiIssuer)(unsafe.Pointer(in.Venafi))
+	out.ADCSWebEnrollment = (*v1.ADCSWebEnrollmentIssuer)(unsafe.Pointer(in.ADCSWebEnrollment))
 	return nil
 }
 
@@ -938,6 +1444,7 @@ func autoConvert_v1_IssuerSpec_To_certmanager_IssuerSpec(in *v1.IssuerSpec, out
 	if err := Convert_v1_IssuerConfig_To_certmanager_IssuerConfig(&in.IssuerConfig, &out.IssuerConfig, s); err != nil {
 		return err
 	}
+	out.ChainValidation = (*certmanager.IssuerChainValidation)(unsafe.Pointer(in.ChainValidation))
 	return nil
 }
 
@@ -950,6 +1457,7 @@ func autoConvert_certmanager_IssuerSpec_To_v1_IssuerSpec(in *certmanager.IssuerS
 	if err := Convert_certmanager_IssuerConfig_To_v1_IssuerConfig(&in.IssuerConfig, &out.IssuerConfig, s); err != nil {
 		return err
 	}
+	out.ChainValidation = (*v1.IssuerChainValidation)(unsafe.Pointer(in.ChainValidation))
 	return nil
 }
 
@@ -986,6 +1494,8 @@ func autoConvert_v1_JKSKeystore_To_certmanager_JKSKeystore(in *v1.JKSKeystore, o
 	if err := s.Convert(&in.PasswordSecretRef, &out.PasswordSecretRef, 0); err != nil {
 		return err
 	}
+	out.Alias = in.Alias
+	out.IncludeChain = in.IncludeChain
 	return nil
 }
 
@@ -1000,6 +1510,8 @@ func autoConvert_certmanager_JKSKeystore_To_v1_JKSKeystore(in *certmanager.JKSKe
 	if err := s.Convert(&in.PasswordSecretRef, &out.PasswordSecretRef, 0); err != nil {
 		return err
 	}
+	out.Alias = in.Alias
+	out.IncludeChain = in.IncludeChain
 	return nil
 }
 
@@ -1014,6 +1526,7 @@ func autoConvert_v1_PKCS12Keystore_To_certmanager_PKCS12Keystore(in *v1.PKCS12Ke
 	if err := s.Convert(&in.PasswordSecretRef, &out.PasswordSecretRef, 0); err != nil {
 		return err
 	}
+	out.Profile = certmanager.PKCS12Profile(in.Profile)
 	return nil
 }
 
@@ -1028,6 +1541,7 @@ func autoConvert_certmanager_PKCS12Keystore_To_v1_PKCS12Keystore(in *certmanager
 	if err := s.Convert(&in.PasswordSecretRef, &out.PasswordSecretRef, 0); err != nil {
 		return err
 	}
+	out.Profile = v1.PKCS12Profile(in.Profile)
 	return nil
 }
 
@@ -1038,6 +1552,11 @@ func Convert_certmanager_PKCS12Keystore_To_v1_PKCS12Keystore(in *certmanager.PKC
 
 func autoConvert_v1_SelfSignedIssuer_To_certmanager_SelfSignedIssuer(in *v1.SelfSignedIssuer, out *certmanager.SelfSignedIssuer, s conversion.Scope) error {
 	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
+	out.SubjectKeyIdentifierMethod = certmanager.SubjectKeyIdentifierMethod(in.SubjectKeyIdentifierMethod)
+	out.KeySeedSecretRef = (*meta.SecretKeySelector)(unsafe.Pointer(in.KeySeedSecretRef))
+	out.CertificateProfile = certmanager.CertificateProfile(in.CertificateProfile)
+	out.CertificatePolicies = *(*[]certmanager.CertificatePolicy)(unsafe.Pointer(&in.CertificatePolicies))
+	out.AllowedCertificatePolicies = *(*[]certmanager.CertificatePolicy)(unsafe.Pointer(&in.AllowedCertificatePolicies))
 	return nil
 }
 
@@ -1048,6 +1567,11 @@ func Convert_v1_SelfSignedIssuer_To_certmanager_SelfSignedIssuer(in *v1.SelfSign
 
 func autoConvert_certmanager_SelfSignedIssuer_To_v1_SelfSignedIssuer(in *certmanager.SelfSignedIssuer, out *v1.SelfSignedIssuer, s conversion.Scope) error {
 	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
+	out.SubjectKeyIdentifierMethod = v1.SubjectKeyIdentifierMethod(in.SubjectKeyIdentifierMethod)
+	out.KeySeedSecretRef = (*apismetav1.SecretKeySelector)(unsafe.Pointer(in.KeySeedSecretRef))
+	out.CertificateProfile = v1.CertificateProfile(in.CertificateProfile)
+	out.CertificatePolicies = *(*[]v1.CertificatePolicy)(unsafe.Pointer(&in.CertificatePolicies))
+	out.AllowedCertificatePolicies = *(*[]v1.CertificatePolicy)(unsafe.Pointer(&in.AllowedCertificatePolicies))
 	return nil
 }
 
@@ -1118,6 +1642,7 @@ func autoConvert_v1_VaultIssuer_To_certmanager_VaultIssuer(in *v1.VaultIssuer, o
 	out.Path = in.Path
 	out.Namespace = in.Namespace
 	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	out.ExpectedRootCA = *(*[]byte)(unsafe.Pointer(&in.ExpectedRootCA))
 	return nil
 }
 
@@ -1134,6 +1659,7 @@ func autoConvert_certmanager_VaultIssuer_To_v1_VaultIssuer(in *certmanager.Vault
 	out.Path = in.Path
 	out.Namespace = in.Namespace
 	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	out.ExpectedRootCA = *(*[]byte)(unsafe.Pointer(&in.ExpectedRootCA))
 	return nil
 }
 
@@ -1149,6 +1675,7 @@ func autoConvert_v1_VaultKubernetesAuth_To_certmanager_VaultKubernetesAuth(in *v
 		return err
 	}
 	out.Role = in.Role
+	out.TokenPath = in.TokenPath
 	return nil
 }
 
@@ -1164,6 +1691,7 @@ func autoConvert_certmanager_VaultKubernetesAuth_To_v1_VaultKubernetesAuth(in *c
 		return err
 	}
 	out.Role = in.Role
+	out.TokenPath = in.TokenPath
 	return nil
 }
 
@@ -1200,10 +1728,33 @@ func Convert_certmanager_VenafiCloud_To_v1_VenafiCloud(in *certmanager.VenafiClo
 	return autoConvert_certmanager_VenafiCloud_To_v1_VenafiCloud(in, out, s)
 }
 
+func autoConvert_v1_VenafiCustomFieldAnnotation_To_certmanager_VenafiCustomFieldAnnotation(in *v1.VenafiCustomFieldAnnotation, out *certmanager.VenafiCustomFieldAnnotation, s conversion.Scope) error {
+	out.Annotation = in.Annotation
+	out.CustomField = in.CustomField
+	return nil
+}
+
+// Convert_v1_VenafiCustomFieldAnnotation_To_certmanager_VenafiCustomFieldAnnotation is an autogenerated conversion function.
+func Convert_v1_VenafiCustomFieldAnnotation_To_certmanager_VenafiCustomFieldAnnotation(in *v1.VenafiCustomFieldAnnotation, out *certmanager.VenafiCustomFieldAnnotation, s conversion.Scope) error {
+	return autoConvert_v1_VenafiCustomFieldAnnotation_To_certmanager_VenafiCustomFieldAnnotation(in, out, s)
+}
+
+func autoConvert_certmanager_VenafiCustomFieldAnnotation_To_v1_VenafiCustomFieldAnnotation(in *certmanager.VenafiCustomFieldAnnotation, out *v1.VenafiCustomFieldAnnotation, s conversion.Scope) error {
+	out.Annotation = in.Annotation
+	out.CustomField = in.CustomField
+	return nil
+}
+
+// Convert_certmanager_VenafiCustomFieldAnnotation_To_v1_VenafiCustomFieldAnnotation is an autogenerated conversion function.
+func Convert_certmanager_VenafiCustomFieldAnnotation_To_v1_VenafiCustomFieldAnnotation(in *certmanager.VenafiCustomFieldAnnotation, out *v1.VenafiCustomFieldAnnotation, s conversion.Scope) error {
+	return autoConvert_certmanager_VenafiCustomFieldAnnotation_To_v1_VenafiCustomFieldAnnotation(in, out, s)
+}
+
 func autoConvert_v1_VenafiIssuer_To_certmanager_VenafiIssuer(in *v1.VenafiIssuer, out *certmanager.VenafiIssuer, s conversion.Scope) error {
 	out.Zone = in.Zone
 	out.TPP = (*certmanager.VenafiTPP)(unsafe.Pointer(in.TPP))
 	out.Cloud = (*certmanager.VenafiCloud)(unsafe.Pointer(in.Cloud))
+	out.CustomFieldAnnotations = *(*[]certmanager.VenafiCustomFieldAnnotation)(unsafe.Pointer(&in.CustomFieldAnnotations))
 	return nil
 }
 
@@ -1216,6 +1767,7 @@ func autoConvert_certmanager_VenafiIssuer_To_v1_VenafiIssuer(in *certmanager.Ven
 	out.Zone = in.Zone
 	out.TPP = (*v1.VenafiTPP)(unsafe.Pointer(in.TPP))
 	out.Cloud = (*v1.VenafiCloud)(unsafe.Pointer(in.Cloud))
+	out.CustomFieldAnnotations = *(*[]v1.VenafiCustomFieldAnnotation)(unsafe.Pointer(&in.CustomFieldAnnotations))
 	return nil
 }
 
@@ -41,6 +41,26 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*v1alpha2.ADCSWebEnrollmentIssuer)(nil), (*certmanager.ADCSWebEnrollmentIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ADCSWebEnrollmentIssuer_To_certmanager_ADCSWebEnrollmentIssuer(a.(*v1alpha2.ADCSWebEnrollmentIssuer), b.(*certmanager.ADCSWebEnrollmentIssuer), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.ADCSWebEnrollmentIssuer)(nil), (*v1alpha2.ADCSWebEnrollmentIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_ADCSWebEnrollmentIssuer_To_v1alpha2_ADCSWebEnrollmentIssuer(a.(*certmanager.ADCSWebEnrollmentIssuer), b.(*v1alpha2.ADCSWebEnrollmentIssuer), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha2.CACRL)(nil), (*certmanager.CACRL)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_CACRL_To_certmanager_CACRL(a.(*v1alpha2.CACRL), b.(*certmanager.CACRL), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CACRL)(nil), (*v1alpha2.CACRL)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CACRL_To_v1alpha2_CACRL(a.(*certmanager.CACRL), b.(*v1alpha2.CACRL), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha2.CAIssuer)(nil), (*certmanager.CAIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_CAIssuer_To_certmanager_CAIssuer(a.(*v1alpha2.CAIssuer), b.(*certmanager.CAIssuer), scope)
 	}); err != nil {
@@ -51,6 +71,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha2.CARevokedCertificate)(nil), (*certmanager.CARevokedCertificate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_CARevokedCertificate_To_certmanager_CARevokedCertificate(a.(*v1alpha2.CARevokedCertificate), b.(*certmanager.CARevokedCertificate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CARevokedCertificate)(nil), (*v1alpha2.CARevokedCertificate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CARevokedCertificate_To_v1alpha2_CARevokedCertificate(a.(*certmanager.CARevokedCertificate), b.(*v1alpha2.CARevokedCertificate), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha2.Certificate)(nil), (*certmanager.Certificate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_Certificate_To_certmanager_Certificate(a.(*v1alpha2.Certificate), b.(*certmanager.Certificate), scope)
 	}); err != nil {
@@ -91,11 +121,51 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha2.CertificateOCSPStapling)(nil), (*certmanager.CertificateOCSPStapling)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_CertificateOCSPStapling_To_certmanager_CertificateOCSPStapling(a.(*v1alpha2.CertificateOCSPStapling), b.(*certmanager.CertificateOCSPStapling), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificateOCSPStapling)(nil), (*v1alpha2.CertificateOCSPStapling)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificateOCSPStapling_To_v1alpha2_CertificateOCSPStapling(a.(*certmanager.CertificateOCSPStapling), b.(*v1alpha2.CertificateOCSPStapling), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha2.CertificatePolicy)(nil), (*certmanager.CertificatePolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_CertificatePolicy_To_certmanager_CertificatePolicy(a.(*v1alpha2.CertificatePolicy), b.(*certmanager.CertificatePolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificatePolicy)(nil), (*v1alpha2.CertificatePolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificatePolicy_To_v1alpha2_CertificatePolicy(a.(*certmanager.CertificatePolicy), b.(*v1alpha2.CertificatePolicy), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha2.CertificatePrivateKey)(nil), (*certmanager.CertificatePrivateKey)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_CertificatePrivateKey_To_certmanager_CertificatePrivateKey(a.(*v1alpha2.CertificatePrivateKey), b.(*certmanager.CertificatePrivateKey), scope)
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha2.CertificateRenewalRecord)(nil), (*certmanager.CertificateRenewalRecord)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_CertificateRenewalRecord_To_certmanager_CertificateRenewalRecord(a.(*v1alpha2.CertificateRenewalRecord), b.(*certmanager.CertificateRenewalRecord), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificateRenewalRecord)(nil), (*v1alpha2.CertificateRenewalRecord)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificateRenewalRecord_To_v1alpha2_CertificateRenewalRecord(a.(*certmanager.CertificateRenewalRecord), b.(*v1alpha2.CertificateRenewalRecord), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha2.CertificateRenewalSchedule)(nil), (*certmanager.CertificateRenewalSchedule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_CertificateRenewalSchedule_To_certmanager_CertificateRenewalSchedule(a.(*v1alpha2.CertificateRenewalSchedule), b.(*certmanager.CertificateRenewalSchedule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificateRenewalSchedule)(nil), (*v1alpha2.CertificateRenewalSchedule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificateRenewalSchedule_To_v1alpha2_CertificateRenewalSchedule(a.(*certmanager.CertificateRenewalSchedule), b.(*v1alpha2.CertificateRenewalSchedule), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha2.CertificateRequest)(nil), (*certmanager.CertificateRequest)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_CertificateRequest_To_certmanager_CertificateRequest(a.(*v1alpha2.CertificateRequest), b.(*certmanager.CertificateRequest), scope)
 	}); err != nil {
@@ -136,6 +206,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha2.CertificateSecretTemplate)(nil), (*certmanager.CertificateSecretTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_CertificateSecretTemplate_To_certmanager_CertificateSecretTemplate(a.(*v1alpha2.CertificateSecretTemplate), b.(*certmanager.CertificateSecretTemplate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificateSecretTemplate)(nil), (*v1alpha2.CertificateSecretTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificateSecretTemplate_To_v1alpha2_CertificateSecretTemplate(a.(*certmanager.CertificateSecretTemplate), b.(*v1alpha2.CertificateSecretTemplate), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha2.CertificateStatus)(nil), (*certmanager.CertificateStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_CertificateStatus_To_certmanager_CertificateStatus(a.(*v1alpha2.CertificateStatus), b.(*certmanager.CertificateStatus), scope)
 	}); err != nil {
@@ -176,6 +256,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha2.IssuerChainValidation)(nil), (*certmanager.IssuerChainValidation)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_IssuerChainValidation_To_certmanager_IssuerChainValidation(a.(*v1alpha2.IssuerChainValidation), b.(*certmanager.IssuerChainValidation), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.IssuerChainValidation)(nil), (*v1alpha2.IssuerChainValidation)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_IssuerChainValidation_To_v1alpha2_IssuerChainValidation(a.(*certmanager.IssuerChainValidation), b.(*v1alpha2.IssuerChainValidation), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha2.IssuerCondition)(nil), (*certmanager.IssuerCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_IssuerCondition_To_certmanager_IssuerCondition(a.(*v1alpha2.IssuerCondition), b.(*certmanager.IssuerCondition), scope)
 	}); err != nil {
@@ -306,6 +396,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha2.VenafiCustomFieldAnnotation)(nil), (*certmanager.VenafiCustomFieldAnnotation)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_VenafiCustomFieldAnnotation_To_certmanager_VenafiCustomFieldAnnotation(a.(*v1alpha2.VenafiCustomFieldAnnotation), b.(*certmanager.VenafiCustomFieldAnnotation), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.VenafiCustomFieldAnnotation)(nil), (*v1alpha2.VenafiCustomFieldAnnotation)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_VenafiCustomFieldAnnotation_To_v1alpha2_VenafiCustomFieldAnnotation(a.(*certmanager.VenafiCustomFieldAnnotation), b.(*v1alpha2.VenafiCustomFieldAnnotation), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha2.VenafiIssuer)(nil), (*certmanager.VenafiIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_VenafiIssuer_To_certmanager_VenafiIssuer(a.(*v1alpha2.VenafiIssuer), b.(*certmanager.VenafiIssuer), scope)
 	}); err != nil {
@@ -364,10 +464,74 @@ func RegisterConversions(s *runtime.Scheme) error {
 	return nil
 }
 
+func autoConvert_v1alpha2_ADCSWebEnrollmentIssuer_To_certmanager_ADCSWebEnrollmentIssuer(in *v1alpha2.ADCSWebEnrollmentIssuer, out *certmanager.ADCSWebEnrollmentIssuer, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Template = in.Template
+	// TODO: Inefficient conversion - can we improve it?
+	if err := s.Convert(&in.CredentialsRef, &out.CredentialsRef, 0); err != nil {
+		return err
+	}
+	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	return nil
+}
+
+// Convert_v1alpha2_ADCSWebEnrollmentIssuer_To_certmanager_ADCSWebEnrollmentIssuer is an autogenerated conversion function.
+func Convert_v1alpha2_ADCSWebEnrollmentIssuer_To_certmanager_ADCSWebEnrollmentIssuer(in *v1alpha2.ADCSWebEnrollmentIssuer, out *certmanager.ADCSWebEnrollmentIssuer, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ADCSWebEnrollmentIssuer_To_certmanager_ADCSWebEnrollmentIssuer(in, out, s)
+}
+
+func autoConvert_certmanager_ADCSWebEnrollmentIssuer_To_v1alpha2_ADCSWebEnrollmentIssuer(in *certmanager.ADCSWebEnrollmentIssuer, out *v1alpha2.ADCSWebEnrollmentIssuer, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Template = in.Template
+	// TODO: Inefficient conversion - can we improve it?
+	if err := s.Convert(&in.CredentialsRef, &out.CredentialsRef, 0); err != nil {
+		return err
+	}
+	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	return nil
+}
+
+// Convert_certmanager_ADCSWebEnrollmentIssuer_To_v1alpha2_ADCSWebEnrollmentIssuer is an autogenerated conversion function.
+func Convert_certmanager_ADCSWebEnrollmentIssuer_To_v1alpha2_ADCSWebEnrollmentIssuer(in *certmanager.ADCSWebEnrollmentIssuer, out *v1alpha2.ADCSWebEnrollmentIssuer, s conversion.Scope) error {
+	return autoConvert_certmanager_ADCSWebEnrollmentIssuer_To_v1alpha2_ADCSWebEnrollmentIssuer(in, out, s)
+}
+
+func autoConvert_v1alpha2_CACRL_To_certmanager_CACRL(in *v1alpha2.CACRL, out *certmanager.CACRL, s conversion.Scope) error {
+	out.SecretName = in.SecretName
+	out.Validity = (*v1.Duration)(unsafe.Pointer(in.Validity))
+	out.RevokedCertificates = *(*[]certmanager.CARevokedCertificate)(unsafe.Pointer(&in.RevokedCertificates))
+	return nil
+}
+
+// Convert_v1alpha2_CACRL_To_certmanager_CACRL is an autogenerated conversion function.
+func Convert_v1alpha2_CACRL_To_certmanager_CACRL(in *v1alpha2.CACRL, out *certmanager.CACRL, s conversion.Scope) error {
+	return autoConvert_v1alpha2_CACRL_To_certmanager_CACRL(in, out, s)
+}
+
+func autoConvert_certmanager_CACRL_To_v1alpha2_CACRL(in *certmanager.CACRL, out *v1alpha2.CACRL, s conversion.Scope) error {
+	out.SecretName = in.SecretName
+	out.Validity = (*v1.Duration)(unsafe.Pointer(in.Validity))
+	out.RevokedCertificates = *(*[]v1alpha2.CARevokedCertificate)(unsafe.Pointer(&in.RevokedCertificates))
+	return nil
+}
+
+// Convert_certmanager_CACRL_To_v1alpha2_CACRL is an autogenerated conversion function.
+func Convert_certmanager_CACRL_To_v1alpha2_CACRL(in *certmanager.CACRL, out *v1alpha2.CACRL, s conversion.Scope) error {
+	return autoConvert_certmanager_CACRL_To_v1alpha2_CACRL(in, out, s)
+}
+
 func autoConvert_v1alpha2_CAIssuer_To_certmanager_CAIssuer(in *v1alpha2.CAIssuer, out *certmanager.CAIssuer, s conversion.Scope) error {
 	out.SecretName = in.SecretName
 	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
 	out.OCSPServers = *(*[]string)(unsafe.Pointer(&in.OCSPServers))
+	out.AllowCAIssuance = in.AllowCAIssuance
+	out.CRL = (*certmanager.CACRL)(unsafe.Pointer(in.CRL))
+	out.SubjectKeyIdentifierMethod = certmanager.SubjectKeyIdentifierMethod(in.SubjectKeyIdentifierMethod)
+	out.MaxLeafDuration = (*v1.Duration)(unsafe.Pointer(in.MaxLeafDuration))
+	out.MaxLeafDurationPolicy = certmanager.MaxLeafDurationPolicy(in.MaxLeafDurationPolicy)
+	out.CertificateProfile = certmanager.CertificateProfile(in.CertificateProfile)
+	out.CertificatePolicies = *(*[]certmanager.CertificatePolicy)(unsafe.Pointer(&in.CertificatePolicies))
+	out.AllowedCertificatePolicies = *(*[]certmanager.CertificatePolicy)(unsafe.Pointer(&in.AllowedCertificatePolicies))
 	return nil
 }
 
@@ -380,6 +544,14 @@ func autoConvert_certmanager_CAIssuer_To_v1alpha2_CAIssuer(in *certmanager.CAIss
 	out.SecretName = in.SecretName
 	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
 	out.OCSPServers = *(*[]string)(unsafe.Pointer(&in.OCSPServers))
+	out.AllowCAIssuance = in.AllowCAIssuance
+	out.CRL = (*v1alpha2.CACRL)(unsafe.Pointer(in.CRL))
+	out.SubjectKeyIdentifierMethod = v1alpha2.SubjectKeyIdentifierMethod(in.SubjectKeyIdentifierMethod)
+	out.MaxLeafDuration = (*v1.Duration)(unsafe.Pointer(in.MaxLeafDuration))
+	out.MaxLeafDurationPolicy = v1alpha2.MaxLeafDurationPolicy(in.MaxLeafDurationPolicy)
+	out.CertificateProfile = v1alpha2.CertificateProfile(in.CertificateProfile)
+	out.CertificatePolicies = *(*[]v1alpha2.CertificatePolicy)(unsafe.Pointer(&in.CertificatePolicies))
+	out.AllowedCertificatePolicies = *(*[]v1alpha2.CertificatePolicy)(unsafe.Pointer(&in.AllowedCertificatePolicies))
 	return nil
 }
 
@@ -388,6 +560,28 @@ func Convert_certmanager_CAIssuer_To_v1alpha2_CAIssuer(in *certmanager.CAIssuer,
 	return autoConvert_certmanager_CAIssuer_To_v1alpha2_CAIssuer(in, out, s)
 }
 
+func autoConvert_v1alpha2_CARevokedCertificate_To_certmanager_CARevokedCertificate(in *v1alpha2.CARevokedCertificate, out *certmanager.CARevokedCertificate, s conversion.Scope) error {
+	out.SerialNumber = in.SerialNumber
+	out.RevocationTime = in.RevocationTime
+	return nil
+}
+
+// Convert_v1alpha2_CARevokedCertificate_To_certmanager_CARevokedCertificate is an autogenerated conversion function.
+func Convert_v1alpha2_CARevokedCertificate_To_certmanager_CARevokedCertificate(in *v1alpha2.CARevokedCertificate, out *certmanager.CARevokedCertificate, s conversion.Scope) error {
+	return autoConvert_v1alpha2_CARevokedCertificate_To_certmanager_CARevokedCertificate(in, out, s)
+}
+
+func autoConvert_certmanager_CARevokedCertificate_To_v1alpha2_CARevokedCertificate(in *certmanager.CARevokedCertificate, out *v1alpha2.CARevokedCertificate, s conversion.Scope) error {
+	out.SerialNumber = in.SerialNumber
+	out.RevocationTime = in.RevocationTime
+	return nil
+}
+
+// Convert_certmanager_CARevokedCertificate_To_v1alpha2_CARevokedCertificate is an autogenerated conversion function.
+func Convert_certmanager_CARevokedCertificate_To_v1alpha2_CARevokedCertificate(in *certmanager.CARevokedCertificate, out *v1alpha2.CARevokedCertificate, s conversion.Scope) error {
+	return autoConvert_certmanager_CARevokedCertificate_To_v1alpha2_CARevokedCertificate(in, out, s)
+}
+
 func autoConvert_v1alpha2_Certificate_To_certmanager_Certificate(in *v1alpha2.Certificate, out *certmanager.Certificate, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha2_CertificateSpec_To_certmanager_CertificateSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -514,6 +708,48 @@ func Convert_certmanager_CertificateList_To_v1alpha2_CertificateList(in *certman
 	return autoConvert_certmanager_CertificateList_To_v1alpha2_CertificateList(in, out, s)
 }
 
+func autoConvert_v1alpha2_CertificateOCSPStapling_To_certmanager_CertificateOCSPStapling(in *v1alpha2.CertificateOCSPStapling, out *certmanager.CertificateOCSPStapling, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	return nil
+}
+
+// Convert_v1alpha2_CertificateOCSPStapling_To_certmanager_CertificateOCSPStapling is an autogenerated conversion function.
+func Convert_v1alpha2_CertificateOCSPStapling_To_certmanager_CertificateOCSPStapling(in *v1alpha2.CertificateOCSPStapling, out *certmanager.CertificateOCSPStapling, s conversion.Scope) error {
+	return autoConvert_v1alpha2_CertificateOCSPStapling_To_certmanager_CertificateOCSPStapling(in, out, s)
+}
+
+func autoConvert_certmanager_CertificateOCSPStapling_To_v1alpha2_CertificateOCSPStapling(in *certmanager.CertificateOCSPStapling, out *v1alpha2.CertificateOCSPStapling, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	return nil
+}
+
+// Convert_certmanager_CertificateOCSPStapling_To_v1alpha2_CertificateOCSPStapling is an autogenerated conversion function.
+func Convert_certmanager_CertificateOCSPStapling_To_v1alpha2_CertificateOCSPStapling(in *certmanager.CertificateOCSPStapling, out *v1alpha2.CertificateOCSPStapling, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificateOCSPStapling_To_v1alpha2_CertificateOCSPStapling(in, out, s)
+}
+
+func autoConvert_v1alpha2_CertificatePolicy_To_certmanager_CertificatePolicy(in *v1alpha2.CertificatePolicy, out *certmanager.CertificatePolicy, s conversion.Scope) error {
+	out.PolicyID = in.PolicyID
+	out.CPSURI = in.CPSURI
+	return nil
+}
+
+// Convert_v1alpha2_CertificatePolicy_To_certmanager_CertificatePolicy is an autogenerated conversion function.
+func Convert_v1alpha2_CertificatePolicy_To_certmanager_CertificatePolicy(in *v1alpha2.CertificatePolicy, out *certmanager.CertificatePolicy, s conversion.Scope) error {
+	return autoConvert_v1alpha2_CertificatePolicy_To_certmanager_CertificatePolicy(in, out, s)
+}
+
+func autoConvert_certmanager_CertificatePolicy_To_v1alpha2_CertificatePolicy(in *certmanager.CertificatePolicy, out *v1alpha2.CertificatePolicy, s conversion.Scope) error {
+	out.PolicyID = in.PolicyID
+	out.CPSURI = in.CPSURI
+	return nil
+}
+
+// Convert_certmanager_CertificatePolicy_To_v1alpha2_CertificatePolicy is an autogenerated conversion function.
+func Convert_certmanager_CertificatePolicy_To_v1alpha2_CertificatePolicy(in *certmanager.CertificatePolicy, out *v1alpha2.CertificatePolicy, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificatePolicy_To_v1alpha2_CertificatePolicy(in, out, s)
+}
+
 func autoConvert_v1alpha2_CertificatePrivateKey_To_certmanager_CertificatePrivateKey(in *v1alpha2.CertificatePrivateKey, out *certmanager.CertificatePrivateKey, s conversion.Scope) error {
 	out.RotationPolicy = certmanager.PrivateKeyRotationPolicy(in.RotationPolicy)
 	return nil
@@ -532,6 +768,62 @@ func autoConvert_certmanager_CertificatePrivateKey_To_v1alpha2_CertificatePrivat
 	return nil
 }
 
+func autoConvert_v1alpha2_CertificateRenewalRecord_To_certmanager_CertificateRenewalRecord(in *v1alpha2.CertificateRenewalRecord, out *certmanager.CertificateRenewalRecord, s conversion.Scope) error {
+	out.Time = in.Time
+	out.Result = certmanager.CertificateRenewalResult(in.Result)
+	if err := s.Convert(&in.IssuerRef, &out.IssuerRef, 0); err != nil {
+		return err
+	}
+	out.Revision = (*int)(unsafe.Pointer(in.Revision))
+	return nil
+}
+
+// Convert_v1alpha2_CertificateRenewalRecord_To_certmanager_CertificateRenewalRecord is an autogenerated conversion function.
+func Convert_v1alpha2_CertificateRenewalRecord_To_certmanager_CertificateRenewalRecord(in *v1alpha2.CertificateRenewalRecord, out *certmanager.CertificateRenewalRecord, s conversion.Scope) error {
+	return autoConvert_v1alpha2_CertificateRenewalRecord_To_certmanager_CertificateRenewalRecord(in, out, s)
+}
+
+func autoConvert_certmanager_CertificateRenewalRecord_To_v1alpha2_CertificateRenewalRecord(in *certmanager.CertificateRenewalRecord, out *v1alpha2.CertificateRenewalRecord, s conversion.Scope) error {
+	out.Time = in.Time
+	out.Result = v1alpha2.CertificateRenewalResult(in.Result)
+	if err := s.Convert(&in.IssuerRef, &out.IssuerRef, 0); err != nil {
+		return err
+	}
+	out.Revision = (*int)(unsafe.Pointer(in.Revision))
+	return nil
+}
+
+// Convert_certmanager_CertificateRenewalRecord_To_v1alpha2_CertificateRenewalRecord is an autogenerated conversion function.
+func Convert_certmanager_CertificateRenewalRecord_To_v1alpha2_CertificateRenewalRecord(in *certmanager.CertificateRenewalRecord, out *v1alpha2.CertificateRenewalRecord, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificateRenewalRecord_To_v1alpha2_CertificateRenewalRecord(in, out, s)
+}
+
+func autoConvert_v1alpha2_CertificateRenewalSchedule_To_certmanager_CertificateRenewalSchedule(in *v1alpha2.CertificateRenewalSchedule, out *certmanager.CertificateRenewalSchedule, s conversion.Scope) error {
+	out.Days = *(*[]string)(unsafe.Pointer(&in.Days))
+	out.StartHour = in.StartHour
+	out.EndHour = in.EndHour
+	out.TimeZone = in.TimeZone
+	return nil
+}
+
+// Convert_v1alpha2_CertificateRenewalSchedule_To_certmanager_CertificateRenewalSchedule is an autogenerated conversion function.
+func Convert_v1alpha2_CertificateRenewalSchedule_To_certmanager_CertificateRenewalSchedule(in *v1alpha2.CertificateRenewalSchedule, out *certmanager.CertificateRenewalSchedule, s conversion.Scope) error {
+	return autoConvert_v1alpha2_CertificateRenewalSchedule_To_certmanager_CertificateRenewalSchedule(in, out, s)
+}
+
+func autoConvert_certmanager_CertificateRenewalSchedule_To_v1alpha2_CertificateRenewalSchedule(in *certmanager.CertificateRenewalSchedule, out *v1alpha2.CertificateRenewalSchedule, s conversion.Scope) error {
+	out.Days = *(*[]string)(unsafe.Pointer(&in.Days))
+	out.StartHour = in.StartHour
+	out.EndHour = in.EndHour
+	out.TimeZone = in.TimeZone
+	return nil
+}
+
+// Convert_certmanager_CertificateRenewalSchedule_To_v1alpha2_CertificateRenewalSchedule is an autogenerated conversion function.
+func Convert_certmanager_CertificateRenewalSchedule_To_v1alpha2_CertificateRenewalSchedule(in *certmanager.CertificateRenewalSchedule, out *v1alpha2.CertificateRenewalSchedule, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificateRenewalSchedule_To_v1alpha2_CertificateRenewalSchedule(in, out, s)
+}
+
 func autoConvert_v1alpha2_CertificateRequest_To_certmanager_CertificateRequest(in *v1alpha2.CertificateRequest, out *certmanager.CertificateRequest, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha2_CertificateRequestSpec_To_certmanager_CertificateRequestSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -692,6 +984,34 @@ func Convert_certmanager_CertificateRequestStatus_To_v1alpha2_CertificateRequest
 	return autoConvert_certmanager_CertificateRequestStatus_To_v1alpha2_CertificateRequestStatus(in, out, s)
 }
 
+func autoConvert_v1alpha2_CertificateSecretTemplate_To_certmanager_CertificateSecretTemplate(in *v1alpha2.CertificateSecretTemplate, out *certmanager.CertificateSecretTemplate, s conversion.Scope) error {
+	out.CertificateKeys = *(*[]string)(unsafe.Pointer(&in.CertificateKeys))
+	out.PrivateKeyKeys = *(*[]string)(unsafe.Pointer(&in.PrivateKeyKeys))
+	out.Annotations = *(*map[string]string)(unsafe.Pointer(&in.Annotations))
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.OwnerReferences = *(*[]v1.OwnerReference)(unsafe.Pointer(&in.OwnerReferences))
+	return nil
+}
+
+// Convert_v1alpha2_CertificateSecretTemplate_To_certmanager_CertificateSecretTemplate is an autogenerated conversion function.
+func Convert_v1alpha2_CertificateSecretTemplate_To_certmanager_CertificateSecretTemplate(in *v1alpha2.CertificateSecretTemplate, out *certmanager.CertificateSecretTemplate, s conversion.Scope) error {
+	return autoConvert_v1alpha2_CertificateSecretTemplate_To_certmanager_CertificateSecretTemplate(in, out, s)
+}
+
+func autoConvert_certmanager_CertificateSecretTemplate_To_v1alpha2_CertificateSecretTemplate(in *certmanager.CertificateSecretTemplate, out *v1alpha2.CertificateSecretTemplate, s conversion.Scope) error {
+	out.CertificateKeys = *(*[]string)(unsafe.Pointer(&in.CertificateKeys))
+	out.PrivateKeyKeys = *(*[]string)(unsafe.Pointer(&in.PrivateKeyKeys))
+	out.Annotations = *(*map[string]string)(unsafe.Pointer(&in.Annotations))
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.OwnerReferences = *(*[]v1.OwnerReference)(unsafe.Pointer(&in.OwnerReferences))
+	return nil
+}
+
+// Convert_certmanager_CertificateSecretTemplate_To_v1alpha2_CertificateSecretTemplate is an autogenerated conversion function.
+func Convert_certmanager_CertificateSecretTemplate_To_v1alpha2_CertificateSecretTemplate(in *certmanager.CertificateSecretTemplate, out *v1alpha2.CertificateSecretTemplate, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificateSecretTemplate_To_v1alpha2_CertificateSecretTemplate(in, out, s)
+}
+
 func autoConvert_v1alpha2_CertificateSpec_To_certmanager_CertificateSpec(in *v1alpha2.CertificateSpec, out *certmanager.CertificateSpec, s conversion.Scope) error {
 	if in.Subject != nil {
 		in, out := &in.Subject, &out.Subject
@@ -732,6 +1052,14 @@ func autoConvert_v1alpha2_CertificateSpec_To_certmanager_CertificateSpec(in *v1a
 	}
 	out.EncodeUsagesInRequest = (*bool)(unsafe.Pointer(in.EncodeUsagesInRequest))
 	out.RevisionHistoryLimit = (*int32)(unsafe.Pointer(in.RevisionHistoryLimit))
+	out.SecretTemplate = (*certmanager.CertificateSecretTemplate)(unsafe.Pointer(in.SecretTemplate))
+	out.ServiceSelector = (*v1.LabelSelector)(unsafe.Pointer(in.ServiceSelector))
+	out.RenewalSchedule = (*certmanager.CertificateRenewalSchedule)(unsafe.Pointer(in.RenewalSchedule))
+	out.OCSPStapling = (*certmanager.CertificateOCSPStapling)(unsafe.Pointer(in.OCSPStapling))
+	out.AddCommonNameToDNSNames = (*bool)(unsafe.Pointer(in.AddCommonNameToDNSNames))
+	out.Replicas = (*int32)(unsafe.Pointer(in.Replicas))
+	out.IssuanceTimeout = (*v1.Duration)(unsafe.Pointer(in.IssuanceTimeout))
+	out.CertificatePolicies = *(*[]certmanager.CertificatePolicy)(unsafe.Pointer(&in.CertificatePolicies))
 	return nil
 }
 
@@ -771,6 +1099,14 @@ func autoConvert_certmanager_CertificateSpec_To_v1alpha2_CertificateSpec(in *cer
 	}
 	out.EncodeUsagesInRequest = (*bool)(unsafe.Pointer(in.EncodeUsagesInRequest))
 	out.RevisionHistoryLimit = (*int32)(unsafe.Pointer(in.RevisionHistoryLimit))
+	out.SecretTemplate = (*v1alpha2.CertificateSecretTemplate)(unsafe.Pointer(in.SecretTemplate))
+	out.ServiceSelector = (*v1.LabelSelector)(unsafe.Pointer(in.ServiceSelector))
+	out.RenewalSchedule = (*v1alpha2.CertificateRenewalSchedule)(unsafe.Pointer(in.RenewalSchedule))
+	out.OCSPStapling = (*v1alpha2.CertificateOCSPStapling)(unsafe.Pointer(in.OCSPStapling))
+	out.AddCommonNameToDNSNames = (*bool)(unsafe.Pointer(in.AddCommonNameToDNSNames))
+	out.Replicas = (*int32)(unsafe.Pointer(in.Replicas))
+	out.IssuanceTimeout = (*v1.Duration)(unsafe.Pointer(in.IssuanceTimeout))
+	out.CertificatePolicies = *(*[]v1alpha2.CertificatePolicy)(unsafe.Pointer(&in.CertificatePolicies))
 	return nil
 }
 
@@ -782,6 +1118,17 @@ func autoConvert_v1alpha2_CertificateStatus_To_certmanager_CertificateStatus(in
 	out.RenewalTime = (*v1.Time)(unsafe.Pointer(in.RenewalTime))
 	out.Revision = (*int)(unsafe.Pointer(in.Revision))
 	out.NextPrivateKeySecretName = (*string)(unsafe.Pointer(in.NextPrivateKeySecretName))
+	if in.RenewalHistory != nil {
+		in, out := &in.RenewalHistory, &out.RenewalHistory
+		*out = make([]certmanager.CertificateRenewalRecord, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_CertificateRenewalRecord_To_certmanager_CertificateRenewalRecord(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.RenewalHistory = nil
+	}
 	return nil
 }
 
@@ -798,6 +1145,17 @@ func autoConvert_certmanager_CertificateStatus_To_v1alpha2_CertificateStatus(in
 	out.RenewalTime = (*v1.Time)(unsafe.Pointer(in.RenewalTime))
 	out.Revision = (*int)(unsafe.Pointer(in.Revision))
 	out.NextPrivateKeySecretName = (*string)(unsafe.Pointer(in.NextPrivateKeySecretName))
+	if in.RenewalHistory != nil {
+		in, out := &in.RenewalHistory, &out.RenewalHistory
+		*out = make([]v1alpha2.CertificateRenewalRecord, len(*in))
+		for i := range *in {
+			if err := Convert_certmanager_CertificateRenewalRecord_To_v1alpha2_CertificateRenewalRecord(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.RenewalHistory = nil
+	}
 	return nil
 }
 
@@ -892,6 +1250,26 @@ func Convert_certmanager_Issuer_To_v1alpha2_Issuer(in *certmanager.Issuer, out *
 	return autoConvert_certmanager_Issuer_To_v1alpha2_Issuer(in, out, s)
 }
 
+func autoConvert_v1alpha2_IssuerChainValidation_To_certmanager_IssuerChainValidation(in *v1alpha2.IssuerChainValidation, out *certmanager.IssuerChainValidation, s conversion.Scope) error {
+	out.TrustAnchors = *(*[]byte)(unsafe.Pointer(&in.TrustAnchors))
+	return nil
+}
+
+// Convert_v1alpha2_IssuerChainValidation_To_certmanager_IssuerChainValidation is an autogenerated conversion function.
+func Convert_v1alpha2_IssuerChainValidation_To_certmanager_IssuerChainValidation(in *v1alpha2.IssuerChainValidation, out *certmanager.IssuerChainValidation, s conversion.Scope) error {
+	return autoConvert_v1alpha2_IssuerChainValidation_To_certmanager_IssuerChainValidation(in, out, s)
+}
+
+func autoConvert_certmanager_IssuerChainValidation_To_v1alpha2_IssuerChainValidation(in *certmanager.IssuerChainValidation, out *v1alpha2.IssuerChainValidation, s conversion.Scope) error {
+	out.TrustAnchors = *(*[]byte)(unsafe.Pointer(&in.TrustAnchors))
+	return nil
+}
+
+// Convert_certmanager_IssuerChainValidation_To_v1alpha2_IssuerChainValidation is an autogenerated conversion function.
+func Convert_certmanager_IssuerChainValidation_To_v1alpha2_IssuerChainValidation(in *certmanager.IssuerChainValidation, out *v1alpha2.IssuerChainValidation, s conversion.Scope) error {
+	return autoConvert_certmanager_IssuerChainValidation_To_v1alpha2_IssuerChainValidation(in, out, s)
+}
+
 func autoConvert_v1alpha2_IssuerCondition_To_certmanager_IssuerCondition(in *v1alpha2.IssuerCondition, out *certmanager.IssuerCondition, s conversion.Scope) error {
 	out.Type = certmanager.IssuerConditionType(in.Type)
 	out.Status = meta.ConditionStatus(in.Status)
@@ -928,6 +1306,7 @@ func autoConvert_v1alpha2_IssuerConfig_To_certmanager_IssuerConfig(in *v1alpha2.
 	out.Vault = (*certmanager.VaultIssuer)(unsafe.Pointer(in.Vault))
 	out.SelfSigned = (*certmanager.SelfSignedIssuer)(unsafe.Pointer(in.SelfSigned))
 	out.Venafi = (*certmanager.VenafiIssuer)(unsafe.Pointer(in.Venafi))
+	out.ADCSWebEnrollment = (*certmanager.ADCSWebEnrollmentIssuer)(unsafe.Pointer(in.ADCSWebEnrollment))
 	return nil
 }
 
@@ -942,6 +1321,7 @@ func autoConvert_certmanager_IssuerConfig_To_v1alpha2_IssuerConfig(in *certmanag
 	out.Vault = (*v1alpha2.VaultIssuer)(unsafe.Pointer(in.Vault))
 	out.SelfSigned = (*v1alpha2.SelfSignedIssuer)(unsafe.Pointer(in.SelfSigned))
 	out.Venafi = (*v1alpha2.VenafiIssuer)(unsafe.Pointer(in.Venafi))
+	out.ADCSWebEnrollment = (*v1alpha2.ADCSWebEnrollmentIssuer)(unsafe.Pointer(in.ADCSWebEnrollment))
 	return nil
 }
 
@@ -976,6 +1356,7 @@ func autoConvert_v1alpha2_IssuerSpec_To_certmanager_IssuerSpec(in *v1alpha2.Issu
 	if err := Convert_v1alpha2_IssuerConfig_To_certmanager_IssuerConfig(&in.IssuerConfig, &out.IssuerConfig, s); err != nil {
 		return err
 	}
+	out.ChainValidation = (*certmanager.IssuerChainValidation)(unsafe.Pointer(in.ChainValidation))
 	return nil
 }
 
@@ -988,6 +1369,7 @@ func autoConvert_certmanager_IssuerSpec_To_v1alpha2_IssuerSpec(in *certmanager.I
 	if err := Convert_certmanager_IssuerConfig_To_v1alpha2_IssuerConfig(&in.IssuerConfig, &out.IssuerConfig, s); err != nil {
 		return err
 	}
+	out.ChainValidation = (*v1alpha2.IssuerChainValidation)(unsafe.Pointer(in.ChainValidation))
 	return nil
 }
 
@@ -1024,6 +1406,8 @@ func autoConvert_v1alpha2_JKSKeystore_To_certmanager_JKSKeystore(in *v1alpha2.JK
 	if err := s.Convert(&in.PasswordSecretRef, &out.PasswordSecretRef, 0); err != nil {
 		return err
 	}
+	out.Alias = in.Alias
+	out.IncludeChain = in.IncludeChain
 	return nil
 }
 
@@ -1038,6 +1422,8 @@ func autoConvert_certmanager_JKSKeystore_To_v1alpha2_JKSKeystore(in *certmanager
 	if err := s.Convert(&in.PasswordSecretRef, &out.PasswordSecretRef, 0); err != nil {
 		return err
 	}
+	out.Alias = in.Alias
+	out.IncludeChain = in.IncludeChain
 	return nil
 }
 
@@ -1052,6 +1438,7 @@ func autoConvert_v1alpha2_PKCS12Keystore_To_certmanager_PKCS12Keystore(in *v1alp
 	if err := s.Convert(&in.PasswordSecretRef, &out.PasswordSecretRef, 0); err != nil {
 		return err
 	}
+	out.Profile = certmanager.PKCS12Profile(in.Profile)
 	return nil
 }
 
@@ -1066,6 +1453,7 @@ func autoConvert_certmanager_PKCS12Keystore_To_v1alpha2_PKCS12Keystore(in *certm
 	if err := s.Convert(&in.PasswordSecretRef, &out.PasswordSecretRef, 0); err != nil {
 		return err
 	}
+	out.Profile = v1alpha2.PKCS12Profile(in.Profile)
 	return nil
 }
 
@@ -1076,6 +1464,11 @@ func Convert_certmanager_PKCS12Keystore_To_v1alpha2_PKCS12Keystore(in *certmanag
 
 func autoConvert_v1alpha2_SelfSignedIssuer_To_certmanager_SelfSignedIssuer(in *v1alpha2.SelfSignedIssuer, out *certmanager.SelfSignedIssuer, s conversion.Scope) error {
 	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
+	out.SubjectKeyIdentifierMethod = certmanager.SubjectKeyIdentifierMethod(in.SubjectKeyIdentifierMethod)
+	out.KeySeedSecretRef = (*meta.SecretKeySelector)(unsafe.Pointer(in.KeySeedSecretRef))
+	out.CertificateProfile = certmanager.CertificateProfile(in.CertificateProfile)
+	out.CertificatePolicies = *(*[]certmanager.CertificatePolicy)(unsafe.Pointer(&in.CertificatePolicies))
+	out.AllowedCertificatePolicies = *(*[]certmanager.CertificatePolicy)(unsafe.Pointer(&in.AllowedCertificatePolicies))
 	return nil
 }
 
@@ -1086,6 +1479,11 @@ func Convert_v1alpha2_SelfSignedIssuer_To_certmanager_SelfSignedIssuer(in *v1alp
 
 func autoConvert_certmanager_SelfSignedIssuer_To_v1alpha2_SelfSignedIssuer(in *certmanager.SelfSignedIssuer, out *v1alpha2.SelfSignedIssuer, s conversion.Scope) error {
 	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
+	out.SubjectKeyIdentifierMethod = v1alpha2.SubjectKeyIdentifierMethod(in.SubjectKeyIdentifierMethod)
+	out.KeySeedSecretRef = (*metav1.SecretKeySelector)(unsafe.Pointer(in.KeySeedSecretRef))
+	out.CertificateProfile = v1alpha2.CertificateProfile(in.CertificateProfile)
+	out.CertificatePolicies = *(*[]v1alpha2.CertificatePolicy)(unsafe.Pointer(&in.CertificatePolicies))
+	out.AllowedCertificatePolicies = *(*[]v1alpha2.CertificatePolicy)(unsafe.Pointer(&in.AllowedCertificatePolicies))
 	return nil
 }
 
@@ -1156,6 +1554,7 @@ func autoConvert_v1alpha2_VaultIssuer_To_certmanager_VaultIssuer(in *v1alpha2.Va
 	out.Path = in.Path
 	out.Namespace = in.Namespace
 	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	out.ExpectedRootCA = *(*[]byte)(unsafe.Pointer(&in.ExpectedRootCA))
 	return nil
 }
 
@@ -1172,6 +1571,7 @@ func autoConvert_certmanager_VaultIssuer_To_v1alpha2_VaultIssuer(in *certmanager
 	out.Path = in.Path
 	out.Namespace = in.Namespace
 	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	out.ExpectedRootCA = *(*[]byte)(unsafe.Pointer(&in.ExpectedRootCA))
 	return nil
 }
 
@@ -1187,6 +1587,7 @@ func autoConvert_v1alpha2_VaultKubernetesAuth_To_certmanager_VaultKubernetesAuth
 		return err
 	}
 	out.Role = in.Role
+	out.TokenPath = in.TokenPath
 	return nil
 }
 
@@ -1202,6 +1603,7 @@ func autoConvert_certmanager_VaultKubernetesAuth_To_v1alpha2_VaultKubernetesAuth
 		return err
 	}
 	out.Role = in.Role
+	out.TokenPath = in.TokenPath
 	return nil
 }
 
@@ -1238,10 +1640,33 @@ func Convert_certmanager_VenafiCloud_To_v1alpha2_VenafiCloud(in *certmanager.Ven
 	return autoConvert_certmanager_VenafiCloud_To_v1alpha2_VenafiCloud(in, out, s)
 }
 
+func autoConvert_v1alpha2_VenafiCustomFieldAnnotation_To_certmanager_VenafiCustomFieldAnnotation(in *v1alpha2.VenafiCustomFieldAnnotation, out *certmanager.VenafiCustomFieldAnnotation, s conversion.Scope) error {
+	out.Annotation = in.Annotation
+	out.CustomField = in.CustomField
+	return nil
+}
+
+// Convert_v1alpha2_VenafiCustomFieldAnnotation_To_certmanager_VenafiCustomFieldAnnotation is an autogenerated conversion function.
+func Convert_v1alpha2_VenafiCustomFieldAnnotation_To_certmanager_VenafiCustomFieldAnnotation(in *v1alpha2.VenafiCustomFieldAnnotation, out *certmanager.VenafiCustomFieldAnnotation, s conversion.Scope) error {
+	return autoConvert_v1alpha2_VenafiCustomFieldAnnotation_To_certmanager_VenafiCustomFieldAnnotation(in, out, s)
+}
+
+func autoConvert_certmanager_VenafiCustomFieldAnnotation_To_v1alpha2_VenafiCustomFieldAnnotation(in *certmanager.VenafiCustomFieldAnnotation, out *v1alpha2.VenafiCustomFieldAnnotation, s conversion.Scope) error {
+	out.Annotation = in.Annotation
+	out.CustomField = in.CustomField
+	return nil
+}
+
+// Convert_certmanager_VenafiCustomFieldAnnotation_To_v1alpha2_VenafiCustomFieldAnnotation is an autogenerated conversion function.
+func Convert_certmanager_VenafiCustomFieldAnnotation_To_v1alpha2_VenafiCustomFieldAnnotation(in *certmanager.VenafiCustomFieldAnnotation, out *v1alpha2.VenafiCustomFieldAnnotation, s conversion.Scope) error {
+	return autoConvert_certmanager_VenafiCustomFieldAnnotation_To_v1alpha2_VenafiCustomFieldAnnotation(in, out, s)
+}
+
 func autoConvert_v1alpha2_VenafiIssuer_To_certmanager_VenafiIssuer(in *v1alpha2.VenafiIssuer, out *certmanager.VenafiIssuer, s conversion.Scope) error {
 	out.Zone = in.Zone
 	out.TPP = (*certmanager.VenafiTPP)(unsafe.Pointer(in.TPP))
 	out.Cloud = (*certmanager.VenafiCloud)(unsafe.Pointer(in.Cloud))
+	out.CustomFieldAnnotations = *(*[]certmanager.VenafiCustomFieldAnnotation)(unsafe.Pointer(&in.CustomFieldAnnotations))
 	return nil
 }
 
@@ -1254,6 +1679,7 @@ func autoConvert_certmanager_VenafiIssuer_To_v1alpha2_VenafiIssuer(in *certmanag
 	out.Zone = in.Zone
 	out.TPP = (*v1alpha2.VenafiTPP)(unsafe.Pointer(in.TPP))
 	out.Cloud = (*v1alpha2.VenafiCloud)(unsafe.Pointer(in.Cloud))
+	out.CustomFieldAnnotations = *(*[]v1alpha2.VenafiCustomFieldAnnotation)(unsafe.Pointer(&in.CustomFieldAnnotations))
 	return nil
 }
 
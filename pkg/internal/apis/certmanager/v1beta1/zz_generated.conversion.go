@@ -41,6 +41,26 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*v1beta1.ADCSWebEnrollmentIssuer)(nil), (*certmanager.ADCSWebEnrollmentIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ADCSWebEnrollmentIssuer_To_certmanager_ADCSWebEnrollmentIssuer(a.(*v1beta1.ADCSWebEnrollmentIssuer), b.(*certmanager.ADCSWebEnrollmentIssuer), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.ADCSWebEnrollmentIssuer)(nil), (*v1beta1.ADCSWebEnrollmentIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_ADCSWebEnrollmentIssuer_To_v1beta1_ADCSWebEnrollmentIssuer(a.(*certmanager.ADCSWebEnrollmentIssuer), b.(*v1beta1.ADCSWebEnrollmentIssuer), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.CACRL)(nil), (*certmanager.CACRL)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_CACRL_To_certmanager_CACRL(a.(*v1beta1.CACRL), b.(*certmanager.CACRL), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CACRL)(nil), (*v1beta1.CACRL)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CACRL_To_v1beta1_CACRL(a.(*certmanager.CACRL), b.(*v1beta1.CACRL), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.CAIssuer)(nil), (*certmanager.CAIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_CAIssuer_To_certmanager_CAIssuer(a.(*v1beta1.CAIssuer), b.(*certmanager.CAIssuer), scope)
 	}); err != nil {
@@ -51,6 +71,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.CARevokedCertificate)(nil), (*certmanager.CARevokedCertificate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_CARevokedCertificate_To_certmanager_CARevokedCertificate(a.(*v1beta1.CARevokedCertificate), b.(*certmanager.CARevokedCertificate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CARevokedCertificate)(nil), (*v1beta1.CARevokedCertificate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CARevokedCertificate_To_v1beta1_CARevokedCertificate(a.(*certmanager.CARevokedCertificate), b.(*v1beta1.CARevokedCertificate), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.Certificate)(nil), (*certmanager.Certificate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_Certificate_To_certmanager_Certificate(a.(*v1beta1.Certificate), b.(*certmanager.Certificate), scope)
 	}); err != nil {
@@ -91,6 +121,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.CertificateOCSPStapling)(nil), (*certmanager.CertificateOCSPStapling)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_CertificateOCSPStapling_To_certmanager_CertificateOCSPStapling(a.(*v1beta1.CertificateOCSPStapling), b.(*certmanager.CertificateOCSPStapling), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificateOCSPStapling)(nil), (*v1beta1.CertificateOCSPStapling)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificateOCSPStapling_To_v1beta1_CertificateOCSPStapling(a.(*certmanager.CertificateOCSPStapling), b.(*v1beta1.CertificateOCSPStapling), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.CertificatePolicy)(nil), (*certmanager.CertificatePolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_CertificatePolicy_To_certmanager_CertificatePolicy(a.(*v1beta1.CertificatePolicy), b.(*certmanager.CertificatePolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificatePolicy)(nil), (*v1beta1.CertificatePolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificatePolicy_To_v1beta1_CertificatePolicy(a.(*certmanager.CertificatePolicy), b.(*v1beta1.CertificatePolicy), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.CertificatePrivateKey)(nil), (*certmanager.CertificatePrivateKey)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_CertificatePrivateKey_To_certmanager_CertificatePrivateKey(a.(*v1beta1.CertificatePrivateKey), b.(*certmanager.CertificatePrivateKey), scope)
 	}); err != nil {
@@ -101,6 +151,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.CertificateRenewalRecord)(nil), (*certmanager.CertificateRenewalRecord)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_CertificateRenewalRecord_To_certmanager_CertificateRenewalRecord(a.(*v1beta1.CertificateRenewalRecord), b.(*certmanager.CertificateRenewalRecord), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificateRenewalRecord)(nil), (*v1beta1.CertificateRenewalRecord)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificateRenewalRecord_To_v1beta1_CertificateRenewalRecord(a.(*certmanager.CertificateRenewalRecord), b.(*v1beta1.CertificateRenewalRecord), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.CertificateRenewalSchedule)(nil), (*certmanager.CertificateRenewalSchedule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_CertificateRenewalSchedule_To_certmanager_CertificateRenewalSchedule(a.(*v1beta1.CertificateRenewalSchedule), b.(*certmanager.CertificateRenewalSchedule), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificateRenewalSchedule)(nil), (*v1beta1.CertificateRenewalSchedule)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificateRenewalSchedule_To_v1beta1_CertificateRenewalSchedule(a.(*certmanager.CertificateRenewalSchedule), b.(*v1beta1.CertificateRenewalSchedule), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.CertificateRequest)(nil), (*certmanager.CertificateRequest)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_CertificateRequest_To_certmanager_CertificateRequest(a.(*v1beta1.CertificateRequest), b.(*certmanager.CertificateRequest), scope)
 	}); err != nil {
@@ -151,6 +221,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.CertificateSecretTemplate)(nil), (*certmanager.CertificateSecretTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_CertificateSecretTemplate_To_certmanager_CertificateSecretTemplate(a.(*v1beta1.CertificateSecretTemplate), b.(*certmanager.CertificateSecretTemplate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.CertificateSecretTemplate)(nil), (*v1beta1.CertificateSecretTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_CertificateSecretTemplate_To_v1beta1_CertificateSecretTemplate(a.(*certmanager.CertificateSecretTemplate), b.(*v1beta1.CertificateSecretTemplate), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.CertificateSpec)(nil), (*certmanager.CertificateSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_CertificateSpec_To_certmanager_CertificateSpec(a.(*v1beta1.CertificateSpec), b.(*certmanager.CertificateSpec), scope)
 	}); err != nil {
@@ -201,6 +281,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.IssuerChainValidation)(nil), (*certmanager.IssuerChainValidation)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_IssuerChainValidation_To_certmanager_IssuerChainValidation(a.(*v1beta1.IssuerChainValidation), b.(*certmanager.IssuerChainValidation), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.IssuerChainValidation)(nil), (*v1beta1.IssuerChainValidation)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_IssuerChainValidation_To_v1beta1_IssuerChainValidation(a.(*certmanager.IssuerChainValidation), b.(*v1beta1.IssuerChainValidation), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.IssuerCondition)(nil), (*certmanager.IssuerCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_IssuerCondition_To_certmanager_IssuerCondition(a.(*v1beta1.IssuerCondition), b.(*certmanager.IssuerCondition), scope)
 	}); err != nil {
@@ -331,6 +421,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1beta1.VenafiCustomFieldAnnotation)(nil), (*certmanager.VenafiCustomFieldAnnotation)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_VenafiCustomFieldAnnotation_To_certmanager_VenafiCustomFieldAnnotation(a.(*v1beta1.VenafiCustomFieldAnnotation), b.(*certmanager.VenafiCustomFieldAnnotation), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*certmanager.VenafiCustomFieldAnnotation)(nil), (*v1beta1.VenafiCustomFieldAnnotation)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_certmanager_VenafiCustomFieldAnnotation_To_v1beta1_VenafiCustomFieldAnnotation(a.(*certmanager.VenafiCustomFieldAnnotation), b.(*v1beta1.VenafiCustomFieldAnnotation), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1beta1.VenafiIssuer)(nil), (*certmanager.VenafiIssuer)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_VenafiIssuer_To_certmanager_VenafiIssuer(a.(*v1beta1.VenafiIssuer), b.(*certmanager.VenafiIssuer), scope)
 	}); err != nil {
@@ -364,10 +464,74 @@ func RegisterConversions(s *runtime.Scheme) error {
 	return nil
 }
 
+func autoConvert_v1beta1_ADCSWebEnrollmentIssuer_To_certmanager_ADCSWebEnrollmentIssuer(in *v1beta1.ADCSWebEnrollmentIssuer, out *certmanager.ADCSWebEnrollmentIssuer, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Template = in.Template
+	// TODO: Inefficient conversion - can we improve it?
+	if err := s.Convert(&in.CredentialsRef, &out.CredentialsRef, 0); err != nil {
+		return err
+	}
+	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	return nil
+}
+
+// Convert_v1beta1_ADCSWebEnrollmentIssuer_To_certmanager_ADCSWebEnrollmentIssuer is an autogenerated conversion function.
+func Convert_v1beta1_ADCSWebEnrollmentIssuer_To_certmanager_ADCSWebEnrollmentIssuer(in *v1beta1.ADCSWebEnrollmentIssuer, out *certmanager.ADCSWebEnrollmentIssuer, s conversion.Scope) error {
+	return autoConvert_v1beta1_ADCSWebEnrollmentIssuer_To_certmanager_ADCSWebEnrollmentIssuer(in, out, s)
+}
+
+func autoConvert_certmanager_ADCSWebEnrollmentIssuer_To_v1beta1_ADCSWebEnrollmentIssuer(in *certmanager.ADCSWebEnrollmentIssuer, out *v1beta1.ADCSWebEnrollmentIssuer, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Template = in.Template
+	// TODO: Inefficient conversion - can we improve it?
+	if err := s.Convert(&in.CredentialsRef, &out.CredentialsRef, 0); err != nil {
+		return err
+	}
+	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	return nil
+}
+
+// Convert_certmanager_ADCSWebEnrollmentIssuer_To_v1beta1_ADCSWebEnrollmentIssuer is an autogenerated conversion function.
+func Convert_certmanager_ADCSWebEnrollmentIssuer_To_v1beta1_ADCSWebEnrollmentIssuer(in *certmanager.ADCSWebEnrollmentIssuer, out *v1beta1.ADCSWebEnrollmentIssuer, s conversion.Scope) error {
+	return autoConvert_certmanager_ADCSWebEnrollmentIssuer_To_v1beta1_ADCSWebEnrollmentIssuer(in, out, s)
+}
+
+func autoConvert_v1beta1_CACRL_To_certmanager_CACRL(in *v1beta1.CACRL, out *certmanager.CACRL, s conversion.Scope) error {
+	out.SecretName = in.SecretName
+	out.Validity = (*v1.Duration)(unsafe.Pointer(in.Validity))
+	out.RevokedCertificates = *(*[]certmanager.CARevokedCertificate)(unsafe.Pointer(&in.RevokedCertificates))
+	return nil
+}
+
+// Convert_v1beta1_CACRL_To_certmanager_CACRL is an autogenerated conversion function.
+func Convert_v1beta1_CACRL_To_certmanager_CACRL(in *v1beta1.CACRL, out *certmanager.CACRL, s conversion.Scope) error {
+	return autoConvert_v1beta1_CACRL_To_certmanager_CACRL(in, out, s)
+}
+
+func autoConvert_certmanager_CACRL_To_v1beta1_CACRL(in *certmanager.CACRL, out *v1beta1.CACRL, s conversion.Scope) error {
+	out.SecretName = in.SecretName
+	out.Validity = (*v1.Duration)(unsafe.Pointer(in.Validity))
+	out.RevokedCertificates = *(*[]v1beta1.CARevokedCertificate)(unsafe.Pointer(&in.RevokedCertificates))
+	return nil
+}
+
+// Convert_certmanager_CACRL_To_v1beta1_CACRL is an autogenerated conversion function.
+func Convert_certmanager_CACRL_To_v1beta1_CACRL(in *certmanager.CACRL, out *v1beta1.CACRL, s conversion.Scope) error {
+	return autoConvert_certmanager_CACRL_To_v1beta1_CACRL(in, out, s)
+}
+
 func autoConvert_v1beta1_CAIssuer_To_certmanager_CAIssuer(in *v1beta1.CAIssuer, out *certmanager.CAIssuer, s conversion.Scope) error {
 	out.SecretName = in.SecretName
 	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
 	out.OCSPServers = *(*[]string)(unsafe.Pointer(&in.OCSPServers))
+	out.AllowCAIssuance = in.AllowCAIssuance
+	out.CRL = (*certmanager.CACRL)(unsafe.Pointer(in.CRL))
+	out.SubjectKeyIdentifierMethod = certmanager.SubjectKeyIdentifierMethod(in.SubjectKeyIdentifierMethod)
+	out.MaxLeafDuration = (*v1.Duration)(unsafe.Pointer(in.MaxLeafDuration))
+	out.MaxLeafDurationPolicy = certmanager.MaxLeafDurationPolicy(in.MaxLeafDurationPolicy)
+	out.CertificateProfile = certmanager.CertificateProfile(in.CertificateProfile)
+	out.CertificatePolicies = *(*[]certmanager.CertificatePolicy)(unsafe.Pointer(&in.CertificatePolicies))
+	out.AllowedCertificatePolicies = *(*[]certmanager.CertificatePolicy)(unsafe.Pointer(&in.AllowedCertificatePolicies))
 	return nil
 }
 
@@ -380,6 +544,14 @@ func autoConvert_certmanager_CAIssuer_To_v1beta1_CAIssuer(in *certmanager.CAIssu
 	out.SecretName = in.SecretName
 	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
 	out.OCSPServers = *(*[]string)(unsafe.Pointer(&in.OCSPServers))
+	out.AllowCAIssuance = in.AllowCAIssuance
+	out.CRL = (*v1beta1.CACRL)(unsafe.Pointer(in.CRL))
+	out.SubjectKeyIdentifierMethod = v1beta1.SubjectKeyIdentifierMethod(in.SubjectKeyIdentifierMethod)
+	out.MaxLeafDuration = (*v1.Duration)(unsafe.Pointer(in.MaxLeafDuration))
+	out.MaxLeafDurationPolicy = v1beta1.MaxLeafDurationPolicy(in.MaxLeafDurationPolicy)
+	out.CertificateProfile = v1beta1.CertificateProfile(in.CertificateProfile)
+	out.CertificatePolicies = *(*[]v1beta1.CertificatePolicy)(unsafe.Pointer(&in.CertificatePolicies))
+	out.AllowedCertificatePolicies = *(*[]v1beta1.CertificatePolicy)(unsafe.Pointer(&in.AllowedCertificatePolicies))
 	return nil
 }
 
@@ -388,6 +560,28 @@ func Convert_certmanager_CAIssuer_To_v1beta1_CAIssuer(in *certmanager.CAIssuer,
 	return autoConvert_certmanager_CAIssuer_To_v1beta1_CAIssuer(in, out, s)
 }
 
+func autoConvert_v1beta1_CARevokedCertificate_To_certmanager_CARevokedCertificate(in *v1beta1.CARevokedCertificate, out *certmanager.CARevokedCertificate, s conversion.Scope) error {
+	out.SerialNumber = in.SerialNumber
+	out.RevocationTime = in.RevocationTime
+	return nil
+}
+
+// Convert_v1beta1_CARevokedCertificate_To_certmanager_CARevokedCertificate is an autogenerated conversion function.
+func Convert_v1beta1_CARevokedCertificate_To_certmanager_CARevokedCertificate(in *v1beta1.CARevokedCertificate, out *certmanager.CARevokedCertificate, s conversion.Scope) error {
+	return autoConvert_v1beta1_CARevokedCertificate_To_certmanager_CARevokedCertificate(in, out, s)
+}
+
+func autoConvert_certmanager_CARevokedCertificate_To_v1beta1_CARevokedCertificate(in *certmanager.CARevokedCertificate, out *v1beta1.CARevokedCertificate, s conversion.Scope) error {
+	out.SerialNumber = in.SerialNumber
+	out.RevocationTime = in.RevocationTime
+	return nil
+}
+
+// Convert_certmanager_CARevokedCertificate_To_v1beta1_CARevokedCertificate is an autogenerated conversion function.
+func Convert_certmanager_CARevokedCertificate_To_v1beta1_CARevokedCertificate(in *certmanager.CARevokedCertificate, out *v1beta1.CARevokedCertificate, s conversion.Scope) error {
+	return autoConvert_certmanager_CARevokedCertificate_To_v1beta1_CARevokedCertificate(in, out, s)
+}
+
 func autoConvert_v1beta1_Certificate_To_certmanager_Certificate(in *v1beta1.Certificate, out *certmanager.Certificate, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1beta1_CertificateSpec_To_certmanager_CertificateSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -494,6 +688,48 @@ func Convert_certmanager_CertificateList_To_v1beta1_CertificateList(in *certmana
 	return autoConvert_certmanager_CertificateList_To_v1beta1_CertificateList(in, out, s)
 }
 
+func autoConvert_v1beta1_CertificateOCSPStapling_To_certmanager_CertificateOCSPStapling(in *v1beta1.CertificateOCSPStapling, out *certmanager.CertificateOCSPStapling, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	return nil
+}
+
+// Convert_v1beta1_CertificateOCSPStapling_To_certmanager_CertificateOCSPStapling is an autogenerated conversion function.
+func Convert_v1beta1_CertificateOCSPStapling_To_certmanager_CertificateOCSPStapling(in *v1beta1.CertificateOCSPStapling, out *certmanager.CertificateOCSPStapling, s conversion.Scope) error {
+	return autoConvert_v1beta1_CertificateOCSPStapling_To_certmanager_CertificateOCSPStapling(in, out, s)
+}
+
+func autoConvert_certmanager_CertificateOCSPStapling_To_v1beta1_CertificateOCSPStapling(in *certmanager.CertificateOCSPStapling, out *v1beta1.CertificateOCSPStapling, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	return nil
+}
+
+// Convert_certmanager_CertificateOCSPStapling_To_v1beta1_CertificateOCSPStapling is an autogenerated conversion function.
+func Convert_certmanager_CertificateOCSPStapling_To_v1beta1_CertificateOCSPStapling(in *certmanager.CertificateOCSPStapling, out *v1beta1.CertificateOCSPStapling, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificateOCSPStapling_To_v1beta1_CertificateOCSPStapling(in, out, s)
+}
+
+func autoConvert_v1beta1_CertificatePolicy_To_certmanager_CertificatePolicy(in *v1beta1.CertificatePolicy, out *certmanager.CertificatePolicy, s conversion.Scope) error {
+	out.PolicyID = in.PolicyID
+	out.CPSURI = in.CPSURI
+	return nil
+}
+
+// Convert_v1beta1_CertificatePolicy_To_certmanager_CertificatePolicy is an autogenerated conversion function.
+func Convert_v1beta1_CertificatePolicy_To_certmanager_CertificatePolicy(in *v1beta1.CertificatePolicy, out *certmanager.CertificatePolicy, s conversion.Scope) error {
+	return autoConvert_v1beta1_CertificatePolicy_To_certmanager_CertificatePolicy(in, out, s)
+}
+
+func autoConvert_certmanager_CertificatePolicy_To_v1beta1_CertificatePolicy(in *certmanager.CertificatePolicy, out *v1beta1.CertificatePolicy, s conversion.Scope) error {
+	out.PolicyID = in.PolicyID
+	out.CPSURI = in.CPSURI
+	return nil
+}
+
+// Convert_certmanager_CertificatePolicy_To_v1beta1_CertificatePolicy is an autogenerated conversion function.
+func Convert_certmanager_CertificatePolicy_To_v1beta1_CertificatePolicy(in *certmanager.CertificatePolicy, out *v1beta1.CertificatePolicy, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificatePolicy_To_v1beta1_CertificatePolicy(in, out, s)
+}
+
 func autoConvert_v1beta1_CertificatePrivateKey_To_certmanager_CertificatePrivateKey(in *v1beta1.CertificatePrivateKey, out *certmanager.CertificatePrivateKey, s conversion.Scope) error {
 	out.RotationPolicy = certmanager.PrivateKeyRotationPolicy(in.RotationPolicy)
 	out.Encoding = certmanager.PrivateKeyEncoding(in.Encoding)
@@ -520,6 +756,62 @@ func Convert_certmanager_CertificatePrivateKey_To_v1beta1_CertificatePrivateKey(
 	return autoConvert_certmanager_CertificatePrivateKey_To_v1beta1_CertificatePrivateKey(in, out, s)
 }
 
+func autoConvert_v1beta1_CertificateRenewalRecord_To_certmanager_CertificateRenewalRecord(in *v1beta1.CertificateRenewalRecord, out *certmanager.CertificateRenewalRecord, s conversion.Scope) error {
+	out.Time = in.Time
+	out.Result = certmanager.CertificateRenewalResult(in.Result)
+	if err := s.Convert(&in.IssuerRef, &out.IssuerRef, 0); err != nil {
+		return err
+	}
+	out.Revision = (*int)(unsafe.Pointer(in.Revision))
+	return nil
+}
+
+// Convert_v1beta1_CertificateRenewalRecord_To_certmanager_CertificateRenewalRecord is an autogenerated conversion function.
+func Convert_v1beta1_CertificateRenewalRecord_To_certmanager_CertificateRenewalRecord(in *v1beta1.CertificateRenewalRecord, out *certmanager.CertificateRenewalRecord, s conversion.Scope) error {
+	return autoConvert_v1beta1_CertificateRenewalRecord_To_certmanager_CertificateRenewalRecord(in, out, s)
+}
+
+func autoConvert_certmanager_CertificateRenewalRecord_To_v1beta1_CertificateRenewalRecord(in *certmanager.CertificateRenewalRecord, out *v1beta1.CertificateRenewalRecord, s conversion.Scope) error {
+	out.Time = in.Time
+	out.Result = v1beta1.CertificateRenewalResult(in.Result)
+	if err := s.Convert(&in.IssuerRef, &out.IssuerRef, 0); err != nil {
+		return err
+	}
+	out.Revision = (*int)(unsafe.Pointer(in.Revision))
+	return nil
+}
+
+// Convert_certmanager_CertificateRenewalRecord_To_v1beta1_CertificateRenewalRecord is an autogenerated conversion function.
+func Convert_certmanager_CertificateRenewalRecord_To_v1beta1_CertificateRenewalRecord(in *certmanager.CertificateRenewalRecord, out *v1beta1.CertificateRenewalRecord, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificateRenewalRecord_To_v1beta1_CertificateRenewalRecord(in, out, s)
+}
+
+func autoConvert_v1beta1_CertificateRenewalSchedule_To_certmanager_CertificateRenewalSchedule(in *v1beta1.CertificateRenewalSchedule, out *certmanager.CertificateRenewalSchedule, s conversion.Scope) error {
+	out.Days = *(*[]string)(unsafe.Pointer(&in.Days))
+	out.StartHour = in.StartHour
+	out.EndHour = in.EndHour
+	out.TimeZone = in.TimeZone
+	return nil
+}
+
+// Convert_v1beta1_CertificateRenewalSchedule_To_certmanager_CertificateRenewalSchedule is an autogenerated conversion function.
+func Convert_v1beta1_CertificateRenewalSchedule_To_certmanager_CertificateRenewalSchedule(in *v1beta1.CertificateRenewalSchedule, out *certmanager.CertificateRenewalSchedule, s conversion.Scope) error {
+	return autoConvert_v1beta1_CertificateRenewalSchedule_To_certmanager_CertificateRenewalSchedule(in, out, s)
+}
+
+func autoConvert_certmanager_CertificateRenewalSchedule_To_v1beta1_CertificateRenewalSchedule(in *certmanager.CertificateRenewalSchedule, out *v1beta1.CertificateRenewalSchedule, s conversion.Scope) error {
+	out.Days = *(*[]string)(unsafe.Pointer(&in.Days))
+	out.StartHour = in.StartHour
+	out.EndHour = in.EndHour
+	out.TimeZone = in.TimeZone
+	return nil
+}
+
+// Convert_certmanager_CertificateRenewalSchedule_To_v1beta1_CertificateRenewalSchedule is an autogenerated conversion function.
+func Convert_certmanager_CertificateRenewalSchedule_To_v1beta1_CertificateRenewalSchedule(in *certmanager.CertificateRenewalSchedule, out *v1beta1.CertificateRenewalSchedule, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificateRenewalSchedule_To_v1beta1_CertificateRenewalSchedule(in, out, s)
+}
+
 func autoConvert_v1beta1_CertificateRequest_To_certmanager_CertificateRequest(in *v1beta1.CertificateRequest, out *certmanager.CertificateRequest, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1beta1_CertificateRequestSpec_To_certmanager_CertificateRequestSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -670,6 +962,34 @@ func Convert_certmanager_CertificateRequestStatus_To_v1beta1_CertificateRequestS
 	return autoConvert_certmanager_CertificateRequestStatus_To_v1beta1_CertificateRequestStatus(in, out, s)
 }
 
+func autoConvert_v1beta1_CertificateSecretTemplate_To_certmanager_CertificateSecretTemplate(in *v1beta1.CertificateSecretTemplate, out *certmanager.CertificateSecretTemplate, s conversion.Scope) error {
+	out.CertificateKeys = *(*[]string)(unsafe.Pointer(&in.CertificateKeys))
+	out.PrivateKeyKeys = *(*[]string)(unsafe.Pointer(&in.PrivateKeyKeys))
+	out.Annotations = *(*map[string]string)(unsafe.Pointer(&in.Annotations))
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.OwnerReferences = *(*[]v1.OwnerReference)(unsafe.Pointer(&in.OwnerReferences))
+	return nil
+}
+
+// Convert_v1beta1_CertificateSecretTemplate_To_certmanager_CertificateSecretTemplate is an autogenerated conversion function.
+func Convert_v1beta1_CertificateSecretTemplate_To_certmanager_CertificateSecretTemplate(in *v1beta1.CertificateSecretTemplate, out *certmanager.CertificateSecretTemplate, s conversion.Scope) error {
+	return autoConvert_v1beta1_CertificateSecretTemplate_To_certmanager_CertificateSecretTemplate(in, out, s)
+}
+
+func autoConvert_certmanager_CertificateSecretTemplate_To_v1beta1_CertificateSecretTemplate(in *certmanager.CertificateSecretTemplate, out *v1beta1.CertificateSecretTemplate, s conversion.Scope) error {
+	out.CertificateKeys = *(*[]string)(unsafe.Pointer(&in.CertificateKeys))
+	out.PrivateKeyKeys = *(*[]string)(unsafe.Pointer(&in.PrivateKeyKeys))
+	out.Annotations = *(*map[string]string)(unsafe.Pointer(&in.Annotations))
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.OwnerReferences = *(*[]v1.OwnerReference)(unsafe.Pointer(&in.OwnerReferences))
+	return nil
+}
+
+// Convert_certmanager_CertificateSecretTemplate_To_v1beta1_CertificateSecretTemplate is an autogenerated conversion function.
+func Convert_certmanager_CertificateSecretTemplate_To_v1beta1_CertificateSecretTemplate(in *certmanager.CertificateSecretTemplate, out *v1beta1.CertificateSecretTemplate, s conversion.Scope) error {
+	return autoConvert_certmanager_CertificateSecretTemplate_To_v1beta1_CertificateSecretTemplate(in, out, s)
+}
+
 func autoConvert_v1beta1_CertificateSpec_To_certmanager_CertificateSpec(in *v1beta1.CertificateSpec, out *certmanager.CertificateSpec, s conversion.Scope) error {
 	out.Subject = (*certmanager.X509Subject)(unsafe.Pointer(in.Subject))
 	out.CommonName = in.CommonName
@@ -690,6 +1010,14 @@ func autoConvert_v1beta1_CertificateSpec_To_certmanager_CertificateSpec(in *v1be
 	out.PrivateKey = (*certmanager.CertificatePrivateKey)(unsafe.Pointer(in.PrivateKey))
 	out.EncodeUsagesInRequest = (*bool)(unsafe.Pointer(in.EncodeUsagesInRequest))
 	out.RevisionHistoryLimit = (*int32)(unsafe.Pointer(in.RevisionHistoryLimit))
+	out.SecretTemplate = (*certmanager.CertificateSecretTemplate)(unsafe.Pointer(in.SecretTemplate))
+	out.ServiceSelector = (*v1.LabelSelector)(unsafe.Pointer(in.ServiceSelector))
+	out.RenewalSchedule = (*certmanager.CertificateRenewalSchedule)(unsafe.Pointer(in.RenewalSchedule))
+	out.OCSPStapling = (*certmanager.CertificateOCSPStapling)(unsafe.Pointer(in.OCSPStapling))
+	out.AddCommonNameToDNSNames = (*bool)(unsafe.Pointer(in.AddCommonNameToDNSNames))
+	out.Replicas = (*int32)(unsafe.Pointer(in.Replicas))
+	out.IssuanceTimeout = (*v1.Duration)(unsafe.Pointer(in.IssuanceTimeout))
+	out.CertificatePolicies = *(*[]certmanager.CertificatePolicy)(unsafe.Pointer(&in.CertificatePolicies))
 	return nil
 }
 
@@ -718,6 +1046,14 @@ func autoConvert_certmanager_CertificateSpec_To_v1beta1_CertificateSpec(in *cert
 	out.PrivateKey = (*v1beta1.CertificatePrivateKey)(unsafe.Pointer(in.PrivateKey))
 	out.EncodeUsagesInRequest = (*bool)(unsafe.Pointer(in.EncodeUsagesInRequest))
 	out.RevisionHistoryLimit = (*int32)(unsafe.Pointer(in.RevisionHistoryLimit))
+	out.SecretTemplate = (*v1beta1.CertificateSecretTemplate)(unsafe.Pointer(in.SecretTemplate))
+	out.ServiceSelector = (*v1.LabelSelector)(unsafe.Pointer(in.ServiceSelector))
+	out.RenewalSchedule = (*v1beta1.CertificateRenewalSchedule)(unsafe.Pointer(in.RenewalSchedule))
+	out.OCSPStapling = (*v1beta1.CertificateOCSPStapling)(unsafe.Pointer(in.OCSPStapling))
+	out.AddCommonNameToDNSNames = (*bool)(unsafe.Pointer(in.AddCommonNameToDNSNames))
+	out.Replicas = (*int32)(unsafe.Pointer(in.Replicas))
+	out.IssuanceTimeout = (*v1.Duration)(unsafe.Pointer(in.IssuanceTimeout))
+	out.CertificatePolicies = *(*[]v1beta1.CertificatePolicy)(unsafe.Pointer(&in.CertificatePolicies))
 	return nil
 }
 
@@ -734,6 +1070,17 @@ func autoConvert_v1beta1_CertificateStatus_To_certmanager_CertificateStatus(in *
 	out.RenewalTime = (*v1.Time)(unsafe.Pointer(in.RenewalTime))
 	out.Revision = (*int)(unsafe.Pointer(in.Revision))
 	out.NextPrivateKeySecretName = (*string)(unsafe.Pointer(in.NextPrivateKeySecretName))
+	if in.RenewalHistory != nil {
+		in, out := &in.RenewalHistory, &out.RenewalHistory
+		*out = make([]certmanager.CertificateRenewalRecord, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta1_CertificateRenewalRecord_To_certmanager_CertificateRenewalRecord(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.RenewalHistory = nil
+	}
 	return nil
 }
 
@@ -750,6 +1097,17 @@ func autoConvert_certmanager_CertificateStatus_To_v1beta1_CertificateStatus(in *
 	out.RenewalTime = (*v1.Time)(unsafe.Pointer(in.RenewalTime))
 	out.Revision = (*int)(unsafe.Pointer(in.Revision))
 	out.NextPrivateKeySecretName = (*string)(unsafe.Pointer(in.NextPrivateKeySecretName))
+	if in.RenewalHistory != nil {
+		in, out := &in.RenewalHistory, &out.RenewalHistory
+		*out = make([]v1beta1.CertificateRenewalRecord, len(*in))
+		for i := range *in {
+			if err := Convert_certmanager_CertificateRenewalRecord_To_v1beta1_CertificateRenewalRecord(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.RenewalHistory = nil
+	}
 	return nil
 }
 
@@ -844,6 +1202,26 @@ func Convert_certmanager_Issuer_To_v1beta1_Issuer(in *certmanager.Issuer, out *v
 	return autoConvert_certmanager_Issuer_To_v1beta1_Issuer(in, out, s)
 }
 
+func autoConvert_v1beta1_IssuerChainValidation_To_certmanager_IssuerChainValidation(in *v1beta1.IssuerChainValidation, out *certmanager.IssuerChainValidation, s conversion.Scope) error {
+	out.TrustAnchors = *(*[]byte)(unsafe.Pointer(&in.TrustAnchors))
+	return nil
+}
+
+// Convert_v1beta1_IssuerChainValidation_To_certmanager_IssuerChainValidation is an autogenerated conversion function.
+func Convert_v1beta1_IssuerChainValidation_To_certmanager_IssuerChainValidation(in *v1beta1.IssuerChainValidation, out *certmanager.IssuerChainValidation, s conversion.Scope) error {
+	return autoConvert_v1beta1_IssuerChainValidation_To_certmanager_IssuerChainValidation(in, out, s)
+}
+
+func autoConvert_certmanager_IssuerChainValidation_To_v1beta1_IssuerChainValidation(in *certmanager.IssuerChainValidation, out *v1beta1.IssuerChainValidation, s conversion.Scope) error {
+	out.TrustAnchors = *(*[]byte)(unsafe.Pointer(&in.TrustAnchors))
+	return nil
+}
+
+// Convert_certmanager_IssuerChainValidation_To_v1beta1_IssuerChainValidation is an autogenerated conversion function.
+func Convert_certmanager_IssuerChainValidation_To_v1beta1_IssuerChainValidation(in *certmanager.IssuerChainValidation, out *v1beta1.IssuerChainValidation, s conversion.Scope) error {
+	return autoConvert_certmanager_IssuerChainValidation_To_v1beta1_IssuerChainValidation(in, out, s)
+}
+
 func autoConvert_v1beta1_IssuerCondition_To_certmanager_IssuerCondition(in *v1beta1.IssuerCondition, out *certmanager.IssuerCondition, s conversion.Scope) error {
 	out.Type = certmanager.IssuerConditionType(in.Type)
 	out.Status = meta.ConditionStatus(in.Status)
@@ -880,6 +1258,7 @@ func autoConvert_v1beta1_IssuerConfig_To_certmanager_IssuerConfig(in *v1beta1.Is
 	out.Vault = (*certmanager.VaultIssuer)(unsafe.Pointer(in.Vault))
 	out.SelfSigned = (*certmanager.SelfSignedIssuer)(unsafe.Pointer(in.SelfSigned))
 	out.Venafi = (*certmanager.VenafiIssuer)(unsafe.Pointer(in.Venafi))
+	out.ADCSWebEnrollment = (*certmanager.ADCSWebEnrollmentIssuer)(unsafe.Pointer(in.ADCSWebEnrollment))
 	return nil
 }
 
@@ -894,6 +1273,7 @@ func autoConvert_certmanager_IssuerConfig_To_v1beta1_IssuerConfig(in *certmanage
 	out.Vault = (*v1beta1.VaultIssuer)(unsafe.Pointer(in.Vault))
 	out.SelfSigned = (*v1beta1.SelfSignedIssuer)(unsafe.Pointer(in.SelfSigned))
 	out.Venafi = (*v1beta1.VenafiIssuer)(unsafe.Pointer(in.Venafi))
+	out.ADCSWebEnrollment = (*v1beta1.ADCSWebEnrollmentIssuer)(unsafe.Pointer(in.ADCSWebEnrollment))
 	return nil
 }
 
@@ -928,6 +1308,7 @@ func autoConvert_v1beta1_IssuerSpec_To_certmanager_IssuerSpec(in *v1beta1.Issuer
 	if err := Convert_v1beta1_IssuerConfig_To_certmanager_IssuerConfig(&in.IssuerConfig, &out.IssuerConfig, s); err != nil {
 		return err
 	}
+	out.ChainValidation = (*certmanager.IssuerChainValidation)(unsafe.Pointer(in.ChainValidation))
 	return nil
 }
 
@@ -940,6 +1321,7 @@ func autoConvert_certmanager_IssuerSpec_To_v1beta1_IssuerSpec(in *certmanager.Is
 	if err := Convert_certmanager_IssuerConfig_To_v1beta1_IssuerConfig(&in.IssuerConfig, &out.IssuerConfig, s); err != nil {
 		return err
 	}
+	out.ChainValidation = (*v1beta1.IssuerChainValidation)(unsafe.Pointer(in.ChainValidation))
 	return nil
 }
 
@@ -976,6 +1358,8 @@ func autoConvert_v1beta1_JKSKeystore_To_certmanager_JKSKeystore(in *v1beta1.JKSK
 	if err := s.Convert(&in.PasswordSecretRef, &out.PasswordSecretRef, 0); err != nil {
 		return err
 	}
+	out.Alias = in.Alias
+	out.IncludeChain = in.IncludeChain
 	return nil
 }
 
@@ -990,6 +1374,8 @@ func autoConvert_certmanager_JKSKeystore_To_v1beta1_JKSKeystore(in *certmanager.
 	if err := s.Convert(&in.PasswordSecretRef, &out.PasswordSecretRef, 0); err != nil {
 		return err
 	}
+	out.Alias = in.Alias
+	out.IncludeChain = in.IncludeChain
 	return nil
 }
 
@@ -1004,6 +1390,7 @@ func autoConvert_v1beta1_PKCS12Keystore_To_certmanager_PKCS12Keystore(in *v1beta
 	if err := s.Convert(&in.PasswordSecretRef, &out.PasswordSecretRef, 0); err != nil {
 		return err
 	}
+	out.Profile = certmanager.PKCS12Profile(in.Profile)
 	return nil
 }
 
@@ -1018,6 +1405,7 @@ func autoConvert_certmanager_PKCS12Keystore_To_v1beta1_PKCS12Keystore(in *certma
 	if err := s.Convert(&in.PasswordSecretRef, &out.PasswordSecretRef, 0); err != nil {
 		return err
 	}
+	out.Profile = v1beta1.PKCS12Profile(in.Profile)
 	return nil
 }
 
@@ -1028,6 +1416,11 @@ func Convert_certmanager_PKCS12Keystore_To_v1beta1_PKCS12Keystore(in *certmanage
 
 func autoConvert_v1beta1_SelfSignedIssuer_To_certmanager_SelfSignedIssuer(in *v1beta1.SelfSignedIssuer, out *certmanager.SelfSignedIssuer, s conversion.Scope) error {
 	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
+	out.SubjectKeyIdentifierMethod = certmanager.SubjectKeyIdentifierMethod(in.SubjectKeyIdentifierMethod)
+	out.KeySeedSecretRef = (*meta.SecretKeySelector)(unsafe.Pointer(in.KeySeedSecretRef))
+	out.CertificateProfile = certmanager.CertificateProfile(in.CertificateProfile)
+	out.CertificatePolicies = *(*[]certmanager.CertificatePolicy)(unsafe.Pointer(&in.CertificatePolicies))
+	out.AllowedCertificatePolicies = *(*[]certmanager.CertificatePolicy)(unsafe.Pointer(&in.AllowedCertificatePolicies))
 	return nil
 }
 
@@ -1038,6 +1431,11 @@ func Convert_v1beta1_SelfSignedIssuer_To_certmanager_SelfSignedIssuer(in *v1beta
 
 func autoConvert_certmanager_SelfSignedIssuer_To_v1beta1_SelfSignedIssuer(in *certmanager.SelfSignedIssuer, out *v1beta1.SelfSignedIssuer, s conversion.Scope) error {
 	out.CRLDistributionPoints = *(*[]string)(unsafe.Pointer(&in.CRLDistributionPoints))
+	out.SubjectKeyIdentifierMethod = v1beta1.SubjectKeyIdentifierMethod(in.SubjectKeyIdentifierMethod)
+	out.KeySeedSecretRef = (*metav1.SecretKeySelector)(unsafe.Pointer(in.KeySeedSecretRef))
+	out.CertificateProfile = v1beta1.CertificateProfile(in.CertificateProfile)
+	out.CertificatePolicies = *(*[]v1beta1.CertificatePolicy)(unsafe.Pointer(&in.CertificatePolicies))
+	out.AllowedCertificatePolicies = *(*[]v1beta1.CertificatePolicy)(unsafe.Pointer(&in.AllowedCertificatePolicies))
 	return nil
 }
 
@@ -1108,6 +1506,7 @@ func autoConvert_v1beta1_VaultIssuer_To_certmanager_VaultIssuer(in *v1beta1.Vaul
 	out.Path = in.Path
 	out.Namespace = in.Namespace
 	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	out.ExpectedRootCA = *(*[]byte)(unsafe.Pointer(&in.ExpectedRootCA))
 	return nil
 }
 
@@ -1124,6 +1523,7 @@ func autoConvert_certmanager_VaultIssuer_To_v1beta1_VaultIssuer(in *certmanager.
 	out.Path = in.Path
 	out.Namespace = in.Namespace
 	out.CABundle = *(*[]byte)(unsafe.Pointer(&in.CABundle))
+	out.ExpectedRootCA = *(*[]byte)(unsafe.Pointer(&in.ExpectedRootCA))
 	return nil
 }
 
@@ -1139,6 +1539,7 @@ func autoConvert_v1beta1_VaultKubernetesAuth_To_certmanager_VaultKubernetesAuth(
 		return err
 	}
 	out.Role = in.Role
+	out.TokenPath = in.TokenPath
 	return nil
 }
 
@@ -1154,6 +1555,7 @@ func autoConvert_certmanager_VaultKubernetesAuth_To_v1beta1_VaultKubernetesAuth(
 		return err
 	}
 	out.Role = in.Role
+	out.TokenPath = in.TokenPath
 	return nil
 }
 
@@ -1190,10 +1592,33 @@ func Convert_certmanager_VenafiCloud_To_v1beta1_VenafiCloud(in *certmanager.Vena
 	return autoConvert_certmanager_VenafiCloud_To_v1beta1_VenafiCloud(in, out, s)
 }
 
+func autoConvert_v1beta1_VenafiCustomFieldAnnotation_To_certmanager_VenafiCustomFieldAnnotation(in *v1beta1.VenafiCustomFieldAnnotation, out *certmanager.VenafiCustomFieldAnnotation, s conversion.Scope) error {
+	out.Annotation = in.Annotation
+	out.CustomField = in.CustomField
+	return nil
+}
+
+// Convert_v1beta1_VenafiCustomFieldAnnotation_To_certmanager_VenafiCustomFieldAnnotation is an autogenerated conversion function.
+func Convert_v1beta1_VenafiCustomFieldAnnotation_To_certmanager_VenafiCustomFieldAnnotation(in *v1beta1.VenafiCustomFieldAnnotation, out *certmanager.VenafiCustomFieldAnnotation, s conversion.Scope) error {
+	return autoConvert_v1beta1_VenafiCustomFieldAnnotation_To_certmanager_VenafiCustomFieldAnnotation(in, out, s)
+}
+
+func autoConvert_certmanager_VenafiCustomFieldAnnotation_To_v1beta1_VenafiCustomFieldAnnotation(in *certmanager.VenafiCustomFieldAnnotation, out *v1beta1.VenafiCustomFieldAnnotation, s conversion.Scope) error {
+	out.Annotation = in.Annotation
+	out.CustomField = in.CustomField
+	return nil
+}
+
+// Convert_certmanager_VenafiCustomFieldAnnotation_To_v1beta1_VenafiCustomFieldAnnotation is an autogenerated conversion function.
+func Convert_certmanager_VenafiCustomFieldAnnotation_To_v1beta1_VenafiCustomFieldAnnotation(in *certmanager.VenafiCustomFieldAnnotation, out *v1beta1.VenafiCustomFieldAnnotation, s conversion.Scope) error {
+	return autoConvert_certmanager_VenafiCustomFieldAnnotation_To_v1beta1_VenafiCustomFieldAnnotation(in, out, s)
+}
+
 func autoConvert_v1beta1_VenafiIssuer_To_certmanager_VenafiIssuer(in *v1beta1.VenafiIssuer, out *certmanager.VenafiIssuer, s conversion.Scope) error {
 	out.Zone = in.Zone
 	out.TPP = (*certmanager.VenafiTPP)(unsafe.Pointer(in.TPP))
 	out.Cloud = (*certmanager.VenafiCloud)(unsafe.Pointer(in.Cloud))
+	out.CustomFieldAnnotations = *(*[]certmanager.VenafiCustomFieldAnnotation)(unsafe.Pointer(&in.CustomFieldAnnotations))
 	return nil
 }
 
@@ -1206,6 +1631,7 @@ func autoConvert_certmanager_VenafiIssuer_To_v1beta1_VenafiIssuer(in *certmanage
 	out.Zone = in.Zone
 	out.TPP = (*v1beta1.VenafiTPP)(unsafe.Pointer(in.TPP))
 	out.Cloud = (*v1beta1.VenafiCloud)(unsafe.Pointer(in.Cloud))
+	out.CustomFieldAnnotations = *(*[]v1beta1.VenafiCustomFieldAnnotation)(unsafe.Pointer(&in.CustomFieldAnnotations))
 	return nil
 }
 
@@ -20,9 +20,17 @@ import (
 	"fmt"
 	"net"
 	"net/mail"
+	"regexp"
+	"strings"
+	"time"
 
 	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/jetstack/cert-manager/pkg/api/util"
@@ -30,6 +38,7 @@ import (
 	"github.com/jetstack/cert-manager/pkg/internal/api/validation"
 	internalcmapi "github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
 	cmmeta "github.com/jetstack/cert-manager/pkg/internal/apis/meta"
+	"github.com/jetstack/cert-manager/pkg/util/maintenance"
 )
 
 // Validation functions for cert-manager Certificate types
@@ -42,8 +51,8 @@ func ValidateCertificateSpec(crt *internalcmapi.CertificateSpec, fldPath *field.
 
 	el = append(el, validateIssuerRef(crt.IssuerRef, fldPath)...)
 
-	if len(crt.CommonName) == 0 && len(crt.DNSNames) == 0 && len(crt.URISANs) == 0 && len(crt.EmailSANs) == 0 && len(crt.IPAddresses) == 0 {
-		el = append(el, field.Invalid(fldPath, "", "at least one of commonName, dnsNames, uris ipAddresses, or emailAddresses must be set"))
+	if len(crt.CommonName) == 0 && len(crt.DNSNames) == 0 && len(crt.URISANs) == 0 && len(crt.EmailSANs) == 0 && len(crt.IPAddresses) == 0 && crt.ServiceSelector == nil {
+		el = append(el, field.Invalid(fldPath, "", "at least one of commonName, dnsNames, uris ipAddresses, emailAddresses or serviceSelector must be set"))
 	}
 
 	// if a common name has been specified, ensure it is no longer than 64 chars
@@ -72,21 +81,94 @@ func ValidateCertificateSpec(crt *internalcmapi.CertificateSpec, fldPath *field.
 		default:
 			el = append(el, field.Invalid(fldPath.Child("privateKey", "algorithm"), crt.PrivateKey.Algorithm, "must be either empty or one of rsa or ecdsa"))
 		}
+
+		switch crt.PrivateKey.Encoding {
+		case "", internalcmapi.PKCS8:
+		case internalcmapi.PKCS1:
+			if crt.PrivateKey.Algorithm == internalcmapi.ECDSAKeyAlgorithm {
+				el = append(el, field.Invalid(fldPath.Child("privateKey", "encoding"), crt.PrivateKey.Encoding, "PKCS1 encoding is only supported for rsa keyAlgorithm"))
+			}
+		default:
+			el = append(el, field.NotSupported(fldPath.Child("privateKey", "encoding"), crt.PrivateKey.Encoding, []string{string(internalcmapi.PKCS1), string(internalcmapi.PKCS8)}))
+		}
 	}
 
 	if crt.Duration != nil || crt.RenewBefore != nil {
 		el = append(el, ValidateDuration(crt, fldPath)...)
 	}
+	if crt.IssuanceTimeout != nil && crt.IssuanceTimeout.Duration < 0 {
+		el = append(el, field.Invalid(fldPath.Child("issuanceTimeout"), crt.IssuanceTimeout.Duration, "must not be negative"))
+	}
 	if len(crt.Usages) > 0 {
 		el = append(el, validateUsages(crt, fldPath)...)
 	}
 	if crt.RevisionHistoryLimit != nil && *crt.RevisionHistoryLimit < 1 {
 		el = append(el, field.Invalid(fldPath.Child("revisionHistoryLimit"), *crt.RevisionHistoryLimit, "must not be less than 1"))
 	}
+	if crt.SecretTemplate != nil {
+		el = append(el, validateSecretTemplate(crt.SecretTemplate, fldPath.Child("secretTemplate"))...)
+	}
+	if crt.ServiceSelector != nil {
+		el = append(el, metav1validation.ValidateLabelSelector(crt.ServiceSelector, fldPath.Child("serviceSelector"))...)
+	}
+	if crt.RenewalSchedule != nil {
+		el = append(el, validateRenewalSchedule(crt.RenewalSchedule, fldPath.Child("renewalSchedule"))...)
+	}
+	if crt.Keystores != nil && crt.Keystores.JKS != nil {
+		el = append(el, validateJKSKeystore(crt.Keystores.JKS, fldPath.Child("keystores", "jks"))...)
+	}
+	if crt.Replicas != nil && *crt.Replicas < 1 {
+		el = append(el, field.Invalid(fldPath.Child("replicas"), *crt.Replicas, "must not be less than 1"))
+	}
+	if crt.Replicas == nil {
+		el = append(el, validateNoOrdinalPlaceholder(crt, fldPath)...)
+	}
+	if len(crt.CertificatePolicies) > 0 {
+		el = append(el, validateCertificatePolicies(crt.CertificatePolicies, fldPath.Child("certificatePolicies"))...)
+	}
 
 	return el
 }
 
+// jksCAAliasRegexp matches the aliases of the CA certificate entries in JKS
+// keystores. Java treats aliases case-insensitively.
+var jksCAAliasRegexp = regexp.MustCompile(`(?i)^ca(-[0-9]+)?$`)
+
+// validateJKSKeystore ensures that the private key entry of the JKS keystore
+// does not share an alias with the CA certificate entries.
+func validateJKSKeystore(jks *internalcmapi.JKSKeystore, fldPath *field.Path) field.ErrorList {
+	var el field.ErrorList
+	if jksCAAliasRegexp.MatchString(jks.Alias) {
+		el = append(el, field.Invalid(fldPath.Child("alias"), jks.Alias, "must not be the alias of a CA certificate entry, i.e. ca or ca-<number>"))
+	}
+	return el
+}
+
+// validateNoOrdinalPlaceholder ensures that the replica ordinal placeholder is
+// only used by Certificates that have replicas.
+func validateNoOrdinalPlaceholder(crt *internalcmapi.CertificateSpec, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	msg := fmt.Sprintf("%s may only be used when replicas is set", cmapi.CertificateReplicaOrdinalPlaceholder)
+	if strings.Contains(crt.CommonName, cmapi.CertificateReplicaOrdinalPlaceholder) {
+		el = append(el, field.Invalid(fldPath.Child("commonName"), crt.CommonName, msg))
+	}
+	for _, sans := range []struct {
+		name   string
+		values []string
+	}{
+		{"dnsNames", crt.DNSNames},
+		{"uris", crt.URISANs},
+		{"emailAddresses", crt.EmailSANs},
+	} {
+		for i, v := range sans.values {
+			if strings.Contains(v, cmapi.CertificateReplicaOrdinalPlaceholder) {
+				el = append(el, field.Invalid(fldPath.Child(sans.name).Index(i), v, msg))
+			}
+		}
+	}
+	return el
+}
+
 func ValidateCertificate(_ *admissionv1.AdmissionRequest, obj runtime.Object) (field.ErrorList, validation.WarningList) {
 	crt := obj.(*internalcmapi.Certificate)
 	allErrs := ValidateCertificateSpec(&crt.Spec, field.NewPath("spec"))
@@ -137,10 +219,16 @@ func validateEmailAddresses(a *internalcmapi.CertificateSpec, fldPath *field.Pat
 	}
 	el := field.ErrorList{}
 	for i, d := range a.EmailSANs {
-		e, err := mail.ParseAddress(d)
+		addr := d
+		if a.Replicas != nil {
+			// the placeholder is not valid in an email address, so validate
+			// the address requested by the first replica instead
+			addr = strings.ReplaceAll(addr, cmapi.CertificateReplicaOrdinalPlaceholder, "0")
+		}
+		e, err := mail.ParseAddress(addr)
 		if err != nil {
 			el = append(el, field.Invalid(fldPath.Child("emailAddresses").Index(i), d, fmt.Sprintf("invalid email address: %s", err)))
-		} else if e.Address != d {
+		} else if e.Address != addr {
 			// Go accepts email names as per RFC 5322 (name <email>)
 			// This checks if the supplied value only contains the email address and nothing else
 			el = append(el, field.Invalid(fldPath.Child("emailAddresses").Index(i), d, "invalid email address: make sure the supplied value only contains the email address itself"))
@@ -180,3 +268,121 @@ func ValidateDuration(crt *internalcmapi.CertificateSpec, fldPath *field.Path) f
 	}
 	return el
 }
+
+// reservedSecretKeys are the keys in a Certificate's Secret that are managed
+// by cert-manager, and so cannot be used as additional keys.
+var reservedSecretKeys = map[string]bool{
+	corev1.TLSCertKey:       true,
+	corev1.TLSPrivateKeyKey: true,
+	cmmeta.TLSCAKey:         true,
+	"keystore.p12":          true,
+	"truststore.p12":        true,
+	"keystore.jks":          true,
+	"truststore.jks":        true,
+}
+
+// reservedSecretAnnotations are the annotations on a Certificate's Secret
+// that are managed by cert-manager, and so cannot be set by the secretTemplate.
+var reservedSecretAnnotations = map[string]bool{
+	internalcmapi.CertificateNameKey:       true,
+	internalcmapi.IssuerNameAnnotationKey:  true,
+	internalcmapi.IssuerKindAnnotationKey:  true,
+	internalcmapi.IssuerGroupAnnotationKey: true,
+	internalcmapi.CommonNameAnnotationKey:  true,
+	internalcmapi.AltNamesAnnotationKey:    true,
+	internalcmapi.IPSANAnnotationKey:       true,
+	internalcmapi.URISANAnnotationKey:      true,
+}
+
+func validateRenewalSchedule(schedule *internalcmapi.CertificateRenewalSchedule, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+
+	for i, day := range schedule.Days {
+		if _, err := maintenance.ParseWeekday(day); err != nil {
+			el = append(el, field.Invalid(fldPath.Child("days").Index(i), day, "must be a day of the week, e.g. Monday"))
+		}
+	}
+	if schedule.StartHour < 0 || schedule.StartHour > 23 {
+		el = append(el, field.Invalid(fldPath.Child("startHour"), schedule.StartHour, "must be between 0 and 23"))
+	}
+	if schedule.EndHour < 1 || schedule.EndHour > 24 {
+		el = append(el, field.Invalid(fldPath.Child("endHour"), schedule.EndHour, "must be between 1 and 24"))
+	} else if schedule.EndHour <= schedule.StartHour {
+		el = append(el, field.Invalid(fldPath.Child("endHour"), schedule.EndHour, "must be after startHour"))
+	}
+	if schedule.TimeZone != "" {
+		if _, err := time.LoadLocation(schedule.TimeZone); err != nil {
+			el = append(el, field.Invalid(fldPath.Child("timeZone"), schedule.TimeZone, "must be a valid IANA time zone"))
+		}
+	}
+
+	return el
+}
+
+func validateSecretTemplate(tmpl *internalcmapi.CertificateSecretTemplate, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	seen := make(map[string]bool)
+
+	validateKeys := func(keys []string, fldPath *field.Path) {
+		for i, key := range keys {
+			for _, msg := range k8svalidation.IsConfigMapKey(key) {
+				el = append(el, field.Invalid(fldPath.Index(i), key, msg))
+			}
+			if reservedSecretKeys[key] {
+				el = append(el, field.Invalid(fldPath.Index(i), key, "must not be a key that is managed by cert-manager"))
+			}
+			if seen[key] {
+				el = append(el, field.Duplicate(fldPath.Index(i), key))
+			}
+			seen[key] = true
+		}
+	}
+
+	validateKeys(tmpl.CertificateKeys, fldPath.Child("certificateKeys"))
+	validateKeys(tmpl.PrivateKeyKeys, fldPath.Child("privateKeyKeys"))
+
+	for k, v := range tmpl.Labels {
+		for _, msg := range k8svalidation.IsQualifiedName(k) {
+			el = append(el, field.Invalid(fldPath.Child("labels"), k, msg))
+		}
+		for _, msg := range k8svalidation.IsValidLabelValue(v) {
+			el = append(el, field.Invalid(fldPath.Child("labels").Key(k), v, msg))
+		}
+	}
+
+	for k := range tmpl.Annotations {
+		for _, msg := range k8svalidation.IsQualifiedName(strings.ToLower(k)) {
+			el = append(el, field.Invalid(fldPath.Child("annotations"), k, msg))
+		}
+		if reservedSecretAnnotations[k] {
+			el = append(el, field.Invalid(fldPath.Child("annotations"), k, "must not be an annotation that is managed by cert-manager"))
+		}
+	}
+
+	seenOwners := make(map[types.UID]bool)
+	for i, ref := range tmpl.OwnerReferences {
+		refPath := fldPath.Child("ownerReferences").Index(i)
+		if ref.APIVersion == "" {
+			el = append(el, field.Required(refPath.Child("apiVersion"), ""))
+		} else if _, err := schema.ParseGroupVersion(ref.APIVersion); err != nil {
+			el = append(el, field.Invalid(refPath.Child("apiVersion"), ref.APIVersion, err.Error()))
+		}
+		if ref.Kind == "" {
+			el = append(el, field.Required(refPath.Child("kind"), ""))
+		}
+		if ref.Name == "" {
+			el = append(el, field.Required(refPath.Child("name"), ""))
+		}
+		if ref.UID == "" {
+			el = append(el, field.Required(refPath.Child("uid"), ""))
+		} else if seenOwners[ref.UID] {
+			el = append(el, field.Duplicate(refPath.Child("uid"), ref.UID))
+		}
+		seenOwners[ref.UID] = true
+		if ref.Controller != nil && *ref.Controller {
+			el = append(el, field.Invalid(refPath.Child("controller"), true, "must not be set, as only cert-manager may control the Secret"))
+		}
+	}
+
+	return el
+}
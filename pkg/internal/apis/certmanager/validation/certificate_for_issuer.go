@@ -37,6 +37,7 @@ func ValidateCertificateForIssuer(crt *cmapi.Certificate, issuerObj cmapi.Generi
 		el = append(el, ValidateCertificateForVaultIssuer(&crt.Spec, issuerObj.GetSpec(), path)...)
 	case issuerObj.GetSpec().SelfSigned != nil:
 	case issuerObj.GetSpec().Venafi != nil:
+	case issuerObj.GetSpec().ADCSWebEnrollment != nil:
 	default:
 		el = append(el, field.Invalid(path, "", fmt.Sprintf("no issuer specified for Issuer '%s/%s'", issuerObj.GetObjectMeta().Namespace, issuerObj.GetObjectMeta().Name)))
 	}
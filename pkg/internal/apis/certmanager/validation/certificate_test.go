@@ -23,6 +23,8 @@ import (
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
@@ -46,8 +48,13 @@ func int32Ptr(i int32) *int32 {
 	return &i
 }
 
+func intPtr(i int) *int {
+	return &i
+}
+
 func TestValidateCertificate(t *testing.T) {
 	fldPath := field.NewPath("spec")
+	isController := true
 	scenarios := map[string]struct {
 		cfg      *internalcmapi.Certificate
 		errs     []*field.Error
@@ -131,7 +138,34 @@ func TestValidateCertificate(t *testing.T) {
 				},
 			},
 			errs: []*field.Error{
-				field.Invalid(fldPath, "", "at least one of commonName, dnsNames, uris ipAddresses, or emailAddresses must be set"),
+				field.Invalid(fldPath, "", "at least one of commonName, dnsNames, uris ipAddresses, emailAddresses or serviceSelector must be set"),
+			},
+		},
+		"valid certificate with only serviceSelector": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					ServiceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"app": "mesh"},
+					},
+				},
+			},
+		},
+		"certificate with invalid serviceSelector": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					ServiceSelector: &metav1.LabelSelector{
+						MatchExpressions: []metav1.LabelSelectorRequirement{
+							{Key: "app", Operator: "Invalid"},
+						},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("serviceSelector", "matchExpressions").Index(0).Child("operator"), metav1.LabelSelectorOperator("Invalid"), "not a valid selector operator"),
 			},
 		},
 		"certificate with no issuerRef": {
@@ -331,6 +365,63 @@ func TestValidateCertificate(t *testing.T) {
 				field.Invalid(fldPath.Child("privateKey", "algorithm"), internalcmapi.PrivateKeyAlgorithm("blah"), "must be either empty or one of rsa or ecdsa"),
 			},
 		},
+		"certificate with rsa keyAlgorithm and PKCS1 encoding": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					PrivateKey: &internalcmapi.CertificatePrivateKey{
+						Algorithm: internalcmapi.RSAKeyAlgorithm,
+						Encoding:  internalcmapi.PKCS1,
+					},
+				},
+			},
+		},
+		"certificate with ecdsa keyAlgorithm and PKCS8 encoding": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					PrivateKey: &internalcmapi.CertificatePrivateKey{
+						Algorithm: internalcmapi.ECDSAKeyAlgorithm,
+						Encoding:  internalcmapi.PKCS8,
+					},
+				},
+			},
+		},
+		"certificate with ecdsa keyAlgorithm and PKCS1 encoding": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					PrivateKey: &internalcmapi.CertificatePrivateKey{
+						Algorithm: internalcmapi.ECDSAKeyAlgorithm,
+						Encoding:  internalcmapi.PKCS1,
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("privateKey", "encoding"), internalcmapi.PKCS1, "PKCS1 encoding is only supported for rsa keyAlgorithm"),
+			},
+		},
+		"certificate with invalid encoding": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "testcn",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					PrivateKey: &internalcmapi.CertificatePrivateKey{
+						Encoding: internalcmapi.PrivateKeyEncoding("PKCS12"),
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.NotSupported(fldPath.Child("privateKey", "encoding"), internalcmapi.PrivateKeyEncoding("PKCS12"), []string{"PKCS1", "PKCS8"}),
+			},
+		},
 		"valid certificate with ipAddresses": {
 			cfg: &internalcmapi.Certificate{
 				Spec: internalcmapi.CertificateSpec{
@@ -513,6 +604,247 @@ func TestValidateCertificate(t *testing.T) {
 				field.Invalid(fldPath.Child("revisionHistoryLimit"), int32(0), "must not be less than 1"),
 			},
 		},
+		"valid certificate with issuance timeout of 0": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName:      "abc",
+					SecretName:      "abc",
+					IssuerRef:       validIssuerRef,
+					IssuanceTimeout: &metav1.Duration{Duration: 0},
+				},
+			},
+		},
+		"invalid certificate with negative issuance timeout": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName:      "abc",
+					SecretName:      "abc",
+					IssuerRef:       validIssuerRef,
+					IssuanceTimeout: &metav1.Duration{Duration: -time.Minute},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("issuanceTimeout"), -time.Minute, "must not be negative"),
+			},
+		},
+		"valid certificate with replicas": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "pod-$(ORDINAL)",
+					DNSNames:   []string{"pod-$(ORDINAL).svc"},
+					EmailSANs:  []string{"pod-$(ORDINAL)@example.com"},
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					Replicas:   int32Ptr(3),
+				},
+			},
+		},
+		"invalid certificate with replicas < 1": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "abc",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					Replicas:   int32Ptr(0),
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("replicas"), int32(0), "must not be less than 1"),
+			},
+		},
+		"invalid certificate using the ordinal placeholder without replicas": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "pod-$(ORDINAL)",
+					DNSNames:   []string{"example.com", "pod-$(ORDINAL).svc"},
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("commonName"), "pod-$(ORDINAL)", "$(ORDINAL) may only be used when replicas is set"),
+				field.Invalid(fldPath.Child("dnsNames").Index(1), "pod-$(ORDINAL).svc", "$(ORDINAL) may only be used when replicas is set"),
+			},
+		},
+		"valid certificate with a JKS keystore alias": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "abc",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					Keystores: &internalcmapi.CertificateKeystores{
+						JKS: &internalcmapi.JKSKeystore{Alias: "ca.example.com", IncludeChain: true},
+					},
+				},
+			},
+		},
+		"invalid certificate with a JKS keystore alias of a CA certificate entry": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "abc",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					Keystores: &internalcmapi.CertificateKeystores{
+						JKS: &internalcmapi.JKSKeystore{Alias: "CA-1"},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("keystores", "jks", "alias"), "CA-1", "must not be the alias of a CA certificate entry, i.e. ca or ca-<number>"),
+			},
+		},
+		"valid certificate with additional secret keys": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "abc",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					SecretTemplate: &internalcmapi.CertificateSecretTemplate{
+						CertificateKeys: []string{"server.crt", "cert.pem"},
+						PrivateKeyKeys:  []string{"server.key"},
+					},
+				},
+			},
+		},
+		"invalid certificate with invalid, reserved and duplicate additional secret keys": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "abc",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					SecretTemplate: &internalcmapi.CertificateSecretTemplate{
+						CertificateKeys: []string{"server/crt", "tls.crt", "server.pem"},
+						PrivateKeyKeys:  []string{"server.pem", "keystore.jks"},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("secretTemplate", "certificateKeys").Index(0), "server/crt", k8svalidation.IsConfigMapKey("server/crt")[0]),
+				field.Invalid(fldPath.Child("secretTemplate", "certificateKeys").Index(1), "tls.crt", "must not be a key that is managed by cert-manager"),
+				field.Duplicate(fldPath.Child("secretTemplate", "privateKeyKeys").Index(0), "server.pem"),
+				field.Invalid(fldPath.Child("secretTemplate", "privateKeyKeys").Index(1), "keystore.jks", "must not be a key that is managed by cert-manager"),
+			},
+		},
+		"valid certificate with secret labels and annotations": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "abc",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					SecretTemplate: &internalcmapi.CertificateSecretTemplate{
+						Labels:      map[string]string{"app.kubernetes.io/name": "abc"},
+						Annotations: map[string]string{"example.com/owner": "team a"},
+					},
+				},
+			},
+		},
+		"invalid certificate with invalid secret label and reserved secret annotation": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "abc",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					SecretTemplate: &internalcmapi.CertificateSecretTemplate{
+						Labels:      map[string]string{"app": "not a valid value"},
+						Annotations: map[string]string{internalcmapi.IssuerNameAnnotationKey: "abc"},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("secretTemplate", "labels").Key("app"), "not a valid value", k8svalidation.IsValidLabelValue("not a valid value")[0]),
+				field.Invalid(fldPath.Child("secretTemplate", "annotations"), internalcmapi.IssuerNameAnnotationKey, "must not be an annotation that is managed by cert-manager"),
+			},
+		},
+		"valid certificate with secret owner references": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "abc",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					SecretTemplate: &internalcmapi.CertificateSecretTemplate{
+						OwnerReferences: []metav1.OwnerReference{
+							{APIVersion: "example.com/v1", Kind: "App", Name: "abc", UID: "1234"},
+						},
+					},
+				},
+			},
+		},
+		"invalid certificate with incomplete, duplicate and controller secret owner references": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "abc",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					SecretTemplate: &internalcmapi.CertificateSecretTemplate{
+						OwnerReferences: []metav1.OwnerReference{
+							{APIVersion: "example.com/v1", Kind: "App", Name: "abc", UID: "1234"},
+							{Name: "def"},
+							{APIVersion: "example.com/v1", Kind: "App", Name: "abc", UID: "1234"},
+							{APIVersion: "example.com/v1", Kind: "App", Name: "ghi", UID: "5678", Controller: &isController},
+						},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("secretTemplate", "ownerReferences").Index(1).Child("apiVersion"), ""),
+				field.Required(fldPath.Child("secretTemplate", "ownerReferences").Index(1).Child("kind"), ""),
+				field.Required(fldPath.Child("secretTemplate", "ownerReferences").Index(1).Child("uid"), ""),
+				field.Duplicate(fldPath.Child("secretTemplate", "ownerReferences").Index(2).Child("uid"), types.UID("1234")),
+				field.Invalid(fldPath.Child("secretTemplate", "ownerReferences").Index(3).Child("controller"), true, "must not be set, as only cert-manager may control the Secret"),
+			},
+		},
+		"valid certificate with renewal schedule": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "abc",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					RenewalSchedule: &internalcmapi.CertificateRenewalSchedule{
+						Days:      []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday"},
+						StartHour: 9,
+						EndHour:   17,
+						TimeZone:  "Europe/London",
+					},
+				},
+			},
+		},
+		"invalid certificate with invalid renewal schedule": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "abc",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					RenewalSchedule: &internalcmapi.CertificateRenewalSchedule{
+						Days:      []string{"Monday", "Funday"},
+						StartHour: 17,
+						EndHour:   9,
+						TimeZone:  "Europe/Nowhere",
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("renewalSchedule", "days").Index(1), "Funday", "must be a day of the week, e.g. Monday"),
+				field.Invalid(fldPath.Child("renewalSchedule", "endHour"), 9, "must be after startHour"),
+				field.Invalid(fldPath.Child("renewalSchedule", "timeZone"), "Europe/Nowhere", "must be a valid IANA time zone"),
+			},
+		},
+		"invalid certificate with renewal schedule hours out of range": {
+			cfg: &internalcmapi.Certificate{
+				Spec: internalcmapi.CertificateSpec{
+					CommonName: "abc",
+					SecretName: "abc",
+					IssuerRef:  validIssuerRef,
+					RenewalSchedule: &internalcmapi.CertificateRenewalSchedule{
+						StartHour: 24,
+						EndHour:   25,
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("renewalSchedule", "startHour"), 24, "must be between 0 and 23"),
+				field.Invalid(fldPath.Child("renewalSchedule", "endHour"), 25, "must be between 1 and 24"),
+			},
+		},
 	}
 	for n, s := range scenarios {
 		t.Run(n, func(t *testing.T) {
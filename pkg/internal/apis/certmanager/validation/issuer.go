@@ -19,11 +19,14 @@ package validation
 import (
 	"crypto/x509"
 	"fmt"
+	"net/url"
+	"path"
 	"strings"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/jetstack/cert-manager/pkg/internal/api/validation"
@@ -31,6 +34,7 @@ import (
 	"github.com/jetstack/cert-manager/pkg/internal/apis/certmanager"
 	"github.com/jetstack/cert-manager/pkg/internal/apis/certmanager/validation/util"
 	cmmeta "github.com/jetstack/cert-manager/pkg/internal/apis/meta"
+	"github.com/jetstack/cert-manager/pkg/util/pki"
 )
 
 // Validation functions for cert-manager Issuer types.
@@ -48,7 +52,21 @@ func ValidateUpdateIssuer(_ *admissionv1.AdmissionRequest, oldObj, obj runtime.O
 }
 
 func ValidateIssuerSpec(iss *certmanager.IssuerSpec, fldPath *field.Path) (field.ErrorList, validation.WarningList) {
-	return ValidateIssuerConfig(&iss.IssuerConfig, fldPath)
+	el, warnings := ValidateIssuerConfig(&iss.IssuerConfig, fldPath)
+	if iss.ChainValidation != nil {
+		el = append(el, ValidateIssuerChainValidation(iss.ChainValidation, fldPath.Child("chainValidation"))...)
+	}
+	return el, warnings
+}
+
+func ValidateIssuerChainValidation(cv *certmanager.IssuerChainValidation, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	if len(cv.TrustAnchors) == 0 {
+		el = append(el, field.Required(fldPath.Child("trustAnchors"), ""))
+	} else if !x509.NewCertPool().AppendCertsFromPEM(cv.TrustAnchors) {
+		el = append(el, field.Invalid(fldPath.Child("trustAnchors"), "", "must contain at least one PEM encoded CA certificate"))
+	}
+	return el
 }
 
 func ValidateIssuerConfig(iss *certmanager.IssuerConfig, fldPath *field.Path) (field.ErrorList, validation.WarningList) {
@@ -77,7 +95,8 @@ func ValidateIssuerConfig(iss *certmanager.IssuerConfig, fldPath *field.Path) (f
 			el = append(el, field.Forbidden(fldPath.Child("selfSigned"), "may not specify more than one issuer type"))
 		} else {
 			numConfigs++
-			el = append(el, ValidateSelfSignedIssuerConfig(iss.SelfSigned, fldPath.Child("selfSigned"))...)
+			e, w := ValidateSelfSignedIssuerConfig(iss.SelfSigned, fldPath.Child("selfSigned"))
+			el, warnings = append(el, e...), append(warnings, w...)
 		}
 	}
 	if iss.Vault != nil {
@@ -96,6 +115,14 @@ func ValidateIssuerConfig(iss *certmanager.IssuerConfig, fldPath *field.Path) (f
 			el = append(el, ValidateVenafiIssuerConfig(iss.Venafi, fldPath.Child("venafi"))...)
 		}
 	}
+	if iss.ADCSWebEnrollment != nil {
+		if numConfigs > 0 {
+			el = append(el, field.Forbidden(fldPath.Child("adcsWebEnrollment"), "may not specify more than one issuer type"))
+		} else {
+			numConfigs++
+			el = append(el, ValidateADCSWebEnrollmentIssuerConfig(iss.ADCSWebEnrollment, fldPath.Child("adcsWebEnrollment"))...)
+		}
+	}
 	if numConfigs == 0 {
 		el = append(el, field.Required(fldPath, "at least one issuer must be configured"))
 	}
@@ -126,6 +153,36 @@ func ValidateACMEIssuerConfig(iss *cmacme.ACMEIssuer, fldPath *field.Path) (fiel
 		}
 	}
 
+	if len(iss.JWSAlgorithm) > 0 {
+		valid := false
+		for _, alg := range supportedJWSAlgorithms {
+			if string(iss.JWSAlgorithm) == alg {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			el = append(el, field.NotSupported(fldPath.Child("jwsAlgorithm"), iss.JWSAlgorithm, supportedJWSAlgorithms))
+		}
+	}
+
+	if len(iss.CAContent) > 0 {
+		valid := false
+		for _, content := range supportedACMECAContents {
+			if string(iss.CAContent) == content {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			el = append(el, field.NotSupported(fldPath.Child("caContent"), iss.CAContent, supportedACMECAContents))
+		}
+	}
+
+	if iss.NoncePoolSize < 0 || iss.NoncePoolSize > maxACMENoncePoolSize {
+		el = append(el, field.Invalid(fldPath.Child("noncePoolSize"), iss.NoncePoolSize, fmt.Sprintf("must be between 0 and %d", maxACMENoncePoolSize)))
+	}
+
 	for i, sol := range iss.Solvers {
 		el = append(el, ValidateACMEIssuerChallengeSolverConfig(&sol, fldPath.Child("solvers").Index(i))...)
 	}
@@ -133,6 +190,23 @@ func ValidateACMEIssuerConfig(iss *cmacme.ACMEIssuer, fldPath *field.Path) (fiel
 	return el, warnings
 }
 
+var supportedJWSAlgorithms = []string{
+	string(cmacme.RS256),
+	string(cmacme.ES256),
+	string(cmacme.ES384),
+	string(cmacme.ES512),
+}
+
+// maxACMENoncePoolSize is the maximum number of nonces that may be prefetched
+// for an ACME issuer. The ACME client does not store more nonces than this.
+const maxACMENoncePoolSize = 100
+
+var supportedACMECAContents = []string{
+	string(cmacme.ACMECAContentChain),
+	string(cmacme.ACMECAContentIssuer),
+	string(cmacme.ACMECAContentRoot),
+}
+
 func ValidateACMEIssuerChallengeSolverConfig(sol *cmacme.ACMEChallengeSolver, fldPath *field.Path) field.ErrorList {
 	el := field.ErrorList{}
 
@@ -196,11 +270,90 @@ func ValidateCAIssuerConfig(iss *certmanager.CAIssuer, fldPath *field.Path) fiel
 			el = append(el, field.Invalid(fldPath.Child("ocspServer").Index(i), ocspURL, "must be a valid URL, e.g., http://ocsp.int-x3.letsencrypt.org"))
 		}
 	}
+	if iss.CRL != nil {
+		el = append(el, ValidateCACRL(iss.CRL, fldPath.Child("crl"))...)
+	}
+	if iss.MaxLeafDuration != nil && iss.MaxLeafDuration.Duration <= 0 {
+		el = append(el, field.Invalid(fldPath.Child("maxLeafDuration"), iss.MaxLeafDuration.Duration, "must be greater than zero"))
+	}
+	switch iss.MaxLeafDurationPolicy {
+	case "", certmanager.MaxLeafDurationPolicyClamp, certmanager.MaxLeafDurationPolicyReject:
+	default:
+		el = append(el, field.NotSupported(fldPath.Child("maxLeafDurationPolicy"), iss.MaxLeafDurationPolicy, []string{
+			string(certmanager.MaxLeafDurationPolicyClamp), string(certmanager.MaxLeafDurationPolicyReject),
+		}))
+	}
+	el = append(el, validateCertificateProfile(iss.CertificateProfile, fldPath.Child("certificateProfile"))...)
+	el = append(el, validateCertificatePolicies(iss.CertificatePolicies, fldPath.Child("certificatePolicies"))...)
+	el = append(el, validateCertificatePolicies(iss.AllowedCertificatePolicies, fldPath.Child("allowedCertificatePolicies"))...)
 	return el
 }
 
-func ValidateSelfSignedIssuerConfig(iss *certmanager.SelfSignedIssuer, fldPath *field.Path) field.ErrorList {
-	return nil
+func validateCertificateProfile(profile certmanager.CertificateProfile, fldPath *field.Path) field.ErrorList {
+	switch profile {
+	case "", certmanager.CertificateProfileMinimal, certmanager.CertificateProfileV1:
+		return nil
+	default:
+		return field.ErrorList{field.NotSupported(fldPath, profile, []string{
+			string(certmanager.CertificateProfileMinimal), string(certmanager.CertificateProfileV1),
+		})}
+	}
+}
+
+// validateCertificatePolicies ensures that certificate policies have unique,
+// valid object identifiers and that their CPS URIs can be encoded as the
+// IA5String of a CPS pointer qualifier.
+func validateCertificatePolicies(policies []certmanager.CertificatePolicy, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	seen := make(map[string]bool)
+	for i, policy := range policies {
+		idxPath := fldPath.Index(i)
+		if len(policy.PolicyID) == 0 {
+			el = append(el, field.Required(idxPath.Child("policyID"), ""))
+		} else if _, err := pki.ParseObjectIdentifier(policy.PolicyID); err != nil {
+			el = append(el, field.Invalid(idxPath.Child("policyID"), policy.PolicyID, "must be an object identifier in dotted decimal notation, e.g. 0.4.0.194112.1.2"))
+		} else if seen[policy.PolicyID] {
+			el = append(el, field.Duplicate(idxPath.Child("policyID"), policy.PolicyID))
+		}
+		seen[policy.PolicyID] = true
+
+		if len(policy.CPSURI) > 0 {
+			u, err := url.Parse(policy.CPSURI)
+			if err != nil || !u.IsAbs() || len(u.Host) == 0 || !pki.IsIA5String(policy.CPSURI) {
+				el = append(el, field.Invalid(idxPath.Child("cpsURI"), policy.CPSURI, "must be an absolute URL containing only ASCII characters, e.g. https://pki.example.com/cps"))
+			}
+		}
+	}
+	return el
+}
+
+func ValidateCACRL(crl *certmanager.CACRL, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	if len(crl.SecretName) == 0 {
+		el = append(el, field.Required(fldPath.Child("secretName"), ""))
+	}
+	if crl.Validity != nil && crl.Validity.Duration <= 0 {
+		el = append(el, field.Invalid(fldPath.Child("validity"), crl.Validity.Duration, "must be greater than zero"))
+	}
+	for i, revoked := range crl.RevokedCertificates {
+		if _, err := pki.ParseSerialNumber(revoked.SerialNumber); err != nil {
+			el = append(el, field.Invalid(fldPath.Child("revokedCertificates").Index(i).Child("serialNumber"), revoked.SerialNumber, "must be a hex encoded serial number, e.g. 1f:2a:03"))
+		}
+	}
+	return el
+}
+
+func ValidateSelfSignedIssuerConfig(iss *certmanager.SelfSignedIssuer, fldPath *field.Path) (field.ErrorList, validation.WarningList) {
+	var warnings validation.WarningList
+	el := field.ErrorList{}
+	if iss.KeySeedSecretRef != nil {
+		el = append(el, ValidateSecretKeySelector(iss.KeySeedSecretRef, fldPath.Child("keySeedSecretRef"))...)
+		warnings = append(warnings, selfSignedKeySeedField)
+	}
+	el = append(el, validateCertificateProfile(iss.CertificateProfile, fldPath.Child("certificateProfile"))...)
+	el = append(el, validateCertificatePolicies(iss.CertificatePolicies, fldPath.Child("certificatePolicies"))...)
+	el = append(el, validateCertificatePolicies(iss.AllowedCertificatePolicies, fldPath.Child("allowedCertificatePolicies"))...)
+	return el, warnings
 }
 
 func ValidateVaultIssuerConfig(iss *certmanager.VaultIssuer, fldPath *field.Path) field.ErrorList {
@@ -222,8 +375,34 @@ func ValidateVaultIssuerConfig(iss *certmanager.VaultIssuer, fldPath *field.Path
 		}
 	}
 
+	if len(iss.ExpectedRootCA) > 0 && !x509.NewCertPool().AppendCertsFromPEM(iss.ExpectedRootCA) {
+		el = append(el, field.Invalid(fldPath.Child("expectedRootCA"), "", "must contain at least one PEM encoded CA certificate"))
+	}
+
+	if iss.Auth.Kubernetes != nil {
+		el = append(el, validateVaultKubernetesAuth(iss.Auth.Kubernetes, fldPath.Child("auth", "kubernetes"))...)
+	}
+
+	return el
+	// TODO: add validation for the other Vault authentication types
+}
+
+// validateVaultKubernetesAuth ensures that exactly one source of the
+// ServiceAccount token is configured.
+func validateVaultKubernetesAuth(auth *certmanager.VaultKubernetesAuth, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	if len(auth.Role) == 0 {
+		el = append(el, field.Required(fldPath.Child("role"), ""))
+	}
+	switch {
+	case len(auth.SecretRef.Name) == 0 && len(auth.TokenPath) == 0:
+		el = append(el, field.Required(fldPath, "one of secretRef or tokenPath must be specified"))
+	case len(auth.SecretRef.Name) > 0 && len(auth.TokenPath) > 0:
+		el = append(el, field.Forbidden(fldPath.Child("tokenPath"), "must not be specified together with secretRef"))
+	case len(auth.TokenPath) > 0 && !path.IsAbs(auth.TokenPath):
+		el = append(el, field.Invalid(fldPath.Child("tokenPath"), auth.TokenPath, "must be an absolute path"))
+	}
 	return el
-	// TODO: add validation for Vault authentication types
 }
 
 func ValidateVenafiTPP(tpp *certmanager.VenafiTPP, fldPath *field.Path) (el field.ErrorList) {
@@ -258,6 +437,39 @@ func ValidateVenafiIssuerConfig(iss *certmanager.VenafiIssuer, fldPath *field.Pa
 		el = append(el, field.Forbidden(fldPath, "please supply one of: tpp, cloud"))
 	}
 
+	customFields := make(map[string]bool)
+	for i, m := range iss.CustomFieldAnnotations {
+		fldPath := fldPath.Child("customFieldAnnotations").Index(i)
+		if m.Annotation == "" {
+			el = append(el, field.Required(fldPath.Child("annotation"), ""))
+		} else {
+			for _, msg := range k8svalidation.IsQualifiedName(strings.ToLower(m.Annotation)) {
+				el = append(el, field.Invalid(fldPath.Child("annotation"), m.Annotation, msg))
+			}
+		}
+		if m.CustomField == "" {
+			el = append(el, field.Required(fldPath.Child("customField"), ""))
+		} else if customFields[m.CustomField] {
+			el = append(el, field.Duplicate(fldPath.Child("customField"), m.CustomField))
+		}
+		customFields[m.CustomField] = true
+	}
+
+	return el
+}
+
+func ValidateADCSWebEnrollmentIssuerConfig(iss *certmanager.ADCSWebEnrollmentIssuer, fldPath *field.Path) (el field.ErrorList) {
+	if iss.URL == "" {
+		el = append(el, field.Required(fldPath.Child("url"), ""))
+	} else if u, err := url.Parse(iss.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		el = append(el, field.Invalid(fldPath.Child("url"), iss.URL, "must be an absolute http or https URL"))
+	}
+	if iss.Template == "" {
+		el = append(el, field.Required(fldPath.Child("template"), ""))
+	}
+	if iss.CredentialsRef.Name == "" {
+		el = append(el, field.Required(fldPath.Child("credentialsRef", "name"), ""))
+	}
 	return el
 }
 
@@ -282,6 +494,17 @@ func ValidateACMEChallengeSolverDNS01(p *cmacme.ACMEChallengeSolverDNS01, fldPat
 			el = append(el, field.Invalid(fldPath.Child("cnameStrategy"), p.CNAMEStrategy, fmt.Sprintf("must be one of %q or %q", cmacme.NoneStrategy, cmacme.FollowStrategy)))
 		}
 	}
+	if p.CleanupDelay != nil && p.CleanupDelay.Duration < 0 {
+		el = append(el, field.Invalid(fldPath.Child("cleanupDelay"), p.CleanupDelay.Duration, "must not be negative"))
+	}
+	if p.PropagationThresholdPercent != nil && (*p.PropagationThresholdPercent < 1 || *p.PropagationThresholdPercent > 100) {
+		el = append(el, field.Invalid(fldPath.Child("propagationThresholdPercent"), *p.PropagationThresholdPercent, "must be between 1 and 100"))
+	}
+	if len(p.ZoneName) > 0 {
+		for _, msg := range k8svalidation.IsDNS1123Subdomain(strings.TrimSuffix(p.ZoneName, ".")) {
+			el = append(el, field.Invalid(fldPath.Child("zoneName"), p.ZoneName, msg))
+		}
+	}
 	numProviders := 0
 	if p.Akamai != nil {
 		numProviders++
@@ -441,7 +664,29 @@ func ValidateACMEChallengeSolverDNS01(p *cmacme.ACMEChallengeSolverDNS01, fldPat
 	if numProviders == 0 {
 		el = append(el, field.Required(fldPath, "no DNS01 provider configured"))
 	}
+	if len(p.ZoneCredentials) > 0 && p.Webhook != nil {
+		el = append(el, field.Forbidden(fldPath.Child("zoneCredentials"), "not supported for webhook solvers"))
+	}
+	for i, zc := range p.ZoneCredentials {
+		el = append(el, ValidateACMEChallengeSolverDNS01ZoneCredentials(&zc, fldPath.Child("zoneCredentials").Index(i))...)
+	}
+
+	return el
+}
 
+func ValidateACMEChallengeSolverDNS01ZoneCredentials(zc *cmacme.ACMEChallengeSolverDNS01ZoneCredentials, fldPath *field.Path) field.ErrorList {
+	el := field.ErrorList{}
+	if len(zc.DNSZones) == 0 {
+		el = append(el, field.Required(fldPath.Child("dnsZones"), "at least one DNS zone must be specified"))
+	}
+	for i, zone := range zc.DNSZones {
+		if len(zone) == 0 {
+			el = append(el, field.Invalid(fldPath.Child("dnsZones").Index(i), zone, "must not be empty"))
+		}
+	}
+	if len(zc.SecretName) == 0 {
+		el = append(el, field.Required(fldPath.Child("secretName"), "secret name is required"))
+	}
 	return el
 }
 
@@ -19,10 +19,13 @@ package validation
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/jetstack/cert-manager/pkg/internal/api/validation"
@@ -87,6 +90,84 @@ func TestValidateVaultIssuerConfig(t *testing.T) {
 				field.Invalid(fldPath.Child("caBundle"), "", "Specified CA bundle is invalid"),
 			},
 		},
+		"vault issuer with invalid expected root CA": {
+			spec: &cmapi.VaultIssuer{
+				Server:         "something",
+				Path:           "a/b/c",
+				ExpectedRootCA: []byte("invalid"),
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("expectedRootCA"), "", "must contain at least one PEM encoded CA certificate"),
+			},
+		},
+		"vault issuer with kubernetes auth using a secret": {
+			spec: &cmapi.VaultIssuer{
+				Server: "something",
+				Path:   "a/b/c",
+				Auth: cmapi.VaultAuth{
+					Kubernetes: &cmapi.VaultKubernetesAuth{
+						Role:      "cert-manager",
+						SecretRef: validSecretKeyRef,
+					},
+				},
+			},
+		},
+		"vault issuer with kubernetes auth using a token path": {
+			spec: &cmapi.VaultIssuer{
+				Server: "something",
+				Path:   "a/b/c",
+				Auth: cmapi.VaultAuth{
+					Kubernetes: &cmapi.VaultKubernetesAuth{
+						Role:      "cert-manager",
+						TokenPath: "/var/run/secrets/vault/token",
+					},
+				},
+			},
+		},
+		"vault issuer with kubernetes auth missing role and token": {
+			spec: &cmapi.VaultIssuer{
+				Server: "something",
+				Path:   "a/b/c",
+				Auth: cmapi.VaultAuth{
+					Kubernetes: &cmapi.VaultKubernetesAuth{},
+				},
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("auth", "kubernetes", "role"), ""),
+				field.Required(fldPath.Child("auth", "kubernetes"), "one of secretRef or tokenPath must be specified"),
+			},
+		},
+		"vault issuer with kubernetes auth using both a secret and a token path": {
+			spec: &cmapi.VaultIssuer{
+				Server: "something",
+				Path:   "a/b/c",
+				Auth: cmapi.VaultAuth{
+					Kubernetes: &cmapi.VaultKubernetesAuth{
+						Role:      "cert-manager",
+						SecretRef: validSecretKeyRef,
+						TokenPath: "/var/run/secrets/vault/token",
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Forbidden(fldPath.Child("auth", "kubernetes", "tokenPath"), "must not be specified together with secretRef"),
+			},
+		},
+		"vault issuer with kubernetes auth using a relative token path": {
+			spec: &cmapi.VaultIssuer{
+				Server: "something",
+				Path:   "a/b/c",
+				Auth: cmapi.VaultAuth{
+					Kubernetes: &cmapi.VaultKubernetesAuth{
+						Role:      "cert-manager",
+						TokenPath: "token",
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("auth", "kubernetes", "tokenPath"), "token", "must be an absolute path"),
+			},
+		},
 	}
 	for n, s := range scenarios {
 		t.Run(n, func(t *testing.T) {
@@ -135,6 +216,74 @@ func TestValidateACMEIssuerConfig(t *testing.T) {
 				field.Required(fldPath.Child("solvers").Index(0), "no solver type configured"),
 			},
 		},
+		"acme issuer with a supported jws algorithm": {
+			spec: &cmacme.ACMEIssuer{
+				Email:        "valid-email",
+				Server:       "valid-server",
+				PrivateKey:   validSecretKeyRef,
+				JWSAlgorithm: cmacme.ES384,
+			},
+		},
+		"acme issuer with an unsupported jws algorithm": {
+			spec: &cmacme.ACMEIssuer{
+				Email:        "valid-email",
+				Server:       "valid-server",
+				PrivateKey:   validSecretKeyRef,
+				JWSAlgorithm: "PS256",
+			},
+			errs: []*field.Error{
+				field.NotSupported(fldPath.Child("jwsAlgorithm"), cmacme.JWSAlgorithm("PS256"), supportedJWSAlgorithms),
+			},
+		},
+		"acme issuer with a supported ca content": {
+			spec: &cmacme.ACMEIssuer{
+				Email:      "valid-email",
+				Server:     "valid-server",
+				PrivateKey: validSecretKeyRef,
+				CAContent:  cmacme.ACMECAContentRoot,
+			},
+		},
+		"acme issuer with an unsupported ca content": {
+			spec: &cmacme.ACMEIssuer{
+				Email:      "valid-email",
+				Server:     "valid-server",
+				PrivateKey: validSecretKeyRef,
+				CAContent:  "Intermediates",
+			},
+			errs: []*field.Error{
+				field.NotSupported(fldPath.Child("caContent"), cmacme.ACMECAContent("Intermediates"), supportedACMECAContents),
+			},
+		},
+		"acme issuer with a nonce pool": {
+			spec: &cmacme.ACMEIssuer{
+				Email:         "valid-email",
+				Server:        "valid-server",
+				PrivateKey:    validSecretKeyRef,
+				NoncePoolSize: 10,
+			},
+		},
+		"acme issuer with a negative nonce pool size": {
+			spec: &cmacme.ACMEIssuer{
+				Email:         "valid-email",
+				Server:        "valid-server",
+				PrivateKey:    validSecretKeyRef,
+				NoncePoolSize: -1,
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("noncePoolSize"), -1, "must be between 0 and 100"),
+			},
+		},
+		"acme issuer with a nonce pool size that is too large": {
+			spec: &cmacme.ACMEIssuer{
+				Email:         "valid-email",
+				Server:        "valid-server",
+				PrivateKey:    validSecretKeyRef,
+				NoncePoolSize: 101,
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("noncePoolSize"), 101, "must be between 0 and 100"),
+			},
+		},
 		"acme solver with valid dns01 config": {
 			spec: &cmacme.ACMEIssuer{
 				Email:      "valid-email",
@@ -336,6 +485,92 @@ func TestValidateACMEIssuerConfig(t *testing.T) {
 	}
 }
 
+func TestValidateSelfSignedIssuerConfig(t *testing.T) {
+	fldPath := field.NewPath("")
+	scenarios := map[string]struct {
+		spec     *cmapi.SelfSignedIssuer
+		errs     []*field.Error
+		warnings validation.WarningList
+	}{
+		"valid selfsigned issuer": {
+			spec: &cmapi.SelfSignedIssuer{},
+		},
+		"selfsigned issuer with a key seed": {
+			spec: &cmapi.SelfSignedIssuer{
+				KeySeedSecretRef: &validSecretKeyRef,
+			},
+			warnings: validation.WarningList{selfSignedKeySeedField},
+		},
+		"selfsigned issuer with a key seed missing the secret key": {
+			spec: &cmapi.SelfSignedIssuer{
+				KeySeedSecretRef: &cmmeta.SecretKeySelector{
+					LocalObjectReference: cmmeta.LocalObjectReference{Name: "seed"},
+				},
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("keySeedSecretRef", "key"), "secret key is required"),
+			},
+			warnings: validation.WarningList{selfSignedKeySeedField},
+		},
+		"selfsigned issuer with certificate policies": {
+			spec: &cmapi.SelfSignedIssuer{
+				CertificatePolicies: []cmapi.CertificatePolicy{
+					{PolicyID: "0.4.0.194112.1.2", CPSURI: "https://pki.example.com/cps"},
+					{PolicyID: "2.23.140.1.2.1"},
+				},
+				AllowedCertificatePolicies: []cmapi.CertificatePolicy{
+					{PolicyID: "2.23.140.1.2.2", CPSURI: "https://pki.example.com/cps"},
+				},
+			},
+		},
+		"selfsigned issuer with invalid allowed certificate policies": {
+			spec: &cmapi.SelfSignedIssuer{
+				AllowedCertificatePolicies: []cmapi.CertificatePolicy{
+					{PolicyID: "not-an-oid"},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("allowedCertificatePolicies").Index(0).Child("policyID"), "not-an-oid", "must be an object identifier in dotted decimal notation, e.g. 0.4.0.194112.1.2"),
+			},
+		},
+		"selfsigned issuer with invalid certificate policies": {
+			spec: &cmapi.SelfSignedIssuer{
+				CertificatePolicies: []cmapi.CertificatePolicy{
+					{PolicyID: ""},
+					{PolicyID: "1.2.03"},
+					{PolicyID: "1.40.1"},
+					{PolicyID: "2.23.140.1.2.1", CPSURI: "/cps"},
+					{PolicyID: "2.23.140.1.2.1", CPSURI: "https://pki.exämple.com/cps"},
+				},
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("certificatePolicies").Index(0).Child("policyID"), ""),
+				field.Invalid(fldPath.Child("certificatePolicies").Index(1).Child("policyID"), "1.2.03", "must be an object identifier in dotted decimal notation, e.g. 0.4.0.194112.1.2"),
+				field.Invalid(fldPath.Child("certificatePolicies").Index(2).Child("policyID"), "1.40.1", "must be an object identifier in dotted decimal notation, e.g. 0.4.0.194112.1.2"),
+				field.Invalid(fldPath.Child("certificatePolicies").Index(3).Child("cpsURI"), "/cps", "must be an absolute URL containing only ASCII characters, e.g. https://pki.example.com/cps"),
+				field.Duplicate(fldPath.Child("certificatePolicies").Index(4).Child("policyID"), "2.23.140.1.2.1"),
+				field.Invalid(fldPath.Child("certificatePolicies").Index(4).Child("cpsURI"), "https://pki.exämple.com/cps", "must be an absolute URL containing only ASCII characters, e.g. https://pki.example.com/cps"),
+			},
+		},
+	}
+	for n, s := range scenarios {
+		t.Run(n, func(t *testing.T) {
+			errs, warnings := ValidateSelfSignedIssuerConfig(s.spec, fldPath)
+			if len(errs) != len(s.errs) {
+				t.Errorf("Expected %v but got %v", s.errs, errs)
+				return
+			}
+			for i, e := range errs {
+				expectedErr := s.errs[i]
+				if !reflect.DeepEqual(e, expectedErr) {
+					t.Errorf("Expected %v but got %v", expectedErr, e)
+				}
+			}
+			assert.Equal(t, s.warnings, warnings)
+		})
+	}
+}
+
 func TestValidateIssuerSpec(t *testing.T) {
 	fldPath := field.NewPath("")
 	scenarios := map[string]struct {
@@ -369,6 +604,16 @@ func TestValidateIssuerSpec(t *testing.T) {
 			},
 			errs: []*field.Error{},
 		},
+		"valid self signed issuer with a certificate profile": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					SelfSigned: &cmapi.SelfSignedIssuer{
+						CertificateProfile: cmapi.CertificateProfileMinimal,
+					},
+				},
+			},
+			errs: []*field.Error{},
+		},
 		"valid acme issuer": {
 			spec: &cmapi.IssuerSpec{
 				IssuerConfig: cmapi.IssuerConfig{
@@ -430,6 +675,112 @@ func TestValidateIssuerSpec(t *testing.T) {
 				field.Invalid(fldPath.Child("ca", "ocspServer").Index(0), "", `must be a valid URL, e.g., http://ocsp.int-x3.letsencrypt.org`),
 			},
 		},
+		"valid ca issuer with a crl": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{
+						SecretName: "valid",
+						CRL: &cmapi.CACRL{
+							SecretName: "crl",
+							Validity:   &metav1.Duration{Duration: time.Hour},
+							RevokedCertificates: []cmapi.CARevokedCertificate{
+								{SerialNumber: "1f:2a:03", RevocationTime: metav1.Now()},
+							},
+						},
+					},
+				},
+			},
+			errs: []*field.Error{},
+		},
+		"invalid ca issuer crl": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{
+						SecretName: "valid",
+						CRL: &cmapi.CACRL{
+							Validity: &metav1.Duration{Duration: -time.Hour},
+							RevokedCertificates: []cmapi.CARevokedCertificate{
+								{SerialNumber: "1f:2a:03"},
+								{SerialNumber: "not-hex"},
+							},
+						},
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("ca", "crl", "secretName"), ""),
+				field.Invalid(fldPath.Child("ca", "crl", "validity"), -time.Hour, "must be greater than zero"),
+				field.Invalid(fldPath.Child("ca", "crl", "revokedCertificates").Index(1).Child("serialNumber"), "not-hex", "must be a hex encoded serial number, e.g. 1f:2a:03"),
+			},
+		},
+		"valid ca issuer with a maximum leaf duration": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{
+						SecretName:            "valid",
+						MaxLeafDuration:       &metav1.Duration{Duration: 90 * 24 * time.Hour},
+						MaxLeafDurationPolicy: cmapi.MaxLeafDurationPolicyReject,
+					},
+				},
+			},
+			errs: []*field.Error{},
+		},
+		"invalid ca issuer maximum leaf duration": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{
+						SecretName:            "valid",
+						MaxLeafDuration:       &metav1.Duration{Duration: -time.Hour},
+						MaxLeafDurationPolicy: "Truncate",
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("ca", "maxLeafDuration"), -time.Hour, "must be greater than zero"),
+				field.NotSupported(fldPath.Child("ca", "maxLeafDurationPolicy"), cmapi.MaxLeafDurationPolicy("Truncate"), []string{"Clamp", "Reject"}),
+			},
+		},
+		"invalid ca issuer certificate profile": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{
+						SecretName:         "valid",
+						CertificateProfile: "V2",
+					},
+				},
+			},
+			errs: []*field.Error{
+				field.NotSupported(fldPath.Child("ca", "certificateProfile"), cmapi.CertificateProfile("V2"), []string{"Minimal", "V1"}),
+			},
+		},
+		"issuer with chain validation without trust anchors": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{
+						SecretName: "valid",
+					},
+				},
+				ChainValidation: &cmapi.IssuerChainValidation{},
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("chainValidation", "trustAnchors"), ""),
+			},
+		},
+		"issuer with chain validation with invalid trust anchors": {
+			spec: &cmapi.IssuerSpec{
+				IssuerConfig: cmapi.IssuerConfig{
+					CA: &cmapi.CAIssuer{
+						SecretName: "valid",
+					},
+				},
+				ChainValidation: &cmapi.IssuerChainValidation{
+					TrustAnchors: []byte("invalid"),
+				},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("chainValidation", "trustAnchors"), "", "must contain at least one PEM encoded CA certificate"),
+			},
+		},
 	}
 	for n, s := range scenarios {
 		t.Run(n, func(t *testing.T) {
@@ -947,6 +1298,84 @@ func TestValidateACMEIssuerDNS01Config(t *testing.T) {
 				field.Forbidden(fldPath.Child("cloudflare"), "may not specify more than one provider type"),
 			},
 		},
+		"valid cleanup delay": {
+			cfg: &cmacme.ACMEChallengeSolverDNS01{
+				CloudDNS:     &validCloudDNSProvider,
+				CleanupDelay: &metav1.Duration{Duration: time.Minute},
+			},
+		},
+		"negative cleanup delay": {
+			cfg: &cmacme.ACMEChallengeSolverDNS01{
+				CloudDNS:     &validCloudDNSProvider,
+				CleanupDelay: &metav1.Duration{Duration: -time.Minute},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("cleanupDelay"), -time.Minute, "must not be negative"),
+			},
+		},
+		"valid propagation threshold": {
+			cfg: &cmacme.ACMEChallengeSolverDNS01{
+				CloudDNS:                    &validCloudDNSProvider,
+				PropagationThresholdPercent: intPtr(75),
+			},
+		},
+		"propagation threshold out of range": {
+			cfg: &cmacme.ACMEChallengeSolverDNS01{
+				CloudDNS:                    &validCloudDNSProvider,
+				PropagationThresholdPercent: intPtr(0),
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("propagationThresholdPercent"), 0, "must be between 1 and 100"),
+			},
+		},
+		"valid zone name": {
+			cfg: &cmacme.ACMEChallengeSolverDNS01{
+				CloudDNS: &validCloudDNSProvider,
+				ZoneName: "example.com.",
+			},
+		},
+		"invalid zone name": {
+			cfg: &cmacme.ACMEChallengeSolverDNS01{
+				CloudDNS: &validCloudDNSProvider,
+				ZoneName: "example..com",
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("zoneName"), "example..com", k8svalidation.IsDNS1123Subdomain("example..com")[0]),
+			},
+		},
+		"valid zone credentials": {
+			cfg: &cmacme.ACMEChallengeSolverDNS01{
+				CloudDNS: &validCloudDNSProvider,
+				ZoneCredentials: []cmacme.ACMEChallengeSolverDNS01ZoneCredentials{
+					{DNSZones: []string{"example.com"}, SecretName: "example-com"},
+					{DNSZones: []string{"example.org", "example.net"}, SecretName: "example-org"},
+				},
+			},
+		},
+		"zone credentials missing zones and secret name": {
+			cfg: &cmacme.ACMEChallengeSolverDNS01{
+				CloudDNS:        &validCloudDNSProvider,
+				ZoneCredentials: []cmacme.ACMEChallengeSolverDNS01ZoneCredentials{{}},
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("zoneCredentials").Index(0).Child("dnsZones"), "at least one DNS zone must be specified"),
+				field.Required(fldPath.Child("zoneCredentials").Index(0).Child("secretName"), "secret name is required"),
+			},
+		},
+		"zone credentials with a webhook solver": {
+			cfg: &cmacme.ACMEChallengeSolverDNS01{
+				Webhook: &cmacme.ACMEIssuerDNS01ProviderWebhook{
+					GroupName:  "example.com",
+					SolverName: "example",
+				},
+				ZoneCredentials: []cmacme.ACMEChallengeSolverDNS01ZoneCredentials{
+					{DNSZones: []string{"example.com"}, SecretName: "example-com"},
+				},
+			},
+			errs: []*field.Error{
+				field.Forbidden(fldPath.Child("zoneCredentials"), "not supported for webhook solvers"),
+			},
+		},
 	}
 	for n, s := range scenarios {
 		t.Run(n, func(t *testing.T) {
@@ -1075,6 +1504,38 @@ func TestValidateVenafiIssuerConfig(t *testing.T) {
 				field.Forbidden(fldPath, "please supply one of: tpp, cloud"),
 			},
 		},
+		"valid custom field annotations": {
+			cfg: &cmapi.VenafiIssuer{
+				Zone: "a\\b\\c",
+				TPP: &cmapi.VenafiTPP{
+					URL: "https://tpp.example.com/vedsdk",
+				},
+				CustomFieldAnnotations: []cmapi.VenafiCustomFieldAnnotation{
+					{Annotation: "example.com/requestor", CustomField: "Requestor"},
+					{Annotation: "example.com/cost-centre", CustomField: "Cost Centre"},
+				},
+			},
+		},
+		"invalid custom field annotations": {
+			cfg: &cmapi.VenafiIssuer{
+				Zone: "a\\b\\c",
+				TPP: &cmapi.VenafiTPP{
+					URL: "https://tpp.example.com/vedsdk",
+				},
+				CustomFieldAnnotations: []cmapi.VenafiCustomFieldAnnotation{
+					{Annotation: "", CustomField: "Requestor"},
+					{Annotation: "example.com/requestor", CustomField: ""},
+					{Annotation: "not a valid/annotation/key", CustomField: "Cost Centre"},
+					{Annotation: "example.com/owner", CustomField: "Requestor"},
+				},
+			},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("customFieldAnnotations").Index(0).Child("annotation"), ""),
+				field.Required(fldPath.Child("customFieldAnnotations").Index(1).Child("customField"), ""),
+				field.Invalid(fldPath.Child("customFieldAnnotations").Index(2).Child("annotation"), "not a valid/annotation/key", k8svalidation.IsQualifiedName("not a valid/annotation/key")[0]),
+				field.Duplicate(fldPath.Child("customFieldAnnotations").Index(3).Child("customField"), "Requestor"),
+			},
+		},
 	}
 
 	for n, s := range scenarios {
@@ -1127,3 +1588,52 @@ func TestValidateVenafiTPP(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateADCSWebEnrollmentIssuerConfig(t *testing.T) {
+	fldPath := field.NewPath("test")
+	scenarios := map[string]struct {
+		cfg  *cmapi.ADCSWebEnrollmentIssuer
+		errs []*field.Error
+	}{
+		"valid": {
+			cfg: &cmapi.ADCSWebEnrollmentIssuer{
+				URL:            "https://adcs.example.com/certsrv",
+				Template:       "WebServer",
+				CredentialsRef: cmmeta.LocalObjectReference{Name: "adcs-credentials"},
+			},
+		},
+		"missing fields": {
+			cfg: &cmapi.ADCSWebEnrollmentIssuer{},
+			errs: []*field.Error{
+				field.Required(fldPath.Child("url"), ""),
+				field.Required(fldPath.Child("template"), ""),
+				field.Required(fldPath.Child("credentialsRef", "name"), ""),
+			},
+		},
+		"relative url": {
+			cfg: &cmapi.ADCSWebEnrollmentIssuer{
+				URL:            "adcs.example.com/certsrv",
+				Template:       "WebServer",
+				CredentialsRef: cmmeta.LocalObjectReference{Name: "adcs-credentials"},
+			},
+			errs: []*field.Error{
+				field.Invalid(fldPath.Child("url"), "adcs.example.com/certsrv", "must be an absolute http or https URL"),
+			},
+		},
+	}
+
+	for n, s := range scenarios {
+		t.Run(n, func(t *testing.T) {
+			errs := ValidateADCSWebEnrollmentIssuerConfig(s.cfg, fldPath)
+			if len(errs) != len(s.errs) {
+				t.Fatalf("Expected %v but got %v", s.errs, errs)
+			}
+			for i, e := range errs {
+				expectedErr := s.errs[i]
+				if !reflect.DeepEqual(e, expectedErr) {
+					t.Errorf("Expected %v but got %v", expectedErr, e)
+				}
+			}
+		})
+	}
+}
@@ -21,4 +21,7 @@ package validation
 const (
 	// deprecatedACMEEABKeyAlgorithmField is raised when the deprecated keyAlgorithm field for an ACME issuer's external account binding (EAB) is set.
 	deprecatedACMEEABKeyAlgorithmField = "ACME issuer spec field 'externalAccount.keyAlgorithm' is deprecated. The value of this field will be ignored."
+
+	// selfSignedKeySeedField is raised when a SelfSigned issuer derives private keys from a seed.
+	selfSignedKeySeedField = "SelfSigned issuer spec field 'keySeedSecretRef' derives private keys deterministically from a seed, which is not suitable for production use."
 )
@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// tokenExpiryMargin is how long before its lease ends a cached token is
+// treated as expired, so that it does not expire mid-request.
+const tokenExpiryMargin = time.Second * 30
+
+// vaultToken is a Vault token along with its lease.
+type vaultToken struct {
+	id        string
+	ttl       time.Duration
+	renewable bool
+}
+
+type cachedToken struct {
+	vaultToken
+	// renewAt is the time after which the token should be renewed.
+	renewAt time.Time
+	// expiresAt is the time after which the token is no longer used.
+	expiresAt time.Time
+}
+
+// tokenCache caches the Vault tokens obtained by logging in to Vault, so that
+// Vault does not have to be logged in to for every CertificateRequest.
+type tokenCache struct {
+	clock clock.Clock
+
+	lock   sync.Mutex
+	tokens map[string]cachedToken
+}
+
+func newTokenCache(clock clock.Clock) *tokenCache {
+	return &tokenCache{
+		clock:  clock,
+		tokens: make(map[string]cachedToken),
+	}
+}
+
+// get returns the token cached under key, if it has not expired.
+// needsRenewal is true once two thirds of the token's lease has passed.
+func (c *tokenCache) get(key string) (token vaultToken, needsRenewal bool, ok bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	cached, ok := c.tokens[key]
+	if !ok {
+		return vaultToken{}, false, false
+	}
+	now := c.clock.Now()
+	if !now.Before(cached.expiresAt) {
+		delete(c.tokens, key)
+		return vaultToken{}, false, false
+	}
+	return cached.vaultToken, !now.Before(cached.renewAt), true
+}
+
+// set caches the token under key until shortly before its lease ends. Tokens
+// without a lease, or with a lease too short to be worth caching, are not
+// cached.
+func (c *tokenCache) set(key string, token vaultToken) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if token.ttl <= tokenExpiryMargin {
+		delete(c.tokens, key)
+		return
+	}
+	now := c.clock.Now()
+	c.tokens[key] = cachedToken{
+		vaultToken: token,
+		renewAt:    now.Add(token.ttl * 2 / 3),
+		expiresAt:  now.Add(token.ttl - tokenExpiryMargin),
+	}
+}
+
+// invalidate removes the token cached under key.
+func (c *tokenCache) invalidate(key string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.tokens, key)
+}
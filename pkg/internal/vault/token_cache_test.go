@@ -0,0 +1,71 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"testing"
+	"time"
+
+	fakeclock "k8s.io/utils/clock/testing"
+)
+
+func TestTokenCache(t *testing.T) {
+	fakeClock := fakeclock.NewFakeClock(time.Now())
+	cache := newTokenCache(fakeClock)
+	token := vaultToken{id: "token", ttl: time.Hour, renewable: true}
+
+	expectCached := func(t *testing.T, key string, expectOK, expectNeedsRenewal bool) {
+		t.Helper()
+		got, needsRenewal, ok := cache.get(key)
+		if ok != expectOK {
+			t.Fatalf("expected token to be cached=%t but got %t", expectOK, ok)
+		}
+		if !ok {
+			return
+		}
+		if got != token {
+			t.Errorf("expected cached token %+v but got %+v", token, got)
+		}
+		if needsRenewal != expectNeedsRenewal {
+			t.Errorf("expected needsRenewal=%t but got %t", expectNeedsRenewal, needsRenewal)
+		}
+	}
+
+	expectCached(t, "key", false, false)
+
+	cache.set("key", token)
+	expectCached(t, "key", true, false)
+	expectCached(t, "other-key", false, false)
+
+	// the token is due to be renewed once two thirds of its lease has passed
+	fakeClock.Step(time.Minute * 41)
+	expectCached(t, "key", true, true)
+
+	// and is no longer used shortly before its lease ends
+	fakeClock.Step(time.Hour - time.Minute*41 - tokenExpiryMargin)
+	expectCached(t, "key", false, false)
+
+	// setting the token again resets its lease
+	cache.set("key", token)
+	expectCached(t, "key", true, false)
+	cache.invalidate("key")
+	expectCached(t, "key", false, false)
+
+	// tokens without a lease are not cached
+	cache.set("key", vaultToken{id: "token"})
+	expectCached(t, "key", false, false)
+}
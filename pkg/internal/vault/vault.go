@@ -20,6 +20,7 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"path"
 	"path/filepath"
@@ -29,6 +30,7 @@ import (
 	vault "github.com/hashicorp/vault/api"
 	"github.com/hashicorp/vault/sdk/helper/certutil"
 	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/utils/clock"
 
 	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	"github.com/jetstack/cert-manager/pkg/util/pki"
@@ -58,15 +60,51 @@ type Vault struct {
 	issuer        v1.GenericIssuer
 	namespace     string
 
+	// backendCABundle is a PEM encoded CA bundle which, if set, is trusted
+	// exclusively when connecting to Vault instead of the system root CAs.
+	// A CA bundle configured on the issuer itself takes precedence.
+	backendCABundle []byte
+
+	// canUseAmbientCredentials is true if Kubernetes auth may read the
+	// ServiceAccount token from the file at its tokenPath.
+	canUseAmbientCredentials bool
+
+	// tokenCache caches the Vault tokens obtained using Kubernetes auth. If
+	// nil, Vault is logged in to every time a client is built.
+	tokenCache *tokenCache
+
 	client Client
 }
 
 func New(namespace string, secretsLister corelisters.SecretLister,
 	issuer v1.GenericIssuer) (Interface, error) {
+	return newVault(namespace, secretsLister, issuer, nil, false, nil)
+}
+
+// NewBuilder returns a VaultClientBuilder that builds clients which only trust
+// the certificates in the given PEM encoded CA bundle when connecting to
+// Vault. If caBundle is empty, the system root CAs are used.
+// canUseAmbientCredentials reports whether an issuer may use Kubernetes auth
+// with a ServiceAccount token read from the controller's filesystem.
+// Vault tokens obtained using Kubernetes auth are shared between the clients
+// built, until shortly before they expire.
+func NewBuilder(caBundle []byte, canUseAmbientCredentials func(v1.GenericIssuer) bool) VaultClientBuilder {
+	cache := newTokenCache(clock.RealClock{})
+	return func(namespace string, secretsLister corelisters.SecretLister,
+		issuer v1.GenericIssuer) (Interface, error) {
+		return newVault(namespace, secretsLister, issuer, caBundle, canUseAmbientCredentials(issuer), cache)
+	}
+}
+
+func newVault(namespace string, secretsLister corelisters.SecretLister,
+	issuer v1.GenericIssuer, backendCABundle []byte, canUseAmbientCredentials bool, cache *tokenCache) (Interface, error) {
 	v := &Vault{
-		secretsLister: secretsLister,
-		namespace:     namespace,
-		issuer:        issuer,
+		secretsLister:            secretsLister,
+		namespace:                namespace,
+		issuer:                   issuer,
+		backendCABundle:          backendCABundle,
+		canUseAmbientCredentials: canUseAmbientCredentials,
+		tokenCache:               cache,
 	}
 
 	cfg, err := v.newConfig()
@@ -105,18 +143,25 @@ func (v *Vault) Sign(csrPEM []byte, duration time.Duration) (cert []byte, ca []b
 		"exclude_cn_from_sans": "true",
 	}
 
-	vaultIssuer := v.issuer.GetSpec().Vault
-	url := path.Join("/v1", vaultIssuer.Path)
-
-	request := v.client.NewRequest("POST", url)
-
-	v.addVaultNamespaceToRequest(request)
-
-	if err := request.SetJSONBody(parameters); err != nil {
+	request, err := v.newSignRequest(parameters)
+	if err != nil {
 		return nil, nil, fmt.Errorf("failed to build vault request: %s", err)
 	}
 
 	resp, err := v.client.RawRequest(request)
+	if err != nil && v.shouldRelogin(resp) {
+		// The token obtained using Kubernetes auth may have expired or been
+		// revoked before the end of its lease, so log in again and retry.
+		resp.Body.Close()
+		if err := v.relogin(); err != nil {
+			return nil, nil, fmt.Errorf("failed to log in to vault again after permission was denied: %s", err)
+		}
+		request, err = v.newSignRequest(parameters)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to build vault request: %s", err)
+		}
+		resp, err = v.client.RawRequest(request)
+	}
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to sign certificate by vault: %s", err)
 	}
@@ -132,6 +177,40 @@ func (v *Vault) Sign(csrPEM []byte, duration time.Duration) (cert []byte, ca []b
 	return extractCertificatesFromVaultCertificateSecret(&vaultResult)
 }
 
+func (v *Vault) newSignRequest(parameters map[string]string) (*vault.Request, error) {
+	vaultIssuer := v.issuer.GetSpec().Vault
+	url := path.Join("/v1", vaultIssuer.Path)
+
+	request := v.client.NewRequest("POST", url)
+
+	v.addVaultNamespaceToRequest(request)
+
+	if err := request.SetJSONBody(parameters); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// shouldRelogin returns true if Vault denied a request because the token
+// obtained using Kubernetes auth is no longer valid. Other auth methods are
+// not retried, as logging in again would not produce a different token.
+func (v *Vault) shouldRelogin(resp *vault.Response) bool {
+	return resp != nil && resp.Response != nil &&
+		resp.StatusCode == http.StatusForbidden &&
+		v.issuer.GetSpec().Vault.Auth.Kubernetes != nil
+}
+
+// relogin discards the cached token for the issuer's Kubernetes auth and
+// logs in to Vault again.
+func (v *Vault) relogin() error {
+	kubernetesAuth := v.issuer.GetSpec().Vault.Auth.Kubernetes
+	if v.tokenCache != nil {
+		v.tokenCache.invalidate(v.kubernetesAuthCacheKey(kubernetesAuth))
+	}
+	v.client.SetToken("")
+	return v.setToken(v.client)
+}
+
 func (v *Vault) setToken(client Client) error {
 	tokenRef := v.issuer.GetSpec().Vault.Auth.TokenSecretRef
 	if tokenRef != nil {
@@ -157,9 +236,9 @@ func (v *Vault) setToken(client Client) error {
 
 	kubernetesAuth := v.issuer.GetSpec().Vault.Auth.Kubernetes
 	if kubernetesAuth != nil {
-		token, err := v.requestTokenWithKubernetesAuth(client, kubernetesAuth)
+		token, err := v.kubernetesAuthToken(client, kubernetesAuth)
 		if err != nil {
-			return fmt.Errorf("error reading Kubernetes service account token from %s: %s", kubernetesAuth.SecretRef.Name, err.Error())
+			return fmt.Errorf("error reading Kubernetes service account token from %s: %s", kubernetesAuthTokenSource(kubernetesAuth), err.Error())
 		}
 		client.SetToken(token)
 		return nil
@@ -173,6 +252,9 @@ func (v *Vault) newConfig() (*vault.Config, error) {
 	cfg.Address = v.issuer.GetSpec().Vault.Server
 
 	certs := v.issuer.GetSpec().Vault.CABundle
+	if len(certs) == 0 {
+		certs = v.backendCABundle
+	}
 	if len(certs) == 0 {
 		return cfg, nil
 	}
@@ -281,7 +363,89 @@ func (v *Vault) requestTokenWithAppRoleRef(client Client, appRole *v1.VaultAppRo
 	return token, nil
 }
 
-func (v *Vault) requestTokenWithKubernetesAuth(client Client, kubernetesAuth *v1.VaultKubernetesAuth) (string, error) {
+// kubernetesAuthTokenSource returns the name of the Secret or the path of the
+// file the ServiceAccount token for Kubernetes auth is read from.
+func kubernetesAuthTokenSource(kubernetesAuth *v1.VaultKubernetesAuth) string {
+	if kubernetesAuth.TokenPath != "" {
+		return kubernetesAuth.TokenPath
+	}
+	return kubernetesAuth.SecretRef.Name
+}
+
+// kubernetesAuthCacheKey returns the key that tokens obtained using the given
+// Kubernetes auth configuration are cached under.
+func (v *Vault) kubernetesAuthCacheKey(kubernetesAuth *v1.VaultKubernetesAuth) string {
+	vaultIssuer := v.issuer.GetSpec().Vault
+	source := "file:" + kubernetesAuth.TokenPath
+	if kubernetesAuth.TokenPath == "" {
+		source = fmt.Sprintf("secret:%s/%s/%s", v.namespace, kubernetesAuth.SecretRef.Name, kubernetesAuth.SecretRef.Key)
+	}
+	return strings.Join([]string{vaultIssuer.Server, vaultIssuer.Namespace, kubernetesAuth.Path, kubernetesAuth.Role, source}, "|")
+}
+
+// kubernetesAuthToken returns a Vault token obtained using Kubernetes auth.
+// Tokens are cached until shortly before they expire. Renewable tokens are
+// renewed when they are due to expire, and Vault is logged in to again if
+// they cannot be.
+func (v *Vault) kubernetesAuthToken(client Client, kubernetesAuth *v1.VaultKubernetesAuth) (string, error) {
+	if v.tokenCache == nil {
+		token, err := v.requestTokenWithKubernetesAuth(client, kubernetesAuth)
+		return token.id, err
+	}
+
+	key := v.kubernetesAuthCacheKey(kubernetesAuth)
+	cached, needsRenewal, ok := v.tokenCache.get(key)
+	if ok && !needsRenewal {
+		return cached.id, nil
+	}
+	if ok && cached.renewable {
+		renewed, err := v.renewToken(client, cached.id)
+		if err == nil {
+			v.tokenCache.set(key, renewed)
+			return renewed.id, nil
+		}
+		client.SetToken("")
+	}
+
+	token, err := v.requestTokenWithKubernetesAuth(client, kubernetesAuth)
+	if err != nil {
+		return "", err
+	}
+	v.tokenCache.set(key, token)
+	return token.id, nil
+}
+
+// renewToken renews the given token using the token's own renew-self
+// endpoint.
+func (v *Vault) renewToken(client Client, token string) (vaultToken, error) {
+	client.SetToken(token)
+	request := client.NewRequest("POST", path.Join("/v1", "auth", "token", "renew-self"))
+	v.addVaultNamespaceToRequest(request)
+
+	resp, err := client.RawRequest(request)
+	if err != nil {
+		return vaultToken{}, fmt.Errorf("error renewing Vault token: %s", err.Error())
+	}
+
+	defer resp.Body.Close()
+	return decodeVaultToken(resp)
+}
+
+// kubernetesAuthJWT returns the ServiceAccount token to log in to Vault with.
+// It is read from the referenced Secret or, if the issuer may use ambient
+// credentials, from the file at tokenPath.
+func (v *Vault) kubernetesAuthJWT(kubernetesAuth *v1.VaultKubernetesAuth) (string, error) {
+	if kubernetesAuth.TokenPath != "" {
+		if !v.canUseAmbientCredentials {
+			return "", errors.New("tokenPath cannot be used as ambient credentials are disabled for this issuer")
+		}
+		jwt, err := ioutil.ReadFile(kubernetesAuth.TokenPath)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(jwt)), nil
+	}
+
 	secret, err := v.secretsLister.Secrets(v.namespace).Get(kubernetesAuth.SecretRef.Name)
 	if err != nil {
 		return "", err
@@ -297,7 +461,14 @@ func (v *Vault) requestTokenWithKubernetesAuth(client Client, kubernetesAuth *v1
 		return "", fmt.Errorf("no data for %q in secret '%s/%s'", key, v.namespace, kubernetesAuth.SecretRef.Name)
 	}
 
-	jwt := string(keyBytes)
+	return string(keyBytes), nil
+}
+
+func (v *Vault) requestTokenWithKubernetesAuth(client Client, kubernetesAuth *v1.VaultKubernetesAuth) (vaultToken, error) {
+	jwt, err := v.kubernetesAuthJWT(kubernetesAuth)
+	if err != nil {
+		return vaultToken{}, err
+	}
 
 	parameters := map[string]string{
 		"role": kubernetesAuth.Role,
@@ -313,29 +484,39 @@ func (v *Vault) requestTokenWithKubernetesAuth(client Client, kubernetesAuth *v1
 	request := client.NewRequest("POST", url)
 	err = request.SetJSONBody(parameters)
 	if err != nil {
-		return "", fmt.Errorf("error encoding Vault parameters: %s", err.Error())
+		return vaultToken{}, fmt.Errorf("error encoding Vault parameters: %s", err.Error())
 	}
 
 	v.addVaultNamespaceToRequest(request)
 
 	resp, err := client.RawRequest(request)
 	if err != nil {
-		return "", fmt.Errorf("error calling Vault server: %s", err.Error())
+		return vaultToken{}, fmt.Errorf("error calling Vault server: %s", err.Error())
 	}
 
 	defer resp.Body.Close()
+	return decodeVaultToken(resp)
+}
+
+// decodeVaultToken decodes the token and its lease from the response to a
+// login or token renewal request.
+func decodeVaultToken(resp *vault.Response) (vaultToken, error) {
 	vaultResult := vault.Secret{}
-	err = resp.DecodeJSON(&vaultResult)
+	err := resp.DecodeJSON(&vaultResult)
 	if err != nil {
-		return "", fmt.Errorf("unable to decode JSON payload: %s", err.Error())
+		return vaultToken{}, fmt.Errorf("unable to decode JSON payload: %s", err.Error())
 	}
 
-	token, err := vaultResult.TokenID()
+	id, err := vaultResult.TokenID()
 	if err != nil {
-		return "", fmt.Errorf("unable to read token: %s", err.Error())
+		return vaultToken{}, fmt.Errorf("unable to read token: %s", err.Error())
 	}
+	// the lease is only used to decide how long the token is cached for, so
+	// a token whose lease cannot be read is used but not cached
+	ttl, _ := vaultResult.TokenTTL()
+	renewable, _ := vaultResult.TokenIsRenewable()
 
-	return token, nil
+	return vaultToken{id: id, ttl: ttl, renewable: renewable}, nil
 }
 
 func (v *Vault) Sys() *vault.Sys {
@@ -29,6 +29,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -37,6 +38,7 @@ import (
 	"github.com/hashicorp/vault/sdk/helper/certutil"
 	"github.com/hashicorp/vault/sdk/helper/jsonutil"
 	corev1 "k8s.io/api/core/v1"
+	fakeclock "k8s.io/utils/clock/testing"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
@@ -856,9 +858,37 @@ func TestTokenRef(t *testing.T) {
 }
 
 type testNewConfigT struct {
-	expectedErr error
-	issuer      *cmapi.Issuer
-	checkFunc   func(cfg *vault.Config) error
+	expectedErr     error
+	issuer          *cmapi.Issuer
+	backendCABundle []byte
+	checkFunc       func(cfg *vault.Config) error
+}
+
+// checkRootCAs returns a check function that ensures the root CAs configured
+// on the Vault client are exactly those in the given PEM bundle.
+func checkRootCAs(pemBundle string) func(cfg *vault.Config) error {
+	return func(cfg *vault.Config) error {
+		expCA := x509.NewCertPool()
+		expCA.AppendCertsFromPEM([]byte(pemBundle))
+		rootCAs := cfg.HttpClient.Transport.(*http.Transport).TLSClientConfig.RootCAs
+		if rootCAs == nil {
+			return errors.New("expected root CAs to be set in config, but got none")
+		}
+		subs := rootCAs.Subjects()
+
+		err := fmt.Errorf("got unexpected root CAs in config, exp=%s got=%s",
+			expCA.Subjects(), subs)
+		if len(subs) != len(expCA.Subjects()) {
+			return err
+		}
+		for i := range subs {
+			if !bytes.Equal(subs[i], expCA.Subjects()[i]) {
+				return err
+			}
+		}
+
+		return nil
+	}
 }
 
 func TestNewConfig(t *testing.T) {
@@ -907,14 +937,47 @@ func TestNewConfig(t *testing.T) {
 				return nil
 			},
 		},
+
+		"the backend CA bundle should be used if the issuer has no CA bundle": {
+			issuer: gen.Issuer("vault-issuer",
+				gen.SetIssuerVault(cmapi.VaultIssuer{
+					CABundle: nil,
+				}),
+			),
+			backendCABundle: []byte(testRootCa),
+			expectedErr:     nil,
+			checkFunc:       checkRootCAs(testRootCa),
+		},
+
+		"a bad backend CA bundle should error": {
+			issuer: gen.Issuer("vault-issuer",
+				gen.SetIssuerVault(cmapi.VaultIssuer{
+					CABundle: nil,
+				}),
+			),
+			backendCABundle: []byte("a bad cert bundle"),
+			expectedErr:     errors.New("error loading Vault CA bundle"),
+		},
+
+		"the issuer CA bundle should take precedence over the backend CA bundle": {
+			issuer: gen.Issuer("vault-issuer",
+				gen.SetIssuerVault(cmapi.VaultIssuer{
+					CABundle: []byte(testLeafCertificate),
+				}),
+			),
+			backendCABundle: []byte(testRootCa),
+			expectedErr:     nil,
+			checkFunc:       checkRootCAs(testLeafCertificate),
+		},
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
 			v := &Vault{
-				namespace:     "test-namespace",
-				secretsLister: nil,
-				issuer:        test.issuer,
+				namespace:       "test-namespace",
+				secretsLister:   nil,
+				issuer:          test.issuer,
+				backendCABundle: test.backendCABundle,
 			}
 
 			cfg, err := v.newConfig()
@@ -1061,3 +1124,270 @@ func TestRequestTokenWithAppRoleRef(t *testing.T) {
 		})
 	}
 }
+
+// sequenceRawRequest returns a RawRequest function that returns each of the
+// given responses in turn, failing the test if it is called too many times.
+func sequenceRawRequest(t *testing.T, calls *int, responses ...func() (*vault.Response, error)) func(*vault.Request) (*vault.Response, error) {
+	return func(*vault.Request) (*vault.Response, error) {
+		if *calls >= len(responses) {
+			t.Fatalf("unexpected RawRequest call %d", *calls+1)
+		}
+		fn := responses[*calls]
+		*calls++
+		return fn()
+	}
+}
+
+func jsonResponse(status int, body string) func() (*vault.Response, error) {
+	return func() (*vault.Response, error) {
+		resp := &vault.Response{
+			Response: &http.Response{
+				StatusCode: status,
+				Body:       ioutil.NopCloser(strings.NewReader(body)),
+			},
+		}
+		if status >= 400 {
+			return resp, fmt.Errorf("Code: %d", status)
+		}
+		return resp, nil
+	}
+}
+
+func loginResponse(token string) func() (*vault.Response, error) {
+	return jsonResponse(http.StatusOK, fmt.Sprintf(`{"auth":{"client_token":%q,"lease_duration":3600,"renewable":true}}`, token))
+}
+
+func kubernetesAuthIssuer(auth *cmapi.VaultKubernetesAuth) *cmapi.Issuer {
+	return gen.Issuer("vault-issuer",
+		gen.SetIssuerVault(cmapi.VaultIssuer{
+			Server: "https://vault.example.com",
+			Path:   "pki/sign/example",
+			Auth: cmapi.VaultAuth{
+				Kubernetes: auth,
+			},
+		}),
+	)
+}
+
+func TestKubernetesAuthTokenPath(t *testing.T) {
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	if err := ioutil.WriteFile(tokenPath, []byte("projected-jwt\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	issuer := kubernetesAuthIssuer(&cmapi.VaultKubernetesAuth{
+		Role:      "kube-vault-role",
+		TokenPath: tokenPath,
+	})
+
+	t.Run("the token is read from the file if ambient credentials are allowed", func(t *testing.T) {
+		var calls int
+		client := vaultfake.NewFakeClient()
+		client.RawRequestFn = sequenceRawRequest(t, &calls, loginResponse("my-token"))
+		v := &Vault{
+			namespace:                "test-namespace",
+			issuer:                   issuer,
+			canUseAmbientCredentials: true,
+		}
+
+		if err := v.setToken(client); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if client.Token() != "my-token" {
+			t.Errorf("expected token %q but got %q", "my-token", client.Token())
+		}
+		jwt, err := v.kubernetesAuthJWT(issuer.Spec.Vault.Auth.Kubernetes)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if jwt != "projected-jwt" {
+			t.Errorf("expected the projected token to be used to log in but got %q", jwt)
+		}
+	})
+
+	t.Run("the file is not read if ambient credentials are not allowed", func(t *testing.T) {
+		client := vaultfake.NewFakeClient()
+		v := &Vault{
+			namespace: "test-namespace",
+			issuer:    issuer,
+		}
+
+		err := v.setToken(client)
+		expectedErr := fmt.Sprintf("error reading Kubernetes service account token from %s: tokenPath cannot be used as ambient credentials are disabled for this issuer", tokenPath)
+		if err == nil || err.Error() != expectedErr {
+			t.Errorf("expected error %q but got: %v", expectedErr, err)
+		}
+		if client.Token() != "" {
+			t.Errorf("expected no token to be set but got %q", client.Token())
+		}
+	})
+}
+
+func TestKubernetesAuthTokenCache(t *testing.T) {
+	kubeAuthSecret := &corev1.Secret{
+		Data: map[string][]byte{
+			"my-kube-key": []byte("my-secret-kube-token"),
+		},
+	}
+	issuer := kubernetesAuthIssuer(&cmapi.VaultKubernetesAuth{
+		Role: "kube-vault-role",
+		SecretRef: cmmeta.SecretKeySelector{
+			LocalObjectReference: cmmeta.LocalObjectReference{
+				Name: "secret-ref-name",
+			},
+			Key: "my-kube-key",
+		},
+	})
+
+	fakeClock := fakeclock.NewFakeClock(time.Now())
+	cache := newTokenCache(fakeClock)
+	var calls int
+	responses := []func() (*vault.Response, error){
+		loginResponse("token-1"),
+		// renew-self
+		loginResponse("token-1"),
+		// renew-self fails, so Vault is logged in to again
+		jsonResponse(http.StatusForbidden, `{"errors":["permission denied"]}`),
+		loginResponse("token-2"),
+	}
+	rawRequest := sequenceRawRequest(t, &calls, responses...)
+
+	expectToken := func(t *testing.T, expectedToken string, expectedCalls int) {
+		t.Helper()
+		client := vaultfake.NewFakeClient()
+		client.RawRequestFn = rawRequest
+		v := &Vault{
+			namespace: "test-namespace",
+			secretsLister: listers.FakeSecretListerFrom(listers.NewFakeSecretLister(),
+				listers.SetFakeSecretNamespaceListerGet(kubeAuthSecret, nil),
+			),
+			issuer:     issuer,
+			tokenCache: cache,
+		}
+		if err := v.setToken(client); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if client.Token() != expectedToken {
+			t.Errorf("expected token %q but got %q", expectedToken, client.Token())
+		}
+		if calls != expectedCalls {
+			t.Errorf("expected %d requests to Vault but got %d", expectedCalls, calls)
+		}
+	}
+
+	// the first client logs in, and the second reuses its token
+	expectToken(t, "token-1", 1)
+	expectToken(t, "token-1", 1)
+
+	// the token is renewed once two thirds of its lease has passed
+	fakeClock.Step(time.Minute * 45)
+	expectToken(t, "token-1", 2)
+	expectToken(t, "token-1", 2)
+
+	// if the token cannot be renewed, Vault is logged in to again
+	fakeClock.Step(time.Minute * 45)
+	expectToken(t, "token-2", 4)
+	expectToken(t, "token-2", 4)
+}
+
+func TestSignReloginOnPermissionDenied(t *testing.T) {
+	privatekey := generateRSAPrivateKey(t)
+	csrPEM := generateCSR(t, privatekey)
+	bundleData, err := bundlePEM(testIntermediateCa)
+	if err != nil {
+		t.Fatalf("failed to encode bundle for testing: %s", err)
+	}
+
+	kubeAuthSecret := &corev1.Secret{
+		Data: map[string][]byte{
+			"my-kube-key": []byte("my-secret-kube-token"),
+		},
+	}
+	kubeAuth := &cmapi.VaultKubernetesAuth{
+		Role: "kube-vault-role",
+		SecretRef: cmmeta.SecretKeySelector{
+			LocalObjectReference: cmmeta.LocalObjectReference{
+				Name: "secret-ref-name",
+			},
+			Key: "my-kube-key",
+		},
+	}
+
+	tests := map[string]struct {
+		issuer        *cmapi.Issuer
+		responses     []func() (*vault.Response, error)
+		expectedToken string
+		expectErr     bool
+	}{
+		"a denied request using kubernetes auth logs in again and is retried": {
+			issuer: kubernetesAuthIssuer(kubeAuth),
+			responses: []func() (*vault.Response, error){
+				jsonResponse(http.StatusForbidden, `{"errors":["permission denied"]}`),
+				loginResponse("new-token"),
+				jsonResponse(http.StatusOK, string(bundleData)),
+			},
+			expectedToken: "new-token",
+		},
+		"a request that is denied again after logging in fails": {
+			issuer: kubernetesAuthIssuer(kubeAuth),
+			responses: []func() (*vault.Response, error){
+				jsonResponse(http.StatusForbidden, `{"errors":["permission denied"]}`),
+				loginResponse("new-token"),
+				jsonResponse(http.StatusForbidden, `{"errors":["permission denied"]}`),
+			},
+			expectedToken: "new-token",
+			expectErr:     true,
+		},
+		"a denied request using token auth is not retried": {
+			issuer: gen.Issuer("vault-issuer",
+				gen.SetIssuerVault(cmapi.VaultIssuer{
+					Path: "pki/sign/example",
+					Auth: cmapi.VaultAuth{
+						TokenSecretRef: &cmmeta.SecretKeySelector{
+							LocalObjectReference: cmmeta.LocalObjectReference{
+								Name: "secret-ref-name",
+							},
+						},
+					},
+				}),
+			),
+			responses: []func() (*vault.Response, error){
+				jsonResponse(http.StatusForbidden, `{"errors":["permission denied"]}`),
+			},
+			expectedToken: "old-token",
+			expectErr:     true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var calls int
+			client := vaultfake.NewFakeClient()
+			client.RawRequestFn = sequenceRawRequest(t, &calls, test.responses...)
+			client.SetToken("old-token")
+			cache := newTokenCache(fakeclock.NewFakeClock(time.Now()))
+			v := &Vault{
+				namespace: "test-namespace",
+				secretsLister: listers.FakeSecretListerFrom(listers.NewFakeSecretLister(),
+					listers.SetFakeSecretNamespaceListerGet(kubeAuthSecret, nil),
+				),
+				issuer:     test.issuer,
+				tokenCache: cache,
+				client:     client,
+			}
+
+			cert, _, err := v.Sign(csrPEM, time.Minute)
+			if test.expectErr != (err != nil) {
+				t.Fatalf("expected error=%t but got: %v", test.expectErr, err)
+			}
+			if !test.expectErr && len(cert) == 0 {
+				t.Errorf("expected a certificate to be returned")
+			}
+			if calls != len(test.responses) {
+				t.Errorf("expected %d requests to Vault but got %d", len(test.responses), calls)
+			}
+			if client.Token() != test.expectedToken {
+				t.Errorf("expected token %q but got %q", test.expectedToken, client.Token())
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package issuancestatus implements an optional gRPC server exposed by the
+// cert-manager controller, which answers queries about the issuance status of
+// Certificates from the controller's informer caches.
+package issuancestatus
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	cminformers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
+	cmlisters "github.com/jetstack/cert-manager/pkg/client/listers/certmanager/v1"
+	"github.com/jetstack/cert-manager/pkg/controller/certificates"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+	"github.com/jetstack/cert-manager/pkg/util/predicate"
+	servertls "github.com/jetstack/cert-manager/pkg/webhook/server/tls"
+)
+
+//go:generate protoc --go_out=plugins=grpc,paths=source_relative:. issuancestatus.proto
+
+// ServiceName is the fully qualified name of the CertificateStatus gRPC
+// service.
+const ServiceName = "certmanager.issuancestatus.v1.CertificateStatus"
+
+const serverShutdownTimeout = 5 * time.Second
+
+// Server implements the CertificateStatus service using the informer caches
+// of the controller, and the standard gRPC health service. The health of the
+// CertificateStatus service is reported as NOT_SERVING until the informer
+// caches have synced.
+type Server struct {
+	log logr.Logger
+
+	certificateLister        cmlisters.CertificateLister
+	certificateRequestLister cmlisters.CertificateRequestLister
+	mustSync                 []cache.InformerSynced
+
+	health *health.Server
+}
+
+// NewServer returns a Server reading Certificates and CertificateRequests
+// from informers obtained from the given factory. The factory must be started
+// for the server to answer queries.
+func NewServer(log logr.Logger, factory cminformers.SharedInformerFactory) *Server {
+	certificateInformer := factory.Certmanager().V1().Certificates()
+	certificateRequestInformer := factory.Certmanager().V1().CertificateRequests()
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(ServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+
+	return &Server{
+		log:                      log.WithName("issuancestatus"),
+		certificateLister:        certificateInformer.Lister(),
+		certificateRequestLister: certificateRequestInformer.Lister(),
+		mustSync: []cache.InformerSynced{
+			certificateInformer.Informer().HasSynced,
+			certificateRequestInformer.Informer().HasSynced,
+		},
+		health: healthServer,
+	}
+}
+
+// GetCertificateStatus returns the issuance status of the requested
+// Certificate.
+func (s *Server) GetCertificateStatus(ctx context.Context, req *CertificateStatusRequest) (*CertificateStatusResponse, error) {
+	if req.Namespace == "" || req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "namespace and name must be specified")
+	}
+	if !s.hasSynced() {
+		return nil, status.Error(codes.Unavailable, "informer caches have not synced yet")
+	}
+
+	crt, err := s.certificateLister.Certificates(req.Namespace).Get(req.Name)
+	if apierrors.IsNotFound(err) {
+		return nil, status.Errorf(codes.NotFound, "Certificate %s/%s not found", req.Namespace, req.Name)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &CertificateStatusResponse{
+		Ready: string(cmmeta.ConditionUnknown),
+	}
+	if cond := apiutil.GetCertificateCondition(crt, cmapi.CertificateConditionReady); cond != nil {
+		resp.Ready, resp.Reason, resp.Message = string(cond.Status), cond.Reason, cond.Message
+	}
+	if crt.Status.NotAfter != nil {
+		resp.NotAfter, err = ptypes.TimestampProto(crt.Status.NotAfter.Time)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	cr, err := s.currentCertificateRequest(crt)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if cr != nil {
+		resp.CertificateRequest = &CertificateRequestStatus{
+			Name:  cr.Name,
+			Ready: string(cmmeta.ConditionUnknown),
+		}
+		if cond := apiutil.GetCertificateRequestCondition(cr, cmapi.CertificateRequestConditionReady); cond != nil {
+			resp.CertificateRequest.Ready = string(cond.Status)
+			resp.CertificateRequest.Reason = cond.Reason
+			resp.CertificateRequest.Message = cond.Message
+		}
+	}
+
+	return resp, nil
+}
+
+// currentCertificateRequest returns the CertificateRequest owned by the given
+// Certificate for its next revision, which is the one created for the current
+// or most recent issuance. It returns nil if there is no such request.
+func (s *Server) currentCertificateRequest(crt *cmapi.Certificate) (*cmapi.CertificateRequest, error) {
+	// CertificateRequest revisions begin from 1.
+	nextRevision := 1
+	if crt.Status.Revision != nil {
+		nextRevision = *crt.Status.Revision + 1
+	}
+
+	reqs, err := certificates.ListCertificateRequestsMatchingPredicates(s.certificateRequestLister.CertificateRequests(crt.Namespace),
+		labels.Everything(),
+		predicate.ResourceOwnedBy(crt),
+		predicate.CertificateRequestRevision(nextRevision),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(reqs) {
+	case 0:
+		return nil, nil
+	case 1:
+		return reqs[0], nil
+	default:
+		return nil, fmt.Errorf("found multiple CertificateRequests with revision %d owned by Certificate %s/%s", nextRevision, crt.Namespace, crt.Name)
+	}
+}
+
+func (s *Server) hasSynced() bool {
+	for _, fn := range s.mustSync {
+		if !fn() {
+			return false
+		}
+	}
+	return true
+}
+
+// Start starts a gRPC server serving the CertificateStatus and health services
+// over TLS on the given address, using the certificate provided by source. If
+// the address does not specify a host, only the loopback interface is
+// listened on. The CertificateStatus service is reported as healthy once the
+// informer caches have synced, or never if stopCh is closed first.
+func (s *Server) Start(listenAddress string, source servertls.CertificateSource, stopCh <-chan struct{}) (*grpc.Server, error) {
+	ln, err := listen(listenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		if err := source.Run(stopCh); err != nil {
+			s.log.Error(err, "error running the TLS certificate source of the gRPC server")
+		}
+	}()
+	creds := credentials.NewTLS(&tls.Config{
+		GetCertificate: source.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+	})
+
+	return s.serve(ln, creds, stopCh), nil
+}
+
+// listen listens on the given address, or on the loopback interface if the
+// address does not specify a host.
+func listen(listenAddress string) (net.Listener, error) {
+	host, port, err := net.SplitHostPort(listenAddress)
+	if err != nil {
+		return nil, err
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	return net.Listen("tcp", net.JoinHostPort(host, port))
+}
+
+func (s *Server) serve(ln net.Listener, creds credentials.TransportCredentials, stopCh <-chan struct{}) *grpc.Server {
+	server := grpc.NewServer(grpc.Creds(creds))
+	RegisterCertificateStatusServer(server, s)
+	healthpb.RegisterHealthServer(server, s.health)
+
+	go func() {
+		if cache.WaitForCacheSync(stopCh, s.mustSync...) {
+			s.health.SetServingStatus(ServiceName, healthpb.HealthCheckResponse_SERVING)
+		}
+	}()
+
+	go func() {
+		log := s.log.WithValues("address", ln.Addr())
+		log.V(logf.InfoLevel).Info("listening for gRPC connections")
+
+		if err := server.Serve(ln); err != nil {
+			log.Error(err, "error running gRPC server")
+		}
+	}()
+
+	return server
+}
+
+// Shutdown gracefully stops the given gRPC server, forcibly closing any
+// connections that are still open after a timeout.
+func (s *Server) Shutdown(server *grpc.Server) {
+	s.health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		s.log.V(logf.InfoLevel).Info("gRPC server gracefully stopped")
+	case <-time.After(serverShutdownTimeout):
+		server.Stop()
+		s.log.V(logf.InfoLevel).Info("gRPC server did not stop gracefully in time, closed all connections")
+	}
+}
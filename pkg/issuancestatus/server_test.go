@@ -0,0 +1,278 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package issuancestatus
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	"github.com/jetstack/cert-manager/pkg/client/clientset/versioned/fake"
+	cminformers "github.com/jetstack/cert-manager/pkg/client/informers/externalversions"
+	logtesting "github.com/jetstack/cert-manager/pkg/logs/testing"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+func TestGetCertificateStatus(t *testing.T) {
+	notAfter := metav1.NewTime(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC))
+	notAfterProto, err := ptypes.TimestampProto(notAfter.Time)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readyCertificate := gen.Certificate("ready",
+		gen.SetCertificateNamespace(gen.DefaultTestNamespace),
+		gen.SetCertificateUID(types.UID("ready")),
+		gen.SetCertificateRevision(1),
+		gen.SetCertificateNotAfter(notAfter),
+		gen.SetCertificateStatusCondition(cmapi.CertificateCondition{
+			Type:    cmapi.CertificateConditionReady,
+			Status:  cmmeta.ConditionTrue,
+			Reason:  "Ready",
+			Message: "Certificate is up to date and has not expired",
+		}),
+	)
+	issuingCertificate := gen.Certificate("issuing",
+		gen.SetCertificateNamespace(gen.DefaultTestNamespace),
+		gen.SetCertificateUID(types.UID("issuing")),
+	)
+
+	tests := map[string]struct {
+		existing []runtime.Object
+		request  *CertificateStatusRequest
+
+		expectedResponse *CertificateStatusResponse
+		expectedCode     codes.Code
+	}{
+		"return InvalidArgument if the name is not specified": {
+			request:      &CertificateStatusRequest{Namespace: gen.DefaultTestNamespace},
+			expectedCode: codes.InvalidArgument,
+		},
+		"return NotFound if the Certificate does not exist": {
+			request:      &CertificateStatusRequest{Namespace: gen.DefaultTestNamespace, Name: "ready"},
+			expectedCode: codes.NotFound,
+		},
+		"return NotFound if the Certificate only exists in another namespace": {
+			existing:     []runtime.Object{readyCertificate},
+			request:      &CertificateStatusRequest{Namespace: "other", Name: "ready"},
+			expectedCode: codes.NotFound,
+		},
+		"return the Ready condition and notAfter of an issued Certificate": {
+			existing: []runtime.Object{
+				readyCertificate,
+				// the request for the already issued revision is not current
+				gen.CertificateRequest("ready-1",
+					gen.SetCertificateRequestNamespace(gen.DefaultTestNamespace),
+					gen.AddCertificateRequestOwnerReferences(gen.CertificateRef("ready", "ready")),
+					gen.SetCertificateRequestRevision("1"),
+				),
+			},
+			request: &CertificateStatusRequest{Namespace: gen.DefaultTestNamespace, Name: "ready"},
+			expectedResponse: &CertificateStatusResponse{
+				Ready:    string(cmmeta.ConditionTrue),
+				Reason:   "Ready",
+				Message:  "Certificate is up to date and has not expired",
+				NotAfter: notAfterProto,
+			},
+		},
+		"return the current CertificateRequest of a Certificate being issued": {
+			existing: []runtime.Object{
+				issuingCertificate,
+				gen.CertificateRequest("issuing-1",
+					gen.SetCertificateRequestNamespace(gen.DefaultTestNamespace),
+					gen.AddCertificateRequestOwnerReferences(gen.CertificateRef("issuing", "issuing")),
+					gen.SetCertificateRequestRevision("1"),
+					gen.SetCertificateRequestStatusCondition(cmapi.CertificateRequestCondition{
+						Type:    cmapi.CertificateRequestConditionReady,
+						Status:  cmmeta.ConditionFalse,
+						Reason:  cmapi.CertificateRequestReasonPending,
+						Message: "Waiting on certificate issuance",
+					}),
+				),
+				// requests owned by other Certificates are ignored
+				gen.CertificateRequest("other-1",
+					gen.SetCertificateRequestNamespace(gen.DefaultTestNamespace),
+					gen.AddCertificateRequestOwnerReferences(gen.CertificateRef("other", "other")),
+					gen.SetCertificateRequestRevision("1"),
+				),
+			},
+			request: &CertificateStatusRequest{Namespace: gen.DefaultTestNamespace, Name: "issuing"},
+			expectedResponse: &CertificateStatusResponse{
+				Ready: string(cmmeta.ConditionUnknown),
+				CertificateRequest: &CertificateRequestStatus{
+					Name:    "issuing-1",
+					Ready:   string(cmmeta.ConditionFalse),
+					Reason:  cmapi.CertificateRequestReasonPending,
+					Message: "Waiting on certificate issuance",
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client, _ := newTestClient(t, test.existing...)
+
+			resp, err := client.GetCertificateStatus(context.Background(), test.request)
+			if code := status.Code(err); code != test.expectedCode {
+				t.Fatalf("expected code %s but got %s: %v", test.expectedCode, code, err)
+			}
+			if !proto.Equal(test.expectedResponse, resp) {
+				t.Errorf("unexpected response, exp=%+v got=%+v", test.expectedResponse, resp)
+			}
+		})
+	}
+}
+
+func TestGetCertificateStatusBeforeCachesSynced(t *testing.T) {
+	factory := cminformers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	server := NewServer(logtesting.TestLogger{T: t}, factory)
+
+	_, err := server.GetCertificateStatus(context.Background(), &CertificateStatusRequest{Namespace: gen.DefaultTestNamespace, Name: "test"})
+	if code := status.Code(err); code != codes.Unavailable {
+		t.Errorf("expected code %s but got %s: %v", codes.Unavailable, code, err)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	_, conn := newTestClient(t)
+
+	// the service is reported as healthy asynchronously once the caches
+	// have synced
+	healthClient := healthpb.NewHealthClient(conn)
+	var resp *healthpb.HealthCheckResponse
+	for i := 0; i < 50; i++ {
+		var err error
+		resp, err = healthClient.Check(context.Background(), &healthpb.HealthCheckRequest{Service: ServiceName})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Status == healthpb.HealthCheckResponse_SERVING {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Errorf("expected service to be %s but got %s", healthpb.HealthCheckResponse_SERVING, resp.Status)
+}
+
+func TestRejectsInsecureConnections(t *testing.T) {
+	_, conn := newTestClient(t)
+
+	insecureConn, err := grpc.Dial(conn.Target(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer insecureConn.Close()
+
+	_, err = NewCertificateStatusClient(insecureConn).GetCertificateStatus(context.Background(), &CertificateStatusRequest{Namespace: gen.DefaultTestNamespace, Name: "test"})
+	if code := status.Code(err); code != codes.Unavailable {
+		t.Errorf("expected code %s but got %s: %v", codes.Unavailable, code, err)
+	}
+}
+
+func TestListenOnLoopbackByDefault(t *testing.T) {
+	ln, err := listen(":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	if ip := ln.Addr().(*net.TCPAddr).IP; !ip.IsLoopback() {
+		t.Errorf("expected to listen on the loopback interface but listened on %s", ip)
+	}
+}
+
+// newTestClient starts a Server backed by informers containing the given
+// objects and returns a client connected to it. The server is stopped when
+// the test finishes.
+func newTestClient(t *testing.T, objects ...runtime.Object) (CertificateStatusClient, *grpc.ClientConn) {
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+
+	factory := cminformers.NewSharedInformerFactory(fake.NewSimpleClientset(objects...), 0)
+	server := NewServer(logtesting.TestLogger{T: t}, factory)
+	factory.Start(stopCh)
+	for informer, synced := range factory.WaitForCacheSync(stopCh) {
+		if !synced {
+			t.Fatalf("informer %v did not sync", informer)
+		}
+	}
+
+	cert, pool := newTestCertificate(t)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grpcServer := server.serve(ln, credentials.NewServerTLSFromCert(cert), stopCh)
+	t.Cleanup(func() { server.Shutdown(grpcServer) })
+
+	conn, err := grpc.Dial(ln.Addr().String(), grpc.WithTransportCredentials(credentials.NewClientTLSFromCert(pool, "")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return NewCertificateStatusClient(conn), conn
+}
+
+// newTestCertificate returns a self signed serving certificate for 127.0.0.1,
+// and a pool containing it to verify the server with.
+func newTestCertificate(t *testing.T) (*tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, pool
+}
@@ -19,6 +19,7 @@ package acme
 import (
 	"context"
 	"crypto"
+	"crypto/x509"
 	"fmt"
 
 	core "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -57,6 +58,10 @@ type Acme struct {
 
 	// metrics is used to create instrumented ACME clients
 	metrics *metrics.Metrics
+
+	// backendRootCAs, if set, are the only root CAs trusted when connecting
+	// to the ACME server.
+	backendRootCAs *x509.CertPool
 }
 
 // New returns a new ACME issuer interface for the given issuer.
@@ -67,15 +72,24 @@ func New(ctx *controller.Context, issuer v1.GenericIssuer) (issuer.Interface, er
 
 	secretsLister := ctx.KubeSharedInformerFactory.Core().V1().Secrets().Lister()
 
+	var backendRootCAs *x509.CertPool
+	if len(ctx.IssuerOptions.IssuerBackendCABundle) > 0 {
+		backendRootCAs = x509.NewCertPool()
+		if !backendRootCAs.AppendCertsFromPEM(ctx.IssuerOptions.IssuerBackendCABundle) {
+			return nil, fmt.Errorf("error loading issuer backend CA bundle")
+		}
+	}
+
 	a := &Acme{
 		issuer:                   issuer,
 		keyFromSecret:            newKeyFromSecret(secretsLister),
 		clientBuilder:            accounts.NewClient,
 		secretsClient:            ctx.Client.CoreV1(),
 		recorder:                 ctx.Recorder,
-		clusterResourceNamespace: ctx.IssuerOptions.ClusterResourceNamespace,
+		clusterResourceNamespace: ctx.IssuerOptions.ResourceNamespace(issuer),
 		accountRegistry:          ctx.ACMEOptions.AccountRegistry,
 		metrics:                  ctx.Metrics,
+		backendRootCAs:           backendRootCAs,
 	}
 
 	return a, nil
@@ -19,6 +19,12 @@ limitations under the License.
 //    https://github.com/joohoi/acme-dns
 // This code was adapted from lego:
 // 	  https://github.com/xenolf/lego
+//
+// Accounts are not registered by this provider. They must be created ahead of
+// time using the acme-dns /register endpoint (for example with the
+// goacmedns-register tool) and stored in a Secret as a JSON object mapping
+// each domain to its account credentials, with a CNAME record pointing
+// _acme-challenge.<domain> at the account's fulldomain.
 package acmedns
 
 import (
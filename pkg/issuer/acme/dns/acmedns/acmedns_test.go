@@ -17,6 +17,9 @@ limitations under the License.
 package acmedns
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
@@ -66,6 +69,118 @@ func TestNoValidJson(t *testing.T) {
 	assert.Error(t, err, "Expected error constructing DNSProvider from invalid JSON")
 }
 
+// fakeAcmeDNS is a fake acme-dns server which records the requests made to
+// its /update endpoint.
+type fakeAcmeDNS struct {
+	t *testing.T
+
+	status  int
+	updates []fakeAcmeDNSUpdate
+}
+
+type fakeAcmeDNSUpdate struct {
+	user      string
+	key       string
+	subdomain string
+	txt       string
+}
+
+func (f *fakeAcmeDNS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != "/update" {
+		f.t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+		f.t.Errorf("expected Content-Type application/json but got %q", ct)
+	}
+
+	var body struct {
+		SubDomain string `json:"subdomain"`
+		Txt       string `json:"txt"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		f.t.Errorf("failed to decode update request: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	f.updates = append(f.updates, fakeAcmeDNSUpdate{
+		user:      r.Header.Get("X-Api-User"),
+		key:       r.Header.Get("X-Api-Key"),
+		subdomain: body.SubDomain,
+		txt:       body.Txt,
+	})
+
+	w.WriteHeader(f.status)
+	w.Write([]byte(`{"txt": "` + body.Txt + `"}`))
+}
+
+func TestPresent(t *testing.T) {
+	accountJson := []byte(`{
+        "example.com": {
+            "fulldomain": "d420c923-bbd7-4056-ab64-c3ca54c9b3cf.auth.example.org",
+            "password": "htB9mR9DYgcu9bX_afHF62erXaH2TS7bg9KW3F7Z",
+            "subdomain": "d420c923-bbd7-4056-ab64-c3ca54c9b3cf",
+            "username": "c36f50e8-4632-44f0-83fe-e070fef28a10"
+        }
+    }`)
+	value := "LG3tptA6W7T1vw4ujbmDxH2lLu6r8TUIqLZD3pzPmgE"
+
+	tests := map[string]struct {
+		domain      string
+		status      int
+		wantUpdates []fakeAcmeDNSUpdate
+		wantErr     bool
+	}{
+		"updates the TXT record using the account credentials for the domain": {
+			domain: "example.com",
+			status: http.StatusOK,
+			wantUpdates: []fakeAcmeDNSUpdate{{
+				user:      "c36f50e8-4632-44f0-83fe-e070fef28a10",
+				key:       "htB9mR9DYgcu9bX_afHF62erXaH2TS7bg9KW3F7Z",
+				subdomain: "d420c923-bbd7-4056-ab64-c3ca54c9b3cf",
+				txt:       value,
+			}},
+		},
+		"returns an error if acme-dns rejects the update": {
+			domain: "example.com",
+			status: http.StatusUnauthorized,
+			wantUpdates: []fakeAcmeDNSUpdate{{
+				user:      "c36f50e8-4632-44f0-83fe-e070fef28a10",
+				key:       "htB9mR9DYgcu9bX_afHF62erXaH2TS7bg9KW3F7Z",
+				subdomain: "d420c923-bbd7-4056-ab64-c3ca54c9b3cf",
+				txt:       value,
+			}},
+			wantErr: true,
+		},
+		"returns an error without calling acme-dns if there is no account for the domain": {
+			domain:  "example.net",
+			status:  http.StatusOK,
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fake := &fakeAcmeDNS{t: t, status: test.status}
+			srv := httptest.NewServer(fake)
+			defer srv.Close()
+
+			provider, err := NewDNSProviderHostBytes(srv.URL, accountJson, util.RecursiveNameservers)
+			assert.NoError(t, err)
+
+			err = provider.Present(test.domain, "_acme-challenge."+test.domain+".", value)
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.wantUpdates, fake.updates)
+		})
+	}
+}
+
 func TestLiveAcmeDnsPresent(t *testing.T) {
 	if !acmednsLiveTest {
 		t.Skip("skipping live test")
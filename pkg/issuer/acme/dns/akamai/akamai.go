@@ -39,6 +39,9 @@ import (
 // DNSProvider is an implementation of the acme.ChallengeProvider interface
 type DNSProvider struct {
 	dns01Nameservers []string
+	// zoneName, if set, is used as the hosted domain instead of detecting it
+	// from each fqdn.
+	zoneName string
 	// serviceConsumerDomain as issued by Akamai Luna Control Center.
 	// The ServiceConsumerDomain is the base URL.
 	serviceConsumerDomain string
@@ -53,15 +56,21 @@ type DNSProvider struct {
 // NewDNSProvider returns a DNSProvider instance configured for Akamai.
 func NewDNSProvider(serviceConsumerDomain, clientToken, clientSecret, accessToken string, dns01Nameservers []string) (*DNSProvider, error) {
 	return &DNSProvider{
-		dns01Nameservers,
-		serviceConsumerDomain,
-		NewEdgeGridAuth(clientToken, clientSecret, accessToken),
-		http.DefaultTransport,
-		findHostedDomainByFqdn,
-		logf.Log.WithName("akamai-dns"),
+		dns01Nameservers:       dns01Nameservers,
+		serviceConsumerDomain:  serviceConsumerDomain,
+		auth:                   NewEdgeGridAuth(clientToken, clientSecret, accessToken),
+		transport:              http.DefaultTransport,
+		findHostedDomainByFqdn: findHostedDomainByFqdn,
+		log:                    logf.Log.WithName("akamai-dns"),
 	}, nil
 }
 
+// SetZoneName configures the provider to use the given zone instead of
+// detecting the zone of each fqdn.
+func (a *DNSProvider) SetZoneName(zoneName string) {
+	a.zoneName = zoneName
+}
+
 func findHostedDomainByFqdn(fqdn string, ns []string) (string, error) {
 	zone, err := util.FindZoneByFqdn(fqdn, ns)
 	if err != nil {
@@ -71,6 +80,21 @@ func findHostedDomainByFqdn(fqdn string, ns []string) (string, error) {
 	return util.UnFqdn(zone), nil
 }
 
+// hostedDomainForFqdn returns the configured zone name if one is set,
+// otherwise the hosted domain is detected from the fqdn.
+func (a *DNSProvider) hostedDomainForFqdn(fqdn string) (string, error) {
+	if a.zoneName == "" {
+		return a.findHostedDomainByFqdn(fqdn, a.dns01Nameservers)
+	}
+
+	zone, err := util.FindZoneByFqdnOrOverride(fqdn, a.zoneName, a.dns01Nameservers)
+	if err != nil {
+		return "", err
+	}
+
+	return util.UnFqdn(zone), nil
+}
+
 // Present creates a TXT record to fulfil the dns-01 challenge
 func (a *DNSProvider) Present(domain, fqdn, value string) error {
 	return a.setTxtRecord(fqdn, &dns01Record{value, 60})
@@ -87,7 +111,7 @@ type dns01Record struct {
 }
 
 func (a *DNSProvider) setTxtRecord(fqdn string, dns01Record *dns01Record) error {
-	hostedDomain, err := a.findHostedDomainByFqdn(fqdn, a.dns01Nameservers)
+	hostedDomain, err := a.hostedDomainForFqdn(fqdn)
 	if err != nil {
 		return errors.Wrapf(err, "failed to determine hosted domain for %q", fqdn)
 	}
@@ -19,6 +19,7 @@ package akamai
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"strings"
@@ -119,6 +120,27 @@ func TestPresent(t *testing.T) {
 	assert.EqualValues(t, expected, actual)
 }
 
+func TestPresentWithZoneName(t *testing.T) {
+	akamai, err := NewDNSProvider("akamai.example.com", "token", "secret", "access-token", util.RecursiveNameservers)
+	assert.NoError(t, err)
+	akamai.SetZoneName("example.com.")
+
+	var response []byte
+	mockTransport(t, akamai, "example.com", sampleZoneData, &response)
+	akamai.findHostedDomainByFqdn = func(fqdn string, _ []string) (string, error) {
+		return "", fmt.Errorf("zone detection should not be used when a zone name is set")
+	}
+
+	assert.NoError(t, akamai.Present("test.example.com", "_acme-challenge.test.example.com.", "dns01-key"))
+
+	var expected, actual map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(sampleZoneDataWithTxt), &expected))
+	assert.NoError(t, json.Unmarshal(response, &actual))
+	assert.EqualValues(t, expected, actual)
+
+	assert.Error(t, akamai.Present("test.example.org", "_acme-challenge.test.example.org.", "dns01-key"))
+}
+
 func TestCleanUp(t *testing.T) {
 	akamai, err := NewDNSProvider("akamai.example.com", "token", "secret", "access-token", util.RecursiveNameservers)
 	assert.NoError(t, err)
@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+// batchSolver is implemented by DNS providers that can present or clean up
+// multiple TXT records in the same zone with a single API call.
+type batchSolver interface {
+	solver
+	PresentBatch(records []util.TXTRecord) error
+	CleanUpBatch(records []util.TXTRecord) error
+}
+
+// recordBatcher groups operations on DNS01 records that use the same DNS
+// provider and zone and are started within window of each other, so that
+// they can be performed by a single provider API call.
+type recordBatcher struct {
+	window time.Duration
+
+	lock    sync.Mutex
+	pending map[string]*recordBatch
+}
+
+// recordBatch is a group of records whose operation has not been performed
+// yet. done is closed once it has been, after which err holds the result.
+type recordBatch struct {
+	records []util.TXTRecord
+	done    chan struct{}
+	err     error
+}
+
+func newRecordBatcher(window time.Duration) *recordBatcher {
+	return &recordBatcher{
+		window:  window,
+		pending: make(map[string]*recordBatch),
+	}
+}
+
+// add adds record to the pending batch for key. If there is no pending batch
+// a new one is started, which is performed by calling fn with all of its
+// records once the window has passed. add blocks until the batch containing
+// record has been performed and returns its result.
+func (b *recordBatcher) add(ctx context.Context, key string, record util.TXTRecord, fn func([]util.TXTRecord) error) error {
+	b.lock.Lock()
+	batch, ok := b.pending[key]
+	if !ok {
+		batch = &recordBatch{done: make(chan struct{})}
+		b.pending[key] = batch
+		time.AfterFunc(b.window, func() {
+			// no records can be added once the batch is no longer pending
+			b.lock.Lock()
+			delete(b.pending, key)
+			b.lock.Unlock()
+
+			batch.err = fn(batch.records)
+			close(batch.done)
+		})
+	}
+	batch.records = append(batch.records, record)
+	b.lock.Unlock()
+
+	select {
+	case <-batch.done:
+		return batch.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// callBatchProvider performs operation for the record of a challenge as part
+// of a batch of records of other challenges that use the same provider
+// configuration and zone. fn performs the operation for all records of the
+// batch in a single provider API call.
+func (s *Solver) callBatchProvider(ctx context.Context, operation string, issuer v1.GenericIssuer, providerConfig *cmacme.ACMEChallengeSolverDNS01, record util.TXTRecord, fn func([]util.TXTRecord) error) error {
+	zone, err := util.FindZoneByFqdnOrOverride(record.FQDN, providerConfig.ZoneName, s.DNS01Nameservers.Get())
+	if err != nil {
+		return err
+	}
+
+	// The provider configuration, including the Secrets it references, and
+	// the issuer determine the credentials that are used, so only records
+	// for which all of them are the same can be batched.
+	config, err := json.Marshal(providerConfig)
+	if err != nil {
+		return err
+	}
+	// ClusterIssuers have no namespace, so their keys never collide with
+	// those of Issuers.
+	key := fmt.Sprintf("%s/%s/%s/%x/%s", operation, issuer.GetObjectMeta().Namespace, issuer.GetObjectMeta().Name, sha256.Sum256(config), zone)
+
+	logf.FromContext(ctx).V(logf.DebugLevel).Info("adding DNS01 record to batch", "operation", operation, "zone", zone)
+
+	return s.batcher.add(ctx, key, record, func(records []util.TXTRecord) error {
+		logf.FromContext(ctx).V(logf.DebugLevel).Info("performing batched DNS01 provider API call", "operation", operation, "zone", zone, "records", len(records))
+		return s.callProvider(ctx, operation, func() error {
+			return fn(records)
+		})
+	})
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	"github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
+	"github.com/jetstack/cert-manager/test/unit/gen"
+)
+
+// TestCallBatchProviderGroupsRecordsByZone ensures that records presented
+// concurrently for the same provider configuration and zone are passed to a
+// single provider API call, whilst those for another zone are not.
+func TestCallBatchProviderGroupsRecordsByZone(t *testing.T) {
+	s := &Solver{
+		Context: &controller.Context{},
+		batcher: newRecordBatcher(100 * time.Millisecond),
+	}
+	issuer := gen.Issuer("test", gen.SetIssuerNamespace("testns"))
+	exampleConfig := &cmacme.ACMEChallengeSolverDNS01{
+		ZoneName: "example.com",
+		Route53:  &cmacme.ACMEIssuerDNS01ProviderRoute53{Region: "us-east-1"},
+	}
+	otherConfig := exampleConfig.DeepCopy()
+	otherConfig.ZoneName = "example.org"
+
+	var lock sync.Mutex
+	var calls [][]string
+	present := func(records []util.TXTRecord) error {
+		lock.Lock()
+		defer lock.Unlock()
+		var domains []string
+		for _, record := range records {
+			domains = append(domains, record.Domain)
+		}
+		sort.Strings(domains)
+		calls = append(calls, domains)
+		return nil
+	}
+
+	challenges := []struct {
+		config *cmacme.ACMEChallengeSolverDNS01
+		domain string
+	}{
+		{exampleConfig, "example.com"},
+		{exampleConfig, "bar.example.com"},
+		{exampleConfig, "foo.example.com"},
+		{otherConfig, "example.org"},
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(challenges))
+	for i, ch := range challenges {
+		wg.Add(1)
+		go func(i int, config *cmacme.ACMEChallengeSolverDNS01, domain string) {
+			defer wg.Done()
+			record := util.TXTRecord{Domain: domain, FQDN: fmt.Sprintf("_acme-challenge.%s.", domain), Value: "key"}
+			errs[i] = s.callBatchProvider(context.Background(), "present", issuer, config, record, present)
+		}(i, ch.config, ch.domain)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("unexpected error presenting %q: %v", challenges[i].domain, err)
+		}
+	}
+
+	sort.Slice(calls, func(i, j int) bool { return len(calls[i]) > len(calls[j]) })
+	expected := [][]string{{"bar.example.com", "example.com", "foo.example.com"}, {"example.org"}}
+	if fmt.Sprint(calls) != fmt.Sprint(expected) {
+		t.Errorf("expected provider calls %v but got %v", expected, calls)
+	}
+}
+
+func TestRecordBatcherReturnsBatchError(t *testing.T) {
+	b := newRecordBatcher(100 * time.Millisecond)
+
+	calls := 0
+	fail := func([]util.TXTRecord) error {
+		calls++
+		return fmt.Errorf("provider unavailable")
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = b.add(context.Background(), "key", util.TXTRecord{Value: fmt.Sprint(i)}, fail)
+		}(i)
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("expected 1 call but got %d", calls)
+	}
+	for i, err := range errs {
+		if err == nil {
+			t.Errorf("expected record %d to return the error of its batch", i)
+		}
+	}
+
+	// a new batch is started once the previous one has been performed
+	if err := b.add(context.Background(), "key", util.TXTRecord{}, func([]util.TXTRecord) error { return nil }); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -31,6 +31,7 @@ import (
 // DNSProvider is an implementation of the DNSProvider interface.
 type DNSProvider struct {
 	hostedZoneName   string
+	zoneName         string
 	dns01Nameservers []string
 	project          string
 	client           *dns.Service
@@ -57,6 +58,13 @@ func NewDNSProvider(project string, saBytes []byte, dns01Nameservers []string, a
 	return nil, fmt.Errorf("missing Google Cloud DNS provider credentials")
 }
 
+// SetZoneName configures the provider to look up the managed zone using the
+// given zone name instead of the zone detected for each fqdn. It has no
+// effect if a hosted zone name is set.
+func (c *DNSProvider) SetZoneName(zoneName string) {
+	c.zoneName = zoneName
+}
+
 // NewDNSProviderEnvironment returns a DNSProvider instance configured for Google Cloud
 // DNS. Project name must be passed in the environment variable: GCE_PROJECT.
 // A Service Account file can be passed in the environment variable:
@@ -217,7 +225,7 @@ func (c *DNSProvider) getHostedZone(domain string) (string, error) {
 		return c.hostedZoneName, nil
 	}
 
-	authZone, err := util.FindZoneByFqdn(util.ToFqdn(domain), c.dns01Nameservers)
+	authZone, err := util.FindZoneByFqdnOrOverride(util.ToFqdn(domain), c.zoneName, c.dns01Nameservers)
 	if err != nil {
 		return "", err
 	}
@@ -30,7 +30,9 @@ const CloudFlareAPIURL = "https://api.cloudflare.com/client/v4"
 
 // DNSProvider is an implementation of the acme.ChallengeProvider interface
 type DNSProvider struct {
+	apiURL           string
 	dns01Nameservers []string
+	zoneName         string
 	authEmail        string
 	authKey          string
 	authToken        string
@@ -69,6 +71,7 @@ func NewDNSProviderCredentials(email, key, token string, dns01Nameservers []stri
 	}
 
 	return &DNSProvider{
+		apiURL:           CloudFlareAPIURL,
 		authEmail:        email,
 		authKey:          key,
 		authToken:        token,
@@ -76,6 +79,12 @@ func NewDNSProviderCredentials(email, key, token string, dns01Nameservers []stri
 	}, nil
 }
 
+// SetZoneName configures the provider to use the given zone instead of
+// detecting the zone of each fqdn.
+func (c *DNSProvider) SetZoneName(zoneName string) {
+	c.zoneName = zoneName
+}
+
 // Present creates a TXT record to fulfil the dns-01 challenge
 func (c *DNSProvider) Present(domain, fqdn, value string) error {
 	zoneID, err := c.getHostedZoneID(fqdn)
@@ -139,6 +148,90 @@ func (c *DNSProvider) CleanUp(domain, fqdn, value string) error {
 	return nil
 }
 
+// PresentBatch creates TXT records for all of the given records, which must
+// be in the same zone, using a single batch request. Existing TXT records
+// with the same names but other values are deleted by the same request.
+func (c *DNSProvider) PresentBatch(records []util.TXTRecord) error {
+	zoneID, err := c.getHostedZoneID(records[0].FQDN)
+	if err != nil {
+		return err
+	}
+
+	var batch cloudFlareBatch
+	fqdns, values := util.GroupTXTRecordValues(records)
+	for _, fqdn := range fqdns {
+		existing, err := c.listTxtRecords(zoneID, fqdn)
+		if err != nil {
+			return err
+		}
+
+		present := make(map[string]bool)
+		for _, rec := range existing {
+			if pkgutil.Contains(values[fqdn], rec.Content) {
+				present[rec.Content] = true
+				continue
+			}
+			batch.Deletes = append(batch.Deletes, cloudFlareRecordID{ID: rec.ID})
+		}
+
+		for _, value := range values[fqdn] {
+			if present[value] {
+				continue
+			}
+			batch.Posts = append(batch.Posts, cloudFlareRecord{
+				Type:    "TXT",
+				Name:    util.UnFqdn(fqdn),
+				Content: value,
+				TTL:     120,
+			})
+		}
+	}
+
+	return c.applyBatch(zoneID, batch)
+}
+
+// CleanUpBatch removes the TXT records matching all of the given records,
+// which must be in the same zone, using a single batch request.
+func (c *DNSProvider) CleanUpBatch(records []util.TXTRecord) error {
+	zoneID, err := c.getHostedZoneID(records[0].FQDN)
+	if err != nil {
+		return err
+	}
+
+	var batch cloudFlareBatch
+	fqdns, values := util.GroupTXTRecordValues(records)
+	for _, fqdn := range fqdns {
+		existing, err := c.listTxtRecords(zoneID, fqdn)
+		if err != nil {
+			return err
+		}
+
+		for _, rec := range existing {
+			if pkgutil.Contains(values[fqdn], rec.Content) {
+				batch.Deletes = append(batch.Deletes, cloudFlareRecordID{ID: rec.ID})
+			}
+		}
+	}
+
+	return c.applyBatch(zoneID, batch)
+}
+
+// applyBatch performs all of the operations of batch in the given zone with
+// a single request.
+func (c *DNSProvider) applyBatch(zoneID string, batch cloudFlareBatch) error {
+	if len(batch.Deletes) == 0 && len(batch.Posts) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.makeRequest("POST", fmt.Sprintf("/zones/%s/dns_records/batch", zoneID), bytes.NewReader(body))
+	return err
+}
+
 func (c *DNSProvider) getHostedZoneID(fqdn string) (string, error) {
 	// HostedZone represents a CloudFlare DNS zone
 	type HostedZone struct {
@@ -146,7 +239,7 @@ func (c *DNSProvider) getHostedZoneID(fqdn string) (string, error) {
 		Name string `json:"name"`
 	}
 
-	authZone, err := util.FindZoneByFqdn(fqdn, c.dns01Nameservers)
+	authZone, err := util.FindZoneByFqdnOrOverride(fqdn, c.zoneName, c.dns01Nameservers)
 	if err != nil {
 		return "", err
 	}
@@ -177,6 +270,19 @@ func (c *DNSProvider) findTxtRecord(fqdn string) (*cloudFlareRecord, error) {
 		return nil, err
 	}
 
+	records, err := c.listTxtRecords(zoneID, fqdn)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errNoExistingRecord
+	}
+
+	return &records[0], nil
+}
+
+// listTxtRecords returns all TXT records named fqdn in the given zone.
+func (c *DNSProvider) listTxtRecords(zoneID, fqdn string) ([]cloudFlareRecord, error) {
 	result, err := c.makeRequest(
 		"GET",
 		fmt.Sprintf("/zones/%s/dns_records?per_page=100&type=TXT&name=%s", zoneID, util.UnFqdn(fqdn)),
@@ -192,13 +298,14 @@ func (c *DNSProvider) findTxtRecord(fqdn string) (*cloudFlareRecord, error) {
 		return nil, err
 	}
 
+	var matching []cloudFlareRecord
 	for _, rec := range records {
 		if rec.Name == util.UnFqdn(fqdn) {
-			return &rec, nil
+			matching = append(matching, rec)
 		}
 	}
 
-	return nil, errNoExistingRecord
+	return matching, nil
 }
 
 func (c *DNSProvider) makeRequest(method, uri string, body io.Reader) (json.RawMessage, error) {
@@ -216,7 +323,7 @@ func (c *DNSProvider) makeRequest(method, uri string, body io.Reader) (json.RawM
 		Result  json.RawMessage `json:"result"`
 	}
 
-	req, err := http.NewRequest(method, fmt.Sprintf("%s%s", CloudFlareAPIURL, uri), body)
+	req, err := http.NewRequest(method, fmt.Sprintf("%s%s", c.apiURL, uri), body)
 	if err != nil {
 		return nil, err
 	}
@@ -274,6 +381,18 @@ type cloudFlareRecord struct {
 	ZoneID  string `json:"zone_id,omitempty"`
 }
 
+// cloudFlareBatch represents a request to change multiple CloudFlare DNS
+// records at once
+type cloudFlareBatch struct {
+	Deletes []cloudFlareRecordID `json:"deletes,omitempty"`
+	Posts   []cloudFlareRecord   `json:"posts,omitempty"`
+}
+
+// cloudFlareRecordID identifies a CloudFlare DNS record in a batch request
+type cloudFlareRecordID struct {
+	ID string `json:"id"`
+}
+
 // following functions are copy-pasted from go's internal
 // http server
 func validHeaderFieldValue(v string) bool {
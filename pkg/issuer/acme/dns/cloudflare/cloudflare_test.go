@@ -9,12 +9,17 @@ this directory.
 package cloudflare
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -104,3 +109,71 @@ func TestCloudFlareCleanUp(t *testing.T) {
 	err = provider.CleanUp(cflareDomain, "_acme-challenge."+cflareDomain+".", "123d==")
 	assert.NoError(t, err)
 }
+
+func TestCloudFlarePresentBatch(t *testing.T) {
+	var batches []cloudFlareBatch
+	var writes int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var result interface{}
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/zones":
+			result = []map[string]string{{"id": "zone-1", "name": "example.com"}}
+		case r.Method == "GET" && r.URL.Path == "/zones/zone-1/dns_records":
+			var records []cloudFlareRecord
+			if r.URL.Query().Get("name") == "_acme-challenge.example.com" {
+				records = append(records,
+					cloudFlareRecord{ID: "stale", Name: "_acme-challenge.example.com", Type: "TXT", Content: "stale"},
+					cloudFlareRecord{ID: "current", Name: "_acme-challenge.example.com", Type: "TXT", Content: "apex"},
+				)
+			}
+			result = records
+		case r.Method == "POST" && r.URL.Path == "/zones/zone-1/dns_records/batch":
+			writes++
+			var batch cloudFlareBatch
+			if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			batches = append(batches, batch)
+		default:
+			writes++
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"success":false}`)
+			return
+		}
+
+		b, _ := json.Marshal(result)
+		fmt.Fprintf(w, `{"success":true,"result":%s}`, b)
+	}))
+	defer ts.Close()
+
+	provider, err := NewDNSProviderCredentials("", "", "123", []string{"127.0.0.1:1"})
+	require.NoError(t, err)
+	provider.SetZoneName("example.com.")
+	provider.apiURL = ts.URL
+
+	records := []util.TXTRecord{
+		{Domain: "example.com", FQDN: "_acme-challenge.example.com.", Value: "apex"},
+		{Domain: "*.example.com", FQDN: "_acme-challenge.example.com.", Value: "wildcard"},
+		{Domain: "foo.example.com", FQDN: "_acme-challenge.foo.example.com.", Value: "foo"},
+	}
+	err = provider.PresentBatch(records)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, writes, "Expected a single batch request for all records")
+	require.Len(t, batches, 1)
+	assert.Equal(t, []cloudFlareRecordID{{ID: "stale"}}, batches[0].Deletes)
+	assert.Equal(t, []cloudFlareRecord{
+		{Type: "TXT", Name: "_acme-challenge.example.com", Content: "wildcard", TTL: 120},
+		{Type: "TXT", Name: "_acme-challenge.foo.example.com", Content: "foo", TTL: 120},
+	}, batches[0].Posts)
+
+	batches, writes = nil, 0
+	err = provider.CleanUpBatch(records)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, writes, "Expected a single batch request for all records")
+	require.Len(t, batches, 1)
+	assert.Equal(t, []cloudFlareRecordID{{ID: "current"}}, batches[0].Deletes)
+	assert.Empty(t, batches[0].Posts)
+}
@@ -32,6 +32,7 @@ import (
 // DNSProvider is an implementation of the acme.ChallengeProvider interface
 type DNSProvider struct {
 	dns01Nameservers []string
+	zoneName         string
 	client           *godo.Client
 }
 
@@ -60,10 +61,16 @@ func NewDNSProviderCredentials(token string, dns01Nameservers []string) (*DNSPro
 	}, nil
 }
 
+// SetZoneName configures the provider to use the given zone instead of
+// detecting the zone of each fqdn.
+func (c *DNSProvider) SetZoneName(zoneName string) {
+	c.zoneName = zoneName
+}
+
 // Present creates a TXT record to fulfil the dns-01 challenge
 func (c *DNSProvider) Present(domain, fqdn, value string) error {
 	// if DigitalOcean does not have this zone then we will find out later
-	zoneName, err := util.FindZoneByFqdn(fqdn, c.dns01Nameservers)
+	zoneName, err := util.FindZoneByFqdnOrOverride(fqdn, c.zoneName, c.dns01Nameservers)
 	if err != nil {
 		return err
 	}
@@ -103,7 +110,7 @@ func (c *DNSProvider) Present(domain, fqdn, value string) error {
 
 // CleanUp removes the TXT record matching the specified parameters
 func (c *DNSProvider) CleanUp(domain, fqdn, value string) error {
-	zoneName, err := util.FindZoneByFqdn(fqdn, c.dns01Nameservers)
+	zoneName, err := util.FindZoneByFqdnOrOverride(fqdn, c.zoneName, c.dns01Nameservers)
 	if err != nil {
 		return err
 	}
@@ -126,7 +133,7 @@ func (c *DNSProvider) CleanUp(domain, fqdn, value string) error {
 
 func (c *DNSProvider) findTxtRecord(fqdn string) ([]godo.DomainRecord, error) {
 
-	zoneName, err := util.FindZoneByFqdn(fqdn, c.dns01Nameservers)
+	zoneName, err := util.FindZoneByFqdnOrOverride(fqdn, c.zoneName, c.dns01Nameservers)
 	if err != nil {
 		return nil, err
 	}
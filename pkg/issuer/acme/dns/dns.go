@@ -46,6 +46,10 @@ import (
 	logf "github.com/jetstack/cert-manager/pkg/logs"
 )
 
+// maxProviderAPIRetryBackoff is the maximum time waited between retries of a
+// failed DNS provider API call.
+const maxProviderAPIRetryBackoff = time.Minute
+
 // solver is the old solver type interface.
 // All new solvers should be implemented using the new webhook.Solver interface.
 type solver interface {
@@ -53,6 +57,12 @@ type solver interface {
 	CleanUp(domain, fqdn, value string) error
 }
 
+// zoneNameSetter is implemented by providers that can use a configured zone
+// name instead of detecting the zone of each fqdn.
+type zoneNameSetter interface {
+	SetZoneName(zoneName string)
+}
+
 // dnsProviderConstructors defines how each provider may be constructed.
 // It is useful for mocking out a given provider since an alternate set of
 // constructors may be set.
@@ -73,6 +83,10 @@ type Solver struct {
 	secretLister            corev1listers.SecretLister
 	dnsProviderConstructors dnsProviderConstructors
 	webhookSolvers          map[string]webhook.Solver
+
+	// batcher groups the operations of providers that implement
+	// batchSolver. It is nil if batching is disabled.
+	batcher *recordBatcher
 }
 
 // Present performs the work to configure DNS to resolve a DNS01 challenge.
@@ -86,7 +100,9 @@ func (s *Solver) Present(ctx context.Context, issuer v1.GenericIssuer, ch *cmacm
 	}
 	if err == nil {
 		log.V(logf.InfoLevel).Info("presenting DNS01 challenge for domain")
-		return webhookSolver.Present(req)
+		return s.callProvider(ctx, "present", func() error {
+			return webhookSolver.Present(req)
+		})
 	}
 
 	slv, providerConfig, err := s.solverForChallenge(ctx, issuer, ch)
@@ -94,29 +110,51 @@ func (s *Solver) Present(ctx context.Context, issuer v1.GenericIssuer, ch *cmacm
 		return err
 	}
 
-	fqdn, err := util.DNS01LookupFQDN(ch.Spec.DNSName, followCNAME(providerConfig.CNAMEStrategy), s.DNS01Nameservers...)
+	fqdn, err := util.DNS01LookupFQDN(ch.Spec.DNSName, followCNAME(providerConfig.CNAMEStrategy), s.DNS01Nameservers.Get()...)
 	if err != nil {
 		return err
 	}
 
 	log.V(logf.DebugLevel).Info("presenting DNS01 challenge for domain")
 
-	return slv.Present(ch.Spec.DNSName, fqdn, ch.Spec.Key)
+	if bslv, ok := slv.(batchSolver); ok && s.batcher != nil {
+		record := util.TXTRecord{Domain: ch.Spec.DNSName, FQDN: fqdn, Value: ch.Spec.Key}
+		return s.callBatchProvider(ctx, "present", issuer, providerConfig, record, bslv.PresentBatch)
+	}
+
+	return s.callProvider(ctx, "present", func() error {
+		return slv.Present(ch.Spec.DNSName, fqdn, ch.Spec.Key)
+	})
 }
 
 // Check verifies that the DNS records for the ACME challenge have propagated.
 func (s *Solver) Check(ctx context.Context, issuer v1.GenericIssuer, ch *cmacme.Challenge) error {
 	log := logf.WithResource(logf.FromContext(ctx, "Check"), ch).WithValues("domain", ch.Spec.DNSName)
 
-	fqdn, err := util.DNS01LookupFQDN(ch.Spec.DNSName, false, s.DNS01Nameservers...)
+	fqdn, err := util.DNS01LookupFQDN(ch.Spec.DNSName, false, s.DNS01Nameservers.Get()...)
+	if err != nil {
+		return err
+	}
+
+	providerConfig, err := extractChallengeSolverConfig(ch)
 	if err != nil {
 		return err
 	}
 
-	log.V(logf.DebugLevel).Info("checking DNS propagation", "nameservers", s.Context.DNS01Nameservers)
+	threshold := util.DefaultPropagationThresholdPercent
+	if providerConfig.PropagationThresholdPercent != nil {
+		threshold = *providerConfig.PropagationThresholdPercent
+	}
+
+	log.V(logf.DebugLevel).Info("checking DNS propagation", "nameservers", s.Context.DNS01Nameservers.Get(), "validateDNSSEC", providerConfig.ValidateDNSSEC, "propagationThresholdPercent", threshold)
 
-	ok, err := util.PreCheckDNS(fqdn, ch.Spec.Key, s.Context.DNS01Nameservers,
-		s.Context.DNS01CheckAuthoritative)
+	var ok bool
+	if providerConfig.ValidateDNSSEC {
+		ok, err = util.PreCheckDNSSEC(fqdn, ch.Spec.Key, s.Context.DNS01Nameservers.Get(), threshold)
+	} else {
+		ok, err = util.PreCheckDNS(fqdn, ch.Spec.Key, s.Context.DNS01Nameservers.Get(),
+			s.Context.DNS01CheckAuthoritative, threshold)
+	}
 	if err != nil {
 		return err
 	}
@@ -144,7 +182,9 @@ func (s *Solver) CleanUp(ctx context.Context, issuer v1.GenericIssuer, ch *cmacm
 	}
 	if err == nil {
 		log.V(logf.DebugLevel).Info("cleaning up DNS01 challenge")
-		return webhookSolver.CleanUp(req)
+		return s.callProvider(ctx, "cleanup", func() error {
+			return webhookSolver.CleanUp(req)
+		})
 	}
 
 	slv, providerConfig, err := s.solverForChallenge(ctx, issuer, ch)
@@ -152,12 +192,54 @@ func (s *Solver) CleanUp(ctx context.Context, issuer v1.GenericIssuer, ch *cmacm
 		return err
 	}
 
-	fqdn, err := util.DNS01LookupFQDN(ch.Spec.DNSName, followCNAME(providerConfig.CNAMEStrategy), s.DNS01Nameservers...)
+	fqdn, err := util.DNS01LookupFQDN(ch.Spec.DNSName, followCNAME(providerConfig.CNAMEStrategy), s.DNS01Nameservers.Get()...)
 	if err != nil {
 		return err
 	}
 
-	return slv.CleanUp(ch.Spec.DNSName, fqdn, ch.Spec.Key)
+	if bslv, ok := slv.(batchSolver); ok && s.batcher != nil {
+		record := util.TXTRecord{Domain: ch.Spec.DNSName, FQDN: fqdn, Value: ch.Spec.Key}
+		return s.callBatchProvider(ctx, "cleanup", issuer, providerConfig, record, bslv.CleanUpBatch)
+	}
+
+	return s.callProvider(ctx, "cleanup", func() error {
+		return slv.CleanUp(ch.Spec.DNSName, fqdn, ch.Spec.Key)
+	})
+}
+
+// callProvider calls fn, which performs a single DNS provider API call,
+// retrying it up to DNS01ProviderAPIRetries times if it fails. The time
+// waited between attempts starts at DNS01ProviderAPIRetryBackoff and is
+// doubled after each failed attempt, up to maxProviderAPIRetryBackoff.
+func (s *Solver) callProvider(ctx context.Context, operation string, fn func() error) error {
+	log := logf.FromContext(ctx)
+
+	backoff := s.DNS01ProviderAPIRetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if attempt > s.DNS01ProviderAPIRetries {
+			if attempt > 1 {
+				return fmt.Errorf("DNS01 provider %s failed after %d attempts: %w", operation, attempt, err)
+			}
+			return err
+		}
+
+		log.V(logf.DebugLevel).Info("DNS01 provider API call failed, retrying", "operation", operation, "attempt", attempt, "backoff", backoff, "error", err.Error())
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxProviderAPIRetryBackoff {
+			backoff = maxProviderAPIRetryBackoff
+		}
+	}
 }
 
 func followCNAME(strategy cmacme.CNAMEStrategy) bool {
@@ -169,7 +251,90 @@ func extractChallengeSolverConfig(ch *cmacme.Challenge) (*cmacme.ACMEChallengeSo
 		return nil, fmt.Errorf("no dns01 challenge solver configuration found")
 	}
 
-	return ch.Spec.Solver.DNS01, nil
+	return applyZoneCredentials(ch.Spec.Solver.DNS01, ch.Spec.DNSName), nil
+}
+
+// applyZoneCredentials returns the solver configuration to use for a
+// challenge for the given DNS name. If the name falls within one of the zones
+// listed in the configuration's ZoneCredentials, a copy of the configuration
+// is returned with every referenced Secret name replaced by the Secret for
+// the most specific matching zone. Otherwise the configuration is returned
+// unmodified.
+func applyZoneCredentials(cfg *cmacme.ACMEChallengeSolverDNS01, dnsName string) *cmacme.ACMEChallengeSolverDNS01 {
+	secretName := zoneCredentialsSecretName(cfg.ZoneCredentials, dnsName)
+	if secretName == "" {
+		return cfg
+	}
+
+	cfg = cfg.DeepCopy()
+	for _, ref := range providerSecretRefs(cfg) {
+		// an empty name means no Secret is referenced, e.g. because ambient
+		// credentials are used, so leave it unset.
+		if ref.Name != "" {
+			ref.Name = secretName
+		}
+	}
+	return cfg
+}
+
+// zoneCredentialsSecretName returns the name of the Secret configured for the
+// longest zone that is equal to, or a parent of, dnsName. An empty string is
+// returned if no zone matches.
+func zoneCredentialsSecretName(zoneCredentials []cmacme.ACMEChallengeSolverDNS01ZoneCredentials, dnsName string) string {
+	dnsName = strings.ToLower(strings.TrimSuffix(dnsName, "."))
+
+	secretName := ""
+	longestMatch := -1
+	for _, zc := range zoneCredentials {
+		for _, zone := range zc.DNSZones {
+			zone = strings.ToLower(strings.TrimSuffix(zone, "."))
+			if dnsName != zone && !strings.HasSuffix(dnsName, "."+zone) {
+				continue
+			}
+			if len(zone) > longestMatch {
+				longestMatch = len(zone)
+				secretName = zc.SecretName
+			}
+		}
+	}
+	return secretName
+}
+
+// providerSecretRefs returns references to all Secrets used by the DNS
+// provider configured in cfg.
+func providerSecretRefs(cfg *cmacme.ACMEChallengeSolverDNS01) []*cmmeta.LocalObjectReference {
+	var refs []*cmmeta.LocalObjectReference
+	switch {
+	case cfg.Akamai != nil:
+		refs = append(refs,
+			&cfg.Akamai.ClientToken.LocalObjectReference,
+			&cfg.Akamai.ClientSecret.LocalObjectReference,
+			&cfg.Akamai.AccessToken.LocalObjectReference)
+	case cfg.CloudDNS != nil:
+		if cfg.CloudDNS.ServiceAccount != nil {
+			refs = append(refs, &cfg.CloudDNS.ServiceAccount.LocalObjectReference)
+		}
+	case cfg.Cloudflare != nil:
+		if cfg.Cloudflare.APIKey != nil {
+			refs = append(refs, &cfg.Cloudflare.APIKey.LocalObjectReference)
+		}
+		if cfg.Cloudflare.APIToken != nil {
+			refs = append(refs, &cfg.Cloudflare.APIToken.LocalObjectReference)
+		}
+	case cfg.DigitalOcean != nil:
+		refs = append(refs, &cfg.DigitalOcean.Token.LocalObjectReference)
+	case cfg.Route53 != nil:
+		refs = append(refs, &cfg.Route53.SecretAccessKey.LocalObjectReference)
+	case cfg.AzureDNS != nil:
+		if cfg.AzureDNS.ClientSecret != nil {
+			refs = append(refs, &cfg.AzureDNS.ClientSecret.LocalObjectReference)
+		}
+	case cfg.AcmeDNS != nil:
+		refs = append(refs, &cfg.AcmeDNS.AccountSecret.LocalObjectReference)
+	case cfg.RFC2136 != nil:
+		refs = append(refs, &cfg.RFC2136.TSIGSecret.LocalObjectReference)
+	}
+	return refs
 }
 
 // solverForChallenge returns a Solver for the given providerName.
@@ -180,7 +345,6 @@ func (s *Solver) solverForChallenge(ctx context.Context, issuer v1.GenericIssuer
 	dbg := log.V(logf.DebugLevel)
 
 	resourceNamespace := s.ResourceNamespace(issuer)
-	canUseAmbientCredentials := s.CanUseAmbientCredentials(issuer)
 
 	providerConfig, err := extractChallengeSolverConfig(ch)
 	if err != nil {
@@ -211,7 +375,7 @@ func (s *Solver) solverForChallenge(ctx context.Context, issuer v1.GenericIssuer
 			string(clientToken),
 			string(clientSecret),
 			string(accessToken),
-			s.DNS01Nameservers)
+			s.DNS01Nameservers.Get())
 		if err != nil {
 			return nil, nil, errors.Wrap(err, "error instantiating akamai challenge solver")
 		}
@@ -236,7 +400,7 @@ func (s *Solver) solverForChallenge(ctx context.Context, issuer v1.GenericIssuer
 		}
 
 		// attempt to construct the cloud dns provider
-		impl, err = s.dnsProviderConstructors.cloudDNS(providerConfig.CloudDNS.Project, keyData, s.DNS01Nameservers, s.CanUseAmbientCredentials(issuer), providerConfig.CloudDNS.HostedZoneName)
+		impl, err = s.dnsProviderConstructors.cloudDNS(providerConfig.CloudDNS.Project, keyData, s.DNS01Nameservers.Get(), s.CanUseAmbientCredentialsForProvider(issuer, controller.AmbientCredentialProviderCloudDNS), providerConfig.CloudDNS.HostedZoneName)
 		if err != nil {
 			return nil, nil, fmt.Errorf("error instantiating google clouddns challenge solver: %s", err)
 		}
@@ -273,7 +437,7 @@ func (s *Solver) solverForChallenge(ctx context.Context, issuer v1.GenericIssuer
 		}
 
 		email := providerConfig.Cloudflare.Email
-		impl, err = s.dnsProviderConstructors.cloudFlare(email, apiKey, apiToken, s.DNS01Nameservers)
+		impl, err = s.dnsProviderConstructors.cloudFlare(email, apiKey, apiToken, s.DNS01Nameservers.Get())
 		if err != nil {
 			return nil, nil, fmt.Errorf("error instantiating cloudflare challenge solver: %s", err)
 		}
@@ -286,7 +450,7 @@ func (s *Solver) solverForChallenge(ctx context.Context, issuer v1.GenericIssuer
 
 		apiToken := string(apiTokenSecret.Data[providerConfig.DigitalOcean.Token.Key])
 
-		impl, err = s.dnsProviderConstructors.digitalOcean(strings.TrimSpace(apiToken), s.DNS01Nameservers)
+		impl, err = s.dnsProviderConstructors.digitalOcean(strings.TrimSpace(apiToken), s.DNS01Nameservers.Get())
 		if err != nil {
 			return nil, nil, fmt.Errorf("error instantiating digitalocean challenge solver: %s", err.Error())
 		}
@@ -312,8 +476,8 @@ func (s *Solver) solverForChallenge(ctx context.Context, issuer v1.GenericIssuer
 			providerConfig.Route53.HostedZoneID,
 			providerConfig.Route53.Region,
 			providerConfig.Route53.Role,
-			canUseAmbientCredentials,
-			s.DNS01Nameservers,
+			s.CanUseAmbientCredentialsForProvider(issuer, controller.AmbientCredentialProviderRoute53),
+			s.DNS01Nameservers.Get(),
 		)
 		if err != nil {
 			return nil, nil, fmt.Errorf("error instantiating route53 challenge solver: %s", err)
@@ -335,6 +499,11 @@ func (s *Solver) solverForChallenge(ctx context.Context, issuer v1.GenericIssuer
 			}
 			secret = string(clientSecretBytes)
 		}
+		// the configured zone name is used if no hosted zone name is set
+		hostedZoneName := providerConfig.AzureDNS.HostedZoneName
+		if hostedZoneName == "" {
+			hostedZoneName = util.UnFqdn(providerConfig.ZoneName)
+		}
 		impl, err = s.dnsProviderConstructors.azureDNS(
 			string(providerConfig.AzureDNS.Environment),
 			providerConfig.AzureDNS.ClientID,
@@ -342,9 +511,9 @@ func (s *Solver) solverForChallenge(ctx context.Context, issuer v1.GenericIssuer
 			providerConfig.AzureDNS.SubscriptionID,
 			providerConfig.AzureDNS.TenantID,
 			providerConfig.AzureDNS.ResourceGroupName,
-			providerConfig.AzureDNS.HostedZoneName,
-			s.DNS01Nameservers,
-			canUseAmbientCredentials,
+			hostedZoneName,
+			s.DNS01Nameservers.Get(),
+			s.CanUseAmbientCredentialsForProvider(issuer, controller.AmbientCredentialProviderAzureDNS),
 		)
 		if err != nil {
 			return nil, nil, fmt.Errorf("error instantiating azuredns challenge solver: %s", err)
@@ -364,7 +533,7 @@ func (s *Solver) solverForChallenge(ctx context.Context, issuer v1.GenericIssuer
 		impl, err = s.dnsProviderConstructors.acmeDNS(
 			providerConfig.AcmeDNS.Host,
 			accountSecretBytes,
-			s.DNS01Nameservers,
+			s.DNS01Nameservers.Get(),
 		)
 		if err != nil {
 			return nil, providerConfig, fmt.Errorf("error instantiating acmedns challenge solver: %s", err)
@@ -373,6 +542,12 @@ func (s *Solver) solverForChallenge(ctx context.Context, issuer v1.GenericIssuer
 		return nil, providerConfig, fmt.Errorf("no dns provider config specified for challenge")
 	}
 
+	if providerConfig.ZoneName != "" {
+		if setter, ok := impl.(zoneNameSetter); ok {
+			setter.SetZoneName(providerConfig.ZoneName)
+		}
+	}
+
 	return impl, providerConfig, nil
 }
 
@@ -387,18 +562,18 @@ func (s *Solver) prepareChallengeRequest(issuer v1.GenericIssuer, ch *cmacme.Cha
 		return nil, nil, err
 	}
 
-	fqdn, err := util.DNS01LookupFQDN(ch.Spec.DNSName, followCNAME(dns01Config.CNAMEStrategy), s.DNS01Nameservers...)
+	fqdn, err := util.DNS01LookupFQDN(ch.Spec.DNSName, followCNAME(dns01Config.CNAMEStrategy), s.DNS01Nameservers.Get()...)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	zone, err := util.FindZoneByFqdn(fqdn, s.DNS01Nameservers)
+	zone, err := util.FindZoneByFqdnOrOverride(fqdn, dns01Config.ZoneName, s.DNS01Nameservers.Get())
 	if err != nil {
 		return nil, nil, err
 	}
 
 	resourceNamespace := s.ResourceNamespace(issuer)
-	canUseAmbientCredentials := s.CanUseAmbientCredentials(issuer)
+	canUseAmbientCredentials := s.CanUseAmbientCredentialsForProvider(issuer, controller.AmbientCredentialProviderWebhook)
 
 	// construct a ChallengeRequest which can be passed to DNS solvers.
 	// The provided config will be encoded to JSON in order to avoid a coupling
@@ -468,6 +643,11 @@ func NewSolver(ctx *controller.Context) (*Solver, error) {
 		}
 	}
 
+	var batcher *recordBatcher
+	if ctx.DNS01ProviderBatchWindow > 0 {
+		batcher = newRecordBatcher(ctx.DNS01ProviderBatchWindow)
+	}
+
 	return &Solver{
 		Context:      ctx,
 		secretLister: ctx.KubeSharedInformerFactory.Core().V1().Secrets().Lister(),
@@ -480,6 +660,7 @@ func NewSolver(ctx *controller.Context) (*Solver, error) {
 			digitalocean.NewDNSProviderCredentials,
 		},
 		webhookSolvers: initialized,
+		batcher:        batcher,
 	}, nil
 }
 
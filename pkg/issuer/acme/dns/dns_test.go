@@ -18,8 +18,11 @@ package dns
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -32,6 +35,7 @@ import (
 	"github.com/jetstack/cert-manager/pkg/controller/test"
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/acmedns"
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/cloudflare"
+	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/digitalocean"
 	"github.com/jetstack/cert-manager/pkg/issuer/acme/dns/util"
 )
 
@@ -351,6 +355,68 @@ func TestSolveForDigitalOcean(t *testing.T) {
 
 }
 
+func TestSolveForDigitalOceanWithZoneName(t *testing.T) {
+	f := &solverFixture{
+		Builder: &test.Builder{
+			KubeObjects: []runtime.Object{
+				newSecret("digitalocean", "default", map[string][]byte{
+					"token": []byte("FAKE-TOKEN"),
+				}),
+			},
+		},
+		Issuer: newIssuer("test", "default"),
+		Challenge: &cmacme.Challenge{
+			Spec: cmacme.ChallengeSpec{
+				Solver: cmacme.ACMEChallengeSolver{
+					DNS01: &cmacme.ACMEChallengeSolverDNS01{
+						ZoneName: "example.com",
+						DigitalOcean: &cmacme.ACMEIssuerDNS01ProviderDigitalOcean{
+							Token: cmmeta.SecretKeySelector{
+								LocalObjectReference: cmmeta.LocalObjectReference{
+									Name: "digitalocean",
+								},
+								Key: "token",
+							},
+						},
+					},
+				},
+			},
+		},
+		dnsProviders: newFakeDNSProviders(),
+	}
+	// a real provider is needed to check that the zone name is set on it
+	f.dnsProviders.constructors.digitalOcean = func(token string, dns01Nameservers []string) (*digitalocean.DNSProvider, error) {
+		f.dnsProviders.call("digitalocean", token, util.RecursiveNameservers)
+		return digitalocean.NewDNSProviderCredentials(token, dns01Nameservers)
+	}
+
+	f.Setup(t)
+	defer f.Finish(t)
+
+	s := f.Solver
+	impl, _, err := s.solverForChallenge(context.Background(), f.Issuer, f.Challenge)
+	if err != nil {
+		t.Fatalf("expected solverFor to not error, but got: %s", err)
+	}
+
+	expectedDOCall := []fakeDNSProviderCall{
+		{
+			name: "digitalocean",
+			args: []interface{}{"FAKE-TOKEN", util.RecursiveNameservers},
+		},
+	}
+
+	if !reflect.DeepEqual(expectedDOCall, f.dnsProviders.calls) {
+		t.Fatalf("expected %+v == %+v", expectedDOCall, f.dnsProviders.calls)
+	}
+
+	// zone detection is skipped for a domain outside of the configured zone
+	err = impl.Present("example.org", "_acme-challenge.example.org.", "key")
+	if err == nil || !strings.Contains(err.Error(), "not within the configured zone") {
+		t.Fatalf("expected the configured zone to be used, but got: %v", err)
+	}
+}
+
 func TestRoute53TrimCreds(t *testing.T) {
 	f := &solverFixture{
 		Builder: &test.Builder{
@@ -473,6 +539,68 @@ func TestRoute53AmbientCreds(t *testing.T) {
 				},
 			},
 		},
+		{
+			solverFixture{
+				Builder: &test.Builder{
+					Context: &controller.Context{
+						IssuerOptions: controller.IssuerOptions{
+							IssuerAmbientCredentials:   true,
+							AmbientCredentialProviders: []string{controller.AmbientCredentialProviderRoute53},
+						},
+					},
+				},
+				Issuer:       newIssuer("test", "default"),
+				dnsProviders: newFakeDNSProviders(),
+				Challenge: &cmacme.Challenge{
+					Spec: cmacme.ChallengeSpec{
+						Solver: cmacme.ACMEChallengeSolver{
+							DNS01: &cmacme.ACMEChallengeSolverDNS01{
+								Route53: &cmacme.ACMEIssuerDNS01ProviderRoute53{
+									Region: "us-west-2",
+								},
+							},
+						},
+					},
+				},
+			},
+			result{
+				expectedCall: &fakeDNSProviderCall{
+					name: "route53",
+					args: []interface{}{"", "", "", "us-west-2", "", true, util.RecursiveNameservers},
+				},
+			},
+		},
+		{
+			solverFixture{
+				Builder: &test.Builder{
+					Context: &controller.Context{
+						IssuerOptions: controller.IssuerOptions{
+							IssuerAmbientCredentials:   true,
+							AmbientCredentialProviders: []string{controller.AmbientCredentialProviderCloudDNS, controller.AmbientCredentialProviderAzureDNS},
+						},
+					},
+				},
+				Issuer:       newIssuer("test", "default"),
+				dnsProviders: newFakeDNSProviders(),
+				Challenge: &cmacme.Challenge{
+					Spec: cmacme.ChallengeSpec{
+						Solver: cmacme.ACMEChallengeSolver{
+							DNS01: &cmacme.ACMEChallengeSolverDNS01{
+								Route53: &cmacme.ACMEIssuerDNS01ProviderRoute53{
+									Region: "us-west-2",
+								},
+							},
+						},
+					},
+				},
+			},
+			result{
+				expectedCall: &fakeDNSProviderCall{
+					name: "route53",
+					args: []interface{}{"", "", "", "us-west-2", "", false, util.RecursiveNameservers},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -584,3 +712,194 @@ func TestRoute53AssumeRole(t *testing.T) {
 		}
 	}
 }
+
+func TestCallProviderRetries(t *testing.T) {
+	tests := map[string]struct {
+		retries       int
+		failures      int
+		expectedCalls int
+		expectErr     bool
+	}{
+		"a successful call is not retried": {
+			retries:       3,
+			failures:      0,
+			expectedCalls: 1,
+		},
+		"transient failures are retried until the call succeeds": {
+			retries:       3,
+			failures:      2,
+			expectedCalls: 3,
+		},
+		"an error is returned once all retries have failed": {
+			retries:       2,
+			failures:      5,
+			expectedCalls: 3,
+			expectErr:     true,
+		},
+		"failed calls are not retried by default": {
+			retries:       0,
+			failures:      1,
+			expectedCalls: 1,
+			expectErr:     true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := &Solver{
+				Context: &controller.Context{
+					ACMEOptions: controller.ACMEOptions{
+						DNS01ProviderAPIRetries:      test.retries,
+						DNS01ProviderAPIRetryBackoff: time.Millisecond,
+					},
+				},
+			}
+
+			calls := 0
+			err := s.callProvider(context.Background(), "present", func() error {
+				calls++
+				if calls <= test.failures {
+					return fmt.Errorf("transient error %d", calls)
+				}
+				return nil
+			})
+			if test.expectErr != (err != nil) {
+				t.Errorf("expected error=%t but got: %v", test.expectErr, err)
+			}
+			if calls != test.expectedCalls {
+				t.Errorf("expected %d calls but got %d", test.expectedCalls, calls)
+			}
+		})
+	}
+}
+
+func TestCallProviderStopsRetryingWhenContextCancelled(t *testing.T) {
+	s := &Solver{
+		Context: &controller.Context{
+			ACMEOptions: controller.ACMEOptions{
+				DNS01ProviderAPIRetries:      3,
+				DNS01ProviderAPIRetryBackoff: time.Hour,
+			},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := s.callProvider(ctx, "cleanup", func() error {
+		calls++
+		cancel()
+		return fmt.Errorf("provider unavailable")
+	})
+	if err == nil {
+		t.Errorf("expected an error but got none")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call but got %d", calls)
+	}
+}
+
+func TestSolverForZoneCredentials(t *testing.T) {
+	zoneCredentials := []cmacme.ACMEChallengeSolverDNS01ZoneCredentials{
+		{
+			DNSZones:   []string{"example.com"},
+			SecretName: "example-com-token",
+		},
+		{
+			DNSZones:   []string{"example.org", "internal.example.com"},
+			SecretName: "example-org-token",
+		},
+	}
+
+	tests := map[string]struct {
+		domain        string
+		expectedToken string
+	}{
+		"uses the credentials of the matching zone": {
+			domain:        "example.com",
+			expectedToken: "EXAMPLE-COM-TOKEN",
+		},
+		"uses the credentials of a parent zone for a subdomain": {
+			domain:        "www.example.com",
+			expectedToken: "EXAMPLE-COM-TOKEN",
+		},
+		"uses the credentials of a second zone": {
+			domain:        "www.example.org",
+			expectedToken: "EXAMPLE-ORG-TOKEN",
+		},
+		"uses the credentials of the most specific matching zone": {
+			domain:        "www.internal.example.com",
+			expectedToken: "EXAMPLE-ORG-TOKEN",
+		},
+		"falls back to the provider's credentials if no zone matches": {
+			domain:        "example.net",
+			expectedToken: "DEFAULT-TOKEN",
+		},
+		"does not match a zone that is only a suffix of the domain": {
+			domain:        "notexample.com",
+			expectedToken: "DEFAULT-TOKEN",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := &solverFixture{
+				Builder: &test.Builder{
+					KubeObjects: []runtime.Object{
+						newSecret("digitalocean", "default", map[string][]byte{
+							"token": []byte("DEFAULT-TOKEN"),
+						}),
+						newSecret("example-com-token", "default", map[string][]byte{
+							"token": []byte("EXAMPLE-COM-TOKEN"),
+						}),
+						newSecret("example-org-token", "default", map[string][]byte{
+							"token": []byte("EXAMPLE-ORG-TOKEN"),
+						}),
+					},
+				},
+				Issuer: newIssuer("test", "default"),
+				Challenge: &cmacme.Challenge{
+					Spec: cmacme.ChallengeSpec{
+						DNSName: tt.domain,
+						Solver: cmacme.ACMEChallengeSolver{
+							DNS01: &cmacme.ACMEChallengeSolverDNS01{
+								DigitalOcean: &cmacme.ACMEIssuerDNS01ProviderDigitalOcean{
+									Token: cmmeta.SecretKeySelector{
+										LocalObjectReference: cmmeta.LocalObjectReference{
+											Name: "digitalocean",
+										},
+										Key: "token",
+									},
+								},
+								ZoneCredentials: zoneCredentials,
+							},
+						},
+					},
+				},
+				dnsProviders: newFakeDNSProviders(),
+			}
+
+			f.Setup(t)
+			defer f.Finish(t)
+
+			_, _, err := f.Solver.solverForChallenge(context.Background(), f.Issuer, f.Challenge)
+			if err != nil {
+				t.Fatalf("expected solverFor to not error, but got: %s", err)
+			}
+
+			expectedDOCall := []fakeDNSProviderCall{
+				{
+					name: "digitalocean",
+					args: []interface{}{tt.expectedToken, util.RecursiveNameservers},
+				},
+			}
+			if !reflect.DeepEqual(expectedDOCall, f.dnsProviders.calls) {
+				t.Fatalf("expected %+v == %+v", expectedDOCall, f.dnsProviders.calls)
+			}
+
+			// the challenge's own solver configuration must not be modified
+			if name := f.Challenge.Spec.Solver.DNS01.DigitalOcean.Token.Name; name != "digitalocean" {
+				t.Errorf("expected challenge to still reference secret %q but got %q", "digitalocean", name)
+			}
+		})
+	}
+}
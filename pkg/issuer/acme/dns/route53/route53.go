@@ -40,6 +40,7 @@ type DNSProvider struct {
 	dns01Nameservers []string
 	client           *route53.Route53
 	hostedZoneID     string
+	zoneName         string
 	log              logr.Logger
 }
 
@@ -162,6 +163,13 @@ func NewDNSProvider(accessKeyID, secretAccessKey, hostedZoneID, region, role str
 	}, nil
 }
 
+// SetZoneName configures the provider to use the hosted zone with the given
+// name instead of detecting the zone of each fqdn. It has no effect if a
+// hosted zone ID is set.
+func (r *DNSProvider) SetZoneName(zoneName string) {
+	r.zoneName = zoneName
+}
+
 // Present creates a TXT record using the specified parameters
 func (r *DNSProvider) Present(domain, fqdn, value string) error {
 	value = `"` + value + `"`
@@ -174,23 +182,43 @@ func (r *DNSProvider) CleanUp(domain, fqdn, value string) error {
 	return r.changeRecord(route53.ChangeActionDelete, fqdn, value, route53TTL)
 }
 
+// PresentBatch creates the TXT records for all of the given records, which
+// must be in the same hosted zone, in a single change batch.
+func (r *DNSProvider) PresentBatch(records []util.TXTRecord) error {
+	return r.changeRecordSets(route53.ChangeActionUpsert, records[0].FQDN, newTXTRecordSets(records, route53TTL))
+}
+
+// CleanUpBatch removes the TXT records for all of the given records, which
+// must be in the same hosted zone, in a single change batch.
+func (r *DNSProvider) CleanUpBatch(records []util.TXTRecord) error {
+	return r.changeRecordSets(route53.ChangeActionDelete, records[0].FQDN, newTXTRecordSets(records, route53TTL))
+}
+
 func (r *DNSProvider) changeRecord(action, fqdn, value string, ttl int) error {
+	return r.changeRecordSets(action, fqdn, []*route53.ResourceRecordSet{newTXTRecordSet(fqdn, ttl, value)})
+}
+
+// changeRecordSets performs action on all of the given record sets in a
+// single change batch. fqdn is used to determine the hosted zone of the
+// record sets.
+func (r *DNSProvider) changeRecordSets(action, fqdn string, recordSets []*route53.ResourceRecordSet) error {
 	hostedZoneID, err := r.getHostedZoneID(fqdn)
 	if err != nil {
 		return fmt.Errorf("failed to determine Route 53 hosted zone ID: %v", err)
 	}
 
-	recordSet := newTXTRecordSet(fqdn, value, ttl)
+	changes := make([]*route53.Change, len(recordSets))
+	for i, recordSet := range recordSets {
+		changes[i] = &route53.Change{
+			Action:            aws.String(action),
+			ResourceRecordSet: recordSet,
+		}
+	}
 	reqParams := &route53.ChangeResourceRecordSetsInput{
 		HostedZoneId: aws.String(hostedZoneID),
 		ChangeBatch: &route53.ChangeBatch{
 			Comment: aws.String("Managed by cert-manager"),
-			Changes: []*route53.Change{
-				{
-					Action:            &action,
-					ResourceRecordSet: recordSet,
-				},
-			},
+			Changes: changes,
 		},
 	}
 
@@ -198,6 +226,18 @@ func (r *DNSProvider) changeRecord(action, fqdn, value string, ttl int) error {
 	if err != nil {
 		if awserr, ok := err.(awserr.Error); ok {
 			if action == route53.ChangeActionDelete && awserr.Code() == route53.ErrCodeInvalidChangeBatch {
+				if len(recordSets) > 1 {
+					// The whole batch is rejected if any of the record sets
+					// has already been deleted, so delete the others one by
+					// one instead.
+					r.log.V(logf.DebugLevel).WithValues("error", err).Info("deleting record sets individually after InvalidChangeBatch error")
+					for _, recordSet := range recordSets {
+						if err := r.changeRecordSets(action, fqdn, []*route53.ResourceRecordSet{recordSet}); err != nil {
+							return err
+						}
+					}
+					return nil
+				}
 				r.log.V(logf.DebugLevel).WithValues("error", err).Info("ignoring InvalidChangeBatch error")
 				// If we try to delete something and get a 'InvalidChangeBatch' that
 				// means it's already deleted, no need to consider it an error.
@@ -230,7 +270,7 @@ func (r *DNSProvider) getHostedZoneID(fqdn string) (string, error) {
 		return r.hostedZoneID, nil
 	}
 
-	authZone, err := util.FindZoneByFqdn(fqdn, r.dns01Nameservers)
+	authZone, err := util.FindZoneByFqdnOrOverride(fqdn, r.zoneName, r.dns01Nameservers)
 	if err != nil {
 		return "", fmt.Errorf("error finding zone from fqdn: %v", err)
 	}
@@ -253,9 +293,12 @@ func (r *DNSProvider) getHostedZoneID(fqdn string) (string, error) {
 			hostedZones = append(hostedZones, *hostedZone.Name)
 		}
 	}
-	authZone, err = util.FindBestMatch(fqdn, hostedZones...)
-	if err != nil {
-		return "", fmt.Errorf("zone %s not found in Route 53 for domain %s", authZone, fqdn)
+	// a configured zone name must match a hosted zone exactly
+	if r.zoneName == "" {
+		authZone, err = util.FindBestMatch(fqdn, hostedZones...)
+		if err != nil {
+			return "", fmt.Errorf("zone %s not found in Route 53 for domain %s", authZone, fqdn)
+		}
 	}
 
 	hostedZoneID, ok := zoneToID[authZone]
@@ -271,15 +314,35 @@ func (r *DNSProvider) getHostedZoneID(fqdn string) (string, error) {
 	return hostedZoneID, nil
 }
 
-func newTXTRecordSet(fqdn, value string, ttl int) *route53.ResourceRecordSet {
+func newTXTRecordSet(fqdn string, ttl int, values ...string) *route53.ResourceRecordSet {
+	resourceRecords := make([]*route53.ResourceRecord, len(values))
+	for i, value := range values {
+		resourceRecords[i] = &route53.ResourceRecord{Value: aws.String(value)}
+	}
 	return &route53.ResourceRecordSet{
-		Name: aws.String(fqdn),
-		Type: aws.String(route53.RRTypeTxt),
-		TTL:  aws.Int64(int64(ttl)),
-		ResourceRecords: []*route53.ResourceRecord{
-			{Value: aws.String(value)},
-		},
+		Name:            aws.String(fqdn),
+		Type:            aws.String(route53.RRTypeTxt),
+		TTL:             aws.Int64(int64(ttl)),
+		ResourceRecords: resourceRecords,
+	}
+}
+
+// newTXTRecordSets returns a record set for each distinct fqdn of the given
+// records. Route 53 does not allow a change batch to change the same record
+// set more than once, so records with the same fqdn, such as those for a
+// domain and its wildcard, are combined into a single record set.
+func newTXTRecordSets(records []util.TXTRecord, ttl int) []*route53.ResourceRecordSet {
+	fqdns, values := util.GroupTXTRecordValues(records)
+
+	recordSets := make([]*route53.ResourceRecordSet, len(fqdns))
+	for i, fqdn := range fqdns {
+		quoted := make([]string, len(values[fqdn]))
+		for j, value := range values[fqdn] {
+			quoted[j] = `"` + value + `"`
+		}
+		recordSets[i] = newTXTRecordSet(fqdn, ttl, quoted...)
 	}
+	return recordSets
 }
 
 // The aws-sdk-go library appends a request id to its error messages. We
@@ -9,8 +9,11 @@ this directory.
 package route53
 
 import (
+	"encoding/xml"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
@@ -149,6 +152,100 @@ func TestRoute53Present(t *testing.T) {
 	assert.Equal(t, `failed to change Route 53 record set: AccessDenied: User: arn:aws:iam::0123456789:user/test-cert-manager is not authorized to perform: route53:ChangeResourceRecordSets on resource: arn:aws:route53:::hostedzone/OPQRSTU`, err.Error())
 }
 
+func TestRoute53PresentWithZoneName(t *testing.T) {
+	mockResponses := MockResponseMap{
+		"/2013-04-01/hostedzonesbyname":         MockResponse{StatusCode: 200, Body: ListHostedZonesByNameResponse},
+		"/2013-04-01/hostedzone/ABCDEFG/rrset/": MockResponse{StatusCode: 200, Body: ChangeResourceRecordSetsResponse},
+		"/2013-04-01/change/123456":             MockResponse{StatusCode: 200, Body: GetChangeResponse},
+	}
+
+	ts := newMockServer(t, mockResponses)
+	defer ts.Close()
+
+	provider, err := makeRoute53Provider(ts)
+	assert.NoError(t, err, "Expected to make a Route 53 provider without error")
+	// zone detection would fail as no nameservers are reachable
+	provider.dns01Nameservers = []string{"127.0.0.1:1"}
+	provider.zoneName = "example.com"
+
+	keyAuth := "123456d=="
+
+	// foo.example.com is also a hosted zone, but the configured zone is used
+	subDomain := "foo.example.com"
+	err = provider.Present(subDomain, "_acme-challenge."+subDomain+".", keyAuth)
+	assert.NoError(t, err, "Expected Present to return no error")
+
+	err = provider.Present("baz.com", "_acme-challenge.baz.com.", keyAuth)
+	assert.Error(t, err, "Expected Present to return an error for a domain outside of the zone")
+}
+
+func TestRoute53PresentBatch(t *testing.T) {
+	// changeRequest is the part of a ChangeResourceRecordSets request body
+	// that is checked by the test.
+	type changeRequest struct {
+		Changes []struct {
+			Action string   `xml:"Action"`
+			Name   string   `xml:"ResourceRecordSet>Name"`
+			Values []string `xml:"ResourceRecordSet>ResourceRecords>ResourceRecord>Value"`
+		} `xml:"ChangeBatch>Changes>Change"`
+	}
+
+	var requests []changeRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body string
+		switch r.URL.Path {
+		case "/2013-04-01/hostedzonesbyname":
+			body = ListHostedZonesByNameResponse
+		case "/2013-04-01/hostedzone/ABCDEFG/rrset/":
+			b, _ := ioutil.ReadAll(r.Body)
+			var req changeRequest
+			if err := xml.Unmarshal(b, &req); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			requests = append(requests, req)
+			body = ChangeResourceRecordSetsResponse
+		case "/2013-04-01/change/123456":
+			body = GetChangeResponse
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer ts.Close()
+
+	provider, err := makeRoute53Provider(ts)
+	require.NoError(t, err, "Expected to make a Route 53 provider without error")
+	// zone detection would fail as no nameservers are reachable
+	provider.dns01Nameservers = []string{"127.0.0.1:1"}
+	provider.zoneName = "example.com"
+
+	records := []util.TXTRecord{
+		{Domain: "example.com", FQDN: "_acme-challenge.example.com.", Value: "apex"},
+		{Domain: "*.example.com", FQDN: "_acme-challenge.example.com.", Value: "wildcard"},
+		{Domain: "foo.example.com", FQDN: "_acme-challenge.foo.example.com.", Value: "foo"},
+	}
+	err = provider.PresentBatch(records)
+	require.NoError(t, err, "Expected PresentBatch to return no error")
+
+	require.Len(t, requests, 1, "Expected a single change batch for all records")
+	changes := requests[0].Changes
+	require.Len(t, changes, 2, "Expected records with the same name to be combined")
+	assert.Equal(t, "UPSERT", changes[0].Action)
+	assert.Equal(t, "_acme-challenge.example.com.", changes[0].Name)
+	assert.Equal(t, []string{`"apex"`, `"wildcard"`}, changes[0].Values)
+	assert.Equal(t, "_acme-challenge.foo.example.com.", changes[1].Name)
+	assert.Equal(t, []string{`"foo"`}, changes[1].Values)
+
+	requests = nil
+	err = provider.CleanUpBatch(records)
+	require.NoError(t, err, "Expected CleanUpBatch to return no error")
+	require.Len(t, requests, 1, "Expected a single change batch for all records")
+	assert.Equal(t, "DELETE", requests[0].Changes[0].Action)
+}
+
 func TestAssumeRole(t *testing.T) {
 	creds := &sts.Credentials{
 		AccessKeyId:     aws.String("foo"),
@@ -32,6 +32,38 @@ func DNS01LookupFQDN(domain string, followCNAME bool, nameservers ...string) (st
 	return fqdn, nil
 }
 
+// TXTRecord is a TXT record that is presented or cleaned up to solve a
+// dns-01 challenge for Domain.
+type TXTRecord struct {
+	Domain string
+	FQDN   string
+	Value  string
+}
+
+// GroupTXTRecordValues returns the distinct FQDNs of the given records, in
+// the order in which they first appear, and the distinct values of the
+// records for each of them. Providers that change multiple records at once
+// use it to combine the records for a domain and its wildcard, which share
+// the same FQDN.
+func GroupTXTRecordValues(records []TXTRecord) ([]string, map[string][]string) {
+	var fqdns []string
+	values := make(map[string][]string)
+	seen := make(map[TXTRecord]bool)
+	for _, record := range records {
+		key := TXTRecord{FQDN: record.FQDN, Value: record.Value}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if _, ok := values[record.FQDN]; !ok {
+			fqdns = append(fqdns, record.FQDN)
+		}
+		values[record.FQDN] = append(values[record.FQDN], record.Value)
+	}
+	return fqdns, values
+}
+
 // FindBestMatch returns the longest match for a given domain within a list of domains
 func FindBestMatch(query string, domains ...string) (string, error) {
 	var maxSoFar int
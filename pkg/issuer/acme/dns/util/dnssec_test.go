@@ -0,0 +1,193 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+const testZone = "example.com."
+
+// signedZone is a DNSSEC signed zone served by a fakeValidatingResolver.
+type signedZone struct {
+	key *dns.DNSKEY
+	// records maps names to their TXT records and the signature over them.
+	// A nil signature means the record is not signed.
+	records map[string]signedTXT
+}
+
+type signedTXT struct {
+	txt   *dns.TXT
+	rrsig *dns.RRSIG
+}
+
+func newSignedZone(t *testing.T) (*signedZone, crypto.Signer) {
+	key := &dns.DNSKEY{
+		Hdr:       dns.RR_Header{Name: testZone, Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: 3600},
+		Flags:     257,
+		Protocol:  3,
+		Algorithm: dns.ECDSAP256SHA256,
+	}
+	priv, err := key.Generate(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &signedZone{key: key, records: map[string]signedTXT{}}, priv.(crypto.Signer)
+}
+
+// addTXT adds a TXT record to the zone, signed by the given key. If signer is
+// nil the record is not signed.
+func (z *signedZone) addTXT(t *testing.T, name, value string, key *dns.DNSKEY, signer crypto.Signer) *dns.TXT {
+	txt := &dns.TXT{
+		Hdr: dns.RR_Header{Name: name, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: 60},
+		Txt: []string{value},
+	}
+	rec := signedTXT{txt: txt}
+	if signer != nil {
+		rec.rrsig = &dns.RRSIG{
+			Hdr:         dns.RR_Header{Name: name, Rrtype: dns.TypeRRSIG, Class: dns.ClassINET, Ttl: 60},
+			TypeCovered: dns.TypeTXT,
+			Algorithm:   key.Algorithm,
+			Labels:      uint8(dns.CountLabel(name)),
+			OrigTtl:     60,
+			Expiration:  uint32(time.Now().Add(time.Hour).Unix()),
+			Inception:   uint32(time.Now().Add(-time.Hour).Unix()),
+			KeyTag:      key.KeyTag(),
+			SignerName:  testZone,
+		}
+		if err := rec.rrsig.Sign(signer, []dns.RR{txt}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	z.records[name] = rec
+	return txt
+}
+
+// ServeDNS behaves like a validating recursive resolver for the zone. Answers
+// with a signature that does not verify against the zone's key result in a
+// SERVFAIL, and validated answers have the Authenticated Data bit set if the
+// client sent the DNSSEC OK bit.
+func (z *signedZone) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	m := new(dns.Msg)
+	m.SetReply(req)
+	defer w.WriteMsg(m)
+
+	dnssecOK := req.IsEdns0() != nil && req.IsEdns0().Do()
+
+	rec, ok := z.records[req.Question[0].Name]
+	if !ok || req.Question[0].Qtype != dns.TypeTXT {
+		// denial of existence is authenticated as the zone is signed
+		m.Rcode = dns.RcodeNameError
+		m.AuthenticatedData = dnssecOK
+		return
+	}
+
+	if rec.rrsig != nil {
+		if err := rec.rrsig.Verify(z.key, []dns.RR{rec.txt}); err != nil {
+			m.Rcode = dns.RcodeServerFailure
+			return
+		}
+		m.AuthenticatedData = dnssecOK
+	}
+
+	m.Answer = append(m.Answer, rec.txt)
+	if rec.rrsig != nil && dnssecOK {
+		m.Answer = append(m.Answer, rec.rrsig)
+	}
+}
+
+func startFakeResolver(t *testing.T, handler dns.Handler) (string, func()) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	started := make(chan struct{})
+	srv := &dns.Server{PacketConn: pc, Handler: handler, NotifyStartedFunc: func() { close(started) }}
+	go srv.ActivateAndServe()
+	<-started
+	return pc.LocalAddr().String(), func() { srv.Shutdown() }
+}
+
+func TestCheckDNSSECPropagation(t *testing.T) {
+	zone, signer := newSignedZone(t)
+	zone.addTXT(t, "_acme-challenge.valid.example.com.", "token", zone.key, signer)
+	zone.addTXT(t, "_acme-challenge.unsigned.example.com.", "token", nil, nil)
+
+	// a record signed by a key other than the zone's
+	otherZone, otherSigner := newSignedZone(t)
+	zone.addTXT(t, "_acme-challenge.wrong-key.example.com.", "token", otherZone.key, otherSigner)
+
+	// a record whose value was changed after it was signed
+	spoofed := zone.addTXT(t, "_acme-challenge.spoofed.example.com.", "token", zone.key, signer)
+	spoofed.Txt = []string{"spoofed"}
+
+	ns, stop := startFakeResolver(t, zone)
+	defer stop()
+
+	tests := map[string]struct {
+		fqdn  string
+		value string
+		ok    bool
+		err   bool
+	}{
+		"a validated record with the expected value": {
+			fqdn:  "_acme-challenge.valid.example.com.",
+			value: "token",
+			ok:    true,
+		},
+		"a validated record with a different value": {
+			fqdn:  "_acme-challenge.valid.example.com.",
+			value: "another-token",
+		},
+		"an authenticated denial that the record exists": {
+			fqdn:  "_acme-challenge.missing.example.com.",
+			value: "token",
+		},
+		"a record that is not signed": {
+			fqdn:  "_acme-challenge.unsigned.example.com.",
+			value: "token",
+			err:   true,
+		},
+		"a record signed by the wrong key": {
+			fqdn:  "_acme-challenge.wrong-key.example.com.",
+			value: "token",
+			err:   true,
+		},
+		"a record that was modified after being signed": {
+			fqdn:  "_acme-challenge.spoofed.example.com.",
+			value: "spoofed",
+			err:   true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ok, err := checkDNSSECPropagation(test.fqdn, test.value, []string{ns}, DefaultPropagationThresholdPercent)
+			if test.err != (err != nil) {
+				t.Errorf("unexpected error, exp=%t got=%v", test.err, err)
+			}
+			if ok != test.ok {
+				t.Errorf("unexpected result, exp=%t got=%t", test.ok, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	// dohMediaType is the media type of DNS messages sent and received by
+	// DNS-over-HTTPS endpoints, as defined in RFC 8484.
+	dohMediaType = "application/dns-message"
+
+	// dotScheme is the prefix of nameservers that are queried using
+	// DNS-over-TLS, as defined in RFC 7858.
+	dotScheme = "tls://"
+
+	// dotDefaultPort is the port used for DNS-over-TLS nameservers that are
+	// configured without one.
+	dotDefaultPort = "853"
+)
+
+// dohTransport is the transport used to send requests to DNS-over-HTTPS
+// endpoints. It can be overridden in tests.
+var dohTransport http.RoundTripper = http.DefaultTransport
+
+// isDoHNameserver returns true if the given nameserver is a DNS-over-HTTPS
+// endpoint, such as https://1.1.1.1/dns-query.
+func isDoHNameserver(ns string) bool {
+	return strings.HasPrefix(ns, "https://")
+}
+
+// isDoTNameserver returns true if the given nameserver is to be queried using
+// DNS-over-TLS, such as tls://1.1.1.1:853.
+func isDoTNameserver(ns string) bool {
+	return strings.HasPrefix(ns, dotScheme)
+}
+
+// dohExchange sends the given message to a DNS-over-HTTPS endpoint using a
+// POST request.
+func dohExchange(m *dns.Msg, endpoint string) (*dns.Msg, error) {
+	packed, err := m.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(packed))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", dohMediaType)
+	req.Header.Set("Accept", dohMediaType)
+
+	client := &http.Client{Transport: dohTransport, Timeout: DNSTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("DNS-over-HTTPS endpoint %s returned unexpected status: %s", endpoint, resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, dns.MaxMsgSize))
+	if err != nil {
+		return nil, err
+	}
+
+	in := new(dns.Msg)
+	if err := in.Unpack(body); err != nil {
+		return nil, fmt.Errorf("failed to decode response from DNS-over-HTTPS endpoint %s: %v", endpoint, err)
+	}
+	if in.Id != m.Id {
+		return nil, dns.ErrId
+	}
+
+	return in, nil
+}
+
+// dotExchange sends the given message to a DNS-over-TLS nameserver, given as
+// tls://host[:port].
+func dotExchange(m *dns.Msg, ns string) (*dns.Msg, error) {
+	addr := strings.TrimPrefix(ns, dotScheme)
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, dotDefaultPort)
+	}
+
+	c := &dns.Client{Net: "tcp-tls", Timeout: DNSTimeout}
+	in, _, err := c.Exchange(m, addr)
+	return in, err
+}
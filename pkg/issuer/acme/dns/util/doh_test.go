@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/miekg/dns"
+)
+
+// startFakeDoHEndpoint starts a DNS-over-HTTPS endpoint that answers queries
+// using the given handler, and configures dohTransport to trust it.
+func startFakeDoHEndpoint(t *testing.T, handler dns.Handler) (string, func()) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != dohMediaType {
+			http.Error(w, "unsupported request", http.StatusBadRequest)
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		req := new(dns.Msg)
+		if err := req.Unpack(body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		rw := &fakeDoHResponseWriter{}
+		handler.ServeDNS(rw, req)
+		packed, err := rw.msg.Pack()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", dohMediaType)
+		w.Write(packed)
+	}))
+
+	oldTransport := dohTransport
+	dohTransport = srv.Client().Transport
+	return srv.URL + "/dns-query", func() {
+		dohTransport = oldTransport
+		srv.Close()
+	}
+}
+
+// fakeDoHResponseWriter records the message written by a dns.Handler.
+type fakeDoHResponseWriter struct {
+	dns.ResponseWriter
+	msg *dns.Msg
+}
+
+func (w *fakeDoHResponseWriter) WriteMsg(m *dns.Msg) error {
+	w.msg = m
+	return nil
+}
+
+func TestDNSQueryDoH(t *testing.T) {
+	zone, _ := newSignedZone(t)
+	zone.addTXT(t, "_acme-challenge.example.com.", "token", nil, nil)
+
+	endpoint, stop := startFakeDoHEndpoint(t, zone)
+	defer stop()
+
+	in, err := DNSQuery("_acme-challenge.example.com.", dns.TypeTXT, []string{endpoint}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(in.Answer) != 1 {
+		t.Fatalf("expected 1 answer, got %d", len(in.Answer))
+	}
+	if txt, ok := in.Answer[0].(*dns.TXT); !ok || txt.Txt[0] != "token" {
+		t.Errorf("unexpected answer: %v", in.Answer[0])
+	}
+
+	ok, err := checkAuthoritativeNss("_acme-challenge.example.com.", "token", []string{endpoint}, DefaultPropagationThresholdPercent)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Errorf("expected the TXT record to be found using DNS-over-HTTPS")
+	}
+}
+
+func TestDNSQueryDoHErrorStatus(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	oldTransport := dohTransport
+	dohTransport = srv.Client().Transport
+	defer func() { dohTransport = oldTransport }()
+
+	_, err := DNSQuery("_acme-challenge.example.com.", dns.TypeTXT, []string{srv.URL + "/dns-query"}, true)
+	if err == nil {
+		t.Fatal("expected an error but got none")
+	}
+}
@@ -0,0 +1,322 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-logr/logr"
+
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+// ValidateNameserver checks that the given nameserver is either a host and
+// port, a DNS-over-HTTPS endpoint URL or a DNS-over-TLS server prefixed with
+// tls://.
+func ValidateNameserver(server string) error {
+	switch {
+	case strings.HasPrefix(server, "https://"):
+		u, err := url.Parse(server)
+		if err != nil {
+			return err
+		}
+		if u.Host == "" {
+			return fmt.Errorf("DNS-over-HTTPS endpoint must include a host")
+		}
+		return nil
+	case strings.HasPrefix(server, "tls://"):
+		if strings.TrimPrefix(server, "tls://") == "" {
+			return fmt.Errorf("DNS-over-TLS server must include a host")
+		}
+		return nil
+	default:
+		// ensure all servers have a port number
+		_, _, err := net.SplitHostPort(server)
+		return err
+	}
+}
+
+// NameserverList is a list of nameservers that can be safely replaced while
+// it is in use, so that the nameservers used for DNS01 self checks can be
+// changed without restarting the controller.
+type NameserverList struct {
+	v atomic.Value
+}
+
+// NewNameserverList returns a NameserverList containing the given nameservers.
+func NewNameserverList(nameservers []string) *NameserverList {
+	l := &NameserverList{}
+	l.Set(nameservers)
+	return l
+}
+
+// Get returns the current list of nameservers. It returns nil if the list is
+// nil or has not been set. The returned slice must not be modified.
+func (l *NameserverList) Get() []string {
+	if l == nil {
+		return nil
+	}
+	nameservers, _ := l.v.Load().([]string)
+	return nameservers
+}
+
+// Set atomically replaces the list of nameservers.
+func (l *NameserverList) Set(nameservers []string) {
+	l.v.Store(append([]string{}, nameservers...))
+}
+
+// NameserverFailover tracks the health of the nameservers used for DNS
+// queries. It is disabled unless it is replaced with a NameserverHealth that
+// has a non-zero threshold.
+var NameserverFailover = NewNameserverHealth(0, 0)
+
+// NameserverHealth tracks consecutive failures to query each nameserver, so
+// that a nameserver that keeps failing is skipped in favour of the others
+// until a cooldown has passed. It is safe for concurrent use.
+type NameserverHealth struct {
+	// threshold is the number of consecutive failed queries after which a
+	// nameserver is considered unhealthy. If zero, nameservers are never
+	// considered unhealthy.
+	threshold int
+	// cooldown is how long a nameserver is considered unhealthy for before
+	// it is queried again.
+	cooldown time.Duration
+
+	lock           sync.Mutex
+	failures       map[string]int
+	unhealthyUntil map[string]time.Time
+
+	// now is used for testing purposes.
+	now func() time.Time
+}
+
+// NewNameserverHealth returns a NameserverHealth that considers a nameserver
+// unhealthy for the given cooldown once threshold consecutive queries to it
+// have failed. If threshold is zero, nameservers are never considered
+// unhealthy.
+func NewNameserverHealth(threshold int, cooldown time.Duration) *NameserverHealth {
+	return &NameserverHealth{
+		threshold:      threshold,
+		cooldown:       cooldown,
+		failures:       make(map[string]int),
+		unhealthyUntil: make(map[string]time.Time),
+		now:            time.Now,
+	}
+}
+
+// Enabled returns true if nameservers may be considered unhealthy.
+func (h *NameserverHealth) Enabled() bool {
+	return h.threshold > 0
+}
+
+// RecordSuccess records a successful query to the given nameserver, making it
+// healthy again.
+func (h *NameserverHealth) RecordSuccess(ns string) {
+	if !h.Enabled() {
+		return
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	delete(h.failures, ns)
+	delete(h.unhealthyUntil, ns)
+}
+
+// RecordFailure records a failed query to the given nameserver. Once the
+// threshold of consecutive failures is reached, the nameserver is considered
+// unhealthy until the cooldown has passed. As the count of failures is only
+// reset by a successful query, a nameserver that fails again once the
+// cooldown has passed is immediately considered unhealthy again.
+func (h *NameserverHealth) RecordFailure(ns string) {
+	if !h.Enabled() {
+		return
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.failures[ns]++
+	if h.failures[ns] >= h.threshold {
+		h.unhealthyUntil[ns] = h.now().Add(h.cooldown)
+	}
+}
+
+// Healthy returns the given nameservers that are not currently considered
+// unhealthy, in the same order. If every nameserver is unhealthy, all of them
+// are returned so that queries are still attempted.
+func (h *NameserverHealth) Healthy(nameservers []string) []string {
+	healthy, unhealthy := h.partition(nameservers)
+	if len(healthy) == 0 {
+		return unhealthy
+	}
+	return healthy
+}
+
+// Order returns the given nameservers with those that are currently
+// considered unhealthy moved to the end, keeping their relative order
+// otherwise, so that unhealthy nameservers are only queried once all of the
+// healthy ones have failed.
+func (h *NameserverHealth) Order(nameservers []string) []string {
+	healthy, unhealthy := h.partition(nameservers)
+	return append(healthy, unhealthy...)
+}
+
+func (h *NameserverHealth) partition(nameservers []string) (healthy, unhealthy []string) {
+	if !h.Enabled() {
+		return append([]string{}, nameservers...), nil
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	now := h.now()
+	for _, ns := range nameservers {
+		if until, ok := h.unhealthyUntil[ns]; ok && now.Before(until) {
+			unhealthy = append(unhealthy, ns)
+			continue
+		}
+		healthy = append(healthy, ns)
+	}
+	return healthy, unhealthy
+}
+
+// ParseNameserversFile parses the contents of a nameservers file, which
+// contains one nameserver per line in any of the forms accepted by
+// ValidateNameserver. Blank lines and lines starting with '#' are ignored.
+// Invalid lines are logged and skipped.
+func ParseNameserversFile(log logr.Logger, data []byte) []string {
+	var nameservers []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		server := strings.TrimSpace(scanner.Text())
+		if server == "" || strings.HasPrefix(server, "#") {
+			continue
+		}
+		if err := ValidateNameserver(server); err != nil {
+			log.Error(err, "skipping invalid DNS server in nameservers file", "line", line, "server", server)
+			continue
+		}
+		nameservers = append(nameservers, server)
+	}
+	return nameservers
+}
+
+// MergeNameservers returns the nameservers in 'fromFile' followed by those in
+// 'fromFlag' that are not also in 'fromFile'. If both are empty, the default
+// RecursiveNameservers are returned.
+func MergeNameservers(fromFile, fromFlag []string) []string {
+	seen := make(map[string]bool, len(fromFile)+len(fromFlag))
+	var merged []string
+	for _, servers := range [][]string{fromFile, fromFlag} {
+		for _, server := range servers {
+			if seen[server] {
+				continue
+			}
+			seen[server] = true
+			merged = append(merged, server)
+		}
+	}
+	if len(merged) == 0 {
+		return RecursiveNameservers
+	}
+	return merged
+}
+
+// NameserversFileWatcher keeps a NameserverList up to date with the contents
+// of a nameservers file, merged with a static list of nameservers.
+type NameserversFileWatcher struct {
+	// Path is the path of the nameservers file.
+	Path string
+	// Static is the list of nameservers given on the command line, which are
+	// used in addition to those in the file.
+	Static []string
+	// List is updated whenever the nameservers file changes.
+	List *NameserverList
+	// Log is used to log changes to the nameservers and invalid entries.
+	Log logr.Logger
+}
+
+// Load reads the nameservers file and updates the list of nameservers. If the
+// file cannot be read, or if it contains no valid nameservers once the list
+// has been populated, an error is returned and the list is left unchanged.
+// The latter ensures that a file which is observed while it is being written
+// does not replace the configured nameservers with just the static ones.
+func (w *NameserversFileWatcher) Load() error {
+	data, err := ioutil.ReadFile(w.Path)
+	if err != nil {
+		return fmt.Errorf("error reading nameservers file: %w", err)
+	}
+
+	fromFile := ParseNameserversFile(w.Log, data)
+	if len(fromFile) == 0 && len(w.List.Get()) > 0 {
+		return fmt.Errorf("nameservers file %q contains no valid nameservers", w.Path)
+	}
+
+	nameservers := MergeNameservers(fromFile, w.Static)
+	if reflect.DeepEqual(nameservers, w.List.Get()) {
+		return nil
+	}
+	w.List.Set(nameservers)
+	w.Log.V(logf.InfoLevel).Info("configured acme dns01 nameservers", "nameservers", nameservers, "path", w.Path)
+	return nil
+}
+
+// Run watches the nameservers file for changes until stopCh is closed,
+// reloading it whenever it changes. The directory containing the file is
+// watched rather than the file itself, so that files that are replaced rather
+// than written to, such as those mounted from a ConfigMap, are also reloaded.
+func (w *NameserversFileWatcher) Run(stopCh <-chan struct{}) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(filepath.Dir(w.Path)); err != nil {
+		return fmt.Errorf("error watching nameservers file: %w", err)
+	}
+
+	for {
+		select {
+		case <-stopCh:
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			w.Log.V(logf.DebugLevel).Info("nameservers file directory changed", "event", event.String())
+			if err := w.Load(); err != nil {
+				w.Log.Error(err, "failed to reload nameservers file, keeping the current nameservers")
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			w.Log.Error(err, "error watching nameservers file")
+		}
+	}
+}
@@ -0,0 +1,279 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	logtesting "github.com/jetstack/cert-manager/pkg/logs/testing"
+)
+
+func TestValidateNameserver(t *testing.T) {
+	tests := map[string]bool{
+		"8.8.8.8:53":                true,
+		"[2001:4860:4860::8888]:53": true,
+		"https://1.1.1.1/dns-query": true,
+		"tls://1.1.1.1:853":         true,
+		"8.8.8.8":                   false,
+		"https:///dns-query":        false,
+		"tls://":                    false,
+		"2001:4860:4860::8888":      false,
+		"not a nameserver":          false,
+	}
+	for server, valid := range tests {
+		t.Run(server, func(t *testing.T) {
+			err := ValidateNameserver(server)
+			if valid && err != nil {
+				t.Errorf("expected %q to be valid but got error: %v", server, err)
+			}
+			if !valid && err == nil {
+				t.Errorf("expected %q to be invalid but got no error", server)
+			}
+		})
+	}
+}
+
+func TestParseNameserversFile(t *testing.T) {
+	tests := map[string]struct {
+		data     string
+		expected []string
+	}{
+		"empty file": {
+			data:     "",
+			expected: nil,
+		},
+		"one nameserver per line": {
+			data:     "8.8.8.8:53\n1.1.1.1:53\n",
+			expected: []string{"8.8.8.8:53", "1.1.1.1:53"},
+		},
+		"blank lines, comments and surrounding whitespace are ignored": {
+			data:     "# resolvers\n\n  8.8.8.8:53  \n\t\n# 9.9.9.9:53\nhttps://1.1.1.1/dns-query",
+			expected: []string{"8.8.8.8:53", "https://1.1.1.1/dns-query"},
+		},
+		"invalid lines are skipped": {
+			data:     "8.8.8.8\n1.1.1.1:53\ntls://\n",
+			expected: []string{"1.1.1.1:53"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := ParseNameserversFile(logtesting.TestLogger{T: t}, []byte(test.data))
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("expected %v but got %v", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestMergeNameservers(t *testing.T) {
+	tests := map[string]struct {
+		fromFile, fromFlag []string
+		expected           []string
+	}{
+		"defaults are used if neither is set": {
+			expected: RecursiveNameservers,
+		},
+		"flag only": {
+			fromFlag: []string{"8.8.8.8:53"},
+			expected: []string{"8.8.8.8:53"},
+		},
+		"file only": {
+			fromFile: []string{"1.1.1.1:53"},
+			expected: []string{"1.1.1.1:53"},
+		},
+		"file nameservers take precedence and duplicates are removed": {
+			fromFile: []string{"1.1.1.1:53", "8.8.8.8:53"},
+			fromFlag: []string{"8.8.8.8:53", "9.9.9.9:53"},
+			expected: []string{"1.1.1.1:53", "8.8.8.8:53", "9.9.9.9:53"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := MergeNameservers(test.fromFile, test.fromFlag)
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("expected %v but got %v", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestNameserverList(t *testing.T) {
+	var nilList *NameserverList
+	if got := nilList.Get(); got != nil {
+		t.Errorf("expected nil list to return no nameservers but got %v", got)
+	}
+	if got := (&NameserverList{}).Get(); got != nil {
+		t.Errorf("expected unset list to return no nameservers but got %v", got)
+	}
+
+	servers := []string{"8.8.8.8:53"}
+	l := NewNameserverList(servers)
+	servers[0] = "1.1.1.1:53"
+	if got := l.Get(); !reflect.DeepEqual(got, []string{"8.8.8.8:53"}) {
+		t.Errorf("expected list to be unaffected by changes to the input slice but got %v", got)
+	}
+}
+
+func TestNameserverHealth(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	h := NewNameserverHealth(2, time.Minute)
+	h.now = func() time.Time { return now }
+
+	servers := []string{"1.1.1.1:53", "8.8.8.8:53", "9.9.9.9:53"}
+	assertOrder := func(exp ...string) {
+		t.Helper()
+		if got := h.Order(servers); !reflect.DeepEqual(got, exp) {
+			t.Errorf("unexpected order, exp=%v got=%v", exp, got)
+		}
+	}
+	assertHealthy := func(exp ...string) {
+		t.Helper()
+		if got := h.Healthy(servers); !reflect.DeepEqual(got, exp) {
+			t.Errorf("unexpected healthy nameservers, exp=%v got=%v", exp, got)
+		}
+	}
+
+	h.RecordFailure("1.1.1.1:53")
+	assertOrder("1.1.1.1:53", "8.8.8.8:53", "9.9.9.9:53")
+
+	h.RecordFailure("1.1.1.1:53")
+	assertOrder("8.8.8.8:53", "9.9.9.9:53", "1.1.1.1:53")
+	assertHealthy("8.8.8.8:53", "9.9.9.9:53")
+
+	h.RecordFailure("8.8.8.8:53")
+	h.RecordFailure("8.8.8.8:53")
+	h.RecordFailure("9.9.9.9:53")
+	h.RecordFailure("9.9.9.9:53")
+	// all nameservers are returned if none are healthy
+	assertHealthy("1.1.1.1:53", "8.8.8.8:53", "9.9.9.9:53")
+
+	h.RecordSuccess("9.9.9.9:53")
+	assertOrder("9.9.9.9:53", "1.1.1.1:53", "8.8.8.8:53")
+
+	// once the cooldown has passed a nameserver is tried again, but a single
+	// failure makes it unhealthy again
+	now = now.Add(time.Minute)
+	assertOrder("1.1.1.1:53", "8.8.8.8:53", "9.9.9.9:53")
+	h.RecordFailure("1.1.1.1:53")
+	assertOrder("8.8.8.8:53", "9.9.9.9:53", "1.1.1.1:53")
+
+	disabled := NewNameserverHealth(0, time.Minute)
+	for i := 0; i < 5; i++ {
+		disabled.RecordFailure("1.1.1.1:53")
+	}
+	if got := disabled.Order(servers); !reflect.DeepEqual(got, servers) {
+		t.Errorf("expected nameservers to never be unhealthy if disabled but got %v", got)
+	}
+}
+
+func TestNameserversFileWatcher(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-nameservers-file-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	path := filepath.Join(dir, "nameservers")
+	// writeFile replaces the nameservers file atomically, as it would be if
+	// mounted from a ConfigMap
+	writeFile := func(data string) error {
+		f, err := ioutil.TempFile(dir, ".nameservers-")
+		if err != nil {
+			return err
+		}
+		if _, err := f.WriteString(data); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		return os.Rename(f.Name(), path)
+	}
+	if err := writeFile("1.1.1.1:53\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	list := &NameserverList{}
+	watcher := &NameserversFileWatcher{
+		Path:   path,
+		Static: []string{"8.8.8.8:53"},
+		List:   list,
+		Log:    logtesting.TestLogger{T: t},
+	}
+	if err := watcher.Load(); err != nil {
+		t.Fatalf("unexpected error loading nameservers file: %v", err)
+	}
+	if got, exp := list.Get(), []string{"1.1.1.1:53", "8.8.8.8:53"}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected %v but got %v", exp, got)
+	}
+
+	stopCh := make(chan struct{})
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- watcher.Run(stopCh)
+	}()
+	defer func() {
+		close(stopCh)
+		if err := <-errCh; err != nil {
+			t.Errorf("unexpected error from watcher: %v", err)
+		}
+	}()
+
+	// write the file until the change is observed, as the watcher may not
+	// have started watching the directory before the first write
+	exp := []string{"9.9.9.9:53", "8.8.8.8:53"}
+	err = wait.PollImmediate(100*time.Millisecond, 5*time.Second, func() (bool, error) {
+		if err := writeFile("9.9.9.9:53\ninvalid\n"); err != nil {
+			return false, err
+		}
+		return reflect.DeepEqual(list.Get(), exp), nil
+	})
+	if err != nil {
+		t.Errorf("expected nameservers to be reloaded as %v but got %v: %v", exp, list.Get(), err)
+	}
+
+	// a file without any valid nameservers, such as one that is observed
+	// before it has been written to, leaves the current nameservers in place
+	if err := ioutil.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if got := list.Get(); !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected nameservers to be unchanged after the file was emptied, expected %v but got %v", exp, got)
+	}
+
+	// removing the file leaves the current nameservers in place
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if got := list.Get(); !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected nameservers to be unchanged after the file was removed, expected %v but got %v", exp, got)
+	}
+}
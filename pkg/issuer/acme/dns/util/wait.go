@@ -21,7 +21,8 @@ import (
 )
 
 type preCheckDNSFunc func(fqdn, value string, nameservers []string,
-	useAuthoritative bool) (bool, error)
+	useAuthoritative bool, thresholdPercent int) (bool, error)
+type preCheckDNSSECFunc func(fqdn, value string, nameservers []string, thresholdPercent int) (bool, error)
 type dnsQueryFunc func(fqdn string, rtype uint16, nameservers []string, recursive bool) (in *dns.Msg, err error)
 
 var (
@@ -29,6 +30,11 @@ var (
 	// the DNS challenge is ready.
 	PreCheckDNS preCheckDNSFunc = checkDNSPropagation
 
+	// PreCheckDNSSEC checks DNS propagation before notifying ACME that the
+	// DNS challenge is ready, requiring the record to be DNSSEC validated by
+	// the given recursive nameservers.
+	PreCheckDNSSEC preCheckDNSSECFunc = checkDNSSECPropagation
+
 	// dnsQuery is used to be able to mock DNSQuery
 	dnsQuery dnsQueryFunc = DNSQuery
 
@@ -38,6 +44,11 @@ var (
 
 const defaultResolvConf = "/etc/resolv.conf"
 
+// DefaultPropagationThresholdPercent is the percentage of nameservers that
+// must return the expected record for a DNS propagation check to succeed if
+// no other threshold is configured.
+const DefaultPropagationThresholdPercent = 100
+
 const issueTag = "issue"
 const issuewildTag = "issuewild"
 
@@ -100,9 +111,10 @@ func followCNAMEs(fqdn string, nameservers []string, fqdnChain ...string) (strin
 	return fqdn, nil
 }
 
-// checkDNSPropagation checks if the expected TXT record has been propagated to all authoritative nameservers.
+// checkDNSPropagation checks if the expected TXT record has been propagated
+// to at least thresholdPercent of the authoritative nameservers.
 func checkDNSPropagation(fqdn, value string, nameservers []string,
-	useAuthoritative bool) (bool, error) {
+	useAuthoritative bool, thresholdPercent int) (bool, error) {
 
 	var err error
 	fqdn, err = followCNAMEs(fqdn, nameservers)
@@ -111,7 +123,10 @@ func checkDNSPropagation(fqdn, value string, nameservers []string,
 	}
 
 	if !useAuthoritative {
-		return checkAuthoritativeNss(fqdn, value, nameservers)
+		if NameserverFailover.Enabled() {
+			return checkRecursiveNss(fqdn, value, nameservers, thresholdPercent)
+		}
+		return checkAuthoritativeNss(fqdn, value, nameservers, thresholdPercent)
 	}
 
 	authoritativeNss, err := lookupNameservers(fqdn, nameservers)
@@ -122,11 +137,27 @@ func checkDNSPropagation(fqdn, value string, nameservers []string,
 	for i, ans := range authoritativeNss {
 		authoritativeNss[i] = net.JoinHostPort(ans, "53")
 	}
-	return checkAuthoritativeNss(fqdn, value, authoritativeNss)
+	return checkAuthoritativeNss(fqdn, value, authoritativeNss, thresholdPercent)
+}
+
+// allowedPropagationMisses returns the number of nameservers, out of total,
+// that may not yet return the expected record for a propagation check with
+// the given threshold to succeed. Thresholds outside of 1 to 100 percent are
+// treated as 100 percent.
+func allowedPropagationMisses(total, thresholdPercent int) int {
+	if thresholdPercent < 1 || thresholdPercent > 100 {
+		thresholdPercent = 100
+	}
+	required := (total*thresholdPercent + 99) / 100
+	return total - required
 }
 
-// checkAuthoritativeNss queries each of the given nameservers for the expected TXT record.
-func checkAuthoritativeNss(fqdn, value string, nameservers []string) (bool, error) {
+// checkAuthoritativeNss queries each of the given nameservers for the
+// expected TXT record, succeeding if at least thresholdPercent of them return
+// it.
+func checkAuthoritativeNss(fqdn, value string, nameservers []string, thresholdPercent int) (bool, error) {
+	allowedMisses := allowedPropagationMisses(len(nameservers), thresholdPercent)
+	misses := 0
 	for _, ns := range nameservers {
 		r, err := DNSQuery(fqdn, dns.TypeTXT, []string{ns}, true)
 		if err != nil {
@@ -150,7 +181,107 @@ func checkAuthoritativeNss(fqdn, value string, nameservers []string) (bool, erro
 		}
 
 		if !found {
-			return false, nil
+			logf.V(logf.DebugLevel).Infof("NS %s did not return the expected TXT record for %q", ns, fqdn)
+			if misses++; misses > allowedMisses {
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// checkRecursiveNss queries the given recursive nameservers for the expected
+// TXT record as a pool, succeeding if at least thresholdPercent of those that
+// respond return it. Nameservers that are considered unhealthy by
+// NameserverFailover are skipped while any others are healthy, and
+// nameservers that fail to respond are left out of the threshold. An error is
+// only returned if every nameserver that is queried fails.
+func checkRecursiveNss(fqdn, value string, nameservers []string, thresholdPercent int) (bool, error) {
+	var lastErr error
+	responded, misses := 0, 0
+	for _, ns := range NameserverFailover.Healthy(nameservers) {
+		r, err := DNSQuery(fqdn, dns.TypeTXT, []string{ns}, true)
+		if err == nil && !(r.Rcode == dns.RcodeSuccess || r.Rcode == dns.RcodeNameError) {
+			err = fmt.Errorf("NS %s returned %s for %s", ns, dns.RcodeToString[r.Rcode], fqdn)
+		}
+		if err != nil {
+			logf.V(logf.DebugLevel).Infof("NS %s failed to look up TXT records for %q, trying the remaining nameservers: %v", ns, fqdn, err)
+			lastErr = err
+			continue
+		}
+		responded++
+
+		var found bool
+		for _, rr := range r.Answer {
+			if txt, ok := rr.(*dns.TXT); ok {
+				if strings.Join(txt.Txt, "") == value {
+					found = true
+					break
+				}
+			}
+		}
+
+		if !found {
+			logf.V(logf.DebugLevel).Infof("NS %s did not return the expected TXT record for %q", ns, fqdn)
+			misses++
+		}
+	}
+
+	if responded == 0 {
+		return false, fmt.Errorf("all nameservers failed to look up TXT records for %s, last error: %v", fqdn, lastErr)
+	}
+
+	return misses <= allowedPropagationMisses(responded, thresholdPercent), nil
+}
+
+// checkDNSSECPropagation queries each of the given recursive nameservers for
+// the expected TXT record, requiring the answer to have been DNSSEC validated
+// and succeeding if at least thresholdPercent of them return it.
+// CNAMEs are followed by the nameservers themselves, so that every record in
+// the chain is validated.
+func checkDNSSECPropagation(fqdn, value string, nameservers []string, thresholdPercent int) (bool, error) {
+	allowedMisses := allowedPropagationMisses(len(nameservers), thresholdPercent)
+	misses := 0
+	for _, ns := range nameservers {
+		r, err := dnssecQuery(fqdn, dns.TypeTXT, []string{ns})
+		if err != nil {
+			return false, err
+		}
+
+		// Validating resolvers return SERVFAIL when the answer has a bogus
+		// signature
+		if r.Rcode == dns.RcodeServerFailure {
+			return false, fmt.Errorf("NS %s returned %s for %s, which may be because the DNSSEC signature of the record is invalid",
+				ns, dns.RcodeToString[r.Rcode], fqdn)
+		}
+
+		// NXDomain response is not really an error, just waiting for propagation to happen
+		if !(r.Rcode == dns.RcodeSuccess || r.Rcode == dns.RcodeNameError) {
+			return false, fmt.Errorf("NS %s returned %s for %s", ns, dns.RcodeToString[r.Rcode], fqdn)
+		}
+
+		if !r.AuthenticatedData {
+			return false, fmt.Errorf("NS %s did not DNSSEC validate the answer for %s, the zone must be signed and the nameserver must perform DNSSEC validation",
+				ns, fqdn)
+		}
+
+		logf.V(logf.DebugLevel).Infof("Looking up DNSSEC validated TXT records for %q", fqdn)
+		var found bool
+		for _, rr := range r.Answer {
+			if txt, ok := rr.(*dns.TXT); ok {
+				if strings.Join(txt.Txt, "") == value {
+					found = true
+					break
+				}
+			}
+		}
+
+		if !found {
+			logf.V(logf.DebugLevel).Infof("NS %s did not return the expected DNSSEC validated TXT record for %q", ns, fqdn)
+			if misses++; misses > allowedMisses {
+				return false, nil
+			}
 		}
 	}
 
@@ -159,6 +290,8 @@ func checkAuthoritativeNss(fqdn, value string, nameservers []string) (bool, erro
 
 // DNSQuery will query a nameserver, iterating through the supplied servers as it retries
 // The nameserver should include a port, to facilitate testing where we talk to a mock dns server.
+// Nameservers may also be given as a DNS-over-HTTPS endpoint URL, such as
+// https://1.1.1.1/dns-query, or as a DNS-over-TLS server, such as tls://1.1.1.1:853.
 func DNSQuery(fqdn string, rtype uint16, nameservers []string, recursive bool) (in *dns.Msg, err error) {
 	m := new(dns.Msg)
 	m.SetQuestion(fqdn, rtype)
@@ -168,23 +301,70 @@ func DNSQuery(fqdn string, rtype uint16, nameservers []string, recursive bool) (
 		m.RecursionDesired = false
 	}
 
-	// Will retry the request based on the number of servers (n+1)
-	for i := 1; i <= len(nameservers)+1; i++ {
-		ns := nameservers[i%len(nameservers)]
-		udp := &dns.Client{Net: "udp", Timeout: DNSTimeout}
-		in, _, err = udp.Exchange(m, ns)
+	return exchange(m, nameservers)
+}
 
-		if (in != nil && in.Truncated) ||
-			(err != nil && strings.HasPrefix(err.Error(), "read udp") && strings.HasSuffix(err.Error(), "i/o timeout")) {
-			logf.V(logf.DebugLevel).Infof("UDP dns lookup failed, retrying with TCP: %v", err)
-			tcp := &dns.Client{Net: "tcp", Timeout: DNSTimeout}
-			// If the TCP request succeeds, the err will reset to nil
-			in, _, err = tcp.Exchange(m, ns)
-		}
+// dnssecQuery performs a recursive query with the DNSSEC OK bit set, asking
+// the nameserver to validate the answer and report the result in the
+// Authenticated Data bit.
+func dnssecQuery(fqdn string, rtype uint16, nameservers []string) (*dns.Msg, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(fqdn, rtype)
+	m.SetEdns0(4096, true)
+	m.AuthenticatedData = true
 
+	return exchange(m, nameservers)
+}
+
+// exchange sends the given message to a nameserver, iterating through the
+// supplied servers as it retries. If NameserverFailover is enabled, the
+// servers are tried in order with those that are unhealthy tried last, and
+// the result of each attempt is recorded.
+func exchange(m *dns.Msg, nameservers []string) (in *dns.Msg, err error) {
+	start := 1
+	if NameserverFailover.Enabled() {
+		nameservers = NameserverFailover.Order(nameservers)
+		start = 0
+	}
+
+	// Will retry the request based on the number of servers (n+1)
+	for i := start; i <= len(nameservers)+start; i++ {
+		ns := nameservers[i%len(nameservers)]
+		in, err = exchangeNameserver(m, ns)
 		if err == nil {
+			NameserverFailover.RecordSuccess(ns)
 			break
 		}
+		NameserverFailover.RecordFailure(ns)
+	}
+	return
+}
+
+// exchangeNameserver sends the given message to a single nameserver using
+// the protocol given by its form.
+func exchangeNameserver(m *dns.Msg, ns string) (*dns.Msg, error) {
+	switch {
+	case isDoHNameserver(ns):
+		return dohExchange(m, ns)
+	case isDoTNameserver(ns):
+		return dotExchange(m, ns)
+	default:
+		return plainExchange(m, ns)
+	}
+}
+
+// plainExchange sends the given message to a nameserver over UDP, retrying
+// over TCP if the response is truncated or the UDP request times out.
+func plainExchange(m *dns.Msg, ns string) (in *dns.Msg, err error) {
+	udp := &dns.Client{Net: "udp", Timeout: DNSTimeout}
+	in, _, err = udp.Exchange(m, ns)
+
+	if (in != nil && in.Truncated) ||
+		(err != nil && strings.HasPrefix(err.Error(), "read udp") && strings.HasSuffix(err.Error(), "i/o timeout")) {
+		logf.V(logf.DebugLevel).Infof("UDP dns lookup failed, retrying with TCP: %v", err)
+		tcp := &dns.Client{Net: "tcp", Timeout: DNSTimeout}
+		// If the TCP request succeeds, the err will reset to nil
+		in, _, err = tcp.Exchange(m, ns)
 	}
 	return
 }
@@ -389,6 +569,21 @@ func FindZoneByFqdn(fqdn string, nameservers []string) (string, error) {
 	return "", fmt.Errorf("Could not find the SOA record in the DNS tree for the domain '%s' using nameservers %v", fqdn, nameservers)
 }
 
+// FindZoneByFqdnOrOverride returns the given zone in fqdn form if it is set,
+// skipping any nameserver queries. The fqdn must be within that zone.
+// If zone is empty, the zone is detected using FindZoneByFqdn.
+func FindZoneByFqdnOrOverride(fqdn, zone string, nameservers []string) (string, error) {
+	if zone == "" {
+		return FindZoneByFqdn(fqdn, nameservers)
+	}
+
+	zone = ToFqdn(zone)
+	if !dns.IsSubDomain(zone, fqdn) {
+		return "", fmt.Errorf("fqdn %q is not within the configured zone %q", fqdn, zone)
+	}
+	return zone, nil
+}
+
 // dnsMsgContainsCNAME checks for a CNAME answer in msg
 func dnsMsgContainsCNAME(msg *dns.Msg) bool {
 	for _, ans := range msg.Answer {
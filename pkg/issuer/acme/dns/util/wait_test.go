@@ -10,10 +10,12 @@ package util
 
 import (
 	"fmt"
+	"net"
 	"reflect"
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/miekg/dns"
 )
@@ -167,7 +169,7 @@ func TestMatchCAA(t *testing.T) {
 
 func TestPreCheckDNS(t *testing.T) {
 	// TODO: find a better TXT record to use in tests
-	ok, err := PreCheckDNS("google.com.", "v=spf1 include:_spf.google.com ~all", []string{"8.8.8.8:53"}, true)
+	ok, err := PreCheckDNS("google.com.", "v=spf1 include:_spf.google.com ~all", []string{"8.8.8.8:53"}, true, DefaultPropagationThresholdPercent)
 	if err != nil || !ok {
 		t.Errorf("preCheckDNS failed for acme-staging.api.letsencrypt.org: %s", err.Error())
 	}
@@ -175,7 +177,7 @@ func TestPreCheckDNS(t *testing.T) {
 
 func TestPreCheckDNSNonAuthoritative(t *testing.T) {
 	// TODO: find a better TXT record to use in tests
-	ok, err := PreCheckDNS("google.com.", "v=spf1 include:_spf.google.com ~all", []string{"1.1.1.1:53"}, false)
+	ok, err := PreCheckDNS("google.com.", "v=spf1 include:_spf.google.com ~all", []string{"1.1.1.1:53"}, false, DefaultPropagationThresholdPercent)
 	if err != nil || !ok {
 		t.Errorf("preCheckDNS failed for acme-staging.api.letsencrypt.org: %s", err.Error())
 	}
@@ -223,9 +225,52 @@ func TestFindZoneByFqdn(t *testing.T) {
 	}
 }
 
+func TestFindZoneByFqdnOrOverride(t *testing.T) {
+	// no nameservers are reachable, so zone detection would fail for all of
+	// these fqdns if the configured zone was not used.
+	nameservers := []string{"127.0.0.1:1"}
+	tests := map[string]struct {
+		fqdn, zone string
+		expected   string
+		expectErr  bool
+	}{
+		"zone in fqdn form": {
+			fqdn:     "_acme-challenge.www.example.invalid.",
+			zone:     "example.invalid.",
+			expected: "example.invalid.",
+		},
+		"zone without a trailing dot": {
+			fqdn:     "_acme-challenge.example.invalid.",
+			zone:     "example.invalid",
+			expected: "example.invalid.",
+		},
+		"fqdn outside of the zone": {
+			fqdn:      "_acme-challenge.example.invalid.",
+			zone:      "other.invalid.",
+			expectErr: true,
+		},
+		"fqdn that only shares a suffix with the zone": {
+			fqdn:      "_acme-challenge.notexample.invalid.",
+			zone:      "example.invalid.",
+			expectErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			zone, err := FindZoneByFqdnOrOverride(test.fqdn, test.zone, nameservers)
+			if err != nil != test.expectErr {
+				t.Fatalf("expected error=%t, got: %v", test.expectErr, err)
+			}
+			if zone != test.expected {
+				t.Errorf("expected zone %q, got %q", test.expected, zone)
+			}
+		})
+	}
+}
+
 func TestCheckAuthoritativeNss(t *testing.T) {
 	for _, tt := range checkAuthoritativeNssTests {
-		ok, _ := checkAuthoritativeNss(tt.fqdn, tt.value, tt.ns)
+		ok, _ := checkAuthoritativeNss(tt.fqdn, tt.value, tt.ns, DefaultPropagationThresholdPercent)
 		if ok != tt.ok {
 			t.Errorf("%s: got %t; want %t", tt.fqdn, ok, tt.ok)
 		}
@@ -234,7 +279,7 @@ func TestCheckAuthoritativeNss(t *testing.T) {
 
 func TestCheckAuthoritativeNssErr(t *testing.T) {
 	for _, tt := range checkAuthoritativeNssTestsErr {
-		_, err := checkAuthoritativeNss(tt.fqdn, tt.value, tt.ns)
+		_, err := checkAuthoritativeNss(tt.fqdn, tt.value, tt.ns, DefaultPropagationThresholdPercent)
 		if err == nil {
 			t.Fatalf("#%s: expected %q (error); got <nil>", tt.fqdn, tt.error)
 		}
@@ -245,6 +290,97 @@ func TestCheckAuthoritativeNssErr(t *testing.T) {
 	}
 }
 
+func TestCheckAuthoritativeNssThreshold(t *testing.T) {
+	const fqdn = "_acme-challenge.example.com."
+
+	propagated, _ := newSignedZone(t)
+	propagated.addTXT(t, fqdn, "token", nil, nil)
+	notPropagated, _ := newSignedZone(t)
+
+	var nameservers []string
+	for _, zone := range []*signedZone{propagated, propagated, propagated, notPropagated} {
+		ns, stop := startFakeResolver(t, zone)
+		defer stop()
+		nameservers = append(nameservers, ns)
+	}
+
+	tests := map[string]struct {
+		threshold int
+		ok        bool
+	}{
+		"all nameservers are required by default": {
+			threshold: DefaultPropagationThresholdPercent,
+			ok:        false,
+		},
+		"a threshold met by the propagated nameservers": {
+			threshold: 75,
+			ok:        true,
+		},
+		"a threshold that requires more than the propagated nameservers": {
+			threshold: 80,
+			ok:        false,
+		},
+		"an out of range threshold requires all nameservers": {
+			threshold: 0,
+			ok:        false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ok, err := checkAuthoritativeNss(fqdn, "token", nameservers, test.threshold)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != test.ok {
+				t.Errorf("unexpected result, exp=%t got=%t", test.ok, ok)
+			}
+		})
+	}
+}
+
+func TestCheckRecursiveNssFailover(t *testing.T) {
+	const fqdn = "_acme-challenge.example.com."
+
+	defer func(timeout time.Duration, health *NameserverHealth) {
+		DNSTimeout = timeout
+		NameserverFailover = health
+	}(DNSTimeout, NameserverFailover)
+	DNSTimeout = 100 * time.Millisecond
+
+	propagated, _ := newSignedZone(t)
+	propagated.addTXT(t, fqdn, "token", nil, nil)
+	healthy, stop := startFakeResolver(t, propagated)
+	defer stop()
+
+	// a nameserver that never responds
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+	unresponsive := pc.LocalAddr().String()
+
+	NameserverFailover = NewNameserverHealth(2, time.Hour)
+
+	ok, err := checkDNSPropagation(fqdn, "token", []string{unresponsive, healthy}, false, DefaultPropagationThresholdPercent)
+	if err != nil {
+		t.Fatalf("expected the self check to succeed using the responsive nameserver but got error: %v", err)
+	}
+	if !ok {
+		t.Errorf("expected the record to be found by the responsive nameserver")
+	}
+
+	if got := NameserverFailover.Healthy([]string{unresponsive, healthy}); !reflect.DeepEqual(got, []string{healthy}) {
+		t.Errorf("expected the unresponsive nameserver to be unhealthy, healthy nameservers are %v", got)
+	}
+
+	_, err = checkDNSPropagation(fqdn, "token", []string{unresponsive}, false, DefaultPropagationThresholdPercent)
+	if err == nil {
+		t.Errorf("expected the self check to fail if every nameserver fails")
+	}
+}
+
 func TestResolveConfServers(t *testing.T) {
 	for _, tt := range checkResolvConfServersTests {
 		result := getNameservers(tt.fixture, tt.defaults)
@@ -65,7 +65,7 @@ type Solver struct {
 	requiredPasses   int
 }
 
-type reachabilityTest func(ctx context.Context, url *url.URL, key string) error
+type reachabilityTest func(ctx context.Context, url *url.URL, key string, network string) error
 
 // NewSolver returns a new ACME HTTP01 solver for the given Issuer and client.
 // TODO: refactor this to have fewer args
@@ -98,6 +98,10 @@ func httpDomainCfgForChallenge(ch *cmacme.Challenge) (*cmacme.ACMEChallengeSolve
 func (s *Solver) Present(ctx context.Context, issuer v1.GenericIssuer, ch *cmacme.Challenge) error {
 	ctx = http01LogCtx(ctx)
 
+	if s.ACMEOptions.HTTP01SolverSharedDeployment {
+		return s.presentShared(ctx, ch)
+	}
+
 	_, podErr := s.ensurePod(ctx, ch)
 	svc, svcErr := s.ensureService(ctx, ch)
 	if svcErr != nil {
@@ -128,12 +132,13 @@ func (s *Solver) Check(ctx context.Context, issuer v1.GenericIssuer, ch *cmacme.
 	ctx, cancel := context.WithTimeout(ctx, HTTP01Timeout)
 	defer cancel()
 	url := s.buildChallengeUrl(ch)
-	log = log.WithValues("url", url)
+	network := selfCheckNetwork(ch)
+	log = log.WithValues("url", url, "network", network)
 	ctx = logf.NewContext(ctx, log)
 
 	log.V(logf.DebugLevel).Info("running self check multiple times to ensure challenge has propagated", "required_passes", s.requiredPasses)
 	for i := 0; i < s.requiredPasses; i++ {
-		err := s.testReachability(ctx, url, ch.Spec.Key)
+		err := s.testReachability(ctx, url, ch.Spec.Key, network)
 		if err != nil {
 			return err
 		}
@@ -147,9 +152,18 @@ func (s *Solver) Check(ctx context.Context, issuer v1.GenericIssuer, ch *cmacme.
 }
 
 // CleanUp will ensure the created service, ingress and pod are clean/deleted of any
-// cert-manager created data.
+// cert-manager created data. If a shared solver is in use, the challenge's
+// token is removed from it but the shared resources are left in place. If a
+// shared ingress is in use, the challenge's path is removed from it and the
+// ingress is deleted once no other challenges are routed through it.
 func (s *Solver) CleanUp(ctx context.Context, issuer v1.GenericIssuer, ch *cmacme.Challenge) error {
 	var errs []error
+	if s.ACMEOptions.HTTP01SolverSharedDeployment {
+		errs = append(errs, s.cleanupShared(ctx, ch))
+	}
+	if s.ACMEOptions.HTTP01SolverSharedIngress {
+		errs = append(errs, s.cleanupSharedIngress(ctx, ch))
+	}
 	errs = append(errs, s.cleanupPods(ctx, ch))
 	errs = append(errs, s.cleanupServices(ctx, ch))
 	errs = append(errs, s.cleanupIngresses(ctx, ch))
@@ -170,9 +184,20 @@ func (s *Solver) buildChallengeUrl(ch *cmacme.Challenge) *url.URL {
 	return url
 }
 
-// testReachability will attempt to connect to the 'domain' with 'path' and
-// check if the returned body equals 'key'
-func testReachability(ctx context.Context, url *url.URL, key string) error {
+// selfCheckNetwork returns the network that the self check for the given
+// challenge should connect over, as accepted by net.Dial. If the solver is
+// configured to force IPv6, only IPv6 addresses are used. Otherwise both IPv4
+// and IPv6 addresses are tried.
+func selfCheckNetwork(ch *cmacme.Challenge) string {
+	if ch.Spec.Solver.HTTP01 != nil && ch.Spec.Solver.HTTP01.Ingress != nil && ch.Spec.Solver.HTTP01.Ingress.ForceIPv6 {
+		return "tcp6"
+	}
+	return "tcp"
+}
+
+// testReachability will attempt to connect to the 'domain' with 'path' over
+// the given 'network' and check if the returned body equals 'key'
+func testReachability(ctx context.Context, url *url.URL, key string, network string) error {
 	log := logf.FromContext(ctx)
 	log.V(logf.DebugLevel).Info("performing HTTP01 reachability check")
 
@@ -188,8 +213,17 @@ func testReachability(ctx context.Context, url *url.URL, key string) error {
 	// certificate after all).
 	// TODO(dmo): figure out if we need to add a more specific timeout for
 	// individual checks
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
 	transport := &http.Transport{
 		Proxy: http.ProxyFromEnvironment,
+		// the network is overridden so that the self check can be restricted
+		// to IPv6, in which case only the AAAA records of the domain are used
+		DialContext: func(ctx context.Context, _, addr string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, addr)
+		},
 		// we're only doing 1 request, make the code around this
 		// simpler by disabling keepalives
 		DisableKeepAlives: true,
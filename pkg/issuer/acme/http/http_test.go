@@ -19,6 +19,9 @@ package http
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 
@@ -28,9 +31,9 @@ import (
 // countReachabilityTestCalls is a wrapper function that allows us to count the number
 // of calls to a reachabilityTest.
 func countReachabilityTestCalls(counter *int, t reachabilityTest) reachabilityTest {
-	return func(ctx context.Context, url *url.URL, key string) error {
+	return func(ctx context.Context, url *url.URL, key string, network string) error {
 		*counter++
-		return t(ctx, url, key)
+		return t(ctx, url, key, network)
 	}
 }
 
@@ -44,18 +47,37 @@ func TestCheck(t *testing.T) {
 	tests := []testT{
 		{
 			name: "should pass",
-			reachabilityTest: func(context.Context, *url.URL, string) error {
+			reachabilityTest: func(context.Context, *url.URL, string, string) error {
 				return nil
 			},
 			expectedErr: false,
 		},
 		{
 			name: "should error",
-			reachabilityTest: func(context.Context, *url.URL, string) error {
+			reachabilityTest: func(context.Context, *url.URL, string, string) error {
 				return fmt.Errorf("failed")
 			},
 			expectedErr: true,
 		},
+		{
+			name: "should check over IPv6 if the solver forces IPv6",
+			reachabilityTest: func(_ context.Context, _ *url.URL, _ string, network string) error {
+				if network != "tcp6" {
+					return fmt.Errorf("expected self check over tcp6 but got %q", network)
+				}
+				return nil
+			},
+			challenge: &cmacme.Challenge{
+				Spec: cmacme.ChallengeSpec{
+					Solver: cmacme.ACMEChallengeSolver{
+						HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+							Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{ForceIPv6: true},
+						},
+					},
+				},
+			},
+			expectedErr: false,
+		},
 	}
 
 	for i := range tests {
@@ -87,3 +109,50 @@ func TestCheck(t *testing.T) {
 		})
 	}
 }
+
+// newServerOn starts a test HTTP server serving key, listening on the given
+// network and address. It skips the test if the network is not available, for
+// example if IPv6 is disabled on the host.
+func newServerOn(t *testing.T, network, addr, key string) *httptest.Server {
+	l, err := net.Listen(network, addr)
+	if err != nil {
+		t.Skipf("cannot listen on %s %s: %v", network, addr, err)
+	}
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, key)
+	}))
+	srv.Listener.Close()
+	srv.Listener = l
+	srv.Start()
+	return srv
+}
+
+func TestTestReachabilityIPv6(t *testing.T) {
+	const key = "testkey"
+
+	t.Run("succeeds over tcp6 against an IPv6-only server", func(t *testing.T) {
+		srv := newServerOn(t, "tcp6", "[::1]:0", key)
+		defer srv.Close()
+
+		u, err := url.Parse(srv.URL + "/.well-known/acme-challenge/token")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := testReachability(context.Background(), u, key, "tcp6"); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("fails over tcp6 against an IPv4-only server", func(t *testing.T) {
+		srv := newServerOn(t, "tcp4", "127.0.0.1:0", key)
+		defer srv.Close()
+
+		u, err := url.Parse(srv.URL + "/.well-known/acme-challenge/token")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := testReachability(context.Background(), u, key, "tcp6"); err == nil {
+			t.Errorf("expected an error connecting to an IPv4 address over tcp6, but got none")
+		}
+	})
+}
@@ -84,6 +84,9 @@ func (s *Solver) ensureIngress(ctx context.Context, ch *cmacme.Challenge, svcNam
 		log.V(logf.DebugLevel).Info("adding solver paths to existing ingress resource")
 		return s.addChallengePathToIngress(ctx, ch, svcName)
 	}
+	if s.ACMEOptions.HTTP01SolverSharedIngress {
+		return s.ensureSharedIngress(ctx, ch, svcName)
+	}
 	existingIngresses, err := s.getIngressesForChallenge(ctx, ch)
 	if err != nil {
 		return nil, err
@@ -157,11 +160,7 @@ func buildIngressResource(ch *cmacme.Challenge, svcName string) (*networkingv1be
 
 	ingPathToAdd := ingressPath(ch.Spec.Token, svcName)
 
-	httpHost := ch.Spec.DNSName
-	// if we need to verify ownership of an IP the challenge should propagate on all hosts
-	if net.ParseIP(httpHost) != nil {
-		httpHost = ""
-	}
+	httpHost := challengeIngressHost(ch)
 	return &networkingv1beta1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName:    "cm-acme-http-solver-",
@@ -185,6 +184,16 @@ func buildIngressResource(ch *cmacme.Challenge, svcName string) (*networkingv1be
 	}, nil
 }
 
+// challengeIngressHost returns the host of the ingress rule that routes the
+// given challenge to its solver.
+func challengeIngressHost(ch *cmacme.Challenge) string {
+	// if we need to verify ownership of an IP the challenge should propagate on all hosts
+	if net.ParseIP(ch.Spec.DNSName) != nil {
+		return ""
+	}
+	return ch.Spec.DNSName
+}
+
 // Merge object meta from the ingress template. Fall back to default values.
 func (s *Solver) mergeIngressObjectMetaWithIngressResourceTemplate(ingress *networkingv1beta1.Ingress, ingressTempl *cmacme.ACMEChallengeSolverHTTP01IngressTemplate) *networkingv1beta1.Ingress {
 	if ingressTempl == nil {
@@ -363,3 +372,189 @@ func ingressPath(token, serviceName string) networkingv1beta1.HTTPIngressPath {
 var solverPathFn = func(token string) string {
 	return fmt.Sprintf("%s/%s", solver.HTTPChallengePath, token)
 }
+
+// sharedIngressName returns the name of the shared solver Ingress used for
+// challenges with the given ingress class.
+func sharedIngressName(class *string) string {
+	if class == nil || *class == "" {
+		return sharedSolverName
+	}
+	return fmt.Sprintf("%s-%s", sharedSolverName, *class)
+}
+
+// ensureSharedIngress ensures that the shared solver Ingress for the
+// challenge's ingress class exists and routes the challenge's token to the
+// given service. Challenges are added to the shared Ingress as a path on the
+// rule for their DNS name, so any number of in-flight challenges can be
+// routed through the same Ingress.
+func (s *Solver) ensureSharedIngress(ctx context.Context, ch *cmacme.Challenge, svcName string) (*networkingv1beta1.Ingress, error) {
+	log := logf.FromContext(ctx, "ensureSharedIngress")
+
+	httpDomainCfg, err := httpDomainCfgForChallenge(ch)
+	if err != nil {
+		return nil, err
+	}
+	name := sharedIngressName(httpDomainCfg.Class)
+	log = logf.WithRelatedResourceName(log, name, ch.Namespace, "Ingress")
+
+	var ing *networkingv1beta1.Ingress
+	err = retryOnConflict(func() error {
+		existing, err := s.Client.NetworkingV1beta1().Ingresses(ch.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if k8sErrors.IsNotFound(err) {
+			log.V(logf.InfoLevel).Info("creating shared HTTP01 solver ingress")
+			ing, err = buildSharedIngressResource(ch, name, svcName)
+			if err != nil {
+				return err
+			}
+			ing = s.mergeIngressObjectMetaWithIngressResourceTemplate(ing, httpDomainCfg.IngressTemplate)
+			ing, err = s.Client.NetworkingV1beta1().Ingresses(ch.Namespace).Create(ctx, ing, metav1.CreateOptions{})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		ing = existing.DeepCopy()
+		if !setIngressChallengePath(ing, challengeIngressHost(ch), ingressPath(ch.Spec.Token, svcName)) {
+			return nil
+		}
+		log.V(logf.DebugLevel).Info("adding challenge path to shared HTTP01 solver ingress")
+		ing, err = s.Client.NetworkingV1beta1().Ingresses(ch.Namespace).Update(ctx, ing, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ing, nil
+}
+
+// cleanupSharedIngress removes the challenge's path from the shared solver
+// Ingress, and deletes the shared Ingress once no challenge paths remain.
+func (s *Solver) cleanupSharedIngress(ctx context.Context, ch *cmacme.Challenge) error {
+	log := logf.FromContext(ctx, "cleanupSharedIngress")
+
+	httpDomainCfg, err := httpDomainCfgForChallenge(ch)
+	if err != nil {
+		return err
+	}
+	// challenges that name an existing ingress are never added to the
+	// shared ingress
+	if httpDomainCfg.Name != "" {
+		return nil
+	}
+	name := sharedIngressName(httpDomainCfg.Class)
+	log = logf.WithRelatedResourceName(log, name, ch.Namespace, "Ingress")
+
+	return retryOnConflict(func() error {
+		ing, err := s.Client.NetworkingV1beta1().Ingresses(ch.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if k8sErrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		ing = ing.DeepCopy()
+		if !removeIngressChallengePath(ing, challengeIngressHost(ch), solverPathFn(ch.Spec.Token)) {
+			return nil
+		}
+
+		if len(ing.Spec.Rules) > 0 {
+			log.V(logf.DebugLevel).Info("removing challenge path from shared HTTP01 solver ingress")
+			_, err = s.Client.NetworkingV1beta1().Ingresses(ch.Namespace).Update(ctx, ing, metav1.UpdateOptions{})
+			return err
+		}
+
+		// the precondition ensures we don't delete the ingress if another
+		// challenge has added its path since we read it
+		log.V(logf.DebugLevel).Info("deleting shared HTTP01 solver ingress as no challenge paths remain")
+		err = s.Client.NetworkingV1beta1().Ingresses(ch.Namespace).Delete(ctx, name, metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{ResourceVersion: &ing.ResourceVersion},
+		})
+		if k8sErrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	})
+}
+
+// buildSharedIngressResource builds the shared solver Ingress, initially
+// routing only the given challenge. The Ingress is not owned by the
+// challenge as it outlives it.
+func buildSharedIngressResource(ch *cmacme.Challenge, name, svcName string) (*networkingv1beta1.Ingress, error) {
+	ing, err := buildIngressResource(ch, svcName)
+	if err != nil {
+		return nil, err
+	}
+	ing.GenerateName = ""
+	ing.Name = name
+	ing.Labels = sharedSolverLabels()
+	ing.OwnerReferences = nil
+	return ing, nil
+}
+
+// setIngressChallengePath ensures the rule for host on the ingress contains
+// the given challenge path, adding the rule if needed. It returns false if
+// the ingress was already up to date.
+func setIngressChallengePath(ing *networkingv1beta1.Ingress, host string, path networkingv1beta1.HTTPIngressPath) bool {
+	for i := range ing.Spec.Rules {
+		rule := &ing.Spec.Rules[i]
+		if rule.Host != host {
+			continue
+		}
+		if rule.HTTP == nil {
+			rule.HTTP = &networkingv1beta1.HTTPIngressRuleValue{}
+		}
+		for j, p := range rule.HTTP.Paths {
+			if p.Path != path.Path {
+				continue
+			}
+			if p.Backend.ServiceName == path.Backend.ServiceName &&
+				p.Backend.ServicePort == path.Backend.ServicePort {
+				return false
+			}
+			rule.HTTP.Paths[j] = path
+			return true
+		}
+		rule.HTTP.Paths = append(rule.HTTP.Paths, path)
+		return true
+	}
+
+	ing.Spec.Rules = append(ing.Spec.Rules, networkingv1beta1.IngressRule{
+		Host: host,
+		IngressRuleValue: networkingv1beta1.IngressRuleValue{
+			HTTP: &networkingv1beta1.HTTPIngressRuleValue{
+				Paths: []networkingv1beta1.HTTPIngressPath{path},
+			},
+		},
+	})
+	return true
+}
+
+// removeIngressChallengePath removes the challenge path from the rule for
+// host on the ingress, dropping the rule if it has no paths left. It returns
+// false if the path was not present.
+func removeIngressChallengePath(ing *networkingv1beta1.Ingress, host, path string) bool {
+	removed := false
+	var rules []networkingv1beta1.IngressRule
+	for _, rule := range ing.Spec.Rules {
+		if rule.Host != host || rule.HTTP == nil {
+			rules = append(rules, rule)
+			continue
+		}
+		var paths []networkingv1beta1.HTTPIngressPath
+		for _, p := range rule.HTTP.Paths {
+			if p.Path == path {
+				removed = true
+				continue
+			}
+			paths = append(paths, p)
+		}
+		if len(paths) > 0 {
+			rule.HTTP = &networkingv1beta1.HTTPIngressRuleValue{Paths: paths}
+			rules = append(rules, rule)
+		}
+	}
+	ing.Spec.Rules = rules
+	return removed
+}
@@ -32,6 +32,7 @@ import (
 	coretesting "k8s.io/client-go/testing"
 
 	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	"github.com/jetstack/cert-manager/pkg/controller"
 	"github.com/jetstack/cert-manager/pkg/controller/test"
 )
 
@@ -547,3 +548,126 @@ func TestMergeIngressObjectMetaWithIngressResourceTemplate(t *testing.T) {
 		})
 	}
 }
+
+func TestSharedIngressConcurrentChallenges(t *testing.T) {
+	const numChallenges = 3
+
+	s := &solverFixture{
+		Builder: &test.Builder{
+			Context: &controller.Context{
+				RootContext: context.Background(),
+				ACMEOptions: controller.ACMEOptions{
+					HTTP01SolverImage:            "acmesolver:test",
+					HTTP01SolverSharedDeployment: true,
+					HTTP01SolverSharedIngress:    true,
+				},
+			},
+		},
+	}
+	s.Setup(t)
+	defer s.Builder.Stop()
+
+	var challenges []*cmacme.Challenge
+	for i := 0; i < numChallenges; i++ {
+		challenges = append(challenges, sharedSolverChallenge(i))
+	}
+	// a second challenge for the same DNS name shares a rule with the first
+	sameHost := sharedSolverChallenge(numChallenges)
+	sameHost.Spec.DNSName = challenges[0].Spec.DNSName
+	challenges = append(challenges, sameHost)
+
+	for _, ch := range challenges {
+		if err := s.Solver.Present(context.TODO(), nil, ch); err != nil {
+			t.Fatalf("unexpected error presenting challenge %q: %v", ch.Name, err)
+		}
+	}
+	s.Builder.Sync()
+
+	getSharedIngress := func() *v1beta1.Ingress {
+		ing, err := s.Client.NetworkingV1beta1().Ingresses(defaultTestNamespace).Get(context.TODO(), sharedSolverName, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("error getting shared ingress: %v", err)
+		}
+		return ing
+	}
+	// challengePaths returns the paths on the shared ingress by host
+	challengePaths := func(ing *v1beta1.Ingress) map[string][]string {
+		paths := make(map[string][]string)
+		for _, rule := range ing.Spec.Rules {
+			for _, p := range rule.HTTP.Paths {
+				if p.Backend.ServiceName != sharedSolverName {
+					t.Errorf("expected path %q to route to %q but got %q", p.Path, sharedSolverName, p.Backend.ServiceName)
+				}
+				paths[rule.Host] = append(paths[rule.Host], p.Path)
+			}
+		}
+		return paths
+	}
+
+	// every challenge is routed through a single Ingress, with a distinct
+	// path per challenge
+	ings, err := s.Client.NetworkingV1beta1().Ingresses(defaultTestNamespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ings.Items) != 1 || ings.Items[0].Name != sharedSolverName {
+		t.Fatalf("expected a single shared ingress but got: %+v", ings.Items)
+	}
+	if len(ings.Items[0].OwnerReferences) != 0 {
+		t.Errorf("expected shared ingress to have no owner references but got: %+v", ings.Items[0].OwnerReferences)
+	}
+	expectedPaths := map[string][]string{
+		"0.example.com": {solverPathFn("token-0"), solverPathFn("token-3")},
+		"1.example.com": {solverPathFn("token-1")},
+		"2.example.com": {solverPathFn("token-2")},
+	}
+	if paths := challengePaths(&ings.Items[0]); !reflect.DeepEqual(paths, expectedPaths) {
+		t.Errorf("unexpected paths on shared ingress: %s", diff.ObjectReflectDiff(expectedPaths, paths))
+	}
+
+	// presenting a challenge again does not modify the shared ingress
+	s.FakeKubeClient().ClearActions()
+	if err := s.Solver.Present(context.TODO(), nil, challenges[1]); err != nil {
+		t.Fatalf("unexpected error presenting challenge: %v", err)
+	}
+	for _, action := range s.FakeKubeClient().Actions() {
+		if action.Matches("update", "ingresses") || action.Matches("create", "ingresses") {
+			t.Errorf("expected shared ingress to be unchanged but got action: %+v", action)
+		}
+	}
+
+	// cleaning up a challenge only removes its own path
+	if err := s.Solver.CleanUp(context.TODO(), nil, challenges[0]); err != nil {
+		t.Fatalf("unexpected error cleaning up challenge: %v", err)
+	}
+	if err := s.Solver.CleanUp(context.TODO(), nil, challenges[2]); err != nil {
+		t.Fatalf("unexpected error cleaning up challenge: %v", err)
+	}
+	expectedPaths = map[string][]string{
+		"0.example.com": {solverPathFn("token-3")},
+		"1.example.com": {solverPathFn("token-1")},
+	}
+	if paths := challengePaths(getSharedIngress()); !reflect.DeepEqual(paths, expectedPaths) {
+		t.Errorf("unexpected paths on shared ingress: %s", diff.ObjectReflectDiff(expectedPaths, paths))
+	}
+
+	// the shared ingress is deleted once the last challenge is cleaned up
+	for _, ch := range []*cmacme.Challenge{challenges[1], challenges[3]} {
+		if err := s.Solver.CleanUp(context.TODO(), nil, ch); err != nil {
+			t.Fatalf("unexpected error cleaning up challenge: %v", err)
+		}
+	}
+	_, err = s.Client.NetworkingV1beta1().Ingresses(defaultTestNamespace).Get(context.TODO(), sharedSolverName, metav1.GetOptions{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected shared ingress to be deleted but got: %v", err)
+	}
+}
+
+func TestSharedIngressName(t *testing.T) {
+	if name := sharedIngressName(nil); name != sharedSolverName {
+		t.Errorf("expected %q but got %q", sharedSolverName, name)
+	}
+	if name := sharedIngressName(strPtr("nginx")); name != sharedSolverName+"-nginx" {
+		t.Errorf("expected %q but got %q", sharedSolverName+"-nginx", name)
+	}
+}
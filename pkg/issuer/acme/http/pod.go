@@ -167,7 +167,10 @@ func (s *Solver) buildDefaultPod(ch *cmacme.Challenge) *corev1.Pod {
 			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(ch, challengeGvk)},
 		},
 		Spec: corev1.PodSpec{
-			RestartPolicy: corev1.RestartPolicyOnFailure,
+			RestartPolicy:    corev1.RestartPolicyOnFailure,
+			ImagePullSecrets: s.solverImagePullSecrets(),
+			NodeSelector:     s.solverNodeSelector(),
+			Tolerations:      s.solverTolerations(),
 			Containers: []corev1.Container{
 				{
 					Name: "acmesolver",
@@ -203,6 +206,21 @@ func (s *Solver) buildDefaultPod(ch *cmacme.Challenge) *corev1.Pod {
 	}
 }
 
+// solverImagePullSecrets returns references to the configured Secrets used
+// to pull the HTTP01 solver image, which must exist in the namespace of the
+// solver pod.
+func (s *Solver) solverImagePullSecrets() []corev1.LocalObjectReference {
+	if len(s.ACMEOptions.HTTP01SolverImagePullSecrets) == 0 {
+		return nil
+	}
+
+	refs := make([]corev1.LocalObjectReference, len(s.ACMEOptions.HTTP01SolverImagePullSecrets))
+	for i, name := range s.ACMEOptions.HTTP01SolverImagePullSecrets {
+		refs[i] = corev1.LocalObjectReference{Name: name}
+	}
+	return refs
+}
+
 // Merge object meta from the pod template. Fall back to default values.
 func (s *Solver) mergePodObjectMetaWithPodTemplate(pod *corev1.Pod, podTempl *cmacme.ACMEChallengeSolverHTTP01IngressPodTemplate) *corev1.Pod {
 	if podTempl == nil {
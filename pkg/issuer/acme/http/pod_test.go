@@ -27,6 +27,8 @@ import (
 	coretesting "k8s.io/client-go/testing"
 
 	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	"github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/controller/test"
 )
 
 func TestEnsurePod(t *testing.T) {
@@ -181,6 +183,101 @@ func TestEnsurePod(t *testing.T) {
 	}
 }
 
+func TestCreatePodImagePullSecrets(t *testing.T) {
+	s := &solverFixture{
+		Builder: &test.Builder{
+			Context: &controller.Context{
+				RootContext: context.Background(),
+				ACMEOptions: controller.ACMEOptions{
+					HTTP01SolverImage:            "acmesolver:test",
+					HTTP01SolverImagePullSecrets: []string{"registry-creds", "mirror-creds"},
+				},
+			},
+		},
+		Challenge: &cmacme.Challenge{
+			Spec: cmacme.ChallengeSpec{
+				DNSName: "example.com",
+				Token:   "token",
+				Key:     "key",
+				Solver: cmacme.ACMEChallengeSolver{
+					HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+						Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{},
+					},
+				},
+			},
+		},
+	}
+	s.Setup(t)
+	defer s.Builder.Stop()
+
+	pod, err := s.Solver.createPod(context.TODO(), s.Challenge)
+	if err != nil {
+		t.Fatalf("unexpected error creating pod: %v", err)
+	}
+
+	expected := []corev1.LocalObjectReference{{Name: "registry-creds"}, {Name: "mirror-creds"}}
+	if !reflect.DeepEqual(pod.Spec.ImagePullSecrets, expected) {
+		t.Errorf("expected image pull secrets %v but got %v", expected, pod.Spec.ImagePullSecrets)
+	}
+}
+
+func TestCreatePodScheduling(t *testing.T) {
+	tolerations := []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "acme", Effect: corev1.TaintEffectNoSchedule}}
+	s := &solverFixture{
+		Builder: &test.Builder{
+			Context: &controller.Context{
+				RootContext: context.Background(),
+				ACMEOptions: controller.ACMEOptions{
+					HTTP01SolverImage:        "acmesolver:test",
+					HTTP01SolverNodeSelector: map[string]string{"node-pool": "acme"},
+					HTTP01SolverTolerations:  tolerations,
+				},
+			},
+		},
+		Challenge: &cmacme.Challenge{
+			Spec: cmacme.ChallengeSpec{
+				DNSName: "example.com",
+				Token:   "token",
+				Key:     "key",
+				Solver: cmacme.ACMEChallengeSolver{
+					HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+						Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{
+							PodTemplate: &cmacme.ACMEChallengeSolverHTTP01IngressPodTemplate{
+								Spec: cmacme.ACMEChallengeSolverHTTP01IngressPodSpec{
+									NodeSelector: map[string]string{"zone": "a"},
+									Tolerations:  []corev1.Toleration{{Key: "zone", Operator: corev1.TolerationOpExists}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	s.Setup(t)
+	defer s.Builder.Stop()
+
+	pod, err := s.Solver.createPod(context.TODO(), s.Challenge)
+	if err != nil {
+		t.Fatalf("unexpected error creating pod: %v", err)
+	}
+
+	expectedNodeSelector := map[string]string{"node-pool": "acme", "zone": "a"}
+	if !reflect.DeepEqual(pod.Spec.NodeSelector, expectedNodeSelector) {
+		t.Errorf("expected node selector %v but got %v", expectedNodeSelector, pod.Spec.NodeSelector)
+	}
+	expectedTolerations := append(append([]corev1.Toleration(nil), tolerations...), corev1.Toleration{Key: "zone", Operator: corev1.TolerationOpExists})
+	if !reflect.DeepEqual(pod.Spec.Tolerations, expectedTolerations) {
+		t.Errorf("expected tolerations %v but got %v", expectedTolerations, pod.Spec.Tolerations)
+	}
+
+	// the pod template of a solver must not modify the configured defaults
+	if len(s.Solver.ACMEOptions.HTTP01SolverNodeSelector) != 1 || len(s.Solver.ACMEOptions.HTTP01SolverTolerations) != 1 {
+		t.Errorf("expected the configured node selector and tolerations to be unmodified, got %v and %v",
+			s.Solver.ACMEOptions.HTTP01SolverNodeSelector, s.Solver.ACMEOptions.HTTP01SolverTolerations)
+	}
+}
+
 func TestGetPodsForCertificate(t *testing.T) {
 	const createdPodKey = "createdPod"
 	tests := map[string]solverFixture{
@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ParseSolverNodeSelector parses a list of `key=value` pairs into the node
+// selector to set on HTTP01 solver pods.
+func ParseSolverNodeSelector(pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+
+	nodeSelector := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%q is not of the form key=value", pair)
+		}
+		key, value := parts[0], parts[1]
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid key %q: %s", key, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid value %q for key %q: %s", value, key, strings.Join(errs, "; "))
+		}
+		nodeSelector[key] = value
+	}
+
+	return nodeSelector, nil
+}
+
+// ParseSolverTolerations parses a JSON encoded list of tolerations to set on
+// HTTP01 solver pods, e.g.
+// `[{"key":"dedicated","operator":"Equal","value":"acme","effect":"NoSchedule"}]`.
+func ParseSolverTolerations(s string) ([]corev1.Toleration, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var tolerations []corev1.Toleration
+	dec := json.NewDecoder(bytes.NewReader([]byte(s)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&tolerations); err != nil {
+		return nil, fmt.Errorf("tolerations must be a JSON list of tolerations: %v", err)
+	}
+
+	for i, toleration := range tolerations {
+		switch toleration.Operator {
+		case corev1.TolerationOpEqual, "":
+			if toleration.Key == "" {
+				return nil, fmt.Errorf("toleration %d: operator must be %s if the key is empty", i, corev1.TolerationOpExists)
+			}
+		case corev1.TolerationOpExists:
+			if toleration.Value != "" {
+				return nil, fmt.Errorf("toleration %d: value must be empty if the operator is %s", i, corev1.TolerationOpExists)
+			}
+		default:
+			return nil, fmt.Errorf("toleration %d: unsupported operator %q", i, toleration.Operator)
+		}
+
+		switch toleration.Effect {
+		case "", corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+		default:
+			return nil, fmt.Errorf("toleration %d: unsupported effect %q", i, toleration.Effect)
+		}
+	}
+
+	return tolerations, nil
+}
+
+// solverNodeSelector returns a copy of the configured node selector of
+// HTTP01 solver pods, as it may be modified by the pod template of a solver.
+func (s *Solver) solverNodeSelector() map[string]string {
+	if len(s.ACMEOptions.HTTP01SolverNodeSelector) == 0 {
+		return nil
+	}
+
+	nodeSelector := make(map[string]string, len(s.ACMEOptions.HTTP01SolverNodeSelector))
+	for k, v := range s.ACMEOptions.HTTP01SolverNodeSelector {
+		nodeSelector[k] = v
+	}
+	return nodeSelector
+}
+
+// solverTolerations returns a copy of the configured tolerations of HTTP01
+// solver pods, as they may be appended to by the pod template of a solver.
+func (s *Solver) solverTolerations() []corev1.Toleration {
+	if len(s.ACMEOptions.HTTP01SolverTolerations) == 0 {
+		return nil
+	}
+
+	return append([]corev1.Toleration(nil), s.ACMEOptions.HTTP01SolverTolerations...)
+}
@@ -132,6 +132,12 @@ func buildService(ch *cmacme.Challenge) (*corev1.Service, error) {
 	if httpDomainCfg.ServiceType != "" {
 		service.Spec.Type = httpDomainCfg.ServiceType
 	}
+	// if not forced, the IP family is left to be defaulted to the cluster's
+	// primary IP family
+	if httpDomainCfg.ForceIPv6 {
+		ipFamily := corev1.IPv6Protocol
+		service.Spec.IPFamily = &ipFamily
+	}
 
 	return service, nil
 }
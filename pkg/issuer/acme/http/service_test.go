@@ -259,3 +259,37 @@ func TestGetServicesForChallenge(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildServiceIPFamily(t *testing.T) {
+	ipv6 := v1.IPv6Protocol
+	tests := map[string]struct {
+		forceIPv6        bool
+		expectedIPFamily *v1.IPFamily
+	}{
+		"should leave the IP family to be defaulted if IPv6 is not forced": {},
+		"should set the IPv6 IP family if IPv6 is forced": {
+			forceIPv6:        true,
+			expectedIPFamily: &ipv6,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			svc, err := buildService(&cmacme.Challenge{
+				Spec: cmacme.ChallengeSpec{
+					DNSName: "example.com",
+					Solver: cmacme.ACMEChallengeSolver{
+						HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+							Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{ForceIPv6: test.forceIPv6},
+						},
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(svc.Spec.IPFamily, test.expectedIPFamily) {
+				t.Errorf("expected IP family %v but got %v", test.expectedIPFamily, svc.Spec.IPFamily)
+			}
+		})
+	}
+}
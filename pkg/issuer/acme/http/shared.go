@@ -0,0 +1,317 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/util/retry"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	"github.com/jetstack/cert-manager/pkg/issuer/acme/http/solver"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+// When the controller is configured to use a shared HTTP01 solver, a single
+// long-lived Deployment and Service are created in each namespace that
+// contains HTTP01 challenges. The tokens for all in-flight challenges in the
+// namespace are stored in a ConfigMap which is mounted into the solver pods,
+// and each challenge's Ingress routes to the shared Service.
+const (
+	// sharedSolverName is the name of the shared solver Deployment and
+	// Service.
+	sharedSolverName = "cm-acme-http-solver"
+	// sharedSolverTokensName is the name of the ConfigMap containing the
+	// tokens served by the shared solver.
+	sharedSolverTokensName = "cm-acme-http-solver-tokens"
+	// sharedSolverTokensDir is the path the tokens ConfigMap is mounted at in
+	// the shared solver pods.
+	sharedSolverTokensDir = "/var/run/acmesolver/tokens"
+	// sharedSolverLabelKey is added to the labels of the shared solver
+	// Deployment, its Pods and its Service.
+	sharedSolverLabelKey = "acme.cert-manager.io/http01-shared-solver"
+)
+
+func sharedSolverLabels() map[string]string {
+	return map[string]string{
+		cmacme.SolverIdentificationLabelKey: "true",
+		sharedSolverLabelKey:                "true",
+	}
+}
+
+// retryOnConflict retries fn whilst it fails due to another challenge
+// concurrently modifying the same shared resource.
+func retryOnConflict(fn func() error) error {
+	return retry.OnError(retry.DefaultRetry, func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+	}, fn)
+}
+
+// presentShared ensures the challenge's token is served by the shared solver
+// in the challenge's namespace, creating the solver if it does not exist.
+func (s *Solver) presentShared(ctx context.Context, ch *cmacme.Challenge) error {
+	if err := s.ensureSharedToken(ctx, ch); err != nil {
+		return err
+	}
+	if err := s.ensureSharedDeployment(ctx, ch); err != nil {
+		return err
+	}
+	svc, err := s.ensureSharedService(ctx, ch)
+	if err != nil {
+		return err
+	}
+	_, err = s.ensureIngress(ctx, ch, svc.Name)
+	return err
+}
+
+// cleanupShared stops the shared solver from serving the challenge's token.
+// The shared Deployment and Service are left running to serve future
+// challenges.
+func (s *Solver) cleanupShared(ctx context.Context, ch *cmacme.Challenge) error {
+	log := logf.FromContext(ctx, "cleanupShared")
+
+	return retryOnConflict(func() error {
+		cm, err := s.Client.CoreV1().ConfigMaps(ch.Namespace).Get(ctx, sharedSolverTokensName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, ok := cm.Data[ch.Spec.Token]; !ok {
+			return nil
+		}
+
+		log.V(logf.DebugLevel).Info("removing challenge token from shared HTTP01 solver")
+		cm = cm.DeepCopy()
+		delete(cm.Data, ch.Spec.Token)
+		_, err = s.Client.CoreV1().ConfigMaps(ch.Namespace).Update(ctx, cm, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+func (s *Solver) ensureSharedToken(ctx context.Context, ch *cmacme.Challenge) error {
+	log := logf.FromContext(ctx, "ensureSharedToken")
+
+	resp, err := json.Marshal(solver.ChallengeResponse{
+		Domain: ch.Spec.DNSName,
+		Key:    ch.Spec.Key,
+	})
+	if err != nil {
+		return err
+	}
+
+	return retryOnConflict(func() error {
+		cm, err := s.Client.CoreV1().ConfigMaps(ch.Namespace).Get(ctx, sharedSolverTokensName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			log.V(logf.DebugLevel).Info("creating shared HTTP01 solver tokens ConfigMap")
+			cm = buildSharedTokensConfigMap(ch.Namespace)
+			cm.Data[ch.Spec.Token] = string(resp)
+			_, err = s.Client.CoreV1().ConfigMaps(ch.Namespace).Create(ctx, cm, metav1.CreateOptions{})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		if cm.Data[ch.Spec.Token] == string(resp) {
+			return nil
+		}
+
+		log.V(logf.DebugLevel).Info("adding challenge token to shared HTTP01 solver")
+		cm = cm.DeepCopy()
+		if cm.Data == nil {
+			cm.Data = make(map[string]string)
+		}
+		cm.Data[ch.Spec.Token] = string(resp)
+		_, err = s.Client.CoreV1().ConfigMaps(ch.Namespace).Update(ctx, cm, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+func (s *Solver) ensureSharedDeployment(ctx context.Context, ch *cmacme.Challenge) error {
+	log := logf.FromContext(ctx, "ensureSharedDeployment")
+
+	_, err := s.Client.AppsV1().Deployments(ch.Namespace).Get(ctx, sharedSolverName, metav1.GetOptions{})
+	if err == nil || !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	log.V(logf.InfoLevel).Info("creating shared HTTP01 solver deployment")
+	_, err = s.Client.AppsV1().Deployments(ch.Namespace).Create(ctx, s.buildSharedDeployment(ch.Namespace), metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *Solver) ensureSharedService(ctx context.Context, ch *cmacme.Challenge) (*corev1.Service, error) {
+	log := logf.FromContext(ctx, "ensureSharedService")
+
+	svc, err := s.Client.CoreV1().Services(ch.Namespace).Get(ctx, sharedSolverName, metav1.GetOptions{})
+	if err == nil || !apierrors.IsNotFound(err) {
+		return svc, err
+	}
+
+	svc, err = buildSharedService(ch)
+	if err != nil {
+		return nil, err
+	}
+
+	log.V(logf.InfoLevel).Info("creating shared HTTP01 solver service")
+	created, err := s.Client.CoreV1().Services(ch.Namespace).Create(ctx, svc, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		return s.Client.CoreV1().Services(ch.Namespace).Get(ctx, sharedSolverName, metav1.GetOptions{})
+	}
+	return created, err
+}
+
+func buildSharedTokensConfigMap(namespace string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sharedSolverTokensName,
+			Namespace: namespace,
+			Labels:    sharedSolverLabels(),
+		},
+		Data: make(map[string]string),
+	}
+}
+
+// buildSharedDeployment builds the shared solver Deployment for the given
+// namespace. Pod templates configured on individual solvers are not applied,
+// as the Deployment is shared between all challenges in the namespace.
+func (s *Solver) buildSharedDeployment(namespace string) *appsv1.Deployment {
+	labels := sharedSolverLabels()
+	replicas := int32(1)
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sharedSolverName,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+					Annotations: map[string]string{
+						"sidecar.istio.io/inject": "false",
+					},
+				},
+				Spec: corev1.PodSpec{
+					ImagePullSecrets: s.solverImagePullSecrets(),
+					NodeSelector:     s.solverNodeSelector(),
+					Tolerations:      s.solverTolerations(),
+					Containers: []corev1.Container{
+						{
+							Name:            "acmesolver",
+							Image:           s.Context.HTTP01SolverImage,
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							Args: []string{
+								fmt.Sprintf("--listen-port=%d", acmeSolverListenPort),
+								fmt.Sprintf("--tokens-dir=%s", sharedSolverTokensDir),
+							},
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    s.ACMEOptions.HTTP01SolverResourceRequestCPU,
+									corev1.ResourceMemory: s.ACMEOptions.HTTP01SolverResourceRequestMemory,
+								},
+								Limits: corev1.ResourceList{
+									corev1.ResourceCPU:    s.ACMEOptions.HTTP01SolverResourceLimitsCPU,
+									corev1.ResourceMemory: s.ACMEOptions.HTTP01SolverResourceLimitsMemory,
+								},
+							},
+							Ports: []corev1.ContainerPort{
+								{
+									Name:          "http",
+									ContainerPort: acmeSolverListenPort,
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "tokens",
+									MountPath: sharedSolverTokensDir,
+									ReadOnly:  true,
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "tokens",
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{
+										Name: sharedSolverTokensName,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// buildSharedService builds the shared solver Service in the challenge's
+// namespace. The service type is taken from the solver configuration of the
+// challenge that causes the Service to be created.
+func buildSharedService(ch *cmacme.Challenge) (*corev1.Service, error) {
+	labels := sharedSolverLabels()
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sharedSolverName,
+			Namespace: ch.Namespace,
+			Labels:    labels,
+			Annotations: map[string]string{
+				"auth.istio.io/8089": "NONE",
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeNodePort,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "http",
+					Port:       acmeSolverListenPort,
+					TargetPort: intstr.FromInt(acmeSolverListenPort),
+				},
+			},
+			Selector: labels,
+		},
+	}
+
+	httpDomainCfg, err := httpDomainCfgForChallenge(ch)
+	if err != nil {
+		return nil, err
+	}
+	if httpDomainCfg.ServiceType != "" {
+		service.Spec.Type = httpDomainCfg.ServiceType
+	}
+
+	return service, nil
+}
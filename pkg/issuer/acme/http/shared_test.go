@@ -0,0 +1,235 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
+	"github.com/jetstack/cert-manager/pkg/controller"
+	"github.com/jetstack/cert-manager/pkg/controller/test"
+	"github.com/jetstack/cert-manager/pkg/issuer/acme/http/solver"
+)
+
+func sharedSolverChallenge(i int) *cmacme.Challenge {
+	return &cmacme.Challenge{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("test-%d", i),
+			Namespace: defaultTestNamespace,
+		},
+		Spec: cmacme.ChallengeSpec{
+			DNSName: fmt.Sprintf("%d.example.com", i),
+			Token:   fmt.Sprintf("token-%d", i),
+			Key:     fmt.Sprintf("key-%d", i),
+			Solver: cmacme.ACMEChallengeSolver{
+				HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+					Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{},
+				},
+			},
+		},
+	}
+}
+
+func sharedTokens(t *testing.T, s *solverFixture) map[string]solver.ChallengeResponse {
+	cm, err := s.Client.CoreV1().ConfigMaps(defaultTestNamespace).Get(context.TODO(), sharedSolverTokensName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("error getting shared solver tokens: %v", err)
+	}
+	tokens := make(map[string]solver.ChallengeResponse)
+	for token, data := range cm.Data {
+		var resp solver.ChallengeResponse
+		if err := json.Unmarshal([]byte(data), &resp); err != nil {
+			t.Fatalf("error decoding token %q: %v", token, err)
+		}
+		tokens[token] = resp
+	}
+	return tokens
+}
+
+func TestSharedSolverConcurrentChallenges(t *testing.T) {
+	const numChallenges = 3
+
+	s := &solverFixture{
+		Builder: &test.Builder{
+			Context: &controller.Context{
+				RootContext: context.Background(),
+				ACMEOptions: controller.ACMEOptions{
+					HTTP01SolverImage:            "acmesolver:test",
+					HTTP01SolverSharedDeployment: true,
+				},
+			},
+		},
+	}
+	s.Setup(t)
+	defer s.Builder.Stop()
+
+	var challenges []*cmacme.Challenge
+	for i := 0; i < numChallenges; i++ {
+		ch := sharedSolverChallenge(i)
+		challenges = append(challenges, ch)
+		if err := s.Solver.Present(context.TODO(), nil, ch); err != nil {
+			t.Fatalf("unexpected error presenting challenge %q: %v", ch.Name, err)
+		}
+	}
+	s.Builder.Sync()
+
+	// every challenge is served by a single Deployment and Service
+	deployments, err := s.Client.AppsV1().Deployments(defaultTestNamespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deployments.Items) != 1 || deployments.Items[0].Name != sharedSolverName {
+		t.Errorf("expected a single shared solver deployment but got: %+v", deployments.Items)
+	}
+	services, err := s.Solver.serviceLister.List(labels.Everything())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 1 || services[0].Name != sharedSolverName {
+		t.Errorf("expected a single shared solver service but got: %+v", services)
+	}
+	pods, err := s.Solver.podLister.List(labels.Everything())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods) != 0 {
+		t.Errorf("expected no per-challenge solver pods but got: %+v", pods)
+	}
+
+	// each challenge has its own token and Ingress routing to the shared
+	// Service
+	tokens := sharedTokens(t, s)
+	if len(tokens) != numChallenges {
+		t.Errorf("expected %d tokens but got: %+v", numChallenges, tokens)
+	}
+	for _, ch := range challenges {
+		expected := solver.ChallengeResponse{Domain: ch.Spec.DNSName, Key: ch.Spec.Key}
+		if tokens[ch.Spec.Token] != expected {
+			t.Errorf("expected token %q to be served as %+v but got %+v", ch.Spec.Token, expected, tokens[ch.Spec.Token])
+		}
+
+		ings, err := s.Solver.getIngressesForChallenge(context.TODO(), ch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ings) != 1 {
+			t.Errorf("expected one ingress for challenge %q but got: %+v", ch.Name, ings)
+			continue
+		}
+		if svcName := ingressServiceName(ings[0]); svcName != sharedSolverName {
+			t.Errorf("expected ingress for challenge %q to route to %q but got %q", ch.Name, sharedSolverName, svcName)
+		}
+	}
+
+	// presenting a challenge again does not change the served tokens
+	if err := s.Solver.Present(context.TODO(), nil, challenges[0]); err != nil {
+		t.Fatalf("unexpected error presenting challenge: %v", err)
+	}
+	if tokens := sharedTokens(t, s); len(tokens) != numChallenges {
+		t.Errorf("expected %d tokens but got: %+v", numChallenges, tokens)
+	}
+
+	// cleaning up one challenge leaves the others being served
+	if err := s.Solver.CleanUp(context.TODO(), nil, challenges[0]); err != nil {
+		t.Fatalf("unexpected error cleaning up challenge: %v", err)
+	}
+	s.Builder.Sync()
+
+	tokens = sharedTokens(t, s)
+	if _, ok := tokens[challenges[0].Spec.Token]; ok {
+		t.Errorf("expected token %q to be removed", challenges[0].Spec.Token)
+	}
+	if len(tokens) != numChallenges-1 {
+		t.Errorf("expected %d tokens but got: %+v", numChallenges-1, tokens)
+	}
+	ings, err := s.Solver.getIngressesForChallenge(context.TODO(), challenges[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ings) != 0 {
+		t.Errorf("expected ingress for cleaned up challenge to be deleted but got: %+v", ings)
+	}
+	if _, err := s.Client.AppsV1().Deployments(defaultTestNamespace).Get(context.TODO(), sharedSolverName, metav1.GetOptions{}); err != nil {
+		t.Errorf("expected shared solver deployment to be retained: %v", err)
+	}
+}
+
+func TestBuildSharedDeployment(t *testing.T) {
+	s := &Solver{Context: &controller.Context{
+		ACMEOptions: controller.ACMEOptions{
+			HTTP01SolverImage:            "acmesolver:test",
+			HTTP01SolverImagePullSecrets: []string{"registry-creds"},
+		},
+	}}
+	deploy := s.buildSharedDeployment(defaultTestNamespace)
+
+	if deploy.Name != sharedSolverName || deploy.Namespace != defaultTestNamespace {
+		t.Errorf("unexpected deployment name %s/%s", deploy.Namespace, deploy.Name)
+	}
+	if len(deploy.OwnerReferences) != 0 {
+		t.Errorf("expected shared deployment to not be owned by a challenge")
+	}
+
+	container := deploy.Spec.Template.Spec.Containers[0]
+	expectedArgs := []string{"--listen-port=8089", "--tokens-dir=" + sharedSolverTokensDir}
+	if fmt.Sprint(container.Args) != fmt.Sprint(expectedArgs) {
+		t.Errorf("expected args %v but got %v", expectedArgs, container.Args)
+	}
+	if container.Image != "acmesolver:test" {
+		t.Errorf("expected image %q but got %q", "acmesolver:test", container.Image)
+	}
+
+	expectedPullSecrets := []corev1.LocalObjectReference{{Name: "registry-creds"}}
+	if !reflect.DeepEqual(deploy.Spec.Template.Spec.ImagePullSecrets, expectedPullSecrets) {
+		t.Errorf("expected image pull secrets %v but got %v", expectedPullSecrets, deploy.Spec.Template.Spec.ImagePullSecrets)
+	}
+
+	volume := deploy.Spec.Template.Spec.Volumes[0]
+	if volume.ConfigMap == nil || volume.ConfigMap.Name != sharedSolverTokensName {
+		t.Errorf("expected tokens ConfigMap to be mounted but got: %+v", volume)
+	}
+
+	svc, err := buildSharedService(&cmacme.Challenge{
+		ObjectMeta: metav1.ObjectMeta{Namespace: defaultTestNamespace},
+		Spec: cmacme.ChallengeSpec{
+			Solver: cmacme.ACMEChallengeSolver{
+				HTTP01: &cmacme.ACMEChallengeSolverHTTP01{
+					Ingress: &cmacme.ACMEChallengeSolverHTTP01Ingress{
+						ServiceType: corev1.ServiceTypeClusterIP,
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !labels.SelectorFromSet(svc.Spec.Selector).Matches(labels.Set(deploy.Spec.Template.Labels)) {
+		t.Errorf("expected shared service to select the shared deployment's pods")
+	}
+	if svc.Spec.Type != corev1.ServiceTypeClusterIP {
+		t.Errorf("expected service type %q but got %q", corev1.ServiceTypeClusterIP, svc.Spec.Type)
+	}
+}
@@ -17,14 +17,32 @@ limitations under the License.
 package solver
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"path"
+	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/go-logr/logr"
 )
 
+// validToken matches the characters allowed in an ACME challenge token, which
+// is base64url encoded. Requests for any other token are rejected before the
+// tokens directory is consulted.
+var validToken = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ChallengeResponse is the domain and key that should be presented for a
+// single challenge token. When the solver is serving tokens from a directory,
+// each file in the directory is named after a token and contains a JSON
+// encoded ChallengeResponse.
+type ChallengeResponse struct {
+	Domain string `json:"domain"`
+	Key    string `json:"key"`
+}
+
 type HTTP01Solver struct {
 	ListenPort int
 
@@ -32,18 +50,41 @@ type HTTP01Solver struct {
 	Token  string
 	Key    string
 
+	// TokensDir is a directory containing a file per challenge token. If set,
+	// the solver serves every token in the directory and the Domain, Token and
+	// Key fields are ignored. This allows a single long-lived solver to serve
+	// any number of concurrent challenges.
+	TokensDir string
+
 	http.Server
 }
 
 func (h *HTTP01Solver) Listen(log logr.Logger) error {
-	log.Info("starting listener",
-		"expected_domain", h.Domain,
-		"expected_token", h.Token,
-		"expected_key", h.Key,
-		"listen_port", h.ListenPort,
-	)
-
-	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	if h.TokensDir != "" {
+		log.Info("starting listener",
+			"tokens_dir", h.TokensDir,
+			"listen_port", h.ListenPort,
+		)
+	} else {
+		log.Info("starting listener",
+			"expected_domain", h.Domain,
+			"expected_token", h.Token,
+			"expected_key", h.Key,
+			"listen_port", h.ListenPort,
+		)
+	}
+
+	h.Server = http.Server{
+		Addr:    fmt.Sprintf(":%d", h.ListenPort),
+		Handler: h.Handler(log),
+	}
+
+	return h.Server.ListenAndServe()
+}
+
+// Handler returns the http.Handler that responds to challenge requests.
+func (h *HTTP01Solver) Handler(log logr.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// extract vars from the request
 		host := strings.Split(r.Host, ":")[0]
 		basePath := path.Dir(r.URL.EscapedPath())
@@ -69,17 +110,16 @@ func (h *HTTP01Solver) Listen(log logr.Logger) error {
 			return
 		}
 
-		log.Info("comparing host", "expected_host", h.Domain)
-		if h.Domain != host {
-			log.Info("invalid host", "expected_host", h.Domain)
+		resp, err := h.lookup(token)
+		if err != nil {
+			log.Info("invalid token", "error", err)
 			http.NotFound(w, r)
 			return
 		}
 
-		log.Info("comparing token", "expected_token", h.Token)
-		if h.Token != token {
-			// if nothing else, we return a 404 here
-			log.Info("invalid token", "expected_token", h.Token)
+		log.Info("comparing host", "expected_host", resp.Domain)
+		if resp.Domain != host {
+			log.Info("invalid host", "expected_host", resp.Domain)
 			http.NotFound(w, r)
 			return
 		}
@@ -87,13 +127,34 @@ func (h *HTTP01Solver) Listen(log logr.Logger) error {
 		log.Info("got successful challenge request, writing key")
 		w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
 		w.WriteHeader(http.StatusOK)
-		fmt.Fprint(w, h.Key)
+		fmt.Fprint(w, resp.Key)
 	})
+}
 
-	h.Server = http.Server{
-		Addr:    fmt.Sprintf(":%d", h.ListenPort),
-		Handler: handler,
+// lookup returns the response that should be presented for the given token.
+// The tokens directory is read on every request so that tokens added or
+// removed whilst the solver is running are picked up without a restart.
+func (h *HTTP01Solver) lookup(token string) (*ChallengeResponse, error) {
+	if h.TokensDir == "" {
+		if h.Token != token {
+			return nil, fmt.Errorf("expected token %q", h.Token)
+		}
+		return &ChallengeResponse{Domain: h.Domain, Key: h.Key}, nil
 	}
 
-	return h.Server.ListenAndServe()
+	if !validToken.MatchString(token) {
+		return nil, fmt.Errorf("token contains invalid characters")
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(h.TokensDir, token))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ChallengeResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode challenge response: %v", err)
+	}
+
+	return &resp, nil
 }
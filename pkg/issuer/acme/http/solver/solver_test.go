@@ -0,0 +1,232 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package solver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+func writeToken(t *testing.T, dir, token string, resp ChallengeResponse) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, token), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func get(t *testing.T, handler http.Handler, host, path string) (int, string) {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Host = host
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Code, rec.Body.String()
+}
+
+func TestHandler_SingleToken(t *testing.T) {
+	h := &HTTP01Solver{Domain: "example.com", Token: "token", Key: "key"}
+	handler := h.Handler(logf.Log)
+
+	tests := map[string]struct {
+		host, path   string
+		expectedCode int
+		expectedBody string
+	}{
+		"should respond to a health check": {
+			host: "example.com", path: "/healthz", expectedCode: http.StatusOK,
+		},
+		"should respond with the key for the expected token and domain": {
+			host: "example.com", path: HTTPChallengePath + "/token", expectedCode: http.StatusOK, expectedBody: "key",
+		},
+		"should ignore the port in the host header": {
+			host: "example.com:80", path: HTTPChallengePath + "/token", expectedCode: http.StatusOK, expectedBody: "key",
+		},
+		"should not respond for a different token": {
+			host: "example.com", path: HTTPChallengePath + "/other", expectedCode: http.StatusNotFound,
+		},
+		"should not respond for a different domain": {
+			host: "example.org", path: HTTPChallengePath + "/token", expectedCode: http.StatusNotFound,
+		},
+		"should not respond outside of the challenge path": {
+			host: "example.com", path: "/token", expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			code, body := get(t, handler, test.host, test.path)
+			if code != test.expectedCode {
+				t.Errorf("expected status code %d but got %d", test.expectedCode, code)
+			}
+			if test.expectedBody != "" && body != test.expectedBody {
+				t.Errorf("expected body %q but got %q", test.expectedBody, body)
+			}
+		})
+	}
+}
+
+func TestHandler_TokensDir(t *testing.T) {
+	root, err := ioutil.TempDir("", "acmesolver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	dir := filepath.Join(root, "tokens")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	writeToken(t, dir, "token-a", ChallengeResponse{Domain: "a.example.com", Key: "key-a"})
+	writeToken(t, dir, "token-b", ChallengeResponse{Domain: "b.example.com", Key: "key-b"})
+	if err := ioutil.WriteFile(filepath.Join(dir, "invalid"), []byte("not-json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, "outside"), []byte(`{"domain":"a.example.com","key":"secret"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	h := &HTTP01Solver{TokensDir: dir}
+	handler := h.Handler(logf.Log)
+
+	tests := map[string]struct {
+		host, path   string
+		expectedCode int
+		expectedBody string
+	}{
+		"should respond with the key for the first token": {
+			host: "a.example.com", path: HTTPChallengePath + "/token-a", expectedCode: http.StatusOK, expectedBody: "key-a",
+		},
+		"should respond with the key for the second token": {
+			host: "b.example.com", path: HTTPChallengePath + "/token-b", expectedCode: http.StatusOK, expectedBody: "key-b",
+		},
+		"should not respond for a token requested on another token's domain": {
+			host: "b.example.com", path: HTTPChallengePath + "/token-a", expectedCode: http.StatusNotFound,
+		},
+		"should not respond for an unknown token": {
+			host: "a.example.com", path: HTTPChallengePath + "/token-c", expectedCode: http.StatusNotFound,
+		},
+		"should not respond for a token that cannot be decoded": {
+			host: "a.example.com", path: HTTPChallengePath + "/invalid", expectedCode: http.StatusNotFound,
+		},
+		"should not read files outside of the tokens directory": {
+			host: "a.example.com", path: HTTPChallengePath + "/..%2Foutside", expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			code, body := get(t, handler, test.host, test.path)
+			if code != test.expectedCode {
+				t.Errorf("expected status code %d but got %d", test.expectedCode, code)
+			}
+			if test.expectedBody != "" && body != test.expectedBody {
+				t.Errorf("expected body %q but got %q", test.expectedBody, body)
+			}
+		})
+	}
+}
+
+func TestHandler_TokensDirConcurrentChallenges(t *testing.T) {
+	dir, err := ioutil.TempDir("", "acmesolver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	const numChallenges = 50
+	for i := 0; i < numChallenges; i++ {
+		writeToken(t, dir, fmt.Sprintf("token-%d", i), ChallengeResponse{
+			Domain: fmt.Sprintf("%d.example.com", i),
+			Key:    fmt.Sprintf("key-%d", i),
+		})
+	}
+
+	h := &HTTP01Solver{TokensDir: dir}
+	srv := httptest.NewServer(h.Handler(logf.Log))
+	defer srv.Close()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, numChallenges*2)
+	for i := 0; i < numChallenges; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- checkChallenge(srv, fmt.Sprintf("%d.example.com", i), fmt.Sprintf("token-%d", i), http.StatusOK, fmt.Sprintf("key-%d", i))
+			// each token must only be served for its own domain
+			errs <- checkChallenge(srv, fmt.Sprintf("%d.example.com", i), fmt.Sprintf("token-%d", (i+1)%numChallenges), http.StatusNotFound, "")
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	// tokens removed from the directory must no longer be served
+	if err := os.Remove(filepath.Join(dir, "token-0")); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkChallenge(srv, "0.example.com", "token-0", http.StatusNotFound, ""); err != nil {
+		t.Error(err)
+	}
+	if err := checkChallenge(srv, "1.example.com", "token-1", http.StatusOK, "key-1"); err != nil {
+		t.Error(err)
+	}
+}
+
+func checkChallenge(srv *httptest.Server, host, token string, expectedCode int, expectedBody string) error {
+	req, err := http.NewRequest(http.MethodGet, srv.URL+HTTPChallengePath+"/"+token, nil)
+	if err != nil {
+		return err
+	}
+	req.Host = host
+
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != expectedCode {
+		return fmt.Errorf("%s/%s: expected status code %d but got %d", host, token, expectedCode, resp.StatusCode)
+	}
+	if expectedBody != "" && string(body) != expectedBody {
+		return fmt.Errorf("%s/%s: expected body %q but got %q", host, token, expectedBody, string(body))
+	}
+
+	return nil
+}
@@ -18,7 +18,7 @@ package acme
 
 import (
 	"context"
-	"crypto/rsa"
+	"crypto"
 	"encoding/base64"
 	"fmt"
 	"net/url"
@@ -59,7 +59,8 @@ const (
 	messageInvalidPrivateKey             = "Account private key is invalid: "
 
 	messageTemplateUpdateToV2              = "Your ACME server URL is set to a v1 endpoint (%s). You should update the spec.acme.server field to %q"
-	messageTemplateNotRSA                  = "ACME private key in %q is not of type RSA"
+	messageTemplateUnsupportedKey          = "ACME private key in %q cannot be used as an account key: %v"
+	messageTemplateJWSAlgorithmMismatch    = "ACME private key in %q signs requests using %s, but the issuer requires %s"
 	messageTemplateFailedToParseURL        = "Failed to parse existing ACME server URI %q: %v"
 	messageTemplateFailedToParseAccountURL = "Failed to parse existing ACME account URI %q: %v"
 	messageTemplateFailedToGetEABKey       = "failed to get External Account Binding key from secret: %v"
@@ -139,11 +140,17 @@ func (a *Acme) Setup(ctx context.Context) error {
 		msg = messageAccountVerificationFailed + err.Error()
 		return fmt.Errorf(msg)
 	}
-	rsaPk, ok := pk.(*rsa.PrivateKey)
-	if !ok {
+	keyAlgorithm, err := accounts.JWSAlgorithm(pk)
+	if err != nil {
+		reason = errorAccountVerificationFailed
+		msg = fmt.Sprintf(messageTemplateUnsupportedKey,
+			a.issuer.GetSpec().ACME.PrivateKey.Name, err)
+		return nil
+	}
+	if alg := a.issuer.GetSpec().ACME.JWSAlgorithm; alg != "" && alg != keyAlgorithm {
 		reason = errorAccountVerificationFailed
-		msg = fmt.Sprintf(messageTemplateNotRSA,
-			a.issuer.GetSpec().ACME.PrivateKey.Name)
+		msg = fmt.Sprintf(messageTemplateJWSAlgorithmMismatch,
+			a.issuer.GetSpec().ACME.PrivateKey.Name, keyAlgorithm, alg)
 		return nil
 	}
 
@@ -155,8 +162,8 @@ func (a *Acme) Setup(ctx context.Context) error {
 	// We could therefore move the removing of the client up to the start of
 	// this function.
 	a.accountRegistry.RemoveClient(string(a.issuer.GetUID()))
-	httpClient := accounts.BuildHTTPClient(a.metrics, a.issuer.GetSpec().ACME.SkipTLSVerify)
-	cl := a.clientBuilder(httpClient, *a.issuer.GetSpec().ACME, rsaPk)
+	httpClient := accounts.BuildHTTPClient(a.metrics, a.issuer.GetSpec().ACME.SkipTLSVerify, a.backendRootCAs)
+	cl := a.clientBuilder(httpClient, *a.issuer.GetSpec().ACME, pk)
 
 	// TODO: perform a complex check to determine whether we need to verify
 	// the existing registration with the ACME server.
@@ -211,7 +218,7 @@ func (a *Acme) Setup(ctx context.Context) error {
 		status = cmmeta.ConditionTrue
 
 		// ensure the cached client in the account registry is up to date
-		a.accountRegistry.AddClient(httpClient, string(a.issuer.GetUID()), *a.issuer.GetSpec().ACME, rsaPk)
+		a.accountRegistry.AddClient(httpClient, string(a.issuer.GetUID()), *a.issuer.GetSpec().ACME, pk)
 		return nil
 	}
 
@@ -313,7 +320,7 @@ func (a *Acme) Setup(ctx context.Context) error {
 	a.issuer.GetStatus().ACMEStatus().URI = account.URI
 	a.issuer.GetStatus().ACMEStatus().LastRegisteredEmail = registeredEmail
 	// ensure the cached client in the account registry is up to date
-	a.accountRegistry.AddClient(httpClient, string(a.issuer.GetUID()), *a.issuer.GetSpec().ACME, rsaPk)
+	a.accountRegistry.AddClient(httpClient, string(a.issuer.GetUID()), *a.issuer.GetSpec().ACME, pk)
 
 	return nil
 }
@@ -410,11 +417,16 @@ func (a *Acme) getEABKey(ctx context.Context, ns string) ([]byte, error) {
 	return keyData, nil
 }
 
-// createAccountPrivateKey will generate a new RSA private key, and create it
-// as a secret resource in the apiserver.
-func (a *Acme) createAccountPrivateKey(ctx context.Context, sel cmmeta.SecretKeySelector, ns string) (*rsa.PrivateKey, error) {
+// createAccountPrivateKey will generate a new private key of the type required
+// by the issuer's JWS algorithm, RSA by default, and create it as a secret
+// resource in the apiserver.
+func (a *Acme) createAccountPrivateKey(ctx context.Context, sel cmmeta.SecretKeySelector, ns string) (crypto.Signer, error) {
 	sel = acme.PrivateKeySelector(sel)
-	accountPrivKey, err := pki.GenerateRSAPrivateKey(pki.MinRSAKeySize)
+	accountPrivKey, err := accounts.GeneratePrivateKey(a.issuer.GetSpec().ACME.JWSAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	keyData, err := pki.EncodePrivateKey(accountPrivKey, v1.PKCS1)
 	if err != nil {
 		return nil, err
 	}
@@ -425,7 +437,7 @@ func (a *Acme) createAccountPrivateKey(ctx context.Context, sel cmmeta.SecretKey
 			Namespace: ns,
 		},
 		Data: map[string][]byte{
-			sel.Key: pki.EncodePKCS1PrivateKey(accountPrivKey),
+			sel.Key: keyData,
 		},
 	}, metav1.CreateOptions{})
 
@@ -19,7 +19,9 @@ package acme
 import (
 	"context"
 	"crypto"
-	"crypto/rsa"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -68,6 +70,7 @@ func TestAcme_Setup(t *testing.T) {
 		issuerSecretKeyName = "test"
 
 		ecdsaPrivKey = mustGenerateEDCSAKey(t)
+		p224PrivKey  = mustGenerateP224Key(t)
 		rsaPrivKey   = mustGenerateRSAKey(t)
 
 		notFoundErr    = apierrors.NewNotFound(corev1.Resource("test"), "test")
@@ -125,7 +128,7 @@ func TestAcme_Setup(t *testing.T) {
 		// Error returned when creating ACME account key.
 		acmePrivKeySecretCreateErr error
 		// ACME account key created by createAccountPrivateKey.
-		acmePrivKey *rsa.PrivateKey
+		acmePrivKey crypto.Signer
 
 		eabSecret       *corev1.Secret
 		eabSecretGetErr error
@@ -136,6 +139,9 @@ func TestAcme_Setup(t *testing.T) {
 		expectedConditions []cmapi.IssuerCondition
 		expectedEvents     []string
 		wantsErr           bool
+		// if set, the JWS algorithm expected to be used by the private key
+		// passed to AddClient.
+		expectedJWSAlgorithm cmacme.JWSAlgorithm
 	}{
 		"LetsEncrypt ACME v1 prod URL specified, return early": {
 			issuer: gen.IssuerFrom(baseIssuer,
@@ -182,7 +188,7 @@ func TestAcme_Setup(t *testing.T) {
 		"ACME private key secret does not exist, account key generation is enabled, key creation succeeds": {
 			issuer:      gen.IssuerFrom(baseIssuer),
 			kfsErr:      notFoundErr,
-			acmePrivKey: rsaPrivKey.(*rsa.PrivateKey),
+			acmePrivKey: rsaPrivKey,
 			expectedConditions: []cmapi.IssuerCondition{
 				*gen.IssuerConditionFrom(readyTrueCondition)},
 			removeClientShouldBeCalled: true,
@@ -208,14 +214,57 @@ func TestAcme_Setup(t *testing.T) {
 			},
 			wantsErr: true,
 		},
-		"ACME account's key is not an RSA key": {
+		"ACME private key secret does not exist, key is generated for the configured JWS algorithm": {
+			issuer: gen.IssuerFrom(baseIssuer,
+				gen.SetIssuerACMEJWSAlgorithm(cmacme.ES384)),
+			kfsErr: notFoundErr,
+			expectedConditions: []cmapi.IssuerCondition{
+				*gen.IssuerConditionFrom(readyTrueCondition)},
+			removeClientShouldBeCalled: true,
+			addClientShouldBeCalled:    true,
+			expectedRegisteredAcc:      &acmeapi.Account{},
+			expectedJWSAlgorithm:       cmacme.ES384,
+		},
+		"ACME account's key is an ECDSA key": {
+			issuer:                     gen.IssuerFrom(baseIssuer),
+			kfsKey:                     ecdsaPrivKey,
+			removeClientShouldBeCalled: true,
+			addClientShouldBeCalled:    true,
+			expectedRegisteredAcc:      &acmeapi.Account{},
+			expectedConditions: []cmapi.IssuerCondition{
+				*gen.IssuerConditionFrom(readyTrueCondition)},
+			expectedJWSAlgorithm: cmacme.ES256,
+		},
+		"ACME account's key matches the configured JWS algorithm": {
+			issuer: gen.IssuerFrom(baseIssuer,
+				gen.SetIssuerACMEJWSAlgorithm(cmacme.RS256)),
+			kfsKey:                     rsaPrivKey,
+			removeClientShouldBeCalled: true,
+			addClientShouldBeCalled:    true,
+			expectedRegisteredAcc:      &acmeapi.Account{},
+			expectedConditions: []cmapi.IssuerCondition{
+				*gen.IssuerConditionFrom(readyTrueCondition)},
+			expectedJWSAlgorithm: cmacme.RS256,
+		},
+		"ACME account's key does not match the configured JWS algorithm": {
+			issuer: gen.IssuerFrom(baseIssuer,
+				gen.SetIssuerACMEPrivKeyRef(issuerSecretKeyName),
+				gen.SetIssuerACMEJWSAlgorithm(cmacme.ES256)),
+			kfsKey: rsaPrivKey,
+			expectedConditions: []cmapi.IssuerCondition{
+				*gen.IssuerConditionFrom(readyFalseCondition,
+					gen.SetIssuerConditionReason(errorAccountVerificationFailed),
+					gen.SetIssuerConditionMessage(fmt.Sprintf(messageTemplateJWSAlgorithmMismatch, issuerSecretKeyName, cmacme.RS256, cmacme.ES256))),
+			},
+		},
+		"ACME account's key is an ECDSA key on an unsupported curve": {
 			issuer: gen.IssuerFrom(baseIssuer,
 				gen.SetIssuerACMEPrivKeyRef(issuerSecretKeyName)),
-			kfsKey: ecdsaPrivKey,
+			kfsKey: p224PrivKey,
 			expectedConditions: []cmapi.IssuerCondition{
 				*gen.IssuerConditionFrom(readyFalseCondition,
 					gen.SetIssuerConditionReason(errorAccountVerificationFailed),
-					gen.SetIssuerConditionMessage(fmt.Sprintf(messageTemplateNotRSA, issuerSecretKeyName))),
+					gen.SetIssuerConditionMessage(fmt.Sprintf(messageTemplateUnsupportedKey, issuerSecretKeyName, "unsupported ECDSA curve P-224"))),
 			},
 		},
 		"ACME server URL is an invalid URL": {
@@ -411,8 +460,14 @@ func TestAcme_Setup(t *testing.T) {
 				RemoveClientFunc: func(string) {
 					removeClientWasCalled = true
 				},
-				AddClientFunc: func(string, cmacme.ACMEIssuer, *rsa.PrivateKey) {
+				AddClientFunc: func(_ string, _ cmacme.ACMEIssuer, pk crypto.Signer) {
 					addClientWasCalled = true
+					if test.expectedJWSAlgorithm == "" {
+						return
+					}
+					if alg, err := accounts.JWSAlgorithm(pk); err != nil || alg != test.expectedJWSAlgorithm {
+						t.Errorf("Expected account key for JWS algorithm %s, got %s (error: %v)", test.expectedJWSAlgorithm, alg, err)
+					}
 				},
 			}
 
@@ -498,7 +553,7 @@ func keyFromSecretMockBuilder(wasCalled *bool, key crypto.Signer, err error) key
 }
 
 func clientBuilderMock(cl acmecl.Interface) accounts.NewClientFunc {
-	return func(*http.Client, cmacme.ACMEIssuer, *rsa.PrivateKey) acmecl.Interface {
+	return func(*http.Client, cmacme.ACMEIssuer, crypto.Signer) acmecl.Interface {
 		return cl
 	}
 }
@@ -525,3 +580,12 @@ func mustGenerateRSAKey(t *testing.T) crypto.Signer {
 	}
 	return key
 }
+
+func mustGenerateP224Key(t *testing.T) crypto.Signer {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
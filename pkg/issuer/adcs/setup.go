@@ -0,0 +1,66 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package adcs
+
+import (
+	"context"
+
+	apiutil "github.com/jetstack/cert-manager/pkg/api/util"
+	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+	adcsinternal "github.com/jetstack/cert-manager/pkg/internal/adcs"
+	logf "github.com/jetstack/cert-manager/pkg/logs"
+)
+
+const (
+	successADCSVerified = "ADCSVerified"
+	messageADCSVerified = "ADCS verified"
+
+	errorADCS = "ADCSError"
+
+	messageADCSConfigRequired    = "ADCS config cannot be empty"
+	messageADCSClientInitFailed  = "Failed to initialize ADCS client: "
+	messageADCSCACertFetchFailed = "Failed to retrieve ADCS CA certificate: "
+)
+
+func (a *ADCS) Setup(ctx context.Context) error {
+	if a.issuer.GetSpec().ADCSWebEnrollment == nil {
+		logf.V(logf.WarnLevel).Infof("%s: %s", a.issuer.GetObjectMeta().Name, messageADCSConfigRequired)
+		apiutil.SetIssuerCondition(a.issuer, a.issuer.GetGeneration(), v1.IssuerConditionReady, cmmeta.ConditionFalse, errorADCS, messageADCSConfigRequired)
+		return nil
+	}
+
+	client, err := adcsinternal.NewBuilder(a.IssuerOptions.IssuerBackendCABundle)(a.resourceNamespace, a.secretsLister, a.issuer)
+	if err != nil {
+		s := messageADCSClientInitFailed + err.Error()
+		logf.V(logf.WarnLevel).Infof("%s: %s", a.issuer.GetObjectMeta().Name, s)
+		apiutil.SetIssuerCondition(a.issuer, a.issuer.GetGeneration(), v1.IssuerConditionReady, cmmeta.ConditionFalse, errorADCS, s)
+		return err
+	}
+
+	// Fetching the CA certificate verifies that the web enrollment service
+	// is reachable and accepts the configured credentials.
+	if _, err := client.CACertificate(ctx); err != nil {
+		s := messageADCSCACertFetchFailed + err.Error()
+		logf.V(logf.WarnLevel).Infof("%s: %s", a.issuer.GetObjectMeta().Name, s)
+		apiutil.SetIssuerCondition(a.issuer, a.issuer.GetGeneration(), v1.IssuerConditionReady, cmmeta.ConditionFalse, errorADCS, s)
+		return err
+	}
+
+	logf.Log.V(logf.DebugLevel).Info(messageADCSVerified)
+	apiutil.SetIssuerCondition(a.issuer, a.issuer.GetGeneration(), v1.IssuerConditionReady, cmmeta.ConditionTrue, successADCSVerified, messageADCSVerified)
+	return nil
+}
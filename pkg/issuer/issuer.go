@@ -18,6 +18,7 @@ package issuer
 
 import (
 	"context"
+	"time"
 )
 
 type Interface interface {
@@ -44,4 +45,9 @@ type IssueResponse struct {
 	// This field should only be set if the private key field is set, similar
 	// to the Certificate field.
 	CA []byte
+
+	// RequeueAfter, if set and no Certificate is returned, is how long to
+	// wait before attempting to sign the request again. This allows an issuer
+	// to delay a request without it being treated as an error.
+	RequeueAfter time.Duration
 }
@@ -41,7 +41,8 @@ const (
 	messageAuthFieldsRequired            = "Vault tokenSecretRef, appRole, or kubernetes is required"
 	messageMultipleAuthFieldsSet         = "Multiple auth methods cannot be set on the same Vault issuer"
 
-	messageKubeAuthFieldsRequired    = "Vault Kubernetes auth requires both role and secretRef.name"
+	messageKubeAuthFieldsRequired    = "Vault Kubernetes auth requires role and exactly one of secretRef.name or tokenPath"
+	messageKubeAuthAmbientDisabled   = "Vault Kubernetes auth tokenPath cannot be used as ambient credentials are disabled for this issuer"
 	messageTokenAuthNameRequired     = "Vault Token auth requires tokenSecretRef.name"
 	messageAppRoleAuthFieldsRequired = "Vault AppRole auth requires both roleId and tokenSecretRef.name"
 )
@@ -96,13 +97,21 @@ func (v *Vault) Setup(ctx context.Context) error {
 	}
 
 	// check if all mandatory Vault Kubernetes fields are set.
-	if kubeAuth != nil && (len(kubeAuth.SecretRef.Name) == 0 || len(kubeAuth.Role) == 0) {
+	if kubeAuth != nil && (len(kubeAuth.Role) == 0 || (len(kubeAuth.SecretRef.Name) == 0) == (len(kubeAuth.TokenPath) == 0)) {
 		logf.V(logf.WarnLevel).Infof("%s: %s", v.issuer.GetObjectMeta().Name, messageKubeAuthFieldsRequired)
 		apiutil.SetIssuerCondition(v.issuer, v.issuer.GetGeneration(), v1.IssuerConditionReady, cmmeta.ConditionFalse, errorVault, messageKubeAuthFieldsRequired)
 		return nil
 	}
 
-	client, err := vaultinternal.New(v.resourceNamespace, v.secretsLister, v.issuer)
+	// reading the token from a file in the controller's pod is a form of
+	// ambient credentials.
+	if kubeAuth != nil && len(kubeAuth.TokenPath) > 0 && !v.IssuerOptions.CanUseAmbientCredentials(v.issuer) {
+		logf.V(logf.WarnLevel).Infof("%s: %s", v.issuer.GetObjectMeta().Name, messageKubeAuthAmbientDisabled)
+		apiutil.SetIssuerCondition(v.issuer, v.issuer.GetGeneration(), v1.IssuerConditionReady, cmmeta.ConditionFalse, errorVault, messageKubeAuthAmbientDisabled)
+		return nil
+	}
+
+	client, err := vaultinternal.NewBuilder(v.IssuerOptions.IssuerBackendCABundle, v.IssuerOptions.CanUseAmbientCredentials)(v.resourceNamespace, v.secretsLister, v.issuer)
 	if err != nil {
 		s := messageVaultClientInitFailed + err.Error()
 		logf.V(logf.WarnLevel).Infof("%s: %s", v.issuer.GetObjectMeta().Name, s)
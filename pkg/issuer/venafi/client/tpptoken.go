@@ -0,0 +1,189 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/clock"
+)
+
+const (
+	tppRefreshTokenKey      = "refresh-token"
+	tppAccessTokenExpiryKey = "access-token-expiry"
+	tppClientIDKey          = "client-id"
+
+	// defaultTPPClientID is the client ID used when refreshing access tokens
+	// if the credentials Secret does not contain one.
+	defaultTPPClientID = "cert-manager.io"
+
+	// tppAccessTokenRefreshMargin is how long before its expiry an access
+	// token will be refreshed, so that it does not expire mid-request.
+	tppAccessTokenRefreshMargin = time.Minute * 5
+)
+
+// tppTokenResponse is the response body returned by the Venafi TPP token
+// endpoint.
+type tppTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	// Expires is the unix time at which the access token expires.
+	Expires int64 `json:"expires"`
+}
+
+// tppTokenRefresher refreshes Venafi TPP OAuth access tokens using the refresh
+// token stored in a TPP credentials Secret, and writes the new tokens back to
+// that Secret.
+type tppTokenRefresher struct {
+	kubeClient kubernetes.Interface
+	clock      clock.Clock
+}
+
+func newTPPTokenRefresher(kubeClient kubernetes.Interface) *tppTokenRefresher {
+	return &tppTokenRefresher{
+		kubeClient: kubeClient,
+		clock:      clock.RealClock{},
+	}
+}
+
+// accessToken returns the access token to use for the given TPP credentials
+// Secret. If the Secret contains a refresh token and the access token is
+// missing, its expiry is unknown or it is due to expire, a new access token
+// is requested from the TPP instance at baseURL and stored in the Secret.
+func (r *tppTokenRefresher) accessToken(baseURL, caBundle string, secret *corev1.Secret) (string, error) {
+	accessToken := string(secret.Data[tppAccessTokenKey])
+	refreshToken := string(secret.Data[tppRefreshTokenKey])
+	if len(refreshToken) == 0 {
+		return accessToken, nil
+	}
+
+	if len(accessToken) > 0 {
+		expiry, err := time.Parse(time.RFC3339, string(secret.Data[tppAccessTokenExpiryKey]))
+		if err == nil && r.clock.Now().Add(tppAccessTokenRefreshMargin).Before(expiry) {
+			return accessToken, nil
+		}
+	}
+
+	clientID := string(secret.Data[tppClientIDKey])
+	if len(clientID) == 0 {
+		clientID = defaultTPPClientID
+	}
+
+	resp, err := requestTPPToken(baseURL, caBundle, clientID, refreshToken)
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh Venafi TPP access token: %v", err)
+	}
+
+	secret = secret.DeepCopy()
+	secret.Data[tppAccessTokenKey] = []byte(resp.AccessToken)
+	secret.Data[tppAccessTokenExpiryKey] = []byte(time.Unix(resp.Expires, 0).UTC().Format(time.RFC3339))
+	// TPP may issue a new refresh token alongside the access token, in which
+	// case the previous refresh token can no longer be used.
+	if len(resp.RefreshToken) > 0 {
+		secret.Data[tppRefreshTokenKey] = []byte(resp.RefreshToken)
+	}
+
+	_, err = r.kubeClient.CoreV1().Secrets(secret.Namespace).Update(context.TODO(), secret, metav1.UpdateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to store refreshed Venafi TPP access token in secret %s/%s: %v", secret.Namespace, secret.Name, err)
+	}
+
+	return resp.AccessToken, nil
+}
+
+// requestTPPToken exchanges a refresh token for a new access token using the
+// token endpoint of the TPP instance at baseURL.
+func requestTPPToken(baseURL, caBundle, clientID, refreshToken string) (*tppTokenResponse, error) {
+	body, err := json.Marshal(map[string]string{
+		"client_id":     clientID,
+		"refresh_token": refreshToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient, err := tppHTTPClient(caBundle)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := httpClient.Post(tppTokenURL(baseURL), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from token endpoint: %s", resp.StatusCode, respBody)
+	}
+
+	var token tppTokenResponse
+	if err := json.Unmarshal(respBody, &token); err != nil {
+		return nil, fmt.Errorf("failed to decode token endpoint response: %v", err)
+	}
+	if len(token.AccessToken) == 0 {
+		return nil, fmt.Errorf("token endpoint did not return an access token")
+	}
+
+	return &token, nil
+}
+
+// tppTokenURL returns the URL of the token endpoint for the TPP instance at
+// baseURL. Issuers are usually configured with the URL of the /vedsdk API,
+// whereas tokens are served by /vedauth.
+func tppTokenURL(baseURL string) string {
+	u := strings.TrimSuffix(baseURL, "/")
+	if strings.HasSuffix(strings.ToLower(u), "/vedsdk") {
+		u = u[:len(u)-len("/vedsdk")]
+	}
+	return u + "/vedauth/authorize/token"
+}
+
+// tppHTTPClient returns a HTTP client which only trusts the given PEM encoded
+// CA bundle, or the system root CAs if caBundle is empty.
+func tppHTTPClient(caBundle string) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if len(caBundle) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(caBundle)) {
+			return nil, fmt.Errorf("failed to parse CA bundle")
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   time.Second * 30,
+	}, nil
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	fakeclock "k8s.io/utils/clock/testing"
+)
+
+func TestTPPTokenRefresherAccessToken(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	newExpiry := now.Add(time.Hour)
+
+	baseSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "tpp-credentials", Namespace: "test-namespace"},
+		Data: map[string][]byte{
+			tppAccessTokenKey:  []byte("old-access-token"),
+			tppRefreshTokenKey: []byte("old-refresh-token"),
+		},
+	}
+	withData := func(data map[string]string) *corev1.Secret {
+		secret := baseSecret.DeepCopy()
+		for k, v := range data {
+			if v == "" {
+				delete(secret.Data, k)
+				continue
+			}
+			secret.Data[k] = []byte(v)
+		}
+		return secret
+	}
+	refreshedData := map[string][]byte{
+		tppAccessTokenKey:       []byte("new-access-token"),
+		tppRefreshTokenKey:      []byte("new-refresh-token"),
+		tppAccessTokenExpiryKey: []byte(newExpiry.Format(time.RFC3339)),
+	}
+
+	tests := map[string]struct {
+		secret       *corev1.Secret
+		responseCode int
+
+		expectedClientID  string
+		expectedToken     string
+		expectedData      map[string][]byte
+		expectedRefreshed bool
+		expectedErr       bool
+	}{
+		"if there is no refresh token, the access token should be used as is": {
+			secret:        withData(map[string]string{tppRefreshTokenKey: ""}),
+			expectedToken: "old-access-token",
+			expectedData:  map[string][]byte{tppAccessTokenKey: []byte("old-access-token")},
+		},
+		"if the access token has not expired, it should be used as is": {
+			secret:        withData(map[string]string{tppAccessTokenExpiryKey: now.Add(time.Hour).Format(time.RFC3339)}),
+			expectedToken: "old-access-token",
+			expectedData: map[string][]byte{
+				tppAccessTokenKey:       []byte("old-access-token"),
+				tppRefreshTokenKey:      []byte("old-refresh-token"),
+				tppAccessTokenExpiryKey: []byte(now.Add(time.Hour).Format(time.RFC3339)),
+			},
+		},
+		"if the access token has expired, it should be refreshed and stored": {
+			secret:            withData(map[string]string{tppAccessTokenExpiryKey: now.Add(-time.Minute).Format(time.RFC3339)}),
+			responseCode:      http.StatusOK,
+			expectedClientID:  defaultTPPClientID,
+			expectedToken:     "new-access-token",
+			expectedData:      refreshedData,
+			expectedRefreshed: true,
+		},
+		"if the access token is about to expire, it should be refreshed and stored": {
+			secret:            withData(map[string]string{tppAccessTokenExpiryKey: now.Add(time.Minute).Format(time.RFC3339)}),
+			responseCode:      http.StatusOK,
+			expectedClientID:  defaultTPPClientID,
+			expectedToken:     "new-access-token",
+			expectedData:      refreshedData,
+			expectedRefreshed: true,
+		},
+		"if the access token expiry is unknown, it should be refreshed and stored": {
+			secret:            baseSecret,
+			responseCode:      http.StatusOK,
+			expectedClientID:  defaultTPPClientID,
+			expectedToken:     "new-access-token",
+			expectedData:      refreshedData,
+			expectedRefreshed: true,
+		},
+		"if there is no access token, one should be requested and stored": {
+			secret:            withData(map[string]string{tppAccessTokenKey: ""}),
+			responseCode:      http.StatusOK,
+			expectedClientID:  defaultTPPClientID,
+			expectedToken:     "new-access-token",
+			expectedData:      refreshedData,
+			expectedRefreshed: true,
+		},
+		"if a client ID is set, it should be used to refresh the access token": {
+			secret:           withData(map[string]string{tppClientIDKey: "my-client"}),
+			responseCode:     http.StatusOK,
+			expectedClientID: "my-client",
+			expectedToken:    "new-access-token",
+			expectedData: map[string][]byte{
+				tppClientIDKey:          []byte("my-client"),
+				tppAccessTokenKey:       []byte("new-access-token"),
+				tppRefreshTokenKey:      []byte("new-refresh-token"),
+				tppAccessTokenExpiryKey: []byte(newExpiry.Format(time.RFC3339)),
+			},
+			expectedRefreshed: true,
+		},
+		"if the token endpoint rejects the refresh token, should error and not modify the secret": {
+			secret:            baseSecret,
+			responseCode:      http.StatusBadRequest,
+			expectedClientID:  defaultTPPClientID,
+			expectedData:      baseSecret.Data,
+			expectedRefreshed: true,
+			expectedErr:       true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			refreshed := false
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				refreshed = true
+				if r.Method != http.MethodPost || r.URL.Path != "/vedauth/authorize/token" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+
+				var req map[string]string
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Errorf("failed to decode request: %v", err)
+				}
+				if req["client_id"] != test.expectedClientID {
+					t.Errorf("unexpected client_id, exp=%q got=%q", test.expectedClientID, req["client_id"])
+				}
+				if req["refresh_token"] != "old-refresh-token" {
+					t.Errorf("unexpected refresh_token: %q", req["refresh_token"])
+				}
+
+				w.WriteHeader(test.responseCode)
+				if test.responseCode != http.StatusOK {
+					w.Write([]byte(`{"error":"invalid_grant"}`))
+					return
+				}
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"access_token":  "new-access-token",
+					"refresh_token": "new-refresh-token",
+					"expires":       newExpiry.Unix(),
+					"token_type":    "Bearer",
+				})
+			}))
+			defer server.Close()
+
+			kubeClient := fake.NewSimpleClientset(test.secret)
+			r := &tppTokenRefresher{
+				kubeClient: kubeClient,
+				clock:      fakeclock.NewFakeClock(now),
+			}
+
+			token, err := r.accessToken(server.URL+"/vedsdk", "", test.secret)
+			if (err != nil) != test.expectedErr {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expectedErr, err)
+			}
+			if token != test.expectedToken {
+				t.Errorf("unexpected access token, exp=%q got=%q", test.expectedToken, token)
+			}
+			if refreshed != test.expectedRefreshed {
+				t.Errorf("unexpected refresh, exp=%t got=%t", test.expectedRefreshed, refreshed)
+			}
+
+			secret, err := kubeClient.CoreV1().Secrets(test.secret.Namespace).Get(context.TODO(), test.secret.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(secret.Data) != len(test.expectedData) {
+				t.Errorf("unexpected secret data, exp=%q got=%q", test.expectedData, secret.Data)
+			}
+			for k, v := range test.expectedData {
+				if string(secret.Data[k]) != string(v) {
+					t.Errorf("unexpected secret data for key %q, exp=%q got=%q", k, v, secret.Data[k])
+				}
+			}
+		})
+	}
+}
+
+func TestTPPTokenURL(t *testing.T) {
+	tests := map[string]string{
+		"https://tpp.example.com/vedsdk":  "https://tpp.example.com/vedauth/authorize/token",
+		"https://tpp.example.com/vedsdk/": "https://tpp.example.com/vedauth/authorize/token",
+		"https://tpp.example.com/VEDSDK":  "https://tpp.example.com/vedauth/authorize/token",
+		"https://tpp.example.com":         "https://tpp.example.com/vedauth/authorize/token",
+		"https://tpp.example.com/":        "https://tpp.example.com/vedauth/authorize/token",
+	}
+
+	for baseURL, expected := range tests {
+		t.Run(baseURL, func(t *testing.T) {
+			if got := tppTokenURL(baseURL); got != expected {
+				t.Errorf("unexpected token URL, exp=%q got=%q", expected, got)
+			}
+		})
+	}
+}
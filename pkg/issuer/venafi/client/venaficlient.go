@@ -23,6 +23,7 @@ import (
 	vcert "github.com/Venafi/vcert/v4"
 	"github.com/Venafi/vcert/v4/pkg/certificate"
 	"github.com/Venafi/vcert/v4/pkg/endpoint"
+	"k8s.io/client-go/kubernetes"
 	corelisters "k8s.io/client-go/listers/core/v1"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
@@ -71,7 +72,24 @@ type connector interface {
 }
 
 func New(namespace string, secretsLister corelisters.SecretLister, issuer cmapi.GenericIssuer) (Interface, error) {
-	cfg, err := configForIssuer(issuer, secretsLister, namespace)
+	return newVenafi(namespace, secretsLister, issuer, nil, nil)
+}
+
+// NewBuilder returns a VenafiClientBuilder that builds clients which only
+// trust the certificates in the given PEM encoded CA bundle when connecting to
+// Venafi TPP or Venafi Cloud. If caBundle is empty, the system root CAs are
+// used.
+// If a TPP credentials Secret contains a refresh token, expired access tokens
+// are refreshed and written back to the Secret using kubeClient.
+func NewBuilder(caBundle []byte, kubeClient kubernetes.Interface) VenafiClientBuilder {
+	refresher := newTPPTokenRefresher(kubeClient)
+	return func(namespace string, secretsLister corelisters.SecretLister, issuer cmapi.GenericIssuer) (Interface, error) {
+		return newVenafi(namespace, secretsLister, issuer, caBundle, refresher)
+	}
+}
+
+func newVenafi(namespace string, secretsLister corelisters.SecretLister, issuer cmapi.GenericIssuer, backendCABundle []byte, refresher *tppTokenRefresher) (Interface, error) {
+	cfg, err := configForIssuer(issuer, secretsLister, namespace, backendCABundle, refresher)
 	if err != nil {
 		return nil, err
 	}
@@ -90,7 +108,11 @@ func New(namespace string, secretsLister corelisters.SecretLister, issuer cmapi.
 
 // configForIssuer will convert a cert-manager Venafi issuer into a vcert.Config
 // that can be used to instantiate an API client.
-func configForIssuer(iss cmapi.GenericIssuer, secretsLister corelisters.SecretLister, namespace string) (*vcert.Config, error) {
+// If backendCABundle is set, it will be used as the connection trust for
+// issuers that do not specify their own CA bundle.
+// If refresher is set, TPP access tokens will be refreshed using the refresh
+// token in the credentials Secret when they are due to expire.
+func configForIssuer(iss cmapi.GenericIssuer, secretsLister corelisters.SecretLister, namespace string, backendCABundle []byte, refresher *tppTokenRefresher) (*vcert.Config, error) {
 	venCfg := iss.GetSpec().Venafi
 	switch {
 	case venCfg.TPP != nil:
@@ -104,6 +126,16 @@ func configForIssuer(iss cmapi.GenericIssuer, secretsLister corelisters.SecretLi
 		password := string(tppSecret.Data[tppPasswordKey])
 		accessToken := string(tppSecret.Data[tppAccessTokenKey])
 		caBundle := string(tpp.CABundle)
+		if len(caBundle) == 0 {
+			caBundle = string(backendCABundle)
+		}
+
+		if refresher != nil {
+			accessToken, err = refresher.accessToken(tpp.URL, caBundle, tppSecret)
+			if err != nil {
+				return nil, err
+			}
+		}
 
 		return &vcert.Config{
 			ConnectorType: endpoint.ConnectorTypeTPP,
@@ -136,7 +168,8 @@ func configForIssuer(iss cmapi.GenericIssuer, secretsLister corelisters.SecretLi
 			BaseUrl:       cloud.URL,
 			Zone:          venCfg.Zone,
 			// always enable verbose logging for now
-			LogVerbose: true,
+			LogVerbose:      true,
+			ConnectionTrust: string(backendCABundle),
 			Credentials: &endpoint.Authentication{
 				APIKey: apiKey,
 			},
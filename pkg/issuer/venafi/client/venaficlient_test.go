@@ -66,6 +66,8 @@ func TestConfigForIssuerT(t *testing.T) {
 	accessToken := "KT2EEVTIjWM/37L78dqJAg=="
 	apiKey := "test-api-key"
 	customKey := "test-custom-key"
+	backendCABundle := "backend-ca-bundle"
+	issuerCABundle := "issuer-ca-bundle"
 
 	baseIssuer := gen.Issuer("non-venafi-issue",
 		gen.SetIssuerVenafi(cmapi.VenafiIssuer{}),
@@ -178,6 +180,58 @@ func TestConfigForIssuerT(t *testing.T) {
 			},
 			expectedErr: false,
 		},
+		"if TPP without a CA bundle, should use the backend CA bundle": {
+			iss: tppIssuer,
+			secretsLister: generateSecretLister(&corev1.Secret{
+				Data: map[string][]byte{
+					tppAccessTokenKey: []byte(accessToken),
+				},
+			}, nil),
+			backendCABundle: []byte(backendCABundle),
+			CheckFn: func(t *testing.T, cnf *vcert.Config) {
+				if trust := cnf.ConnectionTrust; trust != backendCABundle {
+					t.Errorf("got unexpected connection trust: %q", trust)
+				}
+			},
+			expectedErr: false,
+		},
+		"if TPP with a CA bundle, should prefer it over the backend CA bundle": {
+			iss: gen.IssuerFrom(baseIssuer,
+				gen.SetIssuerVenafi(cmapi.VenafiIssuer{
+					Zone: zone,
+					TPP: &cmapi.VenafiTPP{
+						CABundle: []byte(issuerCABundle),
+					},
+				}),
+			),
+			secretsLister: generateSecretLister(&corev1.Secret{
+				Data: map[string][]byte{
+					tppAccessTokenKey: []byte(accessToken),
+				},
+			}, nil),
+			backendCABundle: []byte(backendCABundle),
+			CheckFn: func(t *testing.T, cnf *vcert.Config) {
+				if trust := cnf.ConnectionTrust; trust != issuerCABundle {
+					t.Errorf("got unexpected connection trust: %q", trust)
+				}
+			},
+			expectedErr: false,
+		},
+		"if Cloud, should use the backend CA bundle": {
+			iss: cloudIssuer,
+			secretsLister: generateSecretLister(&corev1.Secret{
+				Data: map[string][]byte{
+					defaultAPIKeyKey: []byte(apiKey),
+				},
+			}, nil),
+			backendCABundle: []byte(backendCABundle),
+			CheckFn: func(t *testing.T, cnf *vcert.Config) {
+				if trust := cnf.ConnectionTrust; trust != backendCABundle {
+					t.Errorf("got unexpected connection trust: %q", trust)
+				}
+			},
+			expectedErr: false,
+		},
 		"if TPP and Cloud, should chose TPP": {
 			iss: gen.IssuerFrom(baseIssuer,
 				gen.SetIssuerVenafi(cmapi.VenafiIssuer{
@@ -213,8 +267,9 @@ func TestConfigForIssuerT(t *testing.T) {
 }
 
 type testConfigForIssuerT struct {
-	iss           cmapi.GenericIssuer
-	secretsLister corelisters.SecretLister
+	iss             cmapi.GenericIssuer
+	secretsLister   corelisters.SecretLister
+	backendCABundle []byte
 
 	expectedErr bool
 
@@ -222,7 +277,7 @@ type testConfigForIssuerT struct {
 }
 
 func (c *testConfigForIssuerT) runTest(t *testing.T) {
-	resp, err := configForIssuer(c.iss, c.secretsLister, "test-namespace")
+	resp, err := configForIssuer(c.iss, c.secretsLister, "test-namespace", c.backendCABundle, nil)
 	if err != nil && !c.expectedErr {
 		t.Errorf("expected to not get an error, but got: %v", err)
 	}
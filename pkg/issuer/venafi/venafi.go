@@ -51,7 +51,7 @@ func NewVenafi(ctx *controller.Context, issuer cmapi.GenericIssuer) (issuer.Inte
 		issuer:            issuer,
 		secretsLister:     ctx.KubeSharedInformerFactory.Core().V1().Secrets().Lister(),
 		resourceNamespace: ctx.IssuerOptions.ResourceNamespace(issuer),
-		clientBuilder:     client.New,
+		clientBuilder:     client.NewBuilder(ctx.IssuerOptions.IssuerBackendCABundle, ctx.Client),
 		Context:           ctx,
 		log:               logf.Log.WithName("venafi"),
 	}, nil
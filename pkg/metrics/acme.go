@@ -21,6 +21,8 @@ limitations under the License.
 // acme_client_request_count{"scheme", "host", "path", "method", "status"}
 // acme_client_request_duration_seconds{"scheme", "host", "path", "method", "status"}
 // controller_sync_call_count{"controller"}
+// dns01_self_check_duration_seconds{"domain", "nameserver_mode"}
+// certificaterequest_issuance_duration_seconds{"issuer_kind", "issuer_group", "controller"}
 package metrics
 
 import (
@@ -36,3 +38,10 @@ func (m *Metrics) ObserveACMERequestDuration(duration time.Duration, labels ...s
 func (m *Metrics) IncrementACMERequestCount(labels ...string) {
 	m.acmeClientRequestCount.WithLabelValues(labels...).Inc()
 }
+
+// ObserveDNS01SelfCheckDuration increases bucket counters for the duration of
+// a DNS01 self check of the given domain. nameserverMode is the kind of
+// nameservers that were queried, either "authoritative" or "recursive".
+func (m *Metrics) ObserveDNS01SelfCheckDuration(duration time.Duration, domain, nameserverMode string) {
+	m.dns01SelfCheckDurationSeconds.WithLabelValues(domain, nameserverMode).Observe(duration.Seconds())
+}
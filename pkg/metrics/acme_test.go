@@ -0,0 +1,58 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	logtesting "github.com/jetstack/cert-manager/pkg/logs/testing"
+)
+
+func TestObserveDNS01SelfCheckDuration(t *testing.T) {
+	m := New(logtesting.TestLogger{T: t})
+	m.ObserveDNS01SelfCheckDuration(3*time.Second, "example.com", "recursive")
+	m.ObserveDNS01SelfCheckDuration(100*time.Second, "example.com", "recursive")
+
+	if err := testutil.CollectAndCompare(m.dns01SelfCheckDurationSeconds,
+		strings.NewReader(`
+	# HELP certmanager_dns01_self_check_duration_seconds The time taken by self checks of ACME DNS01 challenges.
+	# TYPE certmanager_dns01_self_check_duration_seconds histogram
+	certmanager_dns01_self_check_duration_seconds_bucket{domain="example.com",nameserver_mode="recursive",le="0.5"} 0
+	certmanager_dns01_self_check_duration_seconds_bucket{domain="example.com",nameserver_mode="recursive",le="1"} 0
+	certmanager_dns01_self_check_duration_seconds_bucket{domain="example.com",nameserver_mode="recursive",le="2"} 0
+	certmanager_dns01_self_check_duration_seconds_bucket{domain="example.com",nameserver_mode="recursive",le="4"} 1
+	certmanager_dns01_self_check_duration_seconds_bucket{domain="example.com",nameserver_mode="recursive",le="8"} 1
+	certmanager_dns01_self_check_duration_seconds_bucket{domain="example.com",nameserver_mode="recursive",le="16"} 1
+	certmanager_dns01_self_check_duration_seconds_bucket{domain="example.com",nameserver_mode="recursive",le="32"} 1
+	certmanager_dns01_self_check_duration_seconds_bucket{domain="example.com",nameserver_mode="recursive",le="64"} 1
+	certmanager_dns01_self_check_duration_seconds_bucket{domain="example.com",nameserver_mode="recursive",le="128"} 2
+	certmanager_dns01_self_check_duration_seconds_bucket{domain="example.com",nameserver_mode="recursive",le="256"} 2
+	certmanager_dns01_self_check_duration_seconds_bucket{domain="example.com",nameserver_mode="recursive",le="512"} 2
+	certmanager_dns01_self_check_duration_seconds_bucket{domain="example.com",nameserver_mode="recursive",le="1024"} 2
+	certmanager_dns01_self_check_duration_seconds_bucket{domain="example.com",nameserver_mode="recursive",le="+Inf"} 2
+	certmanager_dns01_self_check_duration_seconds_sum{domain="example.com",nameserver_mode="recursive"} 103
+	certmanager_dns01_self_check_duration_seconds_count{domain="example.com",nameserver_mode="recursive"} 2
+`),
+		"certmanager_dns01_self_check_duration_seconds",
+	); err != nil {
+		t.Errorf("unexpected collecting result:\n%s", err)
+	}
+}
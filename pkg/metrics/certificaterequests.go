@@ -0,0 +1,38 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics contains global structures related to metrics collection
+// cert-manager exposes the following metrics:
+// certificate_expiration_timestamp_seconds{name, namespace}
+// certificate_ready_status{name, namespace, condition}
+// acme_client_request_count{"scheme", "host", "path", "method", "status"}
+// acme_client_request_duration_seconds{"scheme", "host", "path", "method", "status"}
+// controller_sync_call_count{"controller"}
+// dns01_self_check_duration_seconds{"domain", "nameserver_mode"}
+// certificaterequest_issuance_duration_seconds{"issuer_kind", "issuer_group", "controller"}
+package metrics
+
+import (
+	"time"
+)
+
+// ObserveCertificateRequestIssuanceDuration increases bucket counters for the
+// time taken for a CertificateRequest to be issued or to fail. controller is
+// the issuer type of the CertificateRequest controller that signed it, e.g.
+// "acme" or "vault".
+func (m *Metrics) ObserveCertificateRequestIssuanceDuration(duration time.Duration, issuerKind, issuerGroup, controller string) {
+	m.certificateRequestIssuanceDurationSeconds.WithLabelValues(issuerKind, issuerGroup, controller).Observe(duration.Seconds())
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	logtesting "github.com/jetstack/cert-manager/pkg/logs/testing"
+)
+
+func TestObserveCertificateRequestIssuanceDuration(t *testing.T) {
+	m := New(logtesting.TestLogger{T: t})
+	m.ObserveCertificateRequestIssuanceDuration(5*time.Second, "ClusterIssuer", "cert-manager.io", "ca")
+	m.ObserveCertificateRequestIssuanceDuration(300*time.Second, "ClusterIssuer", "cert-manager.io", "ca")
+
+	if err := testutil.CollectAndCompare(m.certificateRequestIssuanceDurationSeconds,
+		strings.NewReader(`
+	# HELP certmanager_certificaterequest_issuance_duration_seconds The time taken from the creation of a CertificateRequest until it is issued or has failed.
+	# TYPE certmanager_certificaterequest_issuance_duration_seconds histogram
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{controller="ca",issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",le="0.1"} 0
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{controller="ca",issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",le="0.2"} 0
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{controller="ca",issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",le="0.4"} 0
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{controller="ca",issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",le="0.8"} 0
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{controller="ca",issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",le="1.6"} 0
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{controller="ca",issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",le="3.2"} 0
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{controller="ca",issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",le="6.4"} 1
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{controller="ca",issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",le="12.8"} 1
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{controller="ca",issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",le="25.6"} 1
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{controller="ca",issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",le="51.2"} 1
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{controller="ca",issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",le="102.4"} 1
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{controller="ca",issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",le="204.8"} 1
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{controller="ca",issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",le="409.6"} 2
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{controller="ca",issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",le="819.2"} 2
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{controller="ca",issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",le="1638.4"} 2
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{controller="ca",issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",le="3276.8"} 2
+	certmanager_certificaterequest_issuance_duration_seconds_bucket{controller="ca",issuer_group="cert-manager.io",issuer_kind="ClusterIssuer",le="+Inf"} 2
+	certmanager_certificaterequest_issuance_duration_seconds_sum{controller="ca",issuer_group="cert-manager.io",issuer_kind="ClusterIssuer"} 305
+	certmanager_certificaterequest_issuance_duration_seconds_count{controller="ca",issuer_group="cert-manager.io",issuer_kind="ClusterIssuer"} 2
+`),
+		"certmanager_certificaterequest_issuance_duration_seconds",
+	); err != nil {
+		t.Errorf("unexpected collecting result:\n%s", err)
+	}
+}
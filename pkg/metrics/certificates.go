@@ -21,6 +21,8 @@ limitations under the License.
 // acme_client_request_count{"scheme", "host", "path", "method", "status"}
 // acme_client_request_duration_seconds{"scheme", "host", "path", "method", "status"}
 // controller_sync_call_count{"controller"}
+// dns01_self_check_duration_seconds{"domain", "nameserver_mode"}
+// certificaterequest_issuance_duration_seconds{"issuer_kind", "issuer_group", "controller"}
 package metrics
 
 import (
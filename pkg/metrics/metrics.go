@@ -21,6 +21,8 @@ limitations under the License.
 // acme_client_request_count{"scheme", "host", "path", "method", "status"}
 // acme_client_request_duration_seconds{"scheme", "host", "path", "method", "status"}
 // controller_sync_call_count{"controller"}
+// dns01_self_check_duration_seconds{"domain", "nameserver_mode"}
+// certificaterequest_issuance_duration_seconds{"issuer_kind", "issuer_group", "controller"}
 package metrics
 
 import (
@@ -60,6 +62,9 @@ type Metrics struct {
 	acmeClientRequestDurationSeconds *prometheus.SummaryVec
 	acmeClientRequestCount           *prometheus.CounterVec
 	controllerSyncCallCount          *prometheus.CounterVec
+	dns01SelfCheckDurationSeconds    *prometheus.HistogramVec
+
+	certificateRequestIssuanceDurationSeconds *prometheus.HistogramVec
 }
 
 var readyConditionStatuses = [...]cmmeta.ConditionStatus{cmmeta.ConditionTrue, cmmeta.ConditionFalse, cmmeta.ConditionUnknown}
@@ -117,6 +122,33 @@ func New(log logr.Logger) *Metrics {
 			},
 			[]string{"controller"},
 		)
+
+		// dns01SelfCheckDurationSeconds is a Prometheus histogram to collect
+		// the time taken by DNS01 self checks, which include waiting for
+		// records to propagate to the nameservers that are queried.
+		dns01SelfCheckDurationSeconds = prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "dns01_self_check_duration_seconds",
+				Help:      "The time taken by self checks of ACME DNS01 challenges.",
+				Buckets:   prometheus.ExponentialBuckets(0.5, 2, 12),
+			},
+			[]string{"domain", "nameserver_mode"},
+		)
+
+		// certificateRequestIssuanceDurationSeconds is a Prometheus histogram
+		// to collect the time taken for CertificateRequests to be issued or
+		// to fail, shared by the CertificateRequest controllers of all
+		// issuer types.
+		certificateRequestIssuanceDurationSeconds = prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "certificaterequest_issuance_duration_seconds",
+				Help:      "The time taken from the creation of a CertificateRequest until it is issued or has failed.",
+				Buckets:   prometheus.ExponentialBuckets(0.1, 2, 16),
+			},
+			[]string{"issuer_kind", "issuer_group", "controller"},
+		)
 	)
 
 	// Create server and register Prometheus metrics handler
@@ -129,6 +161,9 @@ func New(log logr.Logger) *Metrics {
 		acmeClientRequestCount:           acmeClientRequestCount,
 		acmeClientRequestDurationSeconds: acmeClientRequestDurationSeconds,
 		controllerSyncCallCount:          controllerSyncCallCount,
+		dns01SelfCheckDurationSeconds:    dns01SelfCheckDurationSeconds,
+
+		certificateRequestIssuanceDurationSeconds: certificateRequestIssuanceDurationSeconds,
 	}
 
 	return m
@@ -141,6 +176,8 @@ func (m *Metrics) Start(listenAddress string, enablePprof bool) (*http.Server, e
 	m.registry.MustRegister(m.acmeClientRequestDurationSeconds)
 	m.registry.MustRegister(m.acmeClientRequestCount)
 	m.registry.MustRegister(m.controllerSyncCallCount)
+	m.registry.MustRegister(m.dns01SelfCheckDurationSeconds)
+	m.registry.MustRegister(m.certificateRequestIssuanceDurationSeconds)
 
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
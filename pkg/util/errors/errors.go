@@ -16,7 +16,15 @@ limitations under the License.
 
 package errors
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	acmeapi "golang.org/x/crypto/acme"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 type invalidDataError struct{ error }
 
@@ -30,3 +38,80 @@ func IsInvalidData(err error) bool {
 	}
 	return true
 }
+
+// Class describes how soon a resource should be processed again after an
+// error was returned while processing it.
+type Class int
+
+const (
+	// ClassTransient errors, such as network errors and 5xx responses from
+	// remote servers, are expected to resolve themselves and are retried
+	// after a short backoff.
+	ClassTransient Class = iota
+
+	// ClassPermanent errors, such as 4xx responses from remote servers and
+	// invalid configuration, are not expected to resolve until a resource
+	// is changed and are retried after a long backoff.
+	ClassPermanent
+)
+
+type permanentError struct{ error }
+
+func (e *permanentError) Unwrap() error {
+	return e.error
+}
+
+// NewPermanent marks the given error as permanent, so that Classify returns
+// ClassPermanent for it and any error wrapping it.
+func NewPermanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{error: err}
+}
+
+// IsPermanent returns true if the given error is classified as permanent.
+func IsPermanent(err error) bool {
+	return Classify(err) == ClassPermanent
+}
+
+// Classify returns the class of the given error. Errors marked with
+// NewPermanent, invalid data errors, 4xx responses from ACME servers and
+// invalid or bad requests to the Kubernetes API server are permanent. All
+// other errors are assumed to be transient.
+func Classify(err error) Class {
+	var permanent *permanentError
+	if errors.As(err, &permanent) {
+		return ClassPermanent
+	}
+
+	var invalidData *invalidDataError
+	if errors.As(err, &invalidData) {
+		return ClassPermanent
+	}
+
+	var acmeErr *acmeapi.Error
+	if errors.As(err, &acmeErr) {
+		return classifyStatusCode(acmeErr.StatusCode)
+	}
+
+	var apiStatus apierrors.APIStatus
+	if errors.As(err, &apiStatus) {
+		switch apiStatus.Status().Reason {
+		case metav1.StatusReasonInvalid, metav1.StatusReasonBadRequest:
+			return ClassPermanent
+		}
+	}
+
+	return ClassTransient
+}
+
+// classifyStatusCode classifies the HTTP status code of a failed request.
+// Rate limited requests are considered permanent so that they are retried
+// after a long backoff rather than consuming more of the rate limit.
+func classifyStatusCode(code int) Class {
+	if code >= 400 && code < 500 && code != http.StatusRequestTimeout {
+		return ClassPermanent
+	}
+	return ClassTransient
+}
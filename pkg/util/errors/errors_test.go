@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	acmeapi "golang.org/x/crypto/acme"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestClassify(t *testing.T) {
+	resource := schema.GroupResource{Group: "cert-manager.io", Resource: "certificates"}
+
+	tests := map[string]struct {
+		err      error
+		expected Class
+	}{
+		"a generic error is transient": {
+			err:      errors.New("connection refused"),
+			expected: ClassTransient,
+		},
+		"a context deadline is transient": {
+			err:      context.DeadlineExceeded,
+			expected: ClassTransient,
+		},
+		"an error marked as permanent is permanent": {
+			err:      NewPermanent(errors.New("bad config")),
+			expected: ClassPermanent,
+		},
+		"a wrapped permanent error is permanent": {
+			err:      fmt.Errorf("error signing: %w", NewPermanent(errors.New("bad config"))),
+			expected: ClassPermanent,
+		},
+		"an invalid data error is permanent": {
+			err:      NewInvalidData("error decoding %s", "certificate"),
+			expected: ClassPermanent,
+		},
+		"a 4xx ACME error is permanent": {
+			err:      &acmeapi.Error{StatusCode: http.StatusForbidden},
+			expected: ClassPermanent,
+		},
+		"a rate limited ACME error is permanent": {
+			err:      &acmeapi.Error{StatusCode: http.StatusTooManyRequests},
+			expected: ClassPermanent,
+		},
+		"a request timeout ACME error is transient": {
+			err:      &acmeapi.Error{StatusCode: http.StatusRequestTimeout},
+			expected: ClassTransient,
+		},
+		"a 5xx ACME error is transient": {
+			err:      fmt.Errorf("error creating order: %w", &acmeapi.Error{StatusCode: http.StatusServiceUnavailable}),
+			expected: ClassTransient,
+		},
+		"an invalid Kubernetes API request is permanent": {
+			err:      apierrors.NewInvalid(schema.GroupKind{Group: "cert-manager.io", Kind: "Certificate"}, "test", nil),
+			expected: ClassPermanent,
+		},
+		"a bad Kubernetes API request is permanent": {
+			err:      apierrors.NewBadRequest("bad request"),
+			expected: ClassPermanent,
+		},
+		"a Kubernetes API conflict is transient": {
+			err:      apierrors.NewConflict(resource, "test", errors.New("conflict")),
+			expected: ClassTransient,
+		},
+		"a Kubernetes API server error is transient": {
+			err:      apierrors.NewInternalError(errors.New("etcd unavailable")),
+			expected: ClassTransient,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if class := Classify(test.err); class != test.expected {
+				t.Errorf("expected class %d but got %d", test.expected, class)
+			}
+		})
+	}
+}
+
+func TestNewPermanent(t *testing.T) {
+	if NewPermanent(nil) != nil {
+		t.Errorf("expected a nil error to remain nil")
+	}
+
+	err := errors.New("bad config")
+	if !errors.Is(NewPermanent(err), err) {
+		t.Errorf("expected the permanent error to wrap the original error")
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package issuerallowlist implements parsing and evaluation of the list of
+// issuers that Certificates are allowed to reference, as configured by the
+// cluster administrator.
+package issuerallowlist
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/jetstack/cert-manager/pkg/apis/certmanager"
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+// Pattern matches references to issuers of a kind and API group by name.
+// Each of its parts may contain the wildcards supported by path.Match, e.g.
+// `*` to match any sequence of characters.
+type Pattern struct {
+	Kind  string
+	Group string
+	Name  string
+}
+
+// Allowlist is a list of patterns, one of which an issuer reference must
+// match to be allowed. An empty Allowlist allows every issuer.
+type Allowlist []Pattern
+
+// ParsePattern parses an issuer reference pattern in the form
+// `<kind>[.<group>]/<name>`, for example `ClusterIssuer/letsencrypt-*` or
+// `AWSPCAClusterIssuer.awspca.cert-manager.io/*`. The group defaults to
+// `cert-manager.io` if not given.
+func ParsePattern(s string) (Pattern, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 {
+		return Pattern{}, fmt.Errorf("issuer pattern %q must be in the form <kind>[.<group>]/<name>", s)
+	}
+
+	p := Pattern{Kind: parts[0], Group: certmanager.GroupName, Name: parts[1]}
+	if i := strings.Index(p.Kind, "."); i >= 0 {
+		p.Kind, p.Group = p.Kind[:i], p.Kind[i+1:]
+	}
+
+	for _, part := range []struct{ field, value string }{
+		{"kind", p.Kind},
+		{"group", p.Group},
+		{"name", p.Name},
+	} {
+		if len(part.value) == 0 {
+			return Pattern{}, fmt.Errorf("issuer pattern %q must not have an empty %s", s, part.field)
+		}
+		if _, err := path.Match(part.value, ""); err != nil {
+			return Pattern{}, fmt.Errorf("invalid %s in issuer pattern %q: %v", part.field, s, err)
+		}
+	}
+
+	return p, nil
+}
+
+// ParseAllowlist parses each of the given issuer reference patterns using
+// ParsePattern.
+func ParseAllowlist(ss []string) (Allowlist, error) {
+	var l Allowlist
+	for _, s := range ss {
+		p, err := ParsePattern(s)
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, p)
+	}
+	return l, nil
+}
+
+// Matches returns true if the given issuer reference matches the pattern.
+// An empty kind or group in the reference defaults to `Issuer` and
+// `cert-manager.io` respectively.
+func (p Pattern) Matches(ref cmmeta.ObjectReference) bool {
+	kind, group := ref.Kind, ref.Group
+	if kind == "" {
+		kind = cmapi.IssuerKind
+	}
+	if group == "" {
+		group = certmanager.GroupName
+	}
+	// errors are impossible as the pattern has been validated when parsed
+	kindMatches, _ := path.Match(p.Kind, kind)
+	groupMatches, _ := path.Match(p.Group, group)
+	nameMatches, _ := path.Match(p.Name, ref.Name)
+	return kindMatches && groupMatches && nameMatches
+}
+
+// Allows returns true if the Allowlist is empty, or if the given issuer
+// reference matches any of its patterns.
+func (l Allowlist) Allows(ref cmmeta.ObjectReference) bool {
+	if len(l) == 0 {
+		return true
+	}
+	for _, p := range l {
+		if p.Matches(ref) {
+			return true
+		}
+	}
+	return false
+}
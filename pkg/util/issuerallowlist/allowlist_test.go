@@ -0,0 +1,134 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package issuerallowlist
+
+import (
+	"testing"
+
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
+)
+
+func TestParsePattern(t *testing.T) {
+	tests := map[string]struct {
+		pattern string
+		exp     Pattern
+		expErr  bool
+	}{
+		"a pattern without a group defaults to cert-manager.io": {
+			pattern: "ClusterIssuer/letsencrypt-*",
+			exp:     Pattern{Kind: "ClusterIssuer", Group: "cert-manager.io", Name: "letsencrypt-*"},
+		},
+		"a pattern with a group is parsed": {
+			pattern: "AWSPCAClusterIssuer.awspca.cert-manager.io/*",
+			exp:     Pattern{Kind: "AWSPCAClusterIssuer", Group: "awspca.cert-manager.io", Name: "*"},
+		},
+		"a pattern without a name is rejected": {
+			pattern: "ClusterIssuer",
+			expErr:  true,
+		},
+		"a pattern with more than one name is rejected": {
+			pattern: "ClusterIssuer/a/b",
+			expErr:  true,
+		},
+		"a pattern with an empty kind is rejected": {
+			pattern: "/letsencrypt",
+			expErr:  true,
+		},
+		"a pattern with an empty group is rejected": {
+			pattern: "Issuer./letsencrypt",
+			expErr:  true,
+		},
+		"a pattern with an empty name is rejected": {
+			pattern: "Issuer/",
+			expErr:  true,
+		},
+		"a pattern with an invalid wildcard is rejected": {
+			pattern: "Issuer/[a-",
+			expErr:  true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := ParsePattern(test.pattern)
+			if test.expErr != (err != nil) {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+			if p != test.exp {
+				t.Errorf("unexpected pattern, exp=%+v got=%+v", test.exp, p)
+			}
+		})
+	}
+}
+
+func TestAllowlistAllows(t *testing.T) {
+	allowlist, err := ParseAllowlist([]string{
+		"ClusterIssuer/letsencrypt-*",
+		"Issuer/team-ca",
+		"*.awspca.cert-manager.io/*",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		allowlist Allowlist
+		ref       cmmeta.ObjectReference
+		exp       bool
+	}{
+		"an empty allowlist allows every issuer": {
+			ref: cmmeta.ObjectReference{Name: "anything", Kind: "ClusterIssuer"},
+			exp: true,
+		},
+		"a ClusterIssuer matching a wildcard is allowed": {
+			allowlist: allowlist,
+			ref:       cmmeta.ObjectReference{Name: "letsencrypt-prod", Kind: "ClusterIssuer", Group: "cert-manager.io"},
+			exp:       true,
+		},
+		"an Issuer is allowed if the kind and group are defaulted": {
+			allowlist: allowlist,
+			ref:       cmmeta.ObjectReference{Name: "team-ca"},
+			exp:       true,
+		},
+		"an external issuer matching the group is allowed": {
+			allowlist: allowlist,
+			ref:       cmmeta.ObjectReference{Name: "pca", Kind: "AWSPCAIssuer", Group: "awspca.cert-manager.io"},
+			exp:       true,
+		},
+		"an issuer of another kind with a matching name is denied": {
+			allowlist: allowlist,
+			ref:       cmmeta.ObjectReference{Name: "letsencrypt-prod", Kind: "Issuer"},
+			exp:       false,
+		},
+		"an issuer of another group with a matching name is denied": {
+			allowlist: allowlist,
+			ref:       cmmeta.ObjectReference{Name: "team-ca", Kind: "Issuer", Group: "example.com"},
+			exp:       false,
+		},
+		"an issuer with a name that does not match is denied": {
+			allowlist: allowlist,
+			ref:       cmmeta.ObjectReference{Name: "selfsigned", Kind: "ClusterIssuer"},
+			exp:       false,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := test.allowlist.Allows(test.ref); got != test.exp {
+				t.Errorf("unexpected result, exp=%t got=%t", test.exp, got)
+			}
+		})
+	}
+}
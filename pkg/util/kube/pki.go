@@ -150,3 +150,24 @@ func SecretTLSCert(ctx context.Context, secretLister corelisters.SecretLister, n
 
 	return certs[0], nil
 }
+
+// SecretKeySeed will read the seed that private keys may be derived from out
+// of the Secret entry referenced by 'sel' in 'namespace'. An InvalidData error
+// is returned if the entry does not exist or is shorter than
+// pki.MinKeySeedSize bytes.
+func SecretKeySeed(ctx context.Context, secretLister corelisters.SecretLister, namespace string, sel cmmeta.SecretKeySelector) ([]byte, error) {
+	secret, err := secretLister.Secrets(namespace).Get(sel.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	seed, ok := secret.Data[sel.Key]
+	if !ok {
+		return nil, errors.NewInvalidData("no data for %q in secret '%s/%s'", sel.Key, namespace, sel.Name)
+	}
+	if len(seed) < pki.MinKeySeedSize {
+		return nil, errors.NewInvalidData("key seed in secret '%s/%s' is too short: %d bytes. minimum seed size: %d bytes", namespace, sel.Name, len(seed), pki.MinKeySeedSize)
+	}
+
+	return seed, nil
+}
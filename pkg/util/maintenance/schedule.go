@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"fmt"
+	"time"
+
+	// embed the time zone database so that schedules can be evaluated in
+	// any time zone, even if it is not installed in the container image.
+	_ "time/tzdata"
+)
+
+var weekdays = map[string]time.Weekday{
+	"Sunday":    time.Sunday,
+	"Monday":    time.Monday,
+	"Tuesday":   time.Tuesday,
+	"Wednesday": time.Wednesday,
+	"Thursday":  time.Thursday,
+	"Friday":    time.Friday,
+	"Saturday":  time.Saturday,
+}
+
+// ParseWeekday parses the English name of a day of the week, e.g. `Monday`.
+func ParseWeekday(s string) (time.Weekday, error) {
+	wd, ok := weekdays[s]
+	if !ok {
+		return 0, fmt.Errorf("invalid day of the week %q", s)
+	}
+	return wd, nil
+}
+
+// Schedule is a recurring weekly period during which certificates may be
+// renewed, for example on weekdays between 09:00 and 17:00.
+type Schedule struct {
+	// days on which renewal is allowed. If empty, renewal is allowed on
+	// every day.
+	days map[time.Weekday]bool
+	// startHour and endHour bound the hours of each allowed day during
+	// which renewal is allowed. startHour is included but endHour is not.
+	startHour int
+	endHour   int
+	location  *time.Location
+}
+
+// ParseSchedule returns a Schedule that allows renewal on the given days of
+// the week, e.g. `Monday`, from startHour (0-23) until endHour (1-24) in the
+// given IANA time zone. If no days are given renewal is allowed on every day,
+// and if no time zone is given UTC is used.
+func ParseSchedule(days []string, startHour, endHour int, timeZone string) (*Schedule, error) {
+	s := &Schedule{
+		days:      make(map[time.Weekday]bool),
+		startHour: startHour,
+		endHour:   endHour,
+		location:  time.UTC,
+	}
+
+	for _, d := range days {
+		wd, err := ParseWeekday(d)
+		if err != nil {
+			return nil, err
+		}
+		s.days[wd] = true
+	}
+
+	if startHour < 0 || startHour > 23 {
+		return nil, fmt.Errorf("start hour %d must be between 0 and 23", startHour)
+	}
+	if endHour < 1 || endHour > 24 {
+		return nil, fmt.Errorf("end hour %d must be between 1 and 24", endHour)
+	}
+	if endHour <= startHour {
+		return nil, fmt.Errorf("end hour %d must be after start hour %d", endHour, startHour)
+	}
+
+	if timeZone != "" {
+		loc, err := time.LoadLocation(timeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %v", timeZone, err)
+		}
+		s.location = loc
+	}
+
+	return s, nil
+}
+
+// Next returns the earliest time, not before the given time, at which the
+// schedule allows renewal. If renewal is allowed at the given time, it is
+// returned unchanged.
+func (s *Schedule) Next(t time.Time) time.Time {
+	local := t.In(s.location)
+	// every day of the week is considered, as well as the same day in
+	// the following week in case its allowed hours have already passed.
+	for i := 0; i <= 7; i++ {
+		day := time.Date(local.Year(), local.Month(), local.Day()+i, 0, 0, 0, 0, s.location)
+		if len(s.days) > 0 && !s.days[day.Weekday()] {
+			continue
+		}
+
+		start := time.Date(day.Year(), day.Month(), day.Day(), s.startHour, 0, 0, 0, s.location)
+		end := time.Date(day.Year(), day.Month(), day.Day(), s.endHour, 0, 0, 0, s.location)
+		if !t.Before(start) && t.Before(end) {
+			return t
+		}
+		if t.Before(start) {
+			return start
+		}
+	}
+
+	// unreachable, as at least one day of every week is allowed.
+	return t
+}
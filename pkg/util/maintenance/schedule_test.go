@@ -0,0 +1,178 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"testing"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := map[string]struct {
+		days      []string
+		startHour int
+		endHour   int
+		timeZone  string
+		expErr    bool
+	}{
+		"a valid schedule is parsed": {
+			days:      []string{"Monday", "Friday"},
+			startHour: 9,
+			endHour:   17,
+			timeZone:  "Europe/London",
+		},
+		"a schedule without days or a time zone is parsed": {
+			startHour: 0,
+			endHour:   24,
+		},
+		"an unknown day is rejected": {
+			days:      []string{"monday"},
+			startHour: 9,
+			endHour:   17,
+			expErr:    true,
+		},
+		"a start hour after 23 is rejected": {
+			startHour: 24,
+			endHour:   24,
+			expErr:    true,
+		},
+		"an end hour after 24 is rejected": {
+			startHour: 9,
+			endHour:   25,
+			expErr:    true,
+		},
+		"an end hour before the start hour is rejected": {
+			startHour: 17,
+			endHour:   9,
+			expErr:    true,
+		},
+		"an unknown time zone is rejected": {
+			startHour: 9,
+			endHour:   17,
+			timeZone:  "Europe/Nowhere",
+			expErr:    true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseSchedule(test.days, test.startHour, test.endHour, test.timeZone)
+			if test.expErr != (err != nil) {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	weekdays := []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday"}
+
+	tests := map[string]struct {
+		days      []string
+		startHour int
+		endHour   int
+		timeZone  string
+		now       string
+		expNext   string
+	}{
+		// 2021-12-20 is a Monday.
+		"a time within the allowed hours is returned unchanged": {
+			days:      weekdays,
+			startHour: 9,
+			endHour:   17,
+			now:       "2021-12-20T12:30:00Z",
+			expNext:   "2021-12-20T12:30:00Z",
+		},
+		"the start of the allowed hours is allowed": {
+			days:      weekdays,
+			startHour: 9,
+			endHour:   17,
+			now:       "2021-12-20T09:00:00Z",
+			expNext:   "2021-12-20T09:00:00Z",
+		},
+		"a time before the allowed hours moves to their start": {
+			days:      weekdays,
+			startHour: 9,
+			endHour:   17,
+			now:       "2021-12-20T03:00:00Z",
+			expNext:   "2021-12-20T09:00:00Z",
+		},
+		"the end of the allowed hours moves to the next allowed day": {
+			days:      weekdays,
+			startHour: 9,
+			endHour:   17,
+			now:       "2021-12-20T17:00:00Z",
+			expNext:   "2021-12-21T09:00:00Z",
+		},
+		"a time on the weekend moves to Monday": {
+			days:      weekdays,
+			startHour: 9,
+			endHour:   17,
+			now:       "2021-12-25T12:00:00Z",
+			expNext:   "2021-12-27T09:00:00Z",
+		},
+		"a time after the allowed hours of the only allowed day moves to the following week": {
+			days:      []string{"Monday"},
+			startHour: 9,
+			endHour:   17,
+			now:       "2021-12-20T18:00:00Z",
+			expNext:   "2021-12-27T09:00:00Z",
+		},
+		"every day is allowed if no days are given": {
+			startHour: 9,
+			endHour:   17,
+			now:       "2021-12-25T18:00:00Z",
+			expNext:   "2021-12-26T09:00:00Z",
+		},
+		"allowed hours until the end of the day are supported": {
+			days:      []string{"Monday"},
+			startHour: 22,
+			endHour:   24,
+			now:       "2021-12-20T23:59:59Z",
+			expNext:   "2021-12-20T23:59:59Z",
+		},
+		"allowed hours are evaluated in the time zone of the schedule": {
+			days:      weekdays,
+			startHour: 9,
+			endHour:   17,
+			timeZone:  "America/New_York",
+			now:       "2021-12-20T12:00:00Z",
+			expNext:   "2021-12-20T14:00:00Z",
+		},
+		"allowed days are evaluated in the time zone of the schedule": {
+			days:      weekdays,
+			startHour: 9,
+			endHour:   17,
+			timeZone:  "Asia/Tokyo",
+			now:       "2021-12-24T12:00:00Z",
+			expNext:   "2021-12-27T00:00:00Z",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := ParseSchedule(test.days, test.startHour, test.endHour, test.timeZone)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			next := s.Next(mustParseTime(t, test.now))
+			if exp := mustParseTime(t, test.expNext); !next.Equal(exp) {
+				t.Errorf("unexpected next time, exp=%s got=%s", exp, next)
+			}
+		})
+	}
+}
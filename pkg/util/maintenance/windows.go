@@ -0,0 +1,103 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package maintenance implements parsing and evaluation of maintenance
+// windows, during which cert-manager defers the issuance of certificates, and
+// of renewal schedules, which restrict when certificates may be renewed.
+package maintenance
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Window is a period of time during which certificates should not be issued.
+// A Window includes its Start time but not its End time.
+type Window struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Windows is a list of maintenance windows, sorted by their start time.
+type Windows []Window
+
+// ParseWindow parses a maintenance window in the form `<start>/<end>`, where
+// start and end are RFC 3339 timestamps, for example
+// `2021-12-20T00:00:00Z/2022-01-03T09:00:00Z`. The end of the window must be
+// after its start.
+func ParseWindow(s string) (Window, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 2 {
+		return Window{}, fmt.Errorf("maintenance window %q must be in the form <start>/<end>", s)
+	}
+
+	start, err := time.Parse(time.RFC3339, parts[0])
+	if err != nil {
+		return Window{}, fmt.Errorf("invalid start of maintenance window %q: %v", s, err)
+	}
+	end, err := time.Parse(time.RFC3339, parts[1])
+	if err != nil {
+		return Window{}, fmt.Errorf("invalid end of maintenance window %q: %v", s, err)
+	}
+	if !end.After(start) {
+		return Window{}, fmt.Errorf("end of maintenance window %q must be after its start", s)
+	}
+
+	return Window{Start: start, End: end}, nil
+}
+
+// ParseWindows parses each of the given maintenance windows using
+// ParseWindow, returning them sorted by their start time.
+func ParseWindows(ss []string) (Windows, error) {
+	var windows Windows
+	for _, s := range ss {
+		w, err := ParseWindow(s)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, w)
+	}
+
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i].Start.Before(windows[j].Start)
+	})
+
+	return windows, nil
+}
+
+// End returns the time at which the maintenance in progress at the given
+// time ends, and true. If windows overlap or adjoin, the end of the last of
+// them is returned. If no maintenance window includes the given time, the
+// zero time and false are returned.
+func (ws Windows) End(t time.Time) (time.Time, bool) {
+	end, active := t, false
+	// windows are sorted by their start time, so any window that extends
+	// the maintenance must come after the windows that include the time.
+	for _, w := range ws {
+		if end.Before(w.Start) {
+			break
+		}
+		if end.Before(w.End) {
+			end, active = w.End, true
+		}
+	}
+	if !active {
+		return time.Time{}, false
+	}
+	return end, true
+}
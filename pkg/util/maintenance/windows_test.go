@@ -0,0 +1,150 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"testing"
+	"time"
+)
+
+func mustParseTime(t *testing.T, s string) time.Time {
+	tm, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tm
+}
+
+func TestParseWindow(t *testing.T) {
+	tests := map[string]struct {
+		window   string
+		expStart string
+		expEnd   string
+		expErr   bool
+	}{
+		"a valid window is parsed": {
+			window:   "2021-12-20T00:00:00Z/2022-01-03T09:00:00Z",
+			expStart: "2021-12-20T00:00:00Z",
+			expEnd:   "2022-01-03T09:00:00Z",
+		},
+		"timestamps with offsets are parsed": {
+			window:   "2021-12-20T00:00:00+01:00/2021-12-20T02:00:00+01:00",
+			expStart: "2021-12-19T23:00:00Z",
+			expEnd:   "2021-12-20T01:00:00Z",
+		},
+		"a window without an end is rejected": {
+			window: "2021-12-20T00:00:00Z",
+			expErr: true,
+		},
+		"a window with more than two timestamps is rejected": {
+			window: "2021-12-20T00:00:00Z/2021-12-21T00:00:00Z/2021-12-22T00:00:00Z",
+			expErr: true,
+		},
+		"an invalid start is rejected": {
+			window: "2021-12-20/2021-12-21T00:00:00Z",
+			expErr: true,
+		},
+		"an invalid end is rejected": {
+			window: "2021-12-20T00:00:00Z/tomorrow",
+			expErr: true,
+		},
+		"a window ending before it starts is rejected": {
+			window: "2021-12-21T00:00:00Z/2021-12-20T00:00:00Z",
+			expErr: true,
+		},
+		"an empty window is rejected": {
+			window: "2021-12-20T00:00:00Z/2021-12-20T00:00:00Z",
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			w, err := ParseWindow(test.window)
+			if test.expErr != (err != nil) {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+			if test.expErr {
+				return
+			}
+			if !w.Start.Equal(mustParseTime(t, test.expStart)) {
+				t.Errorf("unexpected start, exp=%s got=%s", test.expStart, w.Start)
+			}
+			if !w.End.Equal(mustParseTime(t, test.expEnd)) {
+				t.Errorf("unexpected end, exp=%s got=%s", test.expEnd, w.End)
+			}
+		})
+	}
+}
+
+func TestWindowsEnd(t *testing.T) {
+	windows, err := ParseWindows([]string{
+		"2021-12-27T00:00:00Z/2021-12-28T00:00:00Z",
+		"2021-12-20T00:00:00Z/2021-12-22T00:00:00Z",
+		"2021-12-21T00:00:00Z/2021-12-23T00:00:00Z",
+		"2021-12-23T00:00:00Z/2021-12-24T00:00:00Z",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		now       string
+		expActive bool
+		expEnd    string
+	}{
+		"before all windows": {
+			now: "2021-12-19T00:00:00Z",
+		},
+		"at the start of a window": {
+			now:       "2021-12-27T00:00:00Z",
+			expActive: true,
+			expEnd:    "2021-12-28T00:00:00Z",
+		},
+		"at the end of a window": {
+			now: "2021-12-28T00:00:00Z",
+		},
+		"between windows": {
+			now: "2021-12-25T00:00:00Z",
+		},
+		"in overlapping and adjoining windows": {
+			now:       "2021-12-20T12:00:00Z",
+			expActive: true,
+			expEnd:    "2021-12-24T00:00:00Z",
+		},
+		"in the last of adjoining windows": {
+			now:       "2021-12-23T12:00:00Z",
+			expActive: true,
+			expEnd:    "2021-12-24T00:00:00Z",
+		},
+		"after all windows": {
+			now: "2022-01-01T00:00:00Z",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			end, active := windows.End(mustParseTime(t, test.now))
+			if active != test.expActive {
+				t.Fatalf("unexpected active, exp=%t got=%t", test.expActive, active)
+			}
+			if test.expActive && !end.Equal(mustParseTime(t, test.expEnd)) {
+				t.Errorf("unexpected end, exp=%s got=%s", test.expEnd, end)
+			}
+		})
+	}
+}
@@ -19,13 +19,19 @@ package pki
 import (
 	"bytes"
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/asn1"
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
 	"net"
 	"net/url"
@@ -63,7 +69,26 @@ func DNSNamesForCertificate(crt *v1.Certificate) ([]string, error) {
 		return nil, fmt.Errorf("failed to parse DNSNames: %s", err)
 	}
 
-	return crt.Spec.DNSNames, nil
+	return DNSNamesForCertificateSpec(crt.Spec), nil
+}
+
+// DNSNamesForCertificateSpec returns the DNS names that should be requested
+// for the given CertificateSpec. If addCommonNameToDNSNames is true, the
+// commonName is appended unless it is already one of the dnsNames.
+func DNSNamesForCertificateSpec(spec v1.CertificateSpec) []string {
+	if spec.AddCommonNameToDNSNames == nil || !*spec.AddCommonNameToDNSNames || len(spec.CommonName) == 0 {
+		return spec.DNSNames
+	}
+
+	for _, dnsName := range spec.DNSNames {
+		if dnsName == spec.CommonName {
+			return spec.DNSNames
+		}
+	}
+
+	dnsNames := make([]string, 0, len(spec.DNSNames)+1)
+	dnsNames = append(dnsNames, spec.DNSNames...)
+	return append(dnsNames, spec.CommonName)
 }
 
 func URLsFromStrings(urlStrs []string) ([]*url.URL, error) {
@@ -143,6 +168,70 @@ func SubjectForCertificate(crt *v1.Certificate) v1.X509Subject {
 
 var serialNumberLimit = new(big.Int).Lsh(big.NewInt(1), 128)
 
+const (
+	// MinSerialNumberBits is the minimum number of random bits in serial
+	// numbers generated by GenerateSerialNumber. The CA/Browser Forum Baseline
+	// Requirements require serial numbers to contain at least 64 bits of
+	// output from a CSPRNG.
+	MinSerialNumberBits = 64
+
+	// MaxSerialNumberBits is the maximum number of random bits in serial
+	// numbers generated by GenerateSerialNumber. RFC 5280 limits serial
+	// numbers to 20 octets, and they must be positive, leaving 159 bits of
+	// which one is used to fix the length of the serial number.
+	MaxSerialNumberBits = 158
+
+	// DefaultSerialNumberBits is the default number of random bits in serial
+	// numbers generated by GenerateSerialNumber.
+	DefaultSerialNumberBits = 128
+)
+
+// GenerateSerialNumber returns a random, positive certificate serial number
+// containing the given number of random bits. The bit above the random bits
+// is always set, so the serial number is always bits+1 bits long and leading
+// zero bits do not shorten it. If bits is zero, DefaultSerialNumberBits is
+// used.
+func GenerateSerialNumber(bits int) (*big.Int, error) {
+	if bits == 0 {
+		bits = DefaultSerialNumberBits
+	}
+	if bits < MinSerialNumberBits || bits > MaxSerialNumberBits {
+		return nil, fmt.Errorf("serial number bits must be between %d and %d, got %d", MinSerialNumberBits, MaxSerialNumberBits, bits)
+	}
+
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+	serialNumber, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %s", err.Error())
+	}
+
+	return serialNumber.SetBit(serialNumber, bits, 1), nil
+}
+
+// GenerateSerialNumberFromSeed returns a positive certificate serial number
+// containing the given number of bits derived from the given seed and data,
+// so that the same seed and data always yield the same serial number. As with
+// GenerateSerialNumber, the bit above the derived bits is always set. If bits
+// is zero, DefaultSerialNumberBits is used.
+func GenerateSerialNumberFromSeed(bits int, seed, data []byte) (*big.Int, error) {
+	if bits == 0 {
+		bits = DefaultSerialNumberBits
+	}
+	if bits < MinSerialNumberBits || bits > MaxSerialNumberBits {
+		return nil, fmt.Errorf("serial number bits must be between %d and %d, got %d", MinSerialNumberBits, MaxSerialNumberBits, bits)
+	}
+
+	mac := hmac.New(sha256.New, seed)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	serialNumber := new(big.Int).SetBytes(sum)
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(bits))
+	serialNumber.Mod(serialNumber, limit)
+
+	return serialNumber.SetBit(serialNumber, bits, 1), nil
+}
+
 func BuildKeyUsages(usages []v1.KeyUsage, isCA bool) (ku x509.KeyUsage, eku []x509.ExtKeyUsage, err error) {
 	var unk []v1.KeyUsage
 	if isCA {
@@ -210,6 +299,14 @@ func GenerateCSR(crt *v1.Certificate) (*x509.CertificateRequest, error) {
 		}
 	}
 
+	if len(crt.Spec.CertificatePolicies) > 0 {
+		policies, err := BuildCertificatePoliciesExtension(crt.Spec.CertificatePolicies)
+		if err != nil {
+			return nil, err
+		}
+		extraExtensions = append(extraExtensions, policies)
+	}
+
 	return &x509.CertificateRequest{
 		Version:            3,
 		SignatureAlgorithm: sigAlgo,
@@ -271,7 +368,7 @@ func buildKeyUsagesExtensionsForCertificate(crt *v1.Certificate) ([]pkix.Extensi
 // The PublicKey field must be populated by the caller.
 func GenerateTemplate(crt *v1.Certificate) (*x509.Certificate, error) {
 	commonName := crt.Spec.CommonName
-	dnsNames := crt.Spec.DNSNames
+	dnsNames := DNSNamesForCertificateSpec(crt.Spec)
 	ipAddresses := IPAddressesForCertificate(crt)
 	organization := OrganizationForCertificate(crt)
 	subject := SubjectForCertificate(crt)
@@ -300,7 +397,7 @@ func GenerateTemplate(crt *v1.Certificate) (*x509.Certificate, error) {
 		return nil, err
 	}
 
-	return &x509.Certificate{
+	template := &x509.Certificate{
 		Version:               3,
 		BasicConstraintsValid: true,
 		SerialNumber:          serialNumber,
@@ -326,7 +423,13 @@ func GenerateTemplate(crt *v1.Certificate) (*x509.Certificate, error) {
 		IPAddresses:    ipAddresses,
 		URIs:           uris,
 		EmailAddresses: crt.Spec.EmailAddresses,
-	}, nil
+	}
+
+	if err := AddCertificatePolicies(template, crt.Spec.CertificatePolicies); err != nil {
+		return nil, err
+	}
+
+	return template, nil
 }
 
 // GenerateTemplate will create a x509.Certificate for the given
@@ -395,7 +498,45 @@ func GenerateTemplateFromCSRPEMWithUsages(csrPEM []byte, duration time.Duration,
 // It returns a PEM encoded copy of the Certificate as well as a *x509.Certificate
 // which can be used for reading the encoded values.
 func SignCertificate(template *x509.Certificate, issuerCert *x509.Certificate, publicKey crypto.PublicKey, signerKey interface{}) ([]byte, *x509.Certificate, error) {
-	derBytes, err := x509.CreateCertificate(rand.Reader, template, issuerCert, publicKey, signerKey)
+	return signCertificate(rand.Reader, template, issuerCert, publicKey, signerKey)
+}
+
+// SignCertificateDeterministically behaves like SignCertificate, but does not
+// use a source of randomness when signing, so that signing the same template
+// with the same ECDSA key always produces the same certificate. The ECDSA
+// implementation in the standard library derives the signature nonce from the
+// private key and the signed digest as well as the provided entropy, so
+// signatures over distinct certificates do not reuse nonces.
+func SignCertificateDeterministically(template *x509.Certificate, issuerCert *x509.Certificate, publicKey crypto.PublicKey, signerKey interface{}) ([]byte, *x509.Certificate, error) {
+	return signCertificate(zeroReader{}, template, issuerCert, publicKey, signerKey)
+}
+
+// zeroReader is an io.Reader that returns an endless stream of zero bytes.
+type zeroReader struct{}
+
+func (zeroReader) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = 0
+	}
+	return len(b), nil
+}
+
+func signCertificate(rand io.Reader, template *x509.Certificate, issuerCert *x509.Certificate, publicKey crypto.PublicKey, signerKey interface{}) ([]byte, *x509.Certificate, error) {
+	// The signature algorithm is determined by the key of the signer, which
+	// may be of a different type to the key being signed.
+	if template.SignatureAlgorithm == x509.UnknownSignatureAlgorithm {
+		if signer, ok := signerKey.(crypto.Signer); ok {
+			sigAlgo, err := SignatureAlgorithmForPublicKey(signer.Public())
+			if err != nil {
+				return nil, nil, err
+			}
+			tmpl := *template
+			tmpl.SignatureAlgorithm = sigAlgo
+			template = &tmpl
+		}
+	}
+
+	derBytes, err := x509.CreateCertificate(rand, template, issuerCert, publicKey, signerKey)
 
 	if err != nil {
 		return nil, nil, fmt.Errorf("error creating x509 certificate: %s", err.Error())
@@ -527,3 +668,33 @@ func SignatureAlgorithm(crt *v1.Certificate) (x509.PublicKeyAlgorithm, x509.Sign
 	}
 	return pubKeyAlgo, sigAlgo, nil
 }
+
+// SignatureAlgorithmForPublicKey returns the signature algorithm that should be
+// used to sign with the private key of the given public key. The digest size
+// grows with the size of RSA and ECDSA keys, in line with SignatureAlgorithm.
+func SignatureAlgorithmForPublicKey(pub crypto.PublicKey) (x509.SignatureAlgorithm, error) {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		switch size := k.N.BitLen(); {
+		case size >= 4096:
+			return x509.SHA512WithRSA, nil
+		case size >= 3072:
+			return x509.SHA384WithRSA, nil
+		default:
+			return x509.SHA256WithRSA, nil
+		}
+	case *ecdsa.PublicKey:
+		switch k.Curve.Params().BitSize {
+		case ECCurve521:
+			return x509.ECDSAWithSHA512, nil
+		case ECCurve384:
+			return x509.ECDSAWithSHA384, nil
+		default:
+			return x509.ECDSAWithSHA256, nil
+		}
+	case ed25519.PublicKey:
+		return x509.PureEd25519, nil
+	default:
+		return x509.UnknownSignatureAlgorithm, fmt.Errorf("unsupported signing key type: %T", pub)
+	}
+}
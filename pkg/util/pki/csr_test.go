@@ -19,6 +19,8 @@ package pki
 import (
 	"bytes"
 	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/asn1"
@@ -29,6 +31,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/utils/pointer"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 	"github.com/jetstack/cert-manager/pkg/util"
@@ -346,6 +349,47 @@ func TestSignatureAlgorithmForCertificate(t *testing.T) {
 	}
 }
 
+func TestSignatureAlgorithmForPublicKey(t *testing.T) {
+	mustGenerateRSA := func(keySize int) crypto.PublicKey {
+		pk, err := GenerateRSAPrivateKey(keySize)
+		require.NoError(t, err)
+		return pk.Public()
+	}
+	mustGenerateECDSA := func(keySize int) crypto.PublicKey {
+		pk, err := GenerateECPrivateKey(keySize)
+		require.NoError(t, err)
+		return pk.Public()
+	}
+	ed25519Pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		pub       crypto.PublicKey
+		expSigAlg x509.SignatureAlgorithm
+		expErr    bool
+	}{
+		"rsa 2048":     {pub: mustGenerateRSA(2048), expSigAlg: x509.SHA256WithRSA},
+		"rsa 3072":     {pub: mustGenerateRSA(3072), expSigAlg: x509.SHA384WithRSA},
+		"rsa 4096":     {pub: mustGenerateRSA(4096), expSigAlg: x509.SHA512WithRSA},
+		"ecdsa 256":    {pub: mustGenerateECDSA(ECCurve256), expSigAlg: x509.ECDSAWithSHA256},
+		"ecdsa 384":    {pub: mustGenerateECDSA(ECCurve384), expSigAlg: x509.ECDSAWithSHA384},
+		"ecdsa 521":    {pub: mustGenerateECDSA(ECCurve521), expSigAlg: x509.ECDSAWithSHA512},
+		"ed25519":      {pub: ed25519Pub, expSigAlg: x509.PureEd25519},
+		"unknown type": {pub: "not a key", expSigAlg: x509.UnknownSignatureAlgorithm, expErr: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			sigAlg, err := SignatureAlgorithmForPublicKey(test.pub)
+			if test.expErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.expSigAlg, sigAlg)
+		})
+	}
+}
+
 func TestRemoveDuplicates(t *testing.T) {
 	type testT struct {
 		input  []string
@@ -473,6 +517,85 @@ func TestGenerateCSR(t *testing.T) {
 	}
 }
 
+func TestGenerateCSRAddCommonNameToDNSNames(t *testing.T) {
+	pk, err := GenerateRSAPrivateKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		addCommonNameToDNSNames *bool
+		commonName              string
+		dnsNames                []string
+		expDNSNames             []string
+	}{
+		"if unset, common name should not be added to DNS names": {
+			commonName:  "example.com",
+			dnsNames:    []string{"www.example.com"},
+			expDNSNames: []string{"www.example.com"},
+		},
+		"if false, common name should not be added to DNS names": {
+			addCommonNameToDNSNames: pointer.BoolPtr(false),
+			commonName:              "example.com",
+			dnsNames:                []string{"www.example.com"},
+			expDNSNames:             []string{"www.example.com"},
+		},
+		"if true, common name should be added to DNS names": {
+			addCommonNameToDNSNames: pointer.BoolPtr(true),
+			commonName:              "example.com",
+			dnsNames:                []string{"www.example.com"},
+			expDNSNames:             []string{"www.example.com", "example.com"},
+		},
+		"if true and no DNS names are set, common name should be the only DNS name": {
+			addCommonNameToDNSNames: pointer.BoolPtr(true),
+			commonName:              "example.com",
+			expDNSNames:             []string{"example.com"},
+		},
+		"if true and common name is already a DNS name, it should not be duplicated": {
+			addCommonNameToDNSNames: pointer.BoolPtr(true),
+			commonName:              "example.com",
+			dnsNames:                []string{"www.example.com", "example.com"},
+			expDNSNames:             []string{"www.example.com", "example.com"},
+		},
+		"if true and no common name is set, DNS names should be unchanged": {
+			addCommonNameToDNSNames: pointer.BoolPtr(true),
+			dnsNames:                []string{"www.example.com"},
+			expDNSNames:             []string{"www.example.com"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			crt := buildCertificate(test.commonName, test.dnsNames...)
+			crt.Spec.AddCommonNameToDNSNames = test.addCommonNameToDNSNames
+
+			template, err := GenerateCSR(crt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			csrDER, err := EncodeCSR(template, pk)
+			if err != nil {
+				t.Fatal(err)
+			}
+			csr, err := x509.ParseCertificateRequest(csrDER)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if csr.Subject.CommonName != test.commonName {
+				t.Errorf("unexpected common name, exp=%q got=%q", test.commonName, csr.Subject.CommonName)
+			}
+			if !util.EqualUnsorted(csr.DNSNames, test.expDNSNames) {
+				t.Errorf("unexpected DNS names, exp=%q got=%q", test.expDNSNames, csr.DNSNames)
+			}
+			// The original Certificate must not be modified
+			if !reflect.DeepEqual(crt.Spec.DNSNames, test.dnsNames) {
+				t.Errorf("Certificate DNS names were modified, exp=%q got=%q", test.dnsNames, crt.Spec.DNSNames)
+			}
+		})
+	}
+}
+
 func Test_buildKeyUsagesExtensionsForCertificate(t *testing.T) {
 	// 0xa0 = DigitalSignature and Encipherment usage
 	asn1DefaultKeyUsage, err := asn1.Marshal(asn1.BitString{Bytes: []byte{0xa0}, BitLength: asn1BitLength([]byte{0xa0})})
@@ -675,3 +798,115 @@ func TestSignCSRTemplate(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateSerialNumber(t *testing.T) {
+	tests := map[string]struct {
+		bits           int
+		expectedBitLen int
+		expectErr      bool
+	}{
+		"zero uses the default number of bits": {
+			bits:           0,
+			expectedBitLen: DefaultSerialNumberBits + 1,
+		},
+		"the minimum number of bits": {
+			bits:           MinSerialNumberBits,
+			expectedBitLen: MinSerialNumberBits + 1,
+		},
+		"the maximum number of bits": {
+			bits:           MaxSerialNumberBits,
+			expectedBitLen: MaxSerialNumberBits + 1,
+		},
+		"fewer than the minimum number of bits": {
+			bits:      MinSerialNumberBits - 1,
+			expectErr: true,
+		},
+		"more than the maximum number of bits": {
+			bits:      MaxSerialNumberBits + 1,
+			expectErr: true,
+		},
+		"a negative number of bits": {
+			bits:      -1,
+			expectErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// check several serial numbers, as most random values have the
+			// expected length even without the length being fixed
+			for i := 0; i < 100; i++ {
+				serialNumber, err := GenerateSerialNumber(test.bits)
+				if test.expectErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+
+				assert.Equal(t, test.expectedBitLen, serialNumber.BitLen())
+				assert.Equal(t, 1, serialNumber.Sign())
+				// RFC 5280 limits serial numbers to 20 octets when DER encoded
+				der, err := asn1.Marshal(serialNumber)
+				require.NoError(t, err)
+				assert.LessOrEqual(t, len(der)-2, 20)
+			}
+		})
+	}
+}
+
+func TestGenerateSerialNumberFromSeed(t *testing.T) {
+	seed := bytes.Repeat([]byte{1}, MinKeySeedSize)
+
+	serialNumber, err := GenerateSerialNumberFromSeed(0, seed, []byte("data"))
+	require.NoError(t, err)
+	assert.Equal(t, DefaultSerialNumberBits+1, serialNumber.BitLen())
+
+	sameSerialNumber, err := GenerateSerialNumberFromSeed(0, seed, []byte("data"))
+	require.NoError(t, err)
+	assert.Equal(t, serialNumber, sameSerialNumber, "expected the same seed and data to yield the same serial number")
+
+	otherDataSerialNumber, err := GenerateSerialNumberFromSeed(0, seed, []byte("other data"))
+	require.NoError(t, err)
+	assert.NotEqual(t, serialNumber, otherDataSerialNumber, "expected different data to yield a different serial number")
+
+	otherSeedSerialNumber, err := GenerateSerialNumberFromSeed(0, bytes.Repeat([]byte{2}, MinKeySeedSize), []byte("data"))
+	require.NoError(t, err)
+	assert.NotEqual(t, serialNumber, otherSeedSerialNumber, "expected a different seed to yield a different serial number")
+
+	maxSerialNumber, err := GenerateSerialNumberFromSeed(MaxSerialNumberBits, seed, []byte("data"))
+	require.NoError(t, err)
+	assert.Equal(t, MaxSerialNumberBits+1, maxSerialNumber.BitLen())
+
+	_, err = GenerateSerialNumberFromSeed(MaxSerialNumberBits+1, seed, []byte("data"))
+	assert.Error(t, err)
+}
+
+func TestSignCertificateDeterministically(t *testing.T) {
+	seed := bytes.Repeat([]byte{1}, MinKeySeedSize)
+	sign := func() []byte {
+		// derive the key again each time to check that the whole process is
+		// reproducible from the seed
+		pk, err := GenerateECPrivateKeyFromSeed(ECCurve256, seed)
+		require.NoError(t, err)
+
+		serialNumber, err := GenerateSerialNumberFromSeed(0, seed, []byte("data"))
+		require.NoError(t, err)
+
+		template := &x509.Certificate{
+			SerialNumber:          serialNumber,
+			Subject:               pkix.Name{CommonName: "seeded-ca"},
+			NotBefore:             time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			NotAfter:              time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign,
+		}
+
+		certPEM, cert, err := SignCertificateDeterministically(template, template, pk.Public(), pk)
+		require.NoError(t, err)
+		require.NoError(t, cert.CheckSignatureFrom(cert))
+		return certPEM
+	}
+
+	assert.Equal(t, sign(), sign(), "expected the same seed and template to yield the same certificate")
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"errors"
+	"fmt"
+)
+
+// fipsMode restricts key generation to FIPS approved algorithms and key
+// sizes. It is set once at startup using SetFIPSMode.
+var fipsMode bool
+
+// SetFIPSMode enables or disables FIPS mode for key generation.
+// When enabled, GenerateRSAPrivateKey and GenerateECPrivateKey will only
+// generate keys using FIPS approved algorithms and key sizes, and return an
+// error for any others.
+// FIPS mode can only be enabled if this binary was built using a FIPS
+// validated cryptographic module (i.e. a boringcrypto enabled Go toolchain
+// with the boringcrypto build tag set).
+func SetFIPSMode(enabled bool) error {
+	if enabled && !fipsModuleEnabled() {
+		return errors.New("FIPS mode requires cert-manager to be built with a boringcrypto enabled Go toolchain and the 'boringcrypto' build tag")
+	}
+	fipsMode = enabled
+	return nil
+}
+
+// FIPSMode returns true if FIPS mode is enabled for key generation.
+func FIPSMode() bool {
+	return fipsMode
+}
+
+// validateFIPSRSAKeySize returns an error if FIPS mode is enabled and the
+// given RSA key size is not approved for key generation by FIPS 186-4.
+func validateFIPSRSAKeySize(keySize int) error {
+	if !fipsMode {
+		return nil
+	}
+	switch keySize {
+	case 2048, 3072:
+		return nil
+	}
+	return fmt.Errorf("rsa key size %d is not permitted in FIPS mode. permitted key sizes: 2048, 3072", keySize)
+}
+
+// validateFIPSECCurve returns an error if FIPS mode is enabled and the given
+// ECDSA curve size is not approved for key generation by the FIPS module.
+func validateFIPSECCurve(keySize int) error {
+	if !fipsMode {
+		return nil
+	}
+	switch keySize {
+	case ECCurve256, ECCurve384:
+		return nil
+	}
+	return fmt.Errorf("ecdsa key size %d is not permitted in FIPS mode. permitted key sizes: %d, %d", keySize, ECCurve256, ECCurve384)
+}
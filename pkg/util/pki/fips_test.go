@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"strings"
+	"testing"
+
+	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+func TestSetFIPSMode(t *testing.T) {
+	defer func() { fipsMode = false }()
+
+	err := SetFIPSMode(true)
+	if fipsModuleEnabled() != (err == nil) {
+		t.Errorf("expected enabling FIPS mode to succeed only if the FIPS module is enabled, got: %v", err)
+	}
+	if FIPSMode() != (err == nil) {
+		t.Errorf("expected FIPS mode to only be enabled if SetFIPSMode succeeded")
+	}
+
+	if err := SetFIPSMode(false); err != nil {
+		t.Errorf("expected disabling FIPS mode to succeed, got: %v", err)
+	}
+	if FIPSMode() {
+		t.Errorf("expected FIPS mode to be disabled")
+	}
+}
+
+func TestGeneratePrivateKeyForCertificateFIPSMode(t *testing.T) {
+	// set fipsMode directly as the boringcrypto module is not available to
+	// unit tests
+	fipsMode = true
+	defer func() { fipsMode = false }()
+
+	tests := map[string]struct {
+		keyAlgo      v1.PrivateKeyAlgorithm
+		keySize      int
+		expectErrStr string
+	}{
+		"rsa key with default keysize": {
+			keyAlgo: v1.RSAKeyAlgorithm,
+		},
+		"rsa key with keysize 2048": {
+			keyAlgo: v1.RSAKeyAlgorithm,
+			keySize: 2048,
+		},
+		"rsa key with keysize 3072": {
+			keyAlgo: v1.RSAKeyAlgorithm,
+			keySize: 3072,
+		},
+		"rsa key with keysize 4096 is rejected": {
+			keyAlgo:      v1.RSAKeyAlgorithm,
+			keySize:      4096,
+			expectErrStr: "rsa key size 4096 is not permitted in FIPS mode",
+		},
+		"weak rsa key is still rejected as weak": {
+			keyAlgo:      v1.RSAKeyAlgorithm,
+			keySize:      1024,
+			expectErrStr: "weak rsa key size specified",
+		},
+		"ecdsa key with default keysize": {
+			keyAlgo: v1.ECDSAKeyAlgorithm,
+		},
+		"ecdsa key with keysize 384": {
+			keyAlgo: v1.ECDSAKeyAlgorithm,
+			keySize: 384,
+		},
+		"ecdsa key with keysize 521 is rejected": {
+			keyAlgo:      v1.ECDSAKeyAlgorithm,
+			keySize:      521,
+			expectErrStr: "ecdsa key size 521 is not permitted in FIPS mode",
+		},
+		"unsupported key algorithm is rejected": {
+			keyAlgo:      v1.PrivateKeyAlgorithm("ed25519"),
+			expectErrStr: "unsupported private key algorithm specified",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := GeneratePrivateKeyForCertificate(buildCertificateWithKeyParams(test.keyAlgo, test.keySize))
+			if test.expectErrStr == "" {
+				if err != nil {
+					t.Errorf("expected no err, but got '%q'", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected err, but got no error")
+			}
+			if !strings.Contains(err.Error(), test.expectErrStr) {
+				t.Errorf("expected err string to match: '%s', got: '%s'", test.expectErrStr, err.Error())
+			}
+		})
+	}
+}
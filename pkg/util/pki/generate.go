@@ -19,12 +19,16 @@ package pki
 import (
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha512"
 	"crypto/x509"
+	"encoding/binary"
 	"encoding/pem"
 	"fmt"
+	"math/big"
 
 	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
 )
@@ -44,6 +48,10 @@ const (
 	ECCurve384 = 384
 	// ECCurve521 represents a secp521r1 / NIST P-521 ECDSA key.
 	ECCurve521 = 521
+
+	// MinKeySeedSize is the minimum size in bytes of a seed that private keys
+	// may be derived from.
+	MinKeySeedSize = 32
 )
 
 // GeneratePrivateKeyForCertificate will generate a private key suitable for
@@ -78,7 +86,8 @@ func GeneratePrivateKeyForCertificate(crt *v1.Certificate) (crypto.Signer, error
 }
 
 // GenerateRSAPrivateKey will generate a RSA private key of the given size.
-// It places restrictions on the minimum and maximum RSA keysize.
+// It places restrictions on the minimum and maximum RSA keysize, and on the
+// permitted key sizes if FIPS mode is enabled.
 func GenerateRSAPrivateKey(keySize int) (*rsa.PrivateKey, error) {
 	// Do not allow keySize < 2048
 	// https://en.wikipedia.org/wiki/Key_size#cite_note-twirl-14
@@ -88,12 +97,16 @@ func GenerateRSAPrivateKey(keySize int) (*rsa.PrivateKey, error) {
 	if keySize > MaxRSAKeySize {
 		return nil, fmt.Errorf("rsa key size specified too big: %d. maximum key size: %d", keySize, MaxRSAKeySize)
 	}
+	if err := validateFIPSRSAKeySize(keySize); err != nil {
+		return nil, err
+	}
 
 	return rsa.GenerateKey(rand.Reader, keySize)
 }
 
 // GenerateECPrivateKey will generate an ECDSA private key of the given size.
-// It can be used to generate 256, 384 and 521 sized keys.
+// It can be used to generate 256, 384 and 521 sized keys. If FIPS mode is
+// enabled, only 256 and 384 sized keys can be generated.
 func GenerateECPrivateKey(keySize int) (*ecdsa.PrivateKey, error) {
 	var ecCurve elliptic.Curve
 
@@ -107,10 +120,84 @@ func GenerateECPrivateKey(keySize int) (*ecdsa.PrivateKey, error) {
 	default:
 		return nil, fmt.Errorf("unsupported ecdsa key size specified: %d", keySize)
 	}
+	if err := validateFIPSECCurve(keySize); err != nil {
+		return nil, err
+	}
 
 	return ecdsa.GenerateKey(ecCurve, rand.Reader)
 }
 
+// GeneratePrivateKeyForCertificateFromSeed will deterministically derive a
+// private key suitable for the provided cert-manager Certificate resource from
+// the given seed, so that the same seed always yields the same key.
+// Only ECDSA keys are supported. The returned key is only as secret as the
+// seed, so this must not be used for keys used in production.
+func GeneratePrivateKeyForCertificateFromSeed(crt *v1.Certificate, seed []byte) (crypto.Signer, error) {
+	crt = crt.DeepCopy()
+	if crt.Spec.PrivateKey == nil {
+		crt.Spec.PrivateKey = &v1.CertificatePrivateKey{}
+	}
+	switch crt.Spec.PrivateKey.Algorithm {
+	case v1.ECDSAKeyAlgorithm:
+		keySize := ECCurve256
+
+		if crt.Spec.PrivateKey.Size > 0 {
+			keySize = crt.Spec.PrivateKey.Size
+		}
+
+		return GenerateECPrivateKeyFromSeed(keySize, seed)
+	default:
+		return nil, fmt.Errorf("unsupported private key algorithm for deriving a key from a seed: %q, only %q is supported", crt.Spec.PrivateKey.Algorithm, v1.ECDSAKeyAlgorithm)
+	}
+}
+
+// GenerateECPrivateKeyFromSeed will deterministically derive an ECDSA private
+// key of the given size from the given seed, which must be at least
+// MinKeySeedSize bytes long.
+func GenerateECPrivateKeyFromSeed(keySize int, seed []byte) (*ecdsa.PrivateKey, error) {
+	if len(seed) < MinKeySeedSize {
+		return nil, fmt.Errorf("key seed is too short: %d bytes. minimum seed size: %d bytes", len(seed), MinKeySeedSize)
+	}
+
+	var ecCurve elliptic.Curve
+
+	switch keySize {
+	case ECCurve256:
+		ecCurve = elliptic.P256()
+	case ECCurve384:
+		ecCurve = elliptic.P384()
+	case ECCurve521:
+		ecCurve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported ecdsa key size specified: %d", keySize)
+	}
+	if err := validateFIPSECCurve(keySize); err != nil {
+		return nil, err
+	}
+
+	// Derive the private key using the method in FIPS 186-4 appendix B.4.1,
+	// with the random bits replaced by SHA-512 in counter mode over the seed.
+	params := ecCurve.Params()
+	size := (params.BitSize+7)/8 + 8
+	var b []byte
+	for counter := uint32(0); len(b) < size; counter++ {
+		h := sha512.New()
+		h.Write(seed)
+		binary.Write(h, binary.BigEndian, counter)
+		b = h.Sum(b)
+	}
+
+	one := big.NewInt(1)
+	d := new(big.Int).SetBytes(b[:size])
+	d.Mod(d, new(big.Int).Sub(params.N, one))
+	d.Add(d, one)
+
+	pk := &ecdsa.PrivateKey{D: d}
+	pk.Curve = ecCurve
+	pk.X, pk.Y = ecCurve.ScalarBaseMult(d.Bytes())
+	return pk, nil
+}
+
 // EncodePrivateKey will encode a given crypto.PrivateKey by first inspecting
 // the type of key encoding and then inspecting the type of key provided.
 // It only supports encoding RSA or ECDSA keys.
@@ -162,13 +249,15 @@ func EncodeECPrivateKey(pk *ecdsa.PrivateKey) ([]byte, error) {
 }
 
 // PublicKeyForPrivateKey will return the crypto.PublicKey for the given
-// crypto.PrivateKey. It only supports RSA and ECDSA keys.
+// crypto.PrivateKey. It only supports RSA, ECDSA and Ed25519 keys.
 func PublicKeyForPrivateKey(pk crypto.PrivateKey) (crypto.PublicKey, error) {
 	switch k := pk.(type) {
 	case *rsa.PrivateKey:
 		return k.Public(), nil
 	case *ecdsa.PrivateKey:
 		return k.Public(), nil
+	case ed25519.PrivateKey:
+		return k.Public(), nil
 	default:
 		return nil, fmt.Errorf("unknown private key type: %T", pk)
 	}
@@ -178,7 +267,7 @@ func PublicKeyForPrivateKey(pk crypto.PrivateKey) (crypto.PublicKey, error) {
 // public key in the given x509.Certificate.
 // Returns false and no error if the public key is *not* the same as the certificate's key
 // Returns true and no error if the public key *is* the same as the certificate's key
-// Returns an error if the certificate's key type cannot be determined (i.e. non RSA/ECDSA keys)
+// Returns an error if the certificate's key type cannot be determined (i.e. non RSA/ECDSA/Ed25519 keys)
 func PublicKeyMatchesCertificate(check crypto.PublicKey, crt *x509.Certificate) (bool, error) {
 	return PublicKeysEqual(crt.PublicKey, check)
 }
@@ -187,7 +276,7 @@ func PublicKeyMatchesCertificate(check crypto.PublicKey, crt *x509.Certificate)
 // public key in the given x509.CertificateRequest.
 // Returns false and no error if the given public key is *not* the same as the CSR's key
 // Returns true and no error if the given public key *is* the same as the CSR's key
-// Returns an error if the CSR's key type cannot be determined (i.e. non RSA/ECDSA keys)
+// Returns an error if the CSR's key type cannot be determined (i.e. non RSA/ECDSA/Ed25519 keys)
 func PublicKeyMatchesCSR(check crypto.PublicKey, csr *x509.CertificateRequest) (bool, error) {
 	return PublicKeysEqual(csr.PublicKey, check)
 }
@@ -202,6 +291,8 @@ func PublicKeysEqual(a, b crypto.PublicKey) (bool, error) {
 		return pub.Equal(b), nil
 	case *ecdsa.PublicKey:
 		return pub.Equal(b), nil
+	case ed25519.PublicKey:
+		return pub.Equal(b), nil
 	default:
 		return false, fmt.Errorf("unrecognised public key type: %T", a)
 	}
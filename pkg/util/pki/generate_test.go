@@ -17,6 +17,7 @@ limitations under the License.
 package pki
 
 import (
+	"bytes"
 	"crypto"
 	"crypto/ecdsa"
 	"crypto/elliptic"
@@ -225,6 +226,100 @@ func TestGeneratePrivateKeyForCertificate(t *testing.T) {
 	}
 }
 
+func TestGeneratePrivateKeyForCertificateFromSeed(t *testing.T) {
+	seed := bytes.Repeat([]byte{1}, MinKeySeedSize)
+	otherSeed := bytes.Repeat([]byte{2}, MinKeySeedSize)
+
+	tests := map[string]struct {
+		keyAlgo      v1.PrivateKeyAlgorithm
+		keySize      int
+		seed         []byte
+		expectErrStr string
+	}{
+		"ecdsa with keysize not specified": {
+			keyAlgo: v1.ECDSAKeyAlgorithm,
+			seed:    seed,
+		},
+		"ecdsa key with keysize 384": {
+			keyAlgo: v1.ECDSAKeyAlgorithm,
+			keySize: 384,
+			seed:    seed,
+		},
+		"ecdsa key with keysize 521": {
+			keyAlgo: v1.ECDSAKeyAlgorithm,
+			keySize: 521,
+			seed:    seed,
+		},
+		"ecdsa key with unsupported keysize": {
+			keyAlgo:      v1.ECDSAKeyAlgorithm,
+			keySize:      100,
+			seed:         seed,
+			expectErrStr: "unsupported ecdsa key size specified",
+		},
+		"ecdsa key with a seed that is too short": {
+			keyAlgo:      v1.ECDSAKeyAlgorithm,
+			seed:         seed[:MinKeySeedSize-1],
+			expectErrStr: "key seed is too short",
+		},
+		"rsa keys cannot be derived from a seed": {
+			keyAlgo:      v1.RSAKeyAlgorithm,
+			seed:         seed,
+			expectErrStr: "unsupported private key algorithm for deriving a key from a seed",
+		},
+		"key algorithm not specified defaults to rsa, which cannot be derived from a seed": {
+			seed:         seed,
+			expectErrStr: "unsupported private key algorithm for deriving a key from a seed",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			crt := buildCertificateWithKeyParams(test.keyAlgo, test.keySize)
+			privateKey, err := GeneratePrivateKeyForCertificateFromSeed(crt, test.seed)
+			if test.expectErrStr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.expectErrStr) {
+					t.Errorf("expected err string to match: '%s', got: '%v'", test.expectErrStr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no err, but got '%q'", err)
+			}
+
+			key, ok := privateKey.(*ecdsa.PrivateKey)
+			if !ok {
+				t.Fatalf("expected ecdsa private key, but got %T", privateKey)
+			}
+			curve, err := ecCurveForKeySize(test.keySize)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if key.Curve != curve {
+				t.Errorf("expected key on curve %s, but got %s", curve.Params().Name, key.Curve.Params().Name)
+			}
+			if !curve.IsOnCurve(key.PublicKey.X, key.PublicKey.Y) {
+				t.Error("expected key to be on specified curve")
+			}
+
+			sameKey, err := GeneratePrivateKeyForCertificateFromSeed(crt, test.seed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !key.Equal(sameKey) {
+				t.Error("expected the same seed to yield the same key")
+			}
+
+			otherKey, err := GeneratePrivateKeyForCertificateFromSeed(crt, otherSeed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if key.Equal(otherKey) {
+				t.Error("expected a different seed to yield a different key")
+			}
+		})
+	}
+}
+
 func signTestCert(key crypto.Signer) *x509.Certificate {
 	commonName := "testingcert"
 
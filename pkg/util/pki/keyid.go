@@ -0,0 +1,103 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"crypto"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+
+	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+// subjectPublicKeyInfo is the ASN.1 structure of a marshalled PKIX public
+// key, used to extract the BIT STRING subjectPublicKey.
+type subjectPublicKeyInfo struct {
+	Algorithm        pkix.AlgorithmIdentifier
+	SubjectPublicKey asn1.BitString
+}
+
+// SubjectKeyIdentifier computes the subject key identifier of the given
+// public key using the given method from RFC 5280 section 4.2.1.2.
+func SubjectKeyIdentifier(publicKey crypto.PublicKey, method v1.SubjectKeyIdentifierMethod) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling public key: %w", err)
+	}
+
+	var info subjectPublicKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("error decoding public key: %w", err)
+	}
+
+	hash := sha1.Sum(info.SubjectPublicKey.Bytes)
+
+	switch method {
+	case v1.SubjectKeyIdentifierMethod1:
+		return hash[:], nil
+	case v1.SubjectKeyIdentifierMethod2:
+		// a four-bit type field with the value 0100 followed by the least
+		// significant 60 bits of the hash
+		ski := make([]byte, 8)
+		copy(ski, hash[len(hash)-8:])
+		ski[0] = 0x40 | (ski[0] & 0x0f)
+		return ski, nil
+	default:
+		return nil, fmt.Errorf("unsupported subject key identifier method %q", method)
+	}
+}
+
+// SetKeyIdentifiers sets the subject key identifier of the given template,
+// computed from its public key using the given method, and sets its
+// authority key identifier to the subject key identifier of the issuer. If
+// the issuer certificate does not have a subject key identifier, it is
+// computed from the issuer's public key using the same method. If issuerCert
+// is nil, the template is treated as self-signed.
+// If method is empty, the template is left unchanged so that the Go
+// defaults apply.
+func SetKeyIdentifiers(template, issuerCert *x509.Certificate, method v1.SubjectKeyIdentifierMethod) error {
+	if method == "" {
+		return nil
+	}
+
+	ski, err := SubjectKeyIdentifier(template.PublicKey, method)
+	if err != nil {
+		return err
+	}
+	template.SubjectKeyId = ski
+
+	if issuerCert == nil {
+		template.AuthorityKeyId = ski
+		return nil
+	}
+
+	if len(issuerCert.SubjectKeyId) > 0 {
+		template.AuthorityKeyId = issuerCert.SubjectKeyId
+		return nil
+	}
+
+	aki, err := SubjectKeyIdentifier(issuerCert.PublicKey, method)
+	if err != nil {
+		return fmt.Errorf("error computing authority key identifier: %w", err)
+	}
+	template.AuthorityKeyId = aki
+
+	return nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+func TestSetKeyIdentifiers(t *testing.T) {
+	caKey, err := GenerateECPrivateKey(256)
+	require.NoError(t, err)
+	leafKey, err := GenerateECPrivateKey(256)
+	require.NoError(t, err)
+
+	// expectedSKI independently computes the subject key identifier of the
+	// given certificate's public key using the given method.
+	expectedSKI := func(t *testing.T, cert *x509.Certificate, method cmapi.SubjectKeyIdentifierMethod) []byte {
+		var info struct {
+			Algorithm        pkix.AlgorithmIdentifier
+			SubjectPublicKey asn1.BitString
+		}
+		_, err := asn1.Unmarshal(cert.RawSubjectPublicKeyInfo, &info)
+		require.NoError(t, err)
+
+		hash := sha1.Sum(info.SubjectPublicKey.Bytes)
+		if method == cmapi.SubjectKeyIdentifierMethod1 {
+			return hash[:]
+		}
+		ski := append([]byte{}, hash[12:]...)
+		ski[0] = 0x40 | ski[0]&0x0f
+		return ski
+	}
+
+	template := func(cn string, isCA bool) *x509.Certificate {
+		return &x509.Certificate{
+			SerialNumber:          big.NewInt(1),
+			Subject:               pkix.Name{CommonName: cn},
+			NotBefore:             time.Now(),
+			NotAfter:              time.Now().Add(time.Hour),
+			IsCA:                  isCA,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		}
+	}
+
+	for _, method := range []cmapi.SubjectKeyIdentifierMethod{cmapi.SubjectKeyIdentifierMethod1, cmapi.SubjectKeyIdentifierMethod2} {
+		t.Run(string(method), func(t *testing.T) {
+			caTemplate := template("ca", true)
+			caTemplate.PublicKey = caKey.Public()
+			require.NoError(t, SetKeyIdentifiers(caTemplate, nil, method))
+			_, ca, err := SignCertificate(caTemplate, caTemplate, caKey.Public(), caKey)
+			require.NoError(t, err)
+
+			assert.Equal(t, expectedSKI(t, ca, method), ca.SubjectKeyId, "CA subject key identifier")
+			assert.Equal(t, ca.SubjectKeyId, ca.AuthorityKeyId, "self-signed CA authority key identifier")
+
+			leafTemplate := template("leaf", false)
+			leafTemplate.PublicKey = leafKey.Public()
+			require.NoError(t, SetKeyIdentifiers(leafTemplate, ca, method))
+			_, leaf, err := SignCertificate(leafTemplate, ca, leafKey.Public(), caKey)
+			require.NoError(t, err)
+
+			assert.Equal(t, expectedSKI(t, leaf, method), leaf.SubjectKeyId, "leaf subject key identifier")
+			assert.Equal(t, ca.SubjectKeyId, leaf.AuthorityKeyId, "leaf authority key identifier")
+		})
+	}
+
+	t.Run("computes the authority key identifier if the issuer has no subject key identifier", func(t *testing.T) {
+		caTemplate := template("ca", true)
+		_, ca, err := SignCertificate(caTemplate, caTemplate, caKey.Public(), caKey)
+		require.NoError(t, err)
+		ca.SubjectKeyId = nil
+
+		leafTemplate := template("leaf", false)
+		leafTemplate.PublicKey = leafKey.Public()
+		require.NoError(t, SetKeyIdentifiers(leafTemplate, ca, cmapi.SubjectKeyIdentifierMethod2))
+		assert.Equal(t, expectedSKI(t, ca, cmapi.SubjectKeyIdentifierMethod2), leafTemplate.AuthorityKeyId)
+	})
+
+	t.Run("leaves the template unchanged if no method is set", func(t *testing.T) {
+		leafTemplate := template("leaf", false)
+		leafTemplate.PublicKey = leafKey.Public()
+		require.NoError(t, SetKeyIdentifiers(leafTemplate, nil, ""))
+		assert.Nil(t, leafTemplate.SubjectKeyId)
+		assert.Nil(t, leafTemplate.AuthorityKeyId)
+	})
+}
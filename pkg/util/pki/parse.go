@@ -17,10 +17,13 @@ limitations under the License.
 package pki
 
 import (
+	"bytes"
 	"crypto"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
+	"math/big"
+	"strings"
 
 	"github.com/jetstack/cert-manager/pkg/util/errors"
 )
@@ -116,6 +119,45 @@ func DecodeX509CertificateChainBytes(certBytes []byte) ([]*x509.Certificate, err
 	return certs, nil
 }
 
+// AppendCertificatesPEM returns the PEM encoded certificates in 'pemCerts'
+// followed by each certificate in the PEM encoded 'bundle' that is not already
+// present in 'pemCerts' or earlier in 'bundle'. The contents of 'pemCerts' are
+// kept as-is. An error is returned if either contains invalid certificate
+// data, although 'pemCerts' may be empty.
+func AppendCertificatesPEM(pemCerts, bundle []byte) ([]byte, error) {
+	seen := make(map[string]bool)
+	if len(bytes.TrimSpace(pemCerts)) > 0 {
+		certs, err := DecodeX509CertificateChainBytes(pemCerts)
+		if err != nil {
+			return nil, err
+		}
+		for _, cert := range certs {
+			seen[string(cert.Raw)] = true
+		}
+	}
+
+	bundleCerts, err := DecodeX509CertificateChainBytes(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	out := bytes.NewBuffer(append([]byte{}, pemCerts...))
+	if out.Len() > 0 && !bytes.HasSuffix(pemCerts, []byte("\n")) {
+		out.WriteByte('\n')
+	}
+	for _, cert := range bundleCerts {
+		if seen[string(cert.Raw)] {
+			continue
+		}
+		seen[string(cert.Raw)] = true
+		if err := pem.Encode(out, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}); err != nil {
+			return nil, err
+		}
+	}
+
+	return out.Bytes(), nil
+}
+
 // DecodeX509CertificateBytes will decode a PEM encoded x509 Certificate.
 func DecodeX509CertificateBytes(certBytes []byte) (*x509.Certificate, error) {
 	certs, err := DecodeX509CertificateChainBytes(certBytes)
@@ -141,6 +183,18 @@ func DecodeX509CertificateRequestBytes(csrBytes []byte) (*x509.CertificateReques
 	return csr, nil
 }
 
+// ParseSerialNumber parses a hex encoded certificate serial number, which may
+// optionally be separated by colons as printed by tools such as openssl, e.g.
+// "1f:2a:03".
+func ParseSerialNumber(serial string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(strings.ReplaceAll(serial, ":", ""), 16)
+	if !ok || n.Sign() < 0 {
+		return nil, errors.NewInvalidData("invalid serial number %q: must be a positive hex encoded number", serial)
+	}
+
+	return n, nil
+}
+
 // PEMBundle includes the PEM encoded X.509 certificate chain and CA. CAPEM
 // contains either 1 CA certificate, or is empty if only a single certificate
 // exists in the chain.
@@ -24,6 +24,7 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"math/big"
 	"reflect"
 	"strings"
 	"testing"
@@ -333,3 +334,119 @@ func TestParseSingleCertificateChain(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSerialNumber(t *testing.T) {
+	tests := map[string]struct {
+		serial string
+		exp    *big.Int
+		expErr bool
+	}{
+		"a hex encoded serial number should be parsed": {
+			serial: "1f2a03",
+			exp:    big.NewInt(0x1f2a03),
+		},
+		"a colon separated serial number should be parsed": {
+			serial: "1F:2A:03",
+			exp:    big.NewInt(0x1f2a03),
+		},
+		"an odd length serial number should be parsed": {
+			serial: "abc",
+			exp:    big.NewInt(0xabc),
+		},
+		"an empty serial number should error": {
+			serial: "",
+			expErr: true,
+		},
+		"a serial number that is not hex encoded should error": {
+			serial: "not-hex",
+			expErr: true,
+		},
+		"a negative serial number should error": {
+			serial: "-1f",
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			serial, err := ParseSerialNumber(test.serial)
+			if (err != nil) != test.expErr {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+			if test.exp != nil && serial.Cmp(test.exp) != 0 {
+				t.Errorf("unexpected serial number, exp=%s got=%s", test.exp, serial)
+			}
+		})
+	}
+}
+
+func TestAppendCertificatesPEM(t *testing.T) {
+	root := mustCreateBundle(t, nil, "root")
+	intermediate := mustCreateBundle(t, root, "intermediate")
+	orgRoot := mustCreateBundle(t, nil, "org-root")
+
+	joinPEM := func(pems ...[]byte) []byte {
+		var out []byte
+		for _, b := range pems {
+			out = append(out, b...)
+		}
+		return out
+	}
+
+	tests := map[string]struct {
+		pemCerts, bundle []byte
+		expPEM           []byte
+		expErr           bool
+	}{
+		"if no certificates are given, return the bundle": {
+			bundle: orgRoot.pem,
+			expPEM: orgRoot.pem,
+		},
+		"if the bundle is not already present, append it": {
+			pemCerts: root.pem,
+			bundle:   orgRoot.pem,
+			expPEM:   joinPEM(root.pem, orgRoot.pem),
+		},
+		"if the bundle is already present, do not duplicate it": {
+			pemCerts: joinPEM(root.pem, orgRoot.pem),
+			bundle:   orgRoot.pem,
+			expPEM:   joinPEM(root.pem, orgRoot.pem),
+		},
+		"only append the certificates in the bundle that are not already present": {
+			pemCerts: intermediate.pem,
+			bundle:   joinPEM(root.pem, intermediate.pem, orgRoot.pem),
+			expPEM:   joinPEM(intermediate.pem, root.pem, orgRoot.pem),
+		},
+		"do not duplicate certificates that appear more than once in the bundle": {
+			bundle: joinPEM(orgRoot.pem, orgRoot.pem),
+			expPEM: orgRoot.pem,
+		},
+		"add a newline between the certificates and the bundle if missing": {
+			pemCerts: []byte(strings.TrimSpace(string(root.pem))),
+			bundle:   orgRoot.pem,
+			expPEM:   joinPEM(root.pem, orgRoot.pem),
+		},
+		"if the certificates are invalid, return an error": {
+			pemCerts: []byte("not a certificate"),
+			bundle:   orgRoot.pem,
+			expErr:   true,
+		},
+		"if the bundle is invalid, return an error": {
+			pemCerts: root.pem,
+			bundle:   []byte("not a certificate"),
+			expErr:   true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := AppendCertificatesPEM(test.pemCerts, test.bundle)
+			if (err != nil) != test.expErr {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+			if string(out) != string(test.expPEM) {
+				t.Errorf("unexpected PEM, exp=%q got=%q", test.expPEM, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+var (
+	oidExtensionCertificatePolicies = asn1.ObjectIdentifier{2, 5, 29, 32}
+	oidPolicyQualifierCPS           = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 2, 1}
+)
+
+// policyInformation is the ASN.1 structure of a single policy of the
+// certificatePolicies extension from RFC 5280 section 4.2.1.4.
+type policyInformation struct {
+	PolicyIdentifier asn1.ObjectIdentifier
+	PolicyQualifiers []policyQualifierInfo `asn1:"optional,omitempty"`
+}
+
+// policyQualifierInfo is the ASN.1 structure of a policy qualifier. Only CPS
+// pointer qualifiers, which are an IA5String, are produced.
+type policyQualifierInfo struct {
+	PolicyQualifierID asn1.ObjectIdentifier
+	Qualifier         asn1.RawValue
+}
+
+// ParseObjectIdentifier parses an object identifier in dotted decimal
+// notation, such as `1.3.6.1.4.1.11129`, rejecting arcs with leading zeros
+// and identifiers that cannot be encoded in DER.
+func ParseObjectIdentifier(s string) (asn1.ObjectIdentifier, error) {
+	arcs := strings.Split(s, ".")
+	if len(arcs) < 2 {
+		return nil, fmt.Errorf("object identifier %q must have at least two arcs", s)
+	}
+
+	oid := make(asn1.ObjectIdentifier, len(arcs))
+	for i, arc := range arcs {
+		if arc == "" || strings.TrimLeft(arc, "0123456789") != "" {
+			return nil, fmt.Errorf("object identifier %q must only contain numeric arcs separated by dots", s)
+		}
+		if len(arc) > 1 && arc[0] == '0' {
+			return nil, fmt.Errorf("object identifier %q must not contain arcs with leading zeros", s)
+		}
+		n, err := strconv.Atoi(arc)
+		if err != nil {
+			return nil, fmt.Errorf("object identifier %q contains an arc that is too large", s)
+		}
+		oid[i] = n
+	}
+
+	if oid[0] > 2 {
+		return nil, fmt.Errorf("object identifier %q must start with 0, 1 or 2", s)
+	}
+	if oid[0] < 2 && oid[1] >= 40 {
+		return nil, fmt.Errorf("object identifier %q must have a second arc less than 40 when the first arc is 0 or 1", s)
+	}
+
+	return oid, nil
+}
+
+// BuildCertificatePoliciesExtension builds a certificatePolicies extension
+// containing the given policies, with a CPS pointer qualifier for each policy
+// that has a CPS URI.
+func BuildCertificatePoliciesExtension(policies []v1.CertificatePolicy) (pkix.Extension, error) {
+	infos := make([]policyInformation, 0, len(policies))
+	for _, policy := range policies {
+		oid, err := ParseObjectIdentifier(policy.PolicyID)
+		if err != nil {
+			return pkix.Extension{}, fmt.Errorf("invalid policy ID: %w", err)
+		}
+
+		info := policyInformation{PolicyIdentifier: oid}
+		if policy.CPSURI != "" {
+			if !IsIA5String(policy.CPSURI) {
+				return pkix.Extension{}, fmt.Errorf("CPS URI %q of policy %s must only contain ASCII characters", policy.CPSURI, policy.PolicyID)
+			}
+			info.PolicyQualifiers = []policyQualifierInfo{{
+				PolicyQualifierID: oidPolicyQualifierCPS,
+				Qualifier: asn1.RawValue{
+					Class: asn1.ClassUniversal,
+					Tag:   asn1.TagIA5String,
+					Bytes: []byte(policy.CPSURI),
+				},
+			}}
+		}
+		infos = append(infos, info)
+	}
+
+	value, err := asn1.Marshal(infos)
+	if err != nil {
+		return pkix.Extension{}, fmt.Errorf("failed to asn1 encode certificate policies: %w", err)
+	}
+
+	return pkix.Extension{
+		Id:    oidExtensionCertificatePolicies,
+		Value: value,
+	}, nil
+}
+
+// CertificatePoliciesFromExtensions returns the policies of the
+// certificatePolicies extension in exts, or nil if there is no such
+// extension. Only CPS pointer qualifiers are decoded, any other qualifiers
+// are ignored.
+func CertificatePoliciesFromExtensions(exts []pkix.Extension) ([]v1.CertificatePolicy, error) {
+	for _, ext := range exts {
+		if !ext.Id.Equal(oidExtensionCertificatePolicies) {
+			continue
+		}
+
+		var infos []policyInformation
+		rest, err := asn1.Unmarshal(ext.Value, &infos)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding certificate policies: %w", err)
+		}
+		if len(rest) > 0 {
+			return nil, fmt.Errorf("error decoding certificate policies: trailing data")
+		}
+
+		policies := make([]v1.CertificatePolicy, 0, len(infos))
+		for _, info := range infos {
+			policy := v1.CertificatePolicy{PolicyID: info.PolicyIdentifier.String()}
+			for _, qualifier := range info.PolicyQualifiers {
+				if qualifier.PolicyQualifierID.Equal(oidPolicyQualifierCPS) && qualifier.Qualifier.Tag == asn1.TagIA5String {
+					policy.CPSURI = string(qualifier.Qualifier.Bytes)
+					break
+				}
+			}
+			policies = append(policies, policy)
+		}
+		return policies, nil
+	}
+
+	return nil, nil
+}
+
+// CertificatePoliciesForRequest returns the certificate policies to issue
+// for the given PEM encoded CSR: the issuer's policies, followed by the
+// policies requested in the CSR whose policy ID is in allowed. Requested
+// policies are issued as configured in allowed, so that a request can never
+// choose the policy IDs or CPS URIs of an issued certificate.
+func CertificatePoliciesForRequest(csrPEM []byte, policies, allowed []v1.CertificatePolicy) ([]v1.CertificatePolicy, error) {
+	issued := append([]v1.CertificatePolicy(nil), policies...)
+	if len(allowed) == 0 {
+		return issued, nil
+	}
+
+	csr, err := DecodeX509CertificateRequestBytes(csrPEM)
+	if err != nil {
+		return nil, err
+	}
+	requested, err := CertificatePoliciesFromExtensions(csr.Extensions)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, req := range requested {
+		if certificatePolicyIndex(issued, req.PolicyID) >= 0 {
+			continue
+		}
+		if i := certificatePolicyIndex(allowed, req.PolicyID); i >= 0 {
+			issued = append(issued, allowed[i])
+		}
+	}
+
+	return issued, nil
+}
+
+func certificatePolicyIndex(policies []v1.CertificatePolicy, policyID string) int {
+	for i, policy := range policies {
+		if policy.PolicyID == policyID {
+			return i
+		}
+	}
+	return -1
+}
+
+// AddCertificatePolicies adds the given policies to the certificatePolicies
+// extension of template, creating the extension if it is not already
+// present in the template's ExtraExtensions. A given policy replaces an
+// existing policy with the same policy ID.
+func AddCertificatePolicies(template *x509.Certificate, policies []v1.CertificatePolicy) error {
+	if len(policies) == 0 {
+		return nil
+	}
+
+	existing, err := CertificatePoliciesFromExtensions(template.ExtraExtensions)
+	if err != nil {
+		return err
+	}
+
+	merged := append([]v1.CertificatePolicy(nil), existing...)
+	for _, policy := range policies {
+		if i := certificatePolicyIndex(merged, policy.PolicyID); i >= 0 {
+			merged[i] = policy
+		} else {
+			merged = append(merged, policy)
+		}
+	}
+
+	ext, err := BuildCertificatePoliciesExtension(merged)
+	if err != nil {
+		return err
+	}
+
+	var extraExtensions []pkix.Extension
+	for _, e := range template.ExtraExtensions {
+		if !e.Id.Equal(oidExtensionCertificatePolicies) {
+			extraExtensions = append(extraExtensions, e)
+		}
+	}
+	template.ExtraExtensions = append(extraExtensions, ext)
+
+	return nil
+}
+
+// IsIA5String returns true if s can be encoded as an ASN.1 IA5String, i.e.
+// if it only contains ASCII characters.
+func IsIA5String(s string) bool {
+	for _, r := range s {
+		if r > 0x7f {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+func TestParseObjectIdentifier(t *testing.T) {
+	tests := map[string]struct {
+		oid     string
+		want    asn1.ObjectIdentifier
+		wantErr bool
+	}{
+		"qualified certificate policy": {oid: "0.4.0.194112.1.2", want: asn1.ObjectIdentifier{0, 4, 0, 194112, 1, 2}},
+		"two arcs":                     {oid: "2.999", want: asn1.ObjectIdentifier{2, 999}},
+		"single arc":                   {oid: "1", wantErr: true},
+		"empty":                        {oid: "", wantErr: true},
+		"empty arc":                    {oid: "1..2", wantErr: true},
+		"trailing dot":                 {oid: "1.2.", wantErr: true},
+		"non-numeric arc":              {oid: "1.2.a", wantErr: true},
+		"negative arc":                 {oid: "1.-2.3", wantErr: true},
+		"leading zero":                 {oid: "1.2.03", wantErr: true},
+		"first arc too large":          {oid: "3.1", wantErr: true},
+		"second arc too large":         {oid: "1.40", wantErr: true},
+		"arc overflows":                {oid: "1.2.99999999999999999999", wantErr: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			oid, err := ParseObjectIdentifier(test.oid)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, oid)
+		})
+	}
+}
+
+func TestBuildCertificatePoliciesExtension(t *testing.T) {
+	policies := []cmapi.CertificatePolicy{
+		{PolicyID: "0.4.0.194112.1.2", CPSURI: "https://pki.example.com/cps"},
+		{PolicyID: "2.23.140.1.2.1"},
+	}
+
+	ext, err := BuildCertificatePoliciesExtension(policies)
+	require.NoError(t, err)
+	assert.Equal(t, oidExtensionCertificatePolicies, ext.Id)
+	assert.False(t, ext.Critical)
+
+	decoded, err := CertificatePoliciesFromExtensions([]pkix.Extension{ext})
+	require.NoError(t, err)
+	assert.Equal(t, policies, decoded)
+
+	_, err = BuildCertificatePoliciesExtension([]cmapi.CertificatePolicy{{PolicyID: "1.2.x"}})
+	assert.Error(t, err, "invalid policy ID")
+	_, err = BuildCertificatePoliciesExtension([]cmapi.CertificatePolicy{{PolicyID: "1.2.3", CPSURI: "https://pki.exämple.com"}})
+	assert.Error(t, err, "non-ASCII CPS URI")
+}
+
+func TestCertificatePoliciesAreIssued(t *testing.T) {
+	caKey, err := GenerateECPrivateKey(256)
+	require.NoError(t, err)
+	leafKey, err := GenerateECPrivateKey(256)
+	require.NoError(t, err)
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	_, ca, err := SignCertificate(caTemplate, caTemplate, caKey.Public(), caKey)
+	require.NoError(t, err)
+
+	crt := &cmapi.Certificate{Spec: cmapi.CertificateSpec{
+		CommonName: "example.com",
+		PrivateKey: &cmapi.CertificatePrivateKey{Algorithm: cmapi.ECDSAKeyAlgorithm},
+		CertificatePolicies: []cmapi.CertificatePolicy{
+			{PolicyID: "2.23.140.1.2.1", CPSURI: "https://requester.example.com/cps"},
+			{PolicyID: "1.3.6.1.4.1.99999.1"},
+		},
+	}}
+	csrTemplate, err := GenerateCSR(crt)
+	require.NoError(t, err)
+	csrDER, err := EncodeCSR(csrTemplate, leafKey)
+	require.NoError(t, err)
+	csrPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})
+
+	issuerPolicies := []cmapi.CertificatePolicy{
+		{PolicyID: "0.4.0.194112.1.2", CPSURI: "https://pki.example.com/cps"},
+	}
+
+	tests := map[string]struct {
+		allowed []cmapi.CertificatePolicy
+		want    []cmapi.CertificatePolicy
+	}{
+		"requested policies are not issued if the issuer does not allow any": {
+			want: issuerPolicies,
+		},
+		"requested policies are only issued if allowed, as configured on the issuer": {
+			allowed: []cmapi.CertificatePolicy{
+				{PolicyID: "2.23.140.1.2.1", CPSURI: "https://pki.example.com/dv"},
+				{PolicyID: "2.23.140.1.2.2"},
+			},
+			want: []cmapi.CertificatePolicy{
+				{PolicyID: "0.4.0.194112.1.2", CPSURI: "https://pki.example.com/cps"},
+				{PolicyID: "2.23.140.1.2.1", CPSURI: "https://pki.example.com/dv"},
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			template, err := GenerateTemplateFromCSRPEM(csrPEM, time.Hour, false)
+			require.NoError(t, err)
+			assert.Empty(t, template.ExtraExtensions, "requested certificate policies must not be copied to the template")
+
+			policies, err := CertificatePoliciesForRequest(csrPEM, issuerPolicies, test.allowed)
+			require.NoError(t, err)
+			require.NoError(t, AddCertificatePolicies(template, policies))
+
+			_, cert, err := SignCertificate(template, ca, leafKey.Public(), caKey)
+			require.NoError(t, err)
+
+			// decode the issued certificate to ensure the extension is well-formed
+			parsed, err := x509.ParseCertificate(cert.Raw)
+			require.NoError(t, err)
+
+			var wantIdentifiers []asn1.ObjectIdentifier
+			for _, policy := range test.want {
+				oid, err := ParseObjectIdentifier(policy.PolicyID)
+				require.NoError(t, err)
+				wantIdentifiers = append(wantIdentifiers, oid)
+			}
+			assert.Equal(t, wantIdentifiers, parsed.PolicyIdentifiers)
+
+			got, err := CertificatePoliciesFromExtensions(parsed.Extensions)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+
+			var count int
+			for _, ext := range parsed.Extensions {
+				if ext.Id.Equal(oidExtensionCertificatePolicies) {
+					count++
+				}
+			}
+			assert.Equal(t, 1, count, "certificatePolicies extension must only be present once")
+		})
+	}
+}
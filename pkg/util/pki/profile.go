@@ -0,0 +1,239 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	v1 "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+var (
+	oidExtensionKeyUsage                = asn1.ObjectIdentifier{2, 5, 29, 15}
+	oidExtensionSubjectAltName          = asn1.ObjectIdentifier{2, 5, 29, 17}
+	oidExtensionBasicConstraints        = asn1.ObjectIdentifier{2, 5, 29, 19}
+	oidExtensionExtendedKeyUsage        = asn1.ObjectIdentifier{2, 5, 29, 37}
+	minimalCertificateProfileExtensions = []asn1.ObjectIdentifier{
+		oidExtensionKeyUsage,
+		oidExtensionSubjectAltName,
+		oidExtensionBasicConstraints,
+		oidExtensionExtendedKeyUsage,
+	}
+)
+
+// hashForSignatureAlgorithm is the hash that is signed for each of the
+// signature algorithms the standard library uses when creating certificates.
+// Ed25519 signs the message itself.
+var hashForSignatureAlgorithm = map[x509.SignatureAlgorithm]crypto.Hash{
+	x509.SHA256WithRSA:   crypto.SHA256,
+	x509.SHA384WithRSA:   crypto.SHA384,
+	x509.SHA512WithRSA:   crypto.SHA512,
+	x509.ECDSAWithSHA256: crypto.SHA256,
+	x509.ECDSAWithSHA384: crypto.SHA384,
+	x509.ECDSAWithSHA512: crypto.SHA512,
+	x509.PureEd25519:     crypto.Hash(0),
+}
+
+// certificate is the ASN.1 structure of a certificate from RFC 5280 section
+// 4.1.
+type certificate struct {
+	TBSCertificate     asn1.RawValue
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	SignatureValue     asn1.BitString
+}
+
+// tbsCertificate is the ASN.1 structure of the signed portion of a
+// certificate. Fields that are not modified by a profile are kept as raw
+// values so that they are re-encoded unchanged.
+type tbsCertificate struct {
+	Version            int `asn1:"optional,explicit,default:0,tag:0"`
+	SerialNumber       *big.Int
+	SignatureAlgorithm asn1.RawValue
+	Issuer             asn1.RawValue
+	Validity           asn1.RawValue
+	Subject            asn1.RawValue
+	PublicKey          asn1.RawValue
+	UniqueID           asn1.BitString   `asn1:"optional,tag:1"`
+	SubjectUniqueID    asn1.BitString   `asn1:"optional,tag:2"`
+	Extensions         []pkix.Extension `asn1:"omitempty,optional,explicit,tag:3"`
+}
+
+// ApplyCertificateProfile restricts the version and extensions of the first
+// PEM encoded certificate in certPEM to those of the given profile, and
+// re-signs it with signerKey. Any following certificates in certPEM, such as
+// the rest of a chain, are returned unchanged. The re-signed certificate is
+// verified to parse and to have a valid signature from issuerCert, or from
+// itself if issuerCert is nil.
+// If profile is empty, certPEM is returned unchanged.
+func ApplyCertificateProfile(certPEM []byte, profile v1.CertificateProfile, issuerCert *x509.Certificate, signerKey crypto.Signer) ([]byte, error) {
+	return applyCertificateProfile(rand.Reader, certPEM, profile, issuerCert, signerKey)
+}
+
+// ApplyCertificateProfileDeterministically is like ApplyCertificateProfile,
+// but does not use a source of randomness when re-signing the certificate,
+// for use with certificates signed by SignCertificateDeterministically.
+func ApplyCertificateProfileDeterministically(certPEM []byte, profile v1.CertificateProfile, issuerCert *x509.Certificate, signerKey crypto.Signer) ([]byte, error) {
+	return applyCertificateProfile(zeroReader{}, certPEM, profile, issuerCert, signerKey)
+}
+
+func applyCertificateProfile(rand io.Reader, certPEM []byte, profile v1.CertificateProfile, issuerCert *x509.Certificate, signerKey crypto.Signer) ([]byte, error) {
+	if profile == "" {
+		return certPEM, nil
+	}
+
+	block, rest := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("error decoding certificate PEM block")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding DER certificate bytes: %w", err)
+	}
+
+	hash, ok := hashForSignatureAlgorithm[cert.SignatureAlgorithm]
+	if !ok {
+		return nil, fmt.Errorf("unsupported signature algorithm %s", cert.SignatureAlgorithm)
+	}
+
+	var outer certificate
+	if _, err := asn1.Unmarshal(block.Bytes, &outer); err != nil {
+		return nil, fmt.Errorf("error decoding certificate: %w", err)
+	}
+	var tbs tbsCertificate
+	if _, err := asn1.Unmarshal(outer.TBSCertificate.FullBytes, &tbs); err != nil {
+		return nil, fmt.Errorf("error decoding certificate: %w", err)
+	}
+
+	switch profile {
+	case v1.CertificateProfileMinimal:
+		tbs.Extensions, err = minimalExtensions(tbs.Extensions, cert.Subject.CommonName)
+		if err != nil {
+			return nil, err
+		}
+	case v1.CertificateProfileV1:
+		// version 1 is encoded as the default value 0
+		tbs.Version = 0
+		tbs.Extensions = nil
+	default:
+		return nil, fmt.Errorf("unsupported certificate profile %q", profile)
+	}
+
+	tbsDER, err := asn1.Marshal(tbs)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding certificate: %w", err)
+	}
+
+	signed := tbsDER
+	if hash != 0 {
+		h := hash.New()
+		h.Write(tbsDER)
+		signed = h.Sum(nil)
+	}
+	signature, err := signerKey.Sign(rand, signed, hash)
+	if err != nil {
+		return nil, fmt.Errorf("error signing certificate: %w", err)
+	}
+
+	outer.TBSCertificate = asn1.RawValue{FullBytes: tbsDER}
+	outer.SignatureValue = asn1.BitString{Bytes: signature, BitLength: len(signature) * 8}
+	der, err := asn1.Marshal(outer)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding certificate: %w", err)
+	}
+
+	cert, err = x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding DER certificate bytes: %w", err)
+	}
+	if issuerCert == nil {
+		issuerCert = cert
+	}
+	if err := issuerCert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature); err != nil {
+		return nil, fmt.Errorf("error verifying certificate signature: %w", err)
+	}
+
+	pemBytes := bytes.NewBuffer([]byte{})
+	if err := pem.Encode(pemBytes, &pem.Block{Type: "CERTIFICATE", Bytes: der}); err != nil {
+		return nil, fmt.Errorf("error encoding certificate PEM: %w", err)
+	}
+	pemBytes.Write(rest)
+
+	return pemBytes.Bytes(), nil
+}
+
+// minimalExtensions returns the extensions of the minimal certificate profile
+// from exts. The subject alternative name extension is reduced to the DNS name
+// matching commonName, and dropped if there is no such name. If commonName is
+// empty, the subject alternative names are the only identity of the
+// certificate, so they are kept unchanged.
+func minimalExtensions(exts []pkix.Extension, commonName string) ([]pkix.Extension, error) {
+	var minimal []pkix.Extension
+	for _, ext := range exts {
+		if !oidInList(ext.Id, minimalCertificateProfileExtensions) {
+			continue
+		}
+
+		if ext.Id.Equal(oidExtensionSubjectAltName) && commonName != "" {
+			var names []asn1.RawValue
+			if _, err := asn1.Unmarshal(ext.Value, &names); err != nil {
+				return nil, fmt.Errorf("error decoding subject alternative names: %w", err)
+			}
+
+			var kept []asn1.RawValue
+			for _, name := range names {
+				// dNSName is the context-specific tag 2 of GeneralName
+				if name.Class == asn1.ClassContextSpecific && name.Tag == 2 && strings.EqualFold(string(name.Bytes), commonName) {
+					kept = append(kept, name)
+				}
+			}
+			if len(kept) == 0 {
+				continue
+			}
+
+			value, err := asn1.Marshal(kept)
+			if err != nil {
+				return nil, fmt.Errorf("error encoding subject alternative names: %w", err)
+			}
+			ext.Value = value
+		}
+
+		minimal = append(minimal, ext)
+	}
+
+	return minimal, nil
+}
+
+func oidInList(oid asn1.ObjectIdentifier, list []asn1.ObjectIdentifier) bool {
+	for _, o := range list {
+		if oid.Equal(o) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+)
+
+func TestApplyCertificateProfile(t *testing.T) {
+	caKey, err := GenerateRSAPrivateKey(2048)
+	require.NoError(t, err)
+	leafKey, err := GenerateECPrivateKey(256)
+	require.NoError(t, err)
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		PublicKey:             caKey.Public(),
+	}
+	caPEM, ca, err := SignCertificate(caTemplate, caTemplate, caKey.Public(), caKey)
+	require.NoError(t, err)
+
+	leafTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{CommonName: "device.example.com"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:              []string{"other.example.com", "device.example.com"},
+		IPAddresses:           []net.IP{net.ParseIP("10.0.0.1")},
+		CRLDistributionPoints: []string{"http://crl.example.com"},
+		OCSPServer:            []string{"http://ocsp.example.com"},
+		SubjectKeyId:          []byte{1, 2, 3, 4},
+		PublicKey:             leafKey.Public(),
+	}
+	leafPEM, _, err := SignCertificate(leafTemplate, ca, leafKey.Public(), caKey)
+	require.NoError(t, err)
+	chainPEM := append(append([]byte{}, leafPEM...), caPEM...)
+
+	decode := func(t *testing.T, certPEM []byte) *x509.Certificate {
+		block, _ := pem.Decode(certPEM)
+		require.NotNil(t, block)
+		cert, err := x509.ParseCertificate(block.Bytes)
+		require.NoError(t, err)
+		return cert
+	}
+
+	extensionIDs := func(cert *x509.Certificate) []string {
+		var ids []string
+		for _, ext := range cert.Extensions {
+			ids = append(ids, ext.Id.String())
+		}
+		return ids
+	}
+
+	t.Run("an empty profile leaves the certificate unchanged", func(t *testing.T) {
+		out, err := ApplyCertificateProfile(chainPEM, "", ca, caKey)
+		require.NoError(t, err)
+		assert.Equal(t, chainPEM, out)
+	})
+
+	t.Run("the minimal profile only keeps the minimal extensions", func(t *testing.T) {
+		out, err := ApplyCertificateProfile(chainPEM, cmapi.CertificateProfileMinimal, ca, caKey)
+		require.NoError(t, err)
+
+		cert := decode(t, out)
+		require.NoError(t, cert.CheckSignatureFrom(ca))
+		assert.Equal(t, 3, cert.Version)
+		assert.ElementsMatch(t, []string{"2.5.29.15", "2.5.29.37", "2.5.29.19", "2.5.29.17"}, extensionIDs(cert))
+		assert.Equal(t, []string{"device.example.com"}, cert.DNSNames)
+		assert.Empty(t, cert.IPAddresses)
+		assert.Empty(t, cert.SubjectKeyId)
+		assert.Empty(t, cert.AuthorityKeyId)
+		assert.Empty(t, cert.CRLDistributionPoints)
+		assert.Empty(t, cert.OCSPServer)
+		assert.Equal(t, x509.KeyUsageDigitalSignature, cert.KeyUsage)
+		assert.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}, cert.ExtKeyUsage)
+		assert.True(t, cert.BasicConstraintsValid)
+		assert.False(t, cert.IsCA)
+		assert.Equal(t, leafTemplate.SerialNumber, cert.SerialNumber)
+		assert.Equal(t, "device.example.com", cert.Subject.CommonName)
+
+		// the rest of the chain is returned unchanged
+		_, rest := pem.Decode(out)
+		assert.Equal(t, caPEM, rest)
+	})
+
+	t.Run("the minimal profile drops subject alternative names not matching the common name", func(t *testing.T) {
+		template := *leafTemplate
+		template.DNSNames = []string{"other.example.com"}
+		certPEM, _, err := SignCertificate(&template, ca, leafKey.Public(), caKey)
+		require.NoError(t, err)
+
+		out, err := ApplyCertificateProfile(certPEM, cmapi.CertificateProfileMinimal, ca, caKey)
+		require.NoError(t, err)
+
+		cert := decode(t, out)
+		require.NoError(t, cert.CheckSignatureFrom(ca))
+		assert.ElementsMatch(t, []string{"2.5.29.15", "2.5.29.37", "2.5.29.19"}, extensionIDs(cert))
+		assert.Empty(t, cert.DNSNames)
+	})
+
+	t.Run("the minimal profile keeps subject alternative names without a common name", func(t *testing.T) {
+		template := *leafTemplate
+		template.Subject = pkix.Name{}
+		certPEM, _, err := SignCertificate(&template, ca, leafKey.Public(), caKey)
+		require.NoError(t, err)
+
+		out, err := ApplyCertificateProfile(certPEM, cmapi.CertificateProfileMinimal, ca, caKey)
+		require.NoError(t, err)
+
+		cert := decode(t, out)
+		assert.Equal(t, []string{"other.example.com", "device.example.com"}, cert.DNSNames)
+		assert.Equal(t, []net.IP{net.ParseIP("10.0.0.1").To4()}, cert.IPAddresses)
+	})
+
+	t.Run("the v1 profile removes all extensions", func(t *testing.T) {
+		out, err := ApplyCertificateProfile(chainPEM, cmapi.CertificateProfileV1, ca, caKey)
+		require.NoError(t, err)
+
+		cert := decode(t, out)
+		require.NoError(t, cert.CheckSignatureFrom(ca))
+		assert.Equal(t, 1, cert.Version)
+		assert.Empty(t, cert.Extensions)
+		assert.Empty(t, cert.DNSNames)
+		assert.Equal(t, leafKey.Public(), cert.PublicKey)
+
+		// the version field is omitted from the encoding of v1 certificates
+		var tbs tbsCertificate
+		_, err = asn1.Unmarshal(cert.RawTBSCertificate, &tbs)
+		require.NoError(t, err)
+		assert.Equal(t, 0, tbs.Version)
+	})
+
+	t.Run("self-signed certificates are verified against themselves", func(t *testing.T) {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(3),
+			Subject:      pkix.Name{CommonName: "self-signed"},
+			NotBefore:    time.Now(),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			PublicKey:    key.Public(),
+		}
+		for _, profile := range []cmapi.CertificateProfile{cmapi.CertificateProfileMinimal, cmapi.CertificateProfileV1} {
+			certPEM, _, err := SignCertificateDeterministically(template, template, key.Public(), key)
+			require.NoError(t, err)
+
+			out, err := ApplyCertificateProfileDeterministically(certPEM, profile, nil, key)
+			require.NoError(t, err, string(profile))
+
+			cert := decode(t, out)
+			require.NoError(t, cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature), string(profile))
+			assert.Empty(t, cert.SubjectKeyId, string(profile))
+		}
+	})
+
+	t.Run("signing with a key other than the issuer's fails verification", func(t *testing.T) {
+		otherKey, err := GenerateRSAPrivateKey(2048)
+		require.NoError(t, err)
+
+		_, err = ApplyCertificateProfile(chainPEM, cmapi.CertificateProfileMinimal, ca, otherKey)
+		assert.Error(t, err)
+	})
+
+	t.Run("unsupported profiles are rejected", func(t *testing.T) {
+		_, err := ApplyCertificateProfile(chainPEM, "V2", ca, caKey)
+		assert.EqualError(t, err, `unsupported certificate profile "V2"`)
+	})
+}
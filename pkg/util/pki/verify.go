@@ -0,0 +1,56 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"crypto/x509"
+	"time"
+)
+
+// VerifyCertificateChain verifies that the leaf certificate of the given PEM
+// encoded chain builds to one of roots. Any further certificates in the
+// chain, as well as those in the PEM encoded caPEM, are used as
+// intermediates. The validity period of the leaf is not checked: the chain
+// is verified as of now, clamped to the leaf's validity period.
+func VerifyCertificateChain(chainPEM, caPEM []byte, roots *x509.CertPool, now time.Time) error {
+	chain, err := DecodeX509CertificateChainBytes(chainPEM)
+	if err != nil {
+		return err
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	intermediates.AppendCertsFromPEM(caPEM)
+
+	leaf := chain[0]
+	if now.Before(leaf.NotBefore) {
+		now = leaf.NotBefore
+	}
+	if now.After(leaf.NotAfter) {
+		now = leaf.NotAfter
+	}
+
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err
+}
@@ -31,6 +31,19 @@ func CertificateSecretName(name string) Func {
 	}
 }
 
+// CertificatePKCS12KeystorePasswordSecretName returns a predicate that used to
+// filter Certificates to only those with the given
+// 'spec.keystores.pkcs12.passwordSecretRef.name'.
+func CertificatePKCS12KeystorePasswordSecretName(name string) Func {
+	return func(obj runtime.Object) bool {
+		crt := obj.(*cmapi.Certificate)
+		if crt.Spec.Keystores == nil || crt.Spec.Keystores.PKCS12 == nil {
+			return false
+		}
+		return crt.Spec.Keystores.PKCS12.PasswordSecretRef.Name == name
+	}
+}
+
 // CertificateSecretName returns a predicate that used to filter Certificates
 // to only those with the given 'status.nextPrivateKeySecretName'.
 // It is not possible to select Certificates with a 'nil' secret name using
@@ -22,6 +22,7 @@ import (
 	"k8s.io/utils/pointer"
 
 	cmapi "github.com/jetstack/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 )
 
 func TestCertificateSecretName(t *testing.T) {
@@ -92,3 +93,48 @@ func TestCertificateNextPrivateKeySecretName(t *testing.T) {
 		})
 	}
 }
+
+func TestCertificatePKCS12KeystorePasswordSecretName(t *testing.T) {
+	certWithPasswordSecretName := func(s string) *cmapi.Certificate {
+		return &cmapi.Certificate{
+			Spec: cmapi.CertificateSpec{
+				Keystores: &cmapi.CertificateKeystores{
+					PKCS12: &cmapi.PKCS12Keystore{
+						PasswordSecretRef: cmmeta.SecretKeySelector{
+							LocalObjectReference: cmmeta.LocalObjectReference{Name: s},
+						},
+					},
+				},
+			},
+		}
+	}
+	tests := map[string]struct {
+		secretName string
+		cert       *cmapi.Certificate
+		expected   bool
+	}{
+		"returns true if password secret name matches": {
+			secretName: "abc",
+			cert:       certWithPasswordSecretName("abc"),
+			expected:   true,
+		},
+		"returns false if password secret name does not match": {
+			secretName: "abc",
+			cert:       certWithPasswordSecretName("abcd"),
+			expected:   false,
+		},
+		"returns false if no PKCS12 keystore is configured": {
+			secretName: "",
+			cert:       &cmapi.Certificate{},
+			expected:   false,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := CertificatePKCS12KeystorePasswordSecretName(test.secretName)(test.cert)
+			if got != test.expected {
+				t.Errorf("unexpected response: got=%t, exp=%t", got, test.expected)
+			}
+		})
+	}
+}
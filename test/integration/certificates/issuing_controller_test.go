@@ -65,6 +65,7 @@ func TestIssuingController(t *testing.T) {
 		mustSync,
 		nil,
 		queue,
+		0,
 	)
 	stopController := framework.StartInformersAndController(t, factory, cmFactory, c)
 	defer stopController()
@@ -269,6 +270,7 @@ func TestIssuingController_PKCS8_PrivateKey(t *testing.T) {
 		mustSync,
 		nil,
 		queue,
+		0,
 	)
 	stopController := framework.StartInformersAndController(t, factory, cmFactory, c)
 	defer stopController()
@@ -66,6 +66,7 @@ func TestMetricsController(t *testing.T) {
 		mustSync,
 		nil,
 		queue,
+		0,
 	)
 	stopController := framework.StartInformersAndController(t, factory, cmFactory, c)
 	defer stopController()
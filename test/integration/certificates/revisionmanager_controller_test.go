@@ -59,6 +59,7 @@ func TestRevisionManagerController(t *testing.T) {
 		mustSync,
 		nil,
 		queue,
+		0,
 	)
 	stopController := framework.StartInformersAndController(t, factory, cmFactory, c)
 	defer stopController()
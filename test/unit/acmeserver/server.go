@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// acmeserver contains a minimal fake ACME server for use in unit tests.
+package acmeserver
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// Request is a JWS signed request received by the fake ACME server.
+type Request struct {
+	// Path is the path of the endpoint the request was sent to.
+	Path string
+	// Alg is the algorithm in the protected header of the JWS.
+	Alg string
+	// Payload is the decoded payload of the JWS.
+	Payload []byte
+}
+
+// Server is a minimal ACME server that accepts any account and creates a
+// pending order for any newOrder request, echoing back the fields of the
+// request. It records every request made to its account and order endpoints.
+type Server struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	requests []Request
+}
+
+// NewServer starts a new fake ACME server. Its directory is served at
+// "/directory", and it must be closed once the test is complete.
+func NewServer(t *testing.T) *Server {
+	s := &Server{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/directory", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"newNonce":   s.URL + "/nonce",
+			"newAccount": s.URL + "/account",
+			"newOrder":   s.URL + "/order",
+		})
+	})
+	mux.HandleFunc("/nonce", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/account", func(w http.ResponseWriter, r *http.Request) {
+		req, ok := s.record(t, w, r)
+		if !ok {
+			return
+		}
+		var account struct {
+			OnlyReturnExisting bool `json:"onlyReturnExisting"`
+		}
+		if err := json.Unmarshal(req.Payload, &account); err != nil {
+			t.Errorf("failed to decode newAccount request: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Location", s.URL+"/account/1")
+		// Looking up an existing account returns 200 rather than 201.
+		if account.OnlyReturnExisting {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusCreated)
+		}
+		w.Write([]byte(`{"status":"valid"}`))
+	})
+	mux.HandleFunc("/order", func(w http.ResponseWriter, r *http.Request) {
+		req, ok := s.record(t, w, r)
+		if !ok {
+			return
+		}
+		order := make(map[string]interface{})
+		if err := json.Unmarshal(req.Payload, &order); err != nil {
+			t.Errorf("failed to decode newOrder request: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		order["status"] = "pending"
+		order["finalize"] = s.URL + "/order/1/finalize"
+
+		w.Header().Set("Location", s.URL+"/order/1")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(order)
+	})
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Replay-Nonce", "nonce")
+		mux.ServeHTTP(w, r)
+	}))
+	return s
+}
+
+// record decodes and records the JWS signed request r. If the request cannot
+// be decoded, the test is failed, an error response is written and false is
+// returned.
+func (s *Server) record(t *testing.T, w http.ResponseWriter, r *http.Request) (Request, bool) {
+	var jws struct {
+		Protected string `json:"protected"`
+		Payload   string `json:"payload"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&jws); err != nil {
+		t.Errorf("failed to decode JWS: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return Request{}, false
+	}
+	protected, err := base64.RawURLEncoding.DecodeString(jws.Protected)
+	if err != nil {
+		t.Errorf("failed to decode JWS protected header: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return Request{}, false
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(protected, &header); err != nil {
+		t.Errorf("failed to decode JWS protected header: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return Request{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(jws.Payload)
+	if err != nil {
+		t.Errorf("failed to decode JWS payload: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return Request{}, false
+	}
+
+	req := Request{Path: r.URL.Path, Alg: header.Alg, Payload: payload}
+	s.lock.Lock()
+	s.requests = append(s.requests, req)
+	s.lock.Unlock()
+	return req, true
+}
+
+// Requests returns the requests received by the server at the given path, in
+// the order they were received.
+func (s *Server) Requests(path string) []Request {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var reqs []Request
+	for _, req := range s.requests {
+		if req.Path == path {
+			reqs = append(reqs, req)
+		}
+	}
+	return reqs
+}
@@ -110,6 +110,24 @@ func SetCertificateSecretName(secretName string) CertificateModifier {
 	}
 }
 
+func SetCertificateSecretTemplate(tmpl v1.CertificateSecretTemplate) CertificateModifier {
+	return func(crt *v1.Certificate) {
+		crt.Spec.SecretTemplate = &tmpl
+	}
+}
+
+func SetCertificateRenewalSchedule(schedule v1.CertificateRenewalSchedule) CertificateModifier {
+	return func(crt *v1.Certificate) {
+		crt.Spec.RenewalSchedule = &schedule
+	}
+}
+
+func SetCertificateOCSPStapling(enabled bool) CertificateModifier {
+	return func(crt *v1.Certificate) {
+		crt.Spec.OCSPStapling = &v1.CertificateOCSPStapling{Enabled: enabled}
+	}
+}
+
 func SetCertificateDuration(duration time.Duration) CertificateModifier {
 	return func(crt *v1.Certificate) {
 		crt.Spec.Duration = &metav1.Duration{Duration: duration}
@@ -122,6 +140,12 @@ func SetCertificateRenewBefore(renewBefore time.Duration) CertificateModifier {
 	}
 }
 
+func SetCertificateIssuanceTimeout(timeout time.Duration) CertificateModifier {
+	return func(crt *v1.Certificate) {
+		crt.Spec.IssuanceTimeout = &metav1.Duration{Duration: timeout}
+	}
+}
+
 func SetCertificateNextPrivateKeySecretName(name string) CertificateModifier {
 	return func(crt *v1.Certificate) {
 		crt.Status.NextPrivateKeySecretName = &name
@@ -180,6 +204,12 @@ func SetCertificateNamespace(namespace string) CertificateModifier {
 	}
 }
 
+func SetCertificateCreationTimestamp(creationTimestamp metav1.Time) CertificateModifier {
+	return func(crt *v1.Certificate) {
+		crt.ObjectMeta.CreationTimestamp = creationTimestamp
+	}
+}
+
 func SetCertificateKeyUsages(usages ...v1.KeyUsage) CertificateModifier {
 	return func(crt *v1.Certificate) {
 		crt.Spec.Usages = usages
@@ -192,6 +222,12 @@ func SetCertificateRevision(revision int) CertificateModifier {
 	}
 }
 
+func SetCertificateRenewalHistory(records ...v1.CertificateRenewalRecord) CertificateModifier {
+	return func(crt *v1.Certificate) {
+		crt.Status.RenewalHistory = records
+	}
+}
+
 func SetCertificateUID(uid types.UID) CertificateModifier {
 	return func(crt *v1.Certificate) {
 		crt.UID = uid
@@ -251,3 +287,9 @@ func SetCertificateRevisionHistoryLimit(limit int32) CertificateModifier {
 		crt.Spec.RevisionHistoryLimit = &limit
 	}
 }
+
+func SetCertificateReplicas(replicas int32) CertificateModifier {
+	return func(crt *v1.Certificate) {
+		crt.Spec.Replicas = &replicas
+	}
+}
@@ -89,6 +89,19 @@ func IssuerWithRandomName(prefix string, mods ...IssuerModifier) *v1.Issuer {
 	return iss
 }
 
+func AddIssuerAnnotations(annotations map[string]string) IssuerModifier {
+	return func(iss v1.GenericIssuer) {
+		meta := iss.GetObjectMeta()
+		if meta.Annotations == nil {
+			meta.Annotations = make(map[string]string)
+		}
+
+		for k, v := range annotations {
+			meta.Annotations[k] = v
+		}
+	}
+}
+
 func SetIssuerACME(a cmacme.ACMEIssuer) IssuerModifier {
 	return func(iss v1.GenericIssuer) {
 		iss.GetSpec().ACME = &a
@@ -167,6 +180,16 @@ func SetIssuerACMEDisableAccountKeyGeneration(disabled bool) IssuerModifier {
 	}
 }
 
+func SetIssuerACMEJWSAlgorithm(alg cmacme.JWSAlgorithm) IssuerModifier {
+	return func(iss v1.GenericIssuer) {
+		spec := iss.GetSpec()
+		if spec.ACME == nil {
+			spec.ACME = &cmacme.ACMEIssuer{}
+		}
+		spec.ACME.JWSAlgorithm = alg
+	}
+}
+
 func SetIssuerACMEEAB(keyID, secretName string) IssuerModifier {
 	return func(iss v1.GenericIssuer) {
 		spec := iss.GetSpec()
@@ -340,6 +363,18 @@ func SetIssuerVenafi(a v1.VenafiIssuer) IssuerModifier {
 	}
 }
 
+func SetIssuerADCSWebEnrollment(a v1.ADCSWebEnrollmentIssuer) IssuerModifier {
+	return func(iss v1.GenericIssuer) {
+		iss.GetSpec().ADCSWebEnrollment = &a
+	}
+}
+
+func SetIssuerChainValidation(a v1.IssuerChainValidation) IssuerModifier {
+	return func(iss v1.GenericIssuer) {
+		iss.GetSpec().ChainValidation = &a
+	}
+}
+
 func AddIssuerCondition(c v1.IssuerCondition) IssuerModifier {
 	return func(iss v1.GenericIssuer) {
 		iss.GetStatus().Conditions = append(iss.GetStatus().Conditions, c)
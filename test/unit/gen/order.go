@@ -17,6 +17,10 @@ limitations under the License.
 package gen
 
 import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	cmacme "github.com/jetstack/cert-manager/pkg/apis/acme/v1"
 	cmmeta "github.com/jetstack/cert-manager/pkg/apis/meta/v1"
 )
@@ -107,3 +111,9 @@ func SetOrderCsr(csr []byte) OrderModifier {
 		order.Spec.Request = csr
 	}
 }
+
+func SetOrderDuration(duration time.Duration) OrderModifier {
+	return func(order *cmacme.Order) {
+		order.Spec.Duration = &metav1.Duration{Duration: duration}
+	}
+}